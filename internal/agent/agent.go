@@ -9,13 +9,18 @@ import (
 	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/tessro/fab/internal/backend"
 	"github.com/tessro/fab/internal/logging"
+	"github.com/tessro/fab/internal/paths"
 	"github.com/tessro/fab/internal/project"
+	"github.com/tessro/fab/internal/rules"
 )
 
 // StopTimeout is the duration to wait for graceful shutdown before force killing.
@@ -26,6 +31,10 @@ const StopTimeout = 5 * time.Second
 // Default bufio.Scanner limit is 64KB which is too small for large files.
 const MaxScanTokenSize = 10 * 1024 * 1024 // 10MB
 
+// DefaultStuckTimeout is how long after start the read loop waits for the
+// first valid stream-json message before flagging the agent as stuck.
+const DefaultStuckTimeout = 2 * time.Minute
+
 // DefaultInterventionSilence is the duration of silence before considering user intervention ended.
 // After a user sends a message, the orchestrator will pause kickstart for this duration.
 const DefaultInterventionSilence = 60 * time.Second
@@ -48,15 +57,20 @@ const (
 
 	// StateError indicates the agent encountered an error or crashed.
 	StateError State = "error"
+
+	// StateStuck indicates the process is alive but produced no valid
+	// stream-json within the stuck timeout after starting.
+	StateStuck State = "stuck"
 )
 
 // Valid state transitions.
 var validTransitions = map[State][]State{
-	StateStarting: {StateRunning, StateError},
+	StateStarting: {StateRunning, StateError, StateStuck},
 	StateRunning:  {StateIdle, StateDone, StateError},
 	StateIdle:     {StateRunning, StateDone, StateError},
-	StateDone:     {StateStarting}, // Can be restarted
-	StateError:    {StateStarting}, // Can be restarted
+	StateStuck:    {StateRunning, StateDone, StateError}, // Recovers once valid output arrives
+	StateDone:     {StateStarting},                       // Can be restarted
+	StateError:    {StateStarting},                       // Can be restarted
 }
 
 // Errors returned by agent operations.
@@ -76,17 +90,36 @@ type Agent struct {
 	Worktree  *project.Worktree // Assigned worktree
 	StartedAt time.Time         // When the agent was created
 	Backend   backend.Backend   // CLI backend (e.g., ClaudeBackend)
+	SpawnedBy string            // Who spawned the agent (see SpawnedByUser); set at creation
 
 	// +checklocks:mu
 	State State // Current state
 	// +checklocks:mu
+	Name string // Human-friendly name (e.g., "brave-otter"); ID stays the canonical key
+	// +checklocks:mu
 	Task string // Current task ID (e.g., "FAB-25")
 	// +checklocks:mu
 	Description string // Human-readable description of current work
 	// +checklocks:mu
+	descriptionAt time.Time // When the description was last set
+	// +checklocks:mu
 	UpdatedAt time.Time // Last state change
 	// +checklocks:mu
 	LastUserInput time.Time // Timestamp of last user message (for intervention detection)
+	// +checklocks:mu
+	StashRef string // Stash commit holding uncommitted work saved before a forced abort
+	// +checklocks:mu
+	Epic string // Parent issue of the current task (empty if none or unknown)
+	// +checklocks:mu
+	CurrentFile string // File most recently read or edited (relative to the worktree when inside it)
+	// +checklocks:mu
+	Model string // Model passed to the CLI (empty = the CLI's default)
+	// +checklocks:mu
+	Role string // Role restricting the agent's tools (e.g., "reviewer"; empty = none)
+	// +checklocks:mu
+	Subdir string // Worktree subdirectory the CLI runs in (empty = the worktree root)
+	// +checklocks:mu
+	usage backend.Usage // Token usage reported with the most recent model response
 
 	// Process management with pipes
 	// +checklocks:mu
@@ -99,6 +132,14 @@ type Agent struct {
 	// Chat history stores parsed messages for display/scrollback
 	history *ChatHistory
 
+	// debugCapture receives raw CLI output while debug capture is enabled.
+	// Atomic so the stdout/stderr tees never contend with mu.
+	debugCapture atomic.Pointer[DebugCapture]
+
+	// redactor scrubs secrets from CLI output before it is recorded or
+	// forwarded. Set at creation; nil disables redaction.
+	redactor *Redactor
+
 	mu sync.RWMutex
 	// +checklocks:mu
 	onStateChange func(old, new State) // Optional callback for state changes
@@ -175,6 +216,26 @@ func (a *Agent) SetDescription(desc string) {
 	a.mu.Lock()
 	a.Description = desc
 	a.UpdatedAt = time.Now()
+	a.descriptionAt = a.UpdatedAt
+	callback := a.onInfoChange
+	a.mu.Unlock()
+
+	// Call callback OUTSIDE the lock to prevent deadlock
+	if callback != nil {
+		callback()
+	}
+}
+
+// SetName sets the agent's human-friendly name.
+// Info change callbacks fire only when the name actually changes.
+func (a *Agent) SetName(name string) {
+	a.mu.Lock()
+	if a.Name == name {
+		a.mu.Unlock()
+		return
+	}
+	a.Name = name
+	a.UpdatedAt = time.Now()
 	callback := a.onInfoChange
 	a.mu.Unlock()
 
@@ -184,6 +245,13 @@ func (a *Agent) SetDescription(desc string) {
 	}
 }
 
+// GetName returns the agent's human-friendly name (empty if unnamed).
+func (a *Agent) GetName() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.Name
+}
+
 // GetDescription returns the agent's description.
 func (a *Agent) GetDescription() string {
 	a.mu.RLock()
@@ -191,6 +259,123 @@ func (a *Agent) GetDescription() string {
 	return a.Description
 }
 
+// DescriptionSetAt returns when the description was last set (zero if never).
+func (a *Agent) DescriptionSetAt() time.Time {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.descriptionAt
+}
+
+// SetEpic records the parent issue of the agent's current task.
+// Info change callbacks fire only when the epic actually changes.
+func (a *Agent) SetEpic(id string) {
+	a.mu.Lock()
+	if a.Epic == id {
+		a.mu.Unlock()
+		return
+	}
+	a.Epic = id
+	callback := a.onInfoChange
+	a.mu.Unlock()
+
+	// Call callback OUTSIDE the lock to prevent deadlock
+	if callback != nil {
+		callback()
+	}
+}
+
+// Usage returns the token usage reported with the agent's most recent
+// model response. Input and cache tokens together are the context in use.
+func (a *Agent) Usage() backend.Usage {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.usage
+}
+
+// recordUsage stores the token usage of a model response.
+func (a *Agent) recordUsage(u backend.Usage) {
+	a.mu.Lock()
+	a.usage = u
+	a.mu.Unlock()
+}
+
+// SetRole sets the role that restricts which tools the agent may use (see
+// rules.EvaluateRole). It reaches the CLI's permission hook the next time
+// the process is started, so set it before Start.
+func (a *Agent) SetRole(role string) {
+	a.mu.Lock()
+	if a.Role == role {
+		a.mu.Unlock()
+		return
+	}
+	a.Role = role
+	callback := a.onInfoChange
+	a.mu.Unlock()
+
+	// Call callback OUTSIDE the lock to prevent deadlock
+	if callback != nil {
+		callback()
+	}
+}
+
+// SetModel sets the model passed to the CLI. It takes effect the next time
+// the process is started. Info change callbacks fire only when the model
+// actually changes.
+func (a *Agent) SetModel(model string) {
+	a.mu.Lock()
+	if a.Model == model {
+		a.mu.Unlock()
+		return
+	}
+	a.Model = model
+	callback := a.onInfoChange
+	a.mu.Unlock()
+
+	// Call callback OUTSIDE the lock to prevent deadlock
+	if callback != nil {
+		callback()
+	}
+}
+
+// GetModel returns the model passed to the CLI.
+func (a *Agent) GetModel() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.Model
+}
+
+// SetCurrentFile records the file the agent most recently read or edited.
+// Paths inside the agent's worktree are stored relative to it.
+// Info change callbacks fire only when the file actually changes.
+func (a *Agent) SetCurrentFile(path string) {
+	a.mu.Lock()
+	if a.Worktree != nil && a.Worktree.Path != "" && filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(a.Worktree.Path, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	if a.CurrentFile == path {
+		a.mu.Unlock()
+		return
+	}
+	a.CurrentFile = path
+	callback := a.onInfoChange
+	a.mu.Unlock()
+
+	// Call callback OUTSIDE the lock to prevent deadlock
+	if callback != nil {
+		callback()
+	}
+}
+
+// SetStashRef records the stash holding work saved before a forced abort.
+func (a *Agent) SetStashRef(ref string) {
+	a.mu.Lock()
+	a.StashRef = ref
+	a.UpdatedAt = time.Now()
+	a.mu.Unlock()
+}
+
 // Transition attempts to move the agent to a new state.
 // Returns ErrInvalidTransition if the transition is not allowed.
 func (a *Agent) Transition(newState State) error {
@@ -247,7 +432,7 @@ func (a *Agent) OnStateChange(fn func(old, new State)) {
 	a.onStateChange = fn
 }
 
-// OnInfoChange sets a callback that's invoked when task or description changes.
+// OnInfoChange sets a callback that's invoked when task, description, or current file changes.
 func (a *Agent) OnInfoChange(fn func()) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -274,11 +459,16 @@ func (a *Agent) MarkError() error {
 	return a.Transition(StateError)
 }
 
-// IsActive returns true if the agent is in Starting, Running, or Idle state.
+// MarkStuck transitions to Stuck state.
+func (a *Agent) MarkStuck() error {
+	return a.Transition(StateStuck)
+}
+
+// IsActive returns true if the agent is in Starting, Running, Idle, or Stuck state.
 func (a *Agent) IsActive() bool {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
-	return a.State == StateStarting || a.State == StateRunning || a.State == StateIdle
+	return a.State == StateStarting || a.State == StateRunning || a.State == StateIdle || a.State == StateStuck
 }
 
 // IsTerminal returns true if the agent is in Done or Error state.
@@ -289,10 +479,11 @@ func (a *Agent) IsTerminal() bool {
 }
 
 // CanAcceptInput returns true if the agent can receive input.
+// Stuck agents accept input so the user can try to nudge them.
 func (a *Agent) CanAcceptInput() bool {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
-	return a.State == StateRunning || a.State == StateIdle
+	return a.State == StateRunning || a.State == StateIdle || a.State == StateStuck
 }
 
 // IsUserIntervening returns true if the user has recently sent a message to this agent.
@@ -389,7 +580,9 @@ func (a *Agent) Info() AgentInfo {
 
 	return AgentInfo{
 		ID:          a.ID,
+		Name:        a.Name,
 		Project:     projectName,
+		SpawnedBy:   a.SpawnedBy,
 		Worktree:    worktreePath,
 		State:       a.State,
 		Task:        a.Task,
@@ -397,13 +590,21 @@ func (a *Agent) Info() AgentInfo {
 		StartedAt:   a.StartedAt,
 		UpdatedAt:   a.UpdatedAt,
 		Backend:     backendName,
+		StashRef:    a.StashRef,
+		Epic:        a.Epic,
+		CurrentFile: a.CurrentFile,
+		Model:       a.Model,
+		Role:        a.Role,
+		Subdir:      a.Subdir,
 	}
 }
 
 // AgentInfo is a read-only snapshot of agent state for status reporting.
 type AgentInfo struct {
 	ID          string
+	Name        string // Human-friendly name (empty if unnamed)
 	Project     string
+	SpawnedBy   string // Who spawned the agent: "user", "orchestrator", "manager", or a parent agent ID
 	Worktree    string
 	State       State
 	Task        string
@@ -411,6 +612,12 @@ type AgentInfo struct {
 	StartedAt   time.Time
 	UpdatedAt   time.Time
 	Backend     string // CLI backend name (e.g., "claude", "codex")
+	StashRef    string // Stash commit of work saved before a forced abort
+	Epic        string // Parent issue of the current task
+	CurrentFile string // File most recently read or edited
+	Model       string // Model passed to the CLI (empty = the CLI's default)
+	Role        string // Role restricting the agent's tools (empty = none)
+	Subdir      string // Worktree subdirectory the CLI runs in (empty = the root)
 }
 
 // Start spawns the agent CLI with pipe-based I/O within the agent's worktree.
@@ -427,21 +634,16 @@ func (a *Agent) Start(initialPrompt string) error {
 		return ErrProcessAlreadyRuns
 	}
 
-	// Determine working directory
-	workDir := ""
-	if a.Worktree != nil {
-		workDir = a.Worktree.Path
-	} else if a.Project != nil {
-		workDir = a.Project.RepoDir()
-	}
-
 	// Build command using the backend
 	cfg := backend.CommandConfig{
-		WorkDir:       workDir,
+		WorkDir:       a.workDir(),
 		AgentID:       a.ID,
 		InitialPrompt: initialPrompt,
+		Model:         a.Model,
+		Env:           a.roleEnv(),
+		ReadOnly:      a.Role == rules.RoleReviewer,
 	}
-	cmd, err := a.Backend.BuildCommand(cfg)
+	cmd, err := backend.Command(a.Backend, cfg)
 	if err != nil {
 		return err
 	}
@@ -457,6 +659,9 @@ func (a *Agent) Start(initialPrompt string) error {
 		stdin.Close()
 		return err
 	}
+	if a.debugCapture.Load() != nil {
+		cmd.Stderr = captureWriter{agent: a}
+	}
 
 	// Start the process
 	if err := cmd.Start(); err != nil {
@@ -466,14 +671,16 @@ func (a *Agent) Start(initialPrompt string) error {
 	}
 
 	a.stdin = stdin
-	a.stdout = stdout
+	a.stdout = captureReader{ReadCloser: stdout, agent: a}
 	a.cmd = cmd
 	a.UpdatedAt = time.Now()
 
 	// Send initial prompt if provided
 	if initialPrompt != "" {
 		// Log but don't fail if send fails - process is running
-		_ = a.sendMessageLocked(initialPrompt)
+		if err := a.sendMessageLocked(initialPrompt); err == nil {
+			a.recordUserTurn(initialPrompt)
+		}
 	}
 
 	return nil
@@ -551,6 +758,9 @@ func (a *Agent) StopWithTimeout(timeout time.Duration) error {
 // For backends with continuous stdin (Claude Code), the message is written to stdin.
 // For backends that require separate processes per turn (Codex), this spawns a
 // resume process if a thread ID is available and the process has stopped.
+//
+// Successfully sent messages are recorded in the chat history as user turns,
+// since the CLI does not echo them back on stdout.
 func (a *Agent) SendMessage(content string) error {
 	a.mu.Lock()
 
@@ -558,6 +768,9 @@ func (a *Agent) SendMessage(content string) error {
 	if a.stdin != nil {
 		err := a.sendMessageLocked(content)
 		a.mu.Unlock()
+		if err == nil {
+			a.recordUserTurn(content)
+		}
 		return err
 	}
 
@@ -566,13 +779,27 @@ func (a *Agent) SendMessage(content string) error {
 	threadID := a.threadID
 	if threadID != "" && (a.State == StateDone || a.State == StateError) {
 		a.mu.Unlock()
-		return a.resumeWithMessage(threadID, content)
+		if err := a.resumeWithMessage(threadID, content); err != nil {
+			return err
+		}
+		a.recordUserTurn(content)
+		return nil
 	}
 
 	a.mu.Unlock()
 	return ErrProcessNotStarted
 }
 
+// recordUserTurn adds a user message to the chat history.
+// The history has its own lock, so this is safe with or without a.mu held.
+func (a *Agent) recordUserTurn(content string) {
+	a.history.Add(ChatEntry{
+		Role:      "user",
+		Content:   content,
+		Timestamp: time.Now(),
+	})
+}
+
 // sendMessageLocked sends a message while holding the lock.
 //
 // +checklocks:a.mu
@@ -608,22 +835,17 @@ func (a *Agent) resumeWithMessage(threadID, content string) error {
 		return ErrProcessAlreadyRuns
 	}
 
-	// Determine working directory
-	workDir := ""
-	if a.Worktree != nil {
-		workDir = a.Worktree.Path
-	} else if a.Project != nil {
-		workDir = a.Project.RepoDir()
-	}
-
 	// Build command using the backend with thread ID for resume
 	cfg := backend.CommandConfig{
-		WorkDir:       workDir,
+		WorkDir:       a.workDir(),
 		AgentID:       a.ID,
 		InitialPrompt: content,
 		ThreadID:      threadID,
+		Model:         a.Model,
+		Env:           a.roleEnv(),
+		ReadOnly:      a.Role == rules.RoleReviewer,
 	}
-	cmd, err := a.Backend.BuildCommand(cfg)
+	cmd, err := backend.Command(a.Backend, cfg)
 	if err != nil {
 		a.mu.Unlock()
 		return err
@@ -642,6 +864,9 @@ func (a *Agent) resumeWithMessage(threadID, content string) error {
 		a.mu.Unlock()
 		return err
 	}
+	if a.debugCapture.Load() != nil {
+		cmd.Stderr = captureWriter{agent: a}
+	}
 
 	// Start the process
 	if err := cmd.Start(); err != nil {
@@ -652,7 +877,7 @@ func (a *Agent) resumeWithMessage(threadID, content string) error {
 	}
 
 	a.stdin = stdin
-	a.stdout = stdout
+	a.stdout = captureReader{ReadCloser: stdout, agent: a}
 	a.cmd = cmd
 	a.UpdatedAt = time.Now()
 	a.stopping = false
@@ -812,10 +1037,47 @@ func (a *Agent) Output(n int) []byte {
 	return result
 }
 
-// AddChatEntry adds a parsed chat entry to the history.
-// This is typically called by the read loop when parsing stream output.
-func (a *Agent) AddChatEntry(entry ChatEntry) {
-	a.history.Add(entry)
+// SetDebugCapture turns capture of the raw CLI output on or off. While on,
+// process stdout is appended to paths.DebugLogPath(agentID) before it is
+// parsed. Stderr is captured too for processes started while capture is on.
+func (a *Agent) SetDebugCapture(enabled bool) error {
+	if !enabled {
+		if c := a.debugCapture.Swap(nil); c != nil {
+			return c.Close()
+		}
+		return nil
+	}
+	if a.debugCapture.Load() != nil {
+		return nil
+	}
+	path, err := paths.DebugLogPath(a.ID)
+	if err != nil {
+		return err
+	}
+	c, err := NewDebugCapture(path, DefaultDebugCaptureMaxSize)
+	if err != nil {
+		return err
+	}
+	if !a.debugCapture.CompareAndSwap(nil, c) {
+		// Enabled concurrently; keep the existing capture
+		return c.Close()
+	}
+	return nil
+}
+
+// DebugCapturePath returns the debug capture log path, or "" if capture is off.
+func (a *Agent) DebugCapturePath() string {
+	if c := a.debugCapture.Load(); c != nil {
+		return c.Path()
+	}
+	return ""
+}
+
+// AddChatEntry adds a parsed chat entry to the history and returns its
+// sequence number. This is typically called by the read loop when parsing
+// stream output.
+func (a *Agent) AddChatEntry(entry ChatEntry) int64 {
+	return a.history.Add(entry)
 }
 
 // ReadLoopConfig configures the read loop behavior.
@@ -824,14 +1086,29 @@ type ReadLoopConfig struct {
 	// The callback receives the parsed entry. It should not block.
 	OnEntry func(entry ChatEntry)
 
+	// OnDelta is called with each chunk of assistant text as it streams in.
+	// The complete text is delivered afterwards through OnEntry. Deltas are
+	// not recorded in the chat history. It should not block.
+	OnDelta func(text string)
+
 	// OnOutput is called with the raw JSONL data for each line.
 	// This is useful for broadcasting raw output.
 	OnOutput func(data []byte)
 
-	// OnError is called when a read/parse error occurs (other than EOF).
+	// OnError is called when a read error occurs (other than EOF), and for
+	// parse errors when OnParseError is nil.
 	// If nil, errors are silently ignored.
 	OnError func(err error)
 
+	// OnParseError is called with each line that isn't valid stream-json.
+	// The line is skipped and the read loop continues.
+	OnParseError func(line []byte, err error)
+
+	// StuckTimeout is how long to wait after the read loop starts for the
+	// first valid stream-json message before marking the agent stuck.
+	// Zero disables the watchdog.
+	StuckTimeout time.Duration
+
 	// OnExit is called when the process exits (clean or crash).
 	// The callback receives nil for clean exit, non-nil error for crash.
 	// This is useful for releasing resources when an agent terminates unexpectedly.
@@ -840,7 +1117,7 @@ type ReadLoopConfig struct {
 
 // DefaultReadLoopConfig returns the default read loop configuration.
 func DefaultReadLoopConfig() ReadLoopConfig {
-	return ReadLoopConfig{}
+	return ReadLoopConfig{StuckTimeout: DefaultStuckTimeout}
 }
 
 // StartReadLoop starts a goroutine that continuously reads JSONL from stdout.
@@ -896,9 +1173,26 @@ func (a *Agent) runReadLoop(cfg ReadLoopConfig) {
 		return
 	}
 
+	// Watchdog: flag the agent if the CLI never produces valid stream-json
+	var gotValid atomic.Bool
+	if cfg.StuckTimeout > 0 {
+		watchdog := time.AfterFunc(cfg.StuckTimeout, func() {
+			if gotValid.Load() || a.GetState() != StateStarting {
+				return
+			}
+			log.Warn("readloop: no valid stream-json received, marking agent stuck", "timeout", cfg.StuckTimeout)
+			_ = a.MarkStuck()
+		})
+		defer watchdog.Stop()
+	}
+
 	scanner := bufio.NewScanner(stdout)
 	scanner.Buffer(make([]byte, 0, 64*1024), MaxScanTokenSize)
 
+	// Deltas are scrubbed as one stream, so a secret split across chunks
+	// is still caught
+	deltas := a.redactor.NewStream()
+
 	for scanner.Scan() {
 		// Check for stop signal
 		select {
@@ -914,14 +1208,23 @@ func (a *Agent) runReadLoop(cfg ReadLoopConfig) {
 
 		// Call raw output callback
 		if cfg.OnOutput != nil {
-			cfg.OnOutput(line)
+			if a.redactor != nil {
+				cfg.OnOutput([]byte(a.redactor.Redact(string(line))))
+			} else {
+				cfg.OnOutput(line)
+			}
 		}
 
 		// Parse the JSONL line as a StreamMessage
+		// Malformed lines are skipped so one bad frame can't stall the agent
 		msg, err := ParseStreamMessage(line)
 		if err != nil {
-			log.Warn("readloop: parse error", "error", err, "line", string(line))
-			if cfg.OnError != nil {
+			// The line is logged and broadcast, so scrub it like any output
+			redacted := a.redactor.Redact(string(line))
+			log.Warn("readloop: parse error", "error", err, "line", logging.TruncateForLog(redacted, 200))
+			if cfg.OnParseError != nil {
+				cfg.OnParseError([]byte(redacted), err)
+			} else if cfg.OnError != nil {
 				cfg.OnError(err)
 			}
 			continue
@@ -930,6 +1233,7 @@ func (a *Agent) runReadLoop(cfg ReadLoopConfig) {
 		if msg == nil {
 			continue
 		}
+		gotValid.Store(true)
 
 		// Capture thread ID from system init messages (Codex thread.started)
 		if msg.ThreadID != "" {
@@ -937,6 +1241,22 @@ func (a *Agent) runReadLoop(cfg ReadLoopConfig) {
 			log.Debug("readloop: captured thread ID", "thread_id", msg.ThreadID)
 		}
 
+		// Partial text is forwarded as-is; the complete message that follows
+		// produces the chat entry
+		if delta := msg.TextDelta(); delta != "" {
+			if text := deltas.Write(delta); text != "" && cfg.OnDelta != nil {
+				cfg.OnDelta(text)
+			}
+			if state := a.GetState(); state == StateStarting || state == StateStuck {
+				_ = a.MarkRunning()
+			}
+			continue
+		}
+		if msg.Type != "stream_event" {
+			// The complete message carries whatever the deltas held back
+			deltas.Reset()
+		}
+
 		// Log system messages (init, hook_response) that don't produce chat entries
 		if msg.Type == "system" {
 			log.Info("readloop: system message", "subtype", msg.Subtype)
@@ -963,6 +1283,7 @@ func (a *Agent) runReadLoop(cfg ReadLoopConfig) {
 				"cache_creation", u.CacheCreationInputTokens,
 				"cache_read", u.CacheReadInputTokens,
 			)
+			a.recordUsage(*u)
 		}
 
 		// Log stop reason when present (debug level to reduce noise)
@@ -978,10 +1299,15 @@ func (a *Agent) runReadLoop(cfg ReadLoopConfig) {
 			contentBlocks = len(msg.Message.Content)
 			role = msg.Message.Role
 
-			// Log any unknown content block types
+			// Track file-oriented tool calls and log unknown content block types
 			for _, block := range msg.Message.Content {
 				switch block.Type {
-				case "text", "tool_use", "tool_result", "thinking":
+				case "tool_use":
+					// Track the file the agent is working in for the agent list
+					if path := toolFilePath(block.Name, block.Input); path != "" {
+						a.SetCurrentFile(path)
+					}
+				case "text", "tool_result", "thinking":
 					// Known types - no warning needed
 				default:
 					log.Warn("readloop: unknown content block type", "type", block.Type)
@@ -995,7 +1321,8 @@ func (a *Agent) runReadLoop(cfg ReadLoopConfig) {
 			"entries", len(entries),
 		)
 		for _, entry := range entries {
-			a.AddChatEntry(entry)
+			entry = a.redactor.RedactEntry(entry)
+			entry.Seq = a.AddChatEntry(entry)
 
 			// Call entry callback
 			if cfg.OnEntry != nil {
@@ -1003,8 +1330,8 @@ func (a *Agent) runReadLoop(cfg ReadLoopConfig) {
 			}
 		}
 
-		// Transition to running if we were starting
-		if a.GetState() == StateStarting {
+		// Transition to running if we were starting or had been flagged stuck
+		if state := a.GetState(); state == StateStarting || state == StateStuck {
 			_ = a.MarkRunning()
 		}
 	}
@@ -1073,3 +1400,29 @@ func (a *Agent) IsReadLoopRunning() bool {
 		return true
 	}
 }
+
+// workDir returns the directory the CLI runs in: the agent's subdirectory of
+// its worktree, or the project's main clone if it has no worktree.
+//
+// +checklocks:a.mu
+func (a *Agent) workDir() string {
+	switch {
+	case a.Worktree != nil:
+		return filepath.Join(a.Worktree.Path, a.Subdir)
+	case a.Project != nil:
+		return a.Project.RepoDir()
+	default:
+		return ""
+	}
+}
+
+// roleEnv returns the environment that carries the agent's role to its
+// permission hook.
+//
+// +checklocks:a.mu
+func (a *Agent) roleEnv() []string {
+	if a.Role == "" {
+		return nil
+	}
+	return []string{rules.RoleEnvVar + "=" + a.Role}
+}
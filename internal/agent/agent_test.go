@@ -2,8 +2,15 @@ package agent
 
 import (
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/tessro/fab/internal/project"
 )
 
 func TestAgent_StateTransitions(t *testing.T) {
@@ -362,3 +369,279 @@ func TestFlexContent_EmptyArray(t *testing.T) {
 		t.Errorf("expected empty string, got %q", msg.Message.Content[0].Content)
 	}
 }
+
+func TestToolFilePath(t *testing.T) {
+	tests := []struct {
+		name  string
+		tool  string
+		input string
+		want  string
+	}{
+		{"read", "Read", `{"file_path":"/wt/main.go"}`, "/wt/main.go"},
+		{"edit", "Edit", `{"file_path":"/wt/a.go","old_string":"x"}`, "/wt/a.go"},
+		{"notebook", "NotebookEdit", `{"notebook_path":"/wt/n.ipynb"}`, "/wt/n.ipynb"},
+		{"bash ignored", "Bash", `{"command":"cat /wt/main.go"}`, ""},
+		{"invalid json", "Write", `not json`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toolFilePath(tt.tool, []byte(tt.input)); got != tt.want {
+				t.Errorf("toolFilePath(%q) = %q, want %q", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgent_SetCurrentFile(t *testing.T) {
+	a := New("test-1", nil, &project.Worktree{Path: "/wt"})
+	changes := 0
+	a.OnInfoChange(func() { changes++ })
+
+	a.SetCurrentFile("/wt/internal/main.go")
+	if got := a.Info().CurrentFile; got != "internal/main.go" {
+		t.Errorf("CurrentFile = %q, want path relative to worktree", got)
+	}
+
+	// Same file again shouldn't fire another info change
+	a.SetCurrentFile("/wt/internal/main.go")
+	if changes != 1 {
+		t.Errorf("info changes = %d, want 1", changes)
+	}
+
+	a.SetCurrentFile("/etc/hosts")
+	if got := a.Info().CurrentFile; got != "/etc/hosts" {
+		t.Errorf("CurrentFile = %q, want absolute path outside worktree", got)
+	}
+	if changes != 2 {
+		t.Errorf("info changes = %d, want 2", changes)
+	}
+}
+
+func TestAgent_SetSubdir(t *testing.T) {
+	wt := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(wt, "services", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wt, "README.md"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(t.TempDir(), filepath.Join(wt, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	a := New("test-1", nil, &project.Worktree{Path: wt})
+	changes := 0
+	a.OnInfoChange(func() { changes++ })
+
+	if err := a.SetSubdir("services/api/"); err != nil {
+		t.Fatalf("SetSubdir() error = %v", err)
+	}
+	if got := a.Info().Subdir; got != filepath.Join("services", "api") {
+		t.Errorf("Subdir = %q, want services/api", got)
+	}
+	a.mu.Lock()
+	workDir := a.workDir()
+	a.mu.Unlock()
+	if workDir != filepath.Join(wt, "services", "api") {
+		t.Errorf("workDir() = %q, want the subdirectory of the worktree", workDir)
+	}
+
+	for _, bad := range []string{"../other", "/etc", "missing", "README.md", "escape"} {
+		if err := a.SetSubdir(bad); err == nil {
+			t.Errorf("SetSubdir(%q) succeeded, want an error", bad)
+		}
+	}
+	if got := a.Info().Subdir; got != filepath.Join("services", "api") {
+		t.Errorf("Subdir = %q after invalid subdirs, want it unchanged", got)
+	}
+
+	if err := a.SetSubdir("."); err != nil || a.Info().Subdir != "" {
+		t.Errorf("SetSubdir(.) = %v, Subdir %q; want it cleared", err, a.Info().Subdir)
+	}
+	if changes != 2 {
+		t.Errorf("info changes = %d, want 2", changes)
+	}
+}
+
+func TestAgent_ReadLoop_SkipsMalformedLines(t *testing.T) {
+	a := New("test-1", nil, nil)
+	output := strings.Join([]string{
+		`not json at all`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"first"}]}}`,
+		`{"type":"assistant","message":`,
+		`Reading prompt...`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"second"}]}}`,
+	}, "\n") + "\n"
+	a.mu.Lock()
+	a.stdout = io.NopCloser(strings.NewReader(output))
+	a.mu.Unlock()
+
+	var mu sync.Mutex
+	var entries []string
+	var badLines []string
+	cfg := DefaultReadLoopConfig()
+	cfg.OnEntry = func(entry ChatEntry) {
+		mu.Lock()
+		entries = append(entries, entry.Content)
+		mu.Unlock()
+	}
+	cfg.OnParseError = func(line []byte, err error) {
+		mu.Lock()
+		badLines = append(badLines, string(line))
+		mu.Unlock()
+	}
+	if err := a.StartReadLoop(cfg); err != nil {
+		t.Fatalf("StartReadLoop() error = %v", err)
+	}
+	<-a.readLoopDone
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(entries) != 2 || entries[0] != "first" || entries[1] != "second" {
+		t.Errorf("entries = %q, want [first second]", entries)
+	}
+	if len(badLines) != 3 {
+		t.Errorf("parse errors = %d (%q), want 3", len(badLines), badLines)
+	}
+	if got := a.GetState(); got != StateDone {
+		t.Errorf("state = %s, want %s after clean exit", got, StateDone)
+	}
+}
+
+func TestAgent_ReadLoop_RecordsUsage(t *testing.T) {
+	a := New("test-1", nil, nil)
+	output := strings.Join([]string{
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"one"}],"usage":{"input_tokens":10,"output_tokens":5}}}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"two"}],"usage":{"input_tokens":3,"output_tokens":7,"cache_read_input_tokens":900}}}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"three"}]}}`,
+	}, "\n") + "\n"
+	a.mu.Lock()
+	a.stdout = io.NopCloser(strings.NewReader(output))
+	a.mu.Unlock()
+
+	if err := a.StartReadLoop(DefaultReadLoopConfig()); err != nil {
+		t.Fatalf("StartReadLoop() error = %v", err)
+	}
+	<-a.readLoopDone
+
+	u := a.Usage()
+	if u.InputTokens != 3 || u.OutputTokens != 7 || u.CacheReadInputTokens != 900 {
+		t.Errorf("Usage() = %+v, want the last reported usage", u)
+	}
+}
+
+func TestAgent_ReadLoop_Deltas(t *testing.T) {
+	a := New("test-1", nil, nil)
+	output := strings.Join([]string{
+		`{"type":"stream_event","event":{"type":"message_start"}}`,
+		`{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"lo"}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{"}}}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Hello"}]}}`,
+	}, "\n") + "\n"
+	a.mu.Lock()
+	a.stdout = io.NopCloser(strings.NewReader(output))
+	a.mu.Unlock()
+
+	var mu sync.Mutex
+	var deltas, entries []string
+	cfg := DefaultReadLoopConfig()
+	cfg.OnDelta = func(text string) {
+		mu.Lock()
+		deltas = append(deltas, text)
+		mu.Unlock()
+	}
+	cfg.OnEntry = func(entry ChatEntry) {
+		mu.Lock()
+		entries = append(entries, entry.Content)
+		mu.Unlock()
+	}
+	if err := a.StartReadLoop(cfg); err != nil {
+		t.Fatalf("StartReadLoop() error = %v", err)
+	}
+	<-a.readLoopDone
+
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(deltas, "|") != "Hel|lo" {
+		t.Errorf("deltas = %q, want [Hel lo]", deltas)
+	}
+	if len(entries) != 1 || entries[0] != "Hello" {
+		t.Errorf("entries = %q, want [Hello]", entries)
+	}
+	if n := a.History().Len(); n != 1 {
+		t.Errorf("history has %d entries, want only the complete message", n)
+	}
+}
+
+func TestAgent_ReadLoop_StuckWatchdog(t *testing.T) {
+	a := New("test-1", nil, nil)
+	r, w := io.Pipe()
+	a.mu.Lock()
+	a.stdout = r
+	a.mu.Unlock()
+
+	cfg := DefaultReadLoopConfig()
+	cfg.StuckTimeout = 20 * time.Millisecond
+	if err := a.StartReadLoop(cfg); err != nil {
+		t.Fatalf("StartReadLoop() error = %v", err)
+	}
+
+	// Garbage doesn't count as progress
+	if _, err := w.Write([]byte("Reading prompt...\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for a.GetState() != StateStuck && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := a.GetState(); got != StateStuck {
+		t.Fatalf("state = %s, want %s", got, StateStuck)
+	}
+
+	// A valid frame recovers the agent
+	if _, err := w.Write([]byte(`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"hi"}]}}` + "\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	deadline = time.Now().Add(2 * time.Second)
+	for a.GetState() != StateRunning && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := a.GetState(); got != StateRunning {
+		t.Errorf("state = %s, want %s after valid output", got, StateRunning)
+	}
+
+	w.Close()
+	<-a.readLoopDone
+}
+
+func TestAgent_SetModel(t *testing.T) {
+	a := New("test-1", nil, nil)
+	changes := 0
+	a.OnInfoChange(func() { changes++ })
+
+	a.SetModel("opus")
+	a.SetModel("opus")
+	if got := a.Info().Model; got != "opus" {
+		t.Errorf("Model = %q, want %q", got, "opus")
+	}
+	if changes != 1 {
+		t.Errorf("info changes = %d, want 1", changes)
+	}
+}
+
+func TestAgent_SetEpic(t *testing.T) {
+	a := New("test-1", nil, nil)
+	changes := 0
+	a.OnInfoChange(func() { changes++ })
+
+	a.SetEpic("FAB-100")
+	a.SetEpic("FAB-100")
+	if got := a.Info().Epic; got != "FAB-100" {
+		t.Errorf("Epic = %q, want %q", got, "FAB-100")
+	}
+	a.SetEpic("")
+	if changes != 2 {
+		t.Errorf("info changes = %d, want 2", changes)
+	}
+}
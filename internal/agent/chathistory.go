@@ -2,22 +2,52 @@
 package agent
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"sync"
 )
 
 // DefaultChatHistorySize is the default number of chat entries to retain.
 const DefaultChatHistorySize = 1000
 
+// DefaultChatSpillLimit is the size in bytes past which a spill file is
+// trimmed to its newer half.
+const DefaultChatSpillLimit = 64 << 20
+
+// RoleSummary is the role of the entry that stands in for an agent's
+// earlier history after it is compacted (see ChatHistory.Compact).
+const RoleSummary = "system"
+
 // ChatHistory stores parsed chat messages in a circular buffer.
+// If a spill path is set, evicted entries are appended to that file so older
+// history can still be paged in. The file is kept open and indexed by entry,
+// so paging reads only the requested entries.
 type ChatHistory struct {
 	// +checklocks:mu
-	entries []ChatEntry
-	maxSize int // Maximum number of entries (immutable after creation)
+	entries    []ChatEntry
+	maxSize    int   // Maximum number of entries (immutable after creation)
+	spillLimit int64 // Spill file size that triggers trimming (immutable after creation)
 	// +checklocks:mu
 	head int // Next write position
 	// +checklocks:mu
 	count int // Current number of entries stored
-	mu    sync.RWMutex
+	// +checklocks:mu
+	total int64 // Number of entries ever added; the next entry's sequence number
+	// +checklocks:mu
+	spillPath string // JSONL file receiving evicted entries ("" = discard them)
+	// +checklocks:mu
+	spillStart int64 // Sequence number of the oldest spilled entry
+	// +checklocks:mu
+	spillEnd int64 // One past the newest spilled entry (spillStart == spillEnd means none)
+	// +checklocks:mu
+	spillFile *os.File // Open spill file, created by the first eviction
+	// +checklocks:mu
+	spillOffsets []int64 // File offset of each spilled entry from spillStart, then the end of the file
+	mu           sync.RWMutex
 }
 
 // NewChatHistory creates a new chat history with the given max size.
@@ -27,21 +57,179 @@ func NewChatHistory(maxSize int) *ChatHistory {
 		maxSize = DefaultChatHistorySize
 	}
 	return &ChatHistory{
-		entries: make([]ChatEntry, maxSize),
-		maxSize: maxSize,
+		entries:    make([]ChatEntry, maxSize),
+		maxSize:    maxSize,
+		spillLimit: DefaultChatSpillLimit,
+	}
+}
+
+// SetSpillPath sets the file that evicted entries are appended to.
+// An empty path discards evicted entries.
+func (h *ChatHistory) SetSpillPath(path string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closeSpill()
+	h.spillPath = path
+}
+
+// RemoveSpill deletes the spill file, if any. Entries spilled so far are
+// no longer available to Page.
+func (h *ChatHistory) RemoveSpill() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeSpill()
+}
+
+// +checklocks:h.mu
+func (h *ChatHistory) removeSpill() {
+	h.closeSpill()
+	if h.spillPath != "" {
+		if err := os.Remove(h.spillPath); err != nil && !os.IsNotExist(err) {
+			slog.Warn("failed to remove chat history spill file", "path", h.spillPath, "error", err)
+		}
 	}
 }
 
+// closeSpill closes the spill file and forgets the entries spilled to it.
+//
+// +checklocks:h.mu
+func (h *ChatHistory) closeSpill() {
+	if h.spillFile != nil {
+		h.spillFile.Close()
+		h.spillFile = nil
+	}
+	h.spillOffsets = nil
+	h.spillStart = h.total - int64(h.count)
+	h.spillEnd = h.spillStart
+}
+
 // Add appends a chat entry, evicting oldest if at capacity.
-func (h *ChatHistory) Add(entry ChatEntry) {
+// The entry is stamped with its sequence number, which is returned.
+func (h *ChatHistory) Add(entry ChatEntry) int64 {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	if h.count == h.maxSize {
+		h.spill(h.entries[h.head])
+	}
+
+	entry.Seq = h.total
 	h.entries[h.head] = entry
 	h.head = (h.head + 1) % h.maxSize
 	if h.count < h.maxSize {
 		h.count++
 	}
+	h.total++
+	return entry.Seq
+}
+
+// spill appends an evicted entry to the spill file. Spilling stops at the
+// first write failure so the spilled range stays contiguous.
+//
+// +checklocks:h.mu
+func (h *ChatHistory) spill(entry ChatEntry) {
+	if h.spillPath == "" || entry.Seq != h.spillEnd {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err == nil {
+		err = h.writeSpill(append(data, '\n'))
+	}
+	if err != nil {
+		slog.Warn("failed to spill chat history, older entries will be dropped", "path", h.spillPath, "error", err)
+		return
+	}
+	h.spillEnd++
+
+	if h.spillOffsets[len(h.spillOffsets)-1] > h.spillLimit {
+		if err := h.trimSpill(); err != nil {
+			slog.Warn("failed to trim chat history spill file, older entries will be dropped", "path", h.spillPath, "error", err)
+			h.removeSpill()
+			h.spillPath = ""
+		}
+	}
+}
+
+// writeSpill writes line at the end of the spill file, creating the file
+// (and its directory) on first use.
+//
+// +checklocks:h.mu
+func (h *ChatHistory) writeSpill(line []byte) error {
+	if h.spillFile == nil {
+		if err := os.MkdirAll(filepath.Dir(h.spillPath), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(h.spillPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		h.spillFile = f
+		h.spillOffsets = []int64{0}
+	}
+	end := h.spillOffsets[len(h.spillOffsets)-1]
+	if _, err := h.spillFile.WriteAt(line, end); err != nil {
+		return err
+	}
+	h.spillOffsets = append(h.spillOffsets, end+int64(len(line)))
+	return nil
+}
+
+// trimSpill drops the older half of the spilled entries by copying the
+// newer half to a fresh file that replaces the spill file.
+//
+// +checklocks:h.mu
+func (h *ChatHistory) trimSpill() error {
+	drop := len(h.spillOffsets) / 2
+	from := h.spillOffsets[drop]
+	end := h.spillOffsets[len(h.spillOffsets)-1]
+
+	tmp, err := os.OpenFile(h.spillPath+".tmp", os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, io.NewSectionReader(h.spillFile, from, end-from)); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), h.spillPath); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	h.spillFile.Close()
+	h.spillFile = tmp
+	offsets := make([]int64, 0, len(h.spillOffsets)-drop)
+	for _, off := range h.spillOffsets[drop:] {
+		offsets = append(offsets, off-from)
+	}
+	h.spillOffsets = offsets
+	h.spillStart += int64(drop)
+	return nil
+}
+
+// readSpilled returns spilled entries with sequence numbers in [start, end),
+// reading only their part of the spill file.
+//
+// +checklocks:h.mu
+func (h *ChatHistory) readSpilled(start, end int64) ([]ChatEntry, error) {
+	if h.spillFile == nil {
+		return nil, errors.New("no spill file")
+	}
+	from := h.spillOffsets[start-h.spillStart]
+	to := h.spillOffsets[end-h.spillStart]
+	dec := json.NewDecoder(io.NewSectionReader(h.spillFile, from, to-from))
+
+	result := make([]ChatEntry, 0, end-start)
+	for range end - start {
+		var entry ChatEntry
+		if err := dec.Decode(&entry); err != nil {
+			return nil, err
+		}
+		result = append(result, entry)
+	}
+	return result, nil
 }
 
 // Entries returns the last n entries (or all if n <= 0).
@@ -79,6 +267,56 @@ func (h *ChatHistory) Entries(n int) []ChatEntry {
 	return result
 }
 
+// Page returns up to n entries (or all if n <= 0) immediately preceding the
+// entry with sequence number before, in chronological order. If before <= 0,
+// the page ends at the newest entry. Sequence numbers count every entry ever
+// added, so they stay stable as old entries are evicted. Evicted entries are
+// read back from the spill file when one is set.
+//
+// start is the sequence number of the first returned entry; pass it as before
+// to fetch the next older page. more reports whether older entries remain.
+func (h *ChatHistory) Page(before int64, n int) (page []ChatEntry, start int64, more bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	oldest := h.total - int64(h.count)
+	if h.spillStart < h.spillEnd && h.spillEnd == oldest {
+		oldest = h.spillStart
+	}
+	end := h.total
+	if before > 0 && before < end {
+		end = before
+	}
+	if end <= oldest {
+		return nil, oldest, false
+	}
+
+	start = oldest
+	if n > 0 && end-int64(n) > oldest {
+		start = end - int64(n)
+	}
+
+	// Entries older than the in-memory buffer come from the spill file
+	inMemory := h.total - int64(h.count)
+	if start < inMemory {
+		spilled, err := h.readSpilled(start, min(end, inMemory))
+		if err != nil {
+			slog.Warn("failed to read spilled chat history", "path", h.spillPath, "error", err)
+			start = min(end, inMemory)
+			oldest = start
+		} else {
+			page = spilled
+		}
+	}
+
+	for seq := max(start, inMemory); seq < end; seq++ {
+		// The newest entry (sequence total-1) sits just before head.
+		back := int(h.total - seq)
+		page = append(page, h.entries[(h.head-back+h.maxSize)%h.maxSize])
+	}
+	return page, start, start > oldest
+}
+
 // All returns all entries in chronological order.
 func (h *ChatHistory) All() []ChatEntry {
 	return h.Entries(-1)
@@ -91,7 +329,8 @@ func (h *ChatHistory) Len() int {
 	return h.count
 }
 
-// Clear removes all entries.
+// Clear removes all entries, including any spilled to disk.
+// Sequence numbers keep increasing so outstanding Page cursors stay valid.
 func (h *ChatHistory) Clear() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -101,4 +340,28 @@ func (h *ChatHistory) Clear() {
 	}
 	h.head = 0
 	h.count = 0
+	h.removeSpill()
+}
+
+// Compact replaces all entries, including any spilled to disk, with a single
+// summary entry, and returns its sequence number. Sequence numbers keep
+// increasing so outstanding Page cursors stay valid.
+func (h *ChatHistory) Compact(summary ChatEntry) int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := range h.entries {
+		h.entries[i] = ChatEntry{}
+	}
+	h.head = 0
+	h.count = 0
+	h.removeSpill()
+
+	summary.Role = RoleSummary
+	summary.Seq = h.total
+	h.entries[0] = summary
+	h.head = 1 % h.maxSize
+	h.count = 1
+	h.total++
+	return summary.Seq
 }
@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChatHistory_Page(t *testing.T) {
+	h := NewChatHistory(5)
+	for i := range 8 {
+		h.Add(ChatEntry{Content: fmt.Sprint(i)})
+	}
+	// Entries 0-2 were evicted; 3-7 remain.
+
+	contents := func(entries []ChatEntry) string {
+		var s string
+		for _, e := range entries {
+			s += e.Content
+		}
+		return s
+	}
+
+	page, start, more := h.Page(0, 2)
+	if got := contents(page); got != "67" || start != 6 || !more {
+		t.Fatalf("Page(0, 2) = %q, %d, %v; want \"67\", 6, true", got, start, more)
+	}
+
+	page, start, more = h.Page(start, 2)
+	if got := contents(page); got != "45" || start != 4 || !more {
+		t.Fatalf("Page(6, 2) = %q, %d, %v; want \"45\", 4, true", got, start, more)
+	}
+
+	page, start, more = h.Page(start, 2)
+	if got := contents(page); got != "3" || start != 3 || more {
+		t.Fatalf("Page(4, 2) = %q, %d, %v; want \"3\", 3, false", got, start, more)
+	}
+
+	// A cursor that points at evicted entries returns nothing.
+	if page, _, more := h.Page(2, 2); len(page) != 0 || more {
+		t.Errorf("Page(2, 2) = %d entries, more=%v; want none", len(page), more)
+	}
+
+	// n <= 0 returns everything before the cursor.
+	if page, _, _ := h.Page(0, 0); contents(page) != "34567" {
+		t.Errorf("Page(0, 0) = %q, want \"34567\"", contents(page))
+	}
+
+	// Cursors stay valid across Clear.
+	h.Clear()
+	h.Add(ChatEntry{Content: "8"})
+	if page, start, more := h.Page(0, 10); contents(page) != "8" || start != 8 || more {
+		t.Errorf("after Clear, Page(0, 10) = %q, %d, %v; want \"8\", 8, false", contents(page), start, more)
+	}
+}
+
+func TestChatHistory_PageSpilled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "agent.jsonl")
+	h := NewChatHistory(3)
+	h.SetSpillPath(path)
+	for i := range 8 {
+		if seq := h.Add(ChatEntry{Content: fmt.Sprint(i)}); seq != int64(i) {
+			t.Fatalf("Add() = %d, want %d", seq, i)
+		}
+	}
+	// Entries 0-4 were spilled to disk; 5-7 remain in memory.
+
+	contents := func(entries []ChatEntry) string {
+		var s string
+		for _, e := range entries {
+			s += e.Content
+		}
+		return s
+	}
+
+	page, start, more := h.Page(0, 4)
+	if got := contents(page); got != "4567" || start != 4 || !more {
+		t.Fatalf("Page(0, 4) = %q, %d, %v; want \"4567\", 4, true", got, start, more)
+	}
+
+	page, start, more = h.Page(start, 10)
+	if got := contents(page); got != "0123" || start != 0 || more {
+		t.Fatalf("Page(4, 10) = %q, %d, %v; want \"0123\", 0, false", got, start, more)
+	}
+	for i, e := range page {
+		if e.Seq != int64(i) {
+			t.Errorf("page[%d].Seq = %d, want %d", i, e.Seq, i)
+		}
+	}
+
+	// Clear removes the spill file.
+	h.Clear()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("spill file still exists after Clear: %v", err)
+	}
+	if page, _, _ := h.Page(0, 0); len(page) != 0 {
+		t.Errorf("after Clear, Page(0, 0) = %d entries, want 0", len(page))
+	}
+}
+
+func TestChatHistory_SpillTrimmed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.jsonl")
+	h := NewChatHistory(2)
+	h.spillLimit = 500
+	h.SetSpillPath(path)
+	for i := range 40 {
+		h.Add(ChatEntry{Content: fmt.Sprint(i)})
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Size() > 500 {
+		t.Errorf("spill file size = %d, want at most 500", info.Size())
+	}
+
+	// The oldest entries were dropped; the rest page in order up to memory
+	page, start, more := h.Page(0, 0)
+	if start == 0 || more {
+		t.Fatalf("Page(0, 0) start = %d, more = %v; want older entries trimmed", start, more)
+	}
+	for i, e := range page {
+		if e.Seq != start+int64(i) || e.Content != fmt.Sprint(e.Seq) {
+			t.Fatalf("page[%d] = seq %d %q, want seq %d", i, e.Seq, e.Content, start+int64(i))
+		}
+	}
+	if last := page[len(page)-1].Seq; last != 39 {
+		t.Errorf("newest entry seq = %d, want 39", last)
+	}
+}
+
+func TestChatHistory_Compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "agent.jsonl")
+	h := NewChatHistory(3)
+	h.SetSpillPath(path)
+	for i := range 5 {
+		h.Add(ChatEntry{Role: "user", Content: fmt.Sprint(i)})
+	}
+
+	if seq := h.Compact(ChatEntry{Content: "summary"}); seq != 5 {
+		t.Errorf("Compact() = %d, want 5", seq)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("spill file still exists after Compact: %v", err)
+	}
+	page, start, more := h.Page(0, 0)
+	if len(page) != 1 || page[0].Role != RoleSummary || page[0].Content != "summary" || start != 5 || more {
+		t.Fatalf("after Compact, Page(0, 0) = %+v, %d, %v; want only the summary", page, start, more)
+	}
+
+	// New entries follow the summary and spill as usual.
+	for i := range 4 {
+		h.Add(ChatEntry{Content: fmt.Sprint(i)})
+	}
+	if page, _, _ := h.Page(0, 0); len(page) != 5 || page[0].Role != RoleSummary {
+		t.Errorf("Page(0, 0) = %+v, want the summary and 4 entries", page)
+	}
+}
@@ -0,0 +1,134 @@
+package agent
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultDebugCaptureMaxSize is the size at which a debug capture log is
+// rotated. One rotated file (<path>.1) is kept, so a capture uses at most
+// twice this much disk.
+const DefaultDebugCaptureMaxSize = 10 * 1024 * 1024 // 10MB
+
+// DebugCapture appends raw CLI output to a log file, rotating it once it
+// exceeds a size cap. It is safe for concurrent use.
+type DebugCapture struct {
+	path    string
+	maxSize int64
+
+	mu sync.Mutex
+	// +checklocks:mu
+	f *os.File
+	// +checklocks:mu
+	size int64
+}
+
+// NewDebugCapture opens (or creates) the capture log at path.
+// A maxSize <= 0 uses DefaultDebugCaptureMaxSize.
+func NewDebugCapture(path string, maxSize int64) (*DebugCapture, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultDebugCaptureMaxSize
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &DebugCapture{path: path, maxSize: maxSize, f: f, size: info.Size()}, nil
+}
+
+// Path returns the capture log path.
+func (c *DebugCapture) Path() string {
+	return c.path
+}
+
+// Write appends p to the log, rotating first if p would push it past the cap.
+func (c *DebugCapture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.f == nil {
+		return 0, os.ErrClosed
+	}
+	if c.size > 0 && c.size+int64(len(p)) > c.maxSize {
+		if err := c.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := c.f.Write(p)
+	c.size += int64(n)
+	return n, err
+}
+
+// rotate moves the current log to <path>.1 and starts a new one.
+//
+// +checklocks:c.mu
+func (c *DebugCapture) rotate() error {
+	if err := c.f.Close(); err != nil {
+		return err
+	}
+	c.f = nil
+	if err := os.Rename(c.path, c.path+".1"); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	c.f = f
+	c.size = 0
+	return nil
+}
+
+// Close closes the log. Further writes fail with os.ErrClosed.
+func (c *DebugCapture) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.f == nil {
+		return nil
+	}
+	err := c.f.Close()
+	c.f = nil
+	return err
+}
+
+// captureReader tees everything read from the process stdout into the
+// agent's debug capture, if one is enabled.
+type captureReader struct {
+	io.ReadCloser
+	agent *Agent
+}
+
+func (r captureReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if c := r.agent.debugCapture.Load(); c != nil {
+			_, _ = c.Write(p[:n])
+		}
+	}
+	return n, err
+}
+
+// captureWriter receives the process stderr. Output is written to the debug
+// capture while one is enabled and discarded otherwise; it never fails, so
+// the process can't block on a full stderr pipe.
+type captureWriter struct {
+	agent *Agent
+}
+
+func (w captureWriter) Write(p []byte) (int, error) {
+	if c := w.agent.debugCapture.Load(); c != nil {
+		_, _ = c.Write(p)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDebugCapture_Rotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debug", "agent.log")
+	c, err := NewDebugCapture(path, 10)
+	if err != nil {
+		t.Fatalf("NewDebugCapture() error = %v", err)
+	}
+	defer c.Close()
+
+	for _, s := range []string{"aaaaaa", "bbbbbb", "cc"} {
+		if _, err := c.Write([]byte(s)); err != nil {
+			t.Fatalf("Write(%q) error = %v", s, err)
+		}
+	}
+
+	// "bbbbbb" would have exceeded the cap, so "aaaaaa" was rotated out
+	rotated, err := os.ReadFile(path + ".1")
+	if err != nil || string(rotated) != "aaaaaa" {
+		t.Errorf("rotated log = %q, %v; want \"aaaaaa\"", rotated, err)
+	}
+	current, err := os.ReadFile(path)
+	if err != nil || string(current) != "bbbbbbcc" {
+		t.Errorf("current log = %q, %v; want \"bbbbbbcc\"", current, err)
+	}
+
+	c.Close()
+	if _, err := c.Write([]byte("x")); err == nil {
+		t.Error("Write() after Close should fail")
+	}
+}
+
+func TestAgent_DebugCapture(t *testing.T) {
+	t.Setenv("FAB_DIR", t.TempDir())
+
+	a := New("dbg1", nil, nil)
+	if a.DebugCapturePath() != "" {
+		t.Fatal("debug capture should be off by default")
+	}
+	if err := a.SetDebugCapture(true); err != nil {
+		t.Fatalf("SetDebugCapture(true) error = %v", err)
+	}
+	path := a.DebugCapturePath()
+	if !strings.HasSuffix(path, filepath.Join("debug", "dbg1.log")) {
+		t.Errorf("DebugCapturePath() = %q, want .../debug/dbg1.log", path)
+	}
+
+	// Output read through the stdout tee lands in the log
+	r := captureReader{ReadCloser: io.NopCloser(strings.NewReader("{\"type\":\"system\"}\n")), agent: a}
+	if _, err := io.ReadAll(r); err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	// Stderr is captured too, and never fails the writer
+	if n, err := (captureWriter{agent: a}).Write([]byte("Reading prompt from stdin...\n")); n != 29 || err != nil {
+		t.Errorf("captureWriter.Write() = %d, %v", n, err)
+	}
+
+	if err := a.SetDebugCapture(false); err != nil {
+		t.Fatalf("SetDebugCapture(false) error = %v", err)
+	}
+	if n, err := (captureWriter{agent: a}).Write([]byte("dropped\n")); n != 8 || err != nil {
+		t.Errorf("captureWriter.Write() with capture off = %d, %v", n, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	want := "{\"type\":\"system\"}\nReading prompt from stdin...\n"
+	if string(data) != want {
+		t.Errorf("capture log = %q, want %q", data, want)
+	}
+}
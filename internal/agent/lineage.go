@@ -0,0 +1,37 @@
+package agent
+
+import "strings"
+
+// Spawners recorded in Agent.SpawnedBy. An agent spawned on behalf of
+// another agent records that agent's ID instead.
+const (
+	// SpawnedByUser marks agents created directly by the operator.
+	SpawnedByUser = "user"
+	// SpawnedByOrchestrator marks agents spawned by the orchestrator's
+	// capacity loop.
+	SpawnedByOrchestrator = "orchestrator"
+	// SpawnedByManager marks agents a project's manager delegated a ticket to.
+	SpawnedByManager = "manager"
+)
+
+// SpawnerFromDelegator maps the FAB_AGENT_ID of a delegation's caller to the
+// agent's spawner: "manager:<project>" becomes SpawnedByManager, an empty ID
+// (a delegation from a plain shell) becomes SpawnedByUser, and anything else
+// is taken to be the parent agent's ID.
+func SpawnerFromDelegator(delegatedBy string) string {
+	switch {
+	case delegatedBy == "":
+		return SpawnedByUser
+	case delegatedBy == SpawnedByManager, strings.HasPrefix(delegatedBy, SpawnedByManager+":"):
+		return SpawnedByManager
+	default:
+		return delegatedBy
+	}
+}
+
+// Delegated reports whether an agent with the given spawner was delegated its
+// ticket by a manager or another agent, rather than spawned by the operator
+// or the orchestrator.
+func Delegated(spawnedBy string) bool {
+	return spawnedBy != "" && spawnedBy != SpawnedByUser && spawnedBy != SpawnedByOrchestrator
+}
@@ -0,0 +1,36 @@
+package agent
+
+import "testing"
+
+func TestSpawnerFromDelegator(t *testing.T) {
+	tests := []struct {
+		delegatedBy string
+		want        string
+	}{
+		{"", SpawnedByUser},
+		{"manager:myproject", SpawnedByManager},
+		{"manager", SpawnedByManager},
+		{"a1b2c3", "a1b2c3"},
+		{"plan:x1y2", "plan:x1y2"},
+	}
+
+	for _, tt := range tests {
+		if got := SpawnerFromDelegator(tt.delegatedBy); got != tt.want {
+			t.Errorf("SpawnerFromDelegator(%q) = %q, want %q", tt.delegatedBy, got, tt.want)
+		}
+	}
+}
+
+func TestDelegated(t *testing.T) {
+	for spawner, want := range map[string]bool{
+		"":                    false,
+		SpawnedByUser:         false,
+		SpawnedByOrchestrator: false,
+		SpawnedByManager:      true,
+		"a1b2c3":              true,
+	} {
+		if got := Delegated(spawner); got != want {
+			t.Errorf("Delegated(%q) = %v, want %v", spawner, got, want)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/tessro/fab/internal/backend"
 	"github.com/tessro/fab/internal/event"
 	"github.com/tessro/fab/internal/id"
+	"github.com/tessro/fab/internal/paths"
 	"github.com/tessro/fab/internal/project"
 	"github.com/tessro/fab/internal/runtime"
 )
@@ -76,6 +77,17 @@ type Manager struct {
 	// May be nil if persistence is disabled.
 	runtimeStore *runtime.Store
 
+	// transcriptStore keeps chat history of deleted agents for replay.
+	// May be nil if persistence is disabled.
+	transcriptStore *runtime.TranscriptStore
+
+	// spillHistory makes agents spill evicted chat history to disk.
+	spillHistory bool
+
+	// redactor scrubs secrets from the output of newly created agents.
+	// May be nil if redaction is disabled.
+	redactor *Redactor
+
 	mu sync.RWMutex
 }
 
@@ -103,6 +115,109 @@ func (m *Manager) RuntimeStore() *runtime.Store {
 	return m.runtimeStore
 }
 
+// SetTranscriptStore sets the store used to save agent transcripts on delete.
+func (m *Manager) SetTranscriptStore(store *runtime.TranscriptStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transcriptStore = store
+}
+
+// SetHistorySpill enables spilling evicted chat history of newly created
+// agents to paths.ChatHistoryPath, so it can still be paged in.
+func (m *Manager) SetHistorySpill(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spillHistory = enabled
+}
+
+// SetRedactor sets the redactor applied to the output of agents created
+// or hydrated afterwards.
+func (m *Manager) SetRedactor(r *Redactor) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.redactor = r
+}
+
+// LoadTranscript returns the saved transcript of a deleted agent.
+// Returns runtime.ErrTranscriptNotFound if there is none (or no store is set).
+func (m *Manager) LoadTranscript(id string) (*runtime.Transcript, error) {
+	m.mu.RLock()
+	store := m.transcriptStore
+	m.mu.RUnlock()
+
+	if store == nil {
+		return nil, runtime.ErrTranscriptNotFound
+	}
+	return store.Load(id)
+}
+
+// saveAgentTranscript persists the agent's chat history to the transcript store.
+// Safe to call if transcriptStore is nil.
+func (m *Manager) saveAgentTranscript(agent *Agent) {
+	m.mu.RLock()
+	store := m.transcriptStore
+	m.mu.RUnlock()
+
+	if store == nil {
+		return
+	}
+	if err := m.SaveTranscript(agent); err != nil {
+		slog.Error("failed to save agent transcript", "agent", agent.ID, "error", err)
+	}
+}
+
+// SaveTranscript persists the agent's full chat history to the transcript
+// store. If the history was compacted, it starts with a summary standing in
+// for entries that were saved to the store before compacting; the entries
+// after the summary are appended to those instead.
+func (m *Manager) SaveTranscript(agent *Agent) error {
+	m.mu.RLock()
+	store := m.transcriptStore
+	m.mu.RUnlock()
+
+	if store == nil {
+		return errors.New("no transcript store")
+	}
+
+	// Include history spilled to disk
+	entries, _, _ := agent.History().Page(0, 0)
+	var prior []runtime.TranscriptEntry
+	if len(entries) > 0 && entries[0].Role == RoleSummary {
+		entries = entries[1:]
+		saved, err := store.Load(agent.ID)
+		if err != nil && !errors.Is(err, runtime.ErrTranscriptNotFound) {
+			return err
+		}
+		if saved != nil {
+			prior = saved.Entries
+		}
+	}
+	if len(prior)+len(entries) == 0 {
+		return nil
+	}
+
+	info := agent.Info()
+	t := runtime.Transcript{
+		AgentID: agent.ID,
+		Project: info.Project,
+		Backend: info.Backend,
+		Entries: make([]runtime.TranscriptEntry, 0, len(prior)+len(entries)),
+	}
+	t.Entries = append(t.Entries, prior...)
+	for _, e := range entries {
+		t.Entries = append(t.Entries, runtime.TranscriptEntry{
+			Role:       e.Role,
+			Content:    e.Content,
+			ToolName:   e.ToolName,
+			ToolInput:  e.ToolInput,
+			ToolResult: e.ToolResult,
+			IsError:    e.IsError,
+			Timestamp:  e.Timestamp,
+		})
+	}
+	return store.Save(t)
+}
+
 // saveAgentRuntime persists agent runtime metadata to the store.
 // Safe to call if runtimeStore is nil.
 func (m *Manager) saveAgentRuntime(agent *Agent) {
@@ -241,12 +356,25 @@ func (m *Manager) emitProjectEvent(e ProjectEvent) {
 // Create creates a new agent for the given project.
 // It creates a dedicated worktree for the agent and returns the new agent.
 // Uses the project's configured coding-backend (falling back to agent-backend, then "claude").
+// spawnedBy records the agent's lineage (see SpawnedByUser). priority puts
+// the agent in one of the project's reserved high-priority slots (see
+// project.CreateWorktreeForAgent).
 // Returns ErrNoCapacity if max agents reached.
-func (m *Manager) Create(proj *project.Project) (*Agent, error) {
+func (m *Manager) Create(proj *project.Project, spawnedBy string, priority bool) (*Agent, error) {
 	agentID := id.Generate()
 
+	// Bring the main clone up to date first if configured. A failed pull
+	// isn't fatal: the worktree is still reset to origin's default branch.
+	if proj.PullBeforeSpawn {
+		if result, err := proj.Pull(); err != nil {
+			slog.Warn("pull before spawn failed", "project", proj.Name, "error", err)
+		} else if result.LocalChanges {
+			slog.Warn("pull before spawn skipped: main clone has local changes", "project", proj.Name)
+		}
+	}
+
 	// Create a dedicated worktree for this agent
-	wt, err := proj.CreateWorktreeForAgent(agentID)
+	wt, err := proj.CreateWorktreeForAgent(agentID, priority)
 	if err != nil {
 		if errors.Is(err, project.ErrNoWorktreeAvailable) {
 			slog.Warn("max agents reached for project", "project", proj.Name)
@@ -267,6 +395,23 @@ func (m *Manager) Create(proj *project.Project) (*Agent, error) {
 	}
 
 	agent := NewWithBackend(agentID, proj, wt, b)
+	agent.SpawnedBy = spawnedBy
+	agent.SetModel(proj.Model)
+	if proj.GetAgentNaming() == NamingAnimal {
+		agent.SetName(m.uniqueName())
+	}
+
+	m.mu.RLock()
+	spill := m.spillHistory
+	agent.redactor = m.redactor
+	m.mu.RUnlock()
+	if spill {
+		if path, err := paths.ChatHistoryPath(agentID); err == nil {
+			agent.History().SetSpillPath(path)
+		} else {
+			slog.Warn("failed to get chat history path", "agent", agentID, "error", err)
+		}
+	}
 
 	// Register state change callback to emit events and update runtime store
 	agent.OnStateChange(func(old, new State) {
@@ -444,8 +589,13 @@ func (m *Manager) Delete(id string) error {
 		_ = proj.DeleteWorktreeForAgent(id)
 	}
 
-	// Remove from runtime store
+	// Remove from runtime store, keeping the transcript for replay
 	m.removeAgentRuntime(id)
+	m.saveAgentTranscript(agent)
+	agent.History().RemoveSpill()
+	if err := agent.SetDebugCapture(false); err != nil {
+		slog.Warn("failed to close debug capture", "agent", id, "error", err)
+	}
 
 	slog.Info("agent deleted", "agent", id, "project", projectName)
 
@@ -547,10 +697,41 @@ func (m *Manager) IdleAgents() []*Agent {
 	return idle
 }
 
+// maxNameAttempts bounds retries when a generated name is already taken.
+const maxNameAttempts = 5
+
+// uniqueName generates an adjective-animal name not used by any current
+// agent. After maxNameAttempts collisions, it settles for a duplicate;
+// names are only for display, so that's harmless.
+func (m *Manager) uniqueName() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name := GenerateName()
+	for range maxNameAttempts {
+		taken := false
+		for _, a := range m.agents {
+			if a.GetName() == name {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			break
+		}
+		name = GenerateName()
+	}
+	return name
+}
+
 // HydrateInfo contains the information needed to hydrate an agent from external data.
 // This is used to reconstruct agents from agent host processes after daemon restart.
 type HydrateInfo struct {
 	ID          string    // Agent ID
+	Name        string    // Human-friendly name
+	SpawnedBy   string    // Who spawned the agent
+	Role        string    // Role restricting the agent's tools
+	Subdir      string    // Worktree subdirectory the CLI runs in
 	Project     string    // Project name
 	State       State     // Current state (starting, running, idle, done, error)
 	Worktree    string    // Worktree path
@@ -605,12 +786,17 @@ func (m *Manager) Hydrate(info HydrateInfo) (*Agent, error) {
 		Project:     proj,
 		Worktree:    wt,
 		Backend:     b,
+		SpawnedBy:   info.SpawnedBy,
+		Name:        info.Name,
 		State:       info.State,
 		Task:        info.Task,
 		Description: info.Description,
+		Role:        info.Role,
+		Subdir:      info.Subdir,
 		StartedAt:   info.StartedAt,
 		UpdatedAt:   time.Now(),
 		history:     NewChatHistory(DefaultChatHistorySize),
+		redactor:    m.redactor,
 	}
 
 	// Register state change callback to emit events
@@ -1,12 +1,15 @@
 package agent
 
 import (
+	"errors"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"testing"
 
 	_ "github.com/tessro/fab/internal/backend" // Register backends for tests
 	"github.com/tessro/fab/internal/project"
+	"github.com/tessro/fab/internal/runtime"
 )
 
 func newTestProject(name string, maxAgents int) *project.Project {
@@ -20,7 +23,7 @@ func TestManager_Create(t *testing.T) {
 	proj := newTestProject("test-proj", 3)
 
 	t.Run("creates agent with unique ID", func(t *testing.T) {
-		agent, err := m.Create(proj)
+		agent, err := m.Create(proj, SpawnedByUser, false)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -36,14 +39,17 @@ func TestManager_Create(t *testing.T) {
 		if agent.GetState() != StateStarting {
 			t.Errorf("expected Starting state, got %s", agent.GetState())
 		}
+		if got := agent.Info().SpawnedBy; got != SpawnedByUser {
+			t.Errorf("expected SpawnedBy %q, got %q", SpawnedByUser, got)
+		}
 	})
 
 	t.Run("assigns different worktrees", func(t *testing.T) {
 		m := NewManager()
 		proj := newTestProject("test-proj", 3)
 
-		a1, _ := m.Create(proj)
-		a2, _ := m.Create(proj)
+		a1, _ := m.Create(proj, SpawnedByUser, false)
+		a2, _ := m.Create(proj, SpawnedByUser, false)
 
 		if a1.Worktree.Path == a2.Worktree.Path {
 			t.Error("expected different worktrees")
@@ -54,12 +60,12 @@ func TestManager_Create(t *testing.T) {
 		m := NewManager()
 		proj := newTestProject("small-proj", 1)
 
-		_, err := m.Create(proj)
+		_, err := m.Create(proj, SpawnedByUser, false)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		_, err = m.Create(proj)
+		_, err = m.Create(proj, SpawnedByUser, false)
 		if err != ErrNoCapacity {
 			t.Errorf("expected ErrNoCapacity, got %v", err)
 		}
@@ -70,7 +76,7 @@ func TestManager_Get(t *testing.T) {
 	m := NewManager()
 	proj := newTestProject("test-proj", 3)
 
-	agent, _ := m.Create(proj)
+	agent, _ := m.Create(proj, SpawnedByUser, false)
 
 	t.Run("returns existing agent", func(t *testing.T) {
 		found, err := m.Get(agent.ID)
@@ -95,9 +101,9 @@ func TestManager_List(t *testing.T) {
 	proj1 := newTestProject("proj1", 3)
 	proj2 := newTestProject("proj2", 3)
 
-	a1, _ := m.Create(proj1)
-	a2, _ := m.Create(proj1)
-	a3, _ := m.Create(proj2)
+	a1, _ := m.Create(proj1, SpawnedByUser, false)
+	a2, _ := m.Create(proj1, SpawnedByUser, false)
+	a3, _ := m.Create(proj2, SpawnedByUser, false)
 
 	t.Run("lists all agents", func(t *testing.T) {
 		all := m.List("")
@@ -135,7 +141,7 @@ func TestManager_ListInfo(t *testing.T) {
 	m := NewManager()
 	proj := newTestProject("test-proj", 3)
 
-	agent, _ := m.Create(proj)
+	agent, _ := m.Create(proj, SpawnedByUser, false)
 	agent.SetTask("FAB-42")
 
 	infos := m.ListInfo("")
@@ -163,9 +169,9 @@ func TestManager_Count(t *testing.T) {
 		t.Error("expected 0 agents initially")
 	}
 
-	_, _ = m.Create(proj)
-	_, _ = m.Create(proj)
-	_, _ = m.Create(proj)
+	_, _ = m.Create(proj, SpawnedByUser, false)
+	_, _ = m.Create(proj, SpawnedByUser, false)
+	_, _ = m.Create(proj, SpawnedByUser, false)
 
 	if m.Count() != 3 {
 		t.Errorf("expected 3 agents, got %d", m.Count())
@@ -184,9 +190,9 @@ func TestManager_CountByState(t *testing.T) {
 	m := NewManager()
 	proj := newTestProject("test-proj", 5)
 
-	a1, _ := m.Create(proj)
-	a2, _ := m.Create(proj)
-	a3, _ := m.Create(proj)
+	a1, _ := m.Create(proj, SpawnedByUser, false)
+	a2, _ := m.Create(proj, SpawnedByUser, false)
+	a3, _ := m.Create(proj, SpawnedByUser, false)
 
 	_ = a1.MarkRunning()
 	_ = a2.MarkRunning()
@@ -211,7 +217,7 @@ func TestManager_Delete(t *testing.T) {
 	m := NewManager()
 	proj := newTestProject("test-proj", 3)
 
-	agent, _ := m.Create(proj)
+	agent, _ := m.Create(proj, SpawnedByUser, false)
 	id := agent.ID
 
 	t.Run("deletes existing agent", func(t *testing.T) {
@@ -242,12 +248,72 @@ func TestManager_Delete(t *testing.T) {
 	})
 }
 
+func TestManager_DeleteSavesTranscript(t *testing.T) {
+	m := NewManager()
+	m.SetTranscriptStore(runtime.NewTranscriptStore(t.TempDir()))
+	proj := newTestProject("test-proj", 3)
+
+	a, _ := m.Create(proj, SpawnedByUser, false)
+	a.AddChatEntry(ChatEntry{Role: "user", Content: "first turn"})
+	a.AddChatEntry(ChatEntry{Role: "assistant", Content: "ok"})
+
+	if err := m.Delete(a.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	tr, err := m.LoadTranscript(a.ID)
+	if err != nil {
+		t.Fatalf("LoadTranscript failed: %v", err)
+	}
+	if tr.Project != "test-proj" {
+		t.Errorf("Project = %q, want test-proj", tr.Project)
+	}
+	if len(tr.Entries) != 2 || tr.Entries[0].Content != "first turn" {
+		t.Errorf("got entries %+v", tr.Entries)
+	}
+
+	if _, err := m.LoadTranscript("nonexistent"); !errors.Is(err, runtime.ErrTranscriptNotFound) {
+		t.Errorf("expected ErrTranscriptNotFound, got %v", err)
+	}
+}
+
+func TestManager_SaveTranscriptAfterCompact(t *testing.T) {
+	m := NewManager()
+	m.SetTranscriptStore(runtime.NewTranscriptStore(t.TempDir()))
+	proj := newTestProject("test-proj", 3)
+
+	a, _ := m.Create(proj, SpawnedByUser, false)
+	a.AddChatEntry(ChatEntry{Role: "user", Content: "first turn"})
+	a.AddChatEntry(ChatEntry{Role: "assistant", Content: "ok"})
+	if err := m.SaveTranscript(a); err != nil {
+		t.Fatalf("SaveTranscript failed: %v", err)
+	}
+
+	a.History().Compact(ChatEntry{Content: "summary"})
+	a.AddChatEntry(ChatEntry{Role: "user", Content: "second turn"})
+	if err := m.Delete(a.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	tr, err := m.LoadTranscript(a.ID)
+	if err != nil {
+		t.Fatalf("LoadTranscript failed: %v", err)
+	}
+	var got []string
+	for _, e := range tr.Entries {
+		got = append(got, e.Content)
+	}
+	if want := []string{"first turn", "ok", "second turn"}; !slices.Equal(got, want) {
+		t.Errorf("transcript = %q, want %q without the summary", got, want)
+	}
+}
+
 func TestManager_StopAll(t *testing.T) {
 	m := NewManager()
 	proj := newTestProject("test-proj", 3)
 
-	a1, _ := m.Create(proj)
-	a2, _ := m.Create(proj)
+	a1, _ := m.Create(proj, SpawnedByUser, false)
+	a2, _ := m.Create(proj, SpawnedByUser, false)
 
 	_ = a1.MarkRunning()
 	_ = a2.MarkRunning()
@@ -267,8 +333,8 @@ func TestManager_DeleteAll(t *testing.T) {
 	m := NewManager()
 	proj := newTestProject("test-proj", 3)
 
-	_, _ = m.Create(proj)
-	_, _ = m.Create(proj)
+	_, _ = m.Create(proj, SpawnedByUser, false)
+	_, _ = m.Create(proj, SpawnedByUser, false)
 
 	m.DeleteAll("test-proj")
 
@@ -281,10 +347,10 @@ func TestManager_ActiveAgents(t *testing.T) {
 	m := NewManager()
 	proj := newTestProject("test-proj", 5)
 
-	a1, _ := m.Create(proj) // Starting - active
-	a2, _ := m.Create(proj) // Running - active
-	a3, _ := m.Create(proj) // Idle - active
-	a4, _ := m.Create(proj) // Done - not active
+	a1, _ := m.Create(proj, SpawnedByUser, false) // Starting - active
+	a2, _ := m.Create(proj, SpawnedByUser, false) // Running - active
+	a3, _ := m.Create(proj, SpawnedByUser, false) // Idle - active
+	a4, _ := m.Create(proj, SpawnedByUser, false) // Done - not active
 
 	_ = a2.MarkRunning()
 	_ = a3.MarkRunning()
@@ -304,8 +370,8 @@ func TestManager_RunningAgents(t *testing.T) {
 	m := NewManager()
 	proj := newTestProject("test-proj", 3)
 
-	a1, _ := m.Create(proj)
-	a2, _ := m.Create(proj)
+	a1, _ := m.Create(proj, SpawnedByUser, false)
+	a2, _ := m.Create(proj, SpawnedByUser, false)
 
 	_ = a1.MarkRunning()
 	_ = a2.MarkRunning()
@@ -321,8 +387,8 @@ func TestManager_IdleAgents(t *testing.T) {
 	m := NewManager()
 	proj := newTestProject("test-proj", 3)
 
-	a1, _ := m.Create(proj)
-	a2, _ := m.Create(proj)
+	a1, _ := m.Create(proj, SpawnedByUser, false)
+	a2, _ := m.Create(proj, SpawnedByUser, false)
 
 	_ = a1.MarkRunning()
 	_ = a1.MarkIdle()
@@ -349,7 +415,7 @@ func TestManager_Events(t *testing.T) {
 
 	t.Run("emits created event", func(t *testing.T) {
 		events = nil
-		agent, _ := m.Create(proj)
+		agent, _ := m.Create(proj, SpawnedByUser, false)
 
 		mu.Lock()
 		defer mu.Unlock()
@@ -367,7 +433,7 @@ func TestManager_Events(t *testing.T) {
 
 	t.Run("emits state change event", func(t *testing.T) {
 		events = nil
-		agent, _ := m.Create(proj)
+		agent, _ := m.Create(proj, SpawnedByUser, false)
 
 		_ = agent.MarkRunning()
 
@@ -391,7 +457,7 @@ func TestManager_Events(t *testing.T) {
 
 	t.Run("emits deleted event", func(t *testing.T) {
 		events = nil
-		agent, _ := m.Create(proj)
+		agent, _ := m.Create(proj, SpawnedByUser, false)
 		id := agent.ID
 
 		_ = m.Delete(id)
@@ -421,7 +487,7 @@ func TestManager_Concurrent(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			_, err := m.Create(proj)
+			_, err := m.Create(proj, SpawnedByUser, false)
 			if err == nil {
 				created.Add(1)
 			}
@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"math/rand/v2"
+	"strings"
+	"unicode"
+)
+
+// Agent naming schemes, configured per project with agent-naming.
+const (
+	// NamingID leaves agents unnamed; they are shown by ID only.
+	NamingID = "id"
+	// NamingAnimal names agents with a random adjective-animal slug at creation.
+	NamingAnimal = "animal"
+	// NamingIssue names agents after the title of the issue they claim.
+	NamingIssue = "issue"
+)
+
+var nameAdjectives = []string{
+	"amber", "bold", "brave", "brisk", "calm", "clever", "cosmic", "crisp",
+	"daring", "eager", "fuzzy", "gentle", "giddy", "golden", "happy", "humble",
+	"jolly", "keen", "lively", "lucky", "mellow", "merry", "nimble", "noble",
+	"plucky", "quick", "quiet", "rapid", "rusty", "shiny", "silver", "snappy",
+	"spry", "steady", "sunny", "swift", "tidy", "witty", "zany", "zesty",
+}
+
+var nameAnimals = []string{
+	"badger", "beaver", "bison", "crane", "dingo", "dolphin", "falcon", "ferret",
+	"finch", "gecko", "heron", "ibis", "jackal", "koala", "lemur", "lynx",
+	"marmot", "marten", "moose", "newt", "ocelot", "otter", "panda", "puffin",
+	"quail", "raven", "salmon", "seal", "shrew", "sloth", "stoat", "tapir",
+	"toucan", "turtle", "walrus", "weasel", "wombat", "yak", "zebra", "wren",
+}
+
+// maxNameWords and maxNameLength bound names derived from issue titles.
+const (
+	maxNameWords  = 4
+	maxNameLength = 32
+)
+
+// GenerateName returns a random adjective-animal name (e.g., "brave-otter").
+func GenerateName() string {
+	return nameAdjectives[rand.IntN(len(nameAdjectives))] + "-" + nameAnimals[rand.IntN(len(nameAnimals))]
+}
+
+// NameFromTitle derives a short slug from an issue title
+// (e.g., "Fix login redirect loop on Safari" → "fix-login-redirect-loop").
+// Returns an empty string if the title has no letters or digits.
+func NameFromTitle(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var name string
+	for i, w := range words {
+		if i == maxNameWords {
+			break
+		}
+		candidate := w
+		if name != "" {
+			candidate = name + "-" + w
+		}
+		if len(candidate) > maxNameLength {
+			if name == "" {
+				// A single overlong word is cut rather than dropped
+				name = string([]rune(w)[:min(len([]rune(w)), maxNameLength)])
+			}
+			break
+		}
+		name = candidate
+	}
+	return name
+}
@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateName(t *testing.T) {
+	for range 20 {
+		name := GenerateName()
+		adjective, animal, ok := strings.Cut(name, "-")
+		if !ok || adjective == "" || animal == "" {
+			t.Fatalf("GenerateName() = %q, want adjective-animal", name)
+		}
+	}
+}
+
+func TestNameFromTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Fix login redirect loop on Safari", "fix-login-redirect-loop"},
+		{"Add `fab agent rename`", "add-fab-agent-rename"},
+		{"  Crash: nil map (v2.1)  ", "crash-nil-map-v2"},
+		{"Überprüfe Änderungen", "überprüfe-änderungen"},
+		{"internationalization localization configuration", "internationalization"},
+		{strings.Repeat("x", 40), strings.Repeat("x", 32)},
+		{"!!! ???", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NameFromTitle(tt.title); got != tt.want {
+			t.Errorf("NameFromTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestAgent_SetName(t *testing.T) {
+	a := New("test-1", nil, nil)
+
+	var calls int
+	a.OnInfoChange(func() { calls++ })
+
+	a.SetName("brave-otter")
+	if got := a.Info().Name; got != "brave-otter" {
+		t.Errorf("Info().Name = %q, want %q", got, "brave-otter")
+	}
+	a.SetName("brave-otter")
+	if calls != 1 {
+		t.Errorf("info change callbacks = %d, want 1 (unchanged name should not notify)", calls)
+	}
+}
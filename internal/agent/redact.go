@@ -0,0 +1,217 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RedactedText replaces secrets scrubbed from agent output.
+const RedactedText = "[REDACTED]"
+
+// streamCarryLength is how much of the end of a stream a StreamRedactor
+// holds back, so a secret whose start has arrived but which doesn't match
+// yet (e.g., the first characters of a token) isn't emitted early.
+const streamCarryLength = 256
+
+// minRedactedValueLength is the shortest literal value a Redactor scrubs.
+// Shorter values (e.g., "1" or "true" from a flag variable) would mangle
+// unrelated output.
+const minRedactedValueLength = 8
+
+// Redactor scrubs secrets from agent output before it is recorded in chat
+// history or broadcast. It matches regular expressions and literal values.
+// A nil *Redactor leaves text unchanged.
+type Redactor struct {
+	patterns []*regexp.Regexp
+	literals []string // Values to scrub, longest first
+	values   *strings.Replacer
+}
+
+// NewRedactor compiles patterns and builds a Redactor that also scrubs each
+// literal value. If a pattern has a capture group, only the first group is
+// replaced, so context like "Bearer " or "password=" stays readable.
+// Invalid patterns are skipped and reported in the returned error; the
+// Redactor is still usable with the rest.
+func NewRedactor(patterns, values []string) (*Redactor, error) {
+	r := &Redactor{}
+	var errs []error
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("redaction pattern %q: %w", p, err))
+			continue
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	// Longest first, so a value that contains another is replaced whole
+	var literals []string
+	for _, v := range values {
+		if len(v) >= minRedactedValueLength && !slices.Contains(literals, v) {
+			literals = append(literals, v)
+		}
+	}
+	slices.SortFunc(literals, func(a, b string) int { return len(b) - len(a) })
+	r.literals = literals
+	if len(literals) > 0 {
+		pairs := make([]string, 0, 2*len(literals))
+		for _, v := range literals {
+			pairs = append(pairs, v, RedactedText)
+		}
+		r.values = strings.NewReplacer(pairs...)
+	}
+
+	return r, errors.Join(errs...)
+}
+
+// Redact returns s with every match scrubbed.
+func (r *Redactor) Redact(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	if r.values != nil {
+		s = r.values.Replace(s)
+	}
+	for _, re := range r.patterns {
+		s = redactPattern(re, s)
+	}
+	return s
+}
+
+// active reports whether r scrubs anything at all.
+func (r *Redactor) active() bool {
+	return r != nil && (len(r.patterns) > 0 || len(r.literals) > 0)
+}
+
+// StreamRedactor scrubs text that arrives in chunks, such as a response's
+// text deltas, where a secret may be split across chunks. It holds back the
+// end of the stream until no secret can straddle what it emits.
+type StreamRedactor struct {
+	r       *Redactor
+	pending string
+}
+
+// NewStream returns a StreamRedactor scrubbing with r. A nil r streams text
+// through unchanged.
+func (r *Redactor) NewStream() *StreamRedactor {
+	return &StreamRedactor{r: r}
+}
+
+// Write adds chunk to the stream and returns the scrubbed text that is safe
+// to emit so far, which may be empty. Text is held back while it is within
+// streamCarryLength of the end, is part of a match that reaches the end and
+// may still grow, or follows a pattern's literal prefix (e.g., a PEM
+// header) that hasn't completed a match yet.
+func (s *StreamRedactor) Write(chunk string) string {
+	if !s.r.active() {
+		return chunk
+	}
+	s.pending += chunk
+	cut := s.r.safeLength(s.pending)
+	out := s.r.Redact(s.pending[:cut])
+	s.pending = s.pending[cut:]
+	return out
+}
+
+// Reset drops held-back text. Call it when the complete text arrives by
+// other means, e.g., the message the deltas belong to.
+func (s *StreamRedactor) Reset() {
+	s.pending = ""
+}
+
+// safeLength returns how much of the start of s can be scrubbed and emitted
+// without splitting a secret that more text could complete.
+func (r *Redactor) safeLength(s string) int {
+	cut := max(len(s)-max(streamCarryLength, r.longestLiteral()), 0)
+
+	// Spans that must not be split: matches, and unmatched literal prefixes
+	// running to the end of s
+	type span struct{ start, end int }
+	var spans []span
+	for _, v := range r.literals {
+		for off := 0; ; {
+			i := strings.Index(s[off:], v)
+			if i < 0 {
+				break
+			}
+			spans = append(spans, span{off + i, off + i + len(v)})
+			off += i + len(v)
+		}
+	}
+	for _, re := range r.patterns {
+		matched := make(map[int]bool)
+		for _, m := range re.FindAllStringIndex(s, -1) {
+			spans = append(spans, span{m[0], m[1]})
+			matched[m[0]] = true
+		}
+		if prefix, _ := re.LiteralPrefix(); prefix != "" {
+			for off := 0; ; {
+				i := strings.Index(s[off:], prefix)
+				if i < 0 {
+					break
+				}
+				if !matched[off+i] {
+					spans = append(spans, span{off + i, len(s)})
+				}
+				off += i + len(prefix)
+			}
+		}
+	}
+
+	// Moving the cut back can make it split another span, so repeat
+	for moved := true; moved; {
+		moved = false
+		for _, sp := range spans {
+			if sp.start < cut && (sp.end > cut || sp.end == len(s)) {
+				cut = sp.start
+				moved = true
+			}
+		}
+	}
+	return cut
+}
+
+// longestLiteral returns the length of the longest literal value.
+func (r *Redactor) longestLiteral() int {
+	if len(r.literals) == 0 {
+		return 0
+	}
+	return len(r.literals[0])
+}
+
+// RedactEntry scrubs the free-text fields of a chat entry.
+func (r *Redactor) RedactEntry(entry ChatEntry) ChatEntry {
+	if r == nil {
+		return entry
+	}
+	entry.Content = r.Redact(entry.Content)
+	entry.ToolInput = r.Redact(entry.ToolInput)
+	entry.ToolResult = r.Redact(entry.ToolResult)
+	return entry
+}
+
+// redactPattern replaces matches of re in s, limited to the first capture
+// group when re has one and it matched.
+func redactPattern(re *regexp.Regexp, s string) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(RedactedText)
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
@@ -11,6 +11,8 @@ import (
 // This allows existing code to continue using agent.StreamMessage, agent.ChatEntry, etc.
 type (
 	StreamMessage = backend.StreamMessage
+	PartialEvent  = backend.PartialEvent
+	EventDelta    = backend.EventDelta
 	NestedMessage = backend.NestedMessage
 	ContentBlock  = backend.ContentBlock
 	FlexContent   = backend.FlexContent
@@ -26,6 +28,26 @@ func FormatToolInput(name string, input json.RawMessage) string {
 	return backend.FormatToolInput(name, input)
 }
 
+// toolFilePath returns the file a file-oriented tool call (Read, Write,
+// Edit, MultiEdit, NotebookEdit) operates on, or "" for other tools.
+func toolFilePath(name string, input json.RawMessage) string {
+	var data struct {
+		FilePath     string `json:"file_path"`
+		NotebookPath string `json:"notebook_path"`
+	}
+	switch name {
+	case "Read", "Write", "Edit", "MultiEdit":
+		if json.Unmarshal(input, &data) == nil {
+			return data.FilePath
+		}
+	case "NotebookEdit":
+		if json.Unmarshal(input, &data) == nil {
+			return data.NotebookPath
+		}
+	}
+	return ""
+}
+
 // ParseStreamMessage parses a single JSONL line from Claude Code's stream-json output.
 // Delegates to the ClaudeBackend for parsing.
 func ParseStreamMessage(line []byte) (*StreamMessage, error) {
@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SetSubdir scopes the agent to a subdirectory of its worktree, such as one
+// package of a monorepo: the CLI is started there instead of the worktree
+// root. subdir must be a relative path to a directory inside the worktree;
+// empty or "." clears it. It takes effect the next time the process is
+// started, so set it before Start.
+func (a *Agent) SetSubdir(subdir string) error {
+	a.mu.Lock()
+	if a.Worktree == nil {
+		a.mu.Unlock()
+		return errors.New("agent has no worktree")
+	}
+	clean, err := ValidateSubdir(a.Worktree.Path, subdir)
+	if err != nil || a.Subdir == clean {
+		a.mu.Unlock()
+		return err
+	}
+	a.Subdir = clean
+	callback := a.onInfoChange
+	a.mu.Unlock()
+
+	// Call callback OUTSIDE the lock to prevent deadlock
+	if callback != nil {
+		callback()
+	}
+	return nil
+}
+
+// ValidateSubdir checks that subdir names a directory inside root, following
+// symlinks, and returns it cleaned. Empty and "." return "".
+func ValidateSubdir(root, subdir string) (string, error) {
+	if subdir == "" {
+		return "", nil
+	}
+	if filepath.IsAbs(subdir) {
+		return "", fmt.Errorf("subdir %q must be relative to the worktree", subdir)
+	}
+	clean := filepath.Clean(subdir)
+	if clean == "." {
+		return "", nil
+	}
+	if !filepath.IsLocal(clean) {
+		return "", fmt.Errorf("subdir %q is outside the worktree", subdir)
+	}
+
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("resolve worktree: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Join(root, clean))
+	if err != nil {
+		return "", fmt.Errorf("subdir %q does not exist in the worktree", subdir)
+	}
+	if rel, err := filepath.Rel(resolvedRoot, resolved); err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("subdir %q is outside the worktree", subdir)
+	}
+	if info, err := os.Stat(resolved); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("subdir %q is not a directory", subdir)
+	}
+	return clean, nil
+}
@@ -108,6 +108,10 @@ func (m *Manager) AgentInfo() AgentInfo {
 	info := ag.Info()
 	return AgentInfo{
 		ID:          info.ID,
+		Name:        info.Name,
+		SpawnedBy:   info.SpawnedBy,
+		Role:        info.Role,
+		Subdir:      info.Subdir,
 		Project:     info.Project,
 		State:       string(info.State),
 		PID:         ag.PID(),
@@ -168,6 +172,10 @@ func (m *Manager) StartReadLoop() error {
 		OnOutput: func(data []byte) {
 			m.onOutput(data)
 		},
+		OnParseError: func(line []byte, err error) {
+			m.onParseError(err)
+		},
+		StuckTimeout: agent.DefaultStuckTimeout,
 	}
 
 	return ag.StartReadLoop(cfg)
@@ -210,6 +218,14 @@ func (m *Manager) onOutput(data []byte) {
 	m.bufferAndBroadcast(event)
 }
 
+// onParseError is called when a line of output isn't valid stream-json.
+// The raw line has already been broadcast as an output event.
+func (m *Manager) onParseError(err error) {
+	event := m.createEvent("parse_error", nil)
+	event.Error = err.Error()
+	m.bufferAndBroadcast(event)
+}
+
 // createEvent creates a new stream event with the next offset.
 func (m *Manager) createEvent(eventType string, data []byte) *StreamEvent {
 	m.mu.Lock()
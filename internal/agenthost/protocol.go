@@ -96,6 +96,10 @@ type HostInfo struct {
 // AgentInfo contains agent subprocess status.
 type AgentInfo struct {
 	ID          string    `json:"id"`                    // Agent ID
+	Name        string    `json:"name,omitempty"`        // Human-friendly name
+	SpawnedBy   string    `json:"spawned_by,omitempty"`  // Who spawned the agent
+	Role        string    `json:"role,omitempty"`        // Role restricting the agent's tools
+	Subdir      string    `json:"subdir,omitempty"`      // Worktree subdirectory the CLI runs in
 	Project     string    `json:"project"`               // Project name
 	State       string    `json:"state"`                 // starting, running, idle, done, error
 	PID         int       `json:"pid"`                   // Agent subprocess PID (0 if not running)
@@ -149,7 +153,7 @@ type HistoryResponse struct {
 // StreamEvent is sent to attached clients when agent output occurs.
 // This mirrors the daemon StreamEvent but is specific to a single agent.
 type StreamEvent struct {
-	Type      string `json:"type"`                 // output, state, chat_entry, error
+	Type      string `json:"type"`                 // output, state, chat_entry, parse_error, error
 	AgentID   string `json:"agent_id"`             // Agent that produced the event
 	Offset    int64  `json:"offset"`               // Stream position of this event
 	Timestamp string `json:"timestamp"`            // RFC3339 timestamp
@@ -61,4 +61,13 @@ type CommandConfig struct {
 	// ThreadID is the session thread ID for resuming conversations (Codex-specific).
 	// When set, Codex uses "exec resume <thread-id>" instead of "exec".
 	ThreadID string
+
+	// Model is the model the CLI should use (e.g., "opus", "o3").
+	// Empty leaves the choice to the CLI's own default.
+	Model string
+
+	// ReadOnly means the agent must not write (the reviewer role). Backends
+	// whose CLI never calls the permission hook enforce it with their own
+	// sandbox.
+	ReadOnly bool
 }
@@ -0,0 +1,57 @@
+package backend
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"sync"
+)
+
+// BinaryConfig overrides how a backend's CLI is launched.
+type BinaryConfig struct {
+	// Path is the CLI binary to run. Empty uses the backend name looked up on PATH.
+	Path string
+
+	// ExtraArgs are passed to the CLI in addition to the backend's own arguments.
+	ExtraArgs []string
+}
+
+var (
+	binaryMu      sync.RWMutex
+	binaryConfigs = make(map[string]BinaryConfig)
+)
+
+// SetBinaryConfig sets the CLI path and extra arguments for the named backend.
+// It applies to every command the backend builds afterwards.
+func SetBinaryConfig(name string, cfg BinaryConfig) {
+	binaryMu.Lock()
+	defer binaryMu.Unlock()
+	binaryConfigs[name] = cfg
+}
+
+// binary returns the CLI path and extra arguments for the named backend.
+func binary(name string) (string, []string) {
+	binaryMu.RLock()
+	defer binaryMu.RUnlock()
+	cfg := binaryConfigs[name]
+	path := cfg.Path
+	if path == "" {
+		path = name
+	}
+	return path, slices.Clone(cfg.ExtraArgs)
+}
+
+// Command builds the CLI command for b and verifies its binary exists and is
+// executable, so a missing or misconfigured CLI fails with a clear error
+// before the process is spawned.
+func Command(b Backend, cfg CommandConfig) (*exec.Cmd, error) {
+	cmd, err := b.BuildCommand(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath(cmd.Path); err != nil {
+		return nil, fmt.Errorf("%s CLI not found at %q (install it or set backends.%s.path in the fab config): %w",
+			b.Name(), cmd.Path, b.Name(), err)
+	}
+	return cmd, nil
+}
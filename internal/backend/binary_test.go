@@ -0,0 +1,58 @@
+package backend
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBinaryConfig_ClaudeCommand(t *testing.T) {
+	SetBinaryConfig("claude", BinaryConfig{
+		Path:      "/opt/claude/bin/claude",
+		ExtraArgs: []string{"--model", "opus"},
+	})
+	t.Cleanup(func() { SetBinaryConfig("claude", BinaryConfig{}) })
+
+	cmd, err := (&ClaudeBackend{}).BuildCommand(CommandConfig{WorkDir: "/tmp/test"})
+	if err != nil {
+		t.Fatalf("BuildCommand() error = %v", err)
+	}
+	if cmd.Path != "/opt/claude/bin/claude" {
+		t.Errorf("Path = %q, want %q", cmd.Path, "/opt/claude/bin/claude")
+	}
+	if got := cmd.Args[len(cmd.Args)-2:]; !slices.Equal(got, []string{"--model", "opus"}) {
+		t.Errorf("trailing args = %v, want [--model opus]", got)
+	}
+}
+
+func TestBinaryConfig_CodexCommandKeepsPromptLast(t *testing.T) {
+	SetBinaryConfig("codex", BinaryConfig{ExtraArgs: []string{"--model", "o3"}})
+	t.Cleanup(func() { SetBinaryConfig("codex", BinaryConfig{}) })
+
+	cmd, err := (&CodexBackend{}).BuildCommand(CommandConfig{
+		WorkDir:       "/tmp/test",
+		InitialPrompt: "hello",
+	})
+	if err != nil {
+		t.Fatalf("BuildCommand() error = %v", err)
+	}
+	if cmd.Args[len(cmd.Args)-1] != "hello" {
+		t.Errorf("last arg = %q, want prompt", cmd.Args[len(cmd.Args)-1])
+	}
+	if !slices.Contains(cmd.Args, "o3") {
+		t.Errorf("Args = %v, want extra args included", cmd.Args)
+	}
+}
+
+func TestCommand_MissingBinary(t *testing.T) {
+	SetBinaryConfig("claude", BinaryConfig{Path: "/nonexistent/claude"})
+	t.Cleanup(func() { SetBinaryConfig("claude", BinaryConfig{}) })
+
+	_, err := Command(&ClaudeBackend{}, CommandConfig{WorkDir: "/tmp/test"})
+	if err == nil {
+		t.Fatal("Command() should fail for a missing binary")
+	}
+	if !strings.Contains(err.Error(), "backends.claude.path") {
+		t.Errorf("error = %q, want mention of backends.claude.path", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sync"
+	"time"
 
 	"github.com/tessro/fab/internal/plugin"
 )
@@ -48,13 +50,21 @@ func (b *ClaudeBackend) BuildCommand(cfg CommandConfig) (*exec.Cmd, error) {
 
 	// Build claude command with stream-json mode
 	// --verbose is required when using --output-format stream-json
-	cmd := exec.Command("claude",
+	// --include-partial-messages streams text deltas ahead of each complete message
+	path, extraArgs := binary(b.Name())
+	args := []string{
 		"--output-format", "stream-json",
 		"--input-format", "stream-json",
 		"--verbose",
+		"--include-partial-messages",
 		"--permission-mode", "default",
 		"--plugin-dir", pluginDir,
-		"--settings", string(settingsJSON))
+		"--settings", string(settingsJSON),
+	}
+	if cfg.Model != "" {
+		args = append(args, "--model", cfg.Model)
+	}
+	cmd := exec.Command(path, append(args, extraArgs...)...)
 
 	if cfg.WorkDir != "" {
 		cmd.Dir = cfg.WorkDir
@@ -62,6 +72,7 @@ func (b *ClaudeBackend) BuildCommand(cfg CommandConfig) (*exec.Cmd, error) {
 
 	// Set environment variable for agent identification
 	cmd.Env = append(os.Environ(), "FAB_AGENT_ID="+cfg.AgentID)
+	cmd.Env = append(cmd.Env, cfg.Env...)
 
 	return cmd, nil
 }
@@ -104,11 +115,32 @@ func (b *ClaudeBackend) FormatInputMessage(content string, sessionID string) ([]
 	return append(data, '\n'), nil
 }
 
+var (
+	hookTimeoutMu sync.RWMutex
+	hookTimeout   = 5 * time.Minute
+)
+
+// SetPermissionHookTimeout sets how long Claude Code lets the permission
+// hooks run. It should be the longest permission timeout, or Claude Code
+// kills the hook before the daemon gives up waiting for an answer.
+func SetPermissionHookTimeout(d time.Duration) {
+	hookTimeoutMu.Lock()
+	defer hookTimeoutMu.Unlock()
+	hookTimeout = d
+}
+
+// permissionHookTimeout returns the timeout set by SetPermissionHookTimeout.
+func permissionHookTimeout() time.Duration {
+	hookTimeoutMu.RLock()
+	defer hookTimeoutMu.RUnlock()
+	return hookTimeout
+}
+
 // HookSettings returns Claude Code-specific hook configuration.
-// Hook timeout matches our permission timeout (5 minutes) since hooks may
-// block waiting for user input via the permission manager.
+// Hook timeout matches our longest permission timeout (5 minutes by default)
+// since hooks may block waiting for user input via the permission manager.
 func (b *ClaudeBackend) HookSettings(fabPath string) map[string]any {
-	hookTimeoutSec := 5 * 60 // 5 minutes in seconds
+	hookTimeoutSec := int(permissionHookTimeout().Seconds())
 
 	return map[string]any{
 		"hooks": map[string]any{
@@ -3,7 +3,9 @@ package backend
 import (
 	"encoding/json"
 	"os/exec"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestClaudeBackend_Name(t *testing.T) {
@@ -74,6 +76,9 @@ func TestClaudeBackend_BuildCommand(t *testing.T) {
 	checkArg("--input-format", "stream-json")
 	checkArg("--permission-mode", "default")
 	checkArg("--plugin-dir", cfg.PluginDir)
+	if !slices.Contains(args, "--include-partial-messages") {
+		t.Error("BuildCommand() missing --include-partial-messages")
+	}
 }
 
 func TestClaudeBackend_ParseStreamMessage(t *testing.T) {
@@ -221,6 +226,25 @@ func TestClaudeBackend_HookSettings(t *testing.T) {
 	}
 }
 
+func TestClaudeBackend_HookSettingsTimeout(t *testing.T) {
+	b := &ClaudeBackend{}
+	timeout := func() any {
+		hooks := b.HookSettings("fab")["hooks"].(map[string]any)
+		entry := hooks["PermissionRequest"].([]any)[0].(map[string]any)
+		return entry["hooks"].([]any)[0].(map[string]any)["timeout"]
+	}
+
+	if got := timeout(); got != 300 {
+		t.Errorf("default permission hook timeout = %v, want 300", got)
+	}
+
+	SetPermissionHookTimeout(15 * time.Minute)
+	defer SetPermissionHookTimeout(5 * time.Minute)
+	if got := timeout(); got != 900 {
+		t.Errorf("permission hook timeout = %v, want 900", got)
+	}
+}
+
 func TestClaudeBackend_ImplementsBackend(t *testing.T) {
 	// Compile-time check that ClaudeBackend implements Backend
 	var _ Backend = (*ClaudeBackend)(nil)
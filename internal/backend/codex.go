@@ -20,9 +20,29 @@ func (b *CodexBackend) BuildCommand(cfg CommandConfig) (*exec.Cmd, error) {
 
 	// If ThreadID is provided, use "exec resume" to continue the conversation
 	if cfg.ThreadID != "" {
-		args = []string{"exec", "resume", "--json", "--full-auto", "-c", `model_reasoning_effort="xhigh"`, cfg.ThreadID}
+		args = []string{"exec", "resume", "--json"}
 	} else {
-		args = []string{"exec", "--json", "--full-auto", "-c", `model_reasoning_effort="xhigh"`}
+		args = []string{"exec", "--json"}
+	}
+
+	// Codex never calls the permission hook, so read-only agents are held
+	// to a read-only sandbox instead of the writable one --full-auto grants
+	if cfg.ReadOnly {
+		args = append(args, "-c", `sandbox_mode="read-only"`)
+	} else {
+		args = append(args, "--full-auto")
+	}
+	args = append(args, "-c", `model_reasoning_effort="xhigh"`)
+
+	if cfg.Model != "" {
+		args = append(args, "--model", cfg.Model)
+	}
+
+	// Configured extra args go after the flags but before positional arguments
+	path, extraArgs := binary(b.Name())
+	args = append(args, extraArgs...)
+	if cfg.ThreadID != "" {
+		args = append(args, cfg.ThreadID)
 	}
 
 	// Add prompt if provided (required for resume, optional for new exec)
@@ -30,9 +50,10 @@ func (b *CodexBackend) BuildCommand(cfg CommandConfig) (*exec.Cmd, error) {
 		args = append(args, cfg.InitialPrompt)
 	}
 
-	cmd := exec.Command("codex", args...)
+	cmd := exec.Command(path, args...)
 	cmd.Dir = cfg.WorkDir
 	cmd.Env = append(os.Environ(), "FAB_AGENT_ID="+cfg.AgentID)
+	cmd.Env = append(cmd.Env, cfg.Env...)
 
 	return cmd, nil
 }
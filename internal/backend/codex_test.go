@@ -117,6 +117,54 @@ func TestCodexBackend_BuildCommand(t *testing.T) {
 		}
 	})
 
+	t.Run("with model", func(t *testing.T) {
+		cfg := backend.CommandConfig{
+			WorkDir:       "/tmp/test",
+			AgentID:       "test-agent",
+			Model:         "o3",
+			InitialPrompt: "write hello world",
+		}
+		cmd, err := b.BuildCommand(cfg)
+		if err != nil {
+			t.Fatalf("BuildCommand() error = %v", err)
+		}
+
+		args := strings.Join(cmd.Args, " ")
+		if !strings.Contains(args, "--model o3") {
+			t.Errorf("BuildCommand() args should contain '--model o3', got %v", cmd.Args)
+		}
+		if cmd.Args[len(cmd.Args)-1] != "write hello world" {
+			t.Errorf("BuildCommand() prompt should be last, got %v", cmd.Args)
+		}
+	})
+
+	t.Run("read-only uses the read-only sandbox", func(t *testing.T) {
+		for _, threadID := range []string{"", "thread-123"} {
+			cfg := backend.CommandConfig{
+				WorkDir:       "/tmp/test",
+				AgentID:       "test-agent",
+				InitialPrompt: "review this",
+				ThreadID:      threadID,
+				ReadOnly:      true,
+			}
+			cmd, err := b.BuildCommand(cfg)
+			if err != nil {
+				t.Fatalf("BuildCommand() error = %v", err)
+			}
+
+			args := strings.Join(cmd.Args, " ")
+			if strings.Contains(args, "--full-auto") {
+				t.Errorf("BuildCommand() read-only args should not contain '--full-auto', got %v", cmd.Args)
+			}
+			if !strings.Contains(args, `sandbox_mode="read-only"`) {
+				t.Errorf("BuildCommand() read-only args should set the read-only sandbox, got %v", cmd.Args)
+			}
+			if cmd.Args[len(cmd.Args)-1] != "review this" {
+				t.Errorf("BuildCommand() prompt should be last, got %v", cmd.Args)
+			}
+		}
+	})
+
 	t.Run("environment includes FAB_AGENT_ID", func(t *testing.T) {
 		cfg := backend.CommandConfig{
 			WorkDir: "/tmp/test",
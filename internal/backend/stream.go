@@ -17,6 +17,22 @@ type StreamMessage struct {
 	Result   string         `json:"result,omitempty"`   // For result type
 	IsError  bool           `json:"is_error,omitempty"`
 	ThreadID string         `json:"thread_id,omitempty"` // Session thread ID (Codex: from thread.started)
+	Event    *PartialEvent  `json:"event,omitempty"`     // For stream_event type (partial message streaming)
+}
+
+// PartialEvent is a raw API streaming event carried by a "stream_event"
+// message. Only text deltas are used; the complete assistant message still
+// follows once the content block finishes.
+type PartialEvent struct {
+	Type  string      `json:"type"`            // "content_block_delta", "message_start", etc.
+	Index int         `json:"index,omitempty"` // Content block index
+	Delta *EventDelta `json:"delta,omitempty"` // For content_block_delta events
+}
+
+// EventDelta is the incremental content of a content_block_delta event.
+type EventDelta struct {
+	Type string `json:"type"`           // "text_delta", "input_json_delta", "thinking_delta"
+	Text string `json:"text,omitempty"` // For text_delta
 }
 
 // NestedMessage contains the actual API message content.
@@ -91,6 +107,7 @@ type ChatEntry struct {
 	ToolResult string    // Tool output
 	IsError    bool      // True if tool result is an error
 	Timestamp  time.Time // When the entry was created
+	Seq        int64     // Position in the owning chat history (set by ChatHistory.Add)
 }
 
 // InputMessage is sent to agent CLIs via stdin.
@@ -180,18 +197,17 @@ func FormatToolInput(name string, input json.RawMessage) string {
 		return formatGlobInput(data)
 	case "Grep":
 		return formatGrepInput(data)
+	case "TodoWrite":
+		return formatTodoWriteInput(data)
 	default:
 		return formatGenericInput(data)
 	}
 }
 
-// formatBashInput formats Bash tool input.
+// formatBashInput formats Bash tool input as the full command. Single-line
+// displays truncate it themselves.
 func formatBashInput(data map[string]any) string {
 	if cmd, ok := data["command"].(string); ok {
-		// Truncate long commands
-		if len(cmd) > 100 {
-			return cmd[:97] + "..."
-		}
 		return cmd
 	}
 	return formatGenericInput(data)
@@ -213,13 +229,67 @@ func formatWriteInput(data map[string]any) string {
 	return formatGenericInput(data)
 }
 
-// formatEditInput formats Edit tool input.
+// maxEditHunkLines caps how many lines of each side of an edit are kept in
+// the Edit tool summary.
+const maxEditHunkLines = 20
+
+// formatEditInput formats Edit tool input as the file path followed by a
+// compact hunk: removed lines prefixed "- " and added lines prefixed "+ ".
 func formatEditInput(data map[string]any) string {
 	path, _ := data["file_path"].(string)
-	if path != "" {
-		return path
+	if path == "" {
+		return formatGenericInput(data)
 	}
-	return formatGenericInput(data)
+
+	oldStr, _ := data["old_string"].(string)
+	newStr, _ := data["new_string"].(string)
+	lines := []string{path}
+	lines = appendHunkLines(lines, "- ", oldStr)
+	lines = appendHunkLines(lines, "+ ", newStr)
+	return strings.Join(lines, "\n")
+}
+
+// appendHunkLines appends each line of s to lines with prefix, keeping at
+// most maxEditHunkLines and noting how many were dropped.
+func appendHunkLines(lines []string, prefix, s string) []string {
+	if s == "" {
+		return lines
+	}
+	hunk := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, line := range hunk {
+		if i == maxEditHunkLines {
+			return append(lines, fmt.Sprintf("%s... (%d more lines)", prefix, len(hunk)-i))
+		}
+		lines = append(lines, prefix+line)
+	}
+	return lines
+}
+
+// formatTodoWriteInput formats TodoWrite tool input as a checklist, one todo
+// per line: "[x]" for completed, "[~]" for in progress, "[ ]" otherwise.
+func formatTodoWriteInput(data map[string]any) string {
+	todos, ok := data["todos"].([]any)
+	if !ok {
+		return formatGenericInput(data)
+	}
+
+	lines := make([]string, 0, len(todos))
+	for _, t := range todos {
+		todo, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+		content, _ := todo["content"].(string)
+		mark := "[ ]"
+		switch todo["status"] {
+		case "completed":
+			mark = "[x]"
+		case "in_progress":
+			mark = "[~]"
+		}
+		lines = append(lines, mark+" "+content)
+	}
+	return strings.Join(lines, "\n")
 }
 
 // formatGlobInput formats Glob tool input.
@@ -310,6 +380,18 @@ func (m *StreamMessage) GetText() string {
 	return strings.Join(texts, "\n")
 }
 
+// TextDelta returns the text chunk of a partial assistant message, or ""
+// if the message isn't a text delta.
+func (m *StreamMessage) TextDelta() string {
+	if m.Type != "stream_event" || m.Event == nil || m.Event.Type != "content_block_delta" {
+		return ""
+	}
+	if m.Event.Delta == nil || m.Event.Delta.Type != "text_delta" {
+		return ""
+	}
+	return m.Event.Delta.Text
+}
+
 // GetToolUses returns all tool_use blocks from the message.
 func (m *StreamMessage) GetToolUses() []ContentBlock {
 	if m.Message == nil {
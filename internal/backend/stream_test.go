@@ -2,6 +2,7 @@ package backend
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -146,6 +147,48 @@ func TestStreamMessage_GetText(t *testing.T) {
 	}
 }
 
+func TestStreamMessage_TextDelta(t *testing.T) {
+	b := &ClaudeBackend{}
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "text delta",
+			line: `{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}}`,
+			want: "Hel",
+		},
+		{
+			name: "tool input delta",
+			line: `{"type":"stream_event","event":{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"a"}}}`,
+			want: "",
+		},
+		{
+			name: "other stream event",
+			line: `{"type":"stream_event","event":{"type":"message_stop"}}`,
+			want: "",
+		},
+		{
+			name: "complete message",
+			line: `{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Hello"}]}}`,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := b.ParseStreamMessage([]byte(tt.line))
+			if err != nil {
+				t.Fatalf("ParseStreamMessage() error = %v", err)
+			}
+			if got := msg.TextDelta(); got != tt.want {
+				t.Errorf("TextDelta() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestStreamMessage_GetToolUses(t *testing.T) {
 	msg := &StreamMessage{
 		Message: &NestedMessage{
@@ -350,8 +393,26 @@ func TestFormatToolInput(t *testing.T) {
 			name:  "Edit file",
 			tool:  "Edit",
 			input: `{"file_path":"/tmp/edit.txt","old_string":"a","new_string":"b"}`,
+			want:  "/tmp/edit.txt\n- a\n+ b",
+		},
+		{
+			name:  "Edit multi-line hunk",
+			tool:  "Edit",
+			input: `{"file_path":"/tmp/edit.txt","old_string":"x := 1\n","new_string":"x := 2\ny := 3\n"}`,
+			want:  "/tmp/edit.txt\n- x := 1\n+ x := 2\n+ y := 3",
+		},
+		{
+			name:  "Edit without strings",
+			tool:  "Edit",
+			input: `{"file_path":"/tmp/edit.txt"}`,
 			want:  "/tmp/edit.txt",
 		},
+		{
+			name:  "TodoWrite checklist",
+			tool:  "TodoWrite",
+			input: `{"todos":[{"content":"Write tests","status":"completed"},{"content":"Fix bug","status":"in_progress"},{"content":"Ship it","status":"pending"}]}`,
+			want:  "[x] Write tests\n[~] Fix bug\n[ ] Ship it",
+		},
 		{
 			name:  "Glob pattern",
 			tool:  "Glob",
@@ -383,10 +444,10 @@ func TestFormatToolInput(t *testing.T) {
 			want:  `key="value"`,
 		},
 		{
-			name:  "Long bash command truncated",
+			name:  "Long bash command kept whole",
 			tool:  "Bash",
-			input: `{"command":"` + "echo 'this is a very long command that should definitely be truncated because it exceeds one hundred characters'" + `"}`,
-			want:  "echo 'this is a very long command that should definitely be truncated because it exceeds one hund...",
+			input: `{"command":"` + "echo 'this is a very long command that used to be truncated because it exceeds one hundred characters'" + `"}`,
+			want:  "echo 'this is a very long command that used to be truncated because it exceeds one hundred characters'",
 		},
 	}
 
@@ -413,3 +474,16 @@ func TestFormatToolInput_InvalidJSON(t *testing.T) {
 		t.Errorf("FormatToolInput(invalid) = %q, want raw input", got)
 	}
 }
+
+func TestFormatToolInput_EditHunkCapped(t *testing.T) {
+	old := strings.Repeat("line\n", maxEditHunkLines+5)
+	input, _ := json.Marshal(map[string]string{"file_path": "/tmp/big.go", "old_string": old})
+
+	lines := strings.Split(FormatToolInput("Edit", input), "\n")
+	if len(lines) != maxEditHunkLines+2 {
+		t.Fatalf("got %d lines, want %d", len(lines), maxEditHunkLines+2)
+	}
+	if last := lines[len(lines)-1]; last != "- ... (5 more lines)" {
+		t.Errorf("last line = %q, want %q", last, "- ... (5 more lines)")
+	}
+}
@@ -1,15 +1,21 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"text/tabwriter"
 	"time"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
+	"github.com/tessro/fab/internal/config"
+	"github.com/tessro/fab/internal/daemon"
 	"github.com/tessro/fab/internal/tui"
 )
 
@@ -19,20 +25,29 @@ var agentCmd = &cobra.Command{
 	Long:  "Commands for managing Claude Code agents.",
 }
 
-var agentListProject string
+var (
+	agentListProject string
+	agentListSort    string
+	agentListOrder   string
+)
 
 var agentListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List running agents",
-	Long:  "List all running agents, optionally filtered by project.",
-	RunE:  runAgentList,
+	Long: `List all running agents, optionally filtered by project.
+
+--sort orders the list by "state" (agents waiting on a permission or question
+first, then errored, stuck, idle, running, starting, done), "project", or
+"started" (oldest first). --order desc reverses it. Agents marked ! need
+attention.`,
+	RunE: runAgentList,
 }
 
 func runAgentList(cmd *cobra.Command, args []string) error {
 	client := MustConnect()
 	defer client.Close()
 
-	resp, err := client.AgentList(agentListProject)
+	resp, err := client.AgentListSorted(agentListProject, agentListSort, agentListOrder)
 	if err != nil {
 		return fmt.Errorf("list agents: %w", err)
 	}
@@ -47,7 +62,7 @@ func runAgentList(cmd *cobra.Command, args []string) error {
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, " \tID\tPROJECT\tBACKEND\tDESCRIPTION\tAGE")
+	_, _ = fmt.Fprintln(w, " \tID\tNAME\tPROJECT\tBACKEND\tDESCRIPTION\tAGE")
 
 	for _, a := range resp.Agents {
 		age := formatDuration(time.Since(a.StartedAt))
@@ -63,8 +78,15 @@ func runAgentList(cmd *cobra.Command, args []string) error {
 		if backend == "" {
 			backend = "-"
 		}
+		name := a.Name
+		if name == "" {
+			name = "-"
+		}
 		icon := stateIcon(a.State)
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", icon, a.ID, a.Project, backend, desc, age)
+		if a.NeedsAttention {
+			icon = "!"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", icon, a.ID, name, a.Project, backend, desc, age)
 	}
 
 	_ = w.Flush()
@@ -84,6 +106,8 @@ func stateIcon(state string) string {
 		return "✓"
 	case "error":
 		return "✗"
+	case "stuck":
+		return "⚠"
 	case "stopped":
 		return "○"
 	case "stopping":
@@ -114,11 +138,12 @@ var (
 )
 
 var agentAbortCmd = &cobra.Command{
-	Use:   "abort <agent-id>",
-	Short: "Abort a running agent",
-	Long:  "Abort a running agent. By default sends /quit for graceful shutdown. Use --force to kill immediately.",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runAgentAbort,
+	Use:               "abort <agent-id>",
+	Short:             "Abort a running agent",
+	Long:              "Abort a running agent. By default sends /quit for graceful shutdown. Use --force to kill immediately; uncommitted work is stashed first and can be restored with 'fab agent recover'.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAgent,
+	RunE:              runAgentAbort,
 }
 
 func runAgentAbort(cmd *cobra.Command, args []string) error {
@@ -159,6 +184,7 @@ func runAgentAbort(cmd *cobra.Command, args []string) error {
 		}
 		if abortForce {
 			fmt.Printf("🚌 Force killed agent %s\n", agentID)
+			fmt.Printf("   Uncommitted work, if any, was stashed. Restore it with: fab agent recover %s\n", agentID)
 		} else {
 			fmt.Printf("🚌 Sent quit to agent %s\n", agentID)
 		}
@@ -166,22 +192,290 @@ func runAgentAbort(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var (
+	recoverProject string
+	recoverBranch  string
+)
+
+var agentRecoverCmd = &cobra.Command{
+	Use:   "recover [agent-id]",
+	Short: "Recover uncommitted work saved by a forced abort",
+	Long: `Force-aborting an agent stashes any uncommitted changes in its worktree.
+Recover pops that stash into a new branch (default: fab/recover-<agent-id>) and
+commits it. Without an agent ID, the most recent stash in the project is used.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeAgent,
+	RunE:              runAgentRecover,
+}
+
+func runAgentRecover(cmd *cobra.Command, args []string) error {
+	var agentID string
+	if len(args) > 0 {
+		agentID = args[0]
+	}
+	if agentID == "" && recoverProject == "" {
+		return fmt.Errorf("specify an agent ID or --project")
+	}
+
+	client := MustConnect()
+	defer client.Close()
+
+	resp, err := client.AgentRecover(agentID, recoverProject, recoverBranch)
+	if err != nil {
+		return fmt.Errorf("recover agent work: %w", err)
+	}
+
+	fmt.Printf("🚌 Recovered work from agent %s\n", resp.ID)
+	fmt.Printf("   Project: %s\n", resp.Project)
+	fmt.Printf("   Branch: %s\n", resp.Branch)
+	fmt.Printf("   Commit: %s\n", resp.Commit)
+	return nil
+}
+
+var debugCaptureOff bool
+
+var agentDebugCaptureCmd = &cobra.Command{
+	Use:   "debug-capture <agent-id>",
+	Short: "Capture an agent's raw CLI output to a log file",
+	Long: `Tee the agent's raw CLI output, before it is parsed, into
+~/.fab/debug/<agent-id>.log. The log rotates to <agent-id>.log.1 at 10MB.
+Stdout is captured immediately; stderr is captured from the next time the
+agent's process starts. Use --off to stop capturing.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAgent,
+	RunE:              runAgentDebugCapture,
+}
+
+func runAgentDebugCapture(cmd *cobra.Command, args []string) error {
+	client := MustConnect()
+	defer client.Close()
+
+	resp, err := client.AgentDebugCapture(args[0], !debugCaptureOff)
+	if err != nil {
+		return fmt.Errorf("set debug capture: %w", err)
+	}
+
+	if debugCaptureOff {
+		fmt.Printf("🚌 Stopped debug capture for agent %s\n", args[0])
+	} else {
+		fmt.Printf("🚌 Capturing raw output of agent %s\n", args[0])
+		fmt.Printf("   Log: %s\n", resp.Path)
+	}
+	return nil
+}
+
+var agentCompactCmd = &cobra.Command{
+	Use:   "compact <agent-id>",
+	Short: "Replace an agent's long chat history with a summary",
+	Long: `Ask an idle agent to summarize its conversation so far, then replace its
+chat history in the daemon with that summary. The full history is saved to
+the transcript store first, so it can still be exported or replayed.
+
+The summary is requested in the background; the command returns once the
+agent has been asked.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAgent,
+	RunE:              runAgentCompact,
+}
+
+func runAgentCompact(cmd *cobra.Command, args []string) error {
+	client := MustConnect()
+	defer client.Close()
+
+	resp, err := client.AgentCompact(args[0])
+	if err != nil {
+		return fmt.Errorf("compact agent: %w", err)
+	}
+
+	fmt.Printf("🚌 Asked agent %s for a summary to replace %d chat entries\n", resp.ID, resp.Entries)
+	return nil
+}
+
+var transcriptWrite bool
+var transcriptPath string
+
+var agentTranscriptCmd = &cobra.Command{
+	Use:   "transcript <agent-id>",
+	Short: "Export an agent's conversation as markdown",
+	Long: `Render the agent's chat history as a markdown document, with tool calls
+in collapsible blocks, and print it to stdout. Use --write to save it into
+the agent's worktree instead (default file: transcript-<agent-id>.md).`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAgent,
+	RunE:              runAgentTranscript,
+}
+
+func runAgentTranscript(cmd *cobra.Command, args []string) error {
+	client := MustConnect()
+	defer client.Close()
+
+	write := transcriptWrite || transcriptPath != ""
+	resp, err := client.AgentTranscriptExport(args[0], write, transcriptPath)
+	if err != nil {
+		return fmt.Errorf("export transcript: %w", err)
+	}
+
+	if write {
+		fmt.Printf("🚌 Wrote transcript for agent %s\n", args[0])
+		fmt.Printf("   File: %s\n", resp.Path)
+		return nil
+	}
+	fmt.Print(resp.Markdown)
+	return nil
+}
+
+var (
+	grepTool       string
+	grepIgnoreCase bool
+	grepContext    int
+	grepLimit      int
+)
+
+var agentGrepCmd = &cobra.Command{
+	Use:   "grep <agent-id> [pattern]",
+	Short: "Search an agent's conversation",
+	Long: `Search an agent's whole chat history in the daemon, including entries
+spilled to disk and the saved transcripts of deleted agents. The pattern is
+a Go regular expression matched against message text, tool input, and tool
+output. --tool limits the search to calls to and results from one tool;
+without a pattern it lists them all.`,
+	Example: `  fab agent grep 9b830e 'FAIL|panic'
+  fab agent grep 9b830e --tool Bash -C 1 'go test'`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeAgent,
+	RunE:              runAgentGrep,
+}
+
+func runAgentGrep(cmd *cobra.Command, args []string) error {
+	var pattern string
+	if len(args) > 1 {
+		pattern = args[1]
+	}
+	if pattern == "" && grepTool == "" {
+		return fmt.Errorf("a pattern or --tool is required")
+	}
+	if grepIgnoreCase && pattern != "" {
+		pattern = "(?i)" + pattern
+	}
+
+	client := MustConnect()
+	defer client.Close()
+
+	resp, err := client.AgentGrep(args[0], pattern, grepTool, grepContext, grepLimit)
+	if err != nil {
+		return fmt.Errorf("search agent: %w", err)
+	}
+
+	if len(resp.Matches) == 0 {
+		fmt.Printf("No matches in %d entries\n", resp.Searched)
+		return nil
+	}
+	for i, m := range resp.Matches {
+		if i > 0 && grepContext > 0 {
+			fmt.Println("--")
+		}
+		for _, e := range m.Before {
+			fmt.Println("  " + formatGrepEntry(e))
+		}
+		fmt.Println("> " + formatGrepEntry(m.Entry))
+		for _, e := range m.After {
+			fmt.Println("  " + formatGrepEntry(e))
+		}
+	}
+	if resp.Truncated {
+		fmt.Printf("\nShowing the first %d matches; use --limit to see more\n", len(resp.Matches))
+	}
+	return nil
+}
+
+// grepLineWidth caps the text shown for each entry in fab agent grep.
+const grepLineWidth = 160
+
+// formatGrepEntry renders a chat entry as one line: its sequence number,
+// who or which tool it's from, and the first line of its text.
+func formatGrepEntry(e daemon.ChatEntryDTO) string {
+	label, text := e.Role, e.Content
+	switch {
+	case e.ToolName != "":
+		label, text = e.ToolName, e.ToolInput
+	case e.Role == "tool":
+		label, text = "result", e.ToolResult
+		if e.IsError {
+			label = "error"
+		}
+	}
+	text, _, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	if utf8.RuneCountInString(text) > grepLineWidth {
+		text = string([]rune(text)[:grepLineWidth-3]) + "..."
+	}
+	return fmt.Sprintf("#%d %s: %s", e.Seq, label, text)
+}
+
+var agentExecCmd = &cobra.Command{
+	Use:   "exec <agent-id> -- <command>...",
+	Short: "Run a shell command in an agent's worktree",
+	Long: `Run a shell command in the agent's worktree and print its output, without
+sending anything to the agent. The command runs with sh -c, is killed after
+exec.timeout (default 30s), and only the last 64KB of output is returned.
+
+Disabled unless exec.enabled = true is set in the daemon's config.toml.`,
+	Example: `  fab agent exec 9b830e -- git status --short
+  fab agent exec 9b830e -- 'go test ./... 2>&1 | tail -20'`,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeAgent,
+	RunE:              runAgentExec,
+}
+
+func runAgentExec(cmd *cobra.Command, args []string) error {
+	client := MustConnect()
+	defer client.Close()
+
+	// Wait as long as exec.timeout allows; the daemon kills the command at
+	// whichever of its own timeout and ours is shorter
+	cfg, _ := config.LoadGlobalConfig()
+
+	agentID := args[0]
+	resp, err := client.AgentExec(agentID, strings.Join(args[1:], " "), cfg.GetExecTimeout())
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	if resp.Truncated {
+		_, _ = fmt.Fprintln(os.Stderr, "🚌 Output truncated; showing the end")
+	}
+	fmt.Print(resp.Output)
+
+	if resp.TimedOut {
+		return fmt.Errorf("command timed out in %s", resp.Worktree)
+	}
+	if resp.ExitCode != 0 {
+		return fmt.Errorf("command exited with status %d", resp.ExitCode)
+	}
+	return nil
+}
+
 var agentClaimCmd = &cobra.Command{
-	Use:   "claim <ticket-id>",
-	Short: "Claim a ticket for this agent",
-	Long:  "Claim a ticket to prevent other agents from working on it. Uses FAB_AGENT_ID env var.",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runAgentClaim,
+	Use:   "claim [agent-id] <ticket-id>",
+	Short: "Claim a ticket for an agent",
+	Long: `Claim a ticket to prevent other agents from working on it. With a single
+argument, claims for the agent in FAB_AGENT_ID; operators can pass an agent
+ID to reserve a ticket for that agent.`,
+	Example: `  fab agent claim 42           # From inside an agent
+  fab agent claim a1b2c3 42    # Give ticket 42 to agent a1b2c3`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runAgentClaim,
 }
 
 func runAgentClaim(cmd *cobra.Command, args []string) error {
 	agentID := os.Getenv("FAB_AGENT_ID")
+	ticketID := args[0]
+	if len(args) == 2 {
+		agentID, ticketID = args[0], args[1]
+	}
 	if agentID == "" {
-		return fmt.Errorf("FAB_AGENT_ID environment variable not set")
+		return fmt.Errorf("agent ID required (pass it as an argument or set FAB_AGENT_ID)")
 	}
 
-	ticketID := args[0]
-
 	client := MustConnect()
 	defer client.Close()
 
@@ -189,7 +483,66 @@ func runAgentClaim(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("claim failed: %w", err)
 	}
 
-	fmt.Printf("🚌 Claimed %s\n", ticketID)
+	if len(args) == 2 {
+		fmt.Printf("🚌 Claimed %s for agent %s\n", ticketID, agentID)
+	} else {
+		fmt.Printf("🚌 Claimed %s\n", ticketID)
+	}
+	return nil
+}
+
+var unclaimProject string
+
+var agentUnclaimCmd = &cobra.Command{
+	Use:   "unclaim <ticket-id>",
+	Short: "Release a ticket claim held by any agent",
+	Long: `Release the claim on a ticket so another agent can pick it up, for example
+when the agent holding it is stuck. The agent keeps running. If the same
+ticket ID is claimed in more than one project, pass --project.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentUnclaim,
+}
+
+func runAgentUnclaim(cmd *cobra.Command, args []string) error {
+	client := MustConnect()
+	defer client.Close()
+
+	claim, err := client.ClaimRelease(args[0], unclaimProject)
+	if err != nil {
+		return fmt.Errorf("unclaim failed: %w", err)
+	}
+
+	fmt.Printf("🚌 Released %s from agent %s (%s)\n", claim.TicketID, claim.AgentID, claim.Project)
+	return nil
+}
+
+var delegateProject string
+
+var agentDelegateCmd = &cobra.Command{
+	Use:   "delegate <ticket-id>",
+	Short: "Spawn an agent to work on a specific ticket",
+	Long: `Spawn a new agent with the ticket already claimed for it. Intended for the
+manager, so it can hand work to an agent from within its conversation.
+The caller's FAB_AGENT_ID, if set, is recorded as the agent's delegator.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentDelegate,
+}
+
+func runAgentDelegate(cmd *cobra.Command, args []string) error {
+	ticketID := args[0]
+	delegatedBy := os.Getenv("FAB_AGENT_ID")
+
+	client := MustConnect()
+	defer client.Close()
+
+	resp, err := client.AgentDelegate(ticketID, delegateProject, delegatedBy)
+	if err != nil {
+		return fmt.Errorf("delegate failed: %w", err)
+	}
+
+	fmt.Printf("🚌 Delegated %s to agent %s\n", resp.TicketID, resp.ID)
+	fmt.Printf("   Project: %s\n", resp.Project)
+	fmt.Printf("   Worktree: %s\n", resp.Worktree)
 	return nil
 }
 
@@ -227,6 +580,236 @@ func runAgentDescribe(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var agentRenameCmd = &cobra.Command{
+	Use:   "rename [agent-id] <name>",
+	Short: "Set a human-friendly name for an agent",
+	Long: `Set the name shown alongside an agent's ID in the TUI and agent list.
+The ID remains the canonical key. With a single argument, renames the agent
+in FAB_AGENT_ID. Pass an empty name to clear it.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runAgentRename,
+}
+
+func runAgentRename(cmd *cobra.Command, args []string) error {
+	agentID := os.Getenv("FAB_AGENT_ID")
+	name := args[0]
+	if len(args) == 2 {
+		agentID, name = args[0], args[1]
+	}
+	if agentID == "" {
+		return fmt.Errorf("agent ID required (pass it as an argument or set FAB_AGENT_ID)")
+	}
+
+	client := MustConnect()
+	defer client.Close()
+
+	if err := client.AgentRename(agentID, name); err != nil {
+		return fmt.Errorf("rename failed: %w", err)
+	}
+
+	if name == "" {
+		fmt.Printf("🚌 Cleared name of agent %s\n", agentID)
+	} else {
+		fmt.Printf("🚌 Agent %s is now %s\n", agentID, name)
+	}
+	return nil
+}
+
+// maxSendChunkBytes caps the size of one message sent by fab agent send,
+// keeping each stream-json line written to the agent well within limits.
+const maxSendChunkBytes = 64 * 1024
+
+var (
+	sendFile  string
+	sendFence bool
+)
+
+var agentSendCmd = &cobra.Command{
+	Use:   "send <agent-id> [message]",
+	Short: "Send a message or file contents to an agent",
+	Long: `Send a message to an agent, as if typed in the TUI. With --file, the
+file's contents are sent ("-" reads stdin), after the message if one is
+given. --fence wraps the contents in a code fence.
+
+Contents larger than 64KB are split at line boundaries into parts, each
+labeled "Part i/n", and sent in order. The agent queues the parts and
+reads them as consecutive turns.
+
+Examples:
+  fab agent send a1b2c3 "Please rebase onto main"
+  fab agent send a1b2c3 "Why does this test fail?" --file test.log --fence
+  go test ./... 2>&1 | fab agent send a1b2c3 --file -`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeAgent,
+	RunE:              runAgentSend,
+}
+
+func runAgentSend(cmd *cobra.Command, args []string) error {
+	agentID := args[0]
+	var message string
+	if len(args) == 2 {
+		message = args[1]
+	}
+	if message == "" && sendFile == "" {
+		return fmt.Errorf("a message or --file is required")
+	}
+
+	var messages []string
+	if sendFile == "" {
+		messages = []string{message}
+	} else {
+		name := sendFile
+		var data []byte
+		var err error
+		if sendFile == "-" {
+			name = "stdin"
+			data, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			data, err = os.ReadFile(sendFile)
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+		if len(data) == 0 {
+			return fmt.Errorf("%s is empty", name)
+		}
+		messages = fileMessages(message, name, string(data), sendFence, maxSendChunkBytes)
+	}
+
+	client := MustConnect()
+	defer client.Close()
+
+	for i, m := range messages {
+		if err := client.AgentSendMessage(agentID, m); err != nil {
+			if len(messages) > 1 {
+				return fmt.Errorf("send part %d/%d: %w", i+1, len(messages), err)
+			}
+			return fmt.Errorf("send failed: %w", err)
+		}
+	}
+
+	if len(messages) > 1 {
+		fmt.Printf("🚌 Sent %s to agent %s in %d parts\n", sendFile, agentID, len(messages))
+	} else {
+		fmt.Printf("🚌 Message sent to agent %s\n", agentID)
+	}
+	return nil
+}
+
+// fileMessages builds the messages that send a file's contents to an agent:
+// the intro message (if any) followed by the contents, optionally fenced.
+// Contents that don't fit in chunkSize bytes are split into labeled parts.
+func fileMessages(intro, name, content string, fence bool, chunkSize int) []string {
+	chunks := splitChunks(content, chunkSize)
+	messages := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		if fence {
+			chunk = fenceCode(chunk, strings.TrimPrefix(filepath.Ext(name), "."))
+		}
+		var header string
+		if len(chunks) > 1 {
+			header = fmt.Sprintf("Part %d/%d of %s:\n", i+1, len(chunks), name)
+		}
+		if i == 0 && intro != "" {
+			header = intro + "\n\n" + header
+		}
+		messages[i] = header + chunk
+	}
+	return messages
+}
+
+// splitChunks splits s into chunks of at most size bytes, breaking after a
+// newline where possible and never inside a UTF-8 sequence.
+func splitChunks(s string, size int) []string {
+	var chunks []string
+	for len(s) > size {
+		cut := strings.LastIndexByte(s[:size], '\n') + 1
+		if cut == 0 {
+			cut = size
+			for cut > 0 && !utf8.RuneStart(s[cut]) {
+				cut--
+			}
+		}
+		chunks = append(chunks, s[:cut])
+		s = s[cut:]
+	}
+	return append(chunks, s)
+}
+
+// fenceCode wraps code in a Markdown code fence longer than any backtick
+// run inside it, so the code can't close the fence early.
+func fenceCode(code, lang string) string {
+	longest, run := 0, 0
+	for _, r := range code {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + lang + "\n" + strings.TrimSuffix(code, "\n") + "\n" + fence
+}
+
+var agentLineageProject string
+
+var agentLineageCmd = &cobra.Command{
+	Use:   "lineage",
+	Short: "Show which agent spawned which",
+	Long: `Show agents as a tree by who spawned them. Agents delegated to by another
+agent are nested under it; top-level agents show their spawner: the user,
+the orchestrator, or a project's manager.`,
+	Args: cobra.NoArgs,
+	RunE: runAgentLineage,
+}
+
+func runAgentLineage(cmd *cobra.Command, args []string) error {
+	client := MustConnect()
+	defer client.Close()
+
+	resp, err := client.AgentLineage(agentLineageProject)
+	if err != nil {
+		return fmt.Errorf("agent lineage: %w", err)
+	}
+
+	if len(resp.Roots) == 0 {
+		fmt.Println("No agents running")
+		return nil
+	}
+
+	for _, root := range resp.Roots {
+		printLineageNode(os.Stdout, root, "", "")
+	}
+	return nil
+}
+
+// printLineageNode writes node and its descendants as an indented tree.
+// linePrefix precedes the node's own line and childPrefix its children's;
+// roots have neither and show their spawner instead.
+func printLineageNode(w io.Writer, node daemon.LineageNode, linePrefix, childPrefix string) {
+	label := fmt.Sprintf("%s %s", stateIcon(node.State), node.ID)
+	if node.Name != "" {
+		label += " " + node.Name
+	}
+	if linePrefix == "" {
+		spawner := node.SpawnedBy
+		if spawner == "" {
+			spawner = "unknown"
+		}
+		label += fmt.Sprintf("  (%s, spawned by %s)", node.Project, spawner)
+	}
+	_, _ = fmt.Fprintln(w, linePrefix+label)
+
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			printLineageNode(w, child, childPrefix+"└─ ", childPrefix+"   ")
+		} else {
+			printLineageNode(w, child, childPrefix+"├─ ", childPrefix+"│  ")
+		}
+	}
+}
+
 func runAgentDone(cmd *cobra.Command, args []string) error {
 	agentID := os.Getenv("FAB_AGENT_ID")
 	if agentID == "" {
@@ -237,16 +820,17 @@ func runAgentDone(cmd *cobra.Command, args []string) error {
 	isPlanner := strings.HasPrefix(agentID, tui.PlannerAgentIDPrefix)
 
 	if !isPlanner {
-		// Pre-rebase: fetch and rebase onto origin/main to catch conflicts early
+		// Pre-rebase: fetch and rebase onto the default branch to catch conflicts early
 		// Agent runs in worktree, so use current directory
-		fmt.Println("🚌 Rebasing onto origin/main...")
+		baseRef := defaultBranchRef("")
+		fmt.Printf("🚌 Rebasing onto %s...\n", baseRef)
 
 		fetchCmd := exec.Command("git", "fetch", "origin")
 		if output, err := fetchCmd.CombinedOutput(); err != nil {
 			return fmt.Errorf("fetch origin: %w\n%s", err, output)
 		}
 
-		rebaseCmd := exec.Command("git", "rebase", "origin/main")
+		rebaseCmd := exec.Command("git", "rebase", baseRef)
 		if output, err := rebaseCmd.CombinedOutput(); err != nil {
 			// Rebase failed - abort and return error
 			abortCmd := exec.Command("git", "rebase", "--abort")
@@ -262,6 +846,9 @@ func runAgentDone(cmd *cobra.Command, args []string) error {
 
 	resp, err := client.AgentDoneWithResponse(agentID, doneTaskID, doneErrorMsg)
 	if err != nil {
+		if resp != nil {
+			printConflicts(os.Stderr, resp.Conflicts)
+		}
 		return fmt.Errorf("agent done: %w", err)
 	}
 
@@ -269,6 +856,8 @@ func runAgentDone(cmd *cobra.Command, args []string) error {
 		fmt.Printf("🚌 Agent %s signaled error: %s\n", agentID, doneErrorMsg)
 	} else if isPlanner {
 		fmt.Printf("🚌 Plan agent %s completed\n", agentID)
+	} else if resp.DryRun {
+		fmt.Printf("🚌 Agent %s completed (dry run: %s was not merged)\n", agentID, resp.BranchName)
 	} else if resp.PRCreated {
 		fmt.Printf("🚌 Agent %s completed and created PR: %s\n", agentID, resp.PRURL)
 	} else if resp.Merged {
@@ -279,6 +868,20 @@ func runAgentDone(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printConflicts shows the conflict hunks from a failed merge, so the agent
+// can resolve them without re-running the rebase.
+func printConflicts(w io.Writer, conflicts []daemon.ConflictFile) {
+	for _, f := range conflicts {
+		_, _ = fmt.Fprintf(w, "🚌 Conflict in %s\n", f.Path)
+		for _, h := range f.Hunks {
+			_, _ = fmt.Fprintf(w, "   lines %d-%d:\n%s\n", h.StartLine, h.EndLine, h.Text)
+		}
+		if f.Truncated {
+			_, _ = fmt.Fprintln(w, "   (more conflicts not shown)")
+		}
+	}
+}
+
 // Agent plan subcommand for managing planning agents
 var agentPlanProject string
 
@@ -339,20 +942,47 @@ func runAgentPlan(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var agentPlanListState string
+var agentPlanListCompleted bool
+var agentPlanListJSON bool
+
 var agentPlanListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List planning agents",
+	Long: `List planning agents, optionally filtered by project and state.
+
+With --completed, only planners that have written their plan are listed.
+With --json, each planner's plan_file gives the stored plan, so a script can
+collect the completed plans for a project.`,
+	Example: `  fab agent plan list --project myapp --completed --json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := MustConnect()
 		defer client.Close()
 
-		resp, err := client.PlanList(agentPlanProject)
+		resp, err := client.PlanListFiltered(daemon.PlanListRequest{
+			Project:       agentPlanProject,
+			State:         agentPlanListState,
+			CompletedOnly: agentPlanListCompleted,
+		})
 		if err != nil {
 			return fmt.Errorf("list planners: %w", err)
 		}
 
+		if agentPlanListJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(resp.Planners); err != nil {
+				return fmt.Errorf("encode planners: %w", err)
+			}
+			return nil
+		}
+
 		if len(resp.Planners) == 0 {
-			fmt.Println("No planning agents running")
+			if agentPlanListState != "" || agentPlanListCompleted {
+				fmt.Println("No matching planning agents")
+			} else {
+				fmt.Println("No planning agents running")
+			}
 			return nil
 		}
 
@@ -408,25 +1038,72 @@ var agentPlanStopCmd = &cobra.Command{
 
 func init() {
 	agentListCmd.Flags().StringVarP(&agentListProject, "project", "p", "", "Filter by project name")
+	_ = agentListCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
+	agentListCmd.Flags().StringVarP(&agentListSort, "sort", "s", "", "Sort by state, project, or started")
+	agentListCmd.Flags().StringVar(&agentListOrder, "order", "", "Sort order: asc or desc")
 	agentCmd.AddCommand(agentListCmd)
 
 	agentAbortCmd.Flags().BoolVarP(&abortForce, "force", "f", false, "Force kill immediately (SIGKILL)")
 	agentAbortCmd.Flags().BoolVarP(&abortNoConfirm, "yes", "y", false, "Skip confirmation prompt")
 	agentCmd.AddCommand(agentAbortCmd)
 
+	agentRecoverCmd.Flags().StringVarP(&recoverProject, "project", "p", "", "Project to recover from (required if the agent was deleted)")
+	_ = agentRecoverCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
+	agentRecoverCmd.Flags().StringVarP(&recoverBranch, "branch", "b", "", "Branch to create (default: fab/recover-<agent-id>)")
+	agentCmd.AddCommand(agentRecoverCmd)
+
+	agentDebugCaptureCmd.Flags().BoolVar(&debugCaptureOff, "off", false, "Stop capturing")
+	agentCmd.AddCommand(agentDebugCaptureCmd)
+
+	agentTranscriptCmd.Flags().BoolVarP(&transcriptWrite, "write", "w", false, "Write the transcript into the agent's worktree")
+	agentTranscriptCmd.Flags().StringVar(&transcriptPath, "path", "", "File path relative to the worktree (implies --write)")
+	agentCmd.AddCommand(agentTranscriptCmd)
+
+	agentCmd.AddCommand(agentCompactCmd)
+
+	agentGrepCmd.Flags().StringVar(&grepTool, "tool", "", "Only search calls to and results from this tool (e.g. Bash)")
+	agentGrepCmd.Flags().BoolVarP(&grepIgnoreCase, "ignore-case", "i", false, "Match the pattern case-insensitively")
+	agentGrepCmd.Flags().IntVarP(&grepContext, "context", "C", 0, "Show this many entries before and after each match (max 20)")
+	agentGrepCmd.Flags().IntVar(&grepLimit, "limit", 0, "Maximum matches to show (default 100)")
+	agentCmd.AddCommand(agentGrepCmd)
+
+	agentCmd.AddCommand(agentExecCmd)
+
 	agentCmd.AddCommand(agentClaimCmd)
 
+	agentUnclaimCmd.Flags().StringVarP(&unclaimProject, "project", "p", "", "Project of the ticket (needed only if claimed in several projects)")
+	_ = agentUnclaimCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
+	agentCmd.AddCommand(agentUnclaimCmd)
+
+	agentDelegateCmd.Flags().StringVarP(&delegateProject, "project", "p", "", "Project of the ticket (default: the caller's project)")
+	_ = agentDelegateCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
+	agentCmd.AddCommand(agentDelegateCmd)
+
 	agentDoneCmd.Flags().StringVar(&doneErrorMsg, "error", "", "Error message if task failed")
 	agentDoneCmd.Flags().StringVar(&doneTaskID, "task", "", "Task ID that was completed")
 	agentCmd.AddCommand(agentDoneCmd)
 
 	agentCmd.AddCommand(agentDescribeCmd)
+	agentCmd.AddCommand(agentRenameCmd)
+
+	agentSendCmd.Flags().StringVarP(&sendFile, "file", "f", "", "Send the contents of a file (\"-\" for stdin)")
+	agentSendCmd.Flags().BoolVar(&sendFence, "fence", false, "Wrap the file contents in a code fence")
+	agentCmd.AddCommand(agentSendCmd)
+
+	agentLineageCmd.Flags().StringVarP(&agentLineageProject, "project", "p", "", "Filter by project name")
+	_ = agentLineageCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
+	agentCmd.AddCommand(agentLineageCmd)
 
 	// Agent plan subcommands
 	agentPlanCmd.Flags().StringVarP(&agentPlanProject, "project", "p", "", "Run in project worktree")
+	_ = agentPlanCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
 	agentPlanCmd.AddCommand(agentPlanListCmd)
 	agentPlanCmd.AddCommand(agentPlanStopCmd)
 	agentPlanListCmd.Flags().StringVarP(&agentPlanProject, "project", "p", "", "Filter by project")
+	_ = agentPlanListCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
+	agentPlanListCmd.Flags().StringVar(&agentPlanListState, "state", "", "Filter by state (stopped, starting, running, stopping)")
+	agentPlanListCmd.Flags().BoolVar(&agentPlanListCompleted, "completed", false, "Only list planners that have written their plan")
+	agentPlanListCmd.Flags().BoolVar(&agentPlanListJSON, "json", false, "Print planners as JSON")
 	agentCmd.AddCommand(agentPlanCmd)
 
 	rootCmd.AddCommand(agentCmd)
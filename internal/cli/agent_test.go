@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/tessro/fab/internal/daemon"
+)
+
+func TestSplitChunks(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		size int
+		want []string
+	}{
+		{"fits", "a\nb\n", 10, []string{"a\nb\n"}},
+		{"breaks after newline", "aaa\nbbb\nccc\n", 9, []string{"aaa\nbbb\n", "ccc\n"}},
+		{"long line", "abcdefgh", 3, []string{"abc", "def", "gh"}},
+		{"utf8 boundary", "héllo", 2, []string{"h", "é", "ll", "o"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitChunks(tt.in, tt.size)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("splitChunks(%q, %d) = %q, want %q", tt.in, tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFenceCode(t *testing.T) {
+	if got, want := fenceCode("x := 1\n", "go"), "```go\nx := 1\n```"; got != want {
+		t.Errorf("fenceCode() = %q, want %q", got, want)
+	}
+	if got, want := fenceCode("see ```a```", ""), "````\nsee ```a```\n````"; got != want {
+		t.Errorf("fenceCode() = %q, want %q", got, want)
+	}
+}
+
+func TestFileMessages(t *testing.T) {
+	single := fileMessages("Look at this", "main.go", "package main\n", true, 100)
+	if len(single) != 1 || single[0] != "Look at this\n\n```go\npackage main\n```" {
+		t.Errorf("fileMessages() = %q", single)
+	}
+
+	parts := fileMessages("Review", "log.txt", "line one\nline two\n", false, 10)
+	want := []string{
+		"Review\n\nPart 1/2 of log.txt:\nline one\n",
+		"Part 2/2 of log.txt:\nline two\n",
+	}
+	if strings.Join(parts, "|") != strings.Join(want, "|") {
+		t.Errorf("fileMessages() = %q, want %q", parts, want)
+	}
+}
+
+func TestFormatGrepEntry(t *testing.T) {
+	tests := []struct {
+		entry daemon.ChatEntryDTO
+		want  string
+	}{
+		{daemon.ChatEntryDTO{Role: "user", Content: "fix it\nplease", Seq: 3}, "#3 user: fix it"},
+		{daemon.ChatEntryDTO{Role: "tool", ToolName: "Bash", ToolInput: "go test ./...", Seq: 4}, "#4 Bash: go test ./..."},
+		{daemon.ChatEntryDTO{Role: "tool", ToolResult: "\nFAIL", IsError: true, Seq: 5}, "#5 error: FAIL"},
+		{daemon.ChatEntryDTO{Role: "tool", ToolResult: "ok", Seq: 6}, "#6 result: ok"},
+	}
+	for _, tt := range tests {
+		if got := formatGrepEntry(tt.entry); got != tt.want {
+			t.Errorf("formatGrepEntry(%+v) = %q, want %q", tt.entry, got, tt.want)
+		}
+	}
+
+	long := formatGrepEntry(daemon.ChatEntryDTO{Role: "assistant", Content: strings.Repeat("é", 500)})
+	if !strings.HasSuffix(long, "...") || utf8.RuneCountInString(long) > grepLineWidth+len("#0 assistant: ") {
+		t.Errorf("formatGrepEntry() = %q, want text truncated to %d runes", long, grepLineWidth)
+	}
+}
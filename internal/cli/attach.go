@@ -17,9 +17,10 @@ type eventResult struct {
 }
 
 var attachCmd = &cobra.Command{
-	Use:   "attach [projects...]",
-	Short: "Attach to agent streams and watch output",
-	Long:  "Connect to the daemon and stream live output from running agents. Optionally filter by project names.",
+	Use:               "attach [projects...]",
+	Short:             "Attach to agent streams and watch output",
+	Long:              "Connect to the daemon and stream live output from running agents. Optionally filter by project names.",
+	ValidArgsFunction: completeProjects,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := MustConnect()
 		defer client.Close()
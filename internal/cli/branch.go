@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tessro/fab/internal/project"
 )
 
 var branchCmd = &cobra.Command{
@@ -22,7 +23,7 @@ var branchCleanupLocal bool
 var branchCleanupCmd = &cobra.Command{
 	Use:   "cleanup",
 	Short: "Clean up merged fab/* branches",
-	Long: `Delete fab/* branches that have been merged to main.
+	Long: `Delete fab/* branches that have been merged to the default branch.
 
 By default, only deletes remote branches. Use --local to also delete local refs.
 Use --dry-run to see what would be deleted without making changes.`,
@@ -172,12 +173,14 @@ func getMergedFabBranches(repoDir string, remote bool) ([]string, error) {
 	return branches, nil
 }
 
-// isBranchMerged checks if a branch has been merged to origin/main.
+// isBranchMerged checks if a branch has been merged to the remote default branch.
 // It uses git cherry to detect commits that have equivalent commits in main,
 // which handles both regular merges and rebased merges.
 func isBranchMerged(repoDir, branch string) bool {
+	baseRef := defaultBranchRef(repoDir)
+
 	// Get the merge base between the branch and main
-	mergeBaseCmd := exec.Command("git", "merge-base", branch, "origin/main")
+	mergeBaseCmd := exec.Command("git", "merge-base", branch, baseRef)
 	mergeBaseCmd.Dir = repoDir
 	mergeBaseOutput, err := mergeBaseCmd.Output()
 	if err != nil {
@@ -192,7 +195,7 @@ func isBranchMerged(repoDir, branch string) bool {
 	// Commits with "+" prefix are NOT in main
 	// Commits with "-" prefix have equivalent commits in main
 	// If there are no "+" commits, the branch is merged
-	cherryCmd := exec.Command("git", "cherry", "origin/main", branch, mergeBase)
+	cherryCmd := exec.Command("git", "cherry", baseRef, branch, mergeBase)
 	cherryCmd.Dir = repoDir
 	cherryOutput, err := cherryCmd.Output()
 	if err != nil {
@@ -219,6 +222,16 @@ func isBranchMerged(repoDir, branch string) bool {
 	return true
 }
 
+// defaultBranchRef returns the remote default branch ref (e.g. "origin/main")
+// for the repo at dir, falling back to origin/main if origin/HEAD is unset.
+func defaultBranchRef(dir string) string {
+	branch := project.DetectDefaultBranch(dir)
+	if branch == "" {
+		branch = project.DefaultBranchName
+	}
+	return "origin/" + branch
+}
+
 func init() {
 	branchCleanupCmd.Flags().BoolVar(&branchCleanupDryRun, "dry-run", false, "Show what would be deleted without making changes")
 	branchCleanupCmd.Flags().BoolVar(&branchCleanupLocal, "local", false, "Also delete local branch refs")
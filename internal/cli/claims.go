@@ -48,5 +48,6 @@ func runClaims(cmd *cobra.Command, args []string) error {
 
 func init() {
 	claimsCmd.Flags().StringVarP(&claimsProject, "project", "p", "", "Filter by project name")
+	_ = claimsCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
 	rootCmd.AddCommand(claimsCmd)
 }
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tessro/fab/internal/registry"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish]",
+	Short: "Generate a shell completion script",
+	Long: `Generate a completion script for fab in the given shell.
+
+Project names and agent IDs are completed from the running daemon; with no
+daemon running, only commands and flags are completed.
+
+Bash (requires bash-completion):
+  source <(fab completion bash)
+
+Zsh:
+  fab completion zsh > "${fpath[1]}/_fab"
+
+Fish:
+  fab completion fish > ~/.config/fish/completions/fab.fish`,
+	ValidArgs:             []string{"bash", "zsh", "fish"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	switch args[0] {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(os.Stdout)
+	case "fish":
+		return rootCmd.GenFishCompletion(os.Stdout, true)
+	default:
+		return fmt.Errorf("unsupported shell %q", args[0])
+	}
+}
+
+// completeProject completes a project name as the command's first argument.
+func completeProject(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return projectCompletions(nil), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjects completes project names for every argument, skipping
+// those already given.
+func completeProjects(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return projectCompletions(args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjectFlag completes the value of a --project flag.
+func completeProjectFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return projectCompletions(nil), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjectConfigKey completes a project name, then a config key.
+func completeProjectConfigKey(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return projectCompletions(nil), cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		var keys []string
+		for _, key := range registry.ValidConfigKeys() {
+			keys = append(keys, string(key))
+		}
+		return keys, cobra.ShellCompDirectiveNoFileComp
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeAgent completes an agent ID as the command's first argument.
+func completeAgent(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return agentCompletions(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// projectCompletions returns the daemon's project names, minus those in
+// skip. Returns nil if the daemon isn't reachable.
+func projectCompletions(skip []string) []string {
+	client, err := ConnectClient()
+	if err != nil {
+		return nil
+	}
+	defer client.Close()
+
+	resp, err := client.ProjectList()
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, p := range resp.Projects {
+		if !slices.Contains(skip, p.Name) {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// agentCompletions returns the daemon's agent IDs, each described by its
+// project and what it's working on. Returns nil if the daemon isn't
+// reachable.
+func agentCompletions() []string {
+	client, err := ConnectClient()
+	if err != nil {
+		return nil
+	}
+	defer client.Close()
+
+	resp, err := client.AgentList("")
+	if err != nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(resp.Agents))
+	for _, a := range resp.Agents {
+		desc := a.Project
+		if a.Description != "" {
+			desc += ": " + a.Description
+		} else if a.Task != "" {
+			desc += ": " + a.Task
+		}
+		ids = append(ids, a.ID+"\t"+desc)
+	}
+	return ids
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/tessro/fab/internal/daemon"
+)
+
+func TestCompletions(t *testing.T) {
+	defer SetSocketPath("")
+
+	t.Run("no daemon completes nothing", func(t *testing.T) {
+		SetSocketPath("/nonexistent/path/test.sock")
+		if got, _ := completeProject(projectStartCmd, nil, ""); got != nil {
+			t.Errorf("completeProject() = %v, want nil", got)
+		}
+		if got, _ := completeAgent(agentAbortCmd, nil, ""); got != nil {
+			t.Errorf("completeAgent() = %v, want nil", got)
+		}
+	})
+
+	tmpDir, cleanup := shortTempDir(t)
+	defer cleanup()
+	sockPath := filepath.Join(tmpDir, "test.sock")
+
+	handler := daemon.HandlerFunc(func(ctx context.Context, req *daemon.Request) *daemon.Response {
+		switch req.Type {
+		case daemon.MsgProjectList:
+			return &daemon.Response{Success: true, Payload: daemon.ProjectListResponse{
+				Projects: []daemon.ProjectInfo{{Name: "alpha"}, {Name: "beta"}},
+			}}
+		case daemon.MsgAgentList:
+			return &daemon.Response{Success: true, Payload: daemon.AgentListResponse{
+				Agents: []daemon.AgentStatus{
+					{ID: "a1b2c3", Project: "alpha", Description: "Fix login"},
+					{ID: "d4e5f6", Project: "beta", Task: "42"},
+				},
+			}}
+		}
+		return &daemon.Response{Success: false, Error: "unknown"}
+	})
+
+	srv := daemon.NewServer(sockPath, handler)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("server start: %v", err)
+	}
+	defer func() { _ = srv.Stop() }()
+	SetSocketPath(sockPath)
+
+	t.Run("projects", func(t *testing.T) {
+		if got, _ := completeProject(projectStartCmd, nil, ""); !slices.Equal(got, []string{"alpha", "beta"}) {
+			t.Errorf("completeProject() = %v, want [alpha beta]", got)
+		}
+		if got, _ := completeProject(projectStartCmd, []string{"alpha"}, ""); got != nil {
+			t.Errorf("completeProject() after the project = %v, want nil", got)
+		}
+		if got, _ := completeProjects(attachCmd, []string{"alpha"}, ""); !slices.Equal(got, []string{"beta"}) {
+			t.Errorf("completeProjects() = %v, want [beta]", got)
+		}
+	})
+
+	t.Run("config keys", func(t *testing.T) {
+		got, _ := completeProjectConfigKey(projectConfigSetCmd, []string{"alpha"}, "")
+		if !slices.Contains(got, "max-agents") {
+			t.Errorf("completeProjectConfigKey() = %v, want config keys", got)
+		}
+		if got, _ := completeProjectConfigKey(projectConfigSetCmd, []string{"alpha", "max-agents"}, ""); got != nil {
+			t.Errorf("completeProjectConfigKey() for the value = %v, want nil", got)
+		}
+	})
+
+	t.Run("agents", func(t *testing.T) {
+		want := []string{"a1b2c3\talpha: Fix login", "d4e5f6\tbeta: 42"}
+		if got, _ := completeAgent(agentAbortCmd, nil, ""); !slices.Equal(got, want) {
+			t.Errorf("completeAgent() = %q, want %q", got, want)
+		}
+	})
+}
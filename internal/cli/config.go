@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tessro/fab/internal/config"
+	"github.com/tessro/fab/internal/issue"
+	"github.com/tessro/fab/internal/project"
+	"github.com/tessro/fab/internal/registry"
+)
+
+// configValidateBackendTimeout bounds each issue backend check.
+const configValidateBackendTimeout = 30 * time.Second
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect fab configuration",
+	Long:  "Commands for checking fab's configuration files.",
+}
+
+var configValidateOffline bool
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [project]",
+	Short: "Check project configuration for errors",
+	Long: `Check each project's configuration and confirm its issue backend is reachable.
+
+Reads config files directly, so the daemon does not need to be running.
+Validates every project unless one is named. Exits non-zero if any project fails.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProject,
+	RunE:              runConfigValidate,
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	reg, err := registry.New()
+	if err != nil {
+		return fmt.Errorf("load registry: %w", err)
+	}
+
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("load global config: %w", err)
+	}
+
+	var projects []*project.Project
+	if len(args) == 1 {
+		p, err := reg.Get(args[0])
+		if err != nil {
+			return fmt.Errorf("get project: %w", err)
+		}
+		projects = []*project.Project{p}
+	} else {
+		projects = reg.List()
+	}
+
+	if len(projects) == 0 {
+		fmt.Println("🚌 No projects registered")
+		return nil
+	}
+
+	failed := 0
+	for _, p := range projects {
+		problems, err := reg.ValidateProject(p.Name)
+		if err != nil {
+			return fmt.Errorf("validate %s: %w", p.Name, err)
+		}
+		if !configValidateOffline {
+			if err := checkIssueBackend(cmd.Context(), p, globalCfg); err != nil {
+				problems = append(problems, err)
+			}
+		}
+
+		if len(problems) == 0 {
+			fmt.Printf("🚌 %s: ok\n", p.Name)
+			continue
+		}
+
+		failed++
+		fmt.Printf("🚌 %s: failed\n", p.Name)
+		for _, problem := range problems {
+			fmt.Printf("   %v\n", problem)
+		}
+		fmt.Printf("   Fix with: fab project config set %s <key> <value>\n", p.Name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d projects failed validation", failed, len(projects))
+	}
+	return nil
+}
+
+// checkIssueBackend confirms the project's issue backend can be created and
+// authenticated by listing open issues.
+func checkIssueBackend(ctx context.Context, p *project.Project, globalCfg *config.GlobalConfig) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	backend, err := issueBackendFor(p, globalCfg)
+	if err != nil {
+		return fmt.Errorf("issue backend %s: %w", p.GetIssueBackend(), err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, configValidateBackendTimeout)
+	defer cancel()
+
+	filter := issue.ListFilter{Status: []issue.Status{issue.StatusOpen}}
+	if _, err := backend.List(ctx, filter); err != nil {
+		return fmt.Errorf("issue backend %s: list issues: %w", p.GetIssueBackend(), err)
+	}
+	return nil
+}
+
+func init() {
+	configValidateCmd.Flags().BoolVar(&configValidateOffline, "offline", false, "Skip the issue backend check")
+
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+
+	"github.com/spf13/cobra"
+	"github.com/tessro/fab/internal/config"
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/paths"
+	"github.com/tessro/fab/internal/supervisor"
+	"github.com/tessro/fab/internal/version"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose differences between the CLI and daemon environments",
+	Long: `Compare the user, paths, version, and issue backend credentials the daemon
+runs with against this shell's. Useful when the daemon was started from a
+different shell, such as one with another GITHUB_TOKEN.
+
+Tokens are never printed; each is shown as a short fingerprint so tokens in
+the two environments can be compared. Exits non-zero if problems are found.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	local := localWhoami()
+
+	client, err := ConnectClient()
+	if err != nil {
+		if errors.Is(err, ErrDaemonNotRunning) {
+			fmt.Println("🚌 fab daemon is not running")
+			return nil
+		}
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer client.Close()
+
+	remote, err := client.Whoami()
+	if err != nil {
+		return fmt.Errorf("whoami: %w", err)
+	}
+
+	// Resolve credentials the way the daemon would if started from this shell
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	localAuth := func(backend, host string) (string, string) {
+		return supervisor.ResolveIssueAuth(backend, host, globalCfg)
+	}
+
+	if problems := printDoctorReport(os.Stdout, local, remote, localAuth); problems > 0 {
+		return fmt.Errorf("found %d problem(s)", problems)
+	}
+	return nil
+}
+
+// localWhoami describes the CLI's own environment in the daemon's terms.
+func localWhoami() *daemon.WhoamiResponse {
+	w := &daemon.WhoamiResponse{
+		UID:        os.Getuid(),
+		PID:        os.Getpid(),
+		Version:    version.Version,
+		SocketPath: paths.SocketPath(),
+	}
+	if u, err := user.Current(); err == nil {
+		w.User = u.Username
+	} else {
+		w.User = os.Getenv("USER")
+	}
+	w.Home, _ = os.UserHomeDir()
+	w.ConfigPath, _ = config.GlobalConfigPath()
+	return w
+}
+
+// printDoctorReport compares the CLI's environment (local) with the
+// daemon's (remote) and returns how many problems it found. localAuth
+// resolves an issue backend's token source and fingerprint in this shell,
+// for the GitHub host the daemon resolved.
+// Environment differences are warnings; missing credentials are problems.
+func printDoctorReport(w io.Writer, local, remote *daemon.WhoamiResponse, localAuth func(backend, host string) (source, fingerprint string)) int {
+	_, _ = fmt.Fprintf(w, "🚌 fab daemon (pid %d)\n", remote.PID)
+
+	check := func(label, daemonValue, cliValue string) {
+		if daemonValue == cliValue {
+			_, _ = fmt.Fprintf(w, "   ✓ %-8s %s\n", label, daemonValue)
+			return
+		}
+		_, _ = fmt.Fprintf(w, "   ! %-8s daemon: %s, cli: %s\n", label, daemonValue, cliValue)
+	}
+	check("user", fmt.Sprintf("%s (uid %d)", remote.User, remote.UID), fmt.Sprintf("%s (uid %d)", local.User, local.UID))
+	check("home", remote.Home, local.Home)
+	check("config", remote.ConfigPath, local.ConfigPath)
+	check("socket", remote.SocketPath, local.SocketPath)
+	check("version", remote.Version, local.Version)
+
+	if len(remote.IssueAuth) == 0 {
+		return 0
+	}
+
+	problems := 0
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Issue backends:")
+	for _, a := range remote.IssueAuth {
+		if !a.OK {
+			problems++
+			_, _ = fmt.Fprintf(w, "   ✗ %s (%s): %s\n", a.Project, a.Backend, a.Error)
+		} else if a.Source == "" {
+			_, _ = fmt.Fprintf(w, "   ✓ %s (%s)\n", a.Project, a.Backend)
+		} else {
+			_, _ = fmt.Fprintf(w, "   ✓ %s (%s): %s %s\n", a.Project, a.Backend, a.Source, a.Fingerprint)
+		}
+
+		source, fingerprint := localAuth(a.Backend, a.Host)
+		if fingerprint != a.Fingerprint {
+			cli := "no token"
+			if source != "" {
+				cli = source + " " + fingerprint
+			}
+			_, _ = fmt.Fprintf(w, "     ! this shell would use %s\n", cli)
+		}
+	}
+	return problems
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
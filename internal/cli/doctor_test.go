@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tessro/fab/internal/daemon"
+)
+
+func TestPrintDoctorReport(t *testing.T) {
+	local := &daemon.WhoamiResponse{User: "alice", UID: 501, Home: "/home/alice", ConfigPath: "/home/alice/.config/fab/config.toml", SocketPath: "/home/alice/.fab/fab.sock", Version: "1.2.0"}
+	remote := *local
+	remote.PID = 42
+	remote.Version = "1.1.0"
+	remote.IssueAuth = []daemon.IssueAuthStatus{
+		{Project: "app", Backend: "github", Host: "github.com", Source: "GITHUB_TOKEN", Fingerprint: "aaaaaaaa", OK: true},
+		{Project: "svc", Backend: "linear", OK: false, Error: "LINEAR_API_KEY not set in config or environment"},
+		{Project: "tix", Backend: "tk", OK: true},
+	}
+	localAuth := func(backend, host string) (string, string) {
+		if backend == "github" && host == "github.com" {
+			return "GH_TOKEN", "bbbbbbbb"
+		}
+		return "", ""
+	}
+
+	var buf bytes.Buffer
+	problems := printDoctorReport(&buf, local, &remote, localAuth)
+	out := buf.String()
+
+	if problems != 1 {
+		t.Errorf("problems = %d, want 1 (missing Linear key)", problems)
+	}
+	for _, want := range []string{
+		"✓ user     alice (uid 501)",
+		"! version  daemon: 1.1.0, cli: 1.2.0",
+		"✓ app (github): GITHUB_TOKEN aaaaaaaa",
+		"! this shell would use GH_TOKEN bbbbbbbb",
+		"✗ svc (linear): LINEAR_API_KEY not set",
+		"✓ tix (tk)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "this shell would use") != 1 {
+		t.Errorf("only the mismatched token should be flagged:\n%s", out)
+	}
+}
@@ -174,6 +174,19 @@ func runHook(cmd *cobra.Command, args []string) error {
 		return handleAskUserQuestion(hookName, hookInput)
 	}
 
+	ctx := context.Background()
+
+	// The agent's role restricts it before any other rule can allow
+	if role := os.Getenv(rules.RoleEnvVar); role != "" {
+		action, _, err := rules.EvaluateRole(ctx, role, hookInput.ToolName, hookInput.ToolInput, hookInput.Cwd)
+		if err != nil {
+			slog.Debug("role evaluation error", "role", role, "error", err)
+		}
+		if action == rules.ActionDeny {
+			return outputHookResponse(hookName, "deny", "blocked: "+role+" agents are read-only", false)
+		}
+	}
+
 	// Evaluate permission rules before contacting daemon
 	evaluator := rules.NewEvaluator()
 
@@ -183,7 +196,6 @@ func runHook(cmd *cobra.Command, args []string) error {
 		slog.Debug("failed to find project name", "cwd", hookInput.Cwd, "error", err)
 	}
 
-	ctx := context.Background()
 	action, matched, err := evaluator.Evaluate(ctx, projectName, hookInput.ToolName, hookInput.ToolInput, hookInput.Cwd)
 	if err != nil {
 		slog.Debug("rule evaluation error", "error", err)
@@ -216,13 +228,13 @@ func runHook(cmd *cobra.Command, args []string) error {
 		"input", string(hookInput.ToolInput),
 	)
 
-	// Send permission request to daemon and wait for response
-	resp, err := client.RequestPermission(&daemon.PermissionRequestPayload{
+	// Send permission request to daemon and wait as long as it will for the tool
+	resp, err := client.RequestPermissionWithTimeout(&daemon.PermissionRequestPayload{
 		AgentID:   agentID,
 		ToolName:  hookInput.ToolName,
 		ToolInput: hookInput.ToolInput,
 		ToolUseID: hookInput.ToolUseID,
-	})
+	}, cfg.GetPermissionTimeout(hookInput.ToolName))
 	if err != nil {
 		slog.Warn("permission request failed",
 			"agent", agentID,
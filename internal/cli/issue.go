@@ -14,6 +14,7 @@ import (
 	"github.com/tessro/fab/internal/issue/gh"
 	"github.com/tessro/fab/internal/issue/linear"
 	"github.com/tessro/fab/internal/issue/tk"
+	"github.com/tessro/fab/internal/project"
 	"github.com/tessro/fab/internal/registry"
 )
 
@@ -330,7 +331,7 @@ var issueCommentBody string
 var issueCommentCmd = &cobra.Command{
 	Use:   "comment <id>",
 	Short: "Add a comment to an issue",
-	Long:  "Add a comment to an issue. The comment body can be provided via --body flag.",
+	Long:  "Add a comment to an issue. The comment body can be provided via --body flag. Use 'fab issue commit' to push changes.",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runIssueComment,
 }
@@ -368,7 +369,7 @@ var (
 var issuePlanCmd = &cobra.Command{
 	Use:   "plan <id>",
 	Short: "Upsert a plan section in an issue",
-	Long:  "Update or create a ## Plan section in the issue body. The plan content can be provided via --body or --file flag.",
+	Long:  "Update or create a ## Plan section in the issue body. The plan content can be provided via --body or --file flag. Use 'fab issue commit' to push changes.",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runIssuePlan,
 }
@@ -426,37 +427,32 @@ func getIssueBackend() (issue.Backend, error) {
 		return nil, fmt.Errorf("get project: %w", err)
 	}
 
-	// Default to tk backend
-	backendType := project.IssueBackend
-	if backendType == "" {
-		backendType = "tk"
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load global config: %w", err)
 	}
 
-	switch backendType {
+	return issueBackendFor(project, globalCfg)
+}
+
+// issueBackendFor creates the issue backend configured for a project,
+// taking API keys from the global config.
+func issueBackendFor(p *project.Project, globalCfg *config.GlobalConfig) (issue.Backend, error) {
+	switch backendType := p.GetIssueBackend(); backendType {
 	case "tk":
-		return tk.New(project.RepoDir())
+		return tk.New(p.RepoDir())
 	case "github", "gh":
-		// Load global config to get GitHub API key
-		globalCfg, err := config.LoadGlobalConfig()
-		if err != nil {
-			return nil, fmt.Errorf("load global config: %w", err)
-		}
 		apiKey := ""
 		if globalCfg != nil {
 			apiKey = globalCfg.GetAPIKey("github")
 		}
-		return gh.New(project.RepoDir(), project.AllowedAuthors, apiKey)
+		return gh.New(p.RepoDir(), p.GitHubHost, p.AllowedAuthors, p.GetGitHubIssueLimit(), apiKey)
 	case "linear":
-		// Load global config to get Linear API key
-		globalCfg, err := config.LoadGlobalConfig()
-		if err != nil {
-			return nil, fmt.Errorf("load global config: %w", err)
-		}
 		apiKey := ""
 		if globalCfg != nil {
 			apiKey = globalCfg.GetAPIKey("linear")
 		}
-		return linear.New(project.RepoDir(), project.LinearTeam, project.LinearProject, project.AllowedAuthors, apiKey)
+		return linear.New(p.RepoDir(), p.LinearTeam, p.LinearProject, p.AllowedAuthors, apiKey)
 	default:
 		return nil, fmt.Errorf("unknown issue backend: %s", backendType)
 	}
@@ -465,6 +461,7 @@ func getIssueBackend() (issue.Backend, error) {
 func init() {
 	// Parent command flag
 	issueCmd.PersistentFlags().StringVarP(&issueProject, "project", "p", "", "Project name (default: detect from cwd)")
+	_ = issueCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
 
 	// list flags
 	issueListCmd.Flags().StringVarP(&issueListStatus, "status", "s", "", "Filter by status (open, closed, blocked)")
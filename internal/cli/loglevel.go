@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var loglevelCmd = &cobra.Command{
+	Use:   "loglevel [debug|info|warn|error]",
+	Short: "Show or change the daemon's log level",
+	Long: `Show or change the daemon's log level without restarting it.
+
+The change lasts until the daemon restarts, which goes back to log-level
+from config.toml. With no argument, print the current level.`,
+	Example: `  fab loglevel debug   # Verbose logs while reproducing a problem
+  fab loglevel info    # Back to normal`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"debug", "info", "warn", "error"},
+	RunE:      runLoglevel,
+}
+
+func runLoglevel(cmd *cobra.Command, args []string) error {
+	client := MustConnect()
+	defer client.Close()
+
+	var level string
+	if len(args) > 0 {
+		level = args[0]
+	}
+	resp, err := client.SetLogLevel(level)
+	if err != nil {
+		return fmt.Errorf("set log level: %w", err)
+	}
+
+	switch {
+	case level == "":
+		fmt.Printf("Log level: %s\n", resp.Level)
+	case resp.Level == resp.Previous:
+		fmt.Printf("Log level already %s\n", resp.Level)
+	default:
+		fmt.Printf("Log level changed from %s to %s\n", resp.Previous, resp.Level)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(loglevelCmd)
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tessro/fab/internal/daemon"
+)
+
+var logsLevel string
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Stream daemon logs",
+	Long:  "Connect to the daemon and print its log records as they are written. Use --level to include more or less detail.",
+	RunE:  runLogs,
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	switch strings.ToLower(logsLevel) {
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("invalid level %q: must be debug, info, warn, or error", logsLevel)
+	}
+
+	client := MustConnect()
+	defer client.Close()
+
+	events, err := client.StreamLogs(logsLevel)
+	if err != nil {
+		return fmt.Errorf("subscribe to logs: %w", err)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-sigCh:
+			return nil
+		case result, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if result.Err != nil {
+				return fmt.Errorf("receive log: %w", result.Err)
+			}
+			if result.Event.Type == "log" && result.Event.Log != nil {
+				fmt.Println(formatLogRecord(result.Event.Log))
+			}
+		}
+	}
+}
+
+// formatLogRecord renders a log record as a single human-readable line.
+func formatLogRecord(rec *daemon.LogRecordDTO) string {
+	var b strings.Builder
+
+	ts := rec.Time
+	if t, err := time.Parse(time.RFC3339, rec.Time); err == nil {
+		ts = t.Local().Format("15:04:05")
+	}
+	fmt.Fprintf(&b, "%s %-5s %s", ts, rec.Level, rec.Message)
+
+	keys := make([]string, 0, len(rec.Attrs))
+	for k := range rec.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := rec.Attrs[k]
+		if strings.ContainsAny(v, " \t\n\"") {
+			v = fmt.Sprintf("%q", v)
+		}
+		fmt.Fprintf(&b, " %s=%s", k, v)
+	}
+
+	return b.String()
+}
+
+func init() {
+	logsCmd.Flags().StringVarP(&logsLevel, "level", "l", "info", "Minimum level to show (debug, info, warn, error)")
+	rootCmd.AddCommand(logsCmd)
+}
@@ -2,8 +2,22 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tessro/fab/internal/daemon"
+)
+
+// DefaultManagerAskTimeout is how long 'fab manager ask' waits for an answer by default.
+const DefaultManagerAskTimeout = 10 * time.Minute
+
+var (
+	managerAskProject string
+	managerAskTimeout time.Duration
 )
 
 var managerCmd = &cobra.Command{
@@ -13,9 +27,10 @@ var managerCmd = &cobra.Command{
 }
 
 var managerStartCmd = &cobra.Command{
-	Use:   "start <project>",
-	Short: "Start the manager agent for a project",
-	Args:  cobra.ExactArgs(1),
+	Use:               "start <project>",
+	Short:             "Start the manager agent for a project",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProject,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		project := args[0]
 
@@ -32,9 +47,10 @@ var managerStartCmd = &cobra.Command{
 }
 
 var managerStopCmd = &cobra.Command{
-	Use:   "stop <project>",
-	Short: "Stop the manager agent for a project",
-	Args:  cobra.ExactArgs(1),
+	Use:               "stop <project>",
+	Short:             "Stop the manager agent for a project",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProject,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		project := args[0]
 
@@ -51,9 +67,10 @@ var managerStopCmd = &cobra.Command{
 }
 
 var managerStatusCmd = &cobra.Command{
-	Use:   "status <project>",
-	Short: "Show manager agent status for a project",
-	Args:  cobra.ExactArgs(1),
+	Use:               "status <project>",
+	Short:             "Show manager agent status for a project",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProject,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		project := args[0]
 
@@ -76,10 +93,11 @@ var managerStatusCmd = &cobra.Command{
 }
 
 var managerClearCmd = &cobra.Command{
-	Use:   "clear <project>",
-	Short: "Clear the manager agent's context window for a project",
-	Long:  "Clears the manager agent's chat history. The manager will lose all conversation context but remain running.",
-	Args:  cobra.ExactArgs(1),
+	Use:               "clear <project>",
+	Short:             "Clear the manager agent's context window for a project",
+	Long:              "Clears the manager agent's chat history. The manager will lose all conversation context but remain running.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProject,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		project := args[0]
 
@@ -95,10 +113,149 @@ var managerClearCmd = &cobra.Command{
 	},
 }
 
+var managerAskCmd = &cobra.Command{
+	Use:   "ask <question>",
+	Short: "Ask the manager agent a question and print its answer",
+	Long: `Send a one-shot question to a project's manager agent and print the
+answer to stdout as it streams in. Starts the manager if it isn't running
+and leaves it running afterwards. Progress messages go to stderr.
+
+Exits non-zero if the manager's turn fails, the manager stops before
+answering, or no answer arrives within --timeout.
+
+Examples:
+  fab manager ask --project myapp "what's blocking the release?"
+  fab manager ask -p myapp "summarize open bugs" > bugs.md
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: runManagerAsk,
+}
+
+func runManagerAsk(cmd *cobra.Command, args []string) error {
+	project := managerAskProject
+
+	client := MustConnect()
+	defer client.Close()
+
+	// Subscribe before starting so no manager events can be missed
+	events, err := client.StreamEvents([]string{project})
+	if err != nil {
+		return fmt.Errorf("stream events: %w", err)
+	}
+	defer client.StopEventStream()
+
+	status, err := client.ManagerStatus(project)
+	if err != nil {
+		return fmt.Errorf("get status: %w", err)
+	}
+	if !status.Running {
+		if err := client.ManagerStart(project); err != nil {
+			return fmt.Errorf("start manager: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "🚌 Started manager agent for %s\n", project)
+
+		// A fresh manager answers its system prompt first; wait for that
+		// turn so it isn't mistaken for the answer
+		if err := waitForManagerTurn(events, project, managerAskTimeout, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := client.ManagerSendMessage(project, args[0]); err != nil {
+		return fmt.Errorf("send message: %w", err)
+	}
+
+	wrote := false
+	err = waitForManagerTurn(events, project, managerAskTimeout, func(entry *daemon.ChatEntryDTO) {
+		if entry.Role != "assistant" || strings.TrimSpace(entry.Content) == "" {
+			return
+		}
+		if wrote {
+			fmt.Println()
+		}
+		fmt.Println(strings.TrimSpace(entry.Content))
+		wrote = true
+	})
+	if err != nil {
+		return err
+	}
+	if !wrote {
+		fmt.Fprintln(os.Stderr, "🚌 Manager finished without a text answer")
+	}
+	return nil
+}
+
+// waitForManagerTurn blocks until the project's manager finishes a turn,
+// passing each chat entry to onEntry (if non-nil). It fails if the turn
+// ends with an error, the manager stops mid-turn, the timeout elapses, or
+// the user interrupts.
+func waitForManagerTurn(events <-chan daemon.EventResult, project string, timeout time.Duration, onEntry func(*daemon.ChatEntryDTO)) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	// A "stopped" state from a previous turn can arrive late, so only
+	// treat it as final once this turn has produced something
+	active := false
+
+	for {
+		select {
+		case <-timer.C:
+			return fmt.Errorf("manager for %s did not answer within %s", project, timeout)
+
+		case <-sigCh:
+			return fmt.Errorf("interrupted waiting for manager %s", project)
+
+		case result, ok := <-events:
+			if !ok {
+				return fmt.Errorf("event stream closed before manager %s answered", project)
+			}
+			if result.Err != nil {
+				return fmt.Errorf("receive event: %w", result.Err)
+			}
+
+			event := result.Event
+			if event.Project != project {
+				continue
+			}
+			switch event.Type {
+			case "manager_idle":
+				if event.Error != "" {
+					return fmt.Errorf("manager for %s: %s", project, event.Error)
+				}
+				return nil
+			case "manager_chat_entry":
+				active = true
+				if onEntry != nil && event.ChatEntry != nil {
+					onEntry(event.ChatEntry)
+				}
+			case "manager_state":
+				switch event.ManagerState {
+				case "starting", "running":
+					active = true
+				case "stopped":
+					if active {
+						return fmt.Errorf("manager for %s stopped before answering", project)
+					}
+				}
+			}
+		}
+	}
+}
+
 func init() {
+	managerAskCmd.Flags().StringVarP(&managerAskProject, "project", "p", "", "Project whose manager to ask (required)")
+	_ = managerAskCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
+	managerAskCmd.Flags().DurationVar(&managerAskTimeout, "timeout", DefaultManagerAskTimeout, "How long to wait for an answer")
+	_ = managerAskCmd.MarkFlagRequired("project")
+
 	rootCmd.AddCommand(managerCmd)
 	managerCmd.AddCommand(managerStartCmd)
 	managerCmd.AddCommand(managerStopCmd)
 	managerCmd.AddCommand(managerStatusCmd)
 	managerCmd.AddCommand(managerClearCmd)
+	managerCmd.AddCommand(managerAskCmd)
 }
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tessro/fab/internal/daemon"
+)
+
+func TestWaitForManagerTurn(t *testing.T) {
+	tests := []struct {
+		name        string
+		events      []daemon.StreamEvent
+		wantEntries []string
+		wantErr     string
+	}{
+		{
+			name: "completes on idle",
+			events: []daemon.StreamEvent{
+				{Type: "manager_chat_entry", Project: "other", ChatEntry: &daemon.ChatEntryDTO{Role: "assistant", Content: "wrong project"}},
+				{Type: "manager_chat_entry", Project: "app", ChatEntry: &daemon.ChatEntryDTO{Role: "assistant", Content: "answer"}},
+				{Type: "manager_idle", Project: "other"},
+				{Type: "manager_idle", Project: "app"},
+				{Type: "manager_chat_entry", Project: "app", ChatEntry: &daemon.ChatEntryDTO{Role: "assistant", Content: "next turn"}},
+			},
+			wantEntries: []string{"answer"},
+		},
+		{
+			name:    "reports turn error",
+			events:  []daemon.StreamEvent{{Type: "manager_idle", Project: "app", Error: "manager turn ended with an error"}},
+			wantErr: "ended with an error",
+		},
+		{
+			name: "ignores stale stop",
+			events: []daemon.StreamEvent{
+				{Type: "manager_state", Project: "app", ManagerState: "stopped"},
+				{Type: "manager_idle", Project: "app"},
+			},
+		},
+		{
+			name: "stops mid-turn",
+			events: []daemon.StreamEvent{
+				{Type: "manager_state", Project: "app", ManagerState: "running"},
+				{Type: "manager_state", Project: "app", ManagerState: "stopped"},
+			},
+			wantErr: "stopped before answering",
+		},
+		{
+			name:    "times out",
+			events:  []daemon.StreamEvent{{Type: "manager_idle", Project: "other"}},
+			wantErr: "did not answer within",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := make(chan daemon.EventResult, len(tt.events))
+			for i := range tt.events {
+				events <- daemon.EventResult{Event: &tt.events[i]}
+			}
+
+			var entries []string
+			err := waitForManagerTurn(events, "app", 50*time.Millisecond, func(e *daemon.ChatEntryDTO) {
+				entries = append(entries, e.Content)
+			})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("waitForManagerTurn() error = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("waitForManagerTurn() error = %v, want containing %q", err, tt.wantErr)
+			}
+			if strings.Join(entries, "|") != strings.Join(tt.wantEntries, "|") {
+				t.Errorf("entries = %q, want %q", entries, tt.wantEntries)
+			}
+		})
+	}
+}
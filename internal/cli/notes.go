@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var notesProject string
+
+var notesCmd = &cobra.Command{
+	Use:   "notes",
+	Short: "Show a project's shared notes",
+	Long: `Show the notes agents have shared about a project (NOTES.md in the
+project directory). Inside an agent, the project defaults to the agent's own.`,
+	Args: cobra.NoArgs,
+	RunE: runNotes,
+}
+
+func runNotes(cmd *cobra.Command, args []string) error {
+	agentID := os.Getenv("FAB_AGENT_ID")
+	if notesProject == "" && agentID == "" {
+		return fmt.Errorf("--project is required outside an agent")
+	}
+
+	client := MustConnect()
+	defer client.Close()
+
+	resp, err := client.NoteRead(agentID, notesProject)
+	if err != nil {
+		return fmt.Errorf("read notes: %w", err)
+	}
+
+	if resp.Content == "" {
+		fmt.Printf("No notes for project %q\n", resp.Project)
+		return nil
+	}
+	if resp.Truncated {
+		fmt.Printf("(showing the most recent notes; see %s for all)\n\n", resp.Path)
+	}
+	fmt.Print(resp.Content)
+	return nil
+}
+
+var notesAddCmd = &cobra.Command{
+	Use:   "add <text>",
+	Short: "Add a note to a project's shared notes",
+	Long: `Append a note to the project's shared notes, so other agents can reuse
+findings instead of repeating the investigation. Inside an agent, the note
+goes to the agent's project and names the agent as its author.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runNotesAdd,
+}
+
+func runNotesAdd(cmd *cobra.Command, args []string) error {
+	agentID := os.Getenv("FAB_AGENT_ID")
+	if notesProject == "" && agentID == "" {
+		return fmt.Errorf("--project is required outside an agent")
+	}
+
+	client := MustConnect()
+	defer client.Close()
+
+	if err := client.NoteAppend(agentID, notesProject, strings.Join(args, " ")); err != nil {
+		return fmt.Errorf("add note: %w", err)
+	}
+
+	fmt.Println("🚌 Note added")
+	return nil
+}
+
+func init() {
+	notesCmd.PersistentFlags().StringVarP(&notesProject, "project", "p", "", "Project name (default: the agent's project)")
+	_ = notesCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
+	notesCmd.AddCommand(notesAddCmd)
+	rootCmd.AddCommand(notesCmd)
+}
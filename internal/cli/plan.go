@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"text/tabwriter"
@@ -113,15 +115,20 @@ func runPlanRead(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var planListJSON bool
+
 var planListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List stored plans",
 	Long: `List all stored plans with their IDs and timestamps.
 
-Plans are stored in ~/.fab/plans/ (or $FAB_DIR/plans/).
+Plans are stored in ~/.fab/plans/ (or $FAB_DIR/plans/). To list only the
+planners of one project that have finished, use
+'fab agent plan list --project <name> --completed'.
 
 Examples:
   fab plan list
+  fab plan list --json
 `,
 	RunE: runPlanList,
 }
@@ -133,11 +140,7 @@ func runPlanList(cmd *cobra.Command, args []string) error {
 	}
 
 	entries, err := os.ReadDir(plansDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			fmt.Println("No stored plans")
-			return nil
-		}
+	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("read plans directory: %w", err)
 	}
 
@@ -169,16 +172,34 @@ func runPlanList(cmd *cobra.Command, args []string) error {
 		})
 	}
 
-	if len(plans) == 0 {
-		fmt.Println("No stored plans")
-		return nil
-	}
-
 	// Sort by modification time (newest first)
 	sort.Slice(plans, func(i, j int) bool {
 		return plans[i].modTime.After(plans[j].modTime)
 	})
 
+	if planListJSON {
+		type planJSON struct {
+			ID       string    `json:"id"`
+			Path     string    `json:"path"`
+			Modified time.Time `json:"modified"`
+		}
+		out := make([]planJSON, 0, len(plans))
+		for _, p := range plans {
+			out = append(out, planJSON{ID: p.id, Path: filepath.Join(plansDir, p.id+".md"), Modified: p.modTime})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return fmt.Errorf("encode plans: %w", err)
+		}
+		return nil
+	}
+
+	if len(plans) == 0 {
+		fmt.Println("No stored plans")
+		return nil
+	}
+
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	_, _ = fmt.Fprintln(w, "ID\tMODIFIED")
 
@@ -193,6 +214,7 @@ func runPlanList(cmd *cobra.Command, args []string) error {
 func init() {
 	planCmd.AddCommand(planWriteCmd)
 	planCmd.AddCommand(planReadCmd)
+	planListCmd.Flags().BoolVar(&planListJSON, "json", false, "Print plans as JSON")
 	planCmd.AddCommand(planListCmd)
 
 	rootCmd.AddCommand(planCmd)
@@ -2,13 +2,19 @@ package cli
 
 import (
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tessro/fab/internal/daemon"
 )
 
 var projectCmd = &cobra.Command{
@@ -21,11 +27,15 @@ var projectAddName string
 var projectAddMaxAgents int
 var projectAddAutostart bool
 var projectAddBackend string
+var projectAddPreset string
+var projectAddConfig []string
+var projectAddDepth int
+var projectAddSingleBranch bool
 
 var projectAddCmd = &cobra.Command{
 	Use:   "add <path|url|owner/repo>",
 	Short: "Add a project to fab",
-	Long:  "Register a project with the fab daemon for agent orchestration.\n\nAccepts a local path, git URL, or GitHub owner/repo shorthand (e.g., tessro/fab).",
+	Long:  "Register a project with the fab daemon for agent orchestration.\n\nAccepts a local path, git URL, or GitHub owner/repo shorthand (e.g., tessro/fab).\n\nUse --preset to apply a bundle of config values from [presets.<name>] in the\nglobal config, and --config key=value to set individual values. Inline values\noverride the preset, and flags given explicitly override both.\n\nFor large repositories, --depth and --single-branch make the initial clone\nfaster. The full history is fetched before the project's first merge.",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runProjectAdd,
 }
@@ -41,21 +51,23 @@ var projectListCmd = &cobra.Command{
 var projectStartAll bool
 
 var projectStartCmd = &cobra.Command{
-	Use:   "start [project]",
-	Short: "Start orchestration for a project",
-	Long:  "Start agent orchestration for a registered project. Agents will pick up tasks and work on them.",
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runProjectStart,
+	Use:               "start [project]",
+	Short:             "Start orchestration for a project",
+	Long:              "Start agent orchestration for a registered project. Agents will pick up tasks and work on them.",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProject,
+	RunE:              runProjectStart,
 }
 
 var projectStopAll bool
 
 var projectStopCmd = &cobra.Command{
-	Use:   "stop [project]",
-	Short: "Stop orchestration for a project",
-	Long:  "Stop agent orchestration for the specified project. Running agents will be gracefully stopped.",
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runProjectStop,
+	Use:               "stop [project]",
+	Short:             "Stop orchestration for a project",
+	Long:              "Stop agent orchestration for the specified project. Running agents will be gracefully stopped.",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProject,
+	RunE:              runProjectStop,
 }
 
 var projectRemoveForce bool
@@ -64,9 +76,44 @@ var projectRemoveDeleteWorktrees bool
 var projectRemoveCmd = &cobra.Command{
 	Use:   "remove <name>",
 	Short: "Remove a project from fab",
-	Long:  "Unregister a project from the fab daemon. Optionally delete associated worktrees.",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runProjectRemove,
+	Long: `Unregister a project from the fab daemon. Optionally delete associated worktrees.
+
+A running project is refused; stop it first. With --force, the daemon stops
+it for you: agents are asked to exit and any merges in progress finish
+before the project is removed.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProject,
+	RunE:              runProjectRemove,
+}
+
+var projectPullCmd = &cobra.Command{
+	Use:               "pull <name>",
+	Short:             "Refresh a project's base repository",
+	Long:              "Fetch origin and fast-forward the default branch in the project's main clone, so new agents branch from current code.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProject,
+	RunE:              runProjectPull,
+}
+
+var (
+	projectLogLimit int
+	projectLogSince time.Duration
+	projectLogUntil time.Duration
+	projectLogFetch bool
+)
+
+var projectLogCmd = &cobra.Command{
+	Use:   "log <name>",
+	Short: "Show recent commits on a project's default branch",
+	Long: `Show recent commits on the project's default branch as of origin, marking
+the ones fab agents merged with the agent and issue. Use --fetch to include
+commits pushed since the daemon last fetched. Use --since and --until to
+limit the output to commits that landed in a time window.`,
+	Example: `  fab project log myapp --since 8h       # What landed today
+  fab project log myapp --since 48h --until 24h`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProject,
+	RunE:              runProjectLog,
 }
 
 var projectConfigCmd = &cobra.Command{
@@ -76,27 +123,30 @@ var projectConfigCmd = &cobra.Command{
 }
 
 var projectConfigShowCmd = &cobra.Command{
-	Use:   "show <project>",
-	Short: "Show all configuration for a project",
-	Long:  "Display all configuration settings for a registered project.",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runProjectConfigShow,
+	Use:               "show <project>",
+	Short:             "Show all configuration for a project",
+	Long:              "Display all configuration settings for a registered project.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProject,
+	RunE:              runProjectConfigShow,
 }
 
 var projectConfigGetCmd = &cobra.Command{
-	Use:   "get <project> <key>",
-	Short: "Get a configuration value",
-	Long:  "Get a single configuration value for a project.\n\nValid keys: max-agents, autostart, issue-backend, permissions-checker, agent-backend, planner-backend, coding-backend",
-	Args:  cobra.ExactArgs(2),
-	RunE:  runProjectConfigGet,
+	Use:               "get <project> <key>",
+	Short:             "Get a configuration value",
+	Long:              "Get a single configuration value for a project.\n\nValid keys: max-agents, autostart, issue-backend, permissions-checker, agent-backend, planner-backend, coding-backend",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeProjectConfigKey,
+	RunE:              runProjectConfigGet,
 }
 
 var projectConfigSetCmd = &cobra.Command{
-	Use:   "set <project> <key> <value>",
-	Short: "Set a configuration value",
-	Long:  "Set a single configuration value for a project.\n\nValid keys:\n  max-agents           Maximum concurrent agents (1-100)\n  autostart            Start orchestration when daemon starts (true/false)\n  issue-backend        Issue backend type (tk/gh/github)\n  permissions-checker  Permission authorization method (manual/llm)\n  agent-backend        Agent CLI backend fallback (claude/codex)\n  planner-backend      Planning agent CLI backend (claude/codex)\n  coding-backend       Coding agent CLI backend (claude/codex)",
-	Args:  cobra.ExactArgs(3),
-	RunE:  runProjectConfigSet,
+	Use:               "set <project> <key> <value>",
+	Short:             "Set a configuration value",
+	Long:              "Set a single configuration value for a project.\n\nValid keys:\n  max-agents           Maximum concurrent agents (1-100)\n  autostart            Start orchestration when daemon starts (true/false)\n  issue-backend        Issue backend type (tk/gh/github)\n  permissions-checker  Permission authorization method (manual/llm)\n  agent-backend        Agent CLI backend fallback (claude/codex)\n  planner-backend      Planning agent CLI backend (claude/codex)\n  coding-backend       Coding agent CLI backend (claude/codex)",
+	Args:              cobra.ExactArgs(3),
+	ValidArgsFunction: completeProjectConfigKey,
+	RunE:              runProjectConfigSet,
 }
 
 func runProjectAdd(cmd *cobra.Command, args []string) error {
@@ -121,10 +171,42 @@ func runProjectAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("path does not exist: %s", input)
 	}
 
+	configValues, err := parseConfigAssignments(projectAddConfig)
+	if err != nil {
+		return err
+	}
+	// Values applied after creation would override these flags, so pass
+	// explicitly given ones along as config too
+	if len(configValues) > 0 || projectAddPreset != "" {
+		if configValues == nil {
+			configValues = make(map[string]string)
+		}
+		flags := cmd.Flags()
+		if flags.Changed("max-agents") {
+			configValues["max-agents"] = strconv.Itoa(projectAddMaxAgents)
+		}
+		if flags.Changed("autostart") {
+			configValues["autostart"] = strconv.FormatBool(projectAddAutostart)
+		}
+		if flags.Changed("backend") {
+			configValues["agent-backend"] = projectAddBackend
+		}
+	}
+
 	client := MustConnect()
 	defer client.Close()
 
-	result, err := client.ProjectAdd(remoteURL, projectAddName, projectAddMaxAgents, projectAddAutostart, projectAddBackend)
+	result, err := client.ProjectAddWithConfig(daemon.ProjectAddRequest{
+		RemoteURL:    remoteURL,
+		Name:         projectAddName,
+		MaxAgents:    projectAddMaxAgents,
+		Autostart:    projectAddAutostart,
+		Backend:      projectAddBackend,
+		Preset:       projectAddPreset,
+		Config:       configValues,
+		Depth:        projectAddDepth,
+		SingleBranch: projectAddSingleBranch,
+	})
 	if err != nil {
 		return fmt.Errorf("add project: %w", err)
 	}
@@ -139,10 +221,33 @@ func runProjectAdd(cmd *cobra.Command, args []string) error {
 	if projectAddBackend != "" {
 		fmt.Printf("   Backend: %s\n", projectAddBackend)
 	}
+	if projectAddPreset != "" {
+		fmt.Printf("   Preset: %s\n", projectAddPreset)
+	}
+	for _, key := range slices.Sorted(maps.Keys(result.Config)) {
+		fmt.Printf("   %s = %s\n", key, result.Config[key])
+	}
 
 	return nil
 }
 
+// parseConfigAssignments parses key=value arguments into a map.
+func parseConfigAssignments(assignments []string) (map[string]string, error) {
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]string, len(assignments))
+	for _, a := range assignments {
+		key, value, ok := strings.Cut(a, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid config %q: expected key=value", a)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
 // isGitURL returns true if the string looks like a git URL.
 func isGitURL(s string) bool {
 	return strings.Contains(s, "://") || strings.HasPrefix(s, "git@")
@@ -244,14 +349,33 @@ func runProjectStart(cmd *cobra.Command, args []string) error {
 		project = args[0]
 	}
 
-	if err := client.Start(project, projectStartAll); err != nil {
+	resp, err := client.Start(project, projectStartAll)
+	if err != nil {
 		return fmt.Errorf("start: %w", err)
 	}
 
-	if projectStartAll {
-		fmt.Println("🚌 Started orchestration for all projects")
-	} else {
+	if !projectStartAll {
 		fmt.Printf("🚌 Started orchestration for project: %s\n", project)
+		return nil
+	}
+
+	var failed int
+	for _, r := range resp.Results {
+		if !r.Started {
+			failed++
+		}
+	}
+	fmt.Printf("🚌 Started orchestration for %d of %d projects\n", len(resp.Results)-failed, len(resp.Results))
+	for _, r := range resp.Results {
+		if r.Started {
+			fmt.Printf("   ✓ %s\n", r.Project)
+		} else {
+			fmt.Printf("   ✗ %s: %s\n", r.Project, r.Error)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d project(s) failed to start", failed)
 	}
 	return nil
 }
@@ -269,14 +393,23 @@ func runProjectStop(cmd *cobra.Command, args []string) error {
 		project = args[0]
 	}
 
-	if err := client.Stop(project, projectStopAll); err != nil {
+	resp, err := client.Stop(project, projectStopAll)
+	if err != nil {
 		return fmt.Errorf("stop: %w", err)
 	}
 
-	if projectStopAll {
-		fmt.Println("🚌 Stopped orchestration for all projects")
-	} else {
+	if !projectStopAll {
 		fmt.Printf("🚌 Stopped orchestration for project: %s\n", project)
+		return nil
+	}
+
+	fmt.Println("🚌 Stopped orchestration for all projects")
+	for _, r := range resp.Results {
+		if r.Stopped {
+			fmt.Printf("   ✓ %s\n", r.Project)
+		} else {
+			fmt.Printf("   - %s (not running)\n", r.Project)
+		}
 	}
 	return nil
 }
@@ -333,7 +466,7 @@ func runProjectRemove(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if err := client.ProjectRemove(projectName, projectRemoveDeleteWorktrees); err != nil {
+	if err := client.ProjectRemove(projectName, projectRemoveDeleteWorktrees, projectRemoveForce); err != nil {
 		return fmt.Errorf("remove project: %w", err)
 	}
 
@@ -344,6 +477,76 @@ func runProjectRemove(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runProjectPull(cmd *cobra.Command, args []string) error {
+	projectName := args[0]
+
+	client := MustConnect()
+	defer client.Close()
+
+	result, err := client.ProjectPull(projectName)
+	if err != nil {
+		return fmt.Errorf("pull project: %w", err)
+	}
+
+	switch {
+	case result.LocalChanges:
+		fmt.Printf("⚠ %s has uncommitted changes; %s left at %s\n", projectName, result.Branch, shortSHA(result.SHA))
+	case result.Updated:
+		fmt.Printf("🚌 Updated %s: %s → %s\n", result.Branch, shortSHA(result.PreviousSHA), shortSHA(result.SHA))
+	default:
+		fmt.Printf("🚌 %s already up to date at %s\n", result.Branch, shortSHA(result.SHA))
+	}
+	return nil
+}
+
+func runProjectLog(cmd *cobra.Command, args []string) error {
+	projectName := args[0]
+
+	client := MustConnect()
+	defer client.Close()
+
+	var since, until time.Time
+	if projectLogSince > 0 {
+		since = time.Now().Add(-projectLogSince)
+	}
+	if projectLogUntil > 0 {
+		until = time.Now().Add(-projectLogUntil)
+	}
+	result, err := client.ProjectLog(projectName, projectLogLimit, since, until, projectLogFetch)
+	if err != nil {
+		return fmt.Errorf("project log: %w", err)
+	}
+
+	if len(result.Commits) == 0 {
+		fmt.Printf("No commits on %s\n", result.Branch)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "SHA\tDATE\tAUTHOR\tAGENT\tSUBJECT")
+	for _, c := range result.Commits {
+		agent := "-"
+		if c.Fab {
+			agent = c.AgentID
+			if c.IssueID != "" {
+				agent += " (" + c.IssueID + ")"
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			shortSHA(c.SHA), c.Time.Local().Format("2006-01-02 15:04"), c.Author, agent, c.Subject)
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
 func runProjectConfigShow(cmd *cobra.Command, args []string) error {
 	projectName := args[0]
 
@@ -355,19 +558,47 @@ func runProjectConfigShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("get config: %w", err)
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	printProjectConfig(os.Stdout, result)
+
+	return nil
+}
+
+// printProjectConfig lists a project's config keys with their values.
+// Keys the project sets are marked with their default; the rest are marked
+// as defaulted.
+func printProjectConfig(out io.Writer, result *daemon.ProjectConfigShowResponse) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
 	_, _ = fmt.Fprintf(w, "Project:\t%s\n", result.Name)
 	_, _ = fmt.Fprintln(w, "\nConfiguration:")
-	_, _ = fmt.Fprintf(w, "  max-agents:\t%v\n", result.Config["max-agents"])
-	_, _ = fmt.Fprintf(w, "  autostart:\t%v\n", result.Config["autostart"])
-	_, _ = fmt.Fprintf(w, "  issue-backend:\t%v\n", result.Config["issue-backend"])
-	_, _ = fmt.Fprintf(w, "  permissions-checker:\t%v\n", result.Config["permissions-checker"])
-	_, _ = fmt.Fprintf(w, "  agent-backend:\t%v\n", result.Config["agent-backend"])
-	_, _ = fmt.Fprintf(w, "  planner-backend:\t%v\n", result.Config["planner-backend"])
-	_, _ = fmt.Fprintf(w, "  coding-backend:\t%v\n", result.Config["coding-backend"])
+	for _, e := range result.Entries {
+		source := "(default)"
+		if e.Set {
+			source = "(set)"
+			if !e.Redacted {
+				source = fmt.Sprintf("(set, default: %s)", formatConfigValue(e.Default))
+			}
+		}
+		_, _ = fmt.Fprintf(w, "  %s:\t%s\t%s\n", e.Key, formatConfigValue(e.Value), source)
+	}
 	_ = w.Flush()
+}
 
-	return nil
+// formatConfigValue renders a config value for display.
+func formatConfigValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return "-"
+		}
+		return v
+	case []string:
+		if len(v) == 0 {
+			return "-"
+		}
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
 }
 
 func runProjectConfigGet(cmd *cobra.Command, args []string) error {
@@ -407,12 +638,20 @@ func init() {
 	projectAddCmd.Flags().IntVarP(&projectAddMaxAgents, "max-agents", "m", 3, "Maximum concurrent agents")
 	projectAddCmd.Flags().BoolVar(&projectAddAutostart, "autostart", false, "Start orchestration when daemon starts")
 	projectAddCmd.Flags().StringVarP(&projectAddBackend, "backend", "b", "", "Agent backend (claude/codex, default: claude)")
+	projectAddCmd.Flags().StringVar(&projectAddPreset, "preset", "", "Apply a config preset from the global config's [presets.<name>]")
+	projectAddCmd.Flags().StringArrayVarP(&projectAddConfig, "config", "c", nil, "Set a config value (key=value, repeatable)")
+	projectAddCmd.Flags().IntVar(&projectAddDepth, "depth", 0, "Shallow clone with this many commits of history")
+	projectAddCmd.Flags().BoolVar(&projectAddSingleBranch, "single-branch", false, "Clone only the default branch")
 
 	projectStartCmd.Flags().BoolVarP(&projectStartAll, "all", "a", false, "Start all projects")
 	projectStopCmd.Flags().BoolVarP(&projectStopAll, "all", "a", false, "Stop all projects")
 
-	projectRemoveCmd.Flags().BoolVarP(&projectRemoveForce, "force", "f", false, "Skip confirmation prompt")
+	projectRemoveCmd.Flags().BoolVarP(&projectRemoveForce, "force", "f", false, "Skip confirmation prompt and stop the project if it is running")
 	projectRemoveCmd.Flags().BoolVar(&projectRemoveDeleteWorktrees, "delete-worktrees", false, "Delete associated worktrees")
+	projectLogCmd.Flags().IntVarP(&projectLogLimit, "limit", "n", 20, "Number of commits to show")
+	projectLogCmd.Flags().DurationVar(&projectLogSince, "since", 0, "Only show commits that landed within this long ago (e.g. 8h)")
+	projectLogCmd.Flags().DurationVar(&projectLogUntil, "until", 0, "Only show commits that landed at least this long ago (e.g. 24h)")
+	projectLogCmd.Flags().BoolVar(&projectLogFetch, "fetch", false, "Fetch origin first")
 
 	// Set up project config subcommands
 	projectConfigCmd.AddCommand(projectConfigShowCmd)
@@ -424,6 +663,8 @@ func init() {
 	projectCmd.AddCommand(projectStartCmd)
 	projectCmd.AddCommand(projectStopCmd)
 	projectCmd.AddCommand(projectRemoveCmd)
+	projectCmd.AddCommand(projectPullCmd)
+	projectCmd.AddCommand(projectLogCmd)
 	projectCmd.AddCommand(projectConfigCmd)
 	rootCmd.AddCommand(projectCmd)
 }
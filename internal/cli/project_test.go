@@ -1,9 +1,14 @@
 package cli
 
 import (
+	"bytes"
+	"maps"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/tessro/fab/internal/daemon"
 )
 
 func TestIsGitHubShorthand(t *testing.T) {
@@ -153,3 +158,47 @@ func TestResolveLocalDir(t *testing.T) {
 		}
 	})
 }
+
+func TestParseConfigAssignments(t *testing.T) {
+	got, err := parseConfigAssignments([]string{"max-agents=5", "pre-merge-command=make test=all", "model="})
+	if err != nil {
+		t.Fatalf("parseConfigAssignments() error = %v", err)
+	}
+	want := map[string]string{"max-agents": "5", "pre-merge-command": "make test=all", "model": ""}
+	if !maps.Equal(got, want) {
+		t.Errorf("parseConfigAssignments() = %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"max-agents", "=5"} {
+		if _, err := parseConfigAssignments([]string{bad}); err == nil {
+			t.Errorf("parseConfigAssignments(%q) should fail", bad)
+		}
+	}
+}
+
+func TestPrintProjectConfig(t *testing.T) {
+	var buf bytes.Buffer
+	printProjectConfig(&buf, &daemon.ProjectConfigShowResponse{
+		Name: "app",
+		Entries: []daemon.ProjectConfigEntry{
+			{Key: "max-agents", Type: daemon.ConfigTypeInt, Value: 5, Default: 3, Set: true},
+			{Key: "autostart", Type: daemon.ConfigTypeBool, Value: false, Default: false},
+			{Key: "allowed-authors", Type: daemon.ConfigTypeList, Value: []string{"alice", "bob"}, Default: []string{}, Set: true},
+			{Key: "model", Type: daemon.ConfigTypeString, Value: "", Default: ""},
+			{Key: "api-token", Type: daemon.ConfigTypeString, Value: "[REDACTED]", Default: "", Set: true, Redacted: true},
+		},
+	})
+	out := buf.String()
+
+	for _, want := range []string{
+		"max-agents:       5           (set, default: 3)",
+		"autostart:        false       (default)",
+		"allowed-authors:  alice,bob   (set, default: -)",
+		"model:            -           (default)",
+		"api-token:        [REDACTED]  (set)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
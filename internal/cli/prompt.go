@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/paths"
+)
+
+// DefaultPromptTimeout is how long 'fab prompt' waits for a plan by default.
+const DefaultPromptTimeout = 30 * time.Minute
+
+var (
+	promptProject string
+	promptTimeout time.Duration
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt <prompt>",
+	Short: "Run a one-shot planning prompt and print the plan",
+	Long: `Start a planning agent with the given prompt, wait for it to finish,
+and print the plan it wrote to stdout. Progress messages go to stderr, so
+the output can be piped or captured in CI.
+
+Exits non-zero if the planner reports an error, exits without completing,
+or doesn't finish within --timeout. A timed-out planner is stopped.
+
+Examples:
+  fab prompt --project myapp "design a rate limiter for the API"
+  fab prompt -p myapp --timeout 10m "plan the auth refactor" > plan.md
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPrompt,
+}
+
+func runPrompt(cmd *cobra.Command, args []string) error {
+	client := MustConnect()
+	defer client.Close()
+
+	// Subscribe before starting so the completion event can't be missed
+	events, err := client.StreamEvents(nil)
+	if err != nil {
+		return fmt.Errorf("stream events: %w", err)
+	}
+	defer client.StopEventStream()
+
+	resp, err := client.PlanStart(promptProject, args[0])
+	if err != nil {
+		return fmt.Errorf("start planner: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "🚌 Started planner %s (timeout %s)\n", resp.ID, promptTimeout)
+
+	if err := waitForPlan(events, resp.ID, promptTimeout); err != nil {
+		// Don't leave a runaway planner behind
+		if stopErr := client.PlanStop(resp.ID); stopErr != nil {
+			fmt.Fprintf(os.Stderr, "   Failed to stop planner: %v\n", stopErr)
+		}
+		return err
+	}
+
+	planPath, err := paths.PlanPath(resp.ID)
+	if err != nil {
+		return fmt.Errorf("get plan path: %w", err)
+	}
+	content, err := os.ReadFile(planPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("planner %s completed without writing a plan", resp.ID)
+		}
+		return fmt.Errorf("read plan: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "🚌 Planner %s completed\n", resp.ID)
+	fmt.Print(string(content))
+	return nil
+}
+
+// waitForPlan blocks until the planner reports completion via a
+// "plan_complete" event. It fails if the planner reports an error, stops
+// without completing, the timeout elapses, or the user interrupts.
+func waitForPlan(events <-chan daemon.EventResult, plannerID string, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-timer.C:
+			return fmt.Errorf("planner %s did not complete within %s", plannerID, timeout)
+
+		case <-sigCh:
+			return fmt.Errorf("interrupted waiting for planner %s", plannerID)
+
+		case result, ok := <-events:
+			if !ok {
+				return fmt.Errorf("event stream closed before planner %s completed", plannerID)
+			}
+			if result.Err != nil {
+				return fmt.Errorf("receive event: %w", result.Err)
+			}
+
+			event := result.Event
+			if event.AgentID != plannerID {
+				continue
+			}
+			switch event.Type {
+			case "plan_complete":
+				if event.Error != "" {
+					return fmt.Errorf("planner %s failed: %s", plannerID, event.Error)
+				}
+				return nil
+			case "planner_deleted":
+				return fmt.Errorf("planner %s exited without completing", plannerID)
+			case "planner_state":
+				if event.State == "stopped" {
+					return fmt.Errorf("planner %s stopped without completing", plannerID)
+				}
+			}
+		}
+	}
+}
+
+func init() {
+	promptCmd.Flags().StringVarP(&promptProject, "project", "p", "", "Project to plan in (default: no project)")
+	_ = promptCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
+	promptCmd.Flags().DurationVar(&promptTimeout, "timeout", DefaultPromptTimeout, "How long to wait for the plan")
+	rootCmd.AddCommand(promptCmd)
+}
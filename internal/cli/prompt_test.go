@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tessro/fab/internal/daemon"
+)
+
+func TestWaitForPlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []daemon.StreamEvent
+		wantErr string
+	}{
+		{
+			name: "completes",
+			events: []daemon.StreamEvent{
+				{Type: "plan_complete", AgentID: "other"},
+				{Type: "planner_state", AgentID: "p1", State: "running"},
+				{Type: "plan_complete", AgentID: "p1"},
+			},
+		},
+		{
+			name:    "reports planner error",
+			events:  []daemon.StreamEvent{{Type: "plan_complete", AgentID: "p1", Error: "no access"}},
+			wantErr: "failed: no access",
+		},
+		{
+			name:    "planner stopped",
+			events:  []daemon.StreamEvent{{Type: "planner_state", AgentID: "p1", State: "stopped"}},
+			wantErr: "stopped without completing",
+		},
+		{
+			name:    "planner deleted",
+			events:  []daemon.StreamEvent{{Type: "planner_deleted", AgentID: "p1"}},
+			wantErr: "exited without completing",
+		},
+		{
+			name:    "times out",
+			events:  []daemon.StreamEvent{{Type: "plan_complete", AgentID: "other"}},
+			wantErr: "did not complete within",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := make(chan daemon.EventResult, len(tt.events))
+			for i := range tt.events {
+				events <- daemon.EventResult{Event: &tt.events[i]}
+			}
+
+			err := waitForPlan(events, "p1", 50*time.Millisecond)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("waitForPlan() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("waitForPlan() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var replayProject string
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <agent-id>",
+	Short: "Replay an agent's conversation into a fresh agent",
+	Long: `Start a new agent in a clean worktree and re-send the source agent's user
+turns in order, waiting for each turn to finish before sending the next.
+Assistant and tool entries are not replayed.
+
+The source agent can still be running, or may have been deleted; deleted
+agents are replayed from their saved transcript. Useful for reproducing
+problems an agent hit.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAgent,
+	RunE:              runReplay,
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	client := MustConnect()
+	defer client.Close()
+
+	resp, err := client.AgentReplay(args[0], replayProject)
+	if err != nil {
+		return fmt.Errorf("replay agent: %w", err)
+	}
+
+	fmt.Printf("🚌 Replaying %s into agent %s (%d turns)\n", resp.SourceID, resp.ID, resp.Turns)
+	fmt.Printf("   Project: %s\n", resp.Project)
+	fmt.Printf("   Worktree: %s\n", resp.Worktree)
+	fmt.Println()
+	fmt.Printf("Use 'fab tui' to follow the replay.\n")
+
+	return nil
+}
+
+func init() {
+	replayCmd.Flags().StringVarP(&replayProject, "project", "p", "", "Project to replay into (default: the source agent's project)")
+	_ = replayCmd.RegisterFlagCompletionFunc("project", completeProjectFlag)
+	rootCmd.AddCommand(replayCmd)
+}
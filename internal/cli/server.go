@@ -15,6 +15,7 @@ import (
 	"github.com/tessro/fab/internal/config"
 	"github.com/tessro/fab/internal/daemon"
 	"github.com/tessro/fab/internal/logging"
+	"github.com/tessro/fab/internal/metrics"
 	"github.com/tessro/fab/internal/plugin"
 	"github.com/tessro/fab/internal/registry"
 	"github.com/tessro/fab/internal/supervisor"
@@ -209,15 +210,36 @@ func runDaemon() error {
 	// Create and start daemon server
 	srv := daemon.NewServer("", sup)
 	sup.SetServer(srv)
+	logging.SetSink(srv)
+	defer logging.SetSink(nil)
 
 	if err := srv.Start(); err != nil {
 		return fmt.Errorf("start server: %w", err)
 	}
 	defer func() { _ = srv.Stop() }()
 
+	// Serve the WebSocket event bridge if an HTTP listener is configured
+	if addr := cfg.GetHTTPListen(); addr != "" {
+		if cfg.GetHTTPMetrics() {
+			reg := metrics.NewRegistry()
+			metrics.SetRecorder(reg)
+			srv.SetMetricsHandler(metrics.Handler(reg, sup.MetricsGauges))
+		}
+		if err := srv.StartHTTP(addr, cfg.GetHTTPAllowedOrigins(), cfg.GetHTTPToken()); err != nil {
+			return fmt.Errorf("start http listener: %w", err)
+		}
+	}
+
+	// Finish or roll back merges interrupted by a previous crash
+	// before any orchestrator can start a new one
+	sup.RecoverInterruptedMerges()
+
 	// Start orchestration for projects with autostart=true
 	sup.StartAutostart()
 
+	// Exit on our own once idle for auto-shutdown-idle, if configured
+	sup.StartIdleShutdown()
+
 	// Comment poller is started automatically in supervisor.New()
 	defer sup.StopCommentPoller()
 
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tessro/fab/internal/daemon"
+)
+
+var statJSON bool
+
+var statCmd = &cobra.Command{
+	Use:   "stat",
+	Short: "Show projects, agents, and claims in one report",
+	Long: `Collect daemon status, projects, agents, and ticket claims into a single
+report for scripting. With --json the report is printed as one JSON object.
+
+If some of the data can't be fetched, the rest is still reported and the
+failures are listed (under "errors" in JSON). The command only fails if
+nothing could be fetched.`,
+	Args: cobra.NoArgs,
+	RunE: runStat,
+}
+
+// statReport merges the daemon's status and claim list.
+type statReport struct {
+	Daemon     daemon.DaemonStatus      `json:"daemon"`
+	Supervisor *daemon.SupervisorStatus `json:"supervisor,omitempty"`
+	Projects   []daemon.ProjectStatus   `json:"projects"`
+	Claims     []daemon.ClaimInfo       `json:"claims"`
+	Errors     map[string]string        `json:"errors,omitempty"` // Section -> error for data that couldn't be fetched
+}
+
+// statClient is the subset of the daemon client used by fab stat.
+type statClient interface {
+	Status() (*daemon.StatusResponse, error)
+	ClaimList(project string) (*daemon.ClaimListResponse, error)
+}
+
+// collectStat fetches each section of the report independently, recording
+// failures instead of giving up on the first one.
+func collectStat(client statClient) *statReport {
+	report := &statReport{
+		Projects: []daemon.ProjectStatus{},
+		Claims:   []daemon.ClaimInfo{},
+	}
+	fail := func(section string, err error) {
+		if report.Errors == nil {
+			report.Errors = make(map[string]string)
+		}
+		report.Errors[section] = err.Error()
+	}
+
+	if status, err := client.Status(); err != nil {
+		fail("status", err)
+	} else {
+		report.Daemon = status.Daemon
+		report.Supervisor = &status.Supervisor
+		if status.Projects != nil {
+			report.Projects = status.Projects
+		}
+	}
+
+	if claims, err := client.ClaimList(""); err != nil {
+		fail("claims", err)
+	} else if claims.Claims != nil {
+		report.Claims = claims.Claims
+	}
+
+	return report
+}
+
+func runStat(cmd *cobra.Command, args []string) error {
+	var report *statReport
+	client, err := ConnectClient()
+	if err != nil {
+		if !errors.Is(err, ErrDaemonNotRunning) {
+			return fmt.Errorf("connect to daemon: %w", err)
+		}
+		report = &statReport{
+			Projects: []daemon.ProjectStatus{},
+			Claims:   []daemon.ClaimInfo{},
+			Errors:   map[string]string{"daemon": err.Error()},
+		}
+	} else {
+		defer client.Close()
+		report = collectStat(client)
+	}
+
+	if statJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("encode report: %w", err)
+		}
+	} else {
+		printStat(report)
+	}
+
+	// Partial data is still useful; only fail when nothing was fetched
+	if report.Supervisor == nil && report.Errors["claims"] != "" {
+		return fmt.Errorf("could not fetch daemon status or claims")
+	}
+	return nil
+}
+
+func printStat(report *statReport) {
+	if report.Daemon.Running {
+		uptime := time.Since(report.Daemon.StartedAt).Truncate(time.Second)
+		fmt.Printf("🚌 fab daemon running (pid %d, uptime %s)\n", report.Daemon.PID, uptime)
+	} else if report.Supervisor == nil {
+		fmt.Println("🚌 fab daemon status unavailable")
+	}
+	if sup := report.Supervisor; sup != nil {
+		fmt.Printf("   Projects: %d active, Agents: %d running / %d idle / %d total, Claims: %d\n",
+			sup.ActiveProjects, sup.RunningAgents, sup.IdleAgents, sup.TotalAgents, len(report.Claims))
+	}
+	for _, section := range slices.Sorted(maps.Keys(report.Errors)) {
+		fmt.Fprintf(os.Stderr, "   Failed to fetch %s: %s\n", section, report.Errors[section])
+	}
+
+	if len(report.Projects) > 0 {
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "PROJECT\tSTATUS\tAGENTS\tCLAIMS")
+		claimCounts := make(map[string]int)
+		for _, c := range report.Claims {
+			claimCounts[c.Project]++
+		}
+		for _, p := range report.Projects {
+			projectStatus := "stopped"
+			if p.Running {
+				projectStatus = "running"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%d/%d\t%d\n", p.Name, projectStatus, p.ActiveAgents, p.MaxAgents, claimCounts[p.Name])
+		}
+		_ = w.Flush()
+
+		fmt.Println()
+		printAgents(report.Projects)
+	}
+
+	if len(report.Claims) > 0 {
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "TICKET\tAGENT\tPROJECT")
+		for _, c := range report.Claims {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", c.TicketID, c.AgentID, c.Project)
+		}
+		_ = w.Flush()
+	}
+	printIssueFailures(os.Stdout, report.Projects)
+}
+
+// printIssueFailures lists issues with recent agent failures, if any.
+func printIssueFailures(out io.Writer, projects []daemon.ProjectStatus) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	header := false
+	for _, p := range projects {
+		for _, f := range p.IssueFailures {
+			if !header {
+				_, _ = fmt.Fprintln(out)
+				_, _ = fmt.Fprintln(w, "FAILING ISSUE\tPROJECT\tFAILURES\tBLOCKED\tLAST FAILURE")
+				header = true
+			}
+			blocked := "no"
+			if f.Blocked {
+				blocked = "yes"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", f.Issue, p.Name, f.Failures, blocked, f.LastReason)
+		}
+	}
+	_ = w.Flush()
+}
+
+func init() {
+	statCmd.Flags().BoolVar(&statJSON, "json", false, "Print the report as JSON")
+	rootCmd.AddCommand(statCmd)
+}
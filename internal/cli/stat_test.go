@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tessro/fab/internal/daemon"
+)
+
+type fakeStatClient struct {
+	status    *daemon.StatusResponse
+	statusErr error
+	claims    *daemon.ClaimListResponse
+	claimsErr error
+}
+
+func (c *fakeStatClient) Status() (*daemon.StatusResponse, error) {
+	return c.status, c.statusErr
+}
+
+func (c *fakeStatClient) ClaimList(project string) (*daemon.ClaimListResponse, error) {
+	return c.claims, c.claimsErr
+}
+
+func TestCollectStat(t *testing.T) {
+	status := &daemon.StatusResponse{
+		Daemon:     daemon.DaemonStatus{Running: true, PID: 42},
+		Supervisor: daemon.SupervisorStatus{TotalAgents: 1},
+		Projects:   []daemon.ProjectStatus{{Name: "app"}},
+	}
+	claims := &daemon.ClaimListResponse{Claims: []daemon.ClaimInfo{{TicketID: "FAB-1", AgentID: "a1", Project: "app"}}}
+
+	t.Run("merges all sections", func(t *testing.T) {
+		report := collectStat(&fakeStatClient{status: status, claims: claims})
+		if report.Daemon.PID != 42 || report.Supervisor == nil || report.Supervisor.TotalAgents != 1 {
+			t.Errorf("status not merged: %+v", report)
+		}
+		if len(report.Projects) != 1 || len(report.Claims) != 1 {
+			t.Errorf("got %d projects, %d claims; want 1, 1", len(report.Projects), len(report.Claims))
+		}
+		if report.Errors != nil {
+			t.Errorf("Errors = %v, want nil", report.Errors)
+		}
+	})
+
+	t.Run("reports partial data", func(t *testing.T) {
+		report := collectStat(&fakeStatClient{status: status, claimsErr: errors.New("boom")})
+		if report.Supervisor == nil || len(report.Projects) != 1 {
+			t.Errorf("status should still be reported: %+v", report)
+		}
+		if report.Claims == nil || len(report.Claims) != 0 {
+			t.Errorf("Claims = %v, want empty slice", report.Claims)
+		}
+		if report.Errors["claims"] != "boom" {
+			t.Errorf("Errors = %v, want claims: boom", report.Errors)
+		}
+	})
+
+	t.Run("status failure", func(t *testing.T) {
+		report := collectStat(&fakeStatClient{statusErr: errors.New("down"), claims: claims})
+		if report.Supervisor != nil {
+			t.Error("Supervisor should be nil when status fails")
+		}
+		if len(report.Claims) != 1 || report.Errors["status"] != "down" {
+			t.Errorf("got claims %v, errors %v", report.Claims, report.Errors)
+		}
+	})
+}
+
+func TestPrintIssueFailures(t *testing.T) {
+	var buf bytes.Buffer
+	printIssueFailures(&buf, []daemon.ProjectStatus{{Name: "app"}})
+	if buf.Len() != 0 {
+		t.Errorf("output = %q, want nothing without failures", buf.String())
+	}
+
+	printIssueFailures(&buf, []daemon.ProjectStatus{{
+		Name: "app",
+		IssueFailures: []daemon.IssueFailureStats{
+			{Issue: "FAB-1", Failures: 3, LastReason: "agent crashed", Blocked: true},
+		},
+	}})
+	out := buf.String()
+	for _, want := range []string{"FAILING ISSUE", "FAB-1", "app", "yes", "agent crashed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -64,6 +64,12 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			projectStatus = "running"
 		}
 		agentInfo := fmt.Sprintf("%d/%d", p.ActiveAgents, p.MaxAgents)
+		if p.Reserved > 0 {
+			agentInfo += fmt.Sprintf("+%d", p.Reserved)
+		}
+		if p.AtCapacity && p.WaitingIssues > 0 {
+			agentInfo += fmt.Sprintf(" (%d waiting)", p.WaitingIssues)
+		}
 		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Name, projectStatus, agentInfo, p.RemoteURL)
 	}
 	_ = w.Flush()
@@ -92,7 +98,7 @@ func printAgents(projects []daemon.ProjectStatus) {
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "AGENT\tPROJECT\tSTATE\tUPTIME\tTASK\tDESCRIPTION")
+	_, _ = fmt.Fprintln(w, "AGENT\tPROJECT\tSTATE\tUPTIME\tTASK\tSPAWNED BY\tDESCRIPTION")
 	for _, p := range projects {
 		for _, a := range p.Agents {
 			uptime := time.Since(a.StartedAt).Truncate(time.Second)
@@ -100,6 +106,10 @@ func printAgents(projects []daemon.ProjectStatus) {
 			if task == "" {
 				task = "-"
 			}
+			spawnedBy := a.SpawnedBy
+			if spawnedBy == "" {
+				spawnedBy = "-"
+			}
 			desc := a.Description
 			if desc == "" {
 				desc = "-"
@@ -108,7 +118,7 @@ func printAgents(projects []daemon.ProjectStatus) {
 			if len(desc) > 40 {
 				desc = desc[:37] + "..."
 			}
-			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", a.ID, a.Project, a.State, uptime, task, desc)
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", a.ID, a.Project, a.State, uptime, task, spawnedBy, desc)
 		}
 	}
 	_ = w.Flush()
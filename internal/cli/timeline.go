@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	timelineSince time.Duration
+	timelineLimit int
+)
+
+var timelineCmd = &cobra.Command{
+	Use:   "timeline <project>",
+	Short: "Show a project's activity timeline",
+	Long: `Show when agents were spawned, claimed issues, merged or opened pull
+requests, and were aborted, and when the project's config changed.
+
+The timeline is kept in timeline.jsonl in the project directory and is never
+rewritten, so it survives daemon restarts.`,
+	Example: `  fab timeline myproject              # Recent activity
+  fab timeline myproject --since 24h   # The last day`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProject,
+	RunE:              runTimeline,
+}
+
+func runTimeline(cmd *cobra.Command, args []string) error {
+	client := MustConnect()
+	defer client.Close()
+
+	var since time.Time
+	if timelineSince > 0 {
+		since = time.Now().Add(-timelineSince)
+	}
+	resp, err := client.Timeline(args[0], since, timelineLimit)
+	if err != nil {
+		return fmt.Errorf("read timeline: %w", err)
+	}
+
+	if len(resp.Events) == 0 {
+		fmt.Printf("No activity for project %q\n", resp.Project)
+		return nil
+	}
+	if resp.Truncated {
+		fmt.Printf("(showing the %d most recent events; see %s for all)\n\n", len(resp.Events), resp.Path)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tEVENT\tAGENT\tISSUE\tDETAIL")
+	for _, ev := range resp.Events {
+		detail := ev.Detail
+		if ev.SHA != "" {
+			detail = ev.SHA
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			ev.Time.Local().Format("2006-01-02 15:04:05"), ev.Type, ev.AgentID, ev.IssueID, detail)
+	}
+	_ = w.Flush()
+	return nil
+}
+
+func init() {
+	timelineCmd.Flags().DurationVar(&timelineSince, "since", 0, "Only show events newer than this (e.g. 1h, 24h)")
+	timelineCmd.Flags().IntVarP(&timelineLimit, "limit", "n", 0, "Maximum events to show (default 1000)")
+	rootCmd.AddCommand(timelineCmd)
+}
@@ -1,20 +1,36 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"github.com/tessro/fab/internal/config"
 	"github.com/tessro/fab/internal/logging"
 	"github.com/tessro/fab/internal/tui"
 )
 
+var tuiInitial string
+
 var tuiCmd = &cobra.Command{
 	Use:   "tui",
 	Short: "Launch the terminal user interface",
-	Long:  "Launch the interactive TUI for monitoring and managing fab agents.",
+	Long: `Launch the interactive TUI for monitoring and managing fab agents.
+
+Use --initial to start with a specific entry selected:
+  fab tui --initial a1b2c3          # an agent
+  fab tui --initial plan:d4e5f6     # a planner
+  fab tui --initial manager:myapp   # a project's manager`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Load global config for log level
+		if _, _, err := tui.ParseInitialSelection(tuiInitial); err != nil {
+			return err
+		}
+
+		// Load global config for log level and reconnect settings
 		cfg, _ := config.LoadGlobalConfig()
 		logLevel := logging.ParseLevel(cfg.GetLogLevel())
+		if _, err := tui.NewKeyBindings(cfg.GetKeyBindings()); err != nil {
+			return fmt.Errorf("tui.keys: %w", err)
+		}
 
 		// Set up file logging for TUI debugging
 		cleanup, err := logging.Setup("", logLevel)
@@ -27,10 +43,23 @@ var tuiCmd = &cobra.Command{
 			return err
 		}
 		defer client.Close()
-		return tui.RunWithClient(client, nil)
+		return tui.RunWithClient(client, &tui.TUIOptions{
+			InitialAgentID:     tuiInitial,
+			ReconnectMax:       cfg.GetReconnectMax(),
+			ReconnectBaseDelay: cfg.GetReconnectBaseDelay(),
+			ReconnectMaxDelay:  cfg.GetReconnectMaxDelay(),
+			MaxChatEntries:     cfg.GetMaxChatEntries(),
+			AgentSort:          cfg.GetAgentSort(),
+			AgentSortOrder:     cfg.GetAgentSortOrder(),
+			AssistantName:      cfg.GetAssistantName(),
+			Theme:              cfg.GetTheme(),
+			ThemeColors:        cfg.GetThemeColors(),
+			Keys:               cfg.GetKeyBindings(),
+		})
 	},
 }
 
 func init() {
+	tuiCmd.Flags().StringVar(&tuiInitial, "initial", "", "Entry to select on startup: <agent-id>, plan:<id>, or manager:<project>")
 	rootCmd.AddCommand(tuiCmd)
 }
@@ -1,22 +1,52 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
 	"github.com/tessro/fab/internal/version"
 )
 
+var versionCheck bool
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
-	Long:  "Print the version, commit, and build date of fab.",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("🚌 fab %s (commit: %s, built: %s)\n",
-			version.Version, version.Commit, version.Date)
-	},
+	Long: `Print the version, commit, and build date of fab.
+
+With --check, also look up the latest release on GitHub and report whether
+an update is available.`,
+	RunE: runVersion,
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	fmt.Printf("🚌 fab %s (commit: %s, built: %s)\n",
+		version.Version, version.Commit, version.Date)
+	if !versionCheck {
+		return nil
+	}
+
+	release, err := version.LatestRelease(context.Background())
+	if err != nil {
+		return fmt.Errorf("check for updates: %w", err)
+	}
+
+	switch {
+	case version.UpdateAvailable(version.Version, release.Tag):
+		fmt.Printf("⬆️  Update available: %s (you have %s)\n", release.Tag, version.Version)
+		if release.URL != "" {
+			fmt.Printf("   %s\n", release.URL)
+		}
+	case !version.IsSemver(version.Version):
+		fmt.Printf("Development build; the latest release is %s\n", release.Tag)
+	default:
+		fmt.Printf("✅ Up to date (latest release: %s)\n", release.Tag)
+	}
+	return nil
 }
 
 func init() {
+	versionCmd.Flags().BoolVar(&versionCheck, "check", false, "Check GitHub for a newer release")
 	rootCmd.AddCommand(versionCmd)
 }
@@ -2,7 +2,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 
@@ -15,6 +18,21 @@ type GlobalConfig struct {
 	// Defaults to "info" if not specified.
 	LogLevel string `toml:"log-level"`
 
+	// AutoShutdownIdle stops the daemon after it has been idle this long
+	// (e.g., "30m"): no running projects, no agents, no attached clients,
+	// and no requests. Empty or "0" disables it.
+	AutoShutdownIdle string `toml:"auto-shutdown-idle"`
+
+	// EventReplaySize is how many recent stream events the daemon keeps per
+	// project and replays to clients when they attach. Defaults to
+	// DefaultEventReplaySize; 0 or a negative value disables replay.
+	EventReplaySize *int `toml:"event-replay-size"`
+
+	// PermissionTimeouts maps tool names to how long a permission request
+	// for that tool waits for an answer (e.g., Bash = "15m"). Tools not
+	// listed wait DefaultPermissionTimeout.
+	PermissionTimeouts map[string]string `toml:"permission-timeouts"`
+
 	// Providers contains API provider configurations.
 	Providers ProvidersConfig `toml:"providers"`
 
@@ -23,6 +41,142 @@ type GlobalConfig struct {
 
 	// Defaults contains default values for project configuration.
 	Defaults DefaultsConfig `toml:"defaults"`
+
+	// Notifications configures webhook and desktop notifications.
+	Notifications NotificationsConfig `toml:"notifications"`
+
+	// TUI configures the terminal user interface.
+	TUI TUIConfig `toml:"tui"`
+
+	// HTTP configures the daemon's optional HTTP listener.
+	HTTP HTTPConfig `toml:"http"`
+
+	// Exec configures `fab agent exec`, which runs operator commands in
+	// agent worktrees.
+	Exec ExecConfig `toml:"exec"`
+
+	// Redaction configures scrubbing secrets from agent output before it is
+	// recorded in chat history or sent to clients.
+	Redaction RedactionConfig `toml:"redaction"`
+
+	// Backends overrides how each agent CLI backend is launched, keyed by
+	// backend name ("claude" or "codex").
+	Backends map[string]BackendConfig `toml:"backends"`
+
+	// Presets are named bundles of project config values applied by
+	// `fab project add --preset <name>`, keyed by preset name. Each bundle
+	// maps project config keys to values (e.g., max-agents = 5).
+	Presets map[string]map[string]any `toml:"presets"`
+
+	// IssueTypePrompts maps an issue type ("bug", "feature", "task", ...) to
+	// guidance sent to an agent when it claims an issue of that type.
+	IssueTypePrompts map[string]string `toml:"issue-type-prompts"`
+}
+
+// BackendConfig overrides how an agent CLI backend is launched.
+type BackendConfig struct {
+	// Path is the CLI binary to run. Defaults to the backend name on PATH.
+	Path string `toml:"path"`
+	// ExtraArgs are passed to the CLI in addition to fab's own arguments
+	// (e.g., ["--model", "opus"]).
+	ExtraArgs []string `toml:"extra-args"`
+}
+
+// TUIConfig configures the terminal user interface.
+type TUIConfig struct {
+	// ReconnectMax is how many consecutive reconnect attempts the TUI makes
+	// before giving up. Defaults to 10.
+	ReconnectMax int `toml:"reconnect-max"`
+	// ReconnectBaseDelay is the delay before the first reconnect attempt as a
+	// duration string (default: "500ms"). It doubles after each failure.
+	ReconnectBaseDelay string `toml:"reconnect-base-delay"`
+	// ReconnectMaxDelay caps the exponential backoff between reconnect
+	// attempts as a duration string (default: "8s").
+	ReconnectMaxDelay string `toml:"reconnect-max-delay"`
+	// MaxChatEntries caps the chat entries the TUI keeps in memory per view.
+	// Older entries are dropped and reloaded on scroll. Defaults to 1000.
+	MaxChatEntries int `toml:"max-chat-entries"`
+	// AgentSort orders the agent list: "state" (agents needing attention
+	// first), "project", or "started". Empty keeps the daemon's order.
+	AgentSort string `toml:"agent-sort"`
+	// AgentSortOrder is "asc" (default) or "desc".
+	AgentSortOrder string `toml:"agent-sort-order"`
+	// AssistantName replaces the backend name (e.g., "Claude") shown before
+	// assistant messages in chat.
+	AssistantName string `toml:"assistant-name"`
+	// Theme picks the chat colors: "dark" (default), "light", or "custom"
+	// to use Colors.
+	Theme string `toml:"theme"`
+	// Colors sets the chat colors of the "custom" theme.
+	Colors TUIColors `toml:"colors"`
+	// Keys rebinds TUI actions, mapping an action (e.g., "approve") to the
+	// keys that trigger it (e.g., ["a"]). Unlisted actions keep their
+	// default keys.
+	Keys map[string][]string `toml:"keys"`
+}
+
+// TUIColors sets chat colors as hex ("#RRGGBB") or ANSI color numbers
+// ("0"-"255"). Empty values keep the dark theme's color.
+type TUIColors struct {
+	Assistant  string `toml:"assistant"`   // Assistant message prefix
+	User       string `toml:"user"`        // User message prefix
+	Tool       string `toml:"tool"`        // Tool calls and results
+	Muted      string `toml:"muted"`       // Timestamps, truncation markers, finished todos
+	DiffAdd    string `toml:"diff-add"`    // Added lines in edits
+	DiffRemove string `toml:"diff-remove"` // Removed lines in edits
+	Highlight  string `toml:"highlight"`   // The todo in progress
+}
+
+// HTTPConfig configures the daemon's optional HTTP listener, which serves
+// the WebSocket event bridge for web UIs.
+type HTTPConfig struct {
+	// Listen is the address to listen on (e.g., "127.0.0.1:7878").
+	// Empty disables the listener.
+	Listen string `toml:"listen"`
+	// AllowedOrigins lists browser origins (e.g., "http://localhost:3000")
+	// allowed to open WebSocket connections. Same-origin requests and
+	// non-browser clients are always allowed; "*" allows any origin.
+	AllowedOrigins []string `toml:"allowed-origins"`
+	// Metrics serves daemon metrics in the Prometheus text format at
+	// /metrics on the listener.
+	Metrics bool `toml:"metrics"`
+	// Token, if set, must be presented by every client as a bearer token
+	// (or the token query parameter). Without it, Listen must be a
+	// loopback address.
+	Token string `toml:"token"`
+}
+
+// ExecConfig configures running operator commands in agent worktrees.
+// It is off by default since it gives any daemon client a shell.
+type ExecConfig struct {
+	// Enabled allows agent.exec requests.
+	Enabled bool `toml:"enabled"`
+	// Timeout bounds how long a command may run as a duration string
+	// (default: "30s").
+	Timeout string `toml:"timeout"`
+}
+
+// RedactionConfig configures secret redaction in agent output.
+type RedactionConfig struct {
+	// Defaults enables DefaultRedactionPatterns. Defaults to true.
+	Defaults *bool `toml:"defaults"`
+	// Patterns are extra regular expressions (RE2 syntax) to redact. If a
+	// pattern has a capture group, only the first group is replaced.
+	Patterns []string `toml:"patterns"`
+	// Env names environment variables of the daemon (which agents inherit)
+	// whose values are redacted wherever they appear.
+	Env []string `toml:"env"`
+}
+
+// NotificationsConfig configures out-of-band notifications for key events.
+type NotificationsConfig struct {
+	// WebhookURL receives a JSON POST for each event (e.g., a Slack incoming webhook).
+	WebhookURL string `toml:"webhook-url"`
+	// Desktop enables native desktop notifications (osascript on macOS, notify-send on Linux).
+	Desktop bool `toml:"desktop"`
+	// Events limits which event types trigger notifications
+	// ("permission_request", "user_question", "agent_done"). Empty means all.
+	Events []string `toml:"events"`
 }
 
 // DefaultsConfig contains default values for project configuration.
@@ -47,6 +201,16 @@ type DefaultsConfig struct {
 	Autostart *bool `toml:"autostart"`
 	// MaxAgents is the default max concurrent agents per project.
 	MaxAgents int `toml:"max-agents"`
+	// IssueCacheTTL caches the orchestrator's ready/list issue queries for this
+	// duration (e.g. "30s"). Empty or "0" disables caching.
+	IssueCacheTTL string `toml:"issue-cache-ttl"`
+	// PollInterval is how often orchestrators check for ready issues
+	// (e.g. "1m"). Must be at least MinPollInterval.
+	PollInterval string `toml:"poll-interval"`
+	// GitAuthorName and GitAuthorEmail are the identity fab uses for the
+	// commits it makes when merging agent work (e.g. "fab-bot").
+	GitAuthorName  string `toml:"git-author-name"`
+	GitAuthorEmail string `toml:"git-author-email"`
 }
 
 // ProvidersConfig contains API provider configurations.
@@ -236,3 +400,352 @@ func (c *GlobalConfig) GetDefaultMaxAgents() int {
 	}
 	return DefaultMaxAgents
 }
+
+// GetIssueCacheTTL returns how long issue queries are cached.
+// Returns 0 (caching disabled) if unset or unparseable.
+func (c *GlobalConfig) GetIssueCacheTTL() time.Duration {
+	if c != nil && c.Defaults.IssueCacheTTL != "" {
+		if d, err := time.ParseDuration(c.Defaults.IssueCacheTTL); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// DefaultPollInterval is the internal default for how often orchestrators
+// check for ready issues.
+const DefaultPollInterval = 10 * time.Second
+
+// GetDefaultPollInterval returns the configured default poll interval.
+// Falls back to DefaultPollInterval if unset or invalid.
+func (c *GlobalConfig) GetDefaultPollInterval() time.Duration {
+	if c != nil && c.Defaults.PollInterval != "" && ValidatePollInterval(c.Defaults.PollInterval) == nil {
+		d, _ := time.ParseDuration(c.Defaults.PollInterval)
+		return d
+	}
+	return DefaultPollInterval
+}
+
+// Internal defaults for the identity of commits fab makes.
+const (
+	DefaultGitAuthorName  = "fab-bot"
+	DefaultGitAuthorEmail = "fab-bot@localhost"
+)
+
+// GetDefaultGitAuthorName returns the configured default git author name or "fab-bot".
+func (c *GlobalConfig) GetDefaultGitAuthorName() string {
+	if c != nil && c.Defaults.GitAuthorName != "" {
+		return c.Defaults.GitAuthorName
+	}
+	return DefaultGitAuthorName
+}
+
+// GetDefaultGitAuthorEmail returns the configured default git author email or "fab-bot@localhost".
+func (c *GlobalConfig) GetDefaultGitAuthorEmail() string {
+	if c != nil && c.Defaults.GitAuthorEmail != "" {
+		return c.Defaults.GitAuthorEmail
+	}
+	return DefaultGitAuthorEmail
+}
+
+// DefaultReconnectMax is the internal default for TUI reconnect attempts.
+const DefaultReconnectMax = 10
+
+// DefaultReconnectBaseDelay is the internal default delay before the first TUI reconnect attempt.
+const DefaultReconnectBaseDelay = 500 * time.Millisecond
+
+// DefaultReconnectMaxDelay is the internal default cap on TUI reconnect backoff.
+const DefaultReconnectMaxDelay = 8 * time.Second
+
+// GetReconnectMax returns the configured TUI reconnect attempt limit or 10.
+func (c *GlobalConfig) GetReconnectMax() int {
+	if c != nil && c.TUI.ReconnectMax > 0 {
+		return c.TUI.ReconnectMax
+	}
+	return DefaultReconnectMax
+}
+
+// GetReconnectBaseDelay returns the configured initial TUI reconnect delay.
+// Falls back to DefaultReconnectBaseDelay if unset or unparseable.
+func (c *GlobalConfig) GetReconnectBaseDelay() time.Duration {
+	if c != nil && c.TUI.ReconnectBaseDelay != "" {
+		if d, err := time.ParseDuration(c.TUI.ReconnectBaseDelay); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultReconnectBaseDelay
+}
+
+// GetReconnectMaxDelay returns the configured cap on TUI reconnect backoff.
+// Falls back to DefaultReconnectMaxDelay if unset or unparseable, and is never
+// lower than the base delay.
+func (c *GlobalConfig) GetReconnectMaxDelay() time.Duration {
+	d := DefaultReconnectMaxDelay
+	if c != nil && c.TUI.ReconnectMaxDelay != "" {
+		if parsed, err := time.ParseDuration(c.TUI.ReconnectMaxDelay); err == nil && parsed > 0 {
+			d = parsed
+		}
+	}
+	return max(d, c.GetReconnectBaseDelay())
+}
+
+// GetHTTPListen returns the HTTP listener address, or "" if disabled.
+func (c *GlobalConfig) GetHTTPListen() string {
+	if c != nil {
+		return c.HTTP.Listen
+	}
+	return ""
+}
+
+// GetHTTPAllowedOrigins returns the browser origins allowed to connect to
+// the HTTP listener.
+func (c *GlobalConfig) GetHTTPAllowedOrigins() []string {
+	if c != nil {
+		return c.HTTP.AllowedOrigins
+	}
+	return nil
+}
+
+// GetHTTPToken returns the token clients must present to the HTTP
+// listener, or "" if none is required.
+func (c *GlobalConfig) GetHTTPToken() string {
+	if c != nil {
+		return c.HTTP.Token
+	}
+	return ""
+}
+
+// GetHTTPMetrics reports whether the HTTP listener serves /metrics.
+func (c *GlobalConfig) GetHTTPMetrics() bool {
+	return c != nil && c.HTTP.Metrics
+}
+
+// GetAutoShutdownIdle returns how long the daemon may sit idle before
+// shutting itself down, or 0 if auto-shutdown is disabled or unparseable.
+func (c *GlobalConfig) GetAutoShutdownIdle() time.Duration {
+	if c == nil || c.AutoShutdownIdle == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(c.AutoShutdownIdle)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// DefaultEventReplaySize is the internal default for how many recent events
+// are replayed per project to attaching clients.
+const DefaultEventReplaySize = 100
+
+// GetEventReplaySize returns how many recent events are kept per project for
+// replay, or DefaultEventReplaySize if unset. 0 or a negative value disables
+// replay.
+func (c *GlobalConfig) GetEventReplaySize() int {
+	if c == nil || c.EventReplaySize == nil {
+		return DefaultEventReplaySize
+	}
+	return max(*c.EventReplaySize, 0)
+}
+
+// DefaultPermissionTimeout is how long a permission request waits for an
+// answer when no timeout is configured for its tool.
+const DefaultPermissionTimeout = 5 * time.Minute
+
+// GetPermissionTimeout returns how long a permission request for tool waits
+// for an answer. Falls back to DefaultPermissionTimeout if the tool has no
+// timeout or it is unparseable.
+func (c *GlobalConfig) GetPermissionTimeout(tool string) time.Duration {
+	if c != nil {
+		if d, err := time.ParseDuration(c.PermissionTimeouts[tool]); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultPermissionTimeout
+}
+
+// MaxPermissionTimeout returns the longest any permission request may wait,
+// which bounds how long the agent CLI must let the permission hook run.
+func (c *GlobalConfig) MaxPermissionTimeout() time.Duration {
+	longest := DefaultPermissionTimeout
+	if c != nil {
+		for tool := range c.PermissionTimeouts {
+			longest = max(longest, c.GetPermissionTimeout(tool))
+		}
+	}
+	return longest
+}
+
+// DefaultExecTimeout is the internal default limit on agent exec commands.
+const DefaultExecTimeout = 30 * time.Second
+
+// ExecEnabled reports whether operator commands may run in agent worktrees.
+func (c *GlobalConfig) ExecEnabled() bool {
+	return c != nil && c.Exec.Enabled
+}
+
+// GetExecTimeout returns the configured agent exec timeout.
+// Falls back to DefaultExecTimeout if unset or unparseable.
+func (c *GlobalConfig) GetExecTimeout() time.Duration {
+	if c != nil && c.Exec.Timeout != "" {
+		if d, err := time.ParseDuration(c.Exec.Timeout); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultExecTimeout
+}
+
+// DefaultRedactionPatterns match common secret shapes in agent output.
+var DefaultRedactionPatterns = []string{
+	// AWS access key IDs
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+	// AWS secret access keys following their usual variable name
+	`(?i)aws_secret_access_key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`,
+	// Bearer tokens in Authorization headers
+	`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]{16,}=*)`,
+	// GitHub tokens
+	`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`,
+	// Anthropic and OpenAI API keys
+	`\bsk-(?:ant-)?[A-Za-z0-9_\-]{20,}`,
+	// Slack tokens
+	`\bxox[abprs]-[A-Za-z0-9\-]{10,}`,
+	// PEM private keys
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+}
+
+// GetRedactionPatterns returns the regular expressions to redact from agent
+// output: DefaultRedactionPatterns unless redaction.defaults is false,
+// followed by any configured patterns.
+func (c *GlobalConfig) GetRedactionPatterns() []string {
+	var patterns []string
+	if c == nil || c.Redaction.Defaults == nil || *c.Redaction.Defaults {
+		patterns = append(patterns, DefaultRedactionPatterns...)
+	}
+	if c != nil {
+		patterns = append(patterns, c.Redaction.Patterns...)
+	}
+	return patterns
+}
+
+// GetRedactionValues returns the values of the environment variables named
+// in redaction.env. Unset and empty variables are skipped.
+func (c *GlobalConfig) GetRedactionValues() []string {
+	if c == nil {
+		return nil
+	}
+	var values []string
+	for _, name := range c.Redaction.Env {
+		if v := os.Getenv(name); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// DefaultMaxChatEntries is the internal default cap on chat entries held by the TUI.
+const DefaultMaxChatEntries = 1000
+
+// GetMaxChatEntries returns the configured TUI chat entry cap or 1000.
+func (c *GlobalConfig) GetMaxChatEntries() int {
+	if c != nil && c.TUI.MaxChatEntries > 0 {
+		return c.TUI.MaxChatEntries
+	}
+	return DefaultMaxChatEntries
+}
+
+// GetAgentSort returns the configured TUI agent list sort key, or "" for the
+// daemon's order. The value is passed through as written; the TUI validates it.
+func (c *GlobalConfig) GetAgentSort() string {
+	if c == nil {
+		return ""
+	}
+	return c.TUI.AgentSort
+}
+
+// GetAgentSortOrder returns the configured TUI agent list sort order, or ""
+// for ascending.
+func (c *GlobalConfig) GetAgentSortOrder() string {
+	if c == nil {
+		return ""
+	}
+	return c.TUI.AgentSortOrder
+}
+
+// GetAssistantName returns the configured chat name for assistants, or ""
+// to use the backend name.
+func (c *GlobalConfig) GetAssistantName() string {
+	if c == nil {
+		return ""
+	}
+	return strings.TrimSpace(c.TUI.AssistantName)
+}
+
+// GetTheme returns the configured chat theme, or "" for the default. The
+// value is passed through as written; the TUI validates it.
+func (c *GlobalConfig) GetTheme() string {
+	if c == nil {
+		return ""
+	}
+	return c.TUI.Theme
+}
+
+// GetThemeColors returns the colors configured for the custom chat theme.
+func (c *GlobalConfig) GetThemeColors() TUIColors {
+	if c == nil {
+		return TUIColors{}
+	}
+	return c.TUI.Colors
+}
+
+// GetKeyBindings returns the TUI key rebindings by action. The keys are
+// passed through as written; the TUI validates them.
+func (c *GlobalConfig) GetKeyBindings() map[string][]string {
+	if c == nil {
+		return nil
+	}
+	return c.TUI.Keys
+}
+
+// GetIssueTypePrompt returns the guidance configured for an issue type
+// (matched case-insensitively), or "" if there is none.
+func (c *GlobalConfig) GetIssueTypePrompt(issueType string) string {
+	if c == nil || issueType == "" {
+		return ""
+	}
+	for t, prompt := range c.IssueTypePrompts {
+		if strings.EqualFold(t, issueType) {
+			return strings.TrimSpace(prompt)
+		}
+	}
+	return ""
+}
+
+// HasIssueTypePrompts reports whether any issue type guidance is configured.
+func (c *GlobalConfig) HasIssueTypePrompts() bool {
+	return c != nil && len(c.IssueTypePrompts) > 0
+}
+
+// GetPreset returns the named preset's project config values as strings,
+// in the form `fab project config set` takes them. Lists are joined with
+// commas. ok is false if there is no such preset.
+func (c *GlobalConfig) GetPreset(name string) (values map[string]string, ok bool) {
+	if c == nil {
+		return nil, false
+	}
+	preset, ok := c.Presets[name]
+	if !ok {
+		return nil, false
+	}
+	values = make(map[string]string, len(preset))
+	for key, v := range preset {
+		if list, isList := v.([]any); isList {
+			items := make([]string, len(list))
+			for i, item := range list {
+				items[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(items, ",")
+			continue
+		}
+		values[key] = fmt.Sprint(v)
+	}
+	return values, true
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/tessro/fab/internal/notify"
 )
 
 // Validation errors.
@@ -23,6 +26,9 @@ var (
 	ErrEmptyPattern        = errors.New("pattern cannot be empty when specified")
 	ErrEmptyPatternElement = errors.New("patterns array contains empty element")
 	ErrScriptNotExecutable = errors.New("script is not executable")
+	ErrInvalidFallback     = errors.New("fallback must be 'hold' or 'deny'")
+	ErrInvalidPollInterval = errors.New("poll interval must be a duration of at least 2s")
+	ErrInvalidNotifyEvent  = errors.New("unknown notification event")
 )
 
 // Maximum project name length.
@@ -31,6 +37,10 @@ const MaxProjectNameLength = 64
 // Maximum max_agents value.
 const MaxMaxAgents = 100
 
+// MinPollInterval is the shortest allowed interval between the
+// orchestrator's checks for ready issues.
+const MinPollInterval = 2 * time.Second
+
 // validProjectNameRegex matches valid project names:
 // alphanumeric, dash, underscore, dot, no path separators.
 var validProjectNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
@@ -176,6 +186,37 @@ func ValidateMaxAgents(maxAgents int) error {
 	return nil
 }
 
+// ValidatePollInterval validates a poll-interval duration string.
+func ValidatePollInterval(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil || d < MinPollInterval {
+		return &ValidationError{
+			Field:   "poll-interval",
+			Value:   value,
+			Message: fmt.Sprintf("must be a duration of at least %s (e.g. '30s')", MinPollInterval),
+			Err:     ErrInvalidPollInterval,
+		}
+	}
+	return nil
+}
+
+// ValidateNotificationEvent validates a [notifications] events entry.
+func ValidateNotificationEvent(event string) error {
+	valid := make([]string, 0, len(notify.ValidEventTypes()))
+	for _, t := range notify.ValidEventTypes() {
+		if string(t) == event {
+			return nil
+		}
+		valid = append(valid, string(t))
+	}
+	return &ValidationError{
+		Field:   "notifications.events",
+		Value:   event,
+		Message: fmt.Sprintf("must be one of: %s", strings.Join(valid, ", ")),
+		Err:     ErrInvalidNotifyEvent,
+	}
+}
+
 // ValidateProjectEntry validates a complete project entry.
 func ValidateProjectEntry(name, remoteURL string, maxAgents int) error {
 	if err := ValidateProjectName(name); err != nil {
@@ -312,3 +353,35 @@ func ValidateManagerAllowedPatterns(patterns []string) error {
 	}
 	return nil
 }
+
+// ValidateHeadlessConfig validates the headless permission policy.
+// fallback may be empty (uses the default), "hold", or "deny".
+func ValidateHeadlessConfig(fallback string, patterns, tools []string) error {
+	if fallback != "" && fallback != "hold" && fallback != "deny" {
+		return &ValidationError{
+			Field:   "headless.fallback",
+			Value:   fallback,
+			Message: "must be 'hold' or 'deny'",
+			Err:     ErrInvalidFallback,
+		}
+	}
+	for i, p := range patterns {
+		if isEmptyOrWhitespace(p) {
+			return &ValidationError{
+				Field:   fmt.Sprintf("headless.allowed_patterns[%d]", i),
+				Message: "cannot be empty",
+				Err:     ErrEmptyPatternElement,
+			}
+		}
+	}
+	for i, tool := range tools {
+		if err := ValidateToolName(tool); err != nil {
+			var ve *ValidationError
+			if errors.As(err, &ve) {
+				ve.Field = fmt.Sprintf("headless.allowed_tools[%d]", i)
+			}
+			return err
+		}
+	}
+	return nil
+}
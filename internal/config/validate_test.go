@@ -129,6 +129,32 @@ func TestValidateMaxAgents(t *testing.T) {
 	}
 }
 
+func TestValidatePollInterval(t *testing.T) {
+	for _, value := range []string{"2s", "30s", "5m"} {
+		if err := ValidatePollInterval(value); err != nil {
+			t.Errorf("ValidatePollInterval(%q) = %v, want nil", value, err)
+		}
+	}
+	for _, value := range []string{"", "1s", "0", "-10s", "often"} {
+		if err := ValidatePollInterval(value); !errors.Is(err, ErrInvalidPollInterval) {
+			t.Errorf("ValidatePollInterval(%q) = %v, want ErrInvalidPollInterval", value, err)
+		}
+	}
+}
+
+func TestValidateNotificationEvent(t *testing.T) {
+	for _, value := range []string{"permission_request", "user_question", "agent_done"} {
+		if err := ValidateNotificationEvent(value); err != nil {
+			t.Errorf("ValidateNotificationEvent(%q) = %v, want nil", value, err)
+		}
+	}
+	for _, value := range []string{"", "agent-done", "permission", "AGENT_DONE"} {
+		if err := ValidateNotificationEvent(value); !errors.Is(err, ErrInvalidNotifyEvent) {
+			t.Errorf("ValidateNotificationEvent(%q) = %v, want ErrInvalidNotifyEvent", value, err)
+		}
+	}
+}
+
 func TestValidateProjectEntry(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -297,6 +323,36 @@ func TestValidatePatterns(t *testing.T) {
 	}
 }
 
+func TestValidateHeadlessConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback string
+		patterns []string
+		tools    []string
+		wantErr  error
+	}{
+		{"defaults", "", nil, nil, nil},
+		{"hold", "hold", []string{"go test:*"}, []string{"Read"}, nil},
+		{"deny", "deny", nil, []string{"Grep", "Glob"}, nil},
+		{"invalid fallback", "allow", nil, nil, ErrInvalidFallback},
+		{"empty pattern", "hold", []string{"go test:*", " "}, nil, ErrEmptyPatternElement},
+		{"invalid tool", "hold", nil, []string{"Read", "read"}, ErrInvalidToolName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHeadlessConfig(tt.fallback, tt.patterns, tt.tools)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("ValidateHeadlessConfig() = %v, want nil", err)
+				}
+			} else if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateHeadlessConfig() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateRule(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -55,6 +55,24 @@ const ConnectTimeout = 5 * time.Second
 // RequestTimeout is the default timeout for request/response operations.
 const RequestTimeout = 30 * time.Second
 
+// PermissionRequestTimeout is how long RequestPermission waits for an answer.
+// It matches the hook timeout, so held requests can be answered by a TUI that
+// attaches later.
+const PermissionRequestTimeout = 5 * time.Minute
+
+// agentDonePreMergeTimeout is the pre-merge timeout AgentDoneWithResponse
+// assumes when it can't look up the agent's project. It matches
+// project.DefaultPreMergeTimeout.
+const agentDonePreMergeTimeout = 10 * time.Minute
+
+// AgentDoneMargin is how much longer than the project's pre-merge timeout
+// AgentDoneWithResponse waits, covering the rebase, merge, and push.
+const AgentDoneMargin = 2 * time.Minute
+
+// ExecResponseMargin is how much longer than an exec command's timeout
+// AgentExec waits, covering the kill and the wait for its output pipes.
+const ExecResponseMargin = 15 * time.Second
+
 // Connect establishes a connection to the daemon.
 func (c *Client) Connect() error {
 	c.mu.Lock()
@@ -135,6 +153,11 @@ func decodePayload[T any](payload any) (*T, error) {
 // Send and RecvEvent are mutually exclusive - only one can run at a time.
 // On connection errors, the connection is closed so that IsConnected() returns false.
 func (c *Client) Send(req *Request) (*Response, error) {
+	return c.sendWithTimeout(req, RequestTimeout)
+}
+
+// sendWithTimeout is Send with a custom deadline for the request/response cycle.
+func (c *Client) sendWithTimeout(req *Request, timeout time.Duration) (*Response, error) {
 	// Get connection state under mu
 	c.mu.Lock()
 	if c.conn == nil {
@@ -156,7 +179,7 @@ func (c *Client) Send(req *Request) (*Response, error) {
 	defer c.ioMu.Unlock()
 
 	// Set deadline for this request/response cycle
-	if err := conn.SetDeadline(time.Now().Add(RequestTimeout)); err != nil {
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
 		c.closeConnLocked()
 		return nil, fmt.Errorf("set deadline: %w", err)
 	}
@@ -202,6 +225,35 @@ func (c *Client) Ping() (*PingResponse, error) {
 	return decodePayload[PingResponse](resp.Payload)
 }
 
+// Whoami reports the user, paths, version, and issue backend credentials
+// the daemon runs with.
+func (c *Client) Whoami() (*WhoamiResponse, error) {
+	resp, err := c.Send(&Request{Type: MsgWhoami})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("whoami", resp.Error)
+	}
+	return decodePayload[WhoamiResponse](resp.Payload)
+}
+
+// SetLogLevel changes the daemon's log level without restarting it.
+// An empty level reports the current level without changing it.
+func (c *Client) SetLogLevel(level string) (*SetLogLevelResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgSetLogLevel,
+		Payload: SetLogLevelRequest{Level: level},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("log.level", resp.Error)
+	}
+	return decodePayload[SetLogLevelResponse](resp.Payload)
+}
+
 // Shutdown requests the daemon to shut down.
 // If stopHost is true, also stops the agent host process.
 func (c *Client) Shutdown(stopHost bool) error {
@@ -230,41 +282,48 @@ func (c *Client) Status() (*StatusResponse, error) {
 	return decodePayload[StatusResponse](resp.Payload)
 }
 
-// Start starts orchestration for a project.
-func (c *Client) Start(project string, all bool) error {
+// Start starts orchestration for a project, or all projects if all is set.
+// With all, per-project failures are reported in the results rather than as an error.
+func (c *Client) Start(project string, all bool) (*StartResponse, error) {
 	resp, err := c.Send(&Request{
 		Type:    MsgStart,
 		Payload: StartRequest{Project: project, All: all},
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !resp.Success {
-		return NewServerError("start", resp.Error)
+		return nil, NewServerError("start", resp.Error)
 	}
-	return nil
+	return decodePayload[StartResponse](resp.Payload)
 }
 
-// Stop stops orchestration for a project.
-func (c *Client) Stop(project string, all bool) error {
+// Stop stops orchestration for a project, or all projects if all is set.
+func (c *Client) Stop(project string, all bool) (*StopResponse, error) {
 	resp, err := c.Send(&Request{
 		Type:    MsgStop,
 		Payload: StopRequest{Project: project, All: all},
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !resp.Success {
-		return NewServerError("stop", resp.Error)
+		return nil, NewServerError("stop", resp.Error)
 	}
-	return nil
+	return decodePayload[StopResponse](resp.Payload)
 }
 
 // ProjectAdd adds a project to the daemon.
 func (c *Client) ProjectAdd(remoteURL, name string, maxAgents int, autostart bool, backend string) (*ProjectAddResponse, error) {
+	return c.ProjectAddWithConfig(ProjectAddRequest{RemoteURL: remoteURL, Name: name, MaxAgents: maxAgents, Autostart: autostart, Backend: backend})
+}
+
+// ProjectAddWithConfig adds a project and applies a preset and inline
+// config values to it.
+func (c *Client) ProjectAddWithConfig(addReq ProjectAddRequest) (*ProjectAddResponse, error) {
 	resp, err := c.Send(&Request{
 		Type:    MsgProjectAdd,
-		Payload: ProjectAddRequest{RemoteURL: remoteURL, Name: name, MaxAgents: maxAgents, Autostart: autostart, Backend: backend},
+		Payload: addReq,
 	})
 	if err != nil {
 		return nil, err
@@ -275,11 +334,12 @@ func (c *Client) ProjectAdd(remoteURL, name string, maxAgents int, autostart boo
 	return decodePayload[ProjectAddResponse](resp.Payload)
 }
 
-// ProjectRemove removes a project from the daemon.
-func (c *Client) ProjectRemove(name string, deleteWorktrees bool) error {
+// ProjectRemove removes a project from the daemon. A running project is
+// only removed with force, which stops it first.
+func (c *Client) ProjectRemove(name string, deleteWorktrees, force bool) error {
 	resp, err := c.Send(&Request{
 		Type:    MsgProjectRemove,
-		Payload: ProjectRemoveRequest{Name: name, DeleteWorktrees: deleteWorktrees},
+		Payload: ProjectRemoveRequest{Name: name, DeleteWorktrees: deleteWorktrees, Force: force},
 	})
 	if err != nil {
 		return err
@@ -363,11 +423,49 @@ func (c *Client) ProjectConfigSet(name, key, value string) error {
 	return nil
 }
 
+// ProjectLog returns the newest limit commits on a project's default branch
+// that landed between since and until (zero for no bound), fetching origin
+// first if fetch is set.
+func (c *Client) ProjectLog(name string, limit int, since, until time.Time, fetch bool) (*ProjectLogResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgProjectLog,
+		Payload: ProjectLogRequest{Name: name, Limit: limit, Since: since, Until: until, Fetch: fetch},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("project log", resp.Error)
+	}
+	return decodePayload[ProjectLogResponse](resp.Payload)
+}
+
+// ProjectPull fetches origin and fast-forwards the project's main clone.
+func (c *Client) ProjectPull(name string) (*ProjectPullResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgProjectPull,
+		Payload: ProjectPullRequest{Name: name},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("project pull", resp.Error)
+	}
+	return decodePayload[ProjectPullResponse](resp.Payload)
+}
+
 // AgentList lists agents, optionally filtered by project.
 func (c *Client) AgentList(project string) (*AgentListResponse, error) {
+	return c.AgentListSorted(project, "", "")
+}
+
+// AgentListSorted lists agents sorted by sortBy (see AgentSortState) in the
+// given order, optionally filtered by project.
+func (c *Client) AgentListSorted(project, sortBy, order string) (*AgentListResponse, error) {
 	resp, err := c.Send(&Request{
 		Type:    MsgAgentList,
-		Payload: AgentListRequest{Project: project},
+		Payload: AgentListRequest{Project: project, SortBy: sortBy, Order: order},
 	})
 	if err != nil {
 		return nil, err
@@ -379,10 +477,11 @@ func (c *Client) AgentList(project string) (*AgentListResponse, error) {
 }
 
 // AgentCreate creates a new agent for a project.
-func (c *Client) AgentCreate(project, task string) (*AgentCreateResponse, error) {
+// An empty model uses the project's configured model.
+func (c *Client) AgentCreate(project, task, model string) (*AgentCreateResponse, error) {
 	resp, err := c.Send(&Request{
 		Type:    MsgAgentCreate,
-		Payload: AgentCreateRequest{Project: project, Task: task},
+		Payload: AgentCreateRequest{Project: project, Task: task, Model: model},
 	})
 	if err != nil {
 		return nil, err
@@ -393,6 +492,117 @@ func (c *Client) AgentCreate(project, task string) (*AgentCreateResponse, error)
 	return decodePayload[AgentCreateResponse](resp.Payload)
 }
 
+// AgentReplay starts a new agent that replays the user turns of an existing
+// or deleted agent. If project is empty, the source agent's project is used.
+func (c *Client) AgentReplay(id, project string) (*AgentReplayResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgAgentReplay,
+		Payload: AgentReplayRequest{ID: id, Project: project},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("agent replay", resp.Error)
+	}
+	return decodePayload[AgentReplayResponse](resp.Payload)
+}
+
+// AgentCompact asks an idle agent to summarize its conversation, then
+// replaces its chat history with the summary. The full history is saved to
+// the transcript store first.
+func (c *Client) AgentCompact(id string) (*AgentCompactResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgAgentCompact,
+		Payload: AgentCompactRequest{ID: id},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("agent compact", resp.Error)
+	}
+	return decodePayload[AgentCompactResponse](resp.Payload)
+}
+
+// AgentRecover restores work stashed by a forced abort into a new branch.
+func (c *Client) AgentRecover(id, project, branch string) (*AgentRecoverResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgAgentRecover,
+		Payload: AgentRecoverRequest{ID: id, Project: project, Branch: branch},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("agent recover", resp.Error)
+	}
+	return decodePayload[AgentRecoverResponse](resp.Payload)
+}
+
+// AgentDebugCapture turns capture of an agent's raw CLI output on or off.
+func (c *Client) AgentDebugCapture(id string, enabled bool) (*AgentDebugCaptureResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgAgentDebugCapture,
+		Payload: AgentDebugCaptureRequest{ID: id, Enabled: enabled},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("agent debug capture", resp.Error)
+	}
+	return decodePayload[AgentDebugCaptureResponse](resp.Payload)
+}
+
+// AgentTranscriptExport renders an agent's chat history as markdown.
+// If write is set, the transcript is also saved to path in the agent's
+// worktree (empty path uses the default file name).
+func (c *Client) AgentTranscriptExport(id string, write bool, path string) (*AgentTranscriptExportResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgAgentTranscriptExport,
+		Payload: AgentTranscriptExportRequest{ID: id, Write: write, Path: path},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("agent transcript export", resp.Error)
+	}
+	return decodePayload[AgentTranscriptExportResponse](resp.Payload)
+}
+
+// AgentGrep searches an agent's chat history for entries matching pattern,
+// optionally only those of one tool, with context entries around each match.
+func (c *Client) AgentGrep(id, pattern, tool string, contextEntries, limit int) (*AgentGrepResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgAgentGrep,
+		Payload: AgentGrepRequest{ID: id, Pattern: pattern, Tool: tool, Context: contextEntries, Limit: limit},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("agent grep", resp.Error)
+	}
+	return decodePayload[AgentGrepResponse](resp.Payload)
+}
+
+// AgentDelegate spawns an agent with the ticket already claimed for it.
+func (c *Client) AgentDelegate(ticketID, project, delegatedBy string) (*AgentDelegateResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgAgentDelegate,
+		Payload: AgentDelegateRequest{TicketID: ticketID, Project: project, DelegatedBy: delegatedBy},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("agent delegate", resp.Error)
+	}
+	return decodePayload[AgentDelegateResponse](resp.Payload)
+}
+
 // AgentDelete deletes an agent.
 func (c *Client) AgentDelete(id string, force bool) error {
 	resp, err := c.Send(&Request{
@@ -462,26 +672,50 @@ func (c *Client) AgentDone(agentID, taskID, errorMsg string) error {
 }
 
 // AgentDoneWithResponse signals that an agent has completed its task and returns the response.
-// This is called by agents to notify the orchestrator they are done.
+// This is called by agents to notify the orchestrator they are done. When the merge fails,
+// the response is returned along with the error, so callers can show its Conflicts.
+// The daemon runs the project's pre-merge command before answering, so this waits
+// for the project's pre-merge timeout plus AgentDoneMargin.
 func (c *Client) AgentDoneWithResponse(agentID, taskID, errorMsg string) (*AgentDoneResponse, error) {
-	resp, err := c.Send(&Request{
+	resp, err := c.sendWithTimeout(&Request{
 		Type: MsgAgentDone,
 		Payload: AgentDoneRequest{
 			AgentID: agentID,
 			TaskID:  taskID,
 			Error:   errorMsg,
 		},
-	})
+	}, c.agentDoneTimeout(agentID))
 	if err != nil {
 		return nil, err
 	}
 	if !resp.Success {
-		return nil, NewServerError("agent done", resp.Error)
+		var done *AgentDoneResponse
+		if resp.Payload != nil {
+			done, _ = decodePayload[AgentDoneResponse](resp.Payload)
+		}
+		return done, NewServerError("agent done", resp.Error)
 	}
 
 	return decodePayload[AgentDoneResponse](resp.Payload)
 }
 
+// agentDoneTimeout returns how long to wait for agent.done: the pre-merge
+// timeout of the agent's project plus AgentDoneMargin. Planners and agents
+// whose project can't be looked up get the default pre-merge timeout.
+func (c *Client) agentDoneTimeout(agentID string) time.Duration {
+	preMerge := agentDonePreMergeTimeout
+	if got, err := c.AgentGet(agentID); err == nil {
+		if cfg, err := c.ProjectConfigGet(got.Agent.Project, "pre-merge-timeout"); err == nil {
+			if s, ok := cfg.Value.(string); ok {
+				if d, err := time.ParseDuration(s); err == nil && d > 0 {
+					preMerge = d
+				}
+			}
+		}
+	}
+	return preMerge + AgentDoneMargin
+}
+
 // AgentClaim claims a ticket for an agent to prevent duplicate work.
 // Returns an error if the ticket is already claimed by another agent.
 func (c *Client) AgentClaim(agentID, ticketID string) error {
@@ -516,6 +750,70 @@ func (c *Client) ClaimList(project string) (*ClaimListResponse, error) {
 	return decodePayload[ClaimListResponse](resp.Payload)
 }
 
+// ClaimRelease releases the claim on a ticket, whichever agent holds it.
+// project may be empty unless the ticket is claimed in several projects.
+func (c *Client) ClaimRelease(ticketID, project string) (*ClaimInfo, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgClaimRelease,
+		Payload: ClaimReleaseRequest{TicketID: ticketID, Project: project},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("claim release", resp.Error)
+	}
+	return decodePayload[ClaimInfo](resp.Payload)
+}
+
+// NoteAppend appends a note to a project's shared notes. If project is
+// empty, the note goes to the project of agentID.
+func (c *Client) NoteAppend(agentID, project, text string) error {
+	resp, err := c.Send(&Request{
+		Type:    MsgNoteAppend,
+		Payload: NoteAppendRequest{AgentID: agentID, Project: project, Text: text},
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return NewServerError("note append", resp.Error)
+	}
+	return nil
+}
+
+// NoteRead returns a project's shared notes. If project is empty, the notes
+// of agentID's project are returned.
+func (c *Client) NoteRead(agentID, project string) (*NoteReadResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgNoteRead,
+		Payload: NoteReadRequest{AgentID: agentID, Project: project},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("note read", resp.Error)
+	}
+	return decodePayload[NoteReadResponse](resp.Payload)
+}
+
+// Timeline returns a project's activity timeline events at or after since
+// (zero for all), keeping the newest limit (0 for the daemon's default).
+func (c *Client) Timeline(project string, since time.Time, limit int) (*TimelineResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgTimeline,
+		Payload: TimelineRequest{Project: project, Since: since, Limit: limit},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("timeline", resp.Error)
+	}
+	return decodePayload[TimelineResponse](resp.Payload)
+}
+
 // AgentSendMessage sends a user message to an agent via stream-json.
 func (c *Client) AgentSendMessage(id, content string) error {
 	resp, err := c.Send(&Request{
@@ -546,6 +844,74 @@ func (c *Client) AgentDescribe(agentID, description string) error {
 	return nil
 }
 
+// AgentRename sets the human-friendly name for an agent.
+func (c *Client) AgentRename(agentID, name string) error {
+	resp, err := c.Send(&Request{
+		Type:    MsgAgentRename,
+		Payload: AgentRenameRequest{AgentID: agentID, Name: name},
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return NewServerError("agent rename", resp.Error)
+	}
+	return nil
+}
+
+// AgentLineage returns the tree of which agent spawned which, optionally
+// filtered by project.
+func (c *Client) AgentLineage(project string) (*AgentLineageResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgAgentLineage,
+		Payload: AgentLineageRequest{Project: project},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("agent lineage", resp.Error)
+	}
+	return decodePayload[AgentLineageResponse](resp.Payload)
+}
+
+// AgentGet returns one agent's detailed state.
+func (c *Client) AgentGet(id string) (*AgentGetResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgAgentGet,
+		Payload: AgentGetRequest{ID: id},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("agent get", resp.Error)
+	}
+	return decodePayload[AgentGetResponse](resp.Payload)
+}
+
+// AgentExec runs a shell command in an agent's worktree and returns its output.
+// The daemon must have exec enabled in its config. The command is killed after
+// timeout, or the daemon's exec.timeout if that is shorter; a non-positive
+// timeout uses the daemon's. This waits ExecResponseMargin past the timeout so
+// the output of a killed command still arrives.
+func (c *Client) AgentExec(agentID, command string, timeout time.Duration) (*AgentExecResponse, error) {
+	execReq := AgentExecRequest{ID: agentID, Command: command}
+	wait := RequestTimeout
+	if timeout > 0 {
+		execReq.Timeout = timeout.String()
+		wait = timeout + ExecResponseMargin
+	}
+	resp, err := c.sendWithTimeout(&Request{Type: MsgAgentExec, Payload: execReq}, wait)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("agent exec", resp.Error)
+	}
+	return decodePayload[AgentExecResponse](resp.Payload)
+}
+
 // NotifyIdle notifies the daemon that an agent has gone idle (finished responding).
 // Called by the Stop hook when Claude Code completes a response.
 func (c *Client) NotifyIdle(agentID string) error {
@@ -563,10 +929,12 @@ func (c *Client) NotifyIdle(agentID string) error {
 }
 
 // AgentChatHistory retrieves the chat history for an agent.
-func (c *Client) AgentChatHistory(id string, limit int) (*AgentChatHistoryResponse, error) {
+// It returns up to limit entries preceding the before cursor (empty = most recent);
+// the response's NextCursor fetches the next older page.
+func (c *Client) AgentChatHistory(id string, limit int, before string) (*AgentChatHistoryResponse, error) {
 	resp, err := c.Send(&Request{
 		Type:    MsgAgentChatHistory,
-		Payload: AgentChatHistoryRequest{ID: id, Limit: limit},
+		Payload: AgentChatHistoryRequest{ID: id, Limit: limit, Before: before},
 	})
 	if err != nil {
 		return nil, err
@@ -581,10 +949,16 @@ func (c *Client) AgentChatHistory(id string, limit int) (*AgentChatHistoryRespon
 // This is called by the fab hook command when Claude Code needs tool permission.
 // The method blocks until the TUI user approves or denies the request.
 func (c *Client) RequestPermission(req *PermissionRequestPayload) (*PermissionResponse, error) {
-	resp, err := c.Send(&Request{
+	return c.RequestPermissionWithTimeout(req, PermissionRequestTimeout)
+}
+
+// RequestPermissionWithTimeout is like RequestPermission, but waits up to
+// timeout for an answer. It should match the daemon's timeout for the tool.
+func (c *Client) RequestPermissionWithTimeout(req *PermissionRequestPayload, timeout time.Duration) (*PermissionResponse, error) {
+	resp, err := c.sendWithTimeout(&Request{
 		Type:    MsgPermissionRequest,
 		Payload: req,
-	})
+	}, timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -596,7 +970,8 @@ func (c *Client) RequestPermission(req *PermissionRequestPayload) (*PermissionRe
 
 // RespondPermission sends a response to a pending permission request.
 // Called by the TUI when the user approves or denies a permission.
-func (c *Client) RespondPermission(id, behavior, message string, interrupt bool) error {
+// If remember is set, the daemon also saves the decision as a project rule.
+func (c *Client) RespondPermission(id, behavior, message string, interrupt, remember bool) error {
 	resp, err := c.Send(&Request{
 		Type: MsgPermissionRespond,
 		Payload: PermissionRespondPayload{
@@ -604,6 +979,7 @@ func (c *Client) RespondPermission(id, behavior, message string, interrupt bool)
 			Behavior:  behavior,
 			Message:   message,
 			Interrupt: interrupt,
+			Remember:  remember,
 		},
 	})
 	if err != nil {
@@ -666,6 +1042,23 @@ func (c *Client) ListPendingPermissions(project string) (*PermissionListResponse
 	return decodePayload[PermissionListResponse](resp.Payload)
 }
 
+// Dashboard fetches the agents, planners, status, claims, and pending
+// permissions the TUI shows at startup in one request, plus the status of
+// the given project's manager if manager is non-empty.
+func (c *Client) Dashboard(sortBy, order, manager string) (*DashboardResponse, error) {
+	resp, err := c.Send(&Request{
+		Type:    MsgDashboard,
+		Payload: DashboardRequest{SortBy: sortBy, Order: order, Manager: manager},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("dashboard", resp.Error)
+	}
+	return decodePayload[DashboardResponse](resp.Payload)
+}
+
 // Attach subscribes to streaming events.
 // After calling Attach, use RecvEvent to receive events.
 func (c *Client) Attach(projects []string) error {
@@ -758,6 +1151,27 @@ type EventResult struct {
 // This is preferred over RecvEvent as it uses a dedicated connection and doesn't require
 // timeout-based polling.
 func (c *Client) StreamEvents(projects []string) (<-chan EventResult, error) {
+	return c.openStream("attach", &Request{
+		ID:      "event-stream",
+		Type:    MsgAttach,
+		Payload: AttachRequest{Projects: projects},
+	})
+}
+
+// StreamLogs opens a dedicated connection that receives daemon log records
+// at or above level as "log" stream events. It shares the event stream slot,
+// so StopEventStream ends it and it replaces any stream opened by StreamEvents.
+func (c *Client) StreamLogs(level string) (<-chan EventResult, error) {
+	return c.openStream("log subscribe", &Request{
+		ID:      "log-stream",
+		Type:    MsgLogSubscribe,
+		Payload: LogSubscribeRequest{Level: level},
+	})
+}
+
+// openStream dials a dedicated connection, sends req, and on success starts
+// a reader goroutine that delivers stream events until StopEventStream is called.
+func (c *Client) openStream(op string, req *Request) (<-chan EventResult, error) {
 	c.eventMu.Lock()
 	defer c.eventMu.Unlock()
 
@@ -778,26 +1192,21 @@ func (c *Client) StreamEvents(projects []string) (<-chan EventResult, error) {
 	encoder := json.NewEncoder(conn)
 	decoder := json.NewDecoder(conn)
 
-	// Send attach request on this connection
-	req := &Request{
-		ID:      "event-stream",
-		Type:    MsgAttach,
-		Payload: AttachRequest{Projects: projects},
-	}
+	// Send subscription request on this connection
 	if err := encoder.Encode(req); err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("encode attach request: %w", err)
+		return nil, fmt.Errorf("encode %s request: %w", op, err)
 	}
 
-	// Wait for attach response
+	// Wait for subscription response
 	var resp Response
 	if err := decoder.Decode(&resp); err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("decode attach response: %w", err)
+		return nil, fmt.Errorf("decode %s response: %w", op, err)
 	}
 	if !resp.Success {
 		conn.Close()
-		return nil, NewServerError("attach", resp.Error)
+		return nil, NewServerError(op, resp.Error)
 	}
 
 	// Store connection and done channel
@@ -901,6 +1310,19 @@ func (c *Client) ManagerStatus(project string) (*ManagerStatusResponse, error) {
 	return decodePayload[ManagerStatusResponse](resp.Payload)
 }
 
+// ManagerListAll returns the status of every project's manager that has
+// been started since the daemon came up.
+func (c *Client) ManagerListAll() (*ManagerListResponse, error) {
+	resp, err := c.Send(&Request{Type: MsgManagerList})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, NewServerError("manager list", resp.Error)
+	}
+	return decodePayload[ManagerListResponse](resp.Payload)
+}
+
 // ManagerSendMessage sends a message to the manager agent for a project.
 func (c *Client) ManagerSendMessage(project, content string) error {
 	resp, err := c.Send(&Request{
@@ -978,9 +1400,14 @@ func (c *Client) PlanStop(id string) error {
 
 // PlanList lists planning agents.
 func (c *Client) PlanList(project string) (*PlanListResponse, error) {
+	return c.PlanListFiltered(PlanListRequest{Project: project})
+}
+
+// PlanListFiltered lists planning agents matching the request's filters.
+func (c *Client) PlanListFiltered(listReq PlanListRequest) (*PlanListResponse, error) {
 	resp, err := c.Send(&Request{
 		Type:    MsgPlanList,
-		Payload: PlanListRequest{Project: project},
+		Payload: listReq,
 	})
 	if err != nil {
 		return nil, err
@@ -300,14 +300,18 @@ func TestClient_StartStop(t *testing.T) {
 				return &Response{Success: false, Error: err.Error()}
 			}
 			lastStart = payload.Project
-			return &Response{Success: true}
+			return &Response{Success: true, Payload: StartResponse{
+				Results: []ProjectStartResult{{Project: payload.Project, Started: true}},
+			}}
 		case MsgStop:
 			payload, err := decodePayload[StopRequest](req.Payload)
 			if err != nil {
 				return &Response{Success: false, Error: err.Error()}
 			}
 			lastStop = payload.Project
-			return &Response{Success: true}
+			return &Response{Success: true, Payload: StopResponse{
+				Results: []ProjectStopResult{{Project: payload.Project, Stopped: true}},
+			}}
 		}
 		return &Response{Success: false, Error: "unknown"}
 	})
@@ -324,19 +328,27 @@ func TestClient_StartStop(t *testing.T) {
 	}
 	defer c.Close()
 
-	if err := c.Start("my-project", false); err != nil {
+	startResp, err := c.Start("my-project", false)
+	if err != nil {
 		t.Fatalf("start: %v", err)
 	}
 	if lastStart != "my-project" {
 		t.Errorf("expected project my-project, got %s", lastStart)
 	}
+	if len(startResp.Results) != 1 || !startResp.Results[0].Started {
+		t.Errorf("unexpected start results: %+v", startResp.Results)
+	}
 
-	if err := c.Stop("my-project", false); err != nil {
+	stopResp, err := c.Stop("my-project", false)
+	if err != nil {
 		t.Fatalf("stop: %v", err)
 	}
 	if lastStop != "my-project" {
 		t.Errorf("expected project my-project, got %s", lastStop)
 	}
+	if len(stopResp.Results) != 1 || !stopResp.Results[0].Stopped {
+		t.Errorf("unexpected stop results: %+v", stopResp.Results)
+	}
 }
 
 func TestClient_ProjectOperations(t *testing.T) {
@@ -407,7 +419,7 @@ func TestClient_ProjectOperations(t *testing.T) {
 	})
 
 	t.Run("remove", func(t *testing.T) {
-		if err := c.ProjectRemove("test-proj", true); err != nil {
+		if err := c.ProjectRemove("test-proj", true, false); err != nil {
 			t.Fatalf("project remove: %v", err)
 		}
 	})
@@ -477,7 +489,7 @@ func TestClient_AgentOperations(t *testing.T) {
 	})
 
 	t.Run("create", func(t *testing.T) {
-		result, err := c.AgentCreate("proj1", "task-123")
+		result, err := c.AgentCreate("proj1", "task-123", "")
 		if err != nil {
 			t.Fatalf("agent create: %v", err)
 		}
@@ -620,6 +632,87 @@ func TestClient_RecvEvent(t *testing.T) {
 	}
 }
 
+func TestClient_AttachReplaysRecentEvents(t *testing.T) {
+	tmpDir, cleanup := shortClientTempDir(t)
+	defer cleanup()
+	sockPath := filepath.Join(tmpDir, "test.sock")
+
+	handler := HandlerFunc(func(ctx context.Context, req *Request) *Response {
+		if req.Type == MsgAttach {
+			payload, err := decodePayload[AttachRequest](req.Payload)
+			if err != nil {
+				return &Response{Success: false, Error: err.Error()}
+			}
+			ServerFromContext(ctx).Attach(ConnFromContext(ctx), payload.Projects, EncoderFromContext(ctx), WriteMuFromContext(ctx))
+			return &Response{Success: true}
+		}
+		return &Response{Success: false, Error: "unknown"}
+	})
+
+	srv := NewServer(sockPath, handler)
+	srv.SetReplaySize(2)
+	srv.SetReplayFilter(func(ev *StreamEvent) bool { return ev.Type != "permission_request" })
+	if err := srv.Start(); err != nil {
+		t.Fatalf("server start: %v", err)
+	}
+	defer func() { _ = srv.Stop() }()
+
+	// Broadcast before anyone attaches
+	for _, ev := range []*StreamEvent{
+		{Type: "state", AgentID: "a1", Project: "proj-a", State: "starting"},
+		{Type: "state", AgentID: "a1", Project: "proj-a", State: "running"},
+		{Type: "state", AgentID: "b1", Project: "proj-b", State: "running"},
+		{Type: "chat_delta", AgentID: "a1", Project: "proj-a", Data: "partial"},
+		{Type: "permission_request", AgentID: "a1", Project: "proj-a"},
+		{Type: "state", AgentID: "a1", Project: "proj-a", State: "idle"},
+	} {
+		srv.Broadcast(ev)
+	}
+
+	c := NewClient(sockPath)
+	if err := c.Connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.Attach([]string{"proj-a"}); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+
+	// Only the newest 2 events for proj-a are kept; the filter then drops the permission request
+	event, err := c.RecvEvent()
+	if err != nil {
+		t.Fatalf("recv event: %v", err)
+	}
+	if event.Project != "proj-a" || event.State != "idle" {
+		t.Errorf("replayed event = %+v, want proj-a idle state", event)
+	}
+
+	// Live events follow the replay
+	srv.Broadcast(&StreamEvent{Type: "output", AgentID: "a1", Project: "proj-a", Data: "live"})
+	event, err = c.RecvEvent()
+	if err != nil {
+		t.Fatalf("recv event: %v", err)
+	}
+	if event.Data != "live" {
+		t.Errorf("event after replay = %+v, want the live output", event)
+	}
+}
+
+func TestServer_SetReplaySizeZeroDisablesReplay(t *testing.T) {
+	srv := NewServer("", nil)
+	srv.SetReplaySize(10)
+	srv.Broadcast(&StreamEvent{Type: "state", AgentID: "a1", Project: "proj", State: "running"})
+	srv.SetReplaySize(0)
+	srv.Broadcast(&StreamEvent{Type: "state", AgentID: "a1", Project: "proj", State: "idle"})
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if events := srv.replayFor(nil); len(events) != 0 {
+		t.Errorf("replayFor() = %v, want none with replay disabled", events)
+	}
+}
+
 func TestClient_Shutdown(t *testing.T) {
 	tmpDir, cleanup := shortClientTempDir(t)
 	defer cleanup()
@@ -686,10 +779,10 @@ func TestClient_ErrorResponses(t *testing.T) {
 	if _, err := c.Status(); err == nil {
 		t.Error("expected error from Status")
 	}
-	if err := c.Start("p", false); err == nil {
+	if _, err := c.Start("p", false); err == nil {
 		t.Error("expected error from Start")
 	}
-	if err := c.Stop("p", false); err == nil {
+	if _, err := c.Stop("p", false); err == nil {
 		t.Error("expected error from Stop")
 	}
 }
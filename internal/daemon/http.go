@@ -0,0 +1,116 @@
+package daemon
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tessro/fab/internal/logging"
+)
+
+// httpReadHeaderTimeout bounds how long the HTTP listener waits for headers.
+const httpReadHeaderTimeout = 10 * time.Second
+
+// StartHTTP starts the optional HTTP listener on addr. It serves the
+// WebSocket event bridge at /events, which forwards StreamEvents as JSON
+// text frames. Browser origins other than the listener's own must appear
+// in allowedOrigins ("*" allows any). If a metrics handler was set, it is
+// served at /metrics. If token is set, every request must present it (see
+// requireToken); without one, addr must be a loopback address. The
+// listener is closed by Stop.
+func (s *Server) StartHTTP(addr string, allowedOrigins []string, token string) error {
+	s.mu.Lock()
+	if s.httpServer != nil {
+		s.mu.Unlock()
+		return errors.New("http listener already started")
+	}
+	metricsHandler := s.metricsHandler
+	s.mu.Unlock()
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", addr, err)
+	}
+	if token == "" && !isLoopback(listener.Addr()) {
+		listener.Close()
+		return fmt.Errorf("refusing to listen on non-loopback address %s without a token", addr)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", s.handleWebSocket)
+	if metricsHandler != nil {
+		mux.Handle("/metrics", metricsHandler)
+	}
+	var handler http.Handler = mux
+	if token != "" {
+		handler = requireToken(token, mux)
+	}
+	httpServer := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: httpReadHeaderTimeout,
+	}
+
+	s.mu.Lock()
+	s.httpServer = httpServer
+	s.httpAddr = listener.Addr().String()
+	s.allowedOrigins = allowedOrigins
+	s.mu.Unlock()
+
+	slog.Info("http listener started", "addr", listener.Addr().String())
+
+	go func() {
+		defer logging.LogPanic("daemon-http-server", nil)
+		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("http listener failed", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// isLoopback reports whether a listener address only accepts local connections.
+func isLoopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
+
+// requireToken wraps h so requests must present token, either as an
+// "Authorization: Bearer" header or, for browser WebSocket clients that
+// can't set headers, as the token query parameter.
+func requireToken(token string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			got = r.URL.Query().Get("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// SetMetricsHandler sets the handler served at /metrics by the HTTP
+// listener. Must be called before StartHTTP.
+func (s *Server) SetMetricsHandler(h http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metricsHandler = h
+}
+
+// HTTPAddr returns the HTTP listener address, or empty string if not started.
+func (s *Server) HTTPAddr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.httpServer == nil {
+		return ""
+	}
+	return s.httpAddr
+}
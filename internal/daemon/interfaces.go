@@ -8,15 +8,20 @@ type TUIClient interface {
 	Connect() error
 	Close() error
 	IsConnected() bool
+	Ping() (*PingResponse, error)
+	Status() (*StatusResponse, error)
+	Dashboard(sortBy, order, manager string) (*DashboardResponse, error)
 
 	// Event streaming
 	StreamEvents(projects []string) (<-chan EventResult, error)
 	StopEventStream()
 
 	// Agent operations
-	AgentList(project string) (*AgentListResponse, error)
+	AgentListSorted(project, sortBy, order string) (*AgentListResponse, error)
+	AgentGet(id string) (*AgentGetResponse, error)
+	AgentCreate(project, task, model string) (*AgentCreateResponse, error)
 	AgentSendMessage(id, content string) error
-	AgentChatHistory(id string, limit int) (*AgentChatHistoryResponse, error)
+	AgentChatHistory(id string, limit int, before string) (*AgentChatHistoryResponse, error)
 	AgentAbort(id string, force bool) error
 
 	// Manager operations
@@ -24,6 +29,7 @@ type TUIClient interface {
 	ManagerChatHistory(project string, limit int) (*ManagerChatHistoryResponse, error)
 	ManagerClearHistory(project string) error
 	ManagerStop(project string) error
+	ManagerStatus(project string) (*ManagerStatusResponse, error)
 
 	// Planner operations
 	PlanStart(project, prompt string) (*PlanStartResponse, error)
@@ -33,15 +39,16 @@ type TUIClient interface {
 	PlanChatHistory(id string, limit int) (*PlanChatHistoryResponse, error)
 
 	// Approval operations
-	RespondPermission(id, behavior, message string, interrupt bool) error
+	ListPendingPermissions(project string) (*PermissionListResponse, error)
+	RespondPermission(id, behavior, message string, interrupt, remember bool) error
 	RespondUserQuestion(id string, answers map[string]string) error
 
 	// Project operations
 	ProjectList() (*ProjectListResponse, error)
 
 	// Supervisor operations
-	Start(project string, all bool) error
-	Stop(project string, all bool) error
+	Start(project string, all bool) (*StartResponse, error)
+	Stop(project string, all bool) (*StopResponse, error)
 
 	// Director operations
 	DirectorStart() error
@@ -0,0 +1,45 @@
+package daemon
+
+// BuildLineage arranges agents into trees by SpawnedBy. An agent whose
+// spawner is another listed agent becomes that agent's child; every other
+// agent is a root. Input order is preserved among siblings.
+func BuildLineage(agents []AgentStatus) []LineageNode {
+	listed := make(map[string]bool, len(agents))
+	for _, a := range agents {
+		listed[a.ID] = true
+	}
+
+	children := make(map[string][]AgentStatus)
+	var roots []AgentStatus
+	for _, a := range agents {
+		if a.SpawnedBy != "" && a.SpawnedBy != a.ID && listed[a.SpawnedBy] {
+			children[a.SpawnedBy] = append(children[a.SpawnedBy], a)
+		} else {
+			roots = append(roots, a)
+		}
+	}
+
+	var build func(a AgentStatus) LineageNode
+	build = func(a AgentStatus) LineageNode {
+		node := LineageNode{
+			ID:        a.ID,
+			Name:      a.Name,
+			Project:   a.Project,
+			State:     a.State,
+			SpawnedBy: a.SpawnedBy,
+		}
+		kids := children[a.ID]
+		// Each agent is visited at most once, which also breaks any cycle
+		delete(children, a.ID)
+		for _, c := range kids {
+			node.Children = append(node.Children, build(c))
+		}
+		return node
+	}
+
+	nodes := make([]LineageNode, 0, len(roots))
+	for _, a := range roots {
+		nodes = append(nodes, build(a))
+	}
+	return nodes
+}
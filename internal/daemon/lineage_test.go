@@ -0,0 +1,37 @@
+package daemon
+
+import "testing"
+
+func TestBuildLineage(t *testing.T) {
+	agents := []AgentStatus{
+		{ID: "a1", SpawnedBy: "orchestrator"},
+		{ID: "a2", SpawnedBy: "a1"},
+		{ID: "a3", SpawnedBy: "manager"},
+		{ID: "a4", SpawnedBy: "a1"},
+		{ID: "a5", SpawnedBy: "a2"},
+		{ID: "a6", SpawnedBy: "gone"}, // Parent no longer listed
+		{ID: "a7", SpawnedBy: "a8"},   // A cycle can't come from real IDs, but must not hang
+		{ID: "a8", SpawnedBy: "a7"},
+	}
+
+	roots := BuildLineage(agents)
+
+	var ids []string
+	for _, r := range roots {
+		ids = append(ids, r.ID)
+	}
+	if len(ids) != 3 || ids[0] != "a1" || ids[1] != "a3" || ids[2] != "a6" {
+		t.Fatalf("roots = %v, want [a1 a3 a6]", ids)
+	}
+
+	a1 := roots[0]
+	if len(a1.Children) != 2 || a1.Children[0].ID != "a2" || a1.Children[1].ID != "a4" {
+		t.Fatalf("a1 children = %+v, want [a2 a4]", a1.Children)
+	}
+	if kids := a1.Children[0].Children; len(kids) != 1 || kids[0].ID != "a5" {
+		t.Errorf("a2 children = %+v, want [a5]", kids)
+	}
+	if roots[2].SpawnedBy != "gone" {
+		t.Errorf("orphan SpawnedBy = %q, want %q", roots[2].SpawnedBy, "gone")
+	}
+}
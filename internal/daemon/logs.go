@@ -0,0 +1,130 @@
+package daemon
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/tessro/fab/internal/logging"
+)
+
+// LogBufferSize is how many log records may be queued per subscriber
+// before new records are dropped.
+const LogBufferSize = 256
+
+// logSubscriber tracks a client subscribed to daemon log records.
+type logSubscriber struct {
+	conn    net.Conn
+	encoder *json.Encoder
+	mu      *sync.Mutex // Shared mutex for all writes to the connection
+	level   slog.Level
+	events  chan *StreamEvent
+	done    chan struct{}
+}
+
+// SubscribeLogs registers a connection to receive daemon log records at or
+// above level. Records are delivered asynchronously as "log" stream events.
+// The encoder and mutex are shared with the connection handler for synchronized writes.
+func (s *Server) SubscribeLogs(conn net.Conn, level slog.Level, encoder *json.Encoder, mu *sync.Mutex) {
+	sub := &logSubscriber{
+		conn:    conn,
+		encoder: encoder,
+		mu:      mu,
+		level:   level,
+		events:  make(chan *StreamEvent, LogBufferSize),
+		done:    make(chan struct{}),
+	}
+
+	s.logMu.Lock()
+	if old, ok := s.logSubs[conn]; ok {
+		close(old.done)
+	}
+	s.logSubs[conn] = sub
+	s.logMu.Unlock()
+
+	go s.writeLogs(sub)
+}
+
+// UnsubscribeLogs stops delivering log records to a connection.
+func (s *Server) UnsubscribeLogs(conn net.Conn) {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	if sub, ok := s.logSubs[conn]; ok {
+		close(sub.done)
+		delete(s.logSubs, conn)
+	}
+}
+
+// LogSubscriberCount returns the number of connections subscribed to logs.
+func (s *Server) LogSubscriberCount() int {
+	s.logMu.RLock()
+	defer s.logMu.RUnlock()
+	return len(s.logSubs)
+}
+
+// Enabled implements logging.Sink.
+// It reports whether any subscriber wants records at the given level.
+func (s *Server) Enabled(level slog.Level) bool {
+	s.logMu.RLock()
+	defer s.logMu.RUnlock()
+	for _, sub := range s.logSubs {
+		if level >= sub.level {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleRecord implements logging.Sink.
+// It queues the record for each interested subscriber without blocking;
+// records are dropped for subscribers whose queue is full.
+func (s *Server) HandleRecord(r logging.Record) {
+	event := &StreamEvent{
+		Type: "log",
+		Log: &LogRecordDTO{
+			Time:    r.Time.Format(time.RFC3339),
+			Level:   r.Level.String(),
+			Message: r.Message,
+			Attrs:   r.Attrs,
+		},
+	}
+
+	s.logMu.RLock()
+	defer s.logMu.RUnlock()
+	for _, sub := range s.logSubs {
+		if r.Level < sub.level {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// writeLogs drains a subscriber's queue onto its connection.
+// It must not log while holding the connection mutex, since the
+// resulting record would be routed back to this subscriber.
+func (s *Server) writeLogs(sub *logSubscriber) {
+	defer logging.LogPanic("daemon-log-writer", nil)
+
+	for {
+		select {
+		case <-sub.done:
+			return
+		case event := <-sub.events:
+			_ = sub.conn.SetWriteDeadline(time.Now().Add(BroadcastTimeout))
+			sub.mu.Lock()
+			err := sub.encoder.Encode(event)
+			sub.mu.Unlock()
+			_ = sub.conn.SetWriteDeadline(time.Time{})
+
+			if err != nil {
+				s.UnsubscribeLogs(sub.conn)
+				return
+			}
+		}
+	}
+}
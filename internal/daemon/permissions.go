@@ -2,8 +2,10 @@
 package daemon
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"sync"
 	"time"
@@ -15,39 +17,89 @@ var (
 	ErrPermissionExpired  = errors.New("permission request expired")
 )
 
+// PermissionDedupWindow is how soon after a pending permission request an
+// identical one from the same agent is collapsed into it rather than queued.
+const PermissionDedupWindow = 30 * time.Second
+
 // PermissionManager tracks pending permission requests with response channels.
 // The hook command blocks waiting for a response, which is sent via the channel.
 type PermissionManager struct {
 	mu sync.RWMutex
 	// +checklocks:mu
-	pending map[string]*pendingPermission
-	timeout time.Duration
+	pending     map[string]*pendingPermission
+	timeout     time.Duration
+	dedupWindow time.Duration
 }
 
-// pendingPermission holds a request and its response channel.
+// pendingPermission holds a request, its response channel, and how long it
+// may wait for a response. Duplicates collapsed into the request wait on
+// their own channels and receive the same response.
 type pendingPermission struct {
-	request  *PermissionRequest
-	response chan *PermissionResponse
+	request    *PermissionRequest
+	response   chan *PermissionResponse
+	duplicates []chan *PermissionResponse
+	timeout    time.Duration
+}
+
+// channels returns every channel waiting on the request's response.
+func (p *pendingPermission) channels() []chan *PermissionResponse {
+	return append([]chan *PermissionResponse{p.response}, p.duplicates...)
 }
 
-// NewPermissionManager creates a new permission manager with the given timeout.
+// close closes every waiting channel without sending a response.
+func (p *pendingPermission) close() {
+	for _, ch := range p.channels() {
+		close(ch)
+	}
+}
+
+// NewPermissionManager creates a new permission manager with the given
+// default timeout.
 func NewPermissionManager(timeout time.Duration) *PermissionManager {
 	if timeout <= 0 {
 		timeout = 60 * time.Second
 	}
 	return &PermissionManager{
-		pending: make(map[string]*pendingPermission),
-		timeout: timeout,
+		pending:     make(map[string]*pendingPermission),
+		timeout:     timeout,
+		dedupWindow: PermissionDedupWindow,
 	}
 }
 
 // Add registers a new permission request and returns a channel that will receive the response.
 // The caller should block on the returned channel.
 // Returns the generated request ID and the response channel.
+// The request expires after the manager's default timeout.
+//
+// A request identical to one the same agent made within PermissionDedupWindow
+// that is still pending is collapsed into it: the returned ID is the pending
+// request's, its Duplicates count goes up, and the response to it is sent
+// to both.
 func (m *PermissionManager) Add(req *PermissionRequest) (string, <-chan *PermissionResponse) {
+	return m.AddWithTimeout(req, m.timeout)
+}
+
+// AddWithTimeout is like Add, but the request expires after timeout instead
+// of the manager's default. A non-positive timeout uses the default.
+func (m *PermissionManager) AddWithTimeout(req *PermissionRequest, timeout time.Duration) (string, <-chan *PermissionResponse) {
+	if timeout <= 0 {
+		timeout = m.timeout
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if pending := m.findDuplicate(req); pending != nil {
+		respCh := make(chan *PermissionResponse, 1)
+		pending.duplicates = append(pending.duplicates, respCh)
+
+		// Copy rather than update in place, since callers may hold the old request
+		updated := *pending.request
+		updated.Duplicates++
+		pending.request = &updated
+		return updated.ID, respCh
+	}
+
 	// Generate ID if not set
 	if req.ID == "" {
 		req.ID = generatePermissionID()
@@ -64,13 +116,45 @@ func (m *PermissionManager) Add(req *PermissionRequest) (string, <-chan *Permiss
 	m.pending[req.ID] = &pendingPermission{
 		request:  req,
 		response: respCh,
+		timeout:  timeout,
 	}
 
 	return req.ID, respCh
 }
 
-// Respond sends a response to a pending permission request.
-// This unblocks the waiting hook command.
+// findDuplicate returns the pending request req duplicates, or nil if none.
+//
+// +checklocks:m.mu
+func (m *PermissionManager) findDuplicate(req *PermissionRequest) *pendingPermission {
+	if req.AgentID == "" || m.dedupWindow <= 0 {
+		return nil
+	}
+	now := req.RequestedAt
+	if now.IsZero() {
+		now = time.Now()
+	}
+	for _, pending := range m.pending {
+		orig := pending.request
+		if orig.AgentID == req.AgentID && orig.ToolName == req.ToolName &&
+			now.Sub(orig.RequestedAt) <= m.dedupWindow && sameToolInput(orig.ToolInput, req.ToolInput) {
+			return pending
+		}
+	}
+	return nil
+}
+
+// sameToolInput reports whether two tool inputs are the same JSON, ignoring
+// insignificant whitespace.
+func sameToolInput(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// Respond sends a response to a pending permission request and any
+// duplicates collapsed into it. This unblocks the waiting hook commands.
 func (m *PermissionManager) Respond(id string, resp *PermissionResponse) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -83,11 +167,13 @@ func (m *PermissionManager) Respond(id string, resp *PermissionResponse) error {
 	// Ensure response has correct ID
 	resp.ID = id
 
-	// Send response (non-blocking due to buffered channel)
-	select {
-	case pending.response <- resp:
-	default:
-		// Channel full - should not happen with buffer size 1
+	// Send response (non-blocking due to buffered channels)
+	for _, ch := range pending.channels() {
+		select {
+		case ch <- resp:
+		default:
+			// Channel full - should not happen with buffer size 1
+		}
 	}
 
 	// Remove from pending
@@ -158,8 +244,8 @@ func (m *PermissionManager) Remove(id string) bool {
 		return false
 	}
 
-	// Close the channel to unblock any waiters
-	close(pending.response)
+	// Close the channels to unblock any waiters
+	pending.close()
 	delete(m.pending, id)
 	return true
 }
@@ -172,7 +258,7 @@ func (m *PermissionManager) RemoveForAgent(agentID string) int {
 	var removed int
 	for id, pending := range m.pending {
 		if pending.request.AgentID == agentID {
-			close(pending.response)
+			pending.close()
 			delete(m.pending, id)
 			removed++
 		}
@@ -180,7 +266,7 @@ func (m *PermissionManager) RemoveForAgent(agentID string) int {
 	return removed
 }
 
-// Cleanup removes expired permission requests.
+// Cleanup removes permission requests that have outlived their timeout.
 // Should be called periodically to prevent memory leaks.
 func (m *PermissionManager) Cleanup() int {
 	m.mu.Lock()
@@ -190,10 +276,10 @@ func (m *PermissionManager) Cleanup() int {
 	var removed int
 
 	for id, pending := range m.pending {
-		if now.Sub(pending.request.RequestedAt) > m.timeout {
+		if now.Sub(pending.request.RequestedAt) > pending.timeout {
 			// Close channel without sending a response - this causes the agent to fail
 			// rather than receiving a rejection that it might try to work around
-			close(pending.response)
+			pending.close()
 			delete(m.pending, id)
 			removed++
 		}
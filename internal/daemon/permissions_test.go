@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPermissionManager_CleanupUsesRequestTimeout(t *testing.T) {
+	m := NewPermissionManager(time.Hour)
+	requestedAt := time.Now().Add(-time.Minute)
+
+	shortID, shortCh := m.AddWithTimeout(&PermissionRequest{ToolName: "Write", RequestedAt: requestedAt}, 30*time.Second)
+	longID, _ := m.AddWithTimeout(&PermissionRequest{ToolName: "Bash", RequestedAt: requestedAt}, 10*time.Minute)
+	defaultID, _ := m.Add(&PermissionRequest{ToolName: "Edit", RequestedAt: requestedAt})
+
+	if removed := m.Cleanup(); removed != 1 {
+		t.Errorf("Cleanup() removed %d, want 1", removed)
+	}
+	if m.Get(shortID) != nil {
+		t.Error("request past its own timeout was not removed")
+	}
+	if resp, ok := <-shortCh; ok || resp != nil {
+		t.Errorf("expired request's channel = (%v, %v), want closed", resp, ok)
+	}
+	if m.Get(longID) == nil || m.Get(defaultID) == nil {
+		t.Error("requests within their timeouts were removed")
+	}
+}
+
+func TestPermissionManager_CollapsesDuplicates(t *testing.T) {
+	m := NewPermissionManager(time.Hour)
+	now := time.Now()
+	req := func(agent, input string, at time.Time) *PermissionRequest {
+		return &PermissionRequest{AgentID: agent, ToolName: "Bash", ToolInput: []byte(input), RequestedAt: at}
+	}
+
+	id, ch := m.Add(req("a1", `{"command": "ls"}`, now))
+	dupID, dupCh := m.Add(req("a1", `{"command":"ls"}`, now.Add(time.Second)))
+	if dupID != id {
+		t.Fatalf("duplicate got ID %s, want %s", dupID, id)
+	}
+	if got := m.Get(id).Duplicates; got != 1 {
+		t.Errorf("Duplicates = %d, want 1", got)
+	}
+
+	// Other agents, other input, and repeats outside the window stay separate
+	for _, r := range []*PermissionRequest{
+		req("a2", `{"command":"ls"}`, now),
+		req("a1", `{"command":"pwd"}`, now),
+		req("a1", `{"command":"ls"}`, now.Add(PermissionDedupWindow+time.Second)),
+	} {
+		if other, _ := m.Add(r); other == id {
+			t.Errorf("request %+v was collapsed, want a separate request", r)
+		}
+	}
+	if m.Count() != 4 {
+		t.Errorf("Count() = %d, want 4", m.Count())
+	}
+
+	// The decision reaches every collapsed request
+	if err := m.Respond(id, &PermissionResponse{Behavior: "allow"}); err != nil {
+		t.Fatalf("Respond() error = %v", err)
+	}
+	for _, c := range []<-chan *PermissionResponse{ch, dupCh} {
+		if resp := <-c; resp == nil || resp.Behavior != "allow" {
+			t.Errorf("response = %+v, want allow", resp)
+		}
+	}
+}
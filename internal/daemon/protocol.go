@@ -2,6 +2,7 @@
 package daemon
 
 import (
+	"bytes"
 	"encoding/json"
 	"time"
 )
@@ -13,6 +14,7 @@ const (
 	// Server management
 	MsgPing     MessageType = "ping"
 	MsgShutdown MessageType = "shutdown"
+	MsgWhoami   MessageType = "whoami" // Report the daemon's user, paths, and credentials
 
 	// Supervisor control
 	MsgStart  MessageType = "start"  // Start orchestration for a project
@@ -27,6 +29,8 @@ const (
 	MsgProjectConfigShow MessageType = "project.config.show" // Show all config for a project
 	MsgProjectConfigGet  MessageType = "project.config.get"  // Get a single config value
 	MsgProjectConfigSet  MessageType = "project.config.set"  // Set a single config value
+	MsgProjectPull       MessageType = "project.pull"        // Fast-forward the main clone's default branch
+	MsgProjectLog        MessageType = "project.log"         // Recent commits on the default branch
 
 	// Agent management
 	MsgAgentList     MessageType = "agent.list"
@@ -36,13 +40,30 @@ const (
 	MsgAgentInput    MessageType = "agent.input"    // Send input to agent
 	MsgAgentOutput   MessageType = "agent.output"   // Get buffered output from agent
 	MsgAgentDescribe MessageType = "agent.describe" // Set agent description
+	MsgAgentRename   MessageType = "agent.rename"   // Set agent's human-friendly name
 	MsgAgentIdle     MessageType = "agent.idle"     // Agent signals it has gone idle (Stop hook)
+	MsgAgentReplay   MessageType = "agent.replay"   // Replay an agent's user turns into a new agent
+	MsgAgentRecover  MessageType = "agent.recover"  // Restore work stashed by a forced abort into a branch
+	MsgAgentDelegate MessageType = "agent.delegate" // Spawn an agent on a specific ticket
+
+	MsgAgentDebugCapture     MessageType = "agent.debug_capture"     // Toggle raw CLI output capture to a log file
+	MsgAgentTranscriptExport MessageType = "agent.transcript_export" // Render an agent's chat history as markdown
+	MsgAgentGrep             MessageType = "agent.grep"              // Search an agent's chat history
+	MsgAgentExec             MessageType = "agent.exec"              // Run an operator command in an agent's worktree
+	MsgAgentLineage          MessageType = "agent.lineage"           // Get the tree of which agent spawned which
+	MsgAgentGet              MessageType = "agent.get"               // Get one agent's detailed state
+	MsgAgentCompact          MessageType = "agent.compact"           // Replace an agent's chat history with a summary
 
 	// TUI streaming
 	MsgAttach           MessageType = "attach" // Subscribe to agent output streams
 	MsgDetach           MessageType = "detach" // Unsubscribe from streams
 	MsgAgentSendMessage MessageType = "agent.send_message"
 	MsgAgentChatHistory MessageType = "agent.chat_history" // Get chat history for an agent
+	MsgDashboard        MessageType = "dashboard"          // Get the TUI's startup state in one round trip
+
+	// Daemon logs
+	MsgLogSubscribe MessageType = "log.subscribe" // Stream daemon log records to this connection
+	MsgSetLogLevel  MessageType = "log.level"     // Change the daemon's log level at runtime
 
 	// Orchestrator (agent signals)
 	MsgAgentDone MessageType = "agent.done" // Agent signals task completion
@@ -57,13 +78,22 @@ const (
 	MsgUserQuestionRespond MessageType = "question.respond" // TUI responds to user question
 
 	// Ticket claims (prevent duplicate work across agents)
-	MsgAgentClaim MessageType = "agent.claim" // Claim a ticket for an agent
-	MsgClaimList  MessageType = "claim.list"  // List all active claims
+	MsgAgentClaim   MessageType = "agent.claim"   // Claim a ticket for an agent
+	MsgClaimList    MessageType = "claim.list"    // List all active claims
+	MsgClaimRelease MessageType = "claim.release" // Release a claim held by any agent
+
+	// Shared notes (findings agents pass on to later agents)
+	MsgNoteAppend MessageType = "note.append" // Append a note to a project's NOTES.md
+	MsgNoteRead   MessageType = "note.read"   // Read a project's NOTES.md
+
+	// Project activity timeline (audit of claims, spawns, merges, and changes)
+	MsgTimeline MessageType = "timeline" // Read a project's timeline.jsonl
 
 	// Manager agent (interactive user conversation)
 	MsgManagerStart        MessageType = "manager.start"         // Start the manager agent
 	MsgManagerStop         MessageType = "manager.stop"          // Stop the manager agent
 	MsgManagerStatus       MessageType = "manager.status"        // Get manager status
+	MsgManagerList         MessageType = "manager.list"          // Get every project's manager status
 	MsgManagerSendMessage  MessageType = "manager.send_message"  // Send message to manager
 	MsgManagerChatHistory  MessageType = "manager.chat_history"  // Get manager chat history
 	MsgManagerClearHistory MessageType = "manager.clear_history" // Clear manager chat history
@@ -107,18 +137,73 @@ type PingResponse struct {
 	StartedAt time.Time `json:"started_at"`
 }
 
+// WhoamiResponse is the payload for whoami responses. It describes the
+// identity and environment the daemon runs with, which can differ from the
+// shell running the CLI (e.g., a different user or token).
+type WhoamiResponse struct {
+	User       string            `json:"user"`        // Effective user name
+	UID        int               `json:"uid"`         // Effective user ID
+	Home       string            `json:"home"`        // Home directory
+	PID        int               `json:"pid"`         // Daemon process ID
+	Version    string            `json:"version"`     // fab version
+	ConfigPath string            `json:"config_path"` // Global config file the daemon reads
+	SocketPath string            `json:"socket_path"` // Socket the daemon listens on
+	IssueAuth  []IssueAuthStatus `json:"issue_auth,omitempty"`
+}
+
+// IssueAuthStatus describes the credentials a project's issue backend
+// resolves in the daemon.
+type IssueAuthStatus struct {
+	Project string `json:"project"`
+	Backend string `json:"backend"` // "tk", "github", "gh", or "linear"
+	// Host is the GitHub host the token is resolved for, as gh.New picks
+	// it. Empty for other backends.
+	Host string `json:"host,omitempty"`
+	// Source is where the token came from: "config" or an environment
+	// variable name. Empty if the backend needs no token or none is set.
+	Source string `json:"source,omitempty"`
+	// Fingerprint identifies the token without revealing it: the first
+	// 8 hex digits of its SHA-256, for comparing tokens across shells.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	OK          bool   `json:"ok"`              // Credentials are in place
+	Error       string `json:"error,omitempty"` // What is missing when not OK
+}
+
 // StartRequest is the payload for start requests.
 type StartRequest struct {
 	Project string `json:"project"`       // Project name, or empty for all
 	All     bool   `json:"all,omitempty"` // Start all projects
 }
 
+// ProjectStartResult is the outcome of starting orchestration for one project.
+type ProjectStartResult struct {
+	Project string `json:"project"`
+	Started bool   `json:"started"`
+	Error   string `json:"error,omitempty"`
+}
+
+// StartResponse is the payload for start responses.
+type StartResponse struct {
+	Results []ProjectStartResult `json:"results"`
+}
+
 // StopRequest is the payload for stop requests.
 type StopRequest struct {
 	Project string `json:"project"`       // Project name, or empty for all
 	All     bool   `json:"all,omitempty"` // Stop all projects
 }
 
+// ProjectStopResult is the outcome of stopping orchestration for one project.
+type ProjectStopResult struct {
+	Project string `json:"project"`
+	Stopped bool   `json:"stopped"` // False if orchestration wasn't running
+}
+
+// StopResponse is the payload for stop responses.
+type StopResponse struct {
+	Results []ProjectStopResult `json:"results"`
+}
+
 // ShutdownRequest is the payload for shutdown requests.
 type ShutdownRequest struct {
 	StopHost bool `json:"stop_host,omitempty"` // Also stop the agent host process
@@ -154,19 +239,58 @@ type ProjectStatus struct {
 	Running      bool          `json:"running"` // Orchestration active
 	MaxAgents    int           `json:"max_agents"`
 	ActiveAgents int           `json:"active_agents"`
+	Reserved     int           `json:"reserved_slots,omitempty"` // High-priority slots above MaxAgents
 	Agents       []AgentStatus `json:"agents,omitempty"`
+
+	// WaitingIssues counts ready issues no agent has claimed yet, and
+	// AtCapacity is set when every normal agent slot is taken, so they wait
+	// for one to free up. Only reported while orchestration is running.
+	WaitingIssues int  `json:"waiting_issues,omitempty"`
+	AtCapacity    bool `json:"at_capacity,omitempty"`
+
+	IssueCache *IssueCacheStats `json:"issue_cache,omitempty"` // Nil when issue caching is off
+
+	IssueFailures []IssueFailureStats `json:"issue_failures,omitempty"` // Issues with recent agent failures
+}
+
+// IssueCacheStats counts issue backend cache lookups for a project.
+type IssueCacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// IssueFailureStats counts consecutive agent failures (crashes or failed
+// merges) on an issue. Blocked is set once the orchestrator blocked the issue.
+type IssueFailureStats struct {
+	Issue       string    `json:"issue"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last_failure"`
+	LastReason  string    `json:"last_reason,omitempty"`
+	Blocked     bool      `json:"blocked,omitempty"`
 }
 
 // AgentStatus contains per-agent status info.
 type AgentStatus struct {
 	ID          string    `json:"id"`
+	Name        string    `json:"name,omitempty"` // Human-friendly name; ID remains the canonical key
 	Project     string    `json:"project"`
 	State       string    `json:"state"` // starting, running, idle, done
 	Worktree    string    `json:"worktree"`
 	StartedAt   time.Time `json:"started_at"`
-	Task        string    `json:"task,omitempty"`        // Current task ID if known
-	Description string    `json:"description,omitempty"` // Human-readable description
-	Backend     string    `json:"backend,omitempty"`     // CLI backend name (e.g., "claude", "codex")
+	Task        string    `json:"task,omitempty"`         // Current task ID if known
+	Description string    `json:"description,omitempty"`  // Human-readable description
+	Backend     string    `json:"backend,omitempty"`      // CLI backend name (e.g., "claude", "codex")
+	StashRef    string    `json:"stash_ref,omitempty"`    // Stash of work saved before a forced abort
+	Epic        string    `json:"epic,omitempty"`         // Parent issue of the current task, if any
+	CurrentFile string    `json:"current_file,omitempty"` // File most recently read or edited
+	Model       string    `json:"model,omitempty"`        // Model passed to the CLI (empty = CLI default)
+	SpawnedBy   string    `json:"spawned_by,omitempty"`   // "user", "orchestrator", "manager", or a parent agent ID
+	Role        string    `json:"role,omitempty"`         // "reviewer" for read-only agents (empty = unrestricted)
+	Subdir      string    `json:"subdir,omitempty"`       // Worktree subdirectory the agent runs in (empty = the root)
+
+	// NeedsAttention is set when the agent has a pending permission request
+	// or user question. Only agent.list fills it in.
+	NeedsAttention bool `json:"needs_attention,omitempty"`
 }
 
 // ProjectAddRequest is the payload for project.add requests.
@@ -176,20 +300,35 @@ type ProjectAddRequest struct {
 	MaxAgents int    `json:"max_agents,omitempty"` // Default: 3
 	Autostart bool   `json:"autostart,omitempty"`  // Start orchestration when daemon starts
 	Backend   string `json:"backend,omitempty"`    // Agent backend (claude/codex)
+
+	// Depth, if > 0, makes a shallow clone with that many commits of history.
+	// The full history is fetched before the project's first merge.
+	Depth int `json:"depth,omitempty"`
+	// SingleBranch clones only the remote's default branch.
+	SingleBranch bool `json:"single_branch,omitempty"`
+
+	// Preset names a bundle of project config values from the daemon's
+	// global config (presets.<name>), applied after the project is created.
+	Preset string `json:"preset,omitempty"`
+	// Config holds project config values (key -> value, as taken by
+	// project.config_set) applied after the preset, overriding its values.
+	Config map[string]string `json:"config,omitempty"`
 }
 
 // ProjectAddResponse is the payload for project.add responses.
 type ProjectAddResponse struct {
-	Name      string `json:"name"`
-	RemoteURL string `json:"remote_url"`
-	RepoDir   string `json:"repo_dir"` // Local clone path
-	MaxAgents int    `json:"max_agents"`
+	Name      string            `json:"name"`
+	RemoteURL string            `json:"remote_url"`
+	RepoDir   string            `json:"repo_dir"` // Local clone path
+	MaxAgents int               `json:"max_agents"`
+	Config    map[string]string `json:"config,omitempty"` // Values applied from the preset and Config
 }
 
 // ProjectRemoveRequest is the payload for project.remove requests.
 type ProjectRemoveRequest struct {
 	Name            string `json:"name"`
 	DeleteWorktrees bool   `json:"delete_worktrees,omitempty"` // Clean up worktrees
+	Force           bool   `json:"force,omitempty"`            // Stop a running project first, letting in-flight merges finish
 }
 
 // ProjectListResponse is the payload for project.list responses.
@@ -221,8 +360,64 @@ type ProjectConfigShowRequest struct {
 
 // ProjectConfigShowResponse is the payload for project.config.show responses.
 type ProjectConfigShowResponse struct {
-	Name   string         `json:"name"`   // Project name
-	Config map[string]any `json:"config"` // Config key-value pairs
+	Name    string               `json:"name"`    // Project name
+	Config  map[string]any       `json:"config"`  // Config key-value pairs
+	Entries []ProjectConfigEntry `json:"entries"` // Every config key with its default and source
+}
+
+// Config value types reported in ProjectConfigEntry.Type.
+const (
+	ConfigTypeInt    = "int"
+	ConfigTypeBool   = "bool"
+	ConfigTypeString = "string"
+	ConfigTypeList   = "list" // List of strings
+)
+
+// ProjectConfigEntry describes one project config key. Value and Default
+// decode to the Go type named by Type (int, bool, string, or []string).
+type ProjectConfigEntry struct {
+	Key      string `json:"key"`
+	Type     string `json:"type"`
+	Value    any    `json:"value"`
+	Default  any    `json:"default"`
+	Set      bool   `json:"set"`                // Set in the project's config rather than defaulted
+	Redacted bool   `json:"redacted,omitempty"` // Value and Default were replaced because the key is secret
+}
+
+// UnmarshalJSON decodes Value and Default as Type, so ints don't come back
+// as float64 and lists as []any.
+func (e *ProjectConfigEntry) UnmarshalJSON(data []byte) error {
+	type plain ProjectConfigEntry
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode((*plain)(e)); err != nil {
+		return err
+	}
+	e.Value = typedConfigValue(e.Type, e.Value)
+	e.Default = typedConfigValue(e.Type, e.Default)
+	return nil
+}
+
+// typedConfigValue converts a decoded JSON value to the Go type for typ.
+func typedConfigValue(typ string, v any) any {
+	switch typ {
+	case ConfigTypeInt:
+		if n, ok := v.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				return int(i)
+			}
+		}
+	case ConfigTypeList:
+		items, _ := v.([]any)
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return v
 }
 
 // ProjectConfigGetRequest is the payload for project.config.get requests.
@@ -245,10 +440,58 @@ type ProjectConfigSetRequest struct {
 	Value string `json:"value"` // Config value (as string, will be parsed based on key type)
 }
 
+// ProjectPullRequest is the payload for project.pull requests.
+type ProjectPullRequest struct {
+	Name string `json:"name"` // Project name
+}
+
+// ProjectPullResponse is the payload for project.pull responses.
+type ProjectPullResponse struct {
+	Name         string `json:"name"`                    // Project name
+	Branch       string `json:"branch"`                  // Default branch that was refreshed
+	PreviousSHA  string `json:"previous_sha"`            // HEAD before the pull
+	SHA          string `json:"sha"`                     // HEAD after the pull
+	Updated      bool   `json:"updated,omitempty"`       // True if the branch moved
+	LocalChanges bool   `json:"local_changes,omitempty"` // True if uncommitted changes prevented the fast-forward
+}
+
+// ProjectLogRequest is the payload for project.log requests.
+type ProjectLogRequest struct {
+	Name  string    `json:"name"`            // Project name
+	Limit int       `json:"limit,omitempty"` // Maximum commits to return (default 20)
+	Since time.Time `json:"since,omitempty"` // Only commits that landed at or after this time (zero = no bound)
+	Until time.Time `json:"until,omitempty"` // Only commits that landed at or before this time (zero = no bound)
+	Fetch bool      `json:"fetch,omitempty"` // Fetch origin first to include commits pushed outside fab
+}
+
+// ProjectLogCommit is a commit on a project's default branch.
+type ProjectLogCommit struct {
+	SHA     string    `json:"sha"`
+	Author  string    `json:"author"`
+	Time    time.Time `json:"time"` // Author date
+	Subject string    `json:"subject"`
+	Fab     bool      `json:"fab,omitempty"`      // True if a fab agent's merged work ends at this commit
+	AgentID string    `json:"agent_id,omitempty"` // Agent that merged it (when Fab is set)
+	IssueID string    `json:"issue_id,omitempty"` // Issue the agent was working on, if known
+}
+
+// ProjectLogResponse is the payload for project.log responses.
+type ProjectLogResponse struct {
+	Name    string             `json:"name"`    // Project name
+	Branch  string             `json:"branch"`  // Default branch the commits are on
+	Commits []ProjectLogCommit `json:"commits"` // Newest first
+}
+
 // AgentCreateRequest is the payload for agent.create requests.
 type AgentCreateRequest struct {
 	Project string `json:"project"`
-	Task    string `json:"task,omitempty"` // Optional initial task
+	Task    string `json:"task,omitempty"`   // Optional initial task
+	Model   string `json:"model,omitempty"`  // Model override (default: the project's model)
+	Role    string `json:"role,omitempty"`   // "reviewer" limits the agent to read-only tools
+	Subdir  string `json:"subdir,omitempty"` // Worktree subdirectory to run the agent in (must exist)
+
+	// DebugCapture tees the agent's raw CLI output into ~/.fab/debug/<agent-id>.log
+	DebugCapture bool `json:"debug_capture,omitempty"`
 }
 
 // AgentCreateResponse is the payload for agent.create responses.
@@ -258,6 +501,114 @@ type AgentCreateResponse struct {
 	Worktree string `json:"worktree"`
 }
 
+// AgentReplayRequest is the payload for agent.replay requests.
+type AgentReplayRequest struct {
+	ID      string `json:"id"`                // Source agent (running or deleted)
+	Project string `json:"project,omitempty"` // Defaults to the source agent's project
+}
+
+// AgentReplayResponse is the payload for agent.replay responses.
+type AgentReplayResponse struct {
+	ID       string `json:"id"` // The new agent
+	SourceID string `json:"source_id"`
+	Project  string `json:"project"`
+	Worktree string `json:"worktree"`
+	Turns    int    `json:"turns"` // Number of user turns that will be replayed
+}
+
+// AgentCompactRequest is the payload for agent.compact requests.
+type AgentCompactRequest struct {
+	ID string `json:"id"`
+}
+
+// AgentCompactResponse is the payload for agent.compact responses.
+// Compaction runs in the background once the agent has been asked for a
+// summary.
+type AgentCompactResponse struct {
+	ID      string `json:"id"`
+	Entries int    `json:"entries"` // Chat entries that will be replaced by the summary
+}
+
+// AgentRecoverRequest is the payload for agent.recover requests.
+type AgentRecoverRequest struct {
+	ID      string `json:"id,omitempty"`      // Agent whose stash to recover (empty = most recent)
+	Project string `json:"project,omitempty"` // Required if the agent no longer exists
+	Branch  string `json:"branch,omitempty"`  // Defaults to fab/recover-<id>
+}
+
+// AgentRecoverResponse is the payload for agent.recover responses.
+type AgentRecoverResponse struct {
+	ID      string `json:"id"` // Agent the work was recovered from
+	Project string `json:"project"`
+	Branch  string `json:"branch"`
+	Commit  string `json:"commit"`
+}
+
+// AgentDebugCaptureRequest is the payload for agent.debug_capture requests.
+type AgentDebugCaptureRequest struct {
+	ID      string `json:"id"`
+	Enabled bool   `json:"enabled"`
+}
+
+// AgentDebugCaptureResponse is the payload for agent.debug_capture responses.
+type AgentDebugCaptureResponse struct {
+	Path string `json:"path,omitempty"` // Capture log (empty when disabled)
+}
+
+// AgentTranscriptExportRequest is the payload for agent.transcript_export requests.
+type AgentTranscriptExportRequest struct {
+	ID    string `json:"id"`
+	Write bool   `json:"write,omitempty"` // Also write the transcript into the agent's worktree
+	Path  string `json:"path,omitempty"`  // File path relative to the worktree (default: transcript-<id>.md)
+}
+
+// AgentTranscriptExportResponse is the payload for agent.transcript_export responses.
+type AgentTranscriptExportResponse struct {
+	AgentID  string `json:"agent_id"`
+	Markdown string `json:"markdown"`
+	Path     string `json:"path,omitempty"` // Absolute path written (empty unless Write was set)
+}
+
+// AgentGrepRequest is the payload for agent.grep requests.
+type AgentGrepRequest struct {
+	ID      string `json:"id"`                // Agent ID; deleted agents are searched in their saved transcript
+	Pattern string `json:"pattern,omitempty"` // RE2 regexp matched against content, tool input, and tool output ("(?i)" ignores case)
+	Tool    string `json:"tool,omitempty"`    // Only search calls to and results from this tool (case-insensitive)
+	Context int    `json:"context,omitempty"` // Entries to include before and after each match
+	Limit   int    `json:"limit,omitempty"`   // Max matches (0 = 100)
+}
+
+// AgentGrepMatch is a chat entry that matched an agent.grep search, with
+// the entries around it.
+type AgentGrepMatch struct {
+	Entry  ChatEntryDTO   `json:"entry"`
+	Before []ChatEntryDTO `json:"before,omitempty"`
+	After  []ChatEntryDTO `json:"after,omitempty"`
+}
+
+// AgentGrepResponse is the payload for agent.grep responses.
+type AgentGrepResponse struct {
+	AgentID   string           `json:"agent_id"`
+	Matches   []AgentGrepMatch `json:"matches"`
+	Searched  int              `json:"searched"`            // Entries searched
+	Truncated bool             `json:"truncated,omitempty"` // More entries matched than Limit
+}
+
+// AgentDelegateRequest is the payload for agent.delegate requests.
+type AgentDelegateRequest struct {
+	TicketID    string `json:"ticket_id"`
+	Project     string `json:"project,omitempty"`      // Defaults to the delegator's project
+	DelegatedBy string `json:"delegated_by,omitempty"` // FAB_AGENT_ID of the caller (e.g., "manager:myproject")
+}
+
+// AgentDelegateResponse is the payload for agent.delegate responses.
+type AgentDelegateResponse struct {
+	ID       string `json:"id"` // The new agent
+	Project  string `json:"project"`
+	TicketID string `json:"ticket_id"`
+	Worktree string `json:"worktree"`
+}
+
 // AgentDeleteRequest is the payload for agent.delete requests.
 type AgentDeleteRequest struct {
 	ID    string `json:"id"`
@@ -273,8 +624,23 @@ type AgentAbortRequest struct {
 // AgentListRequest is the payload for agent.list requests.
 type AgentListRequest struct {
 	Project string `json:"project,omitempty"` // Filter by project
+	SortBy  string `json:"sort_by,omitempty"` // AgentSortState, AgentSortProject, AgentSortStarted, or empty for the daemon's order
+	Order   string `json:"order,omitempty"`   // SortAscending (default) or SortDescending
 }
 
+// Agent list sort keys for AgentListRequest.SortBy.
+const (
+	AgentSortState   = "state"   // Agents needing attention first, then by how much they need the user
+	AgentSortProject = "project" // Alphabetically by project
+	AgentSortStarted = "started" // Oldest first
+)
+
+// Sort orders for AgentListRequest.Order.
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
 // AgentListResponse is the payload for agent.list responses.
 type AgentListResponse struct {
 	Agents []AgentStatus `json:"agents"`
@@ -309,6 +675,81 @@ type AgentDescribeRequest struct {
 	Description string `json:"description"`        // Human-readable description of current work
 }
 
+// AgentRenameRequest is the payload for agent.rename requests.
+type AgentRenameRequest struct {
+	AgentID string `json:"agent_id"` // Agent ID
+	Name    string `json:"name"`     // New name (empty clears it)
+}
+
+// AgentGetRequest is the payload for agent.get requests.
+type AgentGetRequest struct {
+	ID string `json:"id"` // Agent ID
+}
+
+// AgentGetResponse is the payload for agent.get responses: the agent's
+// status plus details too costly to gather for every agent in agent.list.
+type AgentGetResponse struct {
+	Agent         AgentStatus `json:"agent"`
+	WorktreeDirty bool        `json:"worktree_dirty"`   // Uncommitted changes in the worktree
+	Claims        []string    `json:"claims,omitempty"` // Tickets the agent has claimed
+	// PendingRequests counts permission requests and questions waiting
+	// for an answer before the agent can continue.
+	PendingRequests int        `json:"pending_requests"`
+	Usage           TokenUsage `json:"usage"`
+	LastActivity    time.Time  `json:"last_activity,omitempty"` // Last output from the agent (zero if none seen)
+}
+
+// TokenUsage is the token usage reported with an agent's most recent model
+// response.
+type TokenUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
+}
+
+// ContextTokens returns the tokens of context the response was given.
+func (u TokenUsage) ContextTokens() int {
+	return u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+}
+
+// AgentLineageRequest is the payload for agent.lineage requests.
+type AgentLineageRequest struct {
+	Project string `json:"project,omitempty"` // Filter by project
+}
+
+// AgentLineageResponse is the payload for agent.lineage responses.
+type AgentLineageResponse struct {
+	Roots []LineageNode `json:"roots"`
+}
+
+// LineageNode is an agent in the lineage tree, with the agents it spawned.
+type LineageNode struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name,omitempty"`
+	Project   string        `json:"project"`
+	State     string        `json:"state"`
+	SpawnedBy string        `json:"spawned_by,omitempty"` // For roots, the spawner outside the tree
+	Children  []LineageNode `json:"children,omitempty"`
+}
+
+// AgentExecRequest is the payload for agent.exec requests.
+type AgentExecRequest struct {
+	ID      string `json:"id"`                // Agent ID
+	Command string `json:"command"`           // Shell command, run with sh -c in the agent's worktree
+	Timeout string `json:"timeout,omitempty"` // How long the client waits (duration); caps exec.timeout
+}
+
+// AgentExecResponse is the payload for agent.exec responses.
+type AgentExecResponse struct {
+	ID        string `json:"id"`
+	Worktree  string `json:"worktree"`            // Directory the command ran in
+	Output    string `json:"output"`              // Combined stdout and stderr
+	ExitCode  int    `json:"exit_code"`           // -1 if the command was killed
+	TimedOut  bool   `json:"timed_out,omitempty"` // Killed after the configured timeout
+	Truncated bool   `json:"truncated,omitempty"` // Output exceeded the cap; only the tail is kept
+}
+
 // AgentIdleRequest is the payload for agent.idle requests.
 // Sent by the Stop hook when Claude Code finishes responding.
 type AgentIdleRequest struct {
@@ -320,28 +761,90 @@ type AttachRequest struct {
 	Projects []string `json:"projects,omitempty"` // Filter by projects, empty = all
 }
 
+// DashboardRequest is the payload for dashboard requests.
+type DashboardRequest struct {
+	SortBy  string `json:"sort_by,omitempty"` // Agent order, as in AgentListRequest
+	Order   string `json:"order,omitempty"`
+	Manager string `json:"manager,omitempty"` // Project whose manager status to include, if any
+}
+
+// Dashboard sections, the keys of DashboardResponse.Errors.
+const (
+	DashboardAgents      = "agents"
+	DashboardPlanners    = "planners"
+	DashboardManager     = "manager"
+	DashboardStatus      = "status"
+	DashboardClaims      = "claims"
+	DashboardPermissions = "permissions"
+)
+
+// DashboardResponse is the payload for dashboard responses. Each section
+// holds what its own message (agent.list, plan.list, manager.status, status,
+// claim.list, permission.list) would return. A section that failed is left
+// empty, with its error in Errors.
+type DashboardResponse struct {
+	Agents      []AgentStatus          `json:"agents"`
+	Planners    []PlannerStatus        `json:"planners"`
+	Manager     *ManagerStatusResponse `json:"manager,omitempty"` // Nil unless requested
+	Status      *StatusResponse        `json:"status,omitempty"`
+	Claims      []ClaimInfo            `json:"claims"`
+	Permissions []PermissionRequest    `json:"permissions"`
+	Errors      map[string]string      `json:"errors,omitempty"` // Section -> error
+}
+
+// LogSubscribeRequest is the payload for log.subscribe requests.
+type LogSubscribeRequest struct {
+	Level string `json:"level,omitempty"` // Minimum level: "debug", "info", "warn", "error" (default: "info")
+}
+
+// LogRecordDTO is the wire format for daemon log records sent to log subscribers.
+type LogRecordDTO struct {
+	Time    string            `json:"time"`            // RFC3339 format
+	Level   string            `json:"level"`           // "DEBUG", "INFO", "WARN", "ERROR"
+	Message string            `json:"message"`         // Log message
+	Attrs   map[string]string `json:"attrs,omitempty"` // Structured attributes
+}
+
+// SetLogLevelRequest is the payload for log.level requests.
+type SetLogLevelRequest struct {
+	Level string `json:"level,omitempty"` // "debug", "info", "warn", or "error"; empty reports the level without changing it
+}
+
+// SetLogLevelResponse is the payload for log.level responses.
+type SetLogLevelResponse struct {
+	Level    string `json:"level"`    // Level in effect after the request
+	Previous string `json:"previous"` // Level before the request
+}
+
 // AgentChatHistoryRequest is the payload for agent.chat_history requests.
 type AgentChatHistoryRequest struct {
-	ID    string `json:"id"`              // Agent ID
-	Limit int    `json:"limit,omitempty"` // Max entries to return (0 = all)
+	ID     string `json:"id"`               // Agent ID
+	Limit  int    `json:"limit,omitempty"`  // Max entries to return (0 = all)
+	Before string `json:"before,omitempty"` // Cursor from a previous NextCursor; empty = most recent
 }
 
 // AgentChatHistoryResponse is the payload for agent.chat_history responses.
 type AgentChatHistoryResponse struct {
-	AgentID string         `json:"agent_id"`
-	Entries []ChatEntryDTO `json:"entries"`
+	AgentID    string         `json:"agent_id"`
+	Entries    []ChatEntryDTO `json:"entries"`
+	NextCursor string         `json:"next_cursor,omitempty"` // Pass as Before to fetch older entries; empty = no more
 }
 
 // StreamEvent is sent to attached clients when agent output occurs.
 type StreamEvent struct {
-	Type              string             `json:"type"` // "output", "state", "created", "deleted", "info", "permission_request", "user_question", "intervention", "manager_chat_entry", "manager_state", "director_chat_entry", "director_state"
+	Type              string             `json:"type"` // "output", "chat_entry", "chat_delta", "state", "created", "deleted", "info", "permission_request", "user_question", "intervention", "manager_chat_entry", "manager_state", "manager_idle", "director_chat_entry", "director_state", "log", "parse_error", "would_spawn", "would_merge"
 	AgentID           string             `json:"agent_id"`
 	Project           string             `json:"project"`
-	Data              string             `json:"data,omitempty"`               // For output events
+	Data              string             `json:"data,omitempty"`               // For output events, the text chunk for "chat_delta" events, and the offending line for "parse_error" events
 	State             string             `json:"state,omitempty"`              // For state events
 	StartedAt         string             `json:"started_at,omitempty"`         // For created events (RFC3339)
-	Task              string             `json:"task,omitempty"`               // For "info" events (issue/ticket ID)
-	Description       string             `json:"description,omitempty"`        // For "info" events (agent description)
+	Name              string             `json:"name,omitempty"`               // For "created" and "info" events (human-friendly agent name)
+	SpawnedBy         string             `json:"spawned_by,omitempty"`         // For "created" events (who spawned the agent)
+	Task              string             `json:"task,omitempty"`               // For "info" and "would_*" events (issue/ticket ID)
+	Description       string             `json:"description,omitempty"`        // For "info" events (agent description) and "would_*" events (reasoning)
+	Epic              string             `json:"epic,omitempty"`               // For "info" events (parent issue of the agent's task)
+	CurrentFile       string             `json:"current_file,omitempty"`       // For "info" events (file most recently read or edited)
+	Model             string             `json:"model,omitempty"`              // For "created" and "info" events (model passed to the CLI)
 	Backend           string             `json:"backend,omitempty"`            // For "created", "planner_created" events
 	ChatEntry         *ChatEntryDTO      `json:"chat_entry,omitempty"`         // For "chat_entry" events
 	PermissionRequest *PermissionRequest `json:"permission_request,omitempty"` // For "permission_request" events
@@ -349,6 +852,8 @@ type StreamEvent struct {
 	Intervening       *bool              `json:"intervening,omitempty"`        // For "intervention" events (user is intervening)
 	ManagerState      string             `json:"manager_state,omitempty"`      // For "manager_state" events
 	DirectorState     string             `json:"director_state,omitempty"`     // For "director_state" events
+	Log               *LogRecordDTO      `json:"log,omitempty"`                // For "log" events
+	Error             string             `json:"error,omitempty"`              // For "plan_complete" (error reported by the planner), "manager_idle", and "parse_error" events
 }
 
 // ChatEntryDTO is the wire format for chat entries sent to TUI clients
@@ -360,6 +865,7 @@ type ChatEntryDTO struct {
 	ToolResult string `json:"tool_result,omitempty"` // Tool output
 	IsError    bool   `json:"is_error,omitempty"`    // True if tool result is an error
 	Timestamp  string `json:"timestamp"`             // RFC3339 format
+	Seq        int64  `json:"seq,omitempty"`         // Position in the agent's history; use as a chat history cursor
 }
 
 // AgentDoneRequest is the payload for agent.done requests.
@@ -372,12 +878,33 @@ type AgentDoneRequest struct {
 
 // AgentDoneResponse is the payload for agent.done responses.
 type AgentDoneResponse struct {
-	Merged     bool   `json:"merged"`                // True if merge to main succeeded (only for direct merge strategy)
-	BranchName string `json:"branch_name,omitempty"` // The branch that was processed
-	SHA        string `json:"sha,omitempty"`         // Commit SHA of merge commit (only if Merged is true)
-	MergeError string `json:"merge_error,omitempty"` // Conflict message if merge failed
-	PRCreated  bool   `json:"pr_created,omitempty"`  // True if PR was created (only for pull-request strategy)
-	PRURL      string `json:"pr_url,omitempty"`      // URL of created PR (only if PRCreated is true)
+	Merged      bool   `json:"merged"`                 // True if merge to main succeeded (only for direct merge strategy)
+	BranchName  string `json:"branch_name,omitempty"`  // The branch that was processed
+	SHA         string `json:"sha,omitempty"`          // Commit SHA of merge commit (only if Merged is true)
+	MergeError  string `json:"merge_error,omitempty"`  // Conflict message if merge failed
+	CheckFailed bool   `json:"check_failed,omitempty"` // True if the pre-merge command failed (MergeError holds its output)
+	PRCreated   bool   `json:"pr_created,omitempty"`   // True if PR was created (only for pull-request strategy)
+	PRURL       string `json:"pr_url,omitempty"`       // URL of created PR (only if PRCreated is true)
+	DryRun      bool   `json:"dry_run,omitempty"`      // True if the project is in dry-run mode and nothing was merged
+	Duplicate   bool   `json:"duplicate,omitempty"`    // True if this done was already handled; the earlier result is returned
+
+	// Conflicts holds each file that conflicted (only with MergeError) and
+	// its hunks, so the agent can resolve them without re-running the rebase.
+	Conflicts []ConflictFile `json:"conflicts,omitempty"`
+}
+
+// ConflictFile is a file left with unresolved conflicts by a failed rebase.
+type ConflictFile struct {
+	Path      string         `json:"path"`
+	Hunks     []ConflictHunk `json:"hunks,omitempty"`     // Empty for conflicts without markers (e.g., binary files)
+	Truncated bool           `json:"truncated,omitempty"` // True if hunks were left out to limit the response size
+}
+
+// ConflictHunk is one conflicted region of a file, markers included.
+type ConflictHunk struct {
+	StartLine int    `json:"start_line"` // 1-based line of the "<<<<<<<" marker
+	EndLine   int    `json:"end_line"`   // 1-based line of the ">>>>>>>" marker
+	Text      string `json:"text"`
 }
 
 // PermissionRequest represents a tool permission request from Claude Code.
@@ -390,6 +917,7 @@ type PermissionRequest struct {
 	ToolInput   json.RawMessage `json:"tool_input"`            // Raw tool input arguments
 	ToolUseID   string          `json:"tool_use_id,omitempty"` // Claude's tool_use_id for correlation
 	RequestedAt time.Time       `json:"requested_at"`          // When the request was made
+	Duplicates  int             `json:"duplicates,omitempty"`  // Identical requests collapsed into this one
 }
 
 // PermissionResponse is the decision for a permission request.
@@ -410,10 +938,11 @@ type PermissionRequestPayload struct {
 
 // PermissionRespondPayload is the payload for permission.respond requests.
 type PermissionRespondPayload struct {
-	ID        string `json:"id"`                // Permission request ID
-	Behavior  string `json:"behavior"`          // "allow" or "deny"
-	Message   string `json:"message,omitempty"` // Optional denial message
-	Interrupt bool   `json:"interrupt"`         // Stop Claude entirely
+	ID        string `json:"id"`                 // Permission request ID
+	Behavior  string `json:"behavior"`           // "allow" or "deny"
+	Message   string `json:"message,omitempty"`  // Optional denial message
+	Interrupt bool   `json:"interrupt"`          // Stop Claude entirely
+	Remember  bool   `json:"remember,omitempty"` // Persist the decision as a project rule
 }
 
 // PermissionListRequest is the payload for permission.list requests.
@@ -438,12 +967,16 @@ type UserQuestion struct {
 
 // QuestionItem represents a single question with options.
 type QuestionItem struct {
-	Question    string           `json:"question"`    // The full question text
-	Header      string           `json:"header"`      // Short label (max 12 chars) for display
-	MultiSelect bool             `json:"multiSelect"` // Allow multiple selections
-	Options     []QuestionOption `json:"options"`     // Available choices (2-4 options)
+	Question    string           `json:"question"`           // The full question text
+	Header      string           `json:"header"`             // Short label (max 12 chars) for display
+	MultiSelect bool             `json:"multiSelect"`        // Allow multiple selections
+	Options     []QuestionOption `json:"options"`            // Available choices (2-4 options)
+	Validate    string           `json:"validate,omitempty"` // Optional regexp a freeform "Other" answer must match
 }
 
+// MultiSelectSeparator joins the labels of a multi-select answer.
+const MultiSelectSeparator = ", "
+
 // QuestionOption represents a single option for a question.
 type QuestionOption struct {
 	Label       string `json:"label"`       // Short display text (1-5 words)
@@ -484,6 +1017,13 @@ type ClaimListResponse struct {
 	Claims []ClaimInfo `json:"claims"`
 }
 
+// ClaimReleaseRequest is the payload for claim.release requests.
+// The response payload is the ClaimInfo of the released claim.
+type ClaimReleaseRequest struct {
+	TicketID string `json:"ticket_id"`
+	Project  string `json:"project,omitempty"` // Required only if the ticket is claimed in several projects
+}
+
 // ClaimInfo describes a single ticket claim.
 type ClaimInfo struct {
 	TicketID string `json:"ticket_id"`
@@ -491,6 +1031,54 @@ type ClaimInfo struct {
 	Project  string `json:"project"`
 }
 
+// NoteAppendRequest is the payload for note.append requests.
+// The project is taken from the agent if Project is empty.
+type NoteAppendRequest struct {
+	AgentID string `json:"agent_id,omitempty"` // Agent ID (from FAB_AGENT_ID env), empty for the user
+	Project string `json:"project,omitempty"`
+	Text    string `json:"text"`
+}
+
+// NoteReadRequest is the payload for note.read requests.
+// The project is taken from the agent if Project is empty.
+type NoteReadRequest struct {
+	AgentID string `json:"agent_id,omitempty"`
+	Project string `json:"project,omitempty"`
+}
+
+// NoteReadResponse is the payload for note.read responses.
+type NoteReadResponse struct {
+	Project   string `json:"project"`
+	Path      string `json:"path"`                // Path of the notes file
+	Content   string `json:"content"`             // Empty if no notes were written
+	Truncated bool   `json:"truncated,omitempty"` // Only the most recent notes were returned
+}
+
+// TimelineRequest is the payload for timeline requests.
+type TimelineRequest struct {
+	Project string    `json:"project"`
+	Since   time.Time `json:"since,omitempty"` // Only events at or after this time (zero = all)
+	Limit   int       `json:"limit,omitempty"` // Newest events to return (0 = 1000)
+}
+
+// TimelineEvent is one entry in a project's activity timeline.
+type TimelineEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"` // "spawn", "claim", "merge", "pull_request", "merge_failed", "abort", or "config"
+	AgentID string    `json:"agent_id,omitempty"`
+	IssueID string    `json:"issue_id,omitempty"`
+	SHA     string    `json:"sha,omitempty"`    // Merged commit
+	Detail  string    `json:"detail,omitempty"` // e.g. who spawned the agent, the PR URL, or the config change
+}
+
+// TimelineResponse is the payload for timeline responses.
+type TimelineResponse struct {
+	Project   string          `json:"project"`
+	Path      string          `json:"path"`                // Path of the timeline file
+	Events    []TimelineEvent `json:"events"`              // Oldest first
+	Truncated bool            `json:"truncated,omitempty"` // Older events were left out to stay within Limit
+}
+
 // ManagerStartRequest is the payload for manager.start requests.
 type ManagerStartRequest struct {
 	Project string `json:"project"` // Project name (required)
@@ -515,6 +1103,11 @@ type ManagerStatusResponse struct {
 	WorkDir   string `json:"workdir"`    // Working directory (worktree path)
 }
 
+// ManagerListResponse is the payload for manager.list responses.
+type ManagerListResponse struct {
+	Managers []ManagerStatusResponse `json:"managers"` // Sorted by project
+}
+
 // ManagerSendMessageRequest is the payload for manager.send_message requests.
 type ManagerSendMessageRequest struct {
 	Project string `json:"project"` // Project name (required)
@@ -595,6 +1188,11 @@ type PlanStopRequest struct {
 // PlanListRequest is the payload for plan.list requests.
 type PlanListRequest struct {
 	Project string `json:"project,omitempty"` // Filter by project
+	State   string `json:"state,omitempty"`   // Filter by state ("stopped", "starting", "running", "stopping")
+
+	// CompletedOnly keeps only planners that have written their plan with
+	// 'fab plan write'.
+	CompletedOnly bool `json:"completed_only,omitempty"`
 }
 
 // PlanListResponse is the payload for plan.list responses.
@@ -611,6 +1209,7 @@ type PlannerStatus struct {
 	StartedAt   string `json:"started_at"`            // RFC3339 format
 	Description string `json:"description,omitempty"` // User-set description
 	Backend     string `json:"backend,omitempty"`     // CLI backend name (e.g., "claude", "codex")
+	PlanFile    string `json:"plan_file,omitempty"`   // Stored plan, once written
 }
 
 // PlanSendMessageRequest is the payload for plan.send_message requests.
@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestProjectConfigEntry_UnmarshalKeepsTypes(t *testing.T) {
+	in := ProjectConfigShowResponse{
+		Name: "app",
+		Entries: []ProjectConfigEntry{
+			{Key: "max-agents", Type: ConfigTypeInt, Value: 5, Default: 3, Set: true},
+			{Key: "autostart", Type: ConfigTypeBool, Value: true, Default: false, Set: true},
+			{Key: "model", Type: ConfigTypeString, Value: "", Default: ""},
+			{Key: "allowed-authors", Type: ConfigTypeList, Value: []string{"alice"}, Default: []string(nil), Set: true},
+		},
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var out ProjectConfigShowResponse
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := []ProjectConfigEntry{
+		{Key: "max-agents", Type: ConfigTypeInt, Value: 5, Default: 3, Set: true},
+		{Key: "autostart", Type: ConfigTypeBool, Value: true, Default: false, Set: true},
+		{Key: "model", Type: ConfigTypeString, Value: "", Default: ""},
+		{Key: "allowed-authors", Type: ConfigTypeList, Value: []string{"alice"}, Default: []string{}, Set: true},
+	}
+	if !reflect.DeepEqual(out.Entries, want) {
+		t.Errorf("Entries = %#v\nwant %#v", out.Entries, want)
+	}
+}
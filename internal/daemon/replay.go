@@ -0,0 +1,105 @@
+package daemon
+
+import (
+	"cmp"
+	"slices"
+)
+
+// maxReplayed caps how many buffered events one attach replays in total, so
+// the replay never fills a new client's queue before live events arrive.
+const maxReplayed = EventBufferSize / 2
+
+// replayedEvent is a buffered event and its position in the broadcast order.
+type replayedEvent struct {
+	seq   uint64
+	event *StreamEvent
+}
+
+// eventRing holds the most recent events broadcast for one project.
+type eventRing struct {
+	events []replayedEvent
+	next   int // Index the next event overwrites once the ring is full
+}
+
+// add records an event, overwriting the oldest once size are held.
+func (r *eventRing) add(ev replayedEvent, size int) {
+	if len(r.events) < size {
+		r.events = append(r.events, ev)
+		return
+	}
+	r.events[r.next] = ev
+	r.next = (r.next + 1) % size
+}
+
+// replayable reports whether an event type is worth replaying. Streaming
+// fragments are superseded by the chat entry that completes them.
+func replayable(event *StreamEvent) bool {
+	switch event.Type {
+	case "output", "chat_delta":
+		return false
+	default:
+		return true
+	}
+}
+
+// SetReplaySize sets how many recent events are kept per project for
+// replay to attaching clients. Zero disables replay and drops the buffers.
+func (s *Server) SetReplaySize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replaySize = max(size, 0)
+	s.replay = make(map[string]*eventRing)
+}
+
+// SetReplayFilter sets a check run on each buffered event before it is
+// replayed, so events that no longer apply (such as a permission request
+// that has been answered) are skipped. The filter is called with the
+// server's lock held and must not call back into the Server.
+func (s *Server) SetReplayFilter(filter func(*StreamEvent) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replayFilter = filter
+}
+
+// recordReplay buffers a broadcast event for replay.
+//
+// +checklocks:s.mu
+func (s *Server) recordReplay(event *StreamEvent) {
+	if s.replaySize == 0 || !replayable(event) {
+		return
+	}
+	ring, ok := s.replay[event.Project]
+	if !ok {
+		ring = &eventRing{}
+		s.replay[event.Project] = ring
+	}
+	s.replaySeq++
+	ring.add(replayedEvent{seq: s.replaySeq, event: event}, s.replaySize)
+}
+
+// replayFor returns the buffered events a client with the given project
+// filter should see, oldest first, limited to the newest maxReplayed.
+//
+// +checklocks:s.mu
+func (s *Server) replayFor(filter map[string]struct{}) []*StreamEvent {
+	var buffered []replayedEvent
+	for project, ring := range s.replay {
+		if filterAllows(filter, project) {
+			buffered = append(buffered, ring.events...)
+		}
+	}
+	slices.SortFunc(buffered, func(a, b replayedEvent) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+
+	events := make([]*StreamEvent, 0, len(buffered))
+	for _, ev := range buffered {
+		if s.replayFilter == nil || s.replayFilter(ev.event) {
+			events = append(events, ev.event)
+		}
+	}
+	if len(events) > maxReplayed {
+		events = events[len(events)-maxReplayed:]
+	}
+	return events
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sync"
@@ -86,15 +87,107 @@ type Server struct {
 	// +checklocks:mu
 	attached map[net.Conn]*attachedClient
 	// +checklocks:mu
+	sinks map[EventSink]map[string]struct{} // Sink -> project filter (nil means all)
+	// +checklocks:mu
+	replay map[string]*eventRing // Project -> recent events, replayed on attach (see replay.go)
+	// +checklocks:mu
+	replaySize int // Events kept per project; 0 (replay off) until SetReplaySize
+	// +checklocks:mu
+	replaySeq uint64
+	// +checklocks:mu
+	replayFilter func(*StreamEvent) bool
+	// +checklocks:mu
 	started bool
 	done    chan struct{}
+
+	// +checklocks:mu
+	httpServer *http.Server // Optional HTTP listener, set by StartHTTP
+	// +checklocks:mu
+	httpAddr string
+	// +checklocks:mu
+	allowedOrigins []string // Browser origins allowed on the HTTP listener
+	// +checklocks:mu
+	metricsHandler http.Handler // Serves /metrics on the HTTP listener if set
+
+	logMu sync.RWMutex // Separate from mu so logging never contends with connection bookkeeping
+	// +checklocks:logMu
+	logSubs map[net.Conn]*logSubscriber
 }
 
-// attachedClient tracks a client subscribed to streaming events.
+// EventBufferSize is how many stream events may be queued per attached
+// client before it is considered stuck and disconnected.
+const EventBufferSize = 256
+
+// attachedClient tracks a client subscribed to streaming events. Events are
+// queued by Broadcast and written by the client's own goroutine, so a slow
+// client can't hold up the others.
 type attachedClient struct {
-	encoder  *json.Encoder
-	projects []string    // Filter: empty means all projects (immutable after creation)
-	mu       *sync.Mutex // Shared mutex for all writes to the connection
+	conn      net.Conn
+	encoder   *json.Encoder
+	projects  map[string]struct{} // Filter: nil means all projects (immutable after creation)
+	mu        *sync.Mutex         // Shared mutex for all writes to the connection
+	events    chan *StreamEvent
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newProjectFilter builds a subscription filter from the requested projects.
+// Returns nil (all projects) if none are given.
+func newProjectFilter(projects []string) map[string]struct{} {
+	if len(projects) == 0 {
+		return nil
+	}
+	filter := make(map[string]struct{}, len(projects))
+	for _, p := range projects {
+		filter[p] = struct{}{}
+	}
+	return filter
+}
+
+// subscribed reports whether the client should receive events for project.
+// Filtered clients never receive events without a project.
+func (c *attachedClient) subscribed(project string) bool {
+	return filterAllows(c.projects, project)
+}
+
+// send queues an event without blocking. It returns false if the client's
+// queue is full.
+func (c *attachedClient) send(event *StreamEvent) bool {
+	select {
+	case <-c.done:
+		return true // Already detached
+	default:
+	}
+	select {
+	case c.events <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop ends the client's writer goroutine.
+func (c *attachedClient) stop() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+// filterAllows reports whether a project filter admits events for project.
+func filterAllows(filter map[string]struct{}, project string) bool {
+	if filter == nil {
+		return true
+	}
+	_, ok := filter[project]
+	return ok
+}
+
+// EventSink receives broadcast events outside the Unix socket protocol,
+// such as WebSocket clients on the HTTP listener.
+type EventSink interface {
+	// Send queues a JSON-encoded StreamEvent without blocking. It returns
+	// false if the sink can't keep up, in which case it is removed and closed.
+	Send(data []byte) bool
+	// Close disconnects the sink.
+	Close() error
 }
 
 // NewServer creates a new daemon server.
@@ -107,7 +200,10 @@ func NewServer(socketPath string, handler Handler) *Server {
 		handler:    handler,
 		conns:      make(map[net.Conn]struct{}),
 		attached:   make(map[net.Conn]*attachedClient),
+		sinks:      make(map[EventSink]map[string]struct{}),
+		replay:     make(map[string]*eventRing),
 		done:       make(chan struct{}),
+		logSubs:    make(map[net.Conn]*logSubscriber),
 	}
 }
 
@@ -194,9 +290,13 @@ func (s *Server) handleConnection(conn net.Conn) {
 		conn.Close()
 		s.mu.Lock()
 		delete(s.conns, conn)
-		delete(s.attached, conn)
+		if client, ok := s.attached[conn]; ok {
+			client.stop()
+			delete(s.attached, conn)
+		}
 		connCount := len(s.conns)
 		s.mu.Unlock()
+		s.UnsubscribeLogs(conn)
 		slog.Debug("client disconnected", "connections", connCount)
 	}()
 
@@ -233,6 +333,14 @@ func (s *Server) handleConnection(conn net.Conn) {
 		// Use base context (could add per-request timeout here)
 		ctx := baseCtx
 
+		// An attach starts streaming events on this connection, replayed
+		// ones right away; hold the write lock until its response is
+		// written so no event goes out first
+		attaching := req.Type == MsgAttach
+		if attaching {
+			writeMu.Lock()
+		}
+
 		// Dispatch to handler
 		resp := s.handler.Handle(ctx, &req)
 		if resp == nil {
@@ -256,7 +364,9 @@ func (s *Server) handleConnection(conn net.Conn) {
 			slog.Warn("request failed", "type", req.Type, "error", resp.Error)
 		}
 
-		writeMu.Lock()
+		if !attaching {
+			writeMu.Lock()
+		}
 		err := encoder.Encode(resp)
 		writeMu.Unlock()
 		if err != nil {
@@ -292,10 +402,32 @@ func (s *Server) Stop() error {
 	for conn := range s.conns {
 		conn.Close()
 	}
+	for _, client := range s.attached {
+		client.stop()
+	}
 	s.conns = make(map[net.Conn]struct{})
 	s.attached = make(map[net.Conn]*attachedClient)
+	sinks := s.sinks
+	s.sinks = make(map[EventSink]map[string]struct{})
+	httpServer := s.httpServer
+	s.httpServer = nil
 	s.mu.Unlock()
 
+	// Hijacked WebSocket connections aren't closed by http.Server.Close
+	if httpServer != nil {
+		_ = httpServer.Close()
+	}
+	for sink := range sinks {
+		_ = sink.Close()
+	}
+
+	s.logMu.Lock()
+	for conn, sub := range s.logSubs {
+		close(sub.done)
+		delete(s.logSubs, conn)
+	}
+	s.logMu.Unlock()
+
 	// Remove socket file
 	_ = os.Remove(s.socketPath)
 
@@ -315,70 +447,163 @@ func (s *Server) Addr() string {
 }
 
 // Attach registers a connection for streaming events.
+// If projects is non-empty, the connection only receives events for those projects.
+// Recent events for those projects are replayed first, in broadcast order,
+// so a client that attaches just after something happened still sees it.
 // The encoder and mutex are shared with the connection handler for synchronized writes.
 func (s *Server) Attach(conn net.Conn, projects []string, encoder *json.Encoder, mu *sync.Mutex) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.attached[conn] = &attachedClient{
+	client := &attachedClient{
+		conn:     conn,
 		encoder:  encoder,
-		projects: projects,
+		projects: newProjectFilter(projects),
 		mu:       mu,
+		events:   make(chan *StreamEvent, EventBufferSize),
+		done:     make(chan struct{}),
 	}
+
+	s.mu.Lock()
+	if old, ok := s.attached[conn]; ok {
+		old.stop()
+	}
+	// Queue the replay before registering, under the same lock Broadcast
+	// records under, so no event is missed or sent twice. The queue is
+	// empty and larger than any replay, so this never blocks.
+	for _, event := range s.replayFor(client.projects) {
+		client.events <- event
+	}
+	s.attached[conn] = client
+	s.mu.Unlock()
+
+	go s.writeEvents(client)
 }
 
 // Detach removes a connection from streaming events.
 func (s *Server) Detach(conn net.Conn) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	delete(s.attached, conn)
+	if client, ok := s.attached[conn]; ok {
+		client.stop()
+		delete(s.attached, conn)
+	}
+}
+
+// dropClient detaches a client that can't keep up and closes its
+// connection. Its stream has gaps (or a half-written event), so closing
+// makes it reconnect and refetch state rather than carry on out of sync.
+func (s *Server) dropClient(client *attachedClient) {
+	s.mu.Lock()
+	if s.attached[client.conn] == client {
+		delete(s.attached, client.conn)
+	}
+	s.mu.Unlock()
+	client.stop()
+	_ = client.conn.Close()
 }
 
-// BroadcastTimeout is how long to wait for a client write before giving up.
+// writeEvents drains a client's queue onto its connection.
+func (s *Server) writeEvents(client *attachedClient) {
+	defer logging.LogPanic("daemon-event-writer", nil)
+
+	for {
+		select {
+		case <-client.done:
+			return
+		case event := <-client.events:
+			// Set the deadline with the lock held, since the connection
+			// handler may be writing a response (see handleConnection)
+			client.mu.Lock()
+			_ = client.conn.SetWriteDeadline(time.Now().Add(BroadcastTimeout))
+			err := client.encoder.Encode(event)
+			_ = client.conn.SetWriteDeadline(time.Time{})
+			client.mu.Unlock()
+
+			if err != nil {
+				slog.Warn("dropping event stream client after failed write", "type", event.Type, "error", err)
+				s.dropClient(client)
+				return
+			}
+		}
+	}
+}
+
+// AddSink registers a sink for streaming events.
+// If projects is non-empty, the sink only receives events for those projects.
+func (s *Server) AddSink(sink EventSink, projects []string) {
+	filter := newProjectFilter(projects)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sinks[sink] = filter
+}
+
+// RemoveSink unregisters a sink. It does not close the sink.
+func (s *Server) RemoveSink(sink EventSink) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sinks, sink)
+}
+
+// SinkCount returns the number of registered event sinks.
+func (s *Server) SinkCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sinks)
+}
+
+// BroadcastTimeout is how long a write of one event to a client may take
+// before the client is given up on.
 const BroadcastTimeout = 100 * time.Millisecond
 
-// Broadcast sends a stream event to all attached clients.
+// Broadcast sends a stream event to all attached clients and sinks, and
+// buffers it for replay to clients that attach later.
 // Clients are filtered by their project subscriptions.
-// Uses a short write timeout to avoid blocking on slow clients.
+// It never waits on a write: events are queued per client and sink, and
+// clients or sinks whose queue is full are dropped.
 func (s *Server) Broadcast(event *StreamEvent) {
 	s.mu.Lock()
+	s.recordReplay(event)
 	clients := make([]*attachedClient, 0, len(s.attached))
-	conns := make([]net.Conn, 0, len(s.attached))
-	for conn, client := range s.attached {
+	for _, client := range s.attached {
 		clients = append(clients, client)
-		conns = append(conns, conn)
+	}
+	sinks := make(map[EventSink]map[string]struct{}, len(s.sinks))
+	for sink, filter := range s.sinks {
+		sinks[sink] = filter
 	}
 	s.mu.Unlock()
 
-	for i, client := range clients {
-		// Check if client is subscribed to this project
-		if len(client.projects) > 0 {
-			subscribed := false
-			for _, p := range client.projects {
-				if p == event.Project {
-					subscribed = true
-					break
-				}
-			}
-			if !subscribed {
-				continue
-			}
-		}
-
-		// Set write deadline to avoid blocking on slow/stuck clients
-		conn := conns[i]
-		_ = conn.SetWriteDeadline(time.Now().Add(BroadcastTimeout))
+	s.broadcastSinks(sinks, event)
 
-		// Send event (with per-client lock to prevent interleaving)
-		client.mu.Lock()
-		if err := client.encoder.Encode(event); err != nil {
-			slog.Debug("broadcast encode error", "type", event.Type, "error", err)
-		} else {
-			slog.Debug("broadcast sent", "type", event.Type, "agent", event.AgentID)
+	for _, client := range clients {
+		// Never leak events from projects the client didn't subscribe to
+		if !client.subscribed(event.Project) {
+			continue
+		}
+		if !client.send(event) {
+			slog.Warn("dropping slow event stream client", "type", event.Type, "queued", EventBufferSize)
+			s.dropClient(client)
 		}
-		client.mu.Unlock()
+	}
+}
 
-		// Clear write deadline
-		_ = conn.SetWriteDeadline(time.Time{})
+// broadcastSinks queues an event on each subscribed sink, dropping any
+// sink whose queue is full rather than waiting for it.
+func (s *Server) broadcastSinks(sinks map[EventSink]map[string]struct{}, event *StreamEvent) {
+	var data []byte
+	for sink, filter := range sinks {
+		if !filterAllows(filter, event.Project) {
+			continue
+		}
+		if data == nil {
+			var err error
+			if data, err = json.Marshal(event); err != nil {
+				slog.Debug("broadcast marshal error", "type", event.Type, "error", err)
+				return
+			}
+		}
+		if !sink.Send(data) {
+			slog.Debug("dropping slow event sink", "type", event.Type)
+			s.dropSink(sink)
+		}
 	}
 }
 
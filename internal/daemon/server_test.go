@@ -3,11 +3,16 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"log/slog"
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/tessro/fab/internal/logging"
 )
 
 // shortTempDir creates a temp directory with a short path for socket tests.
@@ -257,6 +262,78 @@ func TestServer_AttachBroadcast(t *testing.T) {
 	}
 }
 
+func TestServer_BroadcastDropsSlowClients(t *testing.T) {
+	srv := NewServer("", nil)
+
+	// Nothing reads from the slow client, so its writes time out
+	slowServer, slowClient := net.Pipe()
+	defer slowClient.Close()
+	fastServer, fastClient := net.Pipe()
+	defer fastClient.Close()
+	srv.Attach(slowServer, nil, json.NewEncoder(slowServer), &sync.Mutex{})
+	srv.Attach(fastServer, nil, json.NewEncoder(fastServer), &sync.Mutex{})
+
+	const n = 10
+	received := make(chan StreamEvent, n)
+	go func() {
+		decoder := json.NewDecoder(fastClient)
+		for {
+			var event StreamEvent
+			if err := decoder.Decode(&event); err != nil {
+				return
+			}
+			received <- event
+		}
+	}()
+
+	start := time.Now()
+	for i := range n {
+		srv.Broadcast(&StreamEvent{Type: "output", Data: strconv.Itoa(i)})
+	}
+	if elapsed := time.Since(start); elapsed > BroadcastTimeout {
+		t.Errorf("Broadcast took %v, want it not to wait on clients", elapsed)
+	}
+
+	for i := range n {
+		select {
+		case event := <-received:
+			if event.Data != strconv.Itoa(i) {
+				t.Errorf("event %d Data = %q, want %d", i, event.Data, i)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("fast client got %d of %d events", i, n)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for srv.AttachedCount() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("AttachedCount() = %d, want the slow client dropped", srv.AttachedCount())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := slowClient.Read(make([]byte, 1)); err == nil {
+		t.Error("slow client's connection is still open")
+	}
+}
+
+func TestAttachedClient_SendFullQueue(t *testing.T) {
+	c := &attachedClient{
+		events: make(chan *StreamEvent, 1),
+		done:   make(chan struct{}),
+	}
+	if !c.send(&StreamEvent{Type: "a"}) {
+		t.Fatal("first send() = false, want true")
+	}
+	if c.send(&StreamEvent{Type: "b"}) {
+		t.Error("send() on full queue = true, want false")
+	}
+	c.stop()
+	if !c.send(&StreamEvent{Type: "c"}) {
+		t.Error("send() after stop = false, want true (nothing left to drop)")
+	}
+}
+
 func TestServer_AttachWithProjectFilter(t *testing.T) {
 	tmpDir, cleanup := shortTempDir(t)
 	defer cleanup()
@@ -330,6 +407,170 @@ func TestServer_AttachWithProjectFilter(t *testing.T) {
 	}
 }
 
+func TestServer_AttachProjectIsolation(t *testing.T) {
+	tmpDir, cleanup := shortTempDir(t)
+	defer cleanup()
+	socketPath := filepath.Join(tmpDir, "test.sock")
+
+	handler := HandlerFunc(func(ctx context.Context, req *Request) *Response {
+		if req.Type == MsgAttach {
+			payload, err := decodePayload[AttachRequest](req.Payload)
+			if err != nil {
+				return &Response{Success: false, Error: err.Error()}
+			}
+			srv := ServerFromContext(ctx)
+			srv.Attach(ConnFromContext(ctx), payload.Projects, EncoderFromContext(ctx), WriteMuFromContext(ctx))
+		}
+		return &Response{Success: true}
+	})
+
+	srv := NewServer(socketPath, handler)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = srv.Stop() }()
+
+	// attach dials a new connection subscribed to the given project.
+	attach := func(project string) (net.Conn, *json.Decoder) {
+		conn, err := net.Dial("unix", socketPath)
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		decoder := json.NewDecoder(conn)
+		req := &Request{Type: MsgAttach, Payload: AttachRequest{Projects: []string{project}}}
+		if err := json.NewEncoder(conn).Encode(req); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		var resp Response
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if !resp.Success {
+			t.Fatalf("attach failed: %s", resp.Error)
+		}
+		return conn, decoder
+	}
+
+	connA, decA := attach("project-a")
+	defer connA.Close()
+	connB, decB := attach("project-b")
+	defer connB.Close()
+
+	if got := srv.AttachedCount(); got != 2 {
+		t.Fatalf("AttachedCount() = %d, want 2", got)
+	}
+
+	srv.Broadcast(&StreamEvent{Type: "chat_entry", AgentID: "agent-a", Project: "project-a", Data: "secret-a"})
+	srv.Broadcast(&StreamEvent{Type: "chat_entry", AgentID: "agent-b", Project: "project-b", Data: "secret-b"})
+	srv.Broadcast(&StreamEvent{Type: "director_state", DirectorState: "running"})
+
+	// received collects every event delivered before the read deadline.
+	received := func(conn net.Conn, dec *json.Decoder) []string {
+		_ = conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		var data []string
+		for {
+			var event StreamEvent
+			if err := dec.Decode(&event); err != nil {
+				return data
+			}
+			data = append(data, event.Project+":"+event.Data)
+		}
+	}
+
+	if got := received(connA, decA); len(got) != 1 || got[0] != "project-a:secret-a" {
+		t.Errorf("project-a client received %v, want only project-a's event", got)
+	}
+	if got := received(connB, decB); len(got) != 1 || got[0] != "project-b:secret-b" {
+		t.Errorf("project-b client received %v, want only project-b's event", got)
+	}
+}
+
+func TestServer_LogSubscribe(t *testing.T) {
+	tmpDir, cleanup := shortTempDir(t)
+	defer cleanup()
+	socketPath := filepath.Join(tmpDir, "test.sock")
+
+	handler := HandlerFunc(func(ctx context.Context, req *Request) *Response {
+		if req.Type == MsgLogSubscribe {
+			conn := ConnFromContext(ctx)
+			srv := ServerFromContext(ctx)
+			encoder := EncoderFromContext(ctx)
+			writeMu := WriteMuFromContext(ctx)
+			srv.SubscribeLogs(conn, slog.LevelInfo, encoder, writeMu)
+		}
+		return &Response{Success: true}
+	})
+
+	srv := NewServer(socketPath, handler)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = srv.Stop() }()
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	encoder := json.NewEncoder(conn)
+	decoder := json.NewDecoder(conn)
+
+	if err := encoder.Encode(&Request{Type: MsgLogSubscribe}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	var resp Response
+	if err := decoder.Decode(&resp); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if srv.LogSubscriberCount() != 1 {
+		t.Fatalf("expected 1 log subscriber, got %d", srv.LogSubscriberCount())
+	}
+	if srv.Enabled(slog.LevelDebug) {
+		t.Error("Enabled(debug) = true, want false for info subscriber")
+	}
+	if !srv.Enabled(slog.LevelWarn) {
+		t.Error("Enabled(warn) = false, want true for info subscriber")
+	}
+
+	// Debug record should be filtered; the info record should arrive.
+	srv.HandleRecord(logging.Record{Time: time.Now(), Level: slog.LevelDebug, Message: "hidden"})
+	srv.HandleRecord(logging.Record{
+		Time:    time.Now(),
+		Level:   slog.LevelInfo,
+		Message: "agent started",
+		Attrs:   map[string]string{"agent": "abc123"},
+	})
+
+	var event StreamEvent
+	if err := decoder.Decode(&event); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if event.Type != "log" || event.Log == nil {
+		t.Fatalf("expected log event, got %+v", event)
+	}
+	if event.Log.Message != "agent started" {
+		t.Errorf("Message = %q, want %q", event.Log.Message, "agent started")
+	}
+	if event.Log.Level != "INFO" {
+		t.Errorf("Level = %q, want INFO", event.Log.Level)
+	}
+	if event.Log.Attrs["agent"] != "abc123" {
+		t.Errorf("Attrs[agent] = %q, want abc123", event.Log.Attrs["agent"])
+	}
+
+	// Disconnecting removes the subscription.
+	conn.Close()
+	deadline := time.Now().Add(time.Second)
+	for srv.LogSubscriberCount() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if srv.LogSubscriberCount() != 0 {
+		t.Errorf("expected 0 log subscribers after disconnect, got %d", srv.LogSubscriberCount())
+	}
+}
+
 func TestDefaultSocketPath(t *testing.T) {
 	path := DefaultSocketPath()
 	if path == "" {
@@ -0,0 +1,80 @@
+package daemon
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// stateRanks orders agent states for AgentSortState: states that need the
+// user come first, finished ones last. Unknown states sort after the ones
+// listed here.
+var stateRanks = map[string]int{
+	"error":    0,
+	"stuck":    1,
+	"idle":     2,
+	"running":  3,
+	"starting": 4,
+	"stopping": 5,
+	"done":     6,
+	"stopped":  7,
+}
+
+// ValidateAgentSort checks the sort key and order of an agent.list request.
+// Empty values are valid and mean the daemon's order and ascending.
+func ValidateAgentSort(sortBy, order string) error {
+	switch sortBy {
+	case "", AgentSortState, AgentSortProject, AgentSortStarted:
+	default:
+		return fmt.Errorf("invalid sort %q: must be %q, %q, or %q", sortBy, AgentSortState, AgentSortProject, AgentSortStarted)
+	}
+	switch order {
+	case "", SortAscending, SortDescending:
+	default:
+		return fmt.Errorf("invalid order %q: must be %q or %q", order, SortAscending, SortDescending)
+	}
+	return nil
+}
+
+// SortAgents stably sorts agents in place by sortBy, so agents that compare
+// equal keep their relative order. An empty sortBy leaves the list as is.
+// Callers should validate sortBy and order with ValidateAgentSort first.
+func SortAgents(agents []AgentStatus, sortBy, order string) {
+	var compare func(a, b AgentStatus) int
+	switch sortBy {
+	case AgentSortState:
+		compare = func(a, b AgentStatus) int {
+			// Attention beats any state
+			if a.NeedsAttention != b.NeedsAttention {
+				if a.NeedsAttention {
+					return -1
+				}
+				return 1
+			}
+			return cmp.Compare(stateRank(a.State), stateRank(b.State))
+		}
+	case AgentSortProject:
+		compare = func(a, b AgentStatus) int {
+			return cmp.Compare(a.Project, b.Project)
+		}
+	case AgentSortStarted:
+		compare = func(a, b AgentStatus) int {
+			return a.StartedAt.Compare(b.StartedAt)
+		}
+	default:
+		return
+	}
+
+	if order == SortDescending {
+		asc := compare
+		compare = func(a, b AgentStatus) int { return asc(b, a) }
+	}
+	slices.SortStableFunc(agents, compare)
+}
+
+func stateRank(state string) int {
+	if rank, ok := stateRanks[state]; ok {
+		return rank
+	}
+	return len(stateRanks)
+}
@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSortAgents(t *testing.T) {
+	now := time.Now()
+	agents := func() []AgentStatus {
+		return []AgentStatus{
+			{ID: "a", Project: "web", State: "running", StartedAt: now.Add(-1 * time.Minute)},
+			{ID: "b", Project: "api", State: "done", StartedAt: now.Add(-4 * time.Minute)},
+			{ID: "c", Project: "web", State: "idle", StartedAt: now.Add(-2 * time.Minute)},
+			{ID: "d", Project: "api", State: "running", StartedAt: now, NeedsAttention: true},
+			{ID: "e", Project: "web", State: "mystery", StartedAt: now.Add(-3 * time.Minute)},
+		}
+	}
+
+	tests := []struct {
+		sortBy string
+		order  string
+		want   string
+	}{
+		{"", "", "a,b,c,d,e"},
+		{AgentSortState, "", "d,c,a,b,e"},
+		{AgentSortState, SortDescending, "e,b,a,c,d"},
+		// Ties keep their original order
+		{AgentSortProject, "", "b,d,a,c,e"},
+		{AgentSortProject, SortDescending, "a,c,e,b,d"},
+		{AgentSortStarted, "", "b,e,c,a,d"},
+	}
+
+	for _, tt := range tests {
+		list := agents()
+		SortAgents(list, tt.sortBy, tt.order)
+		var ids []string
+		for _, a := range list {
+			ids = append(ids, a.ID)
+		}
+		if got := strings.Join(ids, ","); got != tt.want {
+			t.Errorf("SortAgents(%q, %q) = %s, want %s", tt.sortBy, tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAgentSort(t *testing.T) {
+	if err := ValidateAgentSort("", ""); err != nil {
+		t.Errorf("ValidateAgentSort(\"\", \"\") error = %v", err)
+	}
+	if err := ValidateAgentSort(AgentSortState, SortDescending); err != nil {
+		t.Errorf("ValidateAgentSort(state, desc) error = %v", err)
+	}
+	if err := ValidateAgentSort("name", ""); err == nil {
+		t.Error("ValidateAgentSort(name) should fail")
+	}
+	if err := ValidateAgentSort(AgentSortState, "up"); err == nil {
+		t.Error("ValidateAgentSort(state, up) should fail")
+	}
+}
@@ -0,0 +1,324 @@
+package daemon
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tessro/fab/internal/logging"
+)
+
+// WebSocketQueueSize is how many events may be queued per WebSocket client.
+// Clients that fall this far behind are disconnected so they never slow
+// down Broadcast.
+const WebSocketQueueSize = 256
+
+// webSocketWriteTimeout bounds a single frame write to a WebSocket client.
+const webSocketWriteTimeout = 10 * time.Second
+
+// webSocketMaxReadPayload caps frames read from clients. Clients only send
+// control frames, which are limited to 125 bytes by RFC 6455.
+const webSocketMaxReadPayload = 4096
+
+// webSocketGUID is appended to the client key to compute Sec-WebSocket-Accept.
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes (RFC 6455 section 5.2).
+const (
+	wsOpText  byte = 0x1
+	wsOpClose byte = 0x8
+	wsOpPing  byte = 0x9
+	wsOpPong  byte = 0xA
+)
+
+// handleWebSocket upgrades an HTTP request and registers the connection as
+// an event sink. The optional projects query parameter (comma-separated or
+// repeated) limits events to those projects.
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+		return
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return
+	}
+	if !s.originAllowed(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, brw, err := hj.Hijack()
+	if err != nil {
+		slog.Warn("websocket hijack failed", "error", err)
+		return
+	}
+
+	_ = conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+	_, err = fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", webSocketAccept(key))
+	if err == nil {
+		err = brw.Flush()
+	}
+	_ = conn.SetWriteDeadline(time.Time{})
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	client := &wsClient{
+		conn:   conn,
+		reader: brw.Reader,
+		queue:  make(chan []byte, WebSocketQueueSize),
+		done:   make(chan struct{}),
+	}
+	s.AddSink(client, queryProjects(r.URL.Query()))
+	slog.Debug("websocket client connected", "remote", conn.RemoteAddr())
+
+	go s.writeWebSocket(client)
+	go s.readWebSocket(client)
+}
+
+// originAllowed reports whether a browser origin may connect. Requests
+// without an Origin header come from non-browser clients and are allowed.
+func (s *Server) originAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+
+	s.mu.Lock()
+	allowed := s.allowedOrigins
+	s.mu.Unlock()
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryProjects returns the project filter from a ?projects= query.
+func queryProjects(q url.Values) []string {
+	var projects []string
+	for _, v := range q["projects"] {
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				projects = append(projects, p)
+			}
+		}
+	}
+	return projects
+}
+
+// headerHasToken reports whether a comma-separated header contains token.
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// webSocketAccept computes the Sec-WebSocket-Accept value for a client key.
+func webSocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + webSocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// wsClient is an EventSink that forwards events to a WebSocket connection.
+type wsClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	queue     chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+
+	writeMu sync.Mutex // Serializes frame writes from the write and read loops
+}
+
+// Send implements EventSink. It queues the event as a text frame and
+// reports false if the client is closed or too far behind.
+func (c *wsClient) Send(data []byte) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+	select {
+	case c.queue <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close implements EventSink.
+func (c *wsClient) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.conn.Close()
+	})
+	return err
+}
+
+// writeFrame writes a single unfragmented frame.
+func (c *wsClient) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+	defer func() { _ = c.conn.SetWriteDeadline(time.Time{}) }()
+	return writeWebSocketFrame(c.conn, opcode, payload)
+}
+
+// writeWebSocket drains a client's queue onto its connection.
+func (s *Server) writeWebSocket(c *wsClient) {
+	defer logging.LogPanic("daemon-websocket-writer", nil)
+	defer s.dropSink(c)
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case data := <-c.queue:
+			if err := c.writeFrame(wsOpText, data); err != nil {
+				slog.Debug("websocket write failed", "error", err)
+				return
+			}
+		}
+	}
+}
+
+// readWebSocket answers pings and close frames until the client disconnects.
+// Data frames from the client are ignored.
+func (s *Server) readWebSocket(c *wsClient) {
+	defer logging.LogPanic("daemon-websocket-reader", nil)
+	defer s.dropSink(c)
+
+	for {
+		opcode, payload, err := readWebSocketFrame(c.reader)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				slog.Debug("websocket read failed", "error", err)
+			}
+			return
+		}
+		switch opcode {
+		case wsOpClose:
+			_ = c.writeFrame(wsOpClose, payload)
+			return
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// dropSink unregisters and closes a sink.
+func (s *Server) dropSink(sink EventSink) {
+	s.RemoveSink(sink)
+	_ = sink.Close()
+}
+
+// writeWebSocketFrame writes an unmasked, unfragmented server frame.
+func writeWebSocketFrame(w io.Writer, opcode byte, payload []byte) error {
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode // FIN + opcode
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	if _, err := w.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readWebSocketFrame reads a single frame from a client, unmasking its payload.
+// Fragmented messages are returned frame by frame.
+func readWebSocketFrame(r *bufio.Reader) (opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode = head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > webSocketMaxReadPayload {
+		return 0, nil, fmt.Errorf("websocket frame too large: %d bytes", length)
+	}
+	if !masked {
+		return 0, nil, errors.New("websocket client frame not masked")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
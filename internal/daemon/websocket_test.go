@@ -0,0 +1,369 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newHTTPTestServer starts a server with its HTTP listener on a free port.
+func newHTTPTestServer(t *testing.T, allowedOrigins []string) *Server {
+	t.Helper()
+	tmpDir, cleanup := shortTempDir(t)
+	t.Cleanup(cleanup)
+
+	srv := NewServer(filepath.Join(tmpDir, "test.sock"), HandlerFunc(func(ctx context.Context, req *Request) *Response {
+		return &Response{Success: true}
+	}))
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+	if err := srv.StartHTTP("127.0.0.1:0", allowedOrigins, ""); err != nil {
+		t.Fatalf("StartHTTP() error = %v", err)
+	}
+	return srv
+}
+
+// dialWebSocket performs a WebSocket handshake and returns the connection
+// and the HTTP status code.
+func dialWebSocket(t *testing.T, addr, path, origin string) (net.Conn, *bufio.Reader, int) {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	req := fmt.Sprintf("GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n", path, addr, key)
+	if origin != "" {
+		req += "Origin: " + origin + "\r\n"
+	}
+	if _, err := conn.Write([]byte(req + "\r\n")); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("read handshake response: %v", err)
+	}
+	if resp.StatusCode == http.StatusSwitchingProtocols {
+		if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+			t.Errorf("Sec-WebSocket-Accept = %q", got)
+		}
+	}
+	return conn, reader, resp.StatusCode
+}
+
+// readEvent reads a text frame and decodes it as a StreamEvent.
+func readEvent(t *testing.T, conn net.Conn, reader *bufio.Reader) *StreamEvent {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var head [2]byte
+	if _, err := io.ReadFull(reader, head[:]); err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+	if head[0] != 0x80|wsOpText {
+		t.Fatalf("frame header = %#x, want final text frame", head[0])
+	}
+	length := int(head[1])
+	if length >= 126 {
+		t.Fatalf("unexpected extended length %d in test event", length)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	var event StreamEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	return &event
+}
+
+// waitForSinks waits until the server has n registered sinks.
+func waitForSinks(t *testing.T, srv *Server, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for srv.SinkCount() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("SinkCount() = %d, want %d", srv.SinkCount(), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestServer_WebSocketProjectFilter(t *testing.T) {
+	srv := newHTTPTestServer(t, nil)
+
+	conn, reader, status := dialWebSocket(t, srv.HTTPAddr(), "/events?projects=alpha,beta", "")
+	if status != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want 101", status)
+	}
+	waitForSinks(t, srv, 1)
+
+	srv.Broadcast(&StreamEvent{Type: "output", Project: "gamma", AgentID: "g1"})
+	srv.Broadcast(&StreamEvent{Type: "output", Project: "beta", AgentID: "b1"})
+
+	event := readEvent(t, conn, reader)
+	if event.Project != "beta" || event.AgentID != "b1" {
+		t.Errorf("got event for %s/%s, want beta/b1", event.Project, event.AgentID)
+	}
+}
+
+func TestServer_WebSocketPingAndClose(t *testing.T) {
+	srv := newHTTPTestServer(t, nil)
+
+	conn, reader, status := dialWebSocket(t, srv.HTTPAddr(), "/events", "")
+	if status != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want 101", status)
+	}
+	waitForSinks(t, srv, 1)
+
+	// Client frames must be masked
+	writeMasked := func(opcode byte, payload []byte) {
+		mask := []byte{1, 2, 3, 4}
+		frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+		frame = append(frame, mask...)
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+		if _, err := conn.Write(frame); err != nil {
+			t.Fatalf("write frame: %v", err)
+		}
+	}
+
+	writeMasked(wsOpPing, []byte("hi"))
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	pong := make([]byte, 4)
+	if _, err := io.ReadFull(reader, pong); err != nil {
+		t.Fatalf("read pong: %v", err)
+	}
+	if !bytes.Equal(pong, []byte{0x80 | wsOpPong, 2, 'h', 'i'}) {
+		t.Errorf("pong = %v", pong)
+	}
+
+	writeMasked(wsOpClose, nil)
+	waitForSinks(t, srv, 0)
+}
+
+func TestServer_WebSocketOrigin(t *testing.T) {
+	srv := newHTTPTestServer(t, []string{"http://localhost:3000"})
+
+	tests := []struct {
+		origin string
+		want   int
+	}{
+		{"", http.StatusSwitchingProtocols},
+		{"http://localhost:3000", http.StatusSwitchingProtocols},
+		{"http://" + srv.HTTPAddr(), http.StatusSwitchingProtocols},
+		{"https://evil.example", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		_, _, status := dialWebSocket(t, srv.HTTPAddr(), "/events", tt.origin)
+		if status != tt.want {
+			t.Errorf("origin %q: status = %d, want %d", tt.origin, status, tt.want)
+		}
+	}
+}
+
+func TestServer_WebSocketRejectsPlainRequest(t *testing.T) {
+	srv := newHTTPTestServer(t, nil)
+
+	resp, err := http.Get("http://" + srv.HTTPAddr() + "/events")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestServer_HTTPToken(t *testing.T) {
+	tmpDir, cleanup := shortTempDir(t)
+	t.Cleanup(cleanup)
+
+	srv := NewServer(filepath.Join(tmpDir, "test.sock"), HandlerFunc(func(ctx context.Context, req *Request) *Response {
+		return &Response{Success: true}
+	}))
+	if err := srv.StartHTTP("127.0.0.1:0", nil, "s3cret"); err != nil {
+		t.Fatalf("StartHTTP() error = %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/events", http.StatusUnauthorized},
+		{"/events?token=wrong", http.StatusUnauthorized},
+		{"/events?token=s3cret", http.StatusSwitchingProtocols},
+	}
+	for _, tt := range tests {
+		_, _, status := dialWebSocket(t, srv.HTTPAddr(), tt.path, "")
+		if status != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.path, status, tt.want)
+		}
+	}
+
+	// A bearer header passes authentication; the plain request then fails the upgrade
+	req, _ := http.NewRequest(http.MethodGet, "http://"+srv.HTTPAddr()+"/events", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status with bearer token = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestServer_HTTPRequiresTokenOffLoopback(t *testing.T) {
+	tmpDir, cleanup := shortTempDir(t)
+	t.Cleanup(cleanup)
+
+	srv := NewServer(filepath.Join(tmpDir, "test.sock"), HandlerFunc(func(ctx context.Context, req *Request) *Response {
+		return &Response{Success: true}
+	}))
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	if err := srv.StartHTTP("0.0.0.0:0", nil, ""); err == nil {
+		t.Fatal("StartHTTP() on all interfaces without a token succeeded, want error")
+	}
+	if srv.HTTPAddr() != "" {
+		t.Errorf("HTTPAddr() = %q after refused start, want empty", srv.HTTPAddr())
+	}
+	if err := srv.StartHTTP("0.0.0.0:0", nil, "s3cret"); err != nil {
+		t.Errorf("StartHTTP() with a token error = %v", err)
+	}
+}
+
+// stuckSink never accepts events, like a client that stopped reading.
+type stuckSink struct {
+	closed chan struct{}
+}
+
+func (s *stuckSink) Send([]byte) bool { return false }
+
+func (s *stuckSink) Close() error {
+	close(s.closed)
+	return nil
+}
+
+// recordingSink records every event it is sent.
+type recordingSink struct {
+	events [][]byte
+}
+
+func (s *recordingSink) Send(data []byte) bool {
+	s.events = append(s.events, data)
+	return true
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func TestServer_BroadcastDropsSlowSinks(t *testing.T) {
+	srv := NewServer("", nil)
+
+	slow := &stuckSink{closed: make(chan struct{})}
+	fast := &recordingSink{}
+	srv.AddSink(slow, nil)
+	srv.AddSink(fast, nil)
+
+	done := make(chan struct{})
+	go func() {
+		srv.Broadcast(&StreamEvent{Type: "output", Project: "p"})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Broadcast blocked on a slow sink")
+	}
+
+	select {
+	case <-slow.closed:
+	default:
+		t.Error("slow sink was not closed")
+	}
+	if got := srv.SinkCount(); got != 1 {
+		t.Errorf("SinkCount() = %d, want 1", got)
+	}
+	if len(fast.events) != 1 || !strings.Contains(string(fast.events[0]), `"type":"output"`) {
+		t.Errorf("fast sink events = %q", fast.events)
+	}
+}
+
+func TestWebSocketClient_SendFullQueue(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	c := &wsClient{
+		conn:  server,
+		queue: make(chan []byte, 1),
+		done:  make(chan struct{}),
+	}
+	if !c.Send([]byte("a")) {
+		t.Fatal("first Send() = false, want true")
+	}
+	if c.Send([]byte("b")) {
+		t.Error("Send() on full queue = true, want false")
+	}
+	_ = c.Close()
+	if c.Send([]byte("c")) {
+		t.Error("Send() after Close = true, want false")
+	}
+}
+
+func TestServer_HTTPMetrics(t *testing.T) {
+	tmpDir, cleanup := shortTempDir(t)
+	t.Cleanup(cleanup)
+
+	srv := NewServer(filepath.Join(tmpDir, "test.sock"), HandlerFunc(func(ctx context.Context, req *Request) *Response {
+		return &Response{Success: true}
+	}))
+	srv.SetMetricsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "fab_active_projects 1\n")
+	}))
+	if err := srv.StartHTTP("127.0.0.1:0", nil, ""); err != nil {
+		t.Fatalf("StartHTTP() error = %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	resp, err := http.Get("http://" + srv.HTTPAddr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "fab_active_projects 1\n" {
+		t.Errorf("GET /metrics = %d %q", resp.StatusCode, body)
+	}
+
+	// Without a handler, /metrics isn't served
+	plain := newHTTPTestServer(t, nil)
+	resp, err = http.Get("http://" + plain.HTTPAddr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /metrics without handler = %d, want 404", resp.StatusCode)
+	}
+}
@@ -125,7 +125,7 @@ func (d *Director) buildCommand() (*exec.Cmd, error) {
 	settings := d.buildSettings()
 
 	// Use backend to build the command
-	return d.backend.BuildCommand(backend.CommandConfig{
+	return backend.Command(d.backend, backend.CommandConfig{
 		WorkDir:   d.WorkDir(),
 		AgentID:   "director",
 		PluginDir: plugin.DefaultInstallDir(),
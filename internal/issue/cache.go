@@ -0,0 +1,235 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheStats counts cache hits and misses for List and Ready.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// cacheEntry is a memoized List or Ready result.
+type cacheEntry struct {
+	issues  []*Issue
+	expires time.Time
+}
+
+// CachedBackend wraps a Backend and memoizes List and Ready results for a
+// short TTL. It implements CollaborativeBackend by passing comments and
+// plans through to the wrapped backend, returning ErrNotSupported if that
+// backend isn't collaborative. Writes through the wrapper (Create,
+// CreateSubIssue, Update, Close, AddComment, UpsertPlanSection) invalidate
+// the cache; changes made elsewhere (for example by an
+// agent running 'fab issue close') are only seen after the TTL expires or
+// Invalidate is called.
+type CachedBackend struct {
+	Backend
+	ttl time.Duration
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+
+	mu sync.Mutex
+	// +checklocks:mu
+	entries map[string]cacheEntry // "ready" or "list:<filter>" -> result
+	// +checklocks:mu
+	generation uint64 // Bumped on invalidation so in-flight fetches aren't cached
+}
+
+// NewCachedBackend wraps b with a cache whose entries live for ttl.
+func NewCachedBackend(b Backend, ttl time.Duration) *CachedBackend {
+	return &CachedBackend{
+		Backend: b,
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// Ready returns cached ready issues, fetching them if the cache is stale.
+func (c *CachedBackend) Ready(ctx context.Context) ([]*Issue, error) {
+	return c.cached("ready", func() ([]*Issue, error) {
+		return c.Backend.Ready(ctx)
+	})
+}
+
+// List returns cached issues matching filter, fetching them if the cache is stale.
+func (c *CachedBackend) List(ctx context.Context, filter ListFilter) ([]*Issue, error) {
+	key := fmt.Sprintf("list:%v|%v", filter.Status, filter.Labels)
+	return c.cached(key, func() ([]*Issue, error) {
+		return c.Backend.List(ctx, filter)
+	})
+}
+
+// cached returns the entry for key if it is fresh, and otherwise calls fetch
+// and stores its result. Results are copied so callers can't mutate the cache.
+func (c *CachedBackend) cached(key string, fetch func() ([]*Issue, error)) ([]*Issue, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	gen := c.generation
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expires) {
+		c.hits.Add(1)
+		return cloneIssues(entry.issues), nil
+	}
+	c.misses.Add(1)
+
+	issues, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	// Don't cache a result that may predate a concurrent invalidation
+	if c.generation == gen {
+		c.entries[key] = cacheEntry{issues: cloneIssues(issues), expires: time.Now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	return issues, nil
+}
+
+// Invalidate drops all cached results.
+func (c *CachedBackend) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+	c.generation++
+}
+
+// Stats returns the cache hit and miss counts.
+func (c *CachedBackend) Stats() CacheStats {
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+// Create creates an issue and invalidates the cache.
+func (c *CachedBackend) Create(ctx context.Context, params CreateParams) (*Issue, error) {
+	defer c.Invalidate()
+	return c.Backend.Create(ctx, params)
+}
+
+// CreateSubIssue creates a child issue and invalidates the cache.
+func (c *CachedBackend) CreateSubIssue(ctx context.Context, parentID string, params CreateParams) (*Issue, error) {
+	defer c.Invalidate()
+	return c.Backend.CreateSubIssue(ctx, parentID, params)
+}
+
+// Update modifies an issue and invalidates the cache.
+func (c *CachedBackend) Update(ctx context.Context, id string, params UpdateParams) (*Issue, error) {
+	defer c.Invalidate()
+	return c.Backend.Update(ctx, id, params)
+}
+
+// Close closes an issue and invalidates the cache.
+func (c *CachedBackend) Close(ctx context.Context, id string) error {
+	defer c.Invalidate()
+	return c.Backend.Close(ctx, id)
+}
+
+// AddComment adds a comment to an issue and invalidates the cache.
+func (c *CachedBackend) AddComment(ctx context.Context, id string, body string) error {
+	collab, ok := c.Backend.(IssueCollaborator)
+	if !ok {
+		return ErrNotSupported
+	}
+	defer c.Invalidate()
+	return collab.AddComment(ctx, id, body)
+}
+
+// ListComments returns an issue's comments from the wrapped backend. Comments
+// aren't cached.
+func (c *CachedBackend) ListComments(ctx context.Context, id string, since time.Time) ([]*Comment, error) {
+	collab, ok := c.Backend.(IssueCollaborator)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return collab.ListComments(ctx, id, since)
+}
+
+// UpsertPlanSection updates an issue's plan section and invalidates the cache.
+func (c *CachedBackend) UpsertPlanSection(ctx context.Context, id string, planContent string) error {
+	collab, ok := c.Backend.(IssueCollaborator)
+	if !ok {
+		return ErrNotSupported
+	}
+	defer c.Invalidate()
+	return collab.UpsertPlanSection(ctx, id, planContent)
+}
+
+// cloneIssues returns a copy of issues with each issue copied.
+func cloneIssues(issues []*Issue) []*Issue {
+	if issues == nil {
+		return nil
+	}
+	out := make([]*Issue, len(issues))
+	for i, iss := range issues {
+		cp := *iss
+		out[i] = &cp
+	}
+	return out
+}
+
+// BackendCache hands out one CachedBackend per repo directory, so the cache
+// outlives the short-lived backends a NewBackendFunc would otherwise create.
+type BackendCache struct {
+	factory NewBackendFunc
+	ttl     time.Duration
+
+	mu sync.Mutex
+	// +checklocks:mu
+	backends map[string]*CachedBackend
+}
+
+// NewBackendCache wraps factory so backends it creates are cached for ttl.
+func NewBackendCache(factory NewBackendFunc, ttl time.Duration) *BackendCache {
+	return &BackendCache{
+		factory:  factory,
+		ttl:      ttl,
+		backends: make(map[string]*CachedBackend),
+	}
+}
+
+// New returns the cached backend for repoDir, creating it on first use.
+// It has the signature of a NewBackendFunc.
+func (c *BackendCache) New(repoDir string) (Backend, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if b, ok := c.backends[repoDir]; ok {
+		return b, nil
+	}
+	b, err := c.factory(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	cached := NewCachedBackend(b, c.ttl)
+	c.backends[repoDir] = cached
+	return cached, nil
+}
+
+// Invalidate drops cached results for every backend.
+func (c *BackendCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, b := range c.backends {
+		b.Invalidate()
+	}
+}
+
+// Stats returns the combined hit and miss counts of every backend.
+func (c *BackendCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var stats CacheStats
+	for _, b := range c.backends {
+		s := b.Stats()
+		stats.Hits += s.Hits
+		stats.Misses += s.Misses
+	}
+	return stats
+}
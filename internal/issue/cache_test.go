@@ -0,0 +1,155 @@
+package issue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingBackend counts Ready and List calls.
+type countingBackend struct {
+	Backend
+	readyCalls int
+	listCalls  int
+	issues     []*Issue
+}
+
+func (b *countingBackend) Ready(ctx context.Context) ([]*Issue, error) {
+	b.readyCalls++
+	return b.issues, nil
+}
+
+func (b *countingBackend) List(ctx context.Context, filter ListFilter) ([]*Issue, error) {
+	b.listCalls++
+	return b.issues, nil
+}
+
+func (b *countingBackend) Close(ctx context.Context, id string) error {
+	return nil
+}
+
+func TestCachedBackend(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingBackend{issues: []*Issue{{ID: "1", Title: "first"}}}
+	c := NewCachedBackend(inner, time.Hour)
+
+	for range 3 {
+		if _, err := c.Ready(ctx); err != nil {
+			t.Fatalf("Ready() error = %v", err)
+		}
+	}
+	if inner.readyCalls != 1 {
+		t.Errorf("inner Ready calls = %d, want 1", inner.readyCalls)
+	}
+	if got := c.Stats(); got != (CacheStats{Hits: 2, Misses: 1}) {
+		t.Errorf("Stats() = %+v, want 2 hits, 1 miss", got)
+	}
+
+	// Callers can't mutate cached issues
+	issues, _ := c.Ready(ctx)
+	issues[0].Title = "changed"
+	if issues, _ := c.Ready(ctx); issues[0].Title != "first" {
+		t.Errorf("cached Title = %q, want %q", issues[0].Title, "first")
+	}
+
+	// List results are cached per filter
+	_, _ = c.List(ctx, ListFilter{Status: []Status{StatusOpen}})
+	_, _ = c.List(ctx, ListFilter{Status: []Status{StatusOpen}})
+	_, _ = c.List(ctx, ListFilter{Labels: []string{"bug"}})
+	if inner.listCalls != 2 {
+		t.Errorf("inner List calls = %d, want 2", inner.listCalls)
+	}
+
+	// Writes invalidate
+	if err := c.Close(ctx, "1"); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	_, _ = c.Ready(ctx)
+	if inner.readyCalls != 2 {
+		t.Errorf("inner Ready calls after Close = %d, want 2", inner.readyCalls)
+	}
+}
+
+// commentingBackend is a countingBackend that supports comments.
+type commentingBackend struct {
+	countingBackend
+	comments []string
+}
+
+func (b *commentingBackend) AddComment(ctx context.Context, id string, body string) error {
+	b.comments = append(b.comments, body)
+	return nil
+}
+
+func (b *commentingBackend) ListComments(ctx context.Context, id string, since time.Time) ([]*Comment, error) {
+	return nil, nil
+}
+
+func (b *commentingBackend) UpsertPlanSection(ctx context.Context, id string, planContent string) error {
+	return nil
+}
+
+func TestCachedBackend_Collaborative(t *testing.T) {
+	ctx := context.Background()
+	inner := &commentingBackend{}
+	var c Backend = NewCachedBackend(inner, time.Hour)
+
+	collab, ok := c.(CollaborativeBackend)
+	if !ok {
+		t.Fatal("CachedBackend should implement CollaborativeBackend")
+	}
+	_, _ = c.Ready(ctx)
+	if err := collab.AddComment(ctx, "1", "hello"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if len(inner.comments) != 1 || inner.comments[0] != "hello" {
+		t.Errorf("inner comments = %v, want [hello]", inner.comments)
+	}
+	_, _ = c.Ready(ctx)
+	if inner.readyCalls != 2 {
+		t.Errorf("inner Ready calls after AddComment = %d, want 2", inner.readyCalls)
+	}
+
+	// Backends without comments report ErrNotSupported through the wrapper
+	plain := NewCachedBackend(&countingBackend{}, time.Hour)
+	if err := plain.AddComment(ctx, "1", "hello"); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("AddComment() on plain backend = %v, want ErrNotSupported", err)
+	}
+	if _, err := plain.ListComments(ctx, "1", time.Time{}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("ListComments() on plain backend = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestCachedBackend_Expires(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingBackend{}
+	c := NewCachedBackend(inner, time.Millisecond)
+
+	_, _ = c.Ready(ctx)
+	time.Sleep(5 * time.Millisecond)
+	_, _ = c.Ready(ctx)
+	if inner.readyCalls != 2 {
+		t.Errorf("inner Ready calls = %d, want 2 after TTL expiry", inner.readyCalls)
+	}
+}
+
+func TestBackendCache(t *testing.T) {
+	created := 0
+	cache := NewBackendCache(func(repoDir string) (Backend, error) {
+		created++
+		return &countingBackend{}, nil
+	}, time.Hour)
+
+	a, _ := cache.New("/repo")
+	b, _ := cache.New("/repo")
+	if a != b || created != 1 {
+		t.Errorf("New() should reuse the backend per repo dir (created %d)", created)
+	}
+
+	_, _ = a.Ready(context.Background())
+	_, _ = b.Ready(context.Background())
+	if got := cache.Stats(); got != (CacheStats{Hits: 1, Misses: 1}) {
+		t.Errorf("Stats() = %+v, want 1 hit, 1 miss", got)
+	}
+}
@@ -0,0 +1,57 @@
+package gh
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CLITokenSource is the token source reported for tokens from the GitHub CLI.
+const CLITokenSource = "gh auth token"
+
+// cliTokenTimeout bounds how long `gh auth token` may take, so a hung
+// keychain prompt doesn't stall backend creation.
+const cliTokenTimeout = 5 * time.Second
+
+// cliTokens caches tokens from the GitHub CLI by host. Only tokens that were
+// found are cached, so logging in with gh later is picked up without a
+// restart.
+var cliTokens = struct {
+	sync.Mutex
+	byHost map[string]string
+}{byHost: make(map[string]string)}
+
+// runAuthToken runs `gh auth token` for host (gh's default host if empty)
+// and returns its output. Replaced in tests.
+var runAuthToken = func(ctx context.Context, host string) (string, error) {
+	args := []string{"auth", "token"}
+	if host != "" {
+		args = append(args, "--hostname", host)
+	}
+	out, err := exec.CommandContext(ctx, "gh", args...).Output()
+	return string(out), err
+}
+
+// cliToken returns the token the GitHub CLI holds for host, or "" if gh
+// isn't installed, isn't logged in, or doesn't answer in time.
+func cliToken(host string) string {
+	cliTokens.Lock()
+	defer cliTokens.Unlock()
+	if token, ok := cliTokens.byHost[host]; ok {
+		return token
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cliTokenTimeout)
+	defer cancel()
+	out, err := runAuthToken(ctx, host)
+	if err != nil {
+		return ""
+	}
+	token := strings.TrimSpace(out)
+	if token != "" {
+		cliTokens.byHost[host] = token
+	}
+	return token
+}
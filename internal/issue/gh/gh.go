@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"regexp"
@@ -16,42 +18,92 @@ import (
 	"time"
 
 	"github.com/tessro/fab/internal/issue"
+	"github.com/tessro/fab/internal/metrics"
 )
 
-const graphqlEndpoint = "https://api.github.com/graphql"
+// DefaultHost is the host of public GitHub.
+const DefaultHost = "github.com"
+
+// HostEnvVar overrides the GitHub host detected from the git remote.
+const HostEnvVar = "GH_HOST"
+
+// DefaultIssueLimit is how many issues List and Ready fetch when no limit
+// is configured.
+const DefaultIssueLimit = 1000
+
+// issuePageSize is the number of issues requested per page, the most the
+// GraphQL API allows.
+const issuePageSize = 100
 
 // Backend implements issue.Backend for GitHub Issues using the GraphQL API.
 type Backend struct {
 	repoDir        string   // Path to a git repository with a GitHub remote
 	nwo            string   // GitHub owner/repo (e.g., "owner/repo")
+	endpoint       string   // GraphQL API URL for the repository's host
 	allowedAuthors []string // GitHub usernames allowed to create issues (empty = owner only)
+	issueLimit     int      // Most issues List and Ready fetch (0 = DefaultIssueLimit)
 	token          string   // GitHub personal access token
 	client         *http.Client
 }
 
+// ResolveToken returns the GitHub token New would use and where it came
+// from: configAPIKey ("config"), then the GITHUB_TOKEN and GH_TOKEN
+// environment variables, then the token the GitHub CLI holds for host
+// (CLITokenSource; gh's default host if empty). Both are empty if no token
+// is found.
+func ResolveToken(configAPIKey, host string) (token, source string) {
+	if configAPIKey != "" {
+		return configAPIKey, "config"
+	}
+	for _, env := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if token := os.Getenv(env); token != "" {
+			return token, env
+		}
+	}
+	if token := cliToken(host); token != "" {
+		return token, CLITokenSource
+	}
+	return "", ""
+}
+
+// ResolveHost returns the GitHub host New would use for the repository at
+// repoDir: host if set, then GH_HOST, then the host of the remote URL.
+// Returns "" if none of these is available.
+func ResolveHost(repoDir, host string) string {
+	if host == "" {
+		host = os.Getenv(HostEnvVar)
+	}
+	if host == "" {
+		host, _, _ = detectNWO(repoDir)
+	}
+	return host
+}
+
 // New creates a new GitHub issues backend.
 // repoDir should be a git repository with a GitHub remote.
+// host selects a GitHub Enterprise server; if empty, falls back to GH_HOST and
+// then to the host of the remote URL.
 // allowedAuthors is a list of GitHub usernames allowed to create issues.
 // If empty, defaults to the repository owner inferred from the remote URL.
+// issueLimit caps how many issues List and Ready fetch; 0 uses DefaultIssueLimit.
 // configAPIKey is an optional API key from the global config; if empty, falls back to
-// GITHUB_TOKEN or GH_TOKEN environment variables.
-func New(repoDir string, allowedAuthors []string, configAPIKey string) (*Backend, error) {
-	// Extract owner/repo from the git remote
-	nwo, err := detectNWO(repoDir)
+// GITHUB_TOKEN or GH_TOKEN environment variables, then to `gh auth token`.
+func New(repoDir string, host string, allowedAuthors []string, issueLimit int, configAPIKey string) (*Backend, error) {
+	// Extract host and owner/repo from the git remote
+	remoteHost, nwo, err := detectNWO(repoDir)
 	if err != nil {
 		return nil, fmt.Errorf("detect github repo: %w", err)
 	}
-
-	// Get GitHub token from config or environment
-	token := configAPIKey
-	if token == "" {
-		token = os.Getenv("GITHUB_TOKEN")
+	if host == "" {
+		host = os.Getenv(HostEnvVar)
 	}
-	if token == "" {
-		token = os.Getenv("GH_TOKEN")
+	if host == "" {
+		host = remoteHost
 	}
+
+	token, _ := ResolveToken(configAPIKey, host)
 	if token == "" {
-		return nil, fmt.Errorf("GITHUB_TOKEN or GH_TOKEN not set in config or environment")
+		return nil, fmt.Errorf("GITHUB_TOKEN or GH_TOKEN not set in config or environment, and gh CLI not logged in")
 	}
 
 	// Default to repo owner if no allowed authors specified
@@ -65,12 +117,25 @@ func New(repoDir string, allowedAuthors []string, configAPIKey string) (*Backend
 	return &Backend{
 		repoDir:        repoDir,
 		nwo:            nwo,
+		endpoint:       graphqlEndpoint(host),
 		allowedAuthors: allowedAuthors,
+		issueLimit:     issueLimit,
 		token:          token,
 		client:         &http.Client{Timeout: 30 * time.Second},
 	}, nil
 }
 
+// graphqlEndpoint returns the GraphQL API URL for a GitHub host.
+// Public GitHub serves its API from a separate host; GitHub Enterprise
+// Server serves it under /api on the instance itself.
+func graphqlEndpoint(host string) string {
+	host = strings.ToLower(host)
+	if host == "" || host == DefaultHost || host == "api."+DefaultHost {
+		return "https://api.github.com/graphql"
+	}
+	return "https://" + host + "/api/graphql"
+}
+
 // ownerFromNWO extracts the owner from an owner/repo string.
 func ownerFromNWO(nwo string) string {
 	parts := strings.Split(nwo, "/")
@@ -101,6 +166,9 @@ type ghIssue struct {
 	Author    ghAuthor  `json:"author"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
+	Parent    *struct {
+		Number int `json:"number"`
+	} `json:"parent"` // Parent issue, if this is a sub-issue (only fetched by Get)
 }
 
 type ghLabels struct {
@@ -244,16 +312,17 @@ func (b *Backend) Get(ctx context.Context, id string) (*issue.Issue, error) {
 					author { login }
 					labels(first: 20) { nodes { id name } }
 					blockedBy(first: 20) { nodes { number state } }
+					parent { number }
 				}
 			}
 		}
 	`
 
-	data, err := b.graphqlRequest(ctx, query, map[string]any{
+	data, err := b.graphqlRequestWithFeatures(ctx, query, map[string]any{
 		"owner":  owner,
 		"repo":   repo,
 		"number": num,
-	})
+	}, []string{"sub_issues"})
 	if err != nil {
 		return nil, fmt.Errorf("get issue %s: %w", id, err)
 	}
@@ -311,57 +380,13 @@ func (b *Backend) List(ctx context.Context, filter issue.ListFilter) ([]*issue.I
 	}
 
 	// Note: Label filtering is done client-side after fetching issues
-	query := `
-		query ListIssues($owner: String!, $repo: String!, $states: [IssueState!], $first: Int!) {
-			repository(owner: $owner, name: $repo) {
-				issues(states: $states, first: $first, orderBy: {field: UPDATED_AT, direction: DESC}) {
-					nodes {
-						id
-						number
-						title
-						body
-						state
-						createdAt
-						updatedAt
-						author { login }
-						labels(first: 20) { nodes { id name } }
-						blockedBy(first: 20) { nodes { number state } }
-					}
-				}
-			}
-		}
-	`
-
-	variables := map[string]any{
-		"owner": owner,
-		"repo":  repo,
-		"first": 100,
-	}
-	if len(states) > 0 {
-		variables["states"] = states
-	}
-
-	data, err := b.graphqlRequest(ctx, query, variables)
+	nodes, err := b.listIssues(ctx, owner, repo, states)
 	if err != nil {
 		return nil, fmt.Errorf("list issues: %w", err)
 	}
 
-	var result struct {
-		Repository struct {
-			Issues struct {
-				Nodes []struct {
-					ghIssue
-					BlockedBy ghBlockedBy `json:"blockedBy"`
-				} `json:"nodes"`
-			} `json:"issues"`
-		} `json:"repository"`
-	}
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("parse issues: %w", err)
-	}
-
-	issues := make([]*issue.Issue, 0, len(result.Repository.Issues.Nodes))
-	for _, gh := range result.Repository.Issues.Nodes {
+	issues := make([]*issue.Issue, 0, len(nodes))
+	for _, gh := range nodes {
 		iss := b.toIssue(&gh.ghIssue)
 
 		// Set dependencies from blockedBy issues
@@ -396,6 +421,90 @@ func (b *Backend) List(ctx context.Context, filter issue.ListFilter) ([]*issue.I
 	return issues, nil
 }
 
+// ghIssueNode is an issue from a list query, with the issues blocking it.
+type ghIssueNode struct {
+	ghIssue
+	BlockedBy ghBlockedBy `json:"blockedBy"`
+}
+
+// listIssues fetches issues in the given states (all states if empty),
+// most recently updated first. It follows the connection's cursor page by
+// page until the issues run out or the backend's issue limit is reached.
+func (b *Backend) listIssues(ctx context.Context, owner, repo string, states []string) ([]ghIssueNode, error) {
+	query := `
+		query ListIssues($owner: String!, $repo: String!, $states: [IssueState!], $first: Int!, $after: String) {
+			repository(owner: $owner, name: $repo) {
+				issues(states: $states, first: $first, after: $after, orderBy: {field: UPDATED_AT, direction: DESC}) {
+					nodes {
+						id
+						number
+						title
+						body
+						state
+						createdAt
+						updatedAt
+						author { login }
+						labels(first: 20) { nodes { id name } }
+						blockedBy(first: 20) { nodes { number state } }
+					}
+					pageInfo { hasNextPage endCursor }
+				}
+			}
+		}
+	`
+
+	limit := b.issueLimit
+	if limit <= 0 {
+		limit = DefaultIssueLimit
+	}
+
+	var nodes []ghIssueNode
+	var cursor string
+	for len(nodes) < limit {
+		variables := map[string]any{
+			"owner": owner,
+			"repo":  repo,
+			"first": min(issuePageSize, limit-len(nodes)),
+		}
+		if len(states) > 0 {
+			variables["states"] = states
+		}
+		if cursor != "" {
+			variables["after"] = cursor
+		}
+
+		data, err := b.graphqlRequest(ctx, query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Repository struct {
+				Issues struct {
+					Nodes    []ghIssueNode `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"issues"`
+			} `json:"repository"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("parse issues: %w", err)
+		}
+
+		page := result.Repository.Issues
+		nodes = append(nodes, page.Nodes...)
+		if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == "" {
+			return nodes, nil
+		}
+		cursor = page.PageInfo.EndCursor
+	}
+
+	slog.Warn("github issue list truncated", "repo", owner+"/"+repo, "limit", limit)
+	return nodes, nil
+}
+
 // Update modifies an existing issue.
 func (b *Backend) Update(ctx context.Context, id string, params issue.UpdateParams) (*issue.Issue, error) {
 	// Get current issue to find its node ID
@@ -579,52 +688,14 @@ func (b *Backend) Ready(ctx context.Context) ([]*issue.Issue, error) {
 	}
 
 	// Fetch open issues with blockedBy and author info
-	query := `
-		query ListIssuesForReady($owner: String!, $repo: String!) {
-			repository(owner: $owner, name: $repo) {
-				issues(states: [OPEN], first: 100, orderBy: {field: UPDATED_AT, direction: DESC}) {
-					nodes {
-						id
-						number
-						title
-						body
-						state
-						createdAt
-						updatedAt
-						author { login }
-						labels(first: 20) { nodes { id name } }
-						blockedBy(first: 20) { nodes { number state } }
-					}
-				}
-			}
-		}
-	`
-
-	data, err := b.graphqlRequest(ctx, query, map[string]any{
-		"owner": owner,
-		"repo":  repo,
-	})
+	nodes, err := b.listIssues(ctx, owner, repo, []string{"OPEN"})
 	if err != nil {
 		return nil, fmt.Errorf("list issues for ready: %w", err)
 	}
 
-	var result struct {
-		Repository struct {
-			Issues struct {
-				Nodes []struct {
-					ghIssue
-					BlockedBy ghBlockedBy `json:"blockedBy"`
-				} `json:"nodes"`
-			} `json:"issues"`
-		} `json:"repository"`
-	}
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("parse issues: %w", err)
-	}
-
 	// Filter: not blocked + authored by allowed user + no open blocking issues
 	ready := make([]*issue.Issue, 0)
-	for _, gh := range result.Repository.Issues.Nodes {
+	for _, gh := range nodes {
 		iss := b.toIssue(&gh.ghIssue)
 
 		// Skip blocked issues (via label)
@@ -672,6 +743,10 @@ func (b *Backend) toIssue(gh *ghIssue) *issue.Issue {
 	// Extract description
 	iss.Description = gh.Body
 
+	if gh.Parent != nil {
+		iss.Parent = strconv.Itoa(gh.Parent.Number)
+	}
+
 	// Determine status
 	if gh.State == "CLOSED" {
 		iss.Status = issue.StatusClosed
@@ -974,7 +1049,7 @@ func (b *Backend) graphqlRequestWithFeatures(ctx context.Context, query string,
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", graphqlEndpoint, bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", b.endpoint, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -985,6 +1060,7 @@ func (b *Backend) graphqlRequestWithFeatures(ctx context.Context, query string,
 		req.Header.Set("GraphQL-Features", strings.Join(features, ","))
 	}
 
+	metrics.Inc(metrics.GraphQLRequests, metrics.Label{Name: "backend", Value: "github"})
 	resp, err := b.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("execute request: %w", err)
@@ -1018,37 +1094,53 @@ func (b *Backend) graphqlRequestWithFeatures(ctx context.Context, query string,
 	return result.Data, nil
 }
 
-// detectNWO extracts the owner/repo from a git repository.
-func detectNWO(repoDir string) (string, error) {
+// detectNWO extracts the host and owner/repo from a git repository.
+func detectNWO(repoDir string) (host, nwo string, err error) {
 	cmd := exec.Command("git", "remote", "get-url", "origin")
 	cmd.Dir = repoDir
 
 	out, err := cmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("get remote URL: %w", err)
+		return "", "", fmt.Errorf("get remote URL: %w", err)
 	}
 
 	return parseNWO(strings.TrimSpace(string(out)))
 }
 
-// parseNWO extracts owner/repo from a GitHub URL.
-// Supports SSH (git@github.com:owner/repo.git) and HTTPS (https://github.com/owner/repo.git).
-func parseNWO(url string) (string, error) {
-	// SSH format: git@github.com:owner/repo.git
-	if strings.HasPrefix(url, "git@github.com:") {
-		nwo := strings.TrimPrefix(url, "git@github.com:")
-		nwo = strings.TrimSuffix(nwo, ".git")
-		return nwo, nil
+// scpLikeRemote matches SSH remotes in scp syntax: [user@]host:owner/repo.git
+var scpLikeRemote = regexp.MustCompile(`^(?:[^@/:]+@)?([^/:]+):([^/]+/[^/]+?)(?:\.git)?/?$`)
+
+// parseNWO extracts the host and owner/repo from a git remote URL.
+// Any host is accepted so GitHub Enterprise remotes work. Supports SSH
+// (git@github.com:owner/repo.git, ssh://git@github.com/owner/repo.git) and
+// HTTPS (https://github.com/owner/repo.git).
+func parseNWO(remote string) (host, nwo string, err error) {
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil || u.Host == "" {
+			return "", "", fmt.Errorf("not a GitHub URL: %s", remote)
+		}
+		switch u.Scheme {
+		case "https", "http":
+			// A port here belongs to the web server, which also serves the API
+			host = u.Host
+		case "ssh", "git+ssh":
+			// A port here is the SSH port, not the API's
+			host = u.Hostname()
+		default:
+			return "", "", fmt.Errorf("not a GitHub URL: %s", remote)
+		}
+		nwo = strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	} else if m := scpLikeRemote.FindStringSubmatch(remote); m != nil {
+		host, nwo = m[1], m[2]
+	} else {
+		return "", "", fmt.Errorf("not a GitHub URL: %s", remote)
 	}
 
-	// HTTPS format: https://github.com/owner/repo.git
-	re := regexp.MustCompile(`https://github\.com/([^/]+/[^/]+?)(?:\.git)?$`)
-	matches := re.FindStringSubmatch(url)
-	if len(matches) == 2 {
-		return matches[1], nil
+	if parts := strings.Split(nwo, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("not a GitHub repository URL: %s", remote)
 	}
-
-	return "", fmt.Errorf("not a GitHub URL: %s", url)
+	return strings.ToLower(host), nwo, nil
 }
 
 // parseIssueNumberFromURL extracts the issue number from a GitHub issue URL.
@@ -2,7 +2,11 @@ package gh
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -17,29 +21,62 @@ func TestBackendImplementsInterface(t *testing.T) {
 
 func TestParseNWO(t *testing.T) {
 	tests := []struct {
-		name    string
-		url     string
-		want    string
-		wantErr bool
+		name     string
+		url      string
+		wantHost string
+		want     string
+		wantErr  bool
 	}{
 		{
-			name: "ssh format",
-			url:  "git@github.com:owner/repo.git",
-			want: "owner/repo",
+			name:     "ssh format",
+			url:      "git@github.com:owner/repo.git",
+			wantHost: "github.com",
+			want:     "owner/repo",
+		},
+		{
+			name:     "https format with .git",
+			url:      "https://github.com/owner/repo.git",
+			wantHost: "github.com",
+			want:     "owner/repo",
+		},
+		{
+			name:     "https format without .git",
+			url:      "https://github.com/owner/repo",
+			wantHost: "github.com",
+			want:     "owner/repo",
 		},
 		{
-			name: "https format with .git",
-			url:  "https://github.com/owner/repo.git",
-			want: "owner/repo",
+			name:     "enterprise ssh format",
+			url:      "git@github.example.com:team/service.git",
+			wantHost: "github.example.com",
+			want:     "team/service",
 		},
 		{
-			name: "https format without .git",
-			url:  "https://github.com/owner/repo",
-			want: "owner/repo",
+			name:     "enterprise ssh url with port",
+			url:      "ssh://git@GitHub.Example.com:2222/team/service.git",
+			wantHost: "github.example.com",
+			want:     "team/service",
 		},
 		{
-			name:    "non-github url",
-			url:     "git@gitlab.com:owner/repo.git",
+			name:     "enterprise https format",
+			url:      "https://github.example.com/team/service.git",
+			wantHost: "github.example.com",
+			want:     "team/service",
+		},
+		{
+			name:     "enterprise https with port",
+			url:      "https://ghe.internal:8443/team/service",
+			wantHost: "ghe.internal:8443",
+			want:     "team/service",
+		},
+		{
+			name:    "nested path",
+			url:     "https://github.example.com/a/b/c.git",
+			wantErr: true,
+		},
+		{
+			name:    "missing repo",
+			url:     "git@github.example.com:team",
 			wantErr: true,
 		},
 		{
@@ -51,18 +88,40 @@ func TestParseNWO(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseNWO(tt.url)
+			host, got, err := parseNWO(tt.url)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseNWO() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if got != tt.want {
-				t.Errorf("parseNWO() = %v, want %v", got, tt.want)
+				t.Errorf("parseNWO() nwo = %v, want %v", got, tt.want)
+			}
+			if host != tt.wantHost {
+				t.Errorf("parseNWO() host = %v, want %v", host, tt.wantHost)
 			}
 		})
 	}
 }
 
+func TestGraphQLEndpoint(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"github.com", "https://api.github.com/graphql"},
+		{"GitHub.com", "https://api.github.com/graphql"},
+		{"", "https://api.github.com/graphql"},
+		{"github.example.com", "https://github.example.com/api/graphql"},
+		{"ghe.internal:8443", "https://ghe.internal:8443/api/graphql"},
+	}
+
+	for _, tt := range tests {
+		if got := graphqlEndpoint(tt.host); got != tt.want {
+			t.Errorf("graphqlEndpoint(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
 func TestParseIssueNumberFromURL(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -227,3 +286,110 @@ func TestBackend_getIssueWithBody_InvalidNWO(t *testing.T) {
 		t.Errorf("Expected 'invalid nwo' error, got: %v", err)
 	}
 }
+
+func TestBackend_Ready_Paginates(t *testing.T) {
+	var afters []any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		afters = append(afters, req.Variables["after"])
+
+		// Two pages: issues 1-2, then issue 3 (blocked) and 4
+		page := `{"data":{"repository":{"issues":{
+			"nodes":[{"number":1,"state":"OPEN","author":{"login":"owner"}},{"number":2,"state":"OPEN","author":{"login":"owner"}}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}}`
+		if req.Variables["after"] == "c1" {
+			page = `{"data":{"repository":{"issues":{
+				"nodes":[{"number":3,"state":"OPEN","author":{"login":"owner"},"blockedBy":{"nodes":[{"number":1,"state":"OPEN"}]}},{"number":4,"state":"OPEN","author":{"login":"owner"}}],
+				"pageInfo":{"hasNextPage":false,"endCursor":"c2"}}}}}`
+		}
+		_, _ = w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	backend := &Backend{
+		nwo:            "owner/repo",
+		endpoint:       srv.URL,
+		allowedAuthors: []string{"owner"},
+		token:          "test-token",
+		client:         srv.Client(),
+	}
+
+	ready, err := backend.Ready(context.Background())
+	if err != nil {
+		t.Fatalf("Ready() error = %v", err)
+	}
+	var ids []string
+	for _, iss := range ready {
+		ids = append(ids, iss.ID)
+	}
+	if !slices.Equal(ids, []string{"1", "2", "4"}) {
+		t.Errorf("Ready() = %v, want [1 2 4] from both pages", ids)
+	}
+	if len(afters) != 2 || afters[0] != nil || afters[1] != "c1" {
+		t.Errorf("after cursors = %v, want [<nil> c1]", afters)
+	}
+
+	// The limit stops paging early and trims the last page request
+	afters = nil
+	backend.issueLimit = 2
+	issues, err := backend.List(context.Background(), issue.ListFilter{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(issues) != 2 || len(afters) != 1 {
+		t.Errorf("List() with limit 2 = %d issues in %d requests, want 2 in 1", len(issues), len(afters))
+	}
+}
+
+func TestResolveToken(t *testing.T) {
+	var calls []string
+	orig := runAuthToken
+	runAuthToken = func(_ context.Context, host string) (string, error) {
+		calls = append(calls, host)
+		if host == "ghe.example.com" {
+			return "", errors.New("not logged in")
+		}
+		return "gho_cli\n", nil
+	}
+	t.Cleanup(func() {
+		runAuthToken = orig
+		cliTokens.byHost = make(map[string]string)
+	})
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "env-token")
+
+	tests := []struct {
+		configKey, host    string
+		wantToken, wantSrc string
+	}{
+		{"config-token", "", "config-token", "config"},
+		{"", "", "env-token", "GH_TOKEN"},
+	}
+	for _, tt := range tests {
+		if token, src := ResolveToken(tt.configKey, tt.host); token != tt.wantToken || src != tt.wantSrc {
+			t.Errorf("ResolveToken(%q, %q) = %q, %q; want %q, %q", tt.configKey, tt.host, token, src, tt.wantToken, tt.wantSrc)
+		}
+	}
+	if len(calls) != 0 {
+		t.Fatalf("gh auth token ran %d times with a token configured, want 0", len(calls))
+	}
+
+	// Without config or environment tokens, fall back to the GitHub CLI
+	t.Setenv("GH_TOKEN", "")
+	for range 2 {
+		if token, src := ResolveToken("", "github.com"); token != "gho_cli" || src != CLITokenSource {
+			t.Errorf("ResolveToken() = %q, %q; want the gh CLI token", token, src)
+		}
+	}
+	if token, src := ResolveToken("", "ghe.example.com"); token != "" || src != "" {
+		t.Errorf("ResolveToken(ghe) = %q, %q; want none when gh fails", token, src)
+	}
+	if !slices.Equal(calls, []string{"github.com", "ghe.example.com"}) {
+		t.Errorf("gh auth token calls = %v, want one per host with the token cached", calls)
+	}
+}
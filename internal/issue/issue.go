@@ -28,6 +28,7 @@ type Issue struct {
 	Priority     int      // 0 = low, 1 = medium, 2 = high
 	Type         string   // task, bug, feature, chore
 	Dependencies []string // IDs of blocking issues
+	Parent       string   // ID of the parent (epic) issue, if known
 	Labels       []string
 	Links        []string
 	Created      time.Time
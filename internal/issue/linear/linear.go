@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/tessro/fab/internal/issue"
+	"github.com/tessro/fab/internal/metrics"
 )
 
 const (
@@ -28,6 +29,19 @@ type Backend struct {
 	client         *http.Client
 }
 
+// ResolveAPIKey returns the Linear API key New would use and where it came
+// from: configAPIKey ("config"), then the LINEAR_API_KEY environment
+// variable. Both are empty if no key is set.
+func ResolveAPIKey(configAPIKey string) (apiKey, source string) {
+	if configAPIKey != "" {
+		return configAPIKey, "config"
+	}
+	if apiKey := os.Getenv("LINEAR_API_KEY"); apiKey != "" {
+		return apiKey, "LINEAR_API_KEY"
+	}
+	return "", ""
+}
+
 // New creates a new Linear issues backend.
 // repoDir is used for context but Linear doesn't require it.
 // configAPIKey is the API key from the global config (can be empty).
@@ -35,10 +49,7 @@ type Backend struct {
 // teamID is required (linear-team setting) for issue creation.
 // projectID is optional (linear-project setting) for scoping issues to a project.
 func New(repoDir string, teamID string, projectID string, allowedAuthors []string, configAPIKey string) (*Backend, error) {
-	apiKey := configAPIKey
-	if apiKey == "" {
-		apiKey = os.Getenv("LINEAR_API_KEY")
-	}
+	apiKey, _ := ResolveAPIKey(configAPIKey)
 	if apiKey == "" {
 		return nil, fmt.Errorf("LINEAR_API_KEY not set in config or environment")
 	}
@@ -83,6 +94,7 @@ func (b *Backend) graphqlRequest(ctx context.Context, query string, variables ma
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", b.apiKey)
 
+	metrics.Inc(metrics.GraphQLRequests, metrics.Label{Name: "backend", Value: "linear"})
 	resp, err := b.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("execute request: %w", err)
@@ -576,6 +588,7 @@ func (b *Backend) toIssue(li *linearIssue) *issue.Issue {
 	// Set parent as dependency
 	if li.Parent != nil {
 		iss.Dependencies = []string{li.Parent.Identifier}
+		iss.Parent = li.Parent.Identifier
 	}
 
 	return iss
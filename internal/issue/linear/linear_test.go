@@ -188,6 +188,9 @@ func TestToIssue_WithParent(t *testing.T) {
 	if len(iss.Dependencies) != 1 || iss.Dependencies[0] != "FAB-100" {
 		t.Errorf("Dependencies = %v, want [FAB-100]", iss.Dependencies)
 	}
+	if iss.Parent != "FAB-100" {
+		t.Errorf("Parent = %q, want FAB-100", iss.Parent)
+	}
 }
 
 // TestCreateSubIssue_ParentInResponse tests that the CreateSubIssue response
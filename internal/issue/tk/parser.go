@@ -18,6 +18,7 @@ type frontmatter struct {
 	ID       string    `yaml:"id"`
 	Status   string    `yaml:"status"`
 	Deps     []string  `yaml:"deps"`
+	Parent   string    `yaml:"parent,omitempty"`
 	Links    []string  `yaml:"links"`
 	Created  time.Time `yaml:"created"`
 	Type     string    `yaml:"type"`
@@ -47,6 +48,7 @@ func parseIssue(data []byte) (*issue.Issue, error) {
 		Priority:     meta.Priority,
 		Type:         meta.Type,
 		Dependencies: meta.Deps,
+		Parent:       meta.Parent,
 		Labels:       meta.Labels,
 		Links:        meta.Links,
 		Created:      meta.Created,
@@ -124,6 +126,7 @@ func formatIssue(iss *issue.Issue) ([]byte, error) {
 		ID:       iss.ID,
 		Status:   string(iss.Status),
 		Deps:     iss.Dependencies,
+		Parent:   iss.Parent,
 		Links:    iss.Links,
 		Created:  iss.Created,
 		Type:     iss.Type,
@@ -256,3 +259,51 @@ func buildCommentsSection(beforeComments, existingComments, newComment, afterSec
 
 	return sb.String()
 }
+
+// commentTimeLayout is the timestamp format of comments written by AddComment.
+const commentTimeLayout = "2006-01-02 15:04"
+
+// commentStampRegex matches the "**<timestamp>**: " prefix that starts a comment.
+var commentStampRegex = regexp.MustCompile(`(?m)^\*\*(\d{4}-\d{2}-\d{2} \d{2}:\d{2})\*\*: `)
+
+// formatComment prefixes a comment body with its timestamp.
+func formatComment(body string, at time.Time) string {
+	return fmt.Sprintf("**%s**: %s", at.Format(commentTimeLayout), strings.TrimSpace(body))
+}
+
+// parseComments extracts the timestamped comments from the ## Comments
+// section of a Markdown body, oldest first. A comment runs until the next
+// timestamp or the end of the section. Text without a timestamp (e.g.,
+// written by hand) is skipped. Comment IDs are their 1-based positions,
+// which stay stable because comments are only ever appended.
+func parseComments(issueID, body string) []*issue.Comment {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	commentsLoc := commentsHeadingRegex.FindStringIndex(body)
+	if commentsLoc == nil {
+		return nil
+	}
+	section := body[commentsLoc[1]:]
+	if next := sectionHeadingRegex.FindStringIndex(section); next != nil {
+		section = section[:next[0]]
+	}
+
+	stamps := commentStampRegex.FindAllStringSubmatchIndex(section, -1)
+	comments := make([]*issue.Comment, 0, len(stamps))
+	for i, loc := range stamps {
+		end := len(section)
+		if i+1 < len(stamps) {
+			end = stamps[i+1][0]
+		}
+		created, err := time.ParseInLocation(commentTimeLayout, section[loc[2]:loc[3]], time.Local)
+		if err != nil {
+			continue
+		}
+		comments = append(comments, &issue.Comment{
+			ID:        fmt.Sprint(i + 1),
+			IssueID:   issueID,
+			Body:      strings.TrimSpace(section[loc[1]:end]),
+			CreatedAt: created,
+		})
+	}
+	return comments
+}
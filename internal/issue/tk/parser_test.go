@@ -904,3 +904,39 @@ func TestUpsertComment(t *testing.T) {
 		})
 	}
 }
+
+func TestParseComments(t *testing.T) {
+	body := "Description.\n\n## Comments\n\nWritten by hand\n\n" +
+		"**2024-01-15 10:00**: First\n\n" +
+		"**2024-01-15 11:30**: Second\nspans lines\n\n" +
+		"## Notes\n\n**2024-01-15 12:00**: Not a comment"
+
+	comments := parseComments("test-1", body)
+	if len(comments) != 2 {
+		t.Fatalf("parseComments() returned %d comments, want 2: %+v", len(comments), comments)
+	}
+	want := []struct {
+		id, body string
+		created  time.Time
+	}{
+		{"1", "First", time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local)},
+		{"2", "Second\nspans lines", time.Date(2024, 1, 15, 11, 30, 0, 0, time.Local)},
+	}
+	for i, w := range want {
+		c := comments[i]
+		if c.ID != w.id || c.IssueID != "test-1" || c.Body != w.body || !c.CreatedAt.Equal(w.created) {
+			t.Errorf("comments[%d] = %+v, want ID %s, body %q, created %v", i, c, w.id, w.body, w.created)
+		}
+	}
+
+	if got := parseComments("test-1", "No comments here."); got != nil {
+		t.Errorf("parseComments() without a Comments section = %+v, want nil", got)
+	}
+
+	// Comments written by AddComment parse back
+	at := time.Date(2024, 3, 1, 9, 5, 0, 0, time.Local)
+	got := parseComments("test-1", upsertComment("", formatComment("  Round trip  ", at)))
+	if len(got) != 1 || got[0].Body != "Round trip" || !got[0].CreatedAt.Equal(at) {
+		t.Errorf("parseComments(formatComment()) = %+v, want the original comment", got)
+	}
+}
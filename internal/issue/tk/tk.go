@@ -44,6 +44,11 @@ func (b *Backend) Name() string {
 
 // Create creates a new issue.
 func (b *Backend) Create(ctx context.Context, params issue.CreateParams) (*issue.Issue, error) {
+	return b.create(params, "")
+}
+
+// create writes a new issue, recording parentID as its parent if set.
+func (b *Backend) create(params issue.CreateParams, parentID string) (*issue.Issue, error) {
 	id := b.generateID()
 
 	iss := &issue.Issue{
@@ -54,6 +59,7 @@ func (b *Backend) Create(ctx context.Context, params issue.CreateParams) (*issue
 		Priority:     params.Priority,
 		Type:         params.Type,
 		Dependencies: params.Dependencies,
+		Parent:       parentID,
 		Labels:       params.Labels,
 		Created:      time.Now(),
 	}
@@ -72,7 +78,7 @@ func (b *Backend) Create(ctx context.Context, params issue.CreateParams) (*issue
 }
 
 // CreateSubIssue creates a child issue under a parent issue.
-// The parent is stored in the child's Dependencies list.
+// The parent is stored in the child's Dependencies list and in its parent field.
 func (b *Backend) CreateSubIssue(ctx context.Context, parentID string, params issue.CreateParams) (*issue.Issue, error) {
 	// Verify parent exists
 	_, err := b.Get(ctx, parentID)
@@ -83,7 +89,7 @@ func (b *Backend) CreateSubIssue(ctx context.Context, parentID string, params is
 	// Add parent to dependencies
 	params.Dependencies = append([]string{parentID}, params.Dependencies...)
 
-	return b.Create(ctx, params)
+	return b.create(params, parentID)
 }
 
 // Get retrieves an issue by ID.
@@ -320,21 +326,31 @@ func (b *Backend) AddComment(ctx context.Context, id string, body string) error
 		return err
 	}
 
-	// Format the comment with timestamp
-	timestamp := time.Now().Format("2006-01-02 15:04")
-	comment := fmt.Sprintf("**%s**: %s", timestamp, strings.TrimSpace(body))
-
 	// Update the description with the new comment
-	iss.Description = upsertComment(iss.Description, comment)
+	iss.Description = upsertComment(iss.Description, formatComment(body, time.Now()))
 	iss.Updated = time.Now()
 
 	return b.writeIssue(iss)
 }
 
-// ListComments is not supported for the tk backend.
-// Comments are stored in the issue body and can be accessed via Get().
-func (b *Backend) ListComments(_ context.Context, _ string, _ time.Time) ([]*issue.Comment, error) {
-	return nil, issue.ErrNotSupported
+// ListComments returns the comments in an issue's ## Comments section,
+// oldest first. Timestamps only have minute precision, so comments from the
+// minute containing since are included; callers deduplicate by comment ID.
+// tk doesn't record comment authors, so Author is empty.
+func (b *Backend) ListComments(ctx context.Context, id string, since time.Time) ([]*issue.Comment, error) {
+	iss, err := b.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := since.Truncate(time.Minute)
+	var comments []*issue.Comment
+	for _, c := range parseComments(id, iss.Description) {
+		if !c.CreatedAt.Before(cutoff) {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
 }
 
 // UpsertPlanSection updates or creates a ## Plan section in the issue body.
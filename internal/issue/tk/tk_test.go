@@ -2,10 +2,13 @@ package tk
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/tessro/fab/internal/issue"
 )
@@ -320,6 +323,15 @@ func TestBackend_CreateSubIssue(t *testing.T) {
 	if len(child.Dependencies) == 0 || child.Dependencies[0] != parent.ID {
 		t.Errorf("Child should have parent %s as dependency, got %v", parent.ID, child.Dependencies)
 	}
+
+	// Verify the parent survives a round trip through the ticket file
+	got, err := backend.Get(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Parent != parent.ID {
+		t.Errorf("Parent = %q, want %q", got.Parent, parent.ID)
+	}
 }
 
 func TestBackend_CreateSubIssue_ParentNotFound(t *testing.T) {
@@ -342,3 +354,131 @@ func TestBackend_CreateSubIssue_ParentNotFound(t *testing.T) {
 		t.Error("CreateSubIssue() should error for non-existent parent")
 	}
 }
+
+func TestBackend_ListComments(t *testing.T) {
+	tmpDir := t.TempDir()
+	ticketsDir := filepath.Join(tmpDir, ".tickets")
+	if err := os.MkdirAll(ticketsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := &Backend{
+		repoDir:    tmpDir,
+		ticketsDir: ticketsDir,
+		prefix:     "test-",
+	}
+
+	ctx := context.Background()
+	iss, err := backend.Create(ctx, issue.CreateParams{Title: "Test Issue", Description: "The description."})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	before := time.Now()
+	if err := backend.AddComment(ctx, iss.ID, "First comment"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if err := backend.UpsertPlanSection(ctx, iss.ID, "- Step 1"); err != nil {
+		t.Fatalf("UpsertPlanSection() error = %v", err)
+	}
+	if err := backend.AddComment(ctx, iss.ID, "Second comment\n\nwith two paragraphs"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+
+	comments, err := backend.ListComments(ctx, iss.ID, before)
+	if err != nil {
+		t.Fatalf("ListComments() error = %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("ListComments() returned %d comments, want 2", len(comments))
+	}
+	wantBodies := []string{"First comment", "Second comment\n\nwith two paragraphs"}
+	for i, c := range comments {
+		if c.ID != fmt.Sprint(i+1) || c.IssueID != iss.ID || c.Body != wantBodies[i] {
+			t.Errorf("comments[%d] = %+v, want ID %d and body %q", i, c, i+1, wantBodies[i])
+		}
+		if c.CreatedAt.Before(before.Truncate(time.Minute)) || c.CreatedAt.After(time.Now()) {
+			t.Errorf("comments[%d].CreatedAt = %v, want around %v", i, c.CreatedAt, before)
+		}
+	}
+
+	// Comments from before since are left out
+	comments, err = backend.ListComments(ctx, iss.ID, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListComments() error = %v", err)
+	}
+	if len(comments) != 0 {
+		t.Errorf("ListComments() with a future since = %+v, want none", comments)
+	}
+
+	if _, err := backend.ListComments(ctx, "nonexistent", time.Time{}); err == nil {
+		t.Error("ListComments() should return error for non-existent issue")
+	}
+}
+
+func TestBackend_CommitPersistsCommentsAndPlan(t *testing.T) {
+	remote := t.TempDir()
+	runGit(t, remote, "init", "--bare", "-b", "main")
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test")
+	runGit(t, repo, "remote", "add", "origin", remote)
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "initial")
+	runGit(t, repo, "push", "origin", "main")
+
+	backend, err := New(repo)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx := context.Background()
+	iss, err := backend.Create(ctx, issue.CreateParams{Title: "Test Issue"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := backend.UpsertPlanSection(ctx, iss.ID, "- Step 1"); err != nil {
+		t.Fatalf("UpsertPlanSection() error = %v", err)
+	}
+	if err := backend.AddComment(ctx, iss.ID, "Looks good"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if err := backend.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	// A fresh clone of the remote sees the plan and the comment
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, "", "clone", remote, clone)
+	cloned, err := New(clone)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	got, err := cloned.Get(ctx, iss.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !strings.Contains(got.Description, "## Plan\n\n- Step 1") {
+		t.Errorf("Description = %q, want the plan section", got.Description)
+	}
+	comments, err := cloned.ListComments(ctx, iss.ID, time.Time{})
+	if err != nil {
+		t.Fatalf("ListComments() error = %v", err)
+	}
+	if len(comments) != 1 || comments[0].Body != "Looks good" {
+		t.Errorf("ListComments() = %+v, want the pushed comment", comments)
+	}
+}
+
+// runGit runs a git command in dir, failing the test on error.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
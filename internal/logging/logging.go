@@ -29,30 +29,55 @@ func DefaultLogPath() string {
 	return filepath.Join(home, ".fab", "fab.log")
 }
 
+// level is the level of the logger installed by Setup and SetupMulti.
+// It is shared by the handler, so SetLevel takes effect without a restart.
+var level slog.LevelVar
+
 // ParseLevel converts a log level string to slog.Level.
 // Valid values: "debug", "info", "warn", "error" (case-insensitive).
 // Returns slog.LevelInfo for unrecognized values.
-func ParseLevel(level string) slog.Level {
-	switch strings.ToLower(level) {
+func ParseLevel(name string) slog.Level {
+	l, _ := LookupLevel(name)
+	return l
+}
+
+// LookupLevel is like ParseLevel but reports whether name was recognized.
+func LookupLevel(name string) (slog.Level, bool) {
+	switch strings.ToLower(name) {
 	case "debug":
-		return slog.LevelDebug
+		return slog.LevelDebug, true
 	case "info":
-		return slog.LevelInfo
+		return slog.LevelInfo, true
 	case "warn":
-		return slog.LevelWarn
+		return slog.LevelWarn, true
 	case "error":
-		return slog.LevelError
+		return slog.LevelError, true
 	default:
-		return slog.LevelInfo
+		return slog.LevelInfo, false
 	}
 }
 
+// LevelName returns the name ParseLevel accepts for l, e.g. "debug".
+func LevelName(l slog.Level) string {
+	return strings.ToLower(l.String())
+}
+
+// Level returns the current level of the logger installed by Setup.
+func Level() slog.Level {
+	return level.Level()
+}
+
+// SetLevel changes the level of the logger installed by Setup while it runs.
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
 // Setup initializes the global slog logger to write to the specified path.
 // If path is empty, uses DefaultLogPath().
 // The level parameter controls logging verbosity (use ParseLevel to convert from string).
 // Returns a cleanup function to close the log file.
 // The log file is automatically rotated when it exceeds MaxLogSize.
-func Setup(path string, level slog.Level) (cleanup func(), err error) {
+func Setup(path string, l slog.Level) (cleanup func(), err error) {
 	if path == "" {
 		path = DefaultLogPath()
 	}
@@ -70,12 +95,13 @@ func Setup(path string, level slog.Level) (cleanup func(), err error) {
 	}
 
 	// Create JSON handler for structured logging
+	level.Set(l)
 	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
-		Level: level,
+		Level: &level,
 	})
 
-	// Set as default logger
-	slog.SetDefault(slog.New(handler))
+	// Set as default logger, forwarding records to any installed Sink
+	slog.SetDefault(slog.New(newSinkHandler(handler)))
 
 	return func() { w.Close() }, nil
 }
@@ -84,7 +110,7 @@ func Setup(path string, level slog.Level) (cleanup func(), err error) {
 // Useful for development when you want console output too.
 // The level parameter controls logging verbosity (use ParseLevel to convert from string).
 // The log file is automatically rotated when it exceeds MaxLogSize.
-func SetupMulti(path string, extra io.Writer, level slog.Level) (cleanup func(), err error) {
+func SetupMulti(path string, extra io.Writer, l slog.Level) (cleanup func(), err error) {
 	if path == "" {
 		path = DefaultLogPath()
 	}
@@ -104,11 +130,12 @@ func SetupMulti(path string, extra io.Writer, level slog.Level) (cleanup func(),
 	// Create multi-writer
 	w := io.MultiWriter(rw, extra)
 
+	level.Set(l)
 	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
-		Level: level,
+		Level: &level,
 	})
 
-	slog.SetDefault(slog.New(handler))
+	slog.SetDefault(slog.New(newSinkHandler(handler)))
 
 	return func() { rw.Close() }, nil
 }
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -36,6 +37,53 @@ func TestParseLevel(t *testing.T) {
 	}
 }
 
+func TestLookupLevel(t *testing.T) {
+	if l, ok := LookupLevel("WARN"); !ok || l != slog.LevelWarn {
+		t.Errorf("LookupLevel(WARN) = %v, %v, want warn, true", l, ok)
+	}
+	if _, ok := LookupLevel("trace"); ok {
+		t.Error("LookupLevel(trace) ok = true, want false")
+	}
+	for _, name := range []string{"debug", "info", "warn", "error"} {
+		if got := LevelName(ParseLevel(name)); got != name {
+			t.Errorf("LevelName(ParseLevel(%q)) = %q", name, got)
+		}
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() {
+		slog.SetDefault(prev)
+		SetLevel(slog.LevelInfo)
+	})
+
+	path := filepath.Join(t.TempDir(), "fab.log")
+	cleanup, err := Setup(path, slog.LevelInfo)
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	defer cleanup()
+
+	slog.Debug("before")
+	SetLevel(slog.LevelDebug)
+	if Level() != slog.LevelDebug {
+		t.Errorf("Level() = %v, want debug", Level())
+	}
+	slog.Debug("after")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Contains(string(content), `"before"`) {
+		t.Error("debug record logged at info level")
+	}
+	if !strings.Contains(string(content), `"after"`) {
+		t.Errorf("debug record missing after SetLevel(debug): %s", content)
+	}
+}
+
 func TestTruncateForLog(t *testing.T) {
 	tests := []struct {
 		name   string
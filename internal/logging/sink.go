@@ -0,0 +1,163 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Record is a flattened log record delivered to a Sink.
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]string // Group-qualified keys (e.g. "req.id")
+}
+
+// Sink receives log records in addition to the log file.
+// HandleRecord is called synchronously from the logging call site,
+// so implementations must not block and must not log.
+type Sink interface {
+	// Enabled reports whether the sink wants records at the given level.
+	Enabled(level slog.Level) bool
+	// HandleRecord delivers a record to the sink.
+	HandleRecord(r Record)
+}
+
+// sinkHolder wraps a Sink so it can be stored in an atomic.Pointer.
+type sinkHolder struct {
+	sink Sink
+}
+
+// currentSink is the process-wide log sink, or nil if none is installed.
+var currentSink atomic.Pointer[sinkHolder]
+
+// SetSink installs a sink that receives every record logged through a
+// logger created by Setup or SetupMulti. Pass nil to remove it.
+func SetSink(sink Sink) {
+	if sink == nil {
+		currentSink.Store(nil)
+		return
+	}
+	currentSink.Store(&sinkHolder{sink: sink})
+}
+
+// loadSink returns the installed sink, or nil.
+func loadSink() Sink {
+	if h := currentSink.Load(); h != nil {
+		return h.sink
+	}
+	return nil
+}
+
+// sinkHandler wraps a slog.Handler and also forwards records to the installed Sink.
+// Records below the base handler's level still reach the sink if it asks for them.
+type sinkHandler struct {
+	base   slog.Handler
+	attrs  []slog.Attr // Attributes added via WithAttrs, already group-qualified
+	prefix string      // Group prefix for record attributes ("a.b.")
+}
+
+// newSinkHandler wraps base so records are also forwarded to the installed Sink.
+func newSinkHandler(base slog.Handler) *sinkHandler {
+	return &sinkHandler{base: base}
+}
+
+// Enabled implements slog.Handler.
+func (h *sinkHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.base.Enabled(ctx, level) {
+		return true
+	}
+	sink := loadSink()
+	return sink != nil && sink.Enabled(level)
+}
+
+// Handle implements slog.Handler.
+func (h *sinkHandler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	if h.base.Enabled(ctx, r.Level) {
+		err = h.base.Handle(ctx, r)
+	}
+
+	sink := loadSink()
+	if sink == nil || !sink.Enabled(r.Level) {
+		return err
+	}
+
+	attrs := make(map[string]string, len(h.attrs)+r.NumAttrs())
+	for _, a := range h.attrs {
+		flattenAttr(attrs, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		flattenAttr(attrs, h.prefix, a)
+		return true
+	})
+
+	sink.HandleRecord(Record{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   attrs,
+	})
+	return err
+}
+
+// WithAttrs implements slog.Handler.
+func (h *sinkHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	qualified := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	qualified = append(qualified, h.attrs...)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		qualified = append(qualified, a)
+	}
+	return &sinkHandler{
+		base:   h.base.WithAttrs(attrs),
+		attrs:  qualified,
+		prefix: h.prefix,
+	}
+}
+
+// WithGroup implements slog.Handler.
+func (h *sinkHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &sinkHandler{
+		base:   h.base.WithGroup(name),
+		attrs:  h.attrs,
+		prefix: h.prefix + name + ".",
+	}
+}
+
+// flattenAttr writes a into m, expanding groups into dotted keys.
+func flattenAttr(m map[string]string, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = prefix + a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			flattenAttr(m, groupPrefix, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	m[prefix+a.Key] = formatValue(v)
+}
+
+// formatValue renders a resolved slog.Value as a string.
+func formatValue(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v.Any())
+	}
+}
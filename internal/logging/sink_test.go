@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	level   slog.Level
+	records []Record
+}
+
+func (s *recordingSink) Enabled(level slog.Level) bool {
+	return level >= s.level
+}
+
+func (s *recordingSink) HandleRecord(r Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, r)
+}
+
+func TestSinkHandler(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := slog.New(newSinkHandler(base))
+
+	sink := &recordingSink{level: slog.LevelDebug}
+	SetSink(sink)
+	defer SetSink(nil)
+
+	logger.With("project", "myapp").WithGroup("req").Debug("debug only", "id", 7)
+	logger.Info("hello", slog.Group("agent", "id", "abc"))
+
+	if len(sink.records) != 2 {
+		t.Fatalf("sink got %d records, want 2", len(sink.records))
+	}
+
+	first := sink.records[0]
+	if first.Message != "debug only" || first.Level != slog.LevelDebug {
+		t.Errorf("first record = %+v", first)
+	}
+	if first.Attrs["project"] != "myapp" {
+		t.Errorf("Attrs[project] = %q, want myapp", first.Attrs["project"])
+	}
+	if first.Attrs["req.id"] != "7" {
+		t.Errorf("Attrs[req.id] = %q, want 7", first.Attrs["req.id"])
+	}
+
+	if got := sink.records[1].Attrs["agent.id"]; got != "abc" {
+		t.Errorf("Attrs[agent.id] = %q, want abc", got)
+	}
+
+	// The base handler only receives records at its own level.
+	if bytes.Contains(buf.Bytes(), []byte("debug only")) {
+		t.Error("debug record written to base handler below its level")
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("hello")) {
+		t.Error("info record missing from base handler")
+	}
+}
+
+func TestSinkHandler_NoSink(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	h := newSinkHandler(base)
+
+	SetSink(nil)
+	if h.Enabled(t.Context(), slog.LevelDebug) {
+		t.Error("Enabled(debug) = true with no sink and info base level")
+	}
+}
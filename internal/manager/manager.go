@@ -12,6 +12,7 @@ import (
 	"github.com/tessro/fab/internal/backend"
 	"github.com/tessro/fab/internal/plugin"
 	"github.com/tessro/fab/internal/processagent"
+	"github.com/tessro/fab/internal/rules"
 )
 
 // Re-export errors from processagent for backward compatibility.
@@ -69,7 +70,7 @@ func New(workDir string, project string, b backend.Backend, allowedPatterns []st
 	m := &Manager{
 		backend:         b,
 		project:         project,
-		allowedPatterns: allowedPatterns,
+		allowedPatterns: withDelegatePattern(allowedPatterns),
 	}
 
 	config := processagent.Config{
@@ -123,7 +124,7 @@ func (m *Manager) buildCommand() (*exec.Cmd, error) {
 
 	// Use backend to build the command
 	// Note: InitialPrompt is sent via processagent.Config.InitialPrompt after startup
-	return m.backend.BuildCommand(backend.CommandConfig{
+	return backend.Command(m.backend, backend.CommandConfig{
 		WorkDir:   m.WorkDir(),
 		AgentID:   "manager:" + m.project,
 		PluginDir: plugin.DefaultInstallDir(),
@@ -187,6 +188,21 @@ func convertPatternToClaudeCode(pattern string) string {
 	return fmt.Sprintf("Bash(%s)", pattern)
 }
 
+// DelegatePattern allows the manager to run 'fab agent delegate' without prompting.
+// It is always allowed so custom pattern sets don't break delegation.
+const DelegatePattern = "fab agent delegate:*"
+
+// withDelegatePattern returns patterns with DelegatePattern appended,
+// unless an existing pattern already covers it.
+func withDelegatePattern(patterns []string) []string {
+	for _, p := range patterns {
+		if rules.MatchPattern(p, "fab agent delegate") {
+			return patterns
+		}
+	}
+	return append(append([]string(nil), patterns...), DelegatePattern)
+}
+
 // buildManagerSystemPrompt creates the system prompt for the manager agent.
 // The manager is project-scoped and works in that project's worktree.
 func buildManagerSystemPrompt(fabPath string, project string) string {
@@ -230,6 +246,9 @@ You can read and explore the project's code using standard tools:
 - fab claims list - List claimed tickets
 - fab project start %s - Start orchestration (agents pick up work)
 - fab project stop %s - Stop orchestration
+- fab agent delegate <issue-id> - Spawn an agent to work on a specific issue right away (orchestration must be running)
+- fab notes - Read the findings agents shared about this project (summarize them when asked what agents have learned)
+- fab notes add "..." - Share a finding with the project's agents
 
 ### fab issue (Issue Management)
 - fab issue list - List all issues for this project
@@ -303,6 +322,9 @@ User: "How does the authentication work?"
 User: "What files handle API routing?"
 → Search for routing patterns and explain the structure
 
+User: "Get an agent on issue 42 right away"
+→ Run: fab agent delegate 42
+
 User: "Add a logout button to the app"
 → Run: fab issue create "Add logout button" --type feature --priority 1 --description "Add a logout button to the application UI"
 → Then suggest: fab project start %s to ensure agents pick it up
@@ -162,3 +162,17 @@ func TestBackendInjection(t *testing.T) {
 		t.Error("FAB_MANAGER=1 not found in Env")
 	}
 }
+
+func TestWithDelegatePattern(t *testing.T) {
+	// Covered by a broader pattern: unchanged
+	got := withDelegatePattern([]string{"fab:*", "git:*"})
+	if len(got) != 2 {
+		t.Errorf("withDelegatePattern(fab:*) = %v, want unchanged", got)
+	}
+
+	// Not covered: appended
+	got = withDelegatePattern([]string{"fab status", "git:*"})
+	if len(got) != 3 || got[2] != DelegatePattern {
+		t.Errorf("withDelegatePattern() = %v, want %s appended", got, DelegatePattern)
+	}
+}
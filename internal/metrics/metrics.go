@@ -0,0 +1,190 @@
+// Package metrics counts daemon events and serves them, along with gauges
+// sampled at scrape time, in the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Metric names.
+const (
+	// Counters, incremented at instrumented call sites
+	CommitsMerged   = "fab_commits_merged_total"   // Labels: project
+	GraphQLRequests = "fab_graphql_requests_total" // Labels: backend
+
+	// Gauges, sampled when /metrics is scraped
+	Agents             = "fab_agents"              // Labels: state
+	ActiveProjects     = "fab_active_projects"     // No labels
+	PendingPermissions = "fab_pending_permissions" // No labels
+)
+
+// family describes a metric for the HELP and TYPE lines.
+type family struct {
+	kind string // "counter" or "gauge"
+	help string
+}
+
+var families = map[string]family{
+	CommitsMerged:      {"counter", "Agent branches merged into the default branch."},
+	GraphQLRequests:    {"counter", "GraphQL API requests made by issue backends."},
+	Agents:             {"gauge", "Agents by state."},
+	ActiveProjects:     {"gauge", "Projects with orchestration running."},
+	PendingPermissions: {"gauge", "Permission requests waiting for an answer."},
+}
+
+// Label is a metric label name and value.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is one value of a metric with its labels.
+type Sample struct {
+	Name   string
+	Labels []Label
+	Value  float64
+}
+
+// Recorder counts events at instrumented call sites.
+type Recorder interface {
+	Inc(name string, labels ...Label)
+}
+
+// nopRecorder discards everything; it is the recorder until SetRecorder is
+// called, so instrumentation costs nothing when metrics are disabled.
+type nopRecorder struct{}
+
+func (nopRecorder) Inc(string, ...Label) {}
+
+// recorderBox lets an interface value be stored in an atomic.Value.
+type recorderBox struct{ Recorder }
+
+var current atomic.Value // recorderBox
+
+func init() {
+	current.Store(recorderBox{nopRecorder{}})
+}
+
+// SetRecorder installs the recorder that Inc reports to. nil restores the
+// no-op recorder.
+func SetRecorder(r Recorder) {
+	if r == nil {
+		r = nopRecorder{}
+	}
+	current.Store(recorderBox{r})
+}
+
+// Inc increments the counter name with labels on the installed recorder.
+func Inc(name string, labels ...Label) {
+	current.Load().(recorderBox).Inc(name, labels...)
+}
+
+// Registry is a Recorder that keeps counters in memory.
+// All methods are safe for concurrent use.
+type Registry struct {
+	mu sync.Mutex
+	// +checklocks:mu
+	counters map[string]*Sample // Series key -> sample
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{counters: make(map[string]*Sample)}
+}
+
+// Inc increments a counter.
+func (r *Registry) Inc(name string, labels ...Label) {
+	key := name + formatLabels(labels)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.counters[key]
+	if !ok {
+		s = &Sample{Name: name, Labels: slices.Clone(labels)}
+		r.counters[key] = s
+	}
+	s.Value++
+}
+
+// Counters returns a copy of all counters.
+func (r *Registry) Counters() []Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	samples := make([]Sample, 0, len(r.counters))
+	for _, s := range r.counters {
+		samples = append(samples, *s)
+	}
+	return samples
+}
+
+// Handler serves the registry's counters and the samples returned by gauges
+// in the Prometheus text exposition format.
+func Handler(r *Registry, gauges func() []Sample) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		samples := r.Counters()
+		if gauges != nil {
+			samples = append(samples, gauges()...)
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := Write(w, samples); err != nil {
+			slog.Debug("failed to write metrics", "error", err)
+		}
+	})
+}
+
+// Write renders samples in the Prometheus text exposition format, grouped
+// by metric with HELP and TYPE lines, in a stable order.
+func Write(w io.Writer, samples []Sample) error {
+	lines := make([]string, len(samples))
+	for i, s := range samples {
+		lines[i] = s.Name + formatLabels(s.Labels) + " " + strconv.FormatFloat(s.Value, 'g', -1, 64)
+	}
+	order := make([]int, len(samples))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		if c := strings.Compare(samples[a].Name, samples[b].Name); c != 0 {
+			return c
+		}
+		return strings.Compare(lines[a], lines[b])
+	})
+
+	var b strings.Builder
+	prev := ""
+	for _, i := range order {
+		if name := samples[i].Name; name != prev {
+			if f, ok := families[name]; ok {
+				fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, f.help, name, f.kind)
+			}
+			prev = name
+		}
+		b.WriteString(lines[i])
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatLabels renders labels as {name="value",...}, or "" if there are none.
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = l.Name + `="` + labelEscaper.Replace(l.Value) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// labelEscaper escapes backslashes, quotes, and newlines in label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	reg := NewRegistry()
+	reg.Inc(CommitsMerged, Label{Name: "project", Value: "web"})
+	reg.Inc(CommitsMerged, Label{Name: "project", Value: "api"})
+	reg.Inc(CommitsMerged, Label{Name: "project", Value: "web"})
+	reg.Inc(GraphQLRequests, Label{Name: "backend", Value: `gh"\`})
+
+	samples := append(reg.Counters(), Sample{Name: ActiveProjects, Value: 2})
+	var b strings.Builder
+	if err := Write(&b, samples); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := `# HELP fab_active_projects Projects with orchestration running.
+# TYPE fab_active_projects gauge
+fab_active_projects 2
+# HELP fab_commits_merged_total Agent branches merged into the default branch.
+# TYPE fab_commits_merged_total counter
+fab_commits_merged_total{project="api"} 1
+fab_commits_merged_total{project="web"} 2
+# HELP fab_graphql_requests_total GraphQL API requests made by issue backends.
+# TYPE fab_graphql_requests_total counter
+fab_graphql_requests_total{backend="gh\"\\"} 1
+`
+	if got := b.String(); got != want {
+		t.Errorf("Write() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestInc_UsesInstalledRecorder(t *testing.T) {
+	t.Cleanup(func() { SetRecorder(nil) })
+
+	// The default recorder discards counts
+	Inc(CommitsMerged)
+
+	reg := NewRegistry()
+	SetRecorder(reg)
+	Inc(CommitsMerged, Label{Name: "project", Value: "web"})
+
+	counters := reg.Counters()
+	if len(counters) != 1 || counters[0].Value != 1 {
+		t.Errorf("Counters() = %+v, want one count", counters)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	reg := NewRegistry()
+	reg.Inc(CommitsMerged, Label{Name: "project", Value: "web"})
+	h := Handler(reg, func() []Sample {
+		return []Sample{{Name: PendingPermissions, Value: 3}}
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{`fab_commits_merged_total{project="web"} 1`, "fab_pending_permissions 3"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Package notify delivers out-of-band notifications (webhooks, desktop alerts)
+// for key daemon events such as permission requests and completed work.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/tessro/fab/internal/logging"
+)
+
+// EventType identifies the kind of event being notified.
+type EventType string
+
+const (
+	// EventPermissionRequest fires when an agent is waiting on a permission decision.
+	EventPermissionRequest EventType = "permission_request"
+	// EventUserQuestion fires when an agent asks the user a question.
+	EventUserQuestion EventType = "user_question"
+	// EventAgentDone fires when an agent finishes its work.
+	EventAgentDone EventType = "agent_done"
+)
+
+// ValidEventTypes returns all event types that can trigger notifications.
+func ValidEventTypes() []EventType {
+	return []EventType{EventPermissionRequest, EventUserQuestion, EventAgentDone}
+}
+
+// DefaultTimeout bounds how long a single notification may take to deliver.
+const DefaultTimeout = 10 * time.Second
+
+// Event describes something worth notifying the user about.
+type Event struct {
+	Type    EventType `json:"type"`
+	Project string    `json:"project,omitempty"`
+	AgentID string    `json:"agent_id,omitempty"`
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// Notifier delivers a single event to some destination.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// Config configures which notifiers are enabled and which events they receive.
+type Config struct {
+	WebhookURL string      // POST events as JSON to this URL (empty = disabled)
+	Desktop    bool        // Show desktop notifications via osascript/notify-send
+	Events     []EventType // Event types to notify on (empty = all)
+}
+
+// Dispatcher fans events out to notifiers asynchronously.
+// A nil Dispatcher is valid and drops all events.
+type Dispatcher struct {
+	notifiers []Notifier
+	events    map[EventType]bool // nil means all events
+	timeout   time.Duration
+}
+
+// New creates a Dispatcher from the given config.
+// Returns nil if no notifiers are configured.
+func New(cfg Config) *Dispatcher {
+	var notifiers []Notifier
+	if cfg.WebhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(cfg.WebhookURL))
+	}
+	if cfg.Desktop {
+		notifiers = append(notifiers, &DesktopNotifier{})
+	}
+	return NewDispatcher(cfg.Events, notifiers...)
+}
+
+// NewDispatcher creates a Dispatcher for the given notifiers.
+// If events is empty, all event types are delivered.
+// Returns nil if no notifiers are given.
+func NewDispatcher(events []EventType, notifiers ...Notifier) *Dispatcher {
+	if len(notifiers) == 0 {
+		return nil
+	}
+
+	d := &Dispatcher{
+		notifiers: notifiers,
+		timeout:   DefaultTimeout,
+	}
+	if len(events) > 0 {
+		d.events = make(map[EventType]bool, len(events))
+		for _, e := range events {
+			d.events[e] = true
+		}
+	}
+	return d
+}
+
+// Enabled reports whether events of the given type are delivered.
+func (d *Dispatcher) Enabled(t EventType) bool {
+	if d == nil {
+		return false
+	}
+	return d.events == nil || d.events[t]
+}
+
+// Send delivers an event to all notifiers in the background.
+// It never blocks the caller; delivery failures are logged.
+func (d *Dispatcher) Send(event Event) {
+	if !d.Enabled(event.Type) {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	for _, n := range d.notifiers {
+		go func(n Notifier) {
+			defer logging.LogPanic("notify", nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+			defer cancel()
+
+			if err := n.Notify(ctx, event); err != nil {
+				slog.Warn("notification failed",
+					"type", event.Type,
+					"notifier", fmt.Sprintf("%T", n),
+					"error", err,
+				)
+			}
+		}(n)
+	}
+}
+
+// WebhookNotifier POSTs events as JSON to a URL.
+// The payload includes a "text" field so Slack incoming webhooks work as-is.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookNotifier creates a WebhookNotifier for the given URL.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:    url,
+		Client: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// webhookPayload is the JSON body sent to webhooks.
+type webhookPayload struct {
+	Text string `json:"text"`
+	Event
+}
+
+// Notify sends the event to the webhook.
+func (w *WebhookNotifier) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(webhookPayload{
+		Text:  fmt.Sprintf("🚌 %s: %s", event.Title, event.Message),
+		Event: event,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// DesktopNotifier shows a native desktop notification.
+// Uses osascript on macOS and notify-send elsewhere.
+type DesktopNotifier struct{}
+
+// Notify displays the event as a desktop notification.
+func (DesktopNotifier) Notify(ctx context.Context, event Event) error {
+	title := "🚌 fab: " + event.Title
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		script := fmt.Sprintf("display notification %q with title %q", event.Message, title)
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	} else {
+		cmd = exec.CommandContext(ctx, "notify-send", title, event.Message)
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w\n%s", cmd.Path, err, output)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingNotifier captures delivered events for assertions.
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []Event
+	ch     chan struct{}
+}
+
+func newRecordingNotifier() *recordingNotifier {
+	return &recordingNotifier{ch: make(chan struct{}, 10)}
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, event Event) error {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+	r.ch <- struct{}{}
+	return nil
+}
+
+func TestNew_NoNotifiersReturnsNil(t *testing.T) {
+	if d := New(Config{}); d != nil {
+		t.Errorf("New(Config{}) = %v, want nil", d)
+	}
+}
+
+func TestDispatcher_NilIsSafe(t *testing.T) {
+	var d *Dispatcher
+	if d.Enabled(EventAgentDone) {
+		t.Error("nil dispatcher should not be enabled")
+	}
+	// Must not panic
+	d.Send(Event{Type: EventAgentDone})
+}
+
+func TestDispatcher_FiltersEvents(t *testing.T) {
+	rec := newRecordingNotifier()
+	d := NewDispatcher([]EventType{EventAgentDone}, rec)
+
+	if d.Enabled(EventPermissionRequest) {
+		t.Error("permission_request should be filtered out")
+	}
+	if !d.Enabled(EventAgentDone) {
+		t.Error("agent_done should be enabled")
+	}
+
+	d.Send(Event{Type: EventPermissionRequest, Title: "skip"})
+	d.Send(Event{Type: EventAgentDone, Title: "keep"})
+
+	select {
+	case <-rec.ch:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for notification")
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.events) != 1 || rec.events[0].Title != "keep" {
+		t.Errorf("events = %+v, want only the agent_done event", rec.events)
+	}
+	if rec.events[0].Time.IsZero() {
+		t.Error("Time should be set when empty")
+	}
+}
+
+func TestDispatcher_EmptyEventsMeansAll(t *testing.T) {
+	d := NewDispatcher(nil, newRecordingNotifier())
+	for _, e := range ValidEventTypes() {
+		if !d.Enabled(e) {
+			t.Errorf("Enabled(%s) = false, want true", e)
+		}
+	}
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	n := NewWebhookNotifier(srv.URL)
+	err := n.Notify(context.Background(), Event{
+		Type:    EventAgentDone,
+		Project: "myapp",
+		AgentID: "abc123",
+		Title:   "Agent done",
+		Message: "Agent abc123 merged its work",
+	})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if got["type"] != string(EventAgentDone) {
+		t.Errorf("type = %v, want %s", got["type"], EventAgentDone)
+	}
+	if got["project"] != "myapp" {
+		t.Errorf("project = %v, want myapp", got["project"])
+	}
+	if got["text"] != "🚌 Agent done: Agent abc123 merged its work" {
+		t.Errorf("text = %v", got["text"])
+	}
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	n := NewWebhookNotifier(srv.URL)
+	if err := n.Notify(context.Background(), Event{Type: EventAgentDone}); err == nil {
+		t.Error("Notify() error = nil, want error for 500 status")
+	}
+}
@@ -0,0 +1,48 @@
+package orchestrator
+
+import "time"
+
+// backlogTTL is how long a backlog count is reused. Status is polled by
+// every attached TUI, and issue backends like GitHub are rate limited.
+const backlogTTL = 30 * time.Second
+
+// Backlog describes ready work waiting for an agent.
+type Backlog struct {
+	Waiting    int  // Ready issues that aren't claimed or blocked
+	AtCapacity bool // Every normal agent slot is taken
+}
+
+// Backlog returns how many ready issues are waiting and whether the project
+// is at its agent limit, so a full project's queue is visible instead of
+// waiting silently. The issue count is cached for backlogTTL; capacity is
+// always current.
+func (o *Orchestrator) Backlog() (Backlog, error) {
+	waiting, err := o.waitingIssues()
+	if err != nil {
+		return Backlog{}, err
+	}
+	normalActive := o.agents.CountByProject(o.project.Name) - o.priorityAgentCount()
+	return Backlog{
+		Waiting:    waiting,
+		AtCapacity: normalActive >= o.project.MaxAgents,
+	}, nil
+}
+
+// waitingIssues returns the number of unclaimed ready issues, querying the
+// issue backend at most once per backlogTTL. Concurrent callers wait for
+// one query rather than each making their own.
+func (o *Orchestrator) waitingIssues() (int, error) {
+	o.backlogMu.Lock()
+	defer o.backlogMu.Unlock()
+
+	if !o.backlogAt.IsZero() && time.Since(o.backlogAt) < backlogTTL {
+		return o.backlogWaiting, nil
+	}
+	ready, err := o.unclaimedReadyIssues()
+	if err != nil {
+		return 0, err
+	}
+	o.backlogWaiting = len(ready)
+	o.backlogAt = time.Now()
+	return o.backlogWaiting, nil
+}
@@ -2,6 +2,9 @@ package orchestrator
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -11,6 +14,22 @@ var (
 	ErrNotClaimed     = errors.New("ticket not claimed")
 )
 
+// reservationPrefix starts the placeholder agent IDs that hold reserved
+// claims.
+const reservationPrefix = "reserved:"
+
+// isReservation reports whether agentID is a reservation placeholder.
+func isReservation(agentID string) bool {
+	return strings.HasPrefix(agentID, reservationPrefix)
+}
+
+// ClaimChange describes a claim being taken or released.
+type ClaimChange struct {
+	TicketID string
+	AgentID  string
+	Claimed  bool // False if the claim was released
+}
+
 // ClaimRegistry tracks which tickets are claimed by which agents.
 // Claims are held in memory and cleared on daemon restart.
 // All methods are safe for concurrent use.
@@ -18,6 +37,10 @@ type ClaimRegistry struct {
 	mu sync.RWMutex
 	// +checklocks:mu
 	claims map[string]string // ticketID -> agentID
+	// +checklocks:mu
+	onChange func(ClaimChange)
+	// +checklocks:mu
+	reservations int // Number of reservations made, for placeholder IDs
 }
 
 // NewClaimRegistry creates a new ClaimRegistry.
@@ -27,43 +50,111 @@ func NewClaimRegistry() *ClaimRegistry {
 	}
 }
 
+// OnChange sets a function called after each new claim and each release of
+// a held claim. It runs without the registry's lock held, so it may call
+// back into the registry. Reservations are reported when they are
+// transferred to an agent, not when they are made or dropped.
+func (r *ClaimRegistry) OnChange(fn func(ClaimChange)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = fn
+}
+
 // Claim attempts to claim a ticket for an agent.
 // Returns ErrAlreadyClaimed if another agent already holds the claim.
 // Claiming a ticket already held by the same agent is idempotent (returns nil).
 func (r *ClaimRegistry) Claim(ticketID, agentID string) error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	if existing, ok := r.claims[ticketID]; ok {
+		r.mu.Unlock()
 		if existing == agentID {
 			return nil // Idempotent - already claimed by same agent
 		}
 		return ErrAlreadyClaimed
 	}
 	r.claims[ticketID] = agentID
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	if onChange != nil {
+		onChange(ClaimChange{TicketID: ticketID, AgentID: agentID, Claimed: true})
+	}
+	return nil
+}
+
+// Reserve claims a ticket for an agent that hasn't been created yet, so no
+// other agent can claim it in the meantime. The claim is held by the
+// returned placeholder ID until Transfer hands it to the agent; Release
+// drops it if the agent can't be created.
+// Returns ErrAlreadyClaimed if the ticket is already claimed or reserved.
+func (r *ClaimRegistry) Reserve(ticketID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.claims[ticketID]; ok {
+		return "", ErrAlreadyClaimed
+	}
+	r.reservations++
+	placeholder := fmt.Sprintf("%s%d", reservationPrefix, r.reservations)
+	r.claims[ticketID] = placeholder
+	return placeholder, nil
+}
+
+// Transfer moves the claim on a ticket from one holder to another, such as
+// from a reservation placeholder to the agent it was made for.
+// Returns ErrNotClaimed if from doesn't hold the claim.
+func (r *ClaimRegistry) Transfer(ticketID, from, to string) error {
+	r.mu.Lock()
+	if r.claims[ticketID] != from {
+		r.mu.Unlock()
+		return ErrNotClaimed
+	}
+	r.claims[ticketID] = to
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	if onChange != nil && from != to {
+		if !isReservation(from) {
+			onChange(ClaimChange{TicketID: ticketID, AgentID: from})
+		}
+		onChange(ClaimChange{TicketID: ticketID, AgentID: to, Claimed: true})
+	}
 	return nil
 }
 
 // Release releases a claim on a specific ticket.
 func (r *ClaimRegistry) Release(ticketID string) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	agentID, ok := r.claims[ticketID]
 	delete(r.claims, ticketID)
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	if ok && onChange != nil && !isReservation(agentID) {
+		onChange(ClaimChange{TicketID: ticketID, AgentID: agentID})
+	}
 }
 
 // ReleaseByAgent releases all claims held by an agent.
 // Returns the number of claims released.
 func (r *ClaimRegistry) ReleaseByAgent(agentID string) int {
 	r.mu.Lock()
-	defer r.mu.Unlock()
-	count := 0
+	var released []string
 	for tid, aid := range r.claims {
 		if aid == agentID {
 			delete(r.claims, tid)
-			count++
+			released = append(released, tid)
+		}
+	}
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	if onChange != nil {
+		slices.Sort(released)
+		for _, tid := range released {
+			onChange(ClaimChange{TicketID: tid, AgentID: agentID})
 		}
 	}
-	return count
+	return len(released)
 }
 
 // ClaimedBy returns the agent ID holding the claim on a ticket, or empty string if unclaimed.
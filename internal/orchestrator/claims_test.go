@@ -1,6 +1,9 @@
 package orchestrator
 
 import (
+	"fmt"
+	"slices"
+	"sync"
 	"testing"
 )
 
@@ -132,3 +135,121 @@ func TestClaimRegistry_Count(t *testing.T) {
 		t.Errorf("expected 2 claims, got %d", r.Count())
 	}
 }
+
+func TestClaimRegistry_OnChange(t *testing.T) {
+	r := NewClaimRegistry()
+	var changes []ClaimChange
+	r.OnChange(func(c ClaimChange) {
+		// Runs without the lock held, so reading back must not deadlock
+		_ = r.IsClaimed(c.TicketID)
+		changes = append(changes, c)
+	})
+
+	_ = r.Claim("TICKET-1", "agent-1")
+	_ = r.Claim("TICKET-1", "agent-1") // Idempotent, not a change
+	_ = r.Claim("TICKET-1", "agent-2") // Rejected, not a change
+	_ = r.Claim("TICKET-2", "agent-1")
+	_ = r.Claim("TICKET-3", "agent-2")
+	r.Release("TICKET-3")
+	r.Release("TICKET-3") // Not held, not a change
+	r.ReleaseByAgent("agent-1")
+
+	want := []ClaimChange{
+		{TicketID: "TICKET-1", AgentID: "agent-1", Claimed: true},
+		{TicketID: "TICKET-2", AgentID: "agent-1", Claimed: true},
+		{TicketID: "TICKET-3", AgentID: "agent-2", Claimed: true},
+		{TicketID: "TICKET-3", AgentID: "agent-2"},
+		{TicketID: "TICKET-1", AgentID: "agent-1"},
+		{TicketID: "TICKET-2", AgentID: "agent-1"},
+	}
+	if !slices.Equal(changes, want) {
+		t.Errorf("changes = %+v, want %+v", changes, want)
+	}
+}
+
+func TestClaimRegistry_ReserveTransfer(t *testing.T) {
+	r := NewClaimRegistry()
+	var changes []ClaimChange
+	r.OnChange(func(c ClaimChange) { changes = append(changes, c) })
+
+	placeholder, err := r.Reserve("TICKET-1")
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if _, err := r.Reserve("TICKET-1"); err != ErrAlreadyClaimed {
+		t.Errorf("second Reserve() error = %v, want ErrAlreadyClaimed", err)
+	}
+	if err := r.Claim("TICKET-1", "agent-2"); err != ErrAlreadyClaimed {
+		t.Errorf("Claim() of reserved ticket error = %v, want ErrAlreadyClaimed", err)
+	}
+
+	if err := r.Transfer("TICKET-1", "agent-2", "agent-1"); err != ErrNotClaimed {
+		t.Errorf("Transfer() from non-holder error = %v, want ErrNotClaimed", err)
+	}
+	if err := r.Transfer("TICKET-1", placeholder, "agent-1"); err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+	if got := r.ClaimedBy("TICKET-1"); got != "agent-1" {
+		t.Errorf("ClaimedBy() = %q, want agent-1", got)
+	}
+
+	// A dropped reservation is never reported
+	placeholder2, _ := r.Reserve("TICKET-2")
+	r.Release("TICKET-2")
+	if r.IsClaimed("TICKET-2") || placeholder2 == placeholder {
+		t.Errorf("expected a distinct reservation that Release drops, got %q", placeholder2)
+	}
+
+	want := []ClaimChange{{TicketID: "TICKET-1", AgentID: "agent-1", Claimed: true}}
+	if !slices.Equal(changes, want) {
+		t.Errorf("changes = %+v, want %+v", changes, want)
+	}
+}
+
+func TestClaimRegistry_ConcurrentReserve(t *testing.T) {
+	// Spawners racing for the same ready issues, as the orchestrator loop and
+	// delegations do, must each end up with a distinct ticket
+	r := NewClaimRegistry()
+	tickets := []string{"TICKET-1", "TICKET-2", "TICKET-3"}
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		won = make(map[string][]string) // ticketID -> agents it was handed to
+	)
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			agentID := fmt.Sprintf("agent-%d", i)
+			for _, tid := range tickets {
+				placeholder, err := r.Reserve(tid)
+				if err != nil {
+					continue
+				}
+				if err := r.Transfer(tid, placeholder, agentID); err != nil {
+					t.Errorf("Transfer(%s) error = %v", tid, err)
+					return
+				}
+				mu.Lock()
+				won[tid] = append(won[tid], agentID)
+				mu.Unlock()
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, tid := range tickets {
+		if len(won[tid]) != 1 {
+			t.Errorf("%s handed to %v, want exactly one agent", tid, won[tid])
+			continue
+		}
+		if got := r.ClaimedBy(tid); got != won[tid][0] {
+			t.Errorf("ClaimedBy(%s) = %q, want %q", tid, got, won[tid][0])
+		}
+	}
+	if r.Count() != len(tickets) {
+		t.Errorf("Count() = %d, want %d", r.Count(), len(tickets))
+	}
+}
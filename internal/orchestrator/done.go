@@ -0,0 +1,96 @@
+package orchestrator
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrDoneInProgress is returned when an agent signals done while an earlier
+// done for the same agent and task is still being handled.
+var ErrDoneInProgress = errors.New("agent done already in progress")
+
+// maxDoneResults caps how many completed done outcomes are remembered.
+const maxDoneResults = 256
+
+// doneKey identifies an agent.done call for deduplication.
+type doneKey struct {
+	agentID string
+	taskID  string
+}
+
+// beginDone marks key as in flight. If a successful outcome was already
+// recorded for key it is returned (marked Duplicate) instead, and if another
+// call for key is still running ErrDoneInProgress is returned.
+func (o *Orchestrator) beginDone(key doneKey) (*AgentDoneResult, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if prior, ok := o.doneResults[key]; ok {
+		dup := *prior
+		dup.Duplicate = true
+		return &dup, nil
+	}
+	if o.doneInFlight[key] {
+		return nil, ErrDoneInProgress
+	}
+	o.doneInFlight[key] = true
+	return nil, nil
+}
+
+// finishDone clears the in-flight mark for key and records result if the
+// agent's work landed (merged or PR opened), so a retried done returns it
+// instead of merging again.
+func (o *Orchestrator) finishDone(key doneKey, result *AgentDoneResult) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.doneInFlight, key)
+	if result == nil || !(result.Merged || result.PRCreated) {
+		return
+	}
+
+	if len(o.doneOrder) >= maxDoneResults {
+		delete(o.doneResults, o.doneOrder[0])
+		o.doneOrder = o.doneOrder[1:]
+	}
+	recorded := *result
+	o.doneResults[key] = &recorded
+	o.doneOrder = append(o.doneOrder, key)
+}
+
+// PriorDoneResult returns the recorded outcome of a successful done for
+// agentID and taskID, marked Duplicate. It lets a retried done be answered
+// after the agent itself has been deleted.
+func (o *Orchestrator) PriorDoneResult(agentID, taskID string) (*AgentDoneResult, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	prior, ok := o.doneResults[doneKey{agentID: agentID, taskID: taskID}]
+	if !ok {
+		return nil, false
+	}
+	dup := *prior
+	dup.Duplicate = true
+	return &dup, true
+}
+
+// DoneInFlight returns how many agent.done calls are still being handled,
+// i.e. gating, merging, or opening a pull request.
+func (o *Orchestrator) DoneInFlight() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.doneInFlight)
+}
+
+// WaitDone waits up to timeout for in-flight agent.done calls to finish and
+// reports whether they did.
+func (o *Orchestrator) WaitDone(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for o.DoneInFlight() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return true
+}
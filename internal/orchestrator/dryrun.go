@@ -0,0 +1,91 @@
+package orchestrator
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/tessro/fab/internal/issue"
+	"github.com/tessro/fab/internal/project"
+)
+
+// Decision types reported in dry-run mode.
+const (
+	DecisionWouldSpawn = "would_spawn"
+	DecisionWouldMerge = "would_merge"
+)
+
+// Decision describes an action the orchestrator would have taken if the
+// project weren't in dry-run mode.
+type Decision struct {
+	Type    string // DecisionWouldSpawn or DecisionWouldMerge
+	Project string // Project the orchestrator belongs to
+	IssueID string // Issue the action is for, if known
+	AgentID string // Agent the action is for (would_merge only)
+	Reason  string // Human-readable explanation of the decision
+}
+
+// reportDecision logs a dry-run decision and passes it to OnDecision.
+func (o *Orchestrator) reportDecision(d Decision) {
+	d.Project = o.project.Name
+	slog.Info("dry run: "+d.Type,
+		"project", o.project.Name,
+		"issue", d.IssueID,
+		"agent", d.AgentID,
+		"reason", d.Reason,
+	)
+	if o.config.OnDecision != nil {
+		o.config.OnDecision(d)
+	}
+}
+
+// reportDryRunSpawns reports the agents checkAndSpawnAgents would spawn for
+// readyIssues, following the same slot rules without creating anything.
+// Normal agents pick their own issue, so each is matched to the next ready
+// issue in backend order as the most likely pick.
+func (o *Orchestrator) reportDryRunSpawns(readyIssues []*issue.Issue, normalActive, available, reservedFree int) {
+	proj := o.project
+
+	if available <= 0 {
+		threshold := proj.GetPriorityThreshold()
+		for i, iss := range o.urgentIssues(readyIssues) {
+			if i >= reservedFree {
+				break
+			}
+			o.reportDecision(Decision{
+				Type:    DecisionWouldSpawn,
+				IssueID: iss.ID,
+				Reason: fmt.Sprintf("priority %d meets threshold %d and %d of %d reserved slots are free",
+					iss.Priority, threshold, reservedFree, proj.ReservedSlots),
+			})
+		}
+		return
+	}
+
+	for i := 0; i < available && i < len(readyIssues); i++ {
+		iss := readyIssues[i]
+		o.reportDecision(Decision{
+			Type:    DecisionWouldSpawn,
+			IssueID: iss.ID,
+			Reason: fmt.Sprintf("issue is ready and unclaimed; %d of %d agents running",
+				normalActive+i, proj.MaxAgents),
+		})
+	}
+}
+
+// reportDryRunMerge reports how HandleAgentDone would have landed the
+// agent's work, without running the pre-merge command, merging, or opening
+// a pull request.
+func (o *Orchestrator) reportDryRunMerge(agentID, taskID string) *AgentDoneResult {
+	branch := "fab/" + agentID
+	reason := fmt.Sprintf("would merge %s into %s", branch, o.project.GetDefaultBranch())
+	if o.project.GetMergeStrategy() == project.MergeStrategyPullRequest {
+		reason = fmt.Sprintf("would open a pull request for %s", branch)
+	}
+	o.reportDecision(Decision{
+		Type:    DecisionWouldMerge,
+		IssueID: taskID,
+		AgentID: agentID,
+		Reason:  reason,
+	})
+	return &AgentDoneResult{BranchName: branch, DryRun: true}
+}
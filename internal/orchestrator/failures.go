@@ -0,0 +1,277 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tessro/fab/internal/issue"
+)
+
+// AutoBlockedLabel is added to issues the orchestrator blocked after
+// repeated agent failures.
+const AutoBlockedLabel = "fab:auto-blocked"
+
+// Defaults for blocking issues whose agents keep failing.
+const (
+	DefaultFailureThreshold = 3
+	DefaultFailureWindow    = time.Hour
+)
+
+// issueFailures tracks consecutive agent failures on one issue.
+type issueFailures struct {
+	count   int
+	first   time.Time // First failure of the current run
+	last    time.Time
+	reason  string // Reason for the last failure
+	blocked bool   // Threshold reached; the issue is no longer handed out
+	marked  bool   // The block was recorded in the issue backend
+}
+
+// IssueFailureStats describes an issue's recent agent failures.
+type IssueFailureStats struct {
+	IssueID     string
+	Failures    int
+	LastFailure time.Time
+	LastReason  string
+	Blocked     bool
+}
+
+// failureThreshold returns how many consecutive failures block an issue.
+func (o *Orchestrator) failureThreshold() int {
+	if o.config.FailureThreshold > 0 {
+		return o.config.FailureThreshold
+	}
+	return DefaultFailureThreshold
+}
+
+// failureWindow returns how long a run of failures counts toward the threshold.
+func (o *Orchestrator) failureWindow() time.Duration {
+	if o.config.FailureWindow > 0 {
+		return o.config.FailureWindow
+	}
+	return DefaultFailureWindow
+}
+
+// RecordIssueFailure counts a failed attempt at an issue. Once the failure
+// threshold is reached within the failure window, the issue is marked
+// blocked in the issue backend with AutoBlockedLabel and a comment, and is
+// no longer handed out to new agents.
+func (o *Orchestrator) RecordIssueFailure(issueID, reason string) {
+	if issueID == "" {
+		return
+	}
+
+	now := time.Now()
+	o.mu.Lock()
+	f := o.failures[issueID]
+	if f == nil || now.Sub(f.first) > o.failureWindow() {
+		f = &issueFailures{first: now}
+		o.failures[issueID] = f
+	}
+	f.count++
+	f.last = now
+	f.reason = reason
+	block := !f.blocked && f.count >= o.failureThreshold()
+	if block {
+		f.blocked = true
+	}
+	count := f.count
+	o.mu.Unlock()
+
+	slog.Warn("agent failed on issue",
+		"project", o.project.Name,
+		"issue", issueID,
+		"failures", count,
+		"reason", reason,
+	)
+	if !block {
+		return
+	}
+
+	marked := o.markIssueBlocked(issueID, count, reason)
+	o.mu.Lock()
+	if f := o.failures[issueID]; f != nil {
+		f.marked = marked
+	}
+	o.mu.Unlock()
+}
+
+// recordIssueSuccess clears the failure count of an issue whose work landed.
+func (o *Orchestrator) recordIssueSuccess(issueID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.failures, issueID)
+}
+
+// IssueFailures returns the failure counts of issues with recent failures,
+// sorted by issue ID.
+func (o *Orchestrator) IssueFailures() []IssueFailureStats {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	stats := make([]IssueFailureStats, 0, len(o.failures))
+	for id, f := range o.failures {
+		stats = append(stats, IssueFailureStats{
+			IssueID:     id,
+			Failures:    f.count,
+			LastFailure: f.last,
+			LastReason:  f.reason,
+			Blocked:     f.blocked,
+		})
+	}
+	slices.SortFunc(stats, func(a, b IssueFailureStats) int {
+		return strings.Compare(a.IssueID, b.IssueID)
+	})
+	return stats
+}
+
+// BlocksClaim reports whether agents must not claim an issue because it was
+// blocked after repeated failures but the block couldn't be recorded in the
+// issue backend. Blocks the backend recorded are left to it, so unblocking
+// the issue there takes effect at once.
+func (o *Orchestrator) BlocksClaim(issueID string) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	f := o.failures[issueID]
+	return f != nil && f.blocked && !f.marked
+}
+
+// filterBlockedIssues drops issues blocked after repeated failures from
+// ready. An issue whose block was recorded in the backend but shows up as
+// ready again was unblocked by hand, so its failure count is cleared.
+func (o *Orchestrator) filterBlockedIssues(ready []*issue.Issue) []*issue.Issue {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var kept []*issue.Issue
+	for _, iss := range ready {
+		if f := o.failures[iss.ID]; f != nil && f.blocked {
+			if !f.marked {
+				continue
+			}
+			delete(o.failures, iss.ID)
+		}
+		kept = append(kept, iss)
+	}
+	return kept
+}
+
+// HandleAgentCrash records a failure for each issue claimed by an agent
+// whose process exited with an error, then releases its claims.
+// Returns the number of claims released.
+func (o *Orchestrator) HandleAgentCrash(agentID string, exitErr error) int {
+	reason := "agent crashed"
+	if exitErr != nil {
+		reason = fmt.Sprintf("agent crashed: %v", exitErr)
+	}
+	for _, issueID := range o.agentClaims(agentID) {
+		o.RecordIssueFailure(issueID, reason)
+	}
+	return o.claims.ReleaseByAgent(agentID)
+}
+
+// agentClaims returns the IDs of the issues an agent has claimed.
+func (o *Orchestrator) agentClaims(agentID string) []string {
+	var ids []string
+	for issueID, claimant := range o.claims.List() {
+		if claimant == agentID {
+			ids = append(ids, issueID)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// recordDoneOutcome updates the failure counts of the issues an agent
+// worked on from the outcome of its done: landed work clears them and a
+// failed merge or pre-merge check counts as a failure.
+func (o *Orchestrator) recordDoneOutcome(issueIDs []string, result *AgentDoneResult) {
+	if result == nil || result.DryRun || result.Duplicate {
+		return
+	}
+	for _, issueID := range issueIDs {
+		switch {
+		case result.Merged || result.PRCreated:
+			o.recordIssueSuccess(issueID)
+		case result.MergeError != "":
+			reason, _, _ := strings.Cut(result.MergeError, "\n")
+			o.RecordIssueFailure(issueID, "merge failed: "+reason)
+		}
+	}
+}
+
+// doneIssues returns the issues an agent.done covers: the task if given,
+// otherwise the agent's claims. Must be called before the claims are
+// released.
+func (o *Orchestrator) doneIssues(agentID, taskID string) []string {
+	if taskID != "" {
+		return []string{taskID}
+	}
+	return o.agentClaims(agentID)
+}
+
+// preservingUpdate returns update params that keep iss's type, priority,
+// and labels. Labels replace the existing ones, and backends that store type
+// and priority as labels only keep them when they're passed explicitly.
+func preservingUpdate(iss *issue.Issue) issue.UpdateParams {
+	params := issue.UpdateParams{
+		Priority: &iss.Priority,
+		Labels:   iss.Labels,
+	}
+	if iss.Type != "" {
+		params.Type = &iss.Type
+	}
+	return params
+}
+
+// markIssueBlocked sets an issue's status to blocked, adds AutoBlockedLabel,
+// and explains why in a comment if the backend supports comments. Returns
+// whether the status was updated.
+func (o *Orchestrator) markIssueBlocked(issueID string, failures int, reason string) bool {
+	if o.config.IssueBackendFactory == nil {
+		return false
+	}
+	backend, err := o.config.IssueBackendFactory(o.project.RepoDir())
+	if err != nil {
+		slog.Warn("failed to block issue", "project", o.project.Name, "issue", issueID, "error", err)
+		return false
+	}
+
+	ctx := context.Background()
+	iss, err := backend.Get(ctx, issueID)
+	if err != nil {
+		slog.Warn("failed to block issue", "project", o.project.Name, "issue", issueID, "error", err)
+		return false
+	}
+
+	status := issue.StatusBlocked
+	params := preservingUpdate(iss)
+	params.Status = &status
+	if !slices.Contains(params.Labels, AutoBlockedLabel) {
+		params.Labels = append(slices.Clone(iss.Labels), AutoBlockedLabel)
+	}
+	if _, err := backend.Update(ctx, issueID, params); err != nil {
+		slog.Warn("failed to block issue", "project", o.project.Name, "issue", issueID, "error", err)
+		return false
+	}
+
+	slog.Warn("blocked issue after repeated agent failures",
+		"project", o.project.Name,
+		"issue", issueID,
+		"failures", failures,
+	)
+
+	if collab, ok := backend.(issue.CollaborativeBackend); ok {
+		body := fmt.Sprintf("fab blocked this issue after %d consecutive agent failures. Last failure: %s\n\n"+
+			"Remove the %s label and set the issue back to open to let agents retry it.",
+			failures, reason, AutoBlockedLabel)
+		if err := collab.AddComment(ctx, issueID, body); err != nil {
+			slog.Debug("failed to comment on blocked issue", "issue", issueID, "error", err)
+		}
+	}
+	return true
+}
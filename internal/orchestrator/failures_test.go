@@ -0,0 +1,200 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/issue"
+	"github.com/tessro/fab/internal/project"
+)
+
+// blockBackend is a stub issue backend that records updates and comments.
+type blockBackend struct {
+	issue.CollaborativeBackend
+	issues    map[string]*issue.Issue
+	updateErr error
+	updates   map[string]issue.UpdateParams
+	comments  map[string][]string
+}
+
+func newBlockBackend(issues ...*issue.Issue) *blockBackend {
+	b := &blockBackend{
+		issues:   make(map[string]*issue.Issue),
+		updates:  make(map[string]issue.UpdateParams),
+		comments: make(map[string][]string),
+	}
+	for _, iss := range issues {
+		b.issues[iss.ID] = iss
+	}
+	return b
+}
+
+func (b *blockBackend) Get(ctx context.Context, id string) (*issue.Issue, error) {
+	iss, ok := b.issues[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return iss, nil
+}
+
+func (b *blockBackend) Ready(ctx context.Context) ([]*issue.Issue, error) {
+	var ready []*issue.Issue
+	for _, iss := range b.issues {
+		ready = append(ready, iss)
+	}
+	return ready, nil
+}
+
+func (b *blockBackend) Update(ctx context.Context, id string, params issue.UpdateParams) (*issue.Issue, error) {
+	if b.updateErr != nil {
+		return nil, b.updateErr
+	}
+	b.updates[id] = params
+	return b.issues[id], nil
+}
+
+func (b *blockBackend) AddComment(ctx context.Context, id string, body string) error {
+	b.comments[id] = append(b.comments[id], body)
+	return nil
+}
+
+func newFailureOrchestrator(backend issue.Backend, threshold int) *Orchestrator {
+	cfg := DefaultConfig()
+	cfg.FailureThreshold = threshold
+	cfg.IssueBackendFactory = func(string) (issue.Backend, error) { return backend, nil }
+	return New(&project.Project{Name: "test-project", MaxAgents: 3}, agent.NewManager(), cfg)
+}
+
+func TestOrchestrator_RecordIssueFailure_BlocksAtThreshold(t *testing.T) {
+	backend := newBlockBackend(&issue.Issue{ID: "1", Type: "bug", Priority: 1, Labels: []string{"backend"}})
+	orch := newFailureOrchestrator(backend, 2)
+
+	orch.RecordIssueFailure("1", "agent crashed")
+	if _, ok := backend.updates["1"]; ok {
+		t.Fatal("issue blocked after one failure, want threshold of 2")
+	}
+
+	orch.RecordIssueFailure("1", "merge failed: conflict")
+	params, ok := backend.updates["1"]
+	if !ok {
+		t.Fatal("issue not blocked after reaching the threshold")
+	}
+	if params.Status == nil || *params.Status != issue.StatusBlocked {
+		t.Errorf("Status = %v, want blocked", params.Status)
+	}
+	if !slices.Equal(params.Labels, []string{"backend", AutoBlockedLabel}) {
+		t.Errorf("Labels = %v, want existing labels plus %s", params.Labels, AutoBlockedLabel)
+	}
+	if params.Type == nil || *params.Type != "bug" || params.Priority == nil || *params.Priority != 1 {
+		t.Errorf("type and priority not preserved: %+v", params)
+	}
+	if len(backend.comments["1"]) != 1 || !strings.Contains(backend.comments["1"][0], "merge failed: conflict") {
+		t.Errorf("comments = %v, want one naming the last failure", backend.comments["1"])
+	}
+
+	stats := orch.IssueFailures()
+	if len(stats) != 1 || stats[0].IssueID != "1" || stats[0].Failures != 2 || !stats[0].Blocked {
+		t.Errorf("IssueFailures() = %+v, want issue 1 blocked after 2 failures", stats)
+	}
+
+	// Further failures don't block again
+	orch.RecordIssueFailure("1", "agent crashed")
+	if len(backend.comments["1"]) != 1 {
+		t.Errorf("issue blocked %d times, want once", len(backend.comments["1"]))
+	}
+}
+
+func TestOrchestrator_RecordIssueFailure_Window(t *testing.T) {
+	backend := newBlockBackend(&issue.Issue{ID: "1"})
+	orch := newFailureOrchestrator(backend, 2)
+
+	orch.RecordIssueFailure("1", "agent crashed")
+	orch.mu.Lock()
+	orch.failures["1"].first = time.Now().Add(-2 * DefaultFailureWindow)
+	orch.mu.Unlock()
+
+	orch.RecordIssueFailure("1", "agent crashed")
+	if _, ok := backend.updates["1"]; ok {
+		t.Error("failure outside the window counted toward the threshold")
+	}
+	if stats := orch.IssueFailures(); len(stats) != 1 || stats[0].Failures != 1 {
+		t.Errorf("IssueFailures() = %+v, want a new run of 1 failure", stats)
+	}
+}
+
+func TestOrchestrator_IssueFailures_ResetOnSuccess(t *testing.T) {
+	orch := newFailureOrchestrator(newBlockBackend(&issue.Issue{ID: "1"}), 3)
+
+	orch.RecordIssueFailure("1", "agent crashed")
+	orch.recordDoneOutcome([]string{"1"}, &AgentDoneResult{MergeError: "conflict\ndetails"})
+	if stats := orch.IssueFailures(); len(stats) != 1 || stats[0].Failures != 2 || stats[0].LastReason != "merge failed: conflict" {
+		t.Fatalf("IssueFailures() = %+v, want 2 failures ending in a merge failure", stats)
+	}
+
+	orch.recordDoneOutcome([]string{"1"}, &AgentDoneResult{Merged: true})
+	if stats := orch.IssueFailures(); len(stats) != 0 {
+		t.Errorf("IssueFailures() = %+v, want none after a merge", stats)
+	}
+}
+
+func TestOrchestrator_HandleAgentCrash(t *testing.T) {
+	orch := newFailureOrchestrator(newBlockBackend(&issue.Issue{ID: "1"}, &issue.Issue{ID: "2"}), 3)
+	_ = orch.Claims().Claim("1", "agent-a")
+	_ = orch.Claims().Claim("2", "agent-b")
+
+	if released := orch.HandleAgentCrash("agent-a", errors.New("exit status 1")); released != 1 {
+		t.Errorf("HandleAgentCrash() released %d claims, want 1", released)
+	}
+	if orch.Claims().IsClaimed("1") || !orch.Claims().IsClaimed("2") {
+		t.Error("only the crashed agent's claim should be released")
+	}
+	stats := orch.IssueFailures()
+	if len(stats) != 1 || stats[0].IssueID != "1" || !strings.Contains(stats[0].LastReason, "exit status 1") {
+		t.Errorf("IssueFailures() = %+v, want one crash on issue 1", stats)
+	}
+}
+
+func TestOrchestrator_BlockedIssuesSkipped(t *testing.T) {
+	t.Run("block not recorded", func(t *testing.T) {
+		backend := newBlockBackend(&issue.Issue{ID: "1"}, &issue.Issue{ID: "2"})
+		backend.updateErr = errors.New("api down")
+		orch := newFailureOrchestrator(backend, 1)
+		orch.RecordIssueFailure("1", "agent crashed")
+
+		if !orch.BlocksClaim("1") {
+			t.Error("BlocksClaim() = false, want true when the backend update failed")
+		}
+		ready, err := orch.unclaimedReadyIssues()
+		if err != nil {
+			t.Fatalf("unclaimedReadyIssues() error = %v", err)
+		}
+		if len(ready) != 1 || ready[0].ID != "2" {
+			t.Errorf("unclaimedReadyIssues() = %v, want only issue 2", ready)
+		}
+	})
+
+	t.Run("unblocked by hand", func(t *testing.T) {
+		orch := newFailureOrchestrator(newBlockBackend(&issue.Issue{ID: "1"}), 1)
+		orch.RecordIssueFailure("1", "agent crashed")
+		if orch.BlocksClaim("1") {
+			t.Error("BlocksClaim() = true, want the backend's block to govern claims")
+		}
+
+		// The stub's Ready still returns the issue, as if it were unblocked
+		ready, err := orch.unclaimedReadyIssues()
+		if err != nil {
+			t.Fatalf("unclaimedReadyIssues() error = %v", err)
+		}
+		if len(ready) != 1 {
+			t.Errorf("unclaimedReadyIssues() = %v, want the unblocked issue", ready)
+		}
+		if stats := orch.IssueFailures(); len(stats) != 0 {
+			t.Errorf("IssueFailures() = %+v, want the count cleared", stats)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package orchestrator
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/tessro/fab/internal/runtime"
+)
+
+// journalMergeBegin records the intent to merge an agent's branch.
+// Journal failures are logged but never block the merge.
+func (o *Orchestrator) journalMergeBegin(agentID, taskID string) {
+	journal := o.config.MergeJournal
+	if journal == nil {
+		return
+	}
+	err := journal.Begin(runtime.MergeIntent{
+		AgentID:   agentID,
+		Project:   o.project.Name,
+		TaskID:    taskID,
+		Branch:    "fab/" + agentID,
+		StartedAt: time.Now(),
+	})
+	if err != nil {
+		slog.Warn("failed to journal merge intent", "agent", agentID, "error", err)
+	}
+}
+
+// journalMergeTarget returns a callback for MergeAgentBranch that records
+// the SHA main is about to be moved to. Returns nil if journaling is disabled.
+func (o *Orchestrator) journalMergeTarget(agentID string) func(sha string) {
+	journal := o.config.MergeJournal
+	if journal == nil {
+		return nil
+	}
+	return func(sha string) {
+		if err := journal.SetTarget(agentID, sha); err != nil {
+			slog.Warn("failed to journal merge target", "agent", agentID, "sha", sha, "error", err)
+		}
+	}
+}
+
+// journalMergeComplete clears the agent's merge intent.
+func (o *Orchestrator) journalMergeComplete(agentID string) {
+	journal := o.config.MergeJournal
+	if journal == nil {
+		return
+	}
+	if err := journal.Complete(agentID); err != nil {
+		slog.Warn("failed to clear merge intent", "agent", agentID, "error", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,14 +15,17 @@ import (
 	"github.com/tessro/fab/internal/agent"
 	"github.com/tessro/fab/internal/issue"
 	"github.com/tessro/fab/internal/logging"
+	"github.com/tessro/fab/internal/metrics"
 	"github.com/tessro/fab/internal/project"
+	"github.com/tessro/fab/internal/rules"
+	"github.com/tessro/fab/internal/runtime"
 )
 
 // ErrAlreadyRunning is returned when attempting to start an already-running orchestrator.
 var ErrAlreadyRunning = errors.New("orchestrator already running")
 
 // Default polling interval for checking ready issues.
-const DefaultPollInterval = 10 * time.Second
+const DefaultPollInterval = project.DefaultPollInterval
 
 // Config configures orchestrator behavior.
 type Config struct {
@@ -36,6 +41,10 @@ type Config struct {
 	// Use this to set up output reading/broadcasting.
 	OnAgentStarted func(*agent.Agent)
 
+	// OnAgentChatEntry is called when the orchestrator adds a chat entry to an
+	// agent's history (e.g. pre-merge command output). Use this to broadcast it.
+	OnAgentChatEntry func(*agent.Agent, agent.ChatEntry)
+
 	// IssueBackendFactory creates an issue backend for checking ready issues.
 	// If nil, auto-spawning of agents is disabled.
 	IssueBackendFactory issue.NewBackendFunc
@@ -43,6 +52,23 @@ type Config struct {
 	// PollInterval is how often to check for ready issues.
 	// Defaults to DefaultPollInterval.
 	PollInterval time.Duration
+
+	// MergeJournal records in-flight direct merges so they can be recovered
+	// if the daemon dies mid-merge. If nil, merges are not journaled.
+	MergeJournal *runtime.MergeJournal
+
+	// OnDecision is called for each action skipped because the project is in
+	// dry-run mode. Use this to broadcast would_spawn/would_merge events.
+	OnDecision func(Decision)
+
+	// FailureThreshold is how many consecutive agent failures (crashes or
+	// failed merges) on an issue block it. Defaults to DefaultFailureThreshold.
+	FailureThreshold int
+
+	// FailureWindow is how long a run of failures counts toward
+	// FailureThreshold; a failure after it starts a new run.
+	// Defaults to DefaultFailureWindow.
+	FailureWindow time.Duration
 }
 
 // DefaultConfig returns the default orchestrator configuration.
@@ -56,6 +82,7 @@ Pick one and run 'fab agent claim <id>' to claim it.
 If already claimed, pick another from the list.
 If all tasks are claimed, run 'fab agent done' to finish your session.
 After claiming a task, run 'fab agent describe "<brief description>"' to set your status (e.g., "Implementing user auth feature").
+Then run 'fab notes' to read findings other agents shared about this project. When you learn something others would otherwise rediscover (a flaky test, a build quirk, where something lives), record it with 'fab notes add "<finding>"'.
 
 Read the issue carefully and decide how to proceed:
 
@@ -97,16 +124,58 @@ type Orchestrator struct {
 
 	// +checklocks:mu
 	running bool
+
+	// Agents spawned into reserved high-priority slots, mapped to the issue
+	// they were spawned for. Entries are pruned once the agent is gone.
+	// +checklocks:mu
+	priorityAgents map[string]string
+
+	// Outcomes of agent.done calls that merged or opened a PR, so a retried
+	// done returns the earlier result instead of merging again. doneOrder
+	// tracks insertion order for evicting the oldest entries.
+	// +checklocks:mu
+	doneResults map[doneKey]*AgentDoneResult
+	// +checklocks:mu
+	doneOrder []doneKey
+	// +checklocks:mu
+	doneInFlight map[doneKey]bool
+
+	// Recent agent failures per issue, for blocking issues that keep failing.
+	// Entries are cleared when an issue's work lands or it is unblocked.
+	// +checklocks:mu
+	failures map[string]*issueFailures
+
+	// Claim changes waiting to be reflected to the issue backend, applied
+	// in order by one goroutine while reflecting is set.
+	reflectMu sync.Mutex
+	// +checklocks:reflectMu
+	reflectQueue []ClaimChange
+	// +checklocks:reflectMu
+	reflecting bool
+
+	// Cached count of ready issues waiting for an agent (see Backlog).
+	backlogMu sync.Mutex
+	// +checklocks:backlogMu
+	backlogWaiting int
+	// +checklocks:backlogMu
+	backlogAt time.Time
 }
 
 // New creates a new Orchestrator for the given project.
 func New(proj *project.Project, agents *agent.Manager, cfg Config) *Orchestrator {
-	return &Orchestrator{
+	o := &Orchestrator{
 		project: proj,
 		config:  cfg,
 		agents:  agents,
 		claims:  NewClaimRegistry(),
+
+		priorityAgents: make(map[string]string),
+		doneResults:    make(map[doneKey]*AgentDoneResult),
+		doneInFlight:   make(map[doneKey]bool),
+		failures:       make(map[string]*issueFailures),
 	}
+	o.claims.OnChange(o.reflectClaimChange)
+	return o
 }
 
 // Claims returns the ticket claim registry.
@@ -220,18 +289,25 @@ func (o *Orchestrator) run() {
 
 // checkAndSpawnAgents checks for ready issues and spawns agents for them.
 // Only spawns agents when there are unclaimed ready issues available.
+// Normal slots (MaxAgents) are filled first; once they are full, reserved
+// slots are used for issues at or above the project's priority threshold.
+// With auto-claim, each agent is spawned with a ready issue already claimed
+// for it instead of picking one itself.
 func (o *Orchestrator) checkAndSpawnAgents() {
 	proj := o.project
 
-	// Check how many agent slots are available
+	// Agents in reserved slots don't count against the normal lane
 	current := o.agents.CountByProject(proj.Name)
-	available := proj.MaxAgents - current
-	if available <= 0 {
+	priorityActive := o.priorityAgentCount()
+	normalActive := current - priorityActive
+	available := proj.MaxAgents - normalActive
+	reservedFree := proj.ReservedSlots - priorityActive
+	if available <= 0 && reservedFree <= 0 {
 		return
 	}
 
 	// Check for ready issues (issues with no open dependencies)
-	readyCount, err := o.countUnclaimedReadyIssues()
+	readyIssues, err := o.unclaimedReadyIssues()
 	if err != nil {
 		slog.Debug("failed to check ready issues",
 			"project", proj.Name,
@@ -239,6 +315,17 @@ func (o *Orchestrator) checkAndSpawnAgents() {
 		)
 		return
 	}
+	readyCount := len(readyIssues)
+
+	if proj.DryRun {
+		o.reportDryRunSpawns(readyIssues, normalActive, available, reservedFree)
+		return
+	}
+
+	if available <= 0 {
+		o.spawnPriorityAgents(readyIssues, reservedFree)
+		return
+	}
 
 	// Don't spawn more agents than ready issues
 	toSpawn := available
@@ -260,7 +347,19 @@ func (o *Orchestrator) checkAndSpawnAgents() {
 
 	// Spawn the agents
 	for i := 0; i < toSpawn; i++ {
-		if err := o.spawnAgent(); err != nil {
+		var err error
+		if proj.AutoClaim {
+			iss := readyIssues[i]
+			prompt := fmt.Sprintf("Issue %s (%q) is already claimed for you. Skip 'fab issue ready' and 'fab agent claim' and work on it directly.\n\n%s",
+				iss.ID, iss.Title, o.config.KickstartPrompt)
+			_, err = o.spawnAgent(prompt, proj.ModelForPriority(iss.Priority), RoleForIssue(iss), iss, false)
+			if errors.Is(err, ErrAlreadyClaimed) {
+				continue // Claimed since the ready check
+			}
+		} else {
+			_, err = o.spawnAgent(o.config.KickstartPrompt, proj.Model, "", nil, false)
+		}
+		if err != nil {
 			slog.Debug("failed to spawn agent",
 				"project", proj.Name,
 				"error", err,
@@ -270,45 +369,218 @@ func (o *Orchestrator) checkAndSpawnAgents() {
 	}
 }
 
-// countUnclaimedReadyIssues returns the count of ready issues that aren't already claimed.
-func (o *Orchestrator) countUnclaimedReadyIssues() (int, error) {
+// spawnPriorityAgents spawns agents into free reserved slots, one per
+// high-priority issue that doesn't already have a priority agent.
+// Each agent is told which issue to claim so urgent work isn't picked up late.
+func (o *Orchestrator) spawnPriorityAgents(readyIssues []*issue.Issue, reservedFree int) {
+	proj := o.project
+	urgent := o.urgentIssues(readyIssues)
+
+	for i := 0; i < len(urgent) && i < reservedFree; i++ {
+		iss := urgent[i]
+		slog.Info("spawning agent in reserved high-priority slot",
+			"project", proj.Name,
+			"issue", iss.ID,
+			"priority", iss.Priority,
+			"max_agents", proj.MaxAgents,
+			"reserved_slots", proj.ReservedSlots,
+		)
+
+		prompt := fmt.Sprintf("URGENT: issue %s (%q) is high priority. Claim it first with 'fab agent claim %s' and work on it before anything else.\n\n%s",
+			iss.ID, iss.Title, iss.ID, o.config.KickstartPrompt)
+		var claimed *issue.Issue
+		if proj.AutoClaim {
+			prompt = fmt.Sprintf("URGENT: issue %s (%q) is high priority and already claimed for you. Skip 'fab issue ready' and 'fab agent claim' and work on it before anything else.\n\n%s",
+				iss.ID, iss.Title, o.config.KickstartPrompt)
+			claimed = iss
+		}
+		a, err := o.spawnAgent(prompt, proj.ModelForPriority(iss.Priority), RoleForIssue(iss), claimed, true)
+		if errors.Is(err, ErrAlreadyClaimed) {
+			continue // Claimed since the ready check
+		}
+		if err != nil {
+			slog.Debug("failed to spawn priority agent",
+				"project", proj.Name,
+				"issue", iss.ID,
+				"error", err,
+			)
+			return
+		}
+
+		o.mu.Lock()
+		o.priorityAgents[a.ID] = iss.ID
+		o.mu.Unlock()
+	}
+}
+
+// urgentIssues returns the ready issues at or above the project's priority
+// threshold that don't already have a priority agent, highest priority first.
+func (o *Orchestrator) urgentIssues(readyIssues []*issue.Issue) []*issue.Issue {
+	threshold := o.project.GetPriorityThreshold()
+
+	o.mu.RLock()
+	targeted := make(map[string]bool, len(o.priorityAgents))
+	for _, issueID := range o.priorityAgents {
+		targeted[issueID] = true
+	}
+	o.mu.RUnlock()
+
+	var urgent []*issue.Issue
+	for _, iss := range readyIssues {
+		if iss.Priority >= threshold && !targeted[iss.ID] {
+			urgent = append(urgent, iss)
+		}
+	}
+	sort.SliceStable(urgent, func(i, j int) bool {
+		return urgent[i].Priority > urgent[j].Priority
+	})
+	return urgent
+}
+
+// priorityAgentCount returns how many agents occupy reserved slots,
+// forgetting agents that have since been deleted.
+func (o *Orchestrator) priorityAgentCount() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for agentID := range o.priorityAgents {
+		if _, err := o.agents.Get(agentID); err != nil {
+			delete(o.priorityAgents, agentID)
+		}
+	}
+	return len(o.priorityAgents)
+}
+
+// unclaimedReadyIssues returns ready issues that aren't already claimed.
+func (o *Orchestrator) unclaimedReadyIssues() ([]*issue.Issue, error) {
 	if o.config.IssueBackendFactory == nil {
-		// No issue backend configured, return 0 (no auto-spawning)
-		return 0, nil
+		// No issue backend configured, return nothing (no auto-spawning)
+		return nil, nil
 	}
 
 	backend, err := o.config.IssueBackendFactory(o.project.RepoDir())
 	if err != nil {
-		return 0, fmt.Errorf("create issue backend: %w", err)
+		return nil, fmt.Errorf("create issue backend: %w", err)
 	}
 
 	ctx := context.Background()
 	readyIssues, err := backend.Ready(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("get ready issues: %w", err)
+		return nil, fmt.Errorf("get ready issues: %w", err)
 	}
 
-	// Count issues that aren't already claimed
-	unclaimed := 0
-	for _, iss := range readyIssues {
+	// Keep issues that aren't already claimed or blocked after failures
+	var unclaimed []*issue.Issue
+	for _, iss := range o.filterBlockedIssues(readyIssues) {
 		if !o.claims.IsClaimed(iss.ID) {
-			unclaimed++
+			unclaimed = append(unclaimed, iss)
 		}
 	}
 
 	return unclaimed, nil
 }
 
-// spawnAgent creates and starts a single agent.
-func (o *Orchestrator) spawnAgent() error {
-	a, err := o.agents.Create(o.project)
+// invalidateIssueCache drops cached issue queries, so a ticket the agent just
+// finished isn't handed out again from a stale Ready result once its claim is
+// released.
+func (o *Orchestrator) invalidateIssueCache() {
+	if o.config.IssueBackendFactory == nil {
+		return
+	}
+	backend, err := o.config.IssueBackendFactory(o.project.RepoDir())
+	if err != nil {
+		return
+	}
+	if cached, ok := backend.(*issue.CachedBackend); ok {
+		cached.Invalidate()
+	}
+}
+
+// ReviewIssueType is the issue type (the "type:review" label) of tickets
+// that should be worked by reviewer agents.
+const ReviewIssueType = "review"
+
+// RoleForIssue returns the agent role for working iss: reviewer for review
+// tickets, otherwise the unrestricted empty role.
+func RoleForIssue(iss *issue.Issue) string {
+	if iss.Type == ReviewIssueType {
+		return rules.RoleReviewer
+	}
+	return ""
+}
+
+// AreaLabelPrefix marks issue labels that scope the agent working the issue
+// to a subdirectory of the repository (e.g., "area:backend").
+const AreaLabelPrefix = "area:"
+
+// SubdirForIssue returns the worktree subdirectory for working iss, from its
+// first "area:<dir>" label, or "" for the worktree root.
+func SubdirForIssue(iss *issue.Issue) string {
+	for _, label := range iss.Labels {
+		if dir, ok := strings.CutPrefix(label, AreaLabelPrefix); ok && dir != "" {
+			return dir
+		}
+	}
+	return ""
+}
+
+// setIssueSubdir scopes a to subdir, derived from the area label of the
+// issue it was spawned for. An area with no matching directory is ignored
+// so the agent still runs, from the worktree root.
+func setIssueSubdir(a *agent.Agent, issueID, subdir string) {
+	if subdir == "" {
+		return
+	}
+	if err := a.SetSubdir(subdir); err != nil {
+		slog.Warn("ignoring issue area", "agent", a.ID, "issue", issueID, "error", err)
+	}
+}
+
+// spawnAgent creates and starts a single agent running model in role,
+// kickstarting it with prompt. An empty model leaves the choice to the agent
+// CLI. If claimed is set, the issue is reserved before the agent is created
+// and handed to it before it starts, so no other agent can claim it in
+// between; ErrAlreadyClaimed is returned if it is already taken. priority
+// puts the agent in a reserved high-priority slot.
+func (o *Orchestrator) spawnAgent(prompt, model, role string, claimed *issue.Issue, priority bool) (*agent.Agent, error) {
+	var placeholder string
+	if claimed != nil {
+		var err error
+		if placeholder, err = o.claims.Reserve(claimed.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	a, err := o.agents.Create(o.project, agent.SpawnedByOrchestrator, priority)
 	if err != nil {
-		return err
+		if claimed != nil {
+			o.claims.Release(claimed.ID)
+		}
+		return nil, err
+	}
+	a.SetModel(model)
+	a.SetRole(role)
+
+	if claimed != nil {
+		if err := o.claims.Transfer(claimed.ID, placeholder, a.ID); err != nil {
+			_ = o.agents.Delete(a.ID)
+			return nil, err
+		}
+		a.SetTask(claimed.ID)
+		setIssueSubdir(a, claimed.ID, SubdirForIssue(claimed))
+		if o.project.GetAgentNaming() == agent.NamingIssue {
+			if name := agent.NameFromTitle(claimed.Title); name != "" {
+				a.SetName(name)
+			}
+		}
 	}
 
 	// Start the agent process immediately (without prompt)
 	if err := a.Start(""); err != nil {
-		return fmt.Errorf("start agent process: %w", err)
+		if claimed != nil {
+			o.claims.Release(claimed.ID)
+		}
+		return nil, fmt.Errorf("start agent process: %w", err)
 	}
 
 	// Notify that the agent has started (for read loop setup)
@@ -317,9 +589,90 @@ func (o *Orchestrator) spawnAgent() error {
 	}
 
 	// Execute kickstart immediately
-	o.executeKickstart(a, o.config.KickstartPrompt)
+	o.executeKickstart(a, prompt)
 
-	return nil
+	return a, nil
+}
+
+// Issue fetches a ticket from the project's issue backend.
+func (o *Orchestrator) Issue(ctx context.Context, id string) (*issue.Issue, error) {
+	if o.config.IssueBackendFactory == nil {
+		return nil, errors.New("no issue backend configured")
+	}
+	backend, err := o.config.IssueBackendFactory(o.project.RepoDir())
+	if err != nil {
+		return nil, fmt.Errorf("create issue backend: %w", err)
+	}
+	return backend.Get(ctx, id)
+}
+
+// Delegate spawns an agent for a specific ticket on behalf of delegatedBy
+// (e.g., a manager). The ticket is reserved before the issue is fetched and
+// claimed for the new agent before it starts, so the agent goes straight to
+// work on it and no other agent can claim it in between.
+func (o *Orchestrator) Delegate(ticketID, delegatedBy string) (_ *agent.Agent, err error) {
+	placeholder, err := o.claims.Reserve(ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket %s is already claimed by agent %s", ticketID, o.claims.ClaimedBy(ticketID))
+	}
+	defer func() {
+		if err != nil {
+			o.claims.Release(ticketID)
+		}
+	}()
+
+	title := ""
+	role := ""
+	subdir := ""
+	model := o.project.Model
+	if o.config.IssueBackendFactory != nil {
+		backend, err := o.config.IssueBackendFactory(o.project.RepoDir())
+		if err != nil {
+			return nil, fmt.Errorf("create issue backend: %w", err)
+		}
+		iss, err := backend.Get(context.Background(), ticketID)
+		if err != nil {
+			return nil, fmt.Errorf("get issue %s: %w", ticketID, err)
+		}
+		title = iss.Title
+		role = RoleForIssue(iss)
+		subdir = SubdirForIssue(iss)
+		model = o.project.ModelForPriority(iss.Priority)
+	}
+
+	a, err := o.agents.Create(o.project, agent.SpawnerFromDelegator(delegatedBy), false)
+	if err != nil {
+		return nil, err
+	}
+	a.SetModel(model)
+	a.SetRole(role)
+	setIssueSubdir(a, ticketID, subdir)
+	if o.project.GetAgentNaming() == agent.NamingIssue {
+		if name := agent.NameFromTitle(title); name != "" {
+			a.SetName(name)
+		}
+	}
+
+	if err := o.claims.Transfer(ticketID, placeholder, a.ID); err != nil {
+		_ = o.agents.Delete(a.ID)
+		return nil, err
+	}
+	a.SetTask(ticketID)
+
+	if err := a.Start(""); err != nil {
+		_ = o.agents.Delete(a.ID)
+		return nil, fmt.Errorf("start agent process: %w", err)
+	}
+
+	if o.config.OnAgentStarted != nil {
+		o.config.OnAgentStarted(a)
+	}
+
+	prompt := fmt.Sprintf("Issue %s (%q) has been delegated to you by %s and is already claimed for you. Skip 'fab issue ready' and 'fab agent claim' and work on it directly.\n\n%s",
+		ticketID, title, delegatedBy, o.config.KickstartPrompt)
+	o.executeKickstart(a, prompt)
+
+	return a, nil
 }
 
 // ExecuteKickstart executes the kickstart action immediately.
@@ -357,12 +710,19 @@ func (o *Orchestrator) executeKickstart(a *agent.Agent, prompt string) {
 
 // AgentDoneResult contains the outcome of HandleAgentDone.
 type AgentDoneResult struct {
-	Merged     bool   // True if merge to main succeeded (only for direct merge strategy)
-	BranchName string // The branch that was processed
-	SHA        string // Commit SHA of merge commit (only set if Merged is true)
-	MergeError string // Conflict message if merge failed
-	PRCreated  bool   // True if PR was created (only for pull-request strategy)
-	PRURL      string // URL of created PR (only if PRCreated is true)
+	Merged        bool     // True if merge to main succeeded (only for direct merge strategy)
+	BranchName    string   // The branch that was processed
+	SHA           string   // Commit SHA of merge commit (only set if Merged is true)
+	MergeError    string   // Conflict message if merge failed
+	ConflictFiles []string // Files that conflicted during rebase (only set with MergeError)
+	CheckFailed   bool     // True if the pre-merge command failed (MergeError holds its output)
+	PRCreated     bool     // True if PR was created (only for pull-request strategy)
+	PRURL         string   // URL of created PR (only if PRCreated is true)
+	DryRun        bool     // True if the project is in dry-run mode and nothing was merged
+	Duplicate     bool     // True if this done was already handled and the earlier result is returned
+
+	// Conflicts holds the conflict hunks of ConflictFiles
+	Conflicts []project.ConflictFile
 }
 
 // HandleAgentDone handles an agent signaling task completion.
@@ -370,7 +730,41 @@ type AgentDoneResult struct {
 // - "direct": merges to main, cleans up agent, spawns replacement
 // - "pull-request": creates a PR, keeps worktree until PR is merged
 // If merge/PR fails, rebases the worktree and returns error (agent stays running to fix conflicts).
+// If the project has a pre-merge command, it must pass before either strategy runs.
+// In dry-run mode nothing is merged; the decision is reported instead.
+//
+// Calls are idempotent per (agentID, taskID): once a done has merged or
+// opened a PR, repeating it returns the earlier result with Duplicate set,
+// and a repeat while the first is still running fails with ErrDoneInProgress.
+//
+// A failed merge or pre-merge check counts as a failure of the agent's
+// issues (see RecordIssueFailure); landed work clears their failure counts.
 func (o *Orchestrator) HandleAgentDone(agentID, taskID, errorMsg string) (*AgentDoneResult, error) {
+	key := doneKey{agentID: agentID, taskID: taskID}
+	if prior, err := o.beginDone(key); err != nil || prior != nil {
+		if prior != nil {
+			slog.Info("agent done already handled, returning prior result", "agent", agentID, "task", taskID)
+		}
+		return prior, err
+	}
+	issueIDs := o.doneIssues(agentID, taskID) // Before a merge releases the claims
+	result, err := o.handleAgentDone(agentID, taskID)
+	o.finishDone(key, result)
+	o.recordDoneOutcome(issueIDs, result)
+	return result, err
+}
+
+// handleAgentDone gates and lands the agent's work for HandleAgentDone.
+func (o *Orchestrator) handleAgentDone(agentID, taskID string) (*AgentDoneResult, error) {
+	if o.project.DryRun {
+		return o.reportDryRunMerge(agentID, taskID), nil
+	}
+
+	// Gate the merge on the pre-merge command (e.g. the test suite)
+	if blocked, err := o.runPreMergeGate(agentID); err != nil || blocked != nil {
+		return blocked, err
+	}
+
 	// Check merge strategy
 	mergeStrategy := o.project.GetMergeStrategy()
 
@@ -387,8 +781,21 @@ func (o *Orchestrator) HandleAgentDone(agentID, taskID, errorMsg string) (*Agent
 func (o *Orchestrator) handleAgentDoneMerge(agentID, taskID string) (*AgentDoneResult, error) {
 	result := &AgentDoneResult{}
 
+	// Journal the merge before touching git. The entry is cleared on every
+	// return path; one left behind means the daemon died mid-merge.
+	o.journalMergeBegin(agentID, taskID)
+	defer o.journalMergeComplete(agentID)
+	onRebased := o.journalMergeTarget(agentID)
+
 	// Try to merge agent's branch into main
-	mergeResult, err := o.project.MergeAgentBranch(agentID)
+	mergeResult, err := o.project.MergeAgentBranch(agentID, onRebased)
+	if err != nil && o.project.GetAutoRebase() {
+		// A fetch or push failure usually means another agent pushed between
+		// our fetch and push. The retry fetches and rebases again. A rebase
+		// conflict isn't retried: the same rebase would conflict again.
+		slog.Info("merge failed, retrying", "agent", agentID, "error", err)
+		mergeResult, err = o.project.MergeAgentBranch(agentID, onRebased)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("merge attempt: %w", err)
 	}
@@ -400,6 +807,7 @@ func (o *Orchestrator) handleAgentDoneMerge(agentID, taskID string) (*AgentDoneR
 		result.Merged = true
 		result.SHA = mergeResult.SHA
 		slog.Info("merged agent branch to main", "agent", agentID, "branch", mergeResult.BranchName, "sha", mergeResult.SHA)
+		metrics.Inc(metrics.CommitsMerged, metrics.Label{Name: "project", Value: o.project.Name})
 
 		_ = o.agents.Stop(agentID)
 		if err := o.agents.Delete(agentID); err != nil {
@@ -411,6 +819,7 @@ func (o *Orchestrator) handleAgentDoneMerge(agentID, taskID string) (*AgentDoneR
 		if released > 0 {
 			slog.Debug("released ticket claims after merge", "agent", agentID, "count", released)
 		}
+		o.invalidateIssueCache()
 
 		// Check for new issues and spawn agents as needed
 		o.checkAndSpawnAgents()
@@ -418,6 +827,8 @@ func (o *Orchestrator) handleAgentDoneMerge(agentID, taskID string) (*AgentDoneR
 		// Merge conflict - rebase worktree onto latest main
 		// Do NOT release claims - agent must fix conflicts
 		result.MergeError = mergeResult.Error.Error()
+		result.ConflictFiles = mergeResult.ConflictFiles
+		result.Conflicts = mergeResult.Conflicts
 
 		if err := o.project.RebaseWorktreeOnMain(agentID); err != nil {
 			slog.Warn("failed to rebase worktree after merge conflict", "agent", agentID, "error", err)
@@ -426,6 +837,7 @@ func (o *Orchestrator) handleAgentDoneMerge(agentID, taskID string) (*AgentDoneR
 		slog.Warn("merge conflict, agent must resolve",
 			"agent", agentID,
 			"branch", mergeResult.BranchName,
+			"files", mergeResult.ConflictFiles,
 			"error", mergeResult.Error)
 	}
 
@@ -478,12 +890,15 @@ func (o *Orchestrator) handleAgentDonePR(agentID, taskID string) (*AgentDoneResu
 		if released > 0 {
 			slog.Debug("released ticket claims after PR creation", "agent", agentID, "count", released)
 		}
+		o.invalidateIssueCache()
 
 		// Check for new issues and spawn agents as needed
 		o.checkAndSpawnAgents()
 	} else {
 		// Rebase conflict - agent must fix conflicts
 		result.MergeError = prResult.Error.Error()
+		result.ConflictFiles = prResult.ConflictFiles
+		result.Conflicts = prResult.Conflicts
 
 		if err := o.project.RebaseWorktreeOnMain(agentID); err != nil {
 			slog.Warn("failed to rebase worktree after conflict", "agent", agentID, "error", err)
@@ -492,6 +907,7 @@ func (o *Orchestrator) handleAgentDonePR(agentID, taskID string) (*AgentDoneResu
 		slog.Warn("rebase conflict, agent must resolve",
 			"agent", agentID,
 			"branch", prResult.BranchName,
+			"files", prResult.ConflictFiles,
 			"error", prResult.Error)
 	}
 
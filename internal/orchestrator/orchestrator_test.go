@@ -1,12 +1,20 @@
 package orchestrator
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/issue"
 	"github.com/tessro/fab/internal/project"
+	"github.com/tessro/fab/internal/rules"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -137,6 +145,7 @@ func TestOrchestrator_KickstartPromptContent(t *testing.T) {
 		"fab agent done",
 		"fab issue close",
 		"local worktree (unmerged)",
+		"fab notes",
 	}
 
 	for _, phrase := range expectedPhrases {
@@ -224,7 +233,7 @@ func TestOrchestrator_IsAgentIntervening(t *testing.T) {
 	orch := New(proj, agents, cfg)
 
 	// Create a real agent through manager
-	a, err := agents.Create(proj)
+	a, err := agents.Create(proj, agent.SpawnedByUser, false)
 	if err != nil {
 		t.Skipf("skipping test: could not create agent: %v", err)
 	}
@@ -258,3 +267,419 @@ func mockAgent(id, projectName string) *agent.Agent {
 	a := agent.New(id, proj, nil)
 	return a
 }
+
+// readyBackend is a stub issue backend that only implements Ready.
+type readyBackend struct {
+	issue.Backend
+	issues []*issue.Issue
+	calls  int
+}
+
+func (b *readyBackend) Ready(ctx context.Context) ([]*issue.Issue, error) {
+	b.calls++
+	return b.issues, nil
+}
+
+func TestOrchestrator_UnclaimedReadyIssues(t *testing.T) {
+	backend := &readyBackend{issues: []*issue.Issue{
+		{ID: "1", Priority: 0},
+		{ID: "2", Priority: 2},
+		{ID: "3", Priority: 1},
+	}}
+	cfg := DefaultConfig()
+	cfg.IssueBackendFactory = func(string) (issue.Backend, error) { return backend, nil }
+
+	orch := New(&project.Project{Name: "test-project", MaxAgents: 3}, agent.NewManager(), cfg)
+	if err := orch.Claims().Claim("2", "agent-a"); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+
+	ready, err := orch.unclaimedReadyIssues()
+	if err != nil {
+		t.Fatalf("unclaimedReadyIssues() error = %v", err)
+	}
+	if len(ready) != 2 || ready[0].ID != "1" || ready[1].ID != "3" {
+		t.Errorf("unclaimedReadyIssues() = %v, want issues 1 and 3", ready)
+	}
+}
+
+func TestOrchestrator_CheckAndSpawnAgents_NoSlots(t *testing.T) {
+	backend := &readyBackend{issues: []*issue.Issue{{ID: "1", Priority: 2}}}
+	cfg := DefaultConfig()
+	cfg.IssueBackendFactory = func(string) (issue.Backend, error) { return backend, nil }
+
+	// No normal or reserved slots: the backend should not even be queried
+	orch := New(&project.Project{Name: "test-project", MaxAgents: 0}, agent.NewManager(), cfg)
+	orch.checkAndSpawnAgents()
+
+	if backend.calls != 0 {
+		t.Errorf("Ready() called %d times, want 0", backend.calls)
+	}
+}
+
+func TestOrchestrator_Backlog(t *testing.T) {
+	backend := &readyBackend{issues: []*issue.Issue{{ID: "1"}, {ID: "2"}, {ID: "3"}}}
+	cfg := DefaultConfig()
+	cfg.IssueBackendFactory = func(string) (issue.Backend, error) { return backend, nil }
+
+	proj := &project.Project{Name: "test-project", MaxAgents: 0}
+	orch := New(proj, agent.NewManager(), cfg)
+	if err := orch.Claims().Claim("2", "agent-a"); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+
+	backlog, err := orch.Backlog()
+	if err != nil {
+		t.Fatalf("Backlog() error = %v", err)
+	}
+	if backlog.Waiting != 2 || !backlog.AtCapacity {
+		t.Errorf("Backlog() = %+v, want 2 waiting at capacity", backlog)
+	}
+
+	// The count is cached, but capacity is not
+	proj.MaxAgents = 1
+	backlog, _ = orch.Backlog()
+	if backlog.Waiting != 2 || backlog.AtCapacity || backend.calls != 1 {
+		t.Errorf("Backlog() = %+v after %d queries, want 2 waiting below capacity from 1 query", backlog, backend.calls)
+	}
+}
+
+func TestOrchestrator_PriorityAgentCount_PrunesMissingAgents(t *testing.T) {
+	orch := New(&project.Project{Name: "test-project", MaxAgents: 1, ReservedSlots: 1}, agent.NewManager(), DefaultConfig())
+
+	orch.mu.Lock()
+	orch.priorityAgents["gone"] = "issue-1"
+	orch.mu.Unlock()
+
+	if got := orch.priorityAgentCount(); got != 0 {
+		t.Errorf("priorityAgentCount() = %d, want 0 after pruning", got)
+	}
+}
+
+// getBackend is a stub issue backend that only implements Get.
+type getBackend struct {
+	issue.Backend
+	issues map[string]*issue.Issue
+}
+
+func (b *getBackend) Get(ctx context.Context, id string) (*issue.Issue, error) {
+	if iss, ok := b.issues[id]; ok {
+		return iss, nil
+	}
+	return nil, fmt.Errorf("issue not found: %s", id)
+}
+
+func TestOrchestrator_Delegate_Errors(t *testing.T) {
+	backend := &getBackend{issues: map[string]*issue.Issue{"1": {ID: "1", Title: "Fix it"}}}
+	cfg := DefaultConfig()
+	cfg.IssueBackendFactory = func(string) (issue.Backend, error) { return backend, nil }
+
+	agents := agent.NewManager()
+	orch := New(&project.Project{Name: "test-project", MaxAgents: 3}, agents, cfg)
+	if err := orch.Claims().Claim("1", "agent-a"); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+
+	_, err := orch.Delegate("1", "manager:test-project")
+	if err == nil || !strings.Contains(err.Error(), "already claimed by agent agent-a") {
+		t.Errorf("Delegate() on claimed ticket error = %v", err)
+	}
+
+	if _, err := orch.Delegate("missing", "manager:test-project"); err == nil {
+		t.Error("expected error delegating a nonexistent issue")
+	}
+
+	if n := agents.Count(); n != 0 {
+		t.Errorf("expected no agents created, got %d", n)
+	}
+}
+
+func TestRoleForIssue(t *testing.T) {
+	if got := RoleForIssue(&issue.Issue{Type: ReviewIssueType}); got != rules.RoleReviewer {
+		t.Errorf("RoleForIssue(review) = %q, want %q", got, rules.RoleReviewer)
+	}
+	if got := RoleForIssue(&issue.Issue{Type: "bug"}); got != "" {
+		t.Errorf("RoleForIssue(bug) = %q, want no role", got)
+	}
+}
+
+func TestSubdirForIssue(t *testing.T) {
+	if got := SubdirForIssue(&issue.Issue{Labels: []string{"bug", "area:services/api", "area:web"}}); got != "services/api" {
+		t.Errorf("SubdirForIssue() = %q, want the first area", got)
+	}
+	if got := SubdirForIssue(&issue.Issue{Labels: []string{"bug", "area:"}}); got != "" {
+		t.Errorf("SubdirForIssue() = %q, want no subdir", got)
+	}
+}
+
+func TestOrchestrator_DryRun_ReportsInsteadOfSpawning(t *testing.T) {
+	backend := &readyBackend{issues: []*issue.Issue{
+		{ID: "1", Priority: 0},
+		{ID: "2", Priority: 0},
+		{ID: "3", Priority: 0},
+	}}
+	var decisions []Decision
+	cfg := DefaultConfig()
+	cfg.IssueBackendFactory = func(string) (issue.Backend, error) { return backend, nil }
+	cfg.OnDecision = func(d Decision) { decisions = append(decisions, d) }
+
+	agents := agent.NewManager()
+	orch := New(&project.Project{Name: "test-project", MaxAgents: 2, DryRun: true}, agents, cfg)
+	orch.checkAndSpawnAgents()
+
+	if n := agents.CountByProject("test-project"); n != 0 {
+		t.Errorf("CountByProject() = %d, want 0 in dry-run mode", n)
+	}
+	if len(decisions) != 2 {
+		t.Fatalf("got %d decisions, want 2 (one per free slot): %+v", len(decisions), decisions)
+	}
+	for i, want := range []string{"1", "2"} {
+		d := decisions[i]
+		if d.Type != DecisionWouldSpawn || d.IssueID != want || d.Project != "test-project" || d.Reason == "" {
+			t.Errorf("decisions[%d] = %+v, want would_spawn for issue %s with a reason", i, d, want)
+		}
+	}
+}
+
+func TestOrchestrator_DryRun_ReservedSlots(t *testing.T) {
+	backend := &readyBackend{issues: []*issue.Issue{
+		{ID: "low", Priority: 0},
+		{ID: "high", Priority: 3},
+	}}
+	var decisions []Decision
+	cfg := DefaultConfig()
+	cfg.IssueBackendFactory = func(string) (issue.Backend, error) { return backend, nil }
+	cfg.OnDecision = func(d Decision) { decisions = append(decisions, d) }
+
+	// No normal slots: only the high-priority issue qualifies for the reserved slot
+	orch := New(&project.Project{Name: "test-project", MaxAgents: 0, ReservedSlots: 1, DryRun: true}, agent.NewManager(), cfg)
+	orch.checkAndSpawnAgents()
+
+	if len(decisions) != 1 || decisions[0].IssueID != "high" {
+		t.Errorf("decisions = %+v, want a single would_spawn for issue high", decisions)
+	}
+}
+
+func TestOrchestrator_DryRun_HandleAgentDone(t *testing.T) {
+	var decisions []Decision
+	cfg := DefaultConfig()
+	cfg.OnDecision = func(d Decision) { decisions = append(decisions, d) }
+
+	orch := New(&project.Project{Name: "test-project", MaxAgents: 1, DryRun: true}, agent.NewManager(), cfg)
+	result, err := orch.HandleAgentDone("abc123", "42", "")
+	if err != nil {
+		t.Fatalf("HandleAgentDone() error = %v", err)
+	}
+	if !result.DryRun || result.Merged || result.BranchName != "fab/abc123" {
+		t.Errorf("HandleAgentDone() = %+v, want an unmerged dry-run result for fab/abc123", result)
+	}
+	if len(decisions) != 1 || decisions[0].Type != DecisionWouldMerge || decisions[0].AgentID != "abc123" || decisions[0].IssueID != "42" {
+		t.Errorf("decisions = %+v, want a single would_merge for agent abc123", decisions)
+	}
+}
+
+func TestOrchestrator_HandleAgentDone_Idempotent(t *testing.T) {
+	orch := New(&project.Project{Name: "test-project", MaxAgents: 1}, agent.NewManager(), DefaultConfig())
+	key := doneKey{agentID: "abc123", taskID: "42"}
+
+	// A second done while the first is running is rejected
+	if _, err := orch.beginDone(key); err != nil {
+		t.Fatalf("beginDone() error = %v", err)
+	}
+	if _, err := orch.HandleAgentDone("abc123", "42", ""); !errors.Is(err, ErrDoneInProgress) {
+		t.Errorf("HandleAgentDone() during first call error = %v, want ErrDoneInProgress", err)
+	}
+	orch.finishDone(key, &AgentDoneResult{Merged: true, BranchName: "fab/abc123", SHA: "deadbeef"})
+
+	// Once merged, repeats return the recorded result without merging again
+	result, err := orch.HandleAgentDone("abc123", "42", "")
+	if err != nil {
+		t.Fatalf("HandleAgentDone() error = %v", err)
+	}
+	if !result.Duplicate || !result.Merged || result.SHA != "deadbeef" {
+		t.Errorf("HandleAgentDone() = %+v, want the prior merged result marked Duplicate", result)
+	}
+	if prior, ok := orch.PriorDoneResult("abc123", "42"); !ok || prior.SHA != "deadbeef" {
+		t.Errorf("PriorDoneResult() = %+v, %v; want the prior merged result", prior, ok)
+	}
+
+	// A different task for the same agent is not deduplicated
+	if _, ok := orch.PriorDoneResult("abc123", "43"); ok {
+		t.Error("PriorDoneResult() found a result for a different task")
+	}
+}
+
+// git runs a git command in dir and returns its trimmed output.
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// commitFile writes content to name in dir and commits it, returning the new HEAD.
+func commitFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, dir, "add", name)
+	git(t, dir, "commit", "-m", "Update "+name)
+	return git(t, dir, "rev-parse", "HEAD")
+}
+
+// setupMergeOrchestrator clones a fresh remote into a project and creates
+// an agent with a worktree and one commit to merge. Returns the
+// orchestrator, the agent ID, and the remote path.
+func setupMergeOrchestrator(t *testing.T) (*Orchestrator, string, string) {
+	t.Helper()
+	base := t.TempDir()
+	remote := filepath.Join(base, "remote.git")
+	git(t, base, "init", "--bare", "-b", "main", remote)
+
+	proj := project.NewProject("test", "file://"+remote)
+	proj.BaseDir = filepath.Join(base, "projects")
+	repo := proj.RepoDir()
+	seed := filepath.Join(base, "seed")
+	git(t, base, "init", "-b", "main", seed)
+	git(t, seed, "config", "user.email", "test@example.com")
+	git(t, seed, "config", "user.name", "Test User")
+	commitFile(t, seed, "README.md", "# Test\n")
+	git(t, seed, "push", remote, "main")
+	git(t, base, "clone", remote, repo)
+	git(t, repo, "config", "user.email", "test@example.com")
+	git(t, repo, "config", "user.name", "Test User")
+
+	agents := agent.NewManager()
+	agents.RegisterProject(proj)
+	a, err := agents.Create(proj, agent.SpawnedByUser, false)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	commitFile(t, a.Info().Worktree, "feature.txt", "done\n")
+
+	return New(proj, agents, DefaultConfig()), a.ID, remote
+}
+
+func TestOrchestrator_HandleAgentDoneMerge_RetriesFailedPush(t *testing.T) {
+	orch, agentID, remote := setupMergeOrchestrator(t)
+	repo := orch.Project().RepoDir()
+
+	// Another agent's commit lands on the remote just before our first
+	// push, which is then rejected
+	other := filepath.Join(t.TempDir(), "other")
+	git(t, filepath.Dir(other), "clone", remote, other)
+	git(t, other, "config", "user.email", "other@example.com")
+	git(t, other, "config", "user.name", "Other Agent")
+	otherSHA := commitFile(t, other, "other.txt", "other\n")
+	hook := fmt.Sprintf(`#!/bin/sh
+unset GIT_DIR GIT_WORK_TREE GIT_INDEX_FILE
+if [ ! -e %[1]q/pushed ]; then
+	touch %[1]q/pushed
+	git -C %[1]q push -q origin main
+fi
+`, other)
+	if err := os.WriteFile(filepath.Join(repo, ".git", "hooks", "pre-push"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := orch.handleAgentDoneMerge(agentID, "")
+	if err != nil {
+		t.Fatalf("handleAgentDoneMerge() error = %v", err)
+	}
+	if !result.Merged {
+		t.Fatalf("handleAgentDoneMerge() = %+v, want merged on retry", result)
+	}
+	if got := git(t, remote, "rev-parse", "main"); got != result.SHA {
+		t.Errorf("remote main = %s, want the retried merge %s", got, result.SHA)
+	}
+	if got := git(t, remote, "rev-parse", "main~1"); got != otherSHA {
+		t.Errorf("remote main~1 = %s, want the competing commit %s", got, otherSHA)
+	}
+}
+
+func TestOrchestrator_HandleAgentDoneMerge_ConflictNotRetried(t *testing.T) {
+	orch, agentID, remote := setupMergeOrchestrator(t)
+	a, err := orch.agents.Get(agentID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	wtPath := a.Info().Worktree
+	commitFile(t, wtPath, "shared.txt", "from agent\n")
+
+	other := filepath.Join(t.TempDir(), "other")
+	git(t, filepath.Dir(other), "clone", remote, other)
+	git(t, other, "config", "user.email", "other@example.com")
+	git(t, other, "config", "user.name", "Other Agent")
+	commitFile(t, other, "shared.txt", "from main\n")
+	git(t, other, "push", "-q", "origin", "main")
+
+	// Count rebases: the merge's, then the one leaving the worktree on
+	// the latest main; a retry would add another
+	counter := filepath.Join(t.TempDir(), "rebases")
+	hook := fmt.Sprintf("#!/bin/sh\necho >> %q\n", counter)
+	if err := os.WriteFile(filepath.Join(orch.Project().RepoDir(), ".git", "hooks", "pre-rebase"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := orch.handleAgentDoneMerge(agentID, "")
+	if err != nil {
+		t.Fatalf("handleAgentDoneMerge() error = %v", err)
+	}
+	if result.Merged || result.MergeError == "" || len(result.Conflicts) != 1 || result.Conflicts[0].Path != "shared.txt" {
+		t.Errorf("handleAgentDoneMerge() = %+v, want a conflict in shared.txt", result)
+	}
+	if data, err := os.ReadFile(counter); err != nil || strings.Count(string(data), "\n") != 2 {
+		t.Errorf("rebases = %q (%v), want 2: a conflict isn't retried", data, err)
+	}
+}
+
+func TestOrchestrator_WaitDone(t *testing.T) {
+	orch := New(&project.Project{Name: "test-project", MaxAgents: 1}, agent.NewManager(), DefaultConfig())
+	if orch.DoneInFlight() != 0 || !orch.WaitDone(0) {
+		t.Fatal("WaitDone() with nothing in flight should return immediately")
+	}
+
+	key := doneKey{agentID: "abc123", taskID: "42"}
+	if _, err := orch.beginDone(key); err != nil {
+		t.Fatalf("beginDone() error = %v", err)
+	}
+	if n := orch.DoneInFlight(); n != 1 {
+		t.Errorf("DoneInFlight() = %d, want 1", n)
+	}
+	if orch.WaitDone(10 * time.Millisecond) {
+		t.Error("WaitDone() = true while a done is in flight")
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		orch.finishDone(key, nil)
+	}()
+	if !orch.WaitDone(5 * time.Second) {
+		t.Error("WaitDone() = false after the done finished")
+	}
+}
+
+func TestOrchestrator_FinishDone_SkipsUnlandedAndEvicts(t *testing.T) {
+	orch := New(&project.Project{Name: "test-project", MaxAgents: 1}, agent.NewManager(), DefaultConfig())
+
+	// Conflicts aren't recorded, so the agent can retry after fixing them
+	orch.finishDone(doneKey{agentID: "a", taskID: "1"}, &AgentDoneResult{MergeError: "conflict"})
+	if _, ok := orch.PriorDoneResult("a", "1"); ok {
+		t.Error("PriorDoneResult() recorded an unmerged result")
+	}
+
+	for i := 0; i <= maxDoneResults; i++ {
+		orch.finishDone(doneKey{agentID: fmt.Sprintf("agent-%d", i)}, &AgentDoneResult{Merged: true})
+	}
+	if _, ok := orch.PriorDoneResult("agent-0", ""); ok {
+		t.Error("oldest result was not evicted")
+	}
+	if _, ok := orch.PriorDoneResult(fmt.Sprintf("agent-%d", maxDoneResults), ""); !ok {
+		t.Error("newest result missing")
+	}
+}
@@ -0,0 +1,151 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/logging"
+)
+
+// preMergeToolName labels pre-merge chat entries in the agent's history.
+const preMergeToolName = "PreMerge"
+
+// preMergeFlushInterval is how often buffered pre-merge output is emitted
+// as a chat entry. Batching keeps chatty test suites from flooding the TUI.
+const preMergeFlushInterval = time.Second
+
+// preMergeMaxErrorOutput caps how much command output is returned to the
+// agent in MergeError. The tail is kept since failures are usually at the end.
+const preMergeMaxErrorOutput = 8 * 1024
+
+// runPreMergeGate runs the project's pre-merge command, if any.
+// Returns a non-nil result when the gate blocks the merge; the agent keeps
+// running (and its claims are kept) so it can fix the failure and retry.
+func (o *Orchestrator) runPreMergeGate(agentID string) (*AgentDoneResult, error) {
+	// Read the command once: it can be cleared while the gate runs
+	command := strings.TrimSpace(o.project.GetPreMergeCommand())
+	if command == "" {
+		return nil, nil
+	}
+
+	emit := o.preMergeEmitter(agentID)
+	emit(agent.ChatEntry{
+		Role:      "tool",
+		ToolName:  preMergeToolName,
+		ToolInput: command,
+		Timestamp: time.Now(),
+	})
+
+	slog.Info("running pre-merge command", "agent", agentID, "command", command)
+
+	var (
+		mu      sync.Mutex
+		pending []string
+	)
+	flush := func() {
+		mu.Lock()
+		lines := pending
+		pending = nil
+		mu.Unlock()
+		if len(lines) > 0 {
+			emit(agent.ChatEntry{
+				Role:       "tool",
+				ToolName:   preMergeToolName,
+				ToolResult: strings.Join(lines, "\n"),
+				Timestamp:  time.Now(),
+			})
+		}
+	}
+
+	stop := make(chan struct{})
+	flushDone := make(chan struct{})
+	go func() {
+		defer logging.LogPanic("premerge-flush", nil)
+		defer close(flushDone)
+		ticker := time.NewTicker(preMergeFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				flush()
+			}
+		}
+	}()
+
+	res, err := o.project.RunPreMergeCommand(context.Background(), agentID, command, func(line string) {
+		mu.Lock()
+		pending = append(pending, line)
+		mu.Unlock()
+	})
+	close(stop)
+	<-flushDone
+	flush()
+
+	if err != nil {
+		return nil, fmt.Errorf("pre-merge command: %w", err)
+	}
+
+	summary := fmt.Sprintf("pre-merge command passed in %s", res.Duration.Round(time.Second))
+	if !res.Passed {
+		summary = fmt.Sprintf("pre-merge command failed after %s", res.Duration.Round(time.Second))
+		if res.TimedOut {
+			summary = fmt.Sprintf("pre-merge command timed out after %s", o.project.GetPreMergeTimeout())
+		}
+	}
+	emit(agent.ChatEntry{
+		Role:       "tool",
+		ToolName:   preMergeToolName,
+		ToolResult: summary,
+		IsError:    !res.Passed,
+		Timestamp:  time.Now(),
+	})
+
+	if res.Passed {
+		slog.Info("pre-merge command passed", "agent", agentID, "duration", res.Duration)
+		return nil, nil
+	}
+
+	slog.Warn("pre-merge command failed, blocking merge",
+		"agent", agentID,
+		"timed_out", res.TimedOut,
+		"duration", res.Duration)
+
+	return &AgentDoneResult{
+		BranchName:  "fab/" + agentID,
+		CheckFailed: true,
+		MergeError:  fmt.Sprintf("%s; fix the failures and run 'fab agent done' again:\n%s", summary, tailOutput(res.Output, preMergeMaxErrorOutput)),
+	}, nil
+}
+
+// preMergeEmitter returns a function that records a chat entry on the agent
+// and forwards it to Config.OnAgentChatEntry. Entries are dropped if the
+// agent no longer exists.
+func (o *Orchestrator) preMergeEmitter(agentID string) func(agent.ChatEntry) {
+	a, err := o.agents.Get(agentID)
+	if err != nil {
+		return func(agent.ChatEntry) {}
+	}
+	onEntry := o.Config().OnAgentChatEntry
+	return func(entry agent.ChatEntry) {
+		entry.Seq = a.AddChatEntry(entry)
+		if onEntry != nil {
+			onEntry(a, entry)
+		}
+	}
+}
+
+// tailOutput returns at most maxLen bytes from the end of s.
+func tailOutput(s string, maxLen int) string {
+	s = strings.TrimRight(s, "\n")
+	if len(s) <= maxLen {
+		return s
+	}
+	return "...\n" + s[len(s)-maxLen:]
+}
@@ -0,0 +1,103 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/tessro/fab/internal/issue"
+	"github.com/tessro/fab/internal/logging"
+)
+
+// InProgressLabel is added to issues while an agent holds a claim on them,
+// if the project's reflect-claims is set.
+const InProgressLabel = "fab:in-progress"
+
+// reflectClaimTimeout bounds the backend calls that reflect one claim change.
+const reflectClaimTimeout = 30 * time.Second
+
+// reflectClaimChange queues a claim change to be reflected to the issue
+// backend if the project's reflect-claims is set. Backend calls can be slow,
+// so changes are applied by a background goroutine, one at a time and in
+// order, so a claim released soon after it was taken can't land first.
+func (o *Orchestrator) reflectClaimChange(change ClaimChange) {
+	if !o.project.ReflectClaims || o.config.IssueBackendFactory == nil {
+		return
+	}
+
+	o.reflectMu.Lock()
+	o.reflectQueue = append(o.reflectQueue, change)
+	start := !o.reflecting
+	o.reflecting = true
+	o.reflectMu.Unlock()
+
+	if start {
+		go o.drainReflections()
+	}
+}
+
+// drainReflections reflects queued claim changes until the queue is empty.
+func (o *Orchestrator) drainReflections() {
+	for {
+		o.reflectMu.Lock()
+		if len(o.reflectQueue) == 0 {
+			o.reflecting = false
+			o.reflectMu.Unlock()
+			return
+		}
+		change := o.reflectQueue[0]
+		o.reflectQueue = o.reflectQueue[1:]
+		o.reflectMu.Unlock()
+
+		o.reflectClaim(change)
+	}
+}
+
+// reflectClaim adds InProgressLabel to a newly claimed issue, or removes it
+// from a released one, and comments on the change if the backend supports
+// comments. Failures are logged; the claim itself is unaffected.
+func (o *Orchestrator) reflectClaim(change ClaimChange) {
+	defer logging.LogPanic("reflect-claim", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), reflectClaimTimeout)
+	defer cancel()
+
+	backend, err := o.config.IssueBackendFactory(o.project.RepoDir())
+	if err != nil {
+		slog.Warn("failed to reflect claim", "project", o.project.Name, "issue", change.TicketID, "error", err)
+		return
+	}
+	iss, err := backend.Get(ctx, change.TicketID)
+	if err != nil {
+		slog.Warn("failed to reflect claim", "project", o.project.Name, "issue", change.TicketID, "error", err)
+		return
+	}
+
+	labeled := slices.Contains(iss.Labels, InProgressLabel)
+	if change.Claimed != labeled {
+		params := preservingUpdate(iss)
+		if change.Claimed {
+			params.Labels = append(slices.Clone(iss.Labels), InProgressLabel)
+		} else {
+			params.Labels = slices.DeleteFunc(slices.Clone(iss.Labels), func(l string) bool {
+				return l == InProgressLabel
+			})
+		}
+		if _, err := backend.Update(ctx, change.TicketID, params); err != nil {
+			slog.Warn("failed to reflect claim", "project", o.project.Name, "issue", change.TicketID, "error", err)
+			return
+		}
+	}
+
+	if collab, ok := backend.(issue.CollaborativeBackend); ok {
+		body := fmt.Sprintf("fab agent %s started working on this issue.", change.AgentID)
+		if !change.Claimed {
+			body = fmt.Sprintf("fab agent %s stopped working on this issue.", change.AgentID)
+		}
+		if err := collab.AddComment(ctx, change.TicketID, body); err != nil {
+			slog.Debug("failed to comment on claimed issue", "issue", change.TicketID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package orchestrator
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tessro/fab/internal/issue"
+)
+
+// waitForReflections waits until the orchestrator's queued claim changes
+// have been reflected to the backend.
+func waitForReflections(t *testing.T, o *Orchestrator) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		o.reflectMu.Lock()
+		done := !o.reflecting
+		o.reflectMu.Unlock()
+		if done {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("claim changes not reflected in time")
+}
+
+func TestOrchestrator_ReflectClaims(t *testing.T) {
+	backend := newBlockBackend(
+		&issue.Issue{ID: "1", Type: "bug", Priority: 2, Labels: []string{"backend"}},
+		&issue.Issue{ID: "2", Labels: []string{InProgressLabel, "docs"}},
+	)
+	orch := newFailureOrchestrator(backend, 3)
+	orch.project.ReflectClaims = true
+
+	_ = orch.Claims().Claim("1", "agent-a")
+	waitForReflections(t, orch)
+
+	params, ok := backend.updates["1"]
+	if !ok {
+		t.Fatal("claimed issue not updated")
+	}
+	if !slices.Equal(params.Labels, []string{"backend", InProgressLabel}) {
+		t.Errorf("Labels = %v, want existing labels plus %s", params.Labels, InProgressLabel)
+	}
+	if params.Type == nil || *params.Type != "bug" || params.Priority == nil || *params.Priority != 2 {
+		t.Errorf("type and priority not preserved: %+v", params)
+	}
+	if params.Status != nil {
+		t.Errorf("Status = %v, want unchanged", *params.Status)
+	}
+	if got := backend.comments["1"]; len(got) != 1 || got[0] != "fab agent agent-a started working on this issue." {
+		t.Errorf("comments = %v, want one noting agent-a started", got)
+	}
+
+	_ = orch.Claims().Claim("2", "agent-b")
+	orch.Claims().Release("2")
+	waitForReflections(t, orch)
+
+	// The label was already there, so only the release updates it
+	if params := backend.updates["2"]; !slices.Equal(params.Labels, []string{"docs"}) {
+		t.Errorf("Labels = %v, want %s removed", params.Labels, InProgressLabel)
+	}
+	want := []string{
+		"fab agent agent-b started working on this issue.",
+		"fab agent agent-b stopped working on this issue.",
+	}
+	if got := backend.comments["2"]; !slices.Equal(got, want) {
+		t.Errorf("comments = %v, want %v", got, want)
+	}
+}
+
+func TestOrchestrator_ReflectClaims_Disabled(t *testing.T) {
+	backend := newBlockBackend(&issue.Issue{ID: "1"})
+	orch := newFailureOrchestrator(backend, 3)
+
+	_ = orch.Claims().Claim("1", "agent-a")
+	orch.Claims().ReleaseByAgent("agent-a")
+	waitForReflections(t, orch)
+
+	if len(backend.updates) != 0 || len(backend.comments) != 0 {
+		t.Errorf("backend changed without reflect-claims: updates %v, comments %v", backend.updates, backend.comments)
+	}
+}
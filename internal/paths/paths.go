@@ -147,6 +147,25 @@ func PlanPath(planID string) (string, error) {
 	return filepath.Join(dir, planID+".md"), nil
 }
 
+// DebugDir returns the directory for raw agent output captures
+// (~/.fab/debug by default, or FAB_DIR/debug).
+func DebugDir() (string, error) {
+	base, err := BaseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "debug"), nil
+}
+
+// DebugLogPath returns the raw output capture log for an agent.
+func DebugLogPath(agentID string) (string, error) {
+	dir, err := DebugDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, agentID+".log"), nil
+}
+
 // AgentHostsDir returns the directory containing agent host sockets.
 // (~/.fab/hosts by default, or FAB_DIR/hosts).
 func AgentHostsDir() (string, error) {
@@ -194,6 +213,16 @@ func AgentsRuntimePath() (string, error) {
 	return filepath.Join(dir, "agents.json"), nil
 }
 
+// ChatHistoryPath returns the file that an agent's evicted chat history
+// spills to (~/.fab/runtime/history/<agentID>.jsonl by default).
+func ChatHistoryPath(agentID string) (string, error) {
+	dir, err := RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history", agentID+".jsonl"), nil
+}
+
 // DirectorWorkDir returns the director's working directory.
 // This is the projects directory (~/.fab/projects by default)
 // which gives the director visibility into all project repos.
@@ -329,6 +329,34 @@ func TestPlanPath(t *testing.T) {
 	}
 }
 
+func TestChatHistoryPath(t *testing.T) {
+	os.Setenv(EnvFabDir, "/tmp/fab-test")
+	defer os.Unsetenv(EnvFabDir)
+
+	path, err := ChatHistoryPath("abc123")
+	if err != nil {
+		t.Fatalf("ChatHistoryPath() error = %v", err)
+	}
+	expected := "/tmp/fab-test/runtime/history/abc123.jsonl"
+	if path != expected {
+		t.Errorf("ChatHistoryPath() = %q, want %q", path, expected)
+	}
+}
+
+func TestDebugLogPath(t *testing.T) {
+	os.Setenv(EnvFabDir, "/tmp/fab-test")
+	defer os.Unsetenv(EnvFabDir)
+
+	path, err := DebugLogPath("abc123")
+	if err != nil {
+		t.Fatalf("DebugLogPath() error = %v", err)
+	}
+	expected := "/tmp/fab-test/debug/abc123.log"
+	if path != expected {
+		t.Errorf("DebugLogPath() = %q, want %q", path, expected)
+	}
+}
+
 func TestDirectorWorkDir(t *testing.T) {
 	t.Run("default uses projects directory", func(t *testing.T) {
 		os.Unsetenv(EnvFabDir)
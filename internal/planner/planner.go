@@ -155,7 +155,7 @@ func (p *Planner) buildCommand(threadID string) (*exec.Cmd, error) {
 	// permission handling (including LLM auth) via the standard agent flow.
 	// InitialPrompt is passed to the backend so it can be included as a command-line
 	// argument for backends that require it (e.g., Codex).
-	return p.backend.BuildCommand(backend.CommandConfig{
+	return backend.Command(p.backend, backend.CommandConfig{
 		WorkDir:       p.WorkDir(),
 		AgentID:       "plan:" + p.id,
 		InitialPrompt: p.planPrompt,
@@ -167,7 +167,7 @@ func (p *Planner) buildCommand(threadID string) (*exec.Cmd, error) {
 // buildResumeCommand creates an exec.Cmd for resuming a conversation.
 // Used by Codex which requires separate processes per turn.
 func (p *Planner) buildResumeCommand(threadID, message string) (*exec.Cmd, error) {
-	return p.backend.BuildCommand(backend.CommandConfig{
+	return backend.Command(p.backend, backend.CommandConfig{
 		WorkDir:       p.WorkDir(),
 		AgentID:       "plan:" + p.id,
 		InitialPrompt: message,
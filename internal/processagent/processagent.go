@@ -100,6 +100,8 @@ type ProcessAgent struct {
 	onEntry func(entry agent.ChatEntry)
 	// +checklocks:mu
 	onThreadIDChange func(threadID string)
+	// +checklocks:mu
+	onTurnComplete func(isError bool)
 
 	// Read loop control
 	readLoopStop chan struct{}
@@ -181,6 +183,14 @@ func (p *ProcessAgent) OnStateChange(fn func(old, new State)) {
 	p.onStateChange = fn
 }
 
+// OnTurnComplete sets a callback for when the agent finishes responding to a
+// message and is waiting for input. isError reports whether the turn failed.
+func (p *ProcessAgent) OnTurnComplete(fn func(isError bool)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onTurnComplete = fn
+}
+
 // OnEntry sets a callback for chat entries.
 func (p *ProcessAgent) OnEntry(fn func(entry agent.ChatEntry)) {
 	p.mu.Lock()
@@ -749,6 +759,17 @@ func (p *ProcessAgent) runReadLoop() {
 				callback(entry)
 			}
 		}
+
+		// A result message ends the turn
+		if msg.Type == "result" {
+			p.mu.RLock()
+			onTurnComplete := p.onTurnComplete
+			p.mu.RUnlock()
+
+			if onTurnComplete != nil {
+				onTurnComplete(msg.IsError)
+			}
+		}
 	}
 
 	// Scanner finished - process likely exited
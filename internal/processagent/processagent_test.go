@@ -247,3 +247,33 @@ func TestParseMessageDefaultFallback(t *testing.T) {
 
 	// If we get here without panic, the default fallback worked
 }
+
+func TestOnTurnCompleteCallback(t *testing.T) {
+	config := Config{
+		WorkDir:   t.TempDir(),
+		LogPrefix: "test",
+		BuildCommand: func() (*exec.Cmd, error) {
+			return exec.Command("printf", "%s\\n%s\\n",
+				`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"hi"}]}}`,
+				`{"type":"result","is_error":true,"result":"boom"}`), nil
+		},
+	}
+
+	p := New(config)
+	done := make(chan bool, 1)
+	p.OnTurnComplete(func(isError bool) {
+		done <- isError
+	})
+	if err := p.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	select {
+	case isError := <-done:
+		if !isError {
+			t.Error("isError = false, want true")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnTurnComplete callback not called")
+	}
+}
@@ -0,0 +1,156 @@
+package project
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ActiveHours is a weekly window during which a project orchestrates,
+// parsed from the active-hours setting.
+type ActiveHours struct {
+	Start    int            // Minutes after midnight the window opens
+	End      int            // Minutes after midnight the window closes; before Start for overnight windows
+	Days     [7]bool        // Days the window opens on, indexed by time.Weekday
+	Location *time.Location // Time zone the window is in
+}
+
+// weekdays maps day abbreviations to weekdays.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseActiveHours parses an active-hours setting of the form
+// "HH:MM-HH:MM [days] [time zone]", e.g. "09:00-18:00 Mon-Fri America/New_York".
+// Days are three-letter names, as a range ("Mon-Fri", which may wrap, like
+// "Fri-Mon") or a list ("Mon,Wed,Fri"); every day if omitted. The time zone
+// is an IANA name, or the daemon's local time if omitted. A window whose end
+// is before its start runs overnight, and counts toward the day it opens.
+func ParseActiveHours(s string) (*ActiveHours, error) {
+	fields := strings.Fields(s)
+	for i, f := range fields {
+		fields[i] = strings.TrimSuffix(f, ",")
+	}
+	if len(fields) == 0 || len(fields) > 3 {
+		return nil, fmt.Errorf("expected HH:MM-HH:MM [days] [time zone], got %q", s)
+	}
+
+	startStr, endStr, ok := strings.Cut(fields[0], "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid time range %q: expected HH:MM-HH:MM", fields[0])
+	}
+	h := &ActiveHours{Location: time.Local}
+	var err error
+	if h.Start, err = parseClock(startStr, false); err != nil {
+		return nil, err
+	}
+	if h.End, err = parseClock(endStr, true); err != nil {
+		return nil, err
+	}
+	if h.Start == h.End {
+		return nil, fmt.Errorf("invalid time range %q: start and end are the same", fields[0])
+	}
+
+	rest := fields[1:]
+	if len(rest) > 0 {
+		if days, err := parseDays(rest[0]); err == nil {
+			h.Days = days
+			rest = rest[1:]
+		} else if len(rest) == 2 {
+			return nil, err
+		}
+	}
+	if h.Days == [7]bool{} {
+		h.Days = [7]bool{true, true, true, true, true, true, true}
+	}
+	if len(rest) > 0 {
+		loc, err := time.LoadLocation(rest[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q", rest[0])
+		}
+		h.Location = loc
+	}
+	return h, nil
+}
+
+// parseClock parses HH:MM into minutes after midnight. "24:00" is accepted
+// as an end time.
+func parseClock(s string, end bool) (int, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	hours, herr := strconv.Atoi(hh)
+	minutes, merr := strconv.Atoi(mm)
+	if !ok || herr != nil || merr != nil || len(mm) != 2 || hours < 0 || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", s)
+	}
+	if hours > 23 && !(end && hours == 24 && minutes == 0) {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", s)
+	}
+	return hours*60 + minutes, nil
+}
+
+// parseDays parses a day range or list into a set of weekdays.
+func parseDays(s string) ([7]bool, error) {
+	var days [7]bool
+	day := func(name string) (time.Weekday, error) {
+		d, ok := weekdays[strings.ToLower(name)]
+		if !ok {
+			return 0, fmt.Errorf("invalid day %q: expected Mon, Tue, ... Sun", name)
+		}
+		return d, nil
+	}
+
+	if from, to, ok := strings.Cut(s, "-"); ok {
+		first, err := day(from)
+		if err != nil {
+			return days, err
+		}
+		last, err := day(to)
+		if err != nil {
+			return days, err
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == last {
+				break
+			}
+		}
+		return days, nil
+	}
+	for _, name := range strings.Split(s, ",") {
+		d, err := day(name)
+		if err != nil {
+			return days, err
+		}
+		days[d] = true
+	}
+	return days, nil
+}
+
+// Contains reports whether t falls inside the window.
+func (h *ActiveHours) Contains(t time.Time) bool {
+	t = t.In(h.Location)
+	minute := t.Hour()*60 + t.Minute()
+	if h.Start < h.End {
+		return h.Days[t.Weekday()] && minute >= h.Start && minute < h.End
+	}
+	// Overnight: the late part belongs to today, the early part to yesterday
+	if minute >= h.Start {
+		return h.Days[t.Weekday()]
+	}
+	return minute < h.End && h.Days[(t.Weekday()+6)%7]
+}
+
+// GetActiveHours returns the project's active-hours window, or nil if the
+// project orchestrates at any time. An unparseable setting is ignored.
+func (p *Project) GetActiveHours() *ActiveHours {
+	if p.ActiveHours == "" {
+		return nil
+	}
+	h, err := ParseActiveHours(p.ActiveHours)
+	if err != nil {
+		return nil
+	}
+	return h
+}
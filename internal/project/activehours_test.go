@@ -0,0 +1,76 @@
+package project
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseActiveHours(t *testing.T) {
+	for _, s := range []string{
+		"09:00-18:00",
+		"09:00-18:00 Mon-Fri",
+		"09:00-18:00 Mon-Fri, UTC",
+		"22:00-06:00 fri-mon America/New_York",
+		"08:30-24:00 Mon,Wed,Fri",
+		"10:00-16:00 Etc/GMT-5",
+	} {
+		if _, err := ParseActiveHours(s); err != nil {
+			t.Errorf("ParseActiveHours(%q) error = %v", s, err)
+		}
+	}
+	for _, s := range []string{
+		"",
+		"9-5",
+		"09:00-09:00",
+		"25:00-26:00",
+		"09:00-18:60",
+		"24:00-06:00",
+		"09:00-18:00 Mon-Fry UTC",
+		"09:00-18:00 Mars/Olympus",
+		"09:00-18:00 Mon UTC extra",
+	} {
+		if _, err := ParseActiveHours(s); err == nil {
+			t.Errorf("ParseActiveHours(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestActiveHours_Contains(t *testing.T) {
+	// 2026-01-05 is a Monday
+	at := func(day int, hhmm string) time.Time {
+		tm, err := time.Parse("15:04", hhmm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return time.Date(2026, 1, day, tm.Hour(), tm.Minute(), 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		hours string
+		t     time.Time
+		want  bool
+	}{
+		{"09:00-18:00 Mon-Fri UTC", at(5, "09:00"), true},
+		{"09:00-18:00 Mon-Fri UTC", at(5, "17:59"), true},
+		{"09:00-18:00 Mon-Fri UTC", at(5, "18:00"), false},
+		{"09:00-18:00 Mon-Fri UTC", at(5, "08:59"), false},
+		{"09:00-18:00 Mon-Fri UTC", at(10, "12:00"), false}, // Saturday
+		{"09:00-18:00 Mon-Fri America/New_York", at(5, "13:00"), false},
+		{"09:00-18:00 Mon-Fri America/New_York", at(5, "15:00"), true},
+		// Overnight windows count toward the day they open
+		{"22:00-06:00 Fri UTC", at(9, "23:00"), true},  // Friday night
+		{"22:00-06:00 Fri UTC", at(10, "05:00"), true}, // Saturday morning
+		{"22:00-06:00 Fri UTC", at(10, "23:00"), false},
+		{"22:00-06:00 Fri UTC", at(9, "05:00"), false},
+		{"00:00-24:00 Sat-Sun UTC", at(11, "23:59"), true},
+	}
+	for _, tt := range tests {
+		h, err := ParseActiveHours(tt.hours)
+		if err != nil {
+			t.Fatalf("ParseActiveHours(%q) error = %v", tt.hours, err)
+		}
+		if got := h.Contains(tt.t); got != tt.want {
+			t.Errorf("%q.Contains(%s) = %v, want %v", tt.hours, tt.t.Format("Mon 15:04"), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Limits on the conflict details collected from a failed rebase, so a huge
+// conflict doesn't flood the agent's context.
+const (
+	maxConflictFiles     = 20  // Files whose hunks are collected
+	maxConflictHunks     = 10  // Hunks collected per file
+	maxConflictHunkLines = 100 // Lines kept per hunk
+)
+
+// ConflictHunk is one conflicted region of a file.
+type ConflictHunk struct {
+	StartLine int    // 1-based line of the opening "<<<<<<<" marker
+	EndLine   int    // 1-based line of the closing ">>>>>>>" marker
+	Text      string // The region verbatim, markers included (capped at maxConflictHunkLines)
+}
+
+// ConflictFile is a file left with unresolved conflicts by a failed rebase.
+type ConflictFile struct {
+	Path      string         // Path relative to the worktree
+	Hunks     []ConflictHunk // Empty for conflicts without markers (e.g., binary files or delete/modify)
+	Truncated bool           // True if hunks or files were left out to stay within the limits
+}
+
+// conflictDetails reads the conflict markers of each conflicted file in a
+// worktree. Must be called before the rebase is aborted.
+func conflictDetails(wtPath string, files []string) []ConflictFile {
+	conflicts := make([]ConflictFile, 0, len(files))
+	for i, path := range files {
+		cf := ConflictFile{Path: path}
+		if i >= maxConflictFiles {
+			cf.Truncated = true
+		} else if data, err := os.ReadFile(filepath.Join(wtPath, path)); err == nil {
+			cf.Hunks, cf.Truncated = parseConflictHunks(string(data))
+		}
+		conflicts = append(conflicts, cf)
+	}
+	return conflicts
+}
+
+// parseConflictHunks extracts the regions between conflict markers in
+// content. truncated is true if hunks or lines were dropped.
+func parseConflictHunks(content string) (hunks []ConflictHunk, truncated bool) {
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "<<<<<<< ") && lines[i] != "<<<<<<<" {
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && !strings.HasPrefix(lines[end], ">>>>>>>") {
+			end++
+		}
+		if end == len(lines) {
+			// Unterminated marker, not a conflict git wrote
+			break
+		}
+
+		if len(hunks) == maxConflictHunks {
+			truncated = true
+			break
+		}
+
+		region := lines[i : end+1]
+		if len(region) > maxConflictHunkLines {
+			region = append(region[:maxConflictHunkLines:maxConflictHunkLines],
+				fmt.Sprintf("... (%d more lines)", end+1-i-maxConflictHunkLines))
+			truncated = true
+		}
+		hunks = append(hunks, ConflictHunk{
+			StartLine: i + 1,
+			EndLine:   end + 1,
+			Text:      strings.Join(region, "\n"),
+		})
+		i = end
+	}
+	return hunks, truncated
+}
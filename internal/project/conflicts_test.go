@@ -0,0 +1,64 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseConflictHunks(t *testing.T) {
+	content := strings.Join([]string{
+		"package main",
+		"<<<<<<< HEAD",
+		"const a = 1",
+		"=======",
+		"const a = 2",
+		">>>>>>> 1a2b3c4 (Change a)",
+		"",
+		"<<<<<<< HEAD",
+		"const b = 1",
+		"=======",
+		">>>>>>> 1a2b3c4 (Remove b)",
+		"<<<<<<< unterminated",
+	}, "\n")
+
+	hunks, truncated := parseConflictHunks(content)
+	if truncated {
+		t.Error("truncated = true, want false")
+	}
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2: %+v", len(hunks), hunks)
+	}
+	if hunks[0].StartLine != 2 || hunks[0].EndLine != 6 {
+		t.Errorf("hunks[0] lines = %d-%d, want 2-6", hunks[0].StartLine, hunks[0].EndLine)
+	}
+	if !strings.HasPrefix(hunks[0].Text, "<<<<<<< HEAD\nconst a = 1") || !strings.HasSuffix(hunks[0].Text, "(Change a)") {
+		t.Errorf("hunks[0].Text = %q", hunks[0].Text)
+	}
+	if hunks[1].StartLine != 8 || hunks[1].EndLine != 11 {
+		t.Errorf("hunks[1] lines = %d-%d, want 8-11", hunks[1].StartLine, hunks[1].EndLine)
+	}
+}
+
+func TestParseConflictHunks_Limits(t *testing.T) {
+	var b strings.Builder
+	for i := range maxConflictHunks + 1 {
+		fmt.Fprintf(&b, "<<<<<<< HEAD\n%d\n=======\n>>>>>>> theirs\n", i)
+	}
+	hunks, truncated := parseConflictHunks(b.String())
+	if len(hunks) != maxConflictHunks || !truncated {
+		t.Errorf("got %d hunks (truncated=%v), want %d truncated", len(hunks), truncated, maxConflictHunks)
+	}
+
+	long := "<<<<<<< HEAD\n" + strings.Repeat("x\n", maxConflictHunkLines) + "=======\n>>>>>>> theirs\n"
+	hunks, truncated = parseConflictHunks(long)
+	if len(hunks) != 1 || !truncated {
+		t.Fatalf("got %d hunks (truncated=%v), want 1 truncated", len(hunks), truncated)
+	}
+	if got := strings.Count(hunks[0].Text, "\n") + 1; got != maxConflictHunkLines+1 {
+		t.Errorf("hunk has %d lines, want %d", got, maxConflictHunkLines+1)
+	}
+	if hunks[0].EndLine != maxConflictHunkLines+3 || !strings.HasSuffix(hunks[0].Text, "... (3 more lines)") {
+		t.Errorf("hunk = %+v", hunks[0])
+	}
+}
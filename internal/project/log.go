@@ -0,0 +1,82 @@
+package project
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogCommit is a commit on the project's default branch.
+type LogCommit struct {
+	SHA     string
+	Author  string
+	Time    time.Time // Author date
+	Subject string
+	AgentID string // Agent whose merged work ends at this commit; empty if fab didn't merge it
+	IssueID string // Issue the agent was working on, if known
+}
+
+// Log returns the newest limit commits on the remote default branch, newest
+// first. A non-zero since or until keeps only commits that landed in that
+// range, by committer date, which for fab's merges is when they were merged.
+// With fetch, origin is fetched first so commits pushed outside fab
+// show up. Commits that fab agents merged are attributed to their agent from
+// the merges in the activity timeline, which records each merged branch's
+// tip; commits beneath a tip aren't attributed.
+func (p *Project) Log(limit int, since, until time.Time, fetch bool) ([]LogCommit, error) {
+	repoDir := p.RepoDir()
+	if fetch {
+		p.mergeMu.Lock()
+		fetchCmd := exec.Command("git", "fetch", "origin")
+		fetchCmd.Dir = repoDir
+		output, err := fetchCmd.CombinedOutput()
+		p.mergeMu.Unlock()
+		if err != nil {
+			return nil, fmt.Errorf("fetch: %w\n%s", err, output)
+		}
+	}
+
+	// Fields are separated by the unit separator, which can't appear in them
+	args := []string{"log", "-n", strconv.Itoa(limit), "--format=%H%x1f%an%x1f%aI%x1f%s"}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.UTC().Format(time.RFC3339))
+	}
+	if !until.IsZero() {
+		args = append(args, "--until="+until.UTC().Format(time.RFC3339))
+	}
+	logCmd := exec.Command("git", append(args, p.remoteBaseRef(), "--")...)
+	logCmd.Dir = repoDir
+	output, err := logCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log %s: %w", p.remoteBaseRef(), err)
+	}
+
+	events, _, err := p.ReadTimeline(time.Time{}, 0)
+	if err != nil {
+		return nil, err
+	}
+	merges := make(map[string]TimelineEvent)
+	for _, ev := range events {
+		if ev.Type == TimelineMerge && ev.SHA != "" {
+			merges[ev.SHA] = ev
+		}
+	}
+
+	var commits []LogCommit
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		c := LogCommit{SHA: fields[0], Author: fields[1], Subject: fields[3]}
+		c.Time, _ = time.Parse(time.RFC3339, fields[2])
+		if ev, ok := merges[c.SHA]; ok {
+			c.AgentID = ev.AgentID
+			c.IssueID = ev.IssueID
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
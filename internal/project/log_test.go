@@ -0,0 +1,58 @@
+package project
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLog(t *testing.T) {
+	p, remote := setupClonedProject(t, "main")
+
+	first := pushUpstreamCommit(t, remote, "main")
+
+	// Without a fetch, the remote branch is as of the clone
+	commits, err := p.Log(10, time.Time{}, time.Time{}, false)
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "Initial commit" {
+		t.Fatalf("Log() = %+v, want only the initial commit", commits)
+	}
+
+	if err := p.AppendTimeline(TimelineEvent{Type: TimelineMerge, AgentID: "a1", IssueID: "42", SHA: first}); err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err = p.Log(10, time.Time{}, time.Time{}, true)
+	if err != nil {
+		t.Fatalf("Log(fetch) error = %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Log(fetch) = %+v, want 2 commits", commits)
+	}
+	c := commits[0]
+	if c.SHA != first || c.Author != "Test User" || c.Subject != "Update upstream.txt" || c.Time.IsZero() {
+		t.Errorf("commits[0] = %+v, want the upstream commit", c)
+	}
+	if c.AgentID != "a1" || c.IssueID != "42" {
+		t.Errorf("commits[0] = %+v, want it attributed to a1 working on 42", c)
+	}
+	if commits[1].AgentID != "" {
+		t.Errorf("commits[1] = %+v, want no agent", commits[1])
+	}
+
+	if commits, _ := p.Log(1, time.Time{}, time.Time{}, false); len(commits) != 1 || commits[0].SHA != first {
+		t.Errorf("Log(1) = %+v, want the newest commit", commits)
+	}
+
+	hourAgo := time.Now().Add(-time.Hour)
+	if commits, _ := p.Log(10, hourAgo, time.Time{}, false); len(commits) != 2 {
+		t.Errorf("Log(since an hour ago) = %+v, want both commits", commits)
+	}
+	if commits, _ := p.Log(10, time.Now().Add(time.Hour), time.Time{}, false); len(commits) != 0 {
+		t.Errorf("Log(since the future) = %+v, want no commits", commits)
+	}
+	if commits, _ := p.Log(10, time.Time{}, hourAgo, false); len(commits) != 0 {
+		t.Errorf("Log(until an hour ago) = %+v, want no commits", commits)
+	}
+}
@@ -0,0 +1,101 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// NotesFile is the name of the shared notes file in the project directory.
+// Agents append findings to it so later agents don't repeat the same
+// investigation.
+const NotesFile = "NOTES.md"
+
+// Limits on the shared notes, so one note or a long history doesn't flood
+// an agent's context.
+const (
+	MaxNoteBytes      = 16 * 1024 // Largest note accepted by AppendNote
+	maxNotesReadBytes = 64 * 1024 // Tail of the file returned by ReadNotes
+)
+
+// NotesPath returns the path of the project's shared notes file.
+func (p *Project) NotesPath() string {
+	return filepath.Join(p.ProjectDir(), NotesFile)
+}
+
+// AppendNote adds a note to the project's shared notes file under a heading
+// naming its author and time. Appends are serialized so concurrent notes
+// don't interleave.
+func (p *Project) AppendNote(author, text string, at time.Time) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return errors.New("note is empty")
+	}
+	if len(text) > MaxNoteBytes {
+		return fmt.Errorf("note is %d bytes, more than the %d byte limit", len(text), MaxNoteBytes)
+	}
+	if author == "" {
+		author = "unknown"
+	}
+
+	p.notesMu.Lock()
+	defer p.notesMu.Unlock()
+
+	path := p.NotesPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create project directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open notes: %w", err)
+	}
+
+	entry := fmt.Sprintf("### %s — %s\n\n%s\n\n", at.UTC().Format(time.RFC3339), author, text)
+	if _, err := f.WriteString(entry); err != nil {
+		f.Close()
+		return fmt.Errorf("write notes: %w", err)
+	}
+	return f.Close()
+}
+
+// ReadNotes returns the project's shared notes, or "" if none were written.
+// A file larger than the read limit is cut to its most recent notes and
+// truncated is true.
+func (p *Project) ReadNotes() (content string, truncated bool, err error) {
+	f, err := os.Open(p.NotesPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("open notes: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", false, fmt.Errorf("stat notes: %w", err)
+	}
+	if size := info.Size(); size > maxNotesReadBytes {
+		if _, err := f.Seek(size-maxNotesReadBytes, io.SeekStart); err != nil {
+			return "", false, fmt.Errorf("seek notes: %w", err)
+		}
+		truncated = true
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", false, fmt.Errorf("read notes: %w", err)
+	}
+	content = string(data)
+	if truncated {
+		// Start at a note heading rather than mid-note
+		if i := strings.Index(content, "\n### "); i >= 0 {
+			content = content[i+1:]
+		}
+	}
+	return content, truncated, nil
+}
@@ -0,0 +1,95 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestProject_AppendNote(t *testing.T) {
+	p := &Project{Name: "proj", BaseDir: t.TempDir()}
+
+	content, truncated, err := p.ReadNotes()
+	if err != nil || content != "" || truncated {
+		t.Fatalf("ReadNotes() = %q, %v, %v; want empty notes", content, truncated, err)
+	}
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := p.AppendNote("agent-1", "  the flaky test needs a running redis\n", at); err != nil {
+		t.Fatalf("AppendNote() error = %v", err)
+	}
+	if err := p.AppendNote("", "second", at); err != nil {
+		t.Fatalf("AppendNote() error = %v", err)
+	}
+
+	content, truncated, err = p.ReadNotes()
+	if err != nil || truncated {
+		t.Fatalf("ReadNotes() error = %v, truncated = %v", err, truncated)
+	}
+	want := "### 2026-01-02T03:04:05Z — agent-1\n\nthe flaky test needs a running redis\n\n" +
+		"### 2026-01-02T03:04:05Z — unknown\n\nsecond\n\n"
+	if content != want {
+		t.Errorf("ReadNotes() = %q, want %q", content, want)
+	}
+}
+
+func TestProject_AppendNote_Invalid(t *testing.T) {
+	p := &Project{Name: "proj", BaseDir: t.TempDir()}
+
+	if err := p.AppendNote("agent-1", " \n", time.Now()); err == nil {
+		t.Error("AppendNote() with an empty note succeeded, want error")
+	}
+	if err := p.AppendNote("agent-1", strings.Repeat("x", MaxNoteBytes+1), time.Now()); err == nil {
+		t.Error("AppendNote() with an oversized note succeeded, want error")
+	}
+}
+
+func TestProject_AppendNote_Concurrent(t *testing.T) {
+	p := &Project{Name: "proj", BaseDir: t.TempDir()}
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := p.AppendNote(fmt.Sprintf("agent-%d", i), strings.Repeat("y", 1000), time.Now()); err != nil {
+				t.Errorf("AppendNote() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	content, _, err := p.ReadNotes()
+	if err != nil {
+		t.Fatalf("ReadNotes() error = %v", err)
+	}
+	if n := strings.Count(content, "\n\n"+strings.Repeat("y", 1000)+"\n\n"); n != 20 {
+		t.Errorf("found %d intact notes, want 20", n)
+	}
+}
+
+func TestProject_ReadNotes_Truncated(t *testing.T) {
+	p := &Project{Name: "proj", BaseDir: t.TempDir()}
+
+	for i := range 100 {
+		if err := p.AppendNote("agent", fmt.Sprintf("note %d %s", i, strings.Repeat("z", 1000)), time.Now()); err != nil {
+			t.Fatalf("AppendNote() error = %v", err)
+		}
+	}
+
+	content, truncated, err := p.ReadNotes()
+	if err != nil {
+		t.Fatalf("ReadNotes() error = %v", err)
+	}
+	if !truncated {
+		t.Error("truncated = false, want true")
+	}
+	if !strings.HasPrefix(content, "### ") {
+		t.Errorf("truncated notes start mid-note: %q", content[:40])
+	}
+	if !strings.Contains(content, "note 99 ") || strings.Contains(content, "note 0 ") {
+		t.Error("truncated notes should keep the most recent notes and drop the oldest")
+	}
+}
@@ -0,0 +1,223 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// poolWorktreePrefix names idle pooled worktrees in the worktrees directory,
+// e.g. pool-3. Agent worktrees are named wt-{agentID}, so the two never clash.
+const poolWorktreePrefix = "pool-"
+
+// The worktree pool keeps up to WorktreePoolSize idle worktrees checked out
+// at the remote default branch while the project orchestrates. A new agent
+// takes one with `git worktree move`, which is a rename, instead of waiting
+// on `git worktree add` to check out the whole tree; a finished agent's
+// worktree is reset and returned to the pool while there is room.
+
+// OpenWorktreePool starts keeping the project's worktree pool filled.
+// The pool stays empty until FillWorktreePool is called.
+func (p *Project) OpenWorktreePool() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.poolOpen = true
+}
+
+// FillWorktreePool creates idle worktrees, reset to the remote default
+// branch, until the pool holds WorktreePoolSize of them. Pool worktrees left
+// behind by an earlier daemon are removed first. It does nothing while the
+// pool is closed.
+func (p *Project) FillWorktreePool() error {
+	gitDir := filepath.Join(p.RepoDir(), ".git")
+	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
+		return nil // Not a git repo - skip (likely a test scenario)
+	}
+
+	p.poolMu.Lock()
+	defer p.poolMu.Unlock()
+
+	p.removeStalePoolWorktrees()
+
+	fetched := false
+	for {
+		p.mu.RLock()
+		need := p.poolOpen && len(p.pool) < p.GetWorktreePoolSize()
+		p.mu.RUnlock()
+		if !need {
+			return nil
+		}
+
+		path := p.nextPoolPath()
+		if err := p.createWorktree(path); err != nil {
+			return err
+		}
+		// Fetch once per fill; later worktrees reset to the same ref
+		reset := p.resetToBase
+		if !fetched {
+			reset = p.resetWorktreeUnlocked
+		}
+		if err := reset(path); err != nil {
+			_ = p.removeWorktree(path)
+			return fmt.Errorf("reset pooled worktree: %w", err)
+		}
+		fetched = true
+
+		if !p.addToPool(path) {
+			return nil
+		}
+	}
+}
+
+// CloseWorktreePool stops keeping the pool filled and removes its idle
+// worktrees, along with any left behind by an earlier daemon. Worktrees in
+// use by agents are not touched; they are deleted as usual when released.
+func (p *Project) CloseWorktreePool() error {
+	p.mu.Lock()
+	p.poolOpen = false
+	pool := p.pool
+	p.pool = nil
+	p.mu.Unlock()
+
+	// Wait for an in-flight fill or recycle, which sees the pool closed
+	p.poolMu.Lock()
+	defer p.poolMu.Unlock()
+
+	var lastErr error
+	for _, path := range pool {
+		if err := p.removeWorktree(path); err != nil {
+			lastErr = err
+		}
+	}
+	p.removeStalePoolWorktrees()
+	return lastErr
+}
+
+// popPooledWorktree takes an idle pooled worktree out of the pool for
+// movePooledWorktree, or returns "" if none is ready.
+//
+// +checklocks:p.mu
+func (p *Project) popPooledWorktree() string {
+	if len(p.pool) == 0 {
+		return ""
+	}
+	path := p.pool[len(p.pool)-1]
+	p.pool = p.pool[:len(p.pool)-1]
+	p.poolTaking = append(p.poolTaking, path)
+	return path
+}
+
+// movePooledWorktree moves a worktree taken by popPooledWorktree to wtPath,
+// reporting whether it did. A pooled worktree that can't be moved is removed.
+// Runs without p.mu held; the worktree stays tracked in poolTaking until
+// moved, so a concurrent fill doesn't sweep it as stale.
+func (p *Project) movePooledWorktree(path, wtPath string) bool {
+	cmd := exec.Command("git", "worktree", "move", path, wtPath)
+	cmd.Dir = p.RepoDir()
+	err := cmd.Run()
+	if err != nil {
+		_ = p.removeWorktree(path)
+	}
+
+	p.mu.Lock()
+	p.poolTaking = slices.DeleteFunc(p.poolTaking, func(s string) bool { return s == path })
+	p.mu.Unlock()
+	return err == nil
+}
+
+// recycleWorktree returns a released agent worktree to the pool if the pool
+// is open and has room, reporting whether it did. HEAD is detached before
+// the reset so the agent's branch keeps its commits.
+func (p *Project) recycleWorktree(wtPath string) bool {
+	gitDir := filepath.Join(p.RepoDir(), ".git")
+	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
+		return false
+	}
+
+	p.poolMu.Lock()
+	defer p.poolMu.Unlock()
+
+	p.mu.RLock()
+	room := p.poolOpen && len(p.pool) < p.GetWorktreePoolSize()
+	p.mu.RUnlock()
+	if !room {
+		return false
+	}
+
+	detachCmd := exec.Command("git", "checkout", "--detach")
+	detachCmd.Dir = wtPath
+	if err := detachCmd.Run(); err != nil {
+		return false
+	}
+	if err := p.resetToBase(wtPath); err != nil {
+		return false
+	}
+
+	path := p.nextPoolPath()
+	moveCmd := exec.Command("git", "worktree", "move", wtPath, path)
+	moveCmd.Dir = p.RepoDir()
+	if err := moveCmd.Run(); err != nil {
+		return false
+	}
+
+	p.addToPool(path)
+	return true
+}
+
+// addToPool adds a ready worktree to the pool, or removes it if the pool
+// was closed while it was being prepared. Reports whether it was added.
+// Must be called with poolMu held.
+func (p *Project) addToPool(path string) bool {
+	p.mu.Lock()
+	if p.poolOpen {
+		p.pool = append(p.pool, path)
+		p.mu.Unlock()
+		return true
+	}
+	p.mu.Unlock()
+
+	_ = p.removeWorktree(path)
+	return false
+}
+
+// nextPoolPath returns an unused path for a pooled worktree.
+// Must be called with poolMu held.
+func (p *Project) nextPoolPath() string {
+	for {
+		p.poolSeq++
+		path := filepath.Join(p.WorktreesDir(), fmt.Sprintf("%s%d", poolWorktreePrefix, p.poolSeq))
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+	}
+}
+
+// removeStalePoolWorktrees removes pool worktrees on disk that the pool
+// doesn't track, such as those left by a daemon that crashed. Pooled
+// worktrees are only created with poolMu held, which the caller holds, and
+// stay tracked in poolTaking until moved out, so an untracked one is stale.
+// Must be called with poolMu held.
+func (p *Project) removeStalePoolWorktrees() {
+	wtDir := p.WorktreesDir()
+	entries, err := os.ReadDir(wtDir)
+	if err != nil {
+		return
+	}
+
+	var stale []string
+	p.mu.RLock()
+	for _, e := range entries {
+		path := filepath.Join(wtDir, e.Name())
+		if strings.HasPrefix(e.Name(), poolWorktreePrefix) && !slices.Contains(p.pool, path) && !slices.Contains(p.poolTaking, path) {
+			stale = append(stale, path)
+		}
+	}
+	p.mu.RUnlock()
+
+	for _, path := range stale {
+		_ = p.removeWorktree(path)
+	}
+}
@@ -0,0 +1,124 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// poolLen returns the number of idle pooled worktrees.
+func poolLen(p *Project) int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.pool)
+}
+
+func TestWorktreePool(t *testing.T) {
+	p, remote := setupClonedProject(t, "main")
+	p.WorktreePoolSize = 1
+
+	// A worktree left by a crashed daemon is swept on fill
+	stale := filepath.Join(p.WorktreesDir(), poolWorktreePrefix+"1")
+	git(t, p.RepoDir(), "worktree", "add", "--detach", stale)
+	leftover := filepath.Join(stale, "leftover.txt")
+	if err := os.WriteFile(leftover, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Closed pools stay empty
+	if err := p.FillWorktreePool(); err != nil {
+		t.Fatalf("FillWorktreePool() error = %v", err)
+	}
+	if len(p.pool) != 0 {
+		t.Fatalf("closed pool = %v, want empty", p.pool)
+	}
+
+	p.OpenWorktreePool()
+	if err := p.FillWorktreePool(); err != nil {
+		t.Fatalf("FillWorktreePool() error = %v", err)
+	}
+	if len(p.pool) != 1 {
+		t.Fatalf("pool = %v, want one worktree", p.pool)
+	}
+	if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+		t.Errorf("stale pool worktree was not removed: %v", err)
+	}
+	pooled := p.pool[0]
+
+	// Spawning takes the pooled worktree
+	wt, err := p.CreateWorktreeForAgent("agent1", false)
+	if err != nil {
+		t.Fatalf("CreateWorktreeForAgent() error = %v", err)
+	}
+	if _, err := os.Stat(pooled); !os.IsNotExist(err) {
+		t.Errorf("pooled worktree %s was not moved to %s", pooled, wt.Path)
+	}
+	if got := git(t, wt.Path, "rev-parse", "--abbrev-ref", "HEAD"); got != "fab/agent1" {
+		t.Errorf("worktree branch = %s, want fab/agent1", got)
+	}
+
+	// Wait for the background refill, then empty the pool so the release is recycled
+	deadline := time.Now().Add(30 * time.Second)
+	for poolLen(p) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if poolLen(p) != 1 {
+		t.Fatal("pool was not refilled after a spawn")
+	}
+	if err := p.CloseWorktreePool(); err != nil {
+		t.Fatalf("CloseWorktreePool() error = %v", err)
+	}
+	p.OpenWorktreePool()
+
+	sha := commitFile(t, wt.Path, "feature.txt", "done\n")
+	if err := os.WriteFile(filepath.Join(wt.Path, "scratch.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.DeleteWorktreeForAgent("agent1"); err != nil {
+		t.Fatalf("DeleteWorktreeForAgent() error = %v", err)
+	}
+	if len(p.pool) != 1 {
+		t.Fatalf("pool = %v, want the recycled worktree", p.pool)
+	}
+	recycled := p.pool[0]
+	if got := git(t, recycled, "rev-parse", "HEAD"); got != git(t, remote, "rev-parse", "main") {
+		t.Errorf("recycled worktree HEAD = %s, want origin/main", got)
+	}
+	if got := git(t, recycled, "status", "--porcelain"); got != "" {
+		t.Errorf("recycled worktree is dirty:\n%s", got)
+	}
+	if got := git(t, p.RepoDir(), "rev-parse", "fab/agent1"); got != sha {
+		t.Errorf("fab/agent1 = %s, want agent commit %s", got, sha)
+	}
+
+	// A full pool deletes released worktrees
+	if err := p.CloseWorktreePool(); err != nil {
+		t.Fatalf("CloseWorktreePool() error = %v", err)
+	}
+	p.OpenWorktreePool()
+	wt, err = p.CreateWorktreeForAgent("agent2", false)
+	if err != nil {
+		t.Fatalf("CreateWorktreeForAgent() error = %v", err)
+	}
+	if err := p.FillWorktreePool(); err != nil {
+		t.Fatalf("FillWorktreePool() error = %v", err)
+	}
+	if err := p.DeleteWorktreeForAgent("agent2"); err != nil {
+		t.Fatalf("DeleteWorktreeForAgent() error = %v", err)
+	}
+	if _, err := os.Stat(wt.Path); !os.IsNotExist(err) {
+		t.Errorf("released worktree %s not deleted with a full pool", wt.Path)
+	}
+	if poolLen(p) != 1 {
+		t.Errorf("pool has %d worktrees, want 1", poolLen(p))
+	}
+
+	if err := p.CloseWorktreePool(); err != nil {
+		t.Fatalf("CloseWorktreePool() error = %v", err)
+	}
+	entries, _ := os.ReadDir(p.WorktreesDir())
+	if len(entries) != 0 {
+		t.Errorf("worktrees left after close: %v", entries)
+	}
+}
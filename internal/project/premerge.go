@@ -0,0 +1,97 @@
+package project
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// preMergeWaitDelay bounds how long we wait for output pipes to close after
+// the pre-merge command is killed (e.g. when it spawned background children).
+const preMergeWaitDelay = 5 * time.Second
+
+// PreMergeResult represents the outcome of running the pre-merge command.
+type PreMergeResult struct {
+	Passed   bool          // True if the command exited zero
+	Output   string        // Combined stdout and stderr
+	TimedOut bool          // True if the command was killed after the timeout
+	Duration time.Duration // How long the command ran
+}
+
+// RunPreMergeCommand runs command, the project's pre-merge command as read
+// with GetPreMergeCommand, in the agent's worktree. Passing it in keeps the
+// caller and the run agreeing if the setting changes in between.
+// Each line of combined output is passed to onLine as it is produced (onLine may be nil).
+// Returns a nil result if command is empty.
+// A non-zero exit is reported as Passed=false, not as an error; errors are
+// reserved for failures to start the command at all.
+func (p *Project) RunPreMergeCommand(ctx context.Context, agentID, command string, onLine func(line string)) (*PreMergeResult, error) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return nil, nil
+	}
+
+	wtPath := p.getWorktreePathForAgent(agentID)
+	if wtPath == "" {
+		return nil, fmt.Errorf("worktree not found for agent %s", agentID)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.GetPreMergeTimeout())
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = wtPath
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	cmd.WaitDelay = preMergeWaitDelay
+	// Run in its own process group so a timeout kills the whole tree
+	// (test runners often fork workers that would otherwise outlive sh).
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		pw.Close()
+		pr.Close()
+		return nil, fmt.Errorf("start pre-merge command: %w", err)
+	}
+
+	var output strings.Builder
+	scanDone := make(chan struct{})
+	go func() {
+		defer close(scanDone)
+		scanner := bufio.NewScanner(pr)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			output.WriteString(line)
+			output.WriteByte('\n')
+			if onLine != nil {
+				onLine(line)
+			}
+		}
+		// Drain anything left (e.g. an over-long line) so the writer never blocks
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+
+	waitErr := cmd.Wait()
+	pw.Close()
+	<-scanDone
+
+	result := &PreMergeResult{
+		Passed:   waitErr == nil,
+		Output:   output.String(),
+		TimedOut: errors.Is(ctx.Err(), context.DeadlineExceeded),
+		Duration: time.Since(start),
+	}
+	return result, nil
+}
@@ -3,10 +3,14 @@ package project
 
 import (
 	"errors"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/tessro/fab/internal/logging"
 	"github.com/tessro/fab/internal/paths"
 )
 
@@ -22,6 +26,9 @@ type Defaults interface {
 	GetDefaultMergeStrategy() string
 	GetDefaultIssueBackend() string
 	GetDefaultPermissionsChecker() string
+	GetDefaultPollInterval() time.Duration
+	GetDefaultGitAuthorName() string
+	GetDefaultGitAuthorEmail() string
 }
 
 // ManagerWorktreeID is the worktree ID for the project manager.
@@ -38,16 +45,36 @@ type Project struct {
 	Name               string   // Unique identifier (e.g., "myapp")
 	RemoteURL          string   // Git remote URL (e.g., "git@github.com:user/repo.git")
 	MaxAgents          int      // Max concurrent agents (default: 3)
+	ReservedSlots      int      // Extra slots above MaxAgents kept free for high-priority issues (default: 0)
+	PriorityThreshold  int      // Minimum issue priority that may use reserved slots (default: 2, high)
 	IssueBackend       string   // Issue backend type: "tk" (default), "github", "gh", "linear"
 	LinearTeam         string   // Linear team ID (required when issue-backend is "linear")
 	LinearProject      string   // Linear project ID (optional, for scoping issues to a project)
+	GitHubHost         string   // GitHub Enterprise host for the github backend (empty = detect from remote)
+	GitHubIssueLimit   int      // Most issues the github backend fetches when listing (default: 1000)
 	AllowedAuthors     []string // GitHub usernames allowed to create issues (empty = infer from remote URL)
 	Autostart          bool     // Start orchestration when daemon starts
+	DryRun             bool     // Log spawn and merge decisions without creating agents or merging
 	PermissionsChecker string   // Permission checker type: "manual" (default, TUI prompts), "llm" (LLM-based)
 	AgentBackend       string   // Agent CLI backend: "claude" (default), "codex" - used as fallback if planner/coding not set
 	PlannerBackend     string   // Planner CLI backend: "claude" (default), "codex"
 	CodingBackend      string   // Coding agent CLI backend: "claude" (default), "codex"
 	MergeStrategy      string   // Merge strategy: "direct" (default), "pull-request"
+	DefaultBranch      string   // Branch agents start from and merge into (default: detected at add time, else "main")
+	AutoRebase         *bool    // Retry a merge whose fetch or push failed once, rebasing onto the new main (default: true)
+	PullBeforeSpawn    bool     // Fast-forward the main clone's default branch before creating each agent
+	ReflectClaims      bool     // Label and comment on issues in the backend while agents hold claims
+	AutoClaim          bool     // Claim a ready issue for each agent before spawning it, instead of letting agents pick one
+	AgentNaming        string   // How agents get human-friendly names: "id" (default, none), "animal", "issue"
+	PreMergeCommand    string   // Shell command run in the worktree before merging; non-zero exit blocks the merge
+	PreMergeTimeout    string   // Timeout for PreMergeCommand as a duration string (default: 10m)
+	PollInterval       string   // How often the orchestrator checks for ready issues as a duration string (default: 10s)
+	ActiveHours        string   // Weekly window orchestration runs in, e.g. "09:00-18:00 Mon-Fri America/New_York" (empty = always)
+	GitAuthorName      string   // Name on commits fab makes when merging (default: "fab-bot")
+	GitAuthorEmail     string   // Email on commits fab makes when merging (default: "fab-bot@localhost")
+	WorktreePoolSize   int      // Idle worktrees kept checked out for new agents while orchestration runs (default: 0, no pool)
+	Model              string   // Model passed to the agent CLI (default: the CLI's own default)
+	HighPriorityModel  string   // Model for agents spawned for issues at or above PriorityThreshold (default: Model)
 	BaseDir            string   // Base directory for project storage (default: ~/.fab/projects)
 	// Defaults provides global default values for configuration.
 	// When set, getters use config precedence: project -> global -> internal.
@@ -56,9 +83,20 @@ type Project struct {
 	Running bool // Whether orchestration is active
 	// +checklocks:mu
 	Worktrees []Worktree // Active worktrees for agents
-
-	mu      sync.RWMutex // Protects Running and Worktrees
-	mergeMu sync.Mutex   // Serializes merge operations
+	// +checklocks:mu
+	pool []string // Paths of idle pooled worktrees, see pool.go
+	// +checklocks:mu
+	poolTaking []string // Pooled worktrees being moved to an agent's path
+	// +checklocks:mu
+	poolOpen bool // Whether the worktree pool is being kept filled
+
+	mu         sync.RWMutex // Protects Running, Worktrees, and the pool
+	poolMu     sync.Mutex   // Serializes adding and removing pooled worktrees
+	poolSeq    int          // Last pool worktree number handed out; guarded by poolMu
+	mergeMu    sync.Mutex   // Serializes merge operations
+	stashMu    sync.Mutex   // Serializes stash operations (refs/stash is shared by all worktrees)
+	notesMu    sync.Mutex   // Serializes appends to the shared notes file
+	timelineMu sync.Mutex   // Serializes appends to the activity timeline
 }
 
 // AddWorktree appends a worktree to the list (for testing).
@@ -90,6 +128,9 @@ type Worktree struct {
 	Path    string // Absolute path (e.g., "~/.fab/projects/myapp/worktrees/wt-001")
 	InUse   bool   // Whether assigned to an agent
 	AgentID string // Agent ID if in use (empty if available)
+	// Priority marks a worktree in a reserved high-priority slot, which
+	// doesn't count against MaxAgents.
+	Priority bool
 }
 
 // NewProject creates a new Project with default settings.
@@ -151,41 +192,66 @@ func (p *Project) IssuesDir() string {
 
 // CreateWorktreeForAgent creates a dedicated worktree for an agent.
 // The worktree is named wt-{agentID} and checked out on a fab/{agentID} branch.
-// Returns ErrNoWorktreeAvailable if MaxAgents is reached.
-func (p *Project) CreateWorktreeForAgent(agentID string) (*Worktree, error) {
+// A worktree is taken from the pool when one is ready, and the pool refilled
+// in the background. Pooled worktrees were fetched and reset when they were
+// pooled, so they start from the default branch as of the last fill; a new
+// worktree fetches first.
+// If priority is set, the agent takes one of the ReservedSlots above
+// MaxAgents; only the orchestrator's high-priority lane should ask for one.
+// Returns ErrNoWorktreeAvailable if MaxAgents is reached, or with priority,
+// MaxAgents plus ReservedSlots.
+func (p *Project) CreateWorktreeForAgent(agentID string, priority bool) (*Worktree, error) {
 	p.mu.Lock()
-	defer p.mu.Unlock()
 
-	// Check capacity
-	if len(p.Worktrees) >= p.MaxAgents {
+	if !p.hasSlot(priority) {
+		p.mu.Unlock()
 		return nil, ErrNoWorktreeAvailable
 	}
 
 	// Create worktree path
 	wtPath := p.worktreePathForAgent(agentID)
+	pooled := p.popPooledWorktree()
 
-	// Create the git worktree
-	if err := p.createWorktree(wtPath); err != nil {
-		return nil, err
+	// Hold the slot while git runs unlocked, so concurrent spawns see it
+	wt := Worktree{
+		Path:     wtPath,
+		InUse:    true,
+		AgentID:  agentID,
+		Priority: priority,
+	}
+	p.Worktrees = append(p.Worktrees, wt)
+	p.mu.Unlock()
+
+	// Take the pooled worktree if there is one, otherwise create the git worktree
+	if pooled != "" && p.movePooledWorktree(pooled, wtPath) {
+		go func() {
+			defer logging.LogPanic("worktree-pool-fill", nil)
+			if err := p.FillWorktreePool(); err != nil {
+				slog.Warn("failed to refill worktree pool", "project", p.Name, "error", err)
+			}
+		}()
+		// Already fetched and reset when pooled; just make sure it's clean
+		_ = p.resetToBase(wtPath)
+	} else {
+		if err := p.createWorktree(wtPath); err != nil {
+			p.mu.Lock()
+			p.Worktrees = slices.DeleteFunc(p.Worktrees, func(w Worktree) bool { return w.AgentID == agentID })
+			p.mu.Unlock()
+			return nil, err
+		}
+		// Reset worktree to pristine state (origin/<default branch>)
+		_ = p.resetWorktreeUnlocked(wtPath)
 	}
 
-	// Reset worktree to pristine state (origin/main)
-	_ = p.resetWorktree(wtPath)
 	// Create a branch for this agent's work
 	_ = p.createAgentBranch(wtPath, agentID)
 
-	wt := Worktree{
-		Path:    wtPath,
-		InUse:   true,
-		AgentID: agentID,
-	}
-	p.Worktrees = append(p.Worktrees, wt)
-
 	return &wt, nil
 }
 
 // DeleteWorktreeForAgent removes an agent's worktree from disk and the tracking list.
-// Returns ErrWorktreeNotFound if no worktree is assigned to that agent.
+// While the worktree pool is open and has room, the worktree is reset and
+// returned to the pool instead. Returns ErrWorktreeNotFound if no worktree is assigned to that agent.
 func (p *Project) DeleteWorktreeForAgent(agentID string) error {
 	p.mu.Lock()
 
@@ -208,7 +274,10 @@ func (p *Project) DeleteWorktreeForAgent(agentID string) error {
 	p.Worktrees = append(p.Worktrees[:wtIndex], p.Worktrees[wtIndex+1:]...)
 	p.mu.Unlock()
 
-	// Delete the worktree from disk outside the lock
+	// Recycle or delete the worktree outside the lock
+	if p.recycleWorktree(wtPath) {
+		return nil
+	}
 	return p.removeWorktree(wtPath)
 }
 
@@ -226,7 +295,67 @@ func (p *Project) AvailableWorktreeCount() int {
 	return count
 }
 
+// DefaultPriorityThreshold is the internal default minimum priority for reserved slots.
+const DefaultPriorityThreshold = 2
+
+// GetPriorityThreshold returns the minimum issue priority that may use reserved slots.
+func (p *Project) GetPriorityThreshold() int {
+	if p.PriorityThreshold > 0 {
+		return p.PriorityThreshold
+	}
+	return DefaultPriorityThreshold
+}
+
+// DefaultGitHubIssueLimit is the internal default for how many issues the
+// github backend fetches when listing.
+const DefaultGitHubIssueLimit = 1000
+
+// GetGitHubIssueLimit returns the most issues the github backend fetches
+// when listing, so large backlogs are read page by page up to a bound.
+func (p *Project) GetGitHubIssueLimit() int {
+	if p.GitHubIssueLimit > 0 {
+		return p.GitHubIssueLimit
+	}
+	return DefaultGitHubIssueLimit
+}
+
+// GetWorktreePoolSize returns how many idle worktrees to keep ready for new
+// agents, or 0 if worktrees are created on demand.
+func (p *Project) GetWorktreePoolSize() int {
+	return max(p.WorktreePoolSize, 0)
+}
+
+// ModelForPriority returns the model for an agent working on an issue of the
+// given priority. Issues at or above the priority threshold use
+// HighPriorityModel when one is configured; everything else uses Model.
+// An empty result leaves the choice to the agent CLI.
+func (p *Project) ModelForPriority(priority int) string {
+	if p.HighPriorityModel != "" && priority >= p.GetPriorityThreshold() {
+		return p.HighPriorityModel
+	}
+	return p.Model
+}
+
+// hasSlot reports whether another agent fits. Normal agents are limited to
+// MaxAgents, not counting those in reserved slots; a priority agent may
+// also use the ReservedSlots, up to MaxAgents plus ReservedSlots in total.
+//
+// +checklocks:p.mu
+func (p *Project) hasSlot(priority bool) bool {
+	if priority {
+		return len(p.Worktrees) < p.MaxAgents+max(p.ReservedSlots, 0)
+	}
+	normal := 0
+	for _, wt := range p.Worktrees {
+		if !wt.Priority {
+			normal++
+		}
+	}
+	return normal < p.MaxAgents
+}
+
 // ActiveAgentCount returns the number of agents currently using worktrees.
+// This includes agents running in reserved high-priority slots, so it may exceed MaxAgents.
 func (p *Project) ActiveAgentCount() int {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
@@ -318,6 +447,119 @@ func (p *Project) GetMergeStrategy() string {
 	return DefaultMergeStrategy
 }
 
+// DefaultBranchName is the internal default base branch.
+const DefaultBranchName = "main"
+
+// GetDefaultBranch returns the branch agents start from and merge into.
+func (p *Project) GetDefaultBranch() string {
+	if p.DefaultBranch != "" {
+		return p.DefaultBranch
+	}
+	return DefaultBranchName
+}
+
+// DefaultAutoRebase is the internal default for auto-rebase.
+const DefaultAutoRebase = true
+
+// GetAutoRebase returns whether a merge whose fetch or push failed should be
+// retried, rebasing onto the latest default branch.
+func (p *Project) GetAutoRebase() bool {
+	if p.AutoRebase != nil {
+		return *p.AutoRebase
+	}
+	return DefaultAutoRebase
+}
+
+// DefaultAgentNaming is the internal default for agent-naming: agents are
+// shown by ID only.
+const DefaultAgentNaming = "id"
+
+// GetAgentNaming returns how agents in this project are named.
+func (p *Project) GetAgentNaming() string {
+	if p.AgentNaming != "" {
+		return p.AgentNaming
+	}
+	return DefaultAgentNaming
+}
+
+// GetPreMergeCommand returns the shell command that must pass before an
+// agent's work is merged, or "" if there is no pre-merge gate.
+func (p *Project) GetPreMergeCommand() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.PreMergeCommand
+}
+
+// SetPreMergeCommand sets the pre-merge command; "" disables the gate.
+func (p *Project) SetPreMergeCommand(command string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.PreMergeCommand = command
+}
+
+// DefaultPreMergeTimeout is the internal default timeout for the pre-merge command.
+const DefaultPreMergeTimeout = 10 * time.Minute
+
+// GetPreMergeTimeout returns how long the pre-merge command may run.
+// Falls back to DefaultPreMergeTimeout if unset or unparseable.
+func (p *Project) GetPreMergeTimeout() time.Duration {
+	if p.PreMergeTimeout != "" {
+		if d, err := time.ParseDuration(p.PreMergeTimeout); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultPreMergeTimeout
+}
+
+// DefaultPollInterval is the internal default interval between checks for
+// ready issues.
+const DefaultPollInterval = 10 * time.Second
+
+// GetPollInterval returns how often the orchestrator checks for ready issues.
+// Uses config precedence: project -> global defaults -> internal defaults.
+// An unparseable project value is ignored.
+func (p *Project) GetPollInterval() time.Duration {
+	if p.PollInterval != "" {
+		if d, err := time.ParseDuration(p.PollInterval); err == nil && d > 0 {
+			return d
+		}
+	}
+	if p.Defaults != nil {
+		return p.Defaults.GetDefaultPollInterval()
+	}
+	return DefaultPollInterval
+}
+
+// Internal defaults for the identity of commits fab makes.
+const (
+	DefaultGitAuthorName  = "fab-bot"
+	DefaultGitAuthorEmail = "fab-bot@localhost"
+)
+
+// GetGitAuthorName returns the name on commits fab makes when merging.
+// Uses config precedence: project -> global defaults -> internal defaults.
+func (p *Project) GetGitAuthorName() string {
+	if p.GitAuthorName != "" {
+		return p.GitAuthorName
+	}
+	if p.Defaults != nil {
+		return p.Defaults.GetDefaultGitAuthorName()
+	}
+	return DefaultGitAuthorName
+}
+
+// GetGitAuthorEmail returns the email on commits fab makes when merging.
+// Uses config precedence: project -> global defaults -> internal defaults.
+func (p *Project) GetGitAuthorEmail() string {
+	if p.GitAuthorEmail != "" {
+		return p.GitAuthorEmail
+	}
+	if p.Defaults != nil {
+		return p.Defaults.GetDefaultGitAuthorEmail()
+	}
+	return DefaultGitAuthorEmail
+}
+
 // DefaultIssueBackend is the internal default issue backend.
 const DefaultIssueBackend = "tk"
 
@@ -399,7 +641,7 @@ func (p *Project) CreatePlannerWorktree(plannerID string) (string, error) {
 		return "", err
 	}
 
-	// Reset to pristine state (planners work off origin/main)
+	// Reset to pristine state (planners work off the default branch)
 	_ = p.resetWorktreeUnlocked(wtPath)
 
 	return wtPath, nil
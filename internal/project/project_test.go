@@ -1,9 +1,11 @@
 package project
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestNewProject(t *testing.T) {
@@ -30,7 +32,7 @@ func TestCreateWorktreeForAgent_Success(t *testing.T) {
 	p := NewProject("test", "")
 	p.MaxAgents = 3
 
-	wt, err := p.CreateWorktreeForAgent("agent1")
+	wt, err := p.CreateWorktreeForAgent("agent1", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -62,7 +64,7 @@ func TestCreateWorktreeForAgent_MaxAgentsReached(t *testing.T) {
 		{Path: "/tmp/wt-agent1", InUse: true, AgentID: "agent1"},
 	}
 
-	_, err := p.CreateWorktreeForAgent("agent2")
+	_, err := p.CreateWorktreeForAgent("agent2", false)
 	if err != ErrNoWorktreeAvailable {
 		t.Errorf("err = %v, want ErrNoWorktreeAvailable", err)
 	}
@@ -108,7 +110,7 @@ func TestWorktreeLifecycle(t *testing.T) {
 	p.MaxAgents = 2
 
 	// Create first worktree
-	wt1, err := p.CreateWorktreeForAgent("agent1")
+	wt1, err := p.CreateWorktreeForAgent("agent1", false)
 	if err != nil {
 		t.Fatalf("create agent1: %v", err)
 	}
@@ -117,7 +119,7 @@ func TestWorktreeLifecycle(t *testing.T) {
 	}
 
 	// Create second worktree
-	wt2, err := p.CreateWorktreeForAgent("agent2")
+	wt2, err := p.CreateWorktreeForAgent("agent2", false)
 	if err != nil {
 		t.Fatalf("create agent2: %v", err)
 	}
@@ -126,7 +128,7 @@ func TestWorktreeLifecycle(t *testing.T) {
 	}
 
 	// Should be at capacity
-	_, err = p.CreateWorktreeForAgent("agent3")
+	_, err = p.CreateWorktreeForAgent("agent3", false)
 	if err != ErrNoWorktreeAvailable {
 		t.Errorf("create agent3: err = %v, want ErrNoWorktreeAvailable", err)
 	}
@@ -137,7 +139,7 @@ func TestWorktreeLifecycle(t *testing.T) {
 	}
 
 	// Now we can create again
-	wt3, err := p.CreateWorktreeForAgent("agent3")
+	wt3, err := p.CreateWorktreeForAgent("agent3", false)
 	if err != nil {
 		t.Fatalf("create agent3 after delete: %v", err)
 	}
@@ -453,21 +455,27 @@ type mockDefaults struct {
 	mergeStrategy      string
 	issueBackend       string
 	permissionsChecker string
+	pollInterval       time.Duration
+	gitAuthorName      string
+	gitAuthorEmail     string
 }
 
-func (m *mockDefaults) GetDefaultAgentBackend() string       { return m.agentBackend }
-func (m *mockDefaults) GetDefaultPlannerBackend() string     { return m.plannerBackend }
-func (m *mockDefaults) GetDefaultCodingBackend() string      { return m.codingBackend }
-func (m *mockDefaults) GetDefaultMergeStrategy() string      { return m.mergeStrategy }
-func (m *mockDefaults) GetDefaultIssueBackend() string       { return m.issueBackend }
-func (m *mockDefaults) GetDefaultPermissionsChecker() string { return m.permissionsChecker }
+func (m *mockDefaults) GetDefaultAgentBackend() string        { return m.agentBackend }
+func (m *mockDefaults) GetDefaultPlannerBackend() string      { return m.plannerBackend }
+func (m *mockDefaults) GetDefaultCodingBackend() string       { return m.codingBackend }
+func (m *mockDefaults) GetDefaultMergeStrategy() string       { return m.mergeStrategy }
+func (m *mockDefaults) GetDefaultIssueBackend() string        { return m.issueBackend }
+func (m *mockDefaults) GetDefaultPermissionsChecker() string  { return m.permissionsChecker }
+func (m *mockDefaults) GetDefaultPollInterval() time.Duration { return m.pollInterval }
+func (m *mockDefaults) GetDefaultGitAuthorName() string       { return m.gitAuthorName }
+func (m *mockDefaults) GetDefaultGitAuthorEmail() string      { return m.gitAuthorEmail }
 
 func TestGetAgentBackendWithDefaults(t *testing.T) {
 	tests := []struct {
-		name            string
-		agentBackend    string
-		defaultBackend  string
-		want            string
+		name           string
+		agentBackend   string
+		defaultBackend string
+		want           string
 	}{
 		{
 			name:           "project value takes precedence",
@@ -625,3 +633,209 @@ func TestGetMergeStrategyWithDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAutoRebase(t *testing.T) {
+	enabled, disabled := true, false
+	tests := []struct {
+		name       string
+		autoRebase *bool
+		want       bool
+	}{
+		{name: "defaults to enabled", autoRebase: nil, want: DefaultAutoRebase},
+		{name: "explicitly enabled", autoRebase: &enabled, want: true},
+		{name: "explicitly disabled", autoRebase: &disabled, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProject("test", "")
+			p.AutoRebase = tt.autoRebase
+			if got := p.GetAutoRebase(); got != tt.want {
+				t.Errorf("GetAutoRebase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPreMergeTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout string
+		want    time.Duration
+	}{
+		{name: "defaults when unset", timeout: "", want: DefaultPreMergeTimeout},
+		{name: "parses duration", timeout: "90s", want: 90 * time.Second},
+		{name: "falls back on invalid", timeout: "soon", want: DefaultPreMergeTimeout},
+		{name: "falls back on non-positive", timeout: "0s", want: DefaultPreMergeTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProject("test", "")
+			p.PreMergeTimeout = tt.timeout
+			if got := p.GetPreMergeTimeout(); got != tt.want {
+				t.Errorf("GetPreMergeTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPollInterval(t *testing.T) {
+	p := NewProject("test", "")
+	if got := p.GetPollInterval(); got != DefaultPollInterval {
+		t.Errorf("GetPollInterval() = %v, want %v", got, DefaultPollInterval)
+	}
+
+	p.Defaults = &mockDefaults{pollInterval: time.Minute}
+	if got := p.GetPollInterval(); got != time.Minute {
+		t.Errorf("GetPollInterval() = %v, want the global default of 1m", got)
+	}
+
+	p.PollInterval = "5s"
+	if got := p.GetPollInterval(); got != 5*time.Second {
+		t.Errorf("GetPollInterval() = %v, want the project's 5s", got)
+	}
+
+	p.PollInterval = "often"
+	if got := p.GetPollInterval(); got != time.Minute {
+		t.Errorf("GetPollInterval() = %v, want an invalid value to fall back to 1m", got)
+	}
+}
+
+func TestRunPreMergeCommand(t *testing.T) {
+	wtPath := t.TempDir()
+	p := NewProject("test", "")
+	p.Worktrees = []Worktree{{Path: wtPath, AgentID: "agent1", InUse: true}}
+
+	t.Run("no command configured", func(t *testing.T) {
+		res, err := p.RunPreMergeCommand(context.Background(), "agent1", "", nil)
+		if err != nil || res != nil {
+			t.Fatalf("RunPreMergeCommand() = %v, %v; want nil, nil", res, err)
+		}
+	})
+
+	t.Run("passing command streams output", func(t *testing.T) {
+		var lines []string
+		res, err := p.RunPreMergeCommand(context.Background(), "agent1", "echo one; echo two >&2", func(line string) {
+			lines = append(lines, line)
+		})
+		if err != nil {
+			t.Fatalf("RunPreMergeCommand() error = %v", err)
+		}
+		if !res.Passed {
+			t.Errorf("Passed = false, want true (output: %q)", res.Output)
+		}
+		if len(lines) != 2 {
+			t.Errorf("got %d lines, want 2: %q", len(lines), lines)
+		}
+	})
+
+	t.Run("runs in worktree", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(wtPath, "marker"), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		res, err := p.RunPreMergeCommand(context.Background(), "agent1", "test -f marker", nil)
+		if err != nil {
+			t.Fatalf("RunPreMergeCommand() error = %v", err)
+		}
+		if !res.Passed {
+			t.Error("Passed = false, want true")
+		}
+	})
+
+	t.Run("failing command", func(t *testing.T) {
+		res, err := p.RunPreMergeCommand(context.Background(), "agent1", "echo broken; exit 3", nil)
+		if err != nil {
+			t.Fatalf("RunPreMergeCommand() error = %v", err)
+		}
+		if res.Passed {
+			t.Error("Passed = true, want false")
+		}
+		if res.Output != "broken\n" {
+			t.Errorf("Output = %q, want %q", res.Output, "broken\n")
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		p.PreMergeTimeout = "100ms"
+		res, err := p.RunPreMergeCommand(context.Background(), "agent1", "sleep 5", nil)
+		if err != nil {
+			t.Fatalf("RunPreMergeCommand() error = %v", err)
+		}
+		if res.Passed || !res.TimedOut {
+			t.Errorf("Passed = %v, TimedOut = %v; want false, true", res.Passed, res.TimedOut)
+		}
+	})
+
+	t.Run("unknown agent", func(t *testing.T) {
+		if _, err := p.RunPreMergeCommand(context.Background(), "nobody", "true", nil); err == nil {
+			t.Error("expected error for agent without worktree")
+		}
+	})
+}
+
+func TestCreateWorktreeForAgent_ReservedSlots(t *testing.T) {
+	p := NewProject("test", "")
+	p.MaxAgents = 1
+	p.ReservedSlots = 1
+	p.Worktrees = []Worktree{
+		{Path: "/tmp/wt-agent1", InUse: true, AgentID: "agent1"},
+	}
+
+	// Normal callers can't take the reserved slot
+	if _, err := p.CreateWorktreeForAgent("agent2", false); err != ErrNoWorktreeAvailable {
+		t.Errorf("normal create: err = %v, want ErrNoWorktreeAvailable", err)
+	}
+
+	// A priority agent can, until the reserved slots are full too
+	p.Worktrees = append(p.Worktrees, Worktree{Path: "/tmp/wt-agent2", InUse: true, AgentID: "agent2", Priority: true})
+	if _, err := p.CreateWorktreeForAgent("agent3", true); err != ErrNoWorktreeAvailable {
+		t.Errorf("priority create past reserved slots: err = %v, want ErrNoWorktreeAvailable", err)
+	}
+
+	// A priority agent doesn't hold a normal slot
+	p.Worktrees = p.Worktrees[1:]
+	p.mu.Lock()
+	ok := p.hasSlot(false)
+	p.mu.Unlock()
+	if !ok {
+		t.Error("hasSlot(false) = false with only a priority agent running")
+	}
+}
+
+func TestGetPriorityThreshold(t *testing.T) {
+	p := NewProject("test", "")
+
+	if got := p.GetPriorityThreshold(); got != DefaultPriorityThreshold {
+		t.Errorf("GetPriorityThreshold() = %d, want %d", got, DefaultPriorityThreshold)
+	}
+	p.PriorityThreshold = 1
+	if got := p.GetPriorityThreshold(); got != 1 {
+		t.Errorf("GetPriorityThreshold() = %d, want 1", got)
+	}
+}
+
+func TestModelForPriority(t *testing.T) {
+	p := NewProject("test", "")
+	if got := p.ModelForPriority(2); got != "" {
+		t.Errorf("ModelForPriority() with nothing configured = %q, want empty", got)
+	}
+
+	p.Model = "sonnet"
+	if got := p.ModelForPriority(2); got != "sonnet" {
+		t.Errorf("ModelForPriority(2) without high-priority model = %q, want sonnet", got)
+	}
+
+	p.HighPriorityModel = "opus"
+	if got := p.ModelForPriority(1); got != "sonnet" {
+		t.Errorf("ModelForPriority(1) = %q, want sonnet", got)
+	}
+	if got := p.ModelForPriority(2); got != "opus" {
+		t.Errorf("ModelForPriority(2) = %q, want opus", got)
+	}
+
+	p.PriorityThreshold = 1
+	if got := p.ModelForPriority(1); got != "opus" {
+		t.Errorf("ModelForPriority(1) with threshold 1 = %q, want opus", got)
+	}
+}
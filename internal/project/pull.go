@@ -0,0 +1,97 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// PullResult describes the outcome of refreshing the project's main clone.
+type PullResult struct {
+	Branch       string // Default branch that was refreshed
+	PreviousSHA  string // HEAD before the pull
+	SHA          string // HEAD after the pull
+	Updated      bool   // True if the branch moved
+	LocalChanges bool   // True if uncommitted changes in the clone prevented the fast-forward
+}
+
+// Pull fetches origin and fast-forwards the default branch in the project's
+// main clone (RepoDir) so it matches the remote.
+// Uncommitted changes in the clone are left alone: the fetch still happens,
+// but the branch isn't moved and the result reports LocalChanges.
+// Returns an error if the local branch has diverged from the remote.
+func (p *Project) Pull() (*PullResult, error) {
+	p.mergeMu.Lock()
+	defer p.mergeMu.Unlock()
+
+	repoDir := p.RepoDir()
+	baseBranch := p.GetDefaultBranch()
+	if _, err := os.Stat(filepath.Join(repoDir, ".git")); os.IsNotExist(err) {
+		return nil, fmt.Errorf("repo not found: %s", repoDir)
+	}
+
+	fetchCmd := exec.Command("git", "fetch", "origin")
+	fetchCmd.Dir = repoDir
+	if output, err := fetchCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("fetch: %w\n%s", err, output)
+	}
+
+	result := &PullResult{Branch: baseBranch}
+
+	// Untracked files are ignored here; the fast-forward fails on its own if
+	// it would overwrite one.
+	statusCmd := exec.Command("git", "status", "--porcelain", "--untracked-files=no")
+	statusCmd.Dir = repoDir
+	status, err := statusCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status: %w", err)
+	}
+	if len(strings.TrimSpace(string(status))) > 0 {
+		sha, err := headSHA(repoDir)
+		if err != nil {
+			return nil, err
+		}
+		result.PreviousSHA = sha
+		result.SHA = sha
+		result.LocalChanges = true
+		return result, nil
+	}
+
+	if err := checkoutBranch(repoDir, baseBranch); err != nil {
+		return nil, err
+	}
+
+	previous, err := headSHA(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	result.PreviousSHA = previous
+
+	ffCmd := exec.Command("git", "merge", "--ff-only", p.remoteBaseRef())
+	ffCmd.Dir = repoDir
+	if output, err := ffCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("fast-forward %s: %w\n%s", baseBranch, err, output)
+	}
+
+	sha, err := headSHA(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	result.SHA = sha
+	result.Updated = sha != previous
+
+	return result, nil
+}
+
+// headSHA returns the commit checked out in dir.
+func headSHA(dir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("resolve HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
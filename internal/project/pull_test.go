@@ -0,0 +1,76 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// pushUpstreamCommit commits a file to the remote's default branch from a
+// separate clone and returns the new commit.
+func pushUpstreamCommit(t *testing.T, remote, branch string) string {
+	t.Helper()
+	other := filepath.Join(t.TempDir(), "other")
+	git(t, filepath.Dir(other), "clone", remote, other)
+	git(t, other, "config", "user.email", "test@example.com")
+	git(t, other, "config", "user.name", "Test User")
+	sha := commitFile(t, other, "upstream.txt", "new\n")
+	git(t, other, "push", "origin", branch)
+	return sha
+}
+
+func TestPull_FastForwards(t *testing.T) {
+	p, remote := setupClonedProject(t, "main")
+	repo := p.RepoDir()
+	before := git(t, repo, "rev-parse", "HEAD")
+
+	upstream := pushUpstreamCommit(t, remote, "main")
+
+	result, err := p.Pull()
+	if err != nil {
+		t.Fatalf("Pull() error = %v", err)
+	}
+	if !result.Updated || result.PreviousSHA != before || result.SHA != upstream {
+		t.Errorf("Pull() = %+v, want update from %s to %s", result, before, upstream)
+	}
+	if got := git(t, repo, "rev-parse", "HEAD"); got != upstream {
+		t.Errorf("HEAD = %s, want %s", got, upstream)
+	}
+
+	// Pulling again is a no-op
+	result, err = p.Pull()
+	if err != nil {
+		t.Fatalf("second Pull() error = %v", err)
+	}
+	if result.Updated {
+		t.Error("second Pull() reported an update")
+	}
+}
+
+func TestPull_LocalChanges(t *testing.T) {
+	p, remote := setupClonedProject(t, "main")
+	repo := p.RepoDir()
+	before := git(t, repo, "rev-parse", "HEAD")
+
+	pushUpstreamCommit(t, remote, "main")
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte("# Edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := p.Pull()
+	if err != nil {
+		t.Fatalf("Pull() error = %v", err)
+	}
+	if !result.LocalChanges || result.Updated || result.SHA != before {
+		t.Errorf("Pull() = %+v, want local changes reported and HEAD left at %s", result, before)
+	}
+
+	// The edit survives
+	data, err := os.ReadFile(filepath.Join(repo, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "# Edited\n" {
+		t.Errorf("README.md = %q, local edit was lost", data)
+	}
+}
@@ -0,0 +1,123 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// MergeRecovery describes how an interrupted merge was resolved.
+type MergeRecovery string
+
+const (
+	// MergeRecoveryCompleted means the merge reached the remote default branch, either
+	// before the interruption or by being pushed during recovery.
+	MergeRecoveryCompleted MergeRecovery = "completed"
+
+	// MergeRecoveryRolledBack means partial state (an in-progress rebase or
+	// an unpushed local default branch) was undone. The agent must retry the merge.
+	MergeRecoveryRolledBack MergeRecovery = "rolled-back"
+)
+
+// RecoverMerge reconciles git state after a merge of the agent's branch was
+// interrupted (e.g. the daemon died inside MergeAgentBranch).
+// targetSHA is the rebased branch tip recorded before main was moved, or
+// empty if the interruption happened before the rebase finished.
+//
+// Recovery completes the merge when it is safe to (the target already reached
+// the remote default branch, or the local one has it and can still be pushed), and
+// otherwise rolls back: an in-progress rebase is aborted and the local default
+// branch is reset to its remote tip.
+func (p *Project) RecoverMerge(agentID, targetSHA string) (MergeRecovery, error) {
+	p.mergeMu.Lock()
+	defer p.mergeMu.Unlock()
+
+	repoDir := p.RepoDir()
+	baseBranch := p.GetDefaultBranch()
+	baseRef := p.remoteBaseRef()
+	if _, err := os.Stat(filepath.Join(repoDir, ".git")); os.IsNotExist(err) {
+		return "", fmt.Errorf("repo not found: %s", repoDir)
+	}
+
+	// The worktree may not be registered yet if the agent hasn't been rehydrated,
+	// so fall back to the deterministic path.
+	wtPath := p.getWorktreePathForAgent(agentID)
+	if wtPath == "" {
+		wtPath = p.worktreePathForAgent(agentID)
+	}
+	if _, err := os.Stat(wtPath); err == nil && rebaseInProgress(wtPath) {
+		abortCmd := exec.Command("git", "rebase", "--abort")
+		abortCmd.Dir = wtPath
+		if output, err := abortCmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("abort rebase: %w\n%s", err, output)
+		}
+	}
+
+	fetchCmd := exec.Command("git", "fetch", "origin")
+	fetchCmd.Dir = repoDir
+	if output, err := fetchCmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("fetch: %w\n%s", err, output)
+	}
+
+	if err := checkoutBranch(repoDir, baseBranch); err != nil {
+		return "", err
+	}
+
+	if targetSHA != "" {
+		// Push landed before the interruption: just bring the local branch up to date
+		if isAncestor(repoDir, targetSHA, baseRef) {
+			syncCmd := exec.Command("git", p.withGitIdentity("merge", "--ff-only", baseRef)...)
+			syncCmd.Dir = repoDir
+			// Best-effort - the local branch is only a staging ref for the next merge
+			_ = syncCmd.Run()
+			return MergeRecoveryCompleted, nil
+		}
+
+		// Fast-forwarded locally but never pushed: finish the push
+		if isAncestor(repoDir, targetSHA, baseBranch) {
+			pushCmd := exec.Command("git", "push", "origin", baseBranch)
+			pushCmd.Dir = repoDir
+			if err := pushCmd.Run(); err == nil {
+				return MergeRecoveryCompleted, nil
+			}
+		}
+	}
+
+	// Roll back any local movement so the next merge starts clean
+	resetCmd := exec.Command("git", "reset", "--hard", baseRef)
+	resetCmd.Dir = repoDir
+	if output, err := resetCmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("reset %s: %w\n%s", baseBranch, err, output)
+	}
+
+	return MergeRecoveryRolledBack, nil
+}
+
+// rebaseInProgress reports whether a rebase is underway in the given worktree.
+func rebaseInProgress(wtPath string) bool {
+	for _, name := range []string{"rebase-merge", "rebase-apply"} {
+		cmd := exec.Command("git", "rev-parse", "--git-path", name)
+		cmd.Dir = wtPath
+		output, err := cmd.Output()
+		if err != nil {
+			continue
+		}
+		path := strings.TrimSpace(string(output))
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(wtPath, path)
+		}
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isAncestor reports whether commit is reachable from ref in repoDir.
+func isAncestor(repoDir, commit, ref string) bool {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", commit, ref)
+	cmd.Dir = repoDir
+	return cmd.Run() == nil
+}
@@ -0,0 +1,179 @@
+package project
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// git runs a git command in dir and returns trimmed stdout, failing the test on error.
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// setupClonedProject creates a bare remote whose default branch is branch
+// and clones it to the project's repo dir. Returns the project and remote path.
+func setupClonedProject(t *testing.T, branch string) (*Project, string) {
+	t.Helper()
+	base := t.TempDir()
+
+	remote := filepath.Join(base, "remote.git")
+	git(t, base, "init", "--bare", "-b", branch, remote)
+
+	seed := filepath.Join(base, "seed")
+	git(t, base, "init", "-b", branch, seed)
+	git(t, seed, "config", "user.email", "test@example.com")
+	git(t, seed, "config", "user.name", "Test User")
+	if err := os.WriteFile(filepath.Join(seed, "README.md"), []byte("# Test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, seed, "add", ".")
+	git(t, seed, "commit", "-m", "Initial commit")
+	git(t, seed, "remote", "add", "origin", remote)
+	git(t, seed, "push", "-u", "origin", branch)
+
+	p := NewProject("test", "file://"+remote)
+	p.BaseDir = filepath.Join(base, "projects")
+	repo := p.RepoDir()
+	if err := os.MkdirAll(filepath.Dir(repo), 0755); err != nil {
+		t.Fatal(err)
+	}
+	git(t, base, "clone", remote, repo)
+	git(t, repo, "config", "user.email", "test@example.com")
+	git(t, repo, "config", "user.name", "Test User")
+
+	return p, remote
+}
+
+// setupMergeProject creates a cloned project (see setupClonedProject) and adds
+// a worktree on fab/<agentID>. Returns the project, the remote path, and the
+// agent's worktree path.
+func setupMergeProject(t *testing.T, branch, agentID string) (*Project, string, string) {
+	t.Helper()
+	p, remote := setupClonedProject(t, branch)
+	repo := p.RepoDir()
+
+	wtPath := p.worktreePathForAgent(agentID)
+	git(t, repo, "worktree", "add", "-b", "fab/"+agentID, wtPath)
+	p.Worktrees = []Worktree{{Path: wtPath, InUse: true, AgentID: agentID}}
+
+	return p, remote, wtPath
+}
+
+// commitFile writes content to name in dir and commits it, returning the new HEAD.
+func commitFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, dir, "add", name)
+	git(t, dir, "commit", "-m", "Update "+name)
+	return git(t, dir, "rev-parse", "HEAD")
+}
+
+func TestRecoverMerge_PushedBeforeCrash(t *testing.T) {
+	p, _, wtPath := setupMergeProject(t, "main", "agent1")
+	repo := p.RepoDir()
+
+	sha := commitFile(t, wtPath, "feature.txt", "done\n")
+	git(t, repo, "merge", "--ff-only", "fab/agent1")
+	git(t, repo, "push", "origin", "main")
+
+	outcome, err := p.RecoverMerge("agent1", sha)
+	if err != nil {
+		t.Fatalf("RecoverMerge() error = %v", err)
+	}
+	if outcome != MergeRecoveryCompleted {
+		t.Errorf("outcome = %q, want %q", outcome, MergeRecoveryCompleted)
+	}
+}
+
+func TestRecoverMerge_PushesUnpushedMain(t *testing.T) {
+	p, remote, wtPath := setupMergeProject(t, "main", "agent1")
+	repo := p.RepoDir()
+
+	// Crash between fast-forwarding local main and pushing it
+	sha := commitFile(t, wtPath, "feature.txt", "done\n")
+	git(t, repo, "merge", "--ff-only", "fab/agent1")
+
+	outcome, err := p.RecoverMerge("agent1", sha)
+	if err != nil {
+		t.Fatalf("RecoverMerge() error = %v", err)
+	}
+	if outcome != MergeRecoveryCompleted {
+		t.Errorf("outcome = %q, want %q", outcome, MergeRecoveryCompleted)
+	}
+	if got := git(t, remote, "rev-parse", "main"); got != sha {
+		t.Errorf("remote main = %s, want %s", got, sha)
+	}
+}
+
+func TestRecoverMerge_RollsBackWhenPushRejected(t *testing.T) {
+	p, remote, wtPath := setupMergeProject(t, "main", "agent1")
+	repo := p.RepoDir()
+
+	sha := commitFile(t, wtPath, "feature.txt", "done\n")
+	git(t, repo, "merge", "--ff-only", "fab/agent1")
+
+	// Someone else pushed to main in the meantime, so the push is rejected
+	other := filepath.Join(t.TempDir(), "other")
+	git(t, filepath.Dir(other), "clone", remote, other)
+	git(t, other, "config", "user.email", "test@example.com")
+	git(t, other, "config", "user.name", "Test User")
+	otherSHA := commitFile(t, other, "other.txt", "other\n")
+	git(t, other, "push", "origin", "main")
+
+	outcome, err := p.RecoverMerge("agent1", sha)
+	if err != nil {
+		t.Fatalf("RecoverMerge() error = %v", err)
+	}
+	if outcome != MergeRecoveryRolledBack {
+		t.Errorf("outcome = %q, want %q", outcome, MergeRecoveryRolledBack)
+	}
+	if got := git(t, repo, "rev-parse", "main"); got != otherSHA {
+		t.Errorf("local main = %s, want origin/main %s", got, otherSHA)
+	}
+}
+
+func TestRecoverMerge_AbortsRebaseInProgress(t *testing.T) {
+	p, remote, wtPath := setupMergeProject(t, "main", "agent1")
+
+	// Conflicting change on main so the agent's rebase stops midway
+	other := filepath.Join(t.TempDir(), "other")
+	git(t, filepath.Dir(other), "clone", remote, other)
+	git(t, other, "config", "user.email", "test@example.com")
+	git(t, other, "config", "user.name", "Test User")
+	commitFile(t, other, "README.md", "theirs\n")
+	git(t, other, "push", "origin", "main")
+
+	commitFile(t, wtPath, "README.md", "ours\n")
+	git(t, p.RepoDir(), "fetch", "origin")
+	cmd := exec.Command("git", "rebase", "origin/main")
+	cmd.Dir = wtPath
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected rebase conflict")
+	}
+	if !rebaseInProgress(wtPath) {
+		t.Fatal("expected rebase to be in progress")
+	}
+
+	outcome, err := p.RecoverMerge("agent1", "")
+	if err != nil {
+		t.Fatalf("RecoverMerge() error = %v", err)
+	}
+	if outcome != MergeRecoveryRolledBack {
+		t.Errorf("outcome = %q, want %q", outcome, MergeRecoveryRolledBack)
+	}
+	if rebaseInProgress(wtPath) {
+		t.Error("rebase still in progress after recovery")
+	}
+}
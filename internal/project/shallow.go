@@ -0,0 +1,36 @@
+package project
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// isShallow reports whether the project's main clone has truncated history,
+// as left by a project.add with a clone depth.
+func (p *Project) isShallow() (bool, error) {
+	cmd := exec.Command("git", "rev-parse", "--is-shallow-repository")
+	cmd.Dir = p.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("git rev-parse --is-shallow-repository: %w", err)
+	}
+	return strings.TrimSpace(string(out)) == "true", nil
+}
+
+// unshallow fetches the full history of a shallow clone. Rebasing an agent's
+// branch needs the merge base, which a shallow clone may not have, so merges
+// call this first; after the first merge it's a cheap no-op.
+// Caller must hold mergeMu.
+func (p *Project) unshallow() error {
+	shallow, err := p.isShallow()
+	if err != nil || !shallow {
+		return err
+	}
+	cmd := exec.Command("git", "fetch", "--unshallow", "origin")
+	cmd.Dir = p.RepoDir()
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("unshallow: %w\n%s", err, output)
+	}
+	return nil
+}
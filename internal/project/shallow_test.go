@@ -0,0 +1,55 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMergeAgentBranch_UnshallowsClone(t *testing.T) {
+	p, remote := setupClonedProject(t, "main")
+	pushUpstreamCommit(t, remote, "main")
+
+	// Replace the full clone with a shallow one, as project.add does with a depth
+	repo := p.RepoDir()
+	if err := os.RemoveAll(repo); err != nil {
+		t.Fatal(err)
+	}
+	git(t, filepath.Dir(repo), "clone", "--depth", "1", "file://"+remote, repo)
+	git(t, repo, "config", "user.email", "test@example.com")
+	git(t, repo, "config", "user.name", "Test User")
+	if shallow, err := p.isShallow(); err != nil || !shallow {
+		t.Fatalf("isShallow() = %v, %v; want true", shallow, err)
+	}
+
+	wt, err := p.CreateWorktreeForAgent("agent1", false)
+	if err != nil {
+		t.Fatalf("CreateWorktreeForAgent() error = %v", err)
+	}
+	sha := commitFile(t, wt.Path, "feature.txt", "done\n")
+
+	// Move main on so the rebase needs history older than the clone
+	other := filepath.Join(t.TempDir(), "other")
+	git(t, filepath.Dir(other), "clone", remote, other)
+	git(t, other, "config", "user.email", "test@example.com")
+	git(t, other, "config", "user.name", "Test User")
+	commitFile(t, other, "other.txt", "more\n")
+	git(t, other, "push", "origin", "main")
+
+	result, err := p.MergeAgentBranch("agent1", nil)
+	if err != nil {
+		t.Fatalf("MergeAgentBranch() error = %v", err)
+	}
+	if !result.Merged {
+		t.Fatalf("MergeAgentBranch() not merged: %v", result.Error)
+	}
+	if shallow, err := p.isShallow(); err != nil || shallow {
+		t.Errorf("isShallow() = %v, %v after merge; want false", shallow, err)
+	}
+	if got := git(t, repo, "rev-list", "--count", "HEAD"); got != "4" {
+		t.Errorf("history has %s commits, want 4", got)
+	}
+	if got := git(t, remote, "log", "-1", "--format=%s", "main"); got != "Update feature.txt" {
+		t.Errorf("remote main tip = %q, want the rebased %s", got, sha)
+	}
+}
@@ -0,0 +1,162 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrNoStash is returned when there is no fab stash to recover.
+var ErrNoStash = errors.New("no fab stash found")
+
+// abortStashPrefix marks stashes created when an agent is force-aborted.
+// The agent ID follows the prefix so stashes can be matched back to agents.
+const abortStashPrefix = "fab: abort "
+
+// StashRecovery describes a stash that was restored into a branch.
+type StashRecovery struct {
+	AgentID string // Agent whose work was recovered
+	Branch  string // Branch the work was committed to
+	Commit  string // SHA of the recovery commit
+}
+
+// StashWorktree saves uncommitted changes (including untracked files) in the
+// agent's worktree to a stash, so the work survives a forced abort.
+// Returns the stash commit SHA, or "" if the worktree was clean or missing.
+func (p *Project) StashWorktree(agentID string) (string, error) {
+	p.stashMu.Lock()
+	defer p.stashMu.Unlock()
+
+	wtPath := p.getWorktreePathForAgent(agentID)
+	if wtPath == "" {
+		return "", nil
+	}
+	if _, err := os.Stat(wtPath); err != nil {
+		return "", nil
+	}
+
+	statusCmd := exec.Command("git", "status", "--porcelain")
+	statusCmd.Dir = wtPath
+	output, err := statusCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git status: %w", err)
+	}
+	if strings.TrimSpace(string(output)) == "" {
+		return "", nil
+	}
+
+	stashCmd := exec.Command("git", p.withGitIdentity("stash", "push", "-u", "-m", abortStashPrefix+agentID)...)
+	stashCmd.Dir = wtPath
+	if output, err := stashCmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git stash: %w\n%s", err, output)
+	}
+
+	revCmd := exec.Command("git", "rev-parse", "refs/stash")
+	revCmd.Dir = wtPath
+	output, err = revCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("resolve stash: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RecoverStash pops the most recent abort stash into a new branch and commits it.
+// If agentID is empty, the most recent abort stash of any agent is used.
+// If branch is empty, it defaults to fab/recover-{agentID}.
+// Returns ErrNoStash if no matching stash exists.
+func (p *Project) RecoverStash(agentID, branch string) (*StashRecovery, error) {
+	p.stashMu.Lock()
+	defer p.stashMu.Unlock()
+
+	repoDir := p.RepoDir()
+
+	ref, stashAgent, err := findAbortStash(repoDir, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if branch == "" {
+		branch = "fab/recover-" + stashAgent
+	}
+
+	// Pop in a scratch worktree so the main checkout and agent worktrees are untouched
+	tmpDir, err := os.MkdirTemp("", "fab-recover-")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	addCmd := exec.Command("git", "worktree", "add", "--detach", tmpDir, ref+"^1")
+	addCmd.Dir = repoDir
+	if output, err := addCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("create recovery worktree: %w\n%s", err, output)
+	}
+	defer func() {
+		removeCmd := exec.Command("git", "worktree", "remove", "--force", tmpDir)
+		removeCmd.Dir = repoDir
+		_ = removeCmd.Run()
+	}()
+
+	// Creates the branch at the stash's base, applies it, and drops the stash
+	branchCmd := exec.Command("git", "stash", "branch", branch, ref)
+	branchCmd.Dir = tmpDir
+	if output, err := branchCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("git stash branch: %w\n%s", err, output)
+	}
+
+	addAllCmd := exec.Command("git", "add", "-A")
+	addAllCmd.Dir = tmpDir
+	if output, err := addAllCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("git add: %w\n%s", err, output)
+	}
+
+	commitCmd := exec.Command("git", p.withGitIdentity("commit", "-m", fmt.Sprintf("Recover uncommitted work from agent %s", stashAgent))...)
+	commitCmd.Dir = tmpDir
+	if output, err := commitCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("git commit: %w\n%s", err, output)
+	}
+
+	revCmd := exec.Command("git", "rev-parse", "HEAD")
+	revCmd.Dir = tmpDir
+	output, err := revCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("resolve recovery commit: %w", err)
+	}
+
+	return &StashRecovery{
+		AgentID: stashAgent,
+		Branch:  branch,
+		Commit:  strings.TrimSpace(string(output)),
+	}, nil
+}
+
+// findAbortStash returns the stash@{n} ref and agent ID of the most recent
+// abort stash, optionally restricted to one agent.
+func findAbortStash(repoDir, agentID string) (string, string, error) {
+	listCmd := exec.Command("git", "stash", "list", "--format=%gd%x00%gs")
+	listCmd.Dir = repoDir
+	output, err := listCmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("git stash list: %w", err)
+	}
+
+	// Stash list is ordered newest first
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		ref, subject, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+		// Subject is "On <branch>: <message>"
+		idx := strings.Index(subject, abortStashPrefix)
+		if idx < 0 {
+			continue
+		}
+		stashAgent := strings.TrimSpace(subject[idx+len(abortStashPrefix):])
+		if agentID != "" && stashAgent != agentID {
+			continue
+		}
+		return ref, stashAgent, nil
+	}
+	return "", "", ErrNoStash
+}
@@ -0,0 +1,67 @@
+package project
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStashWorktree_CleanWorktree(t *testing.T) {
+	p, _, _ := setupMergeProject(t, "main", "agent1")
+
+	ref, err := p.StashWorktree("agent1")
+	if err != nil {
+		t.Fatalf("StashWorktree failed: %v", err)
+	}
+	if ref != "" {
+		t.Errorf("expected no stash for clean worktree, got %s", ref)
+	}
+}
+
+func TestStashWorktree_RecoverIntoBranch(t *testing.T) {
+	p, _, wtPath := setupMergeProject(t, "main", "agent1")
+	repo := p.RepoDir()
+
+	// One tracked modification and one untracked file
+	if err := os.WriteFile(filepath.Join(wtPath, "README.md"), []byte("edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wtPath, "new.txt"), []byte("untracked\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := p.StashWorktree("agent1")
+	if err != nil {
+		t.Fatalf("StashWorktree failed: %v", err)
+	}
+	if ref == "" {
+		t.Fatal("expected a stash ref for dirty worktree")
+	}
+	if status := git(t, wtPath, "status", "--porcelain"); status != "" {
+		t.Errorf("worktree still dirty after stash: %q", status)
+	}
+
+	result, err := p.RecoverStash("agent1", "")
+	if err != nil {
+		t.Fatalf("RecoverStash failed: %v", err)
+	}
+	if result.Branch != "fab/recover-agent1" || result.AgentID != "agent1" {
+		t.Errorf("unexpected recovery %+v", result)
+	}
+
+	files := git(t, repo, "show", "--name-only", "--format=", result.Branch)
+	for _, name := range []string{"README.md", "new.txt"} {
+		if !strings.Contains(files, name) {
+			t.Errorf("recovery commit missing %s, has %q", name, files)
+		}
+	}
+	if list := git(t, repo, "stash", "list"); list != "" {
+		t.Errorf("expected stash to be dropped, got %q", list)
+	}
+
+	if _, err := p.RecoverStash("agent1", ""); !errors.Is(err, ErrNoStash) {
+		t.Errorf("second recover error = %v, want ErrNoStash", err)
+	}
+}
@@ -0,0 +1,108 @@
+package project
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TimelineFile is the name of the activity timeline in the project
+// directory. It is append-only, one JSON event per line.
+const TimelineFile = "timeline.jsonl"
+
+// Timeline event types.
+const (
+	TimelineSpawn       = "spawn"        // An agent was created
+	TimelineClaim       = "claim"        // An agent claimed an issue
+	TimelineMerge       = "merge"        // An agent's work was merged
+	TimelinePullRequest = "pull_request" // A pull request was opened for an agent's work
+	TimelineMergeFailed = "merge_failed" // A merge hit conflicts or a failed pre-merge check
+	TimelineAbort       = "abort"        // An agent was aborted
+	TimelineConfig      = "config"       // A config value was changed
+)
+
+// TimelineEvent is one entry in a project's activity timeline.
+type TimelineEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	AgentID string    `json:"agent_id,omitempty"`
+	IssueID string    `json:"issue_id,omitempty"`
+	SHA     string    `json:"sha,omitempty"`    // Merged commit
+	Detail  string    `json:"detail,omitempty"` // e.g. who spawned the agent, the PR URL, or the config change
+}
+
+// TimelinePath returns the path of the project's activity timeline.
+func (p *Project) TimelinePath() string {
+	return filepath.Join(p.ProjectDir(), TimelineFile)
+}
+
+// AppendTimeline adds an event to the project's activity timeline.
+// Appends are serialized so concurrent events don't interleave.
+func (p *Project) AppendTimeline(ev TimelineEvent) error {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	ev.Time = ev.Time.UTC()
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("encode timeline event: %w", err)
+	}
+
+	p.timelineMu.Lock()
+	defer p.timelineMu.Unlock()
+
+	path := p.TimelinePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create project directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open timeline: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("write timeline: %w", err)
+	}
+	return f.Close()
+}
+
+// ReadTimeline returns the project's timeline events at or after since,
+// oldest first. If limit > 0, only the newest limit events are returned and
+// truncated reports whether older ones were left out. Lines that can't be
+// parsed, such as one cut short by a crash, are skipped.
+func (p *Project) ReadTimeline(since time.Time, limit int) (events []TimelineEvent, truncated bool, err error) {
+	f, err := os.Open(p.TimelinePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("open timeline: %w", err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		var ev TimelineEvent
+		if len(line) > 0 && json.Unmarshal(line, &ev) == nil && !ev.Time.Before(since) {
+			events = append(events, ev)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("read timeline: %w", err)
+		}
+	}
+
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+		truncated = true
+	}
+	return events, truncated, nil
+}
@@ -0,0 +1,70 @@
+package project
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestProject_Timeline(t *testing.T) {
+	p := &Project{Name: "proj", BaseDir: t.TempDir()}
+
+	events, truncated, err := p.ReadTimeline(time.Time{}, 0)
+	if err != nil || len(events) != 0 || truncated {
+		t.Fatalf("ReadTimeline() = %v, %v, %v; want no events", events, truncated, err)
+	}
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, ev := range []TimelineEvent{
+		{Type: TimelineSpawn, AgentID: "a1", Detail: "orchestrator"},
+		{Type: TimelineClaim, AgentID: "a1", IssueID: "42"},
+		{Type: TimelineMerge, AgentID: "a1", IssueID: "42", SHA: "abc123"},
+	} {
+		ev.Time = start.Add(time.Duration(i) * time.Minute)
+		if err := p.AppendTimeline(ev); err != nil {
+			t.Fatalf("AppendTimeline() error = %v", err)
+		}
+	}
+
+	// A line cut short by a crash is skipped
+	f, err := os.OpenFile(p.TimelinePath(), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"time":"2026-01-02T03:10:00Z","ty`)
+	f.Close()
+
+	events, truncated, err = p.ReadTimeline(time.Time{}, 0)
+	if err != nil || truncated {
+		t.Fatalf("ReadTimeline() error = %v, truncated = %v", err, truncated)
+	}
+	if len(events) != 3 || events[0].Type != TimelineSpawn || events[2].SHA != "abc123" {
+		t.Errorf("ReadTimeline() = %+v, want the 3 events in order", events)
+	}
+
+	events, _, _ = p.ReadTimeline(start.Add(time.Minute), 0)
+	if len(events) != 2 || events[0].Type != TimelineClaim {
+		t.Errorf("ReadTimeline(since) = %+v, want the claim and merge", events)
+	}
+
+	events, truncated, _ = p.ReadTimeline(time.Time{}, 1)
+	if len(events) != 1 || events[0].Type != TimelineMerge || !truncated {
+		t.Errorf("ReadTimeline(limit 1) = %+v, %v; want the newest event, truncated", events, truncated)
+	}
+}
+
+func TestProject_AppendTimeline_DefaultsTime(t *testing.T) {
+	p := &Project{Name: "proj", BaseDir: t.TempDir()}
+
+	before := time.Now().Add(-time.Second)
+	if err := p.AppendTimeline(TimelineEvent{Type: TimelineAbort, AgentID: "a1"}); err != nil {
+		t.Fatalf("AppendTimeline() error = %v", err)
+	}
+	events, _, err := p.ReadTimeline(time.Time{}, 0)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("ReadTimeline() = %v, %v; want one event", events, err)
+	}
+	if events[0].Time.Before(before) || events[0].Time.Location() != time.UTC {
+		t.Errorf("Time = %v, want now in UTC", events[0].Time)
+	}
+}
@@ -79,13 +79,7 @@ func (p *Project) DeleteAllWorktrees() error {
 	return p.cleanupWorktrees()
 }
 
-// resetWorktree resets a worktree to origin/main with a clean working directory.
-// Must be called with lock held.
-func (p *Project) resetWorktree(wtPath string) error {
-	return p.resetWorktreeUnlocked(wtPath)
-}
-
-// resetWorktreeUnlocked resets a worktree to origin/main with a clean working directory.
+// resetWorktreeUnlocked resets a worktree to the remote default branch with a clean working directory.
 // This is safe to call without holding the lock since it only operates on the filesystem.
 func (p *Project) resetWorktreeUnlocked(wtPath string) error {
 	// Verify the repo is a valid git repository
@@ -102,11 +96,18 @@ func (p *Project) resetWorktreeUnlocked(wtPath string) error {
 		return fmt.Errorf("fetch origin: %w\n%s", err, output)
 	}
 
-	// Reset worktree to origin/main
-	resetCmd := exec.Command("git", "reset", "--hard", "origin/main")
+	return p.resetToBase(wtPath)
+}
+
+// resetToBase resets a worktree to the already-fetched remote default branch
+// and removes untracked files, without fetching.
+func (p *Project) resetToBase(wtPath string) error {
+	// Reset worktree to the remote default branch
+	baseRef := p.remoteBaseRef()
+	resetCmd := exec.Command("git", "reset", "--hard", baseRef)
 	resetCmd.Dir = wtPath
 	if output, err := resetCmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("reset to origin/main: %w\n%s", err, output)
+		return fmt.Errorf("reset to %s: %w\n%s", baseRef, err, output)
 	}
 
 	// Clean untracked files and directories (including ignored files like build artifacts)
@@ -119,6 +120,16 @@ func (p *Project) resetWorktreeUnlocked(wtPath string) error {
 	return nil
 }
 
+// withGitIdentity prefixes git arguments with the project's git author
+// identity, so commits fab creates (rebased, merged, or recovered) are
+// attributed to it rather than to whatever identity is configured globally.
+func (p *Project) withGitIdentity(args ...string) []string {
+	return append([]string{
+		"-c", "user.name=" + p.GetGitAuthorName(),
+		"-c", "user.email=" + p.GetGitAuthorEmail(),
+	}, args...)
+}
+
 // createAgentBranch creates and checks out a branch for an agent's work.
 // Must be called with lock held.
 func (p *Project) createAgentBranch(wtPath, agentID string) error {
@@ -143,22 +154,28 @@ func (p *Project) createAgentBranch(wtPath, agentID string) error {
 
 // MergeResult represents the outcome of a rebase-and-merge attempt.
 type MergeResult struct {
-	Merged     bool   // True if rebase succeeded and was pushed
-	BranchName string // The branch that was rebased and merged
-	SHA        string // Commit SHA of branch tip after rebase (only set if Merged is true)
-	Error      error  // Conflict or other error if rebase failed
+	Merged        bool           // True if rebase succeeded and was pushed
+	BranchName    string         // The branch that was rebased and merged
+	SHA           string         // Commit SHA of branch tip after rebase (only set if Merged is true)
+	Error         error          // Conflict or other error if rebase failed
+	ConflictFiles []string       // Files with unresolved conflicts (only set on rebase conflict)
+	Conflicts     []ConflictFile // Conflict hunks of ConflictFiles (only set on rebase conflict)
 }
 
-// MergeAgentBranch rebases an agent's branch onto main and fast-forwards main to include it.
-// If rebase succeeds, pushes to origin/main.
+// MergeAgentBranch rebases an agent's branch onto the default branch and fast-forwards
+// the default branch to include it. If rebase succeeds, pushes the default branch to origin.
 // If rebase fails due to conflicts, aborts and returns error (caller should rebase worktree).
 // This method serializes merge operations using mergeMu to prevent concurrent conflicts.
-func (p *Project) MergeAgentBranch(agentID string) (*MergeResult, error) {
+// If onRebased is non-nil, it is called with the rebased branch tip just before the
+// default branch is moved, so callers can journal the target for crash recovery (see RecoverMerge).
+func (p *Project) MergeAgentBranch(agentID string, onRebased func(sha string)) (*MergeResult, error) {
 	p.mergeMu.Lock()
 	defer p.mergeMu.Unlock()
 
 	repoDir := p.RepoDir()
 	branchName := "fab/" + agentID
+	baseBranch := p.GetDefaultBranch()
+	baseRef := p.remoteBaseRef()
 
 	// Verify the repo is a valid git repository
 	gitDir := filepath.Join(repoDir, ".git")
@@ -172,6 +189,11 @@ func (p *Project) MergeAgentBranch(agentID string) (*MergeResult, error) {
 		return nil, fmt.Errorf("worktree not found for agent %s", agentID)
 	}
 
+	// Projects added with a clone depth need full history to rebase
+	if err := p.unshallow(); err != nil {
+		return nil, err
+	}
+
 	// Fetch latest from origin
 	fetchCmd := exec.Command("git", "fetch", "origin")
 	fetchCmd.Dir = repoDir
@@ -179,13 +201,17 @@ func (p *Project) MergeAgentBranch(agentID string) (*MergeResult, error) {
 		return nil, fmt.Errorf("fetch: %w\n%s", err, output)
 	}
 
-	// Rebase the agent's branch onto origin/main directly in the worktree.
+	// Rebase the agent's branch onto the remote default branch directly in the worktree.
 	// No need to detach - the branch stays checked out in the worktree throughout.
-	rebaseCmd := exec.Command("git", "rebase", "origin/main")
+	rebaseCmd := exec.Command("git", p.withGitIdentity("rebase", baseRef)...)
 	rebaseCmd.Dir = wtPath
 	rebaseOutput, rebaseErr := rebaseCmd.CombinedOutput()
 
 	if rebaseErr != nil {
+		// Record conflicting files and their hunks before the abort discards them
+		conflicts := conflictedFiles(wtPath)
+		details := conflictDetails(wtPath, conflicts)
+
 		// Rebase failed - abort and return error (worktree stays on its branch)
 		abortCmd := exec.Command("git", "rebase", "--abort")
 		abortCmd.Dir = wtPath
@@ -193,9 +219,11 @@ func (p *Project) MergeAgentBranch(agentID string) (*MergeResult, error) {
 		_ = abortCmd.Run()
 
 		return &MergeResult{
-			Merged:     false,
-			BranchName: branchName,
-			Error:      fmt.Errorf("rebase conflict: %s", string(rebaseOutput)),
+			Merged:        false,
+			BranchName:    branchName,
+			Error:         fmt.Errorf("rebase conflict: %s", string(rebaseOutput)),
+			ConflictFiles: conflicts,
+			Conflicts:     details,
 		}, nil
 	}
 
@@ -212,25 +240,36 @@ func (p *Project) MergeAgentBranch(agentID string) (*MergeResult, error) {
 		}
 	}
 
-	// Fast-forward main to the rebased branch.
+	if onRebased != nil {
+		onRebased(sha)
+	}
+
+	// The fast-forward happens in the repo's own checkout, so make sure it is
+	// on the default branch (the clone may have a different HEAD if default-branch
+	// was overridden).
+	if err := checkoutBranch(repoDir, baseBranch); err != nil {
+		return nil, err
+	}
+
+	// Fast-forward the default branch to the rebased branch.
 	// This works even though the branch is checked out in the worktree -
-	// we're just moving the main ref, not checking out the branch.
-	ffCmd := exec.Command("git", "merge", "--ff-only", branchName)
+	// we're just moving the base branch ref, not checking out the agent branch.
+	ffCmd := exec.Command("git", p.withGitIdentity("merge", "--ff-only", branchName)...)
 	ffCmd.Dir = repoDir
 	if output, err := ffCmd.CombinedOutput(); err != nil {
-		return nil, fmt.Errorf("fast-forward main: %w\n%s", err, output)
+		return nil, fmt.Errorf("fast-forward %s: %w\n%s", baseBranch, err, output)
 	}
 
 	// Push to origin
-	pushCmd := exec.Command("git", "push", "origin", "main")
+	pushCmd := exec.Command("git", "push", "origin", baseBranch)
 	pushCmd.Dir = repoDir
 	if output, err := pushCmd.CombinedOutput(); err != nil {
-		// Rollback: reset main to origin/main
-		resetCmd := exec.Command("git", "reset", "--hard", "origin/main")
+		// Rollback: reset the default branch to its remote tip
+		resetCmd := exec.Command("git", "reset", "--hard", baseRef)
 		resetCmd.Dir = repoDir
 		// Ignore reset error - best-effort rollback after push failure
 		_ = resetCmd.Run()
-		return nil, fmt.Errorf("push main: %w\n%s", err, output)
+		return nil, fmt.Errorf("push %s: %w\n%s", baseBranch, err, output)
 	}
 
 	return &MergeResult{
@@ -240,8 +279,8 @@ func (p *Project) MergeAgentBranch(agentID string) (*MergeResult, error) {
 	}, nil
 }
 
-// RebaseWorktreeOnMain rebases a worktree's current branch onto origin/main.
-// Used when merge fails to bring the agent's worktree up to date with latest main.
+// RebaseWorktreeOnMain rebases a worktree's current branch onto the remote default branch.
+// Used when merge fails to bring the agent's worktree up to date with the latest base.
 func (p *Project) RebaseWorktreeOnMain(agentID string) error {
 	p.mu.RLock()
 	var wtPath string
@@ -265,8 +304,8 @@ func (p *Project) RebaseWorktreeOnMain(agentID string) error {
 	// Ignore fetch error - rebase will still work with local refs
 	_ = fetchCmd.Run()
 
-	// Rebase onto origin/main
-	rebaseCmd := exec.Command("git", "rebase", "origin/main")
+	// Rebase onto the remote default branch
+	rebaseCmd := exec.Command("git", p.withGitIdentity("rebase", p.remoteBaseRef())...)
 	rebaseCmd.Dir = wtPath
 	if output, err := rebaseCmd.CombinedOutput(); err != nil {
 		// Abort failed rebase
@@ -280,6 +319,37 @@ func (p *Project) RebaseWorktreeOnMain(agentID string) error {
 	return nil
 }
 
+// conflictedFiles returns the paths with unresolved merge conflicts in a worktree.
+// Returns nil if the list cannot be determined.
+func conflictedFiles(wtPath string) []string {
+	cmd := exec.Command("git", "diff", "--name-only", "--diff-filter=U")
+	cmd.Dir = wtPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
+// IsWorktreeDirty reports whether a worktree has uncommitted changes,
+// including untracked files.
+func IsWorktreeDirty(wtPath string) (bool, error) {
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = wtPath
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("git status: %w", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
 // cleanupWorktrees removes all worktrees.
 //
 // +checklocks:p.mu
@@ -287,20 +357,26 @@ func (p *Project) cleanupWorktrees() error {
 	var lastErr error
 	repoDir := p.RepoDir()
 
+	paths := append([]string(nil), p.pool...)
 	for _, wt := range p.Worktrees {
+		paths = append(paths, wt.Path)
+	}
+	for _, path := range paths {
 		// Remove git worktree
-		cmd := exec.Command("git", "worktree", "remove", "--force", wt.Path)
+		cmd := exec.Command("git", "worktree", "remove", "--force", path)
 		cmd.Dir = repoDir
 		if err := cmd.Run(); err != nil {
 			// Try manual removal if git worktree remove fails
-			if rmErr := os.RemoveAll(wt.Path); rmErr != nil {
-				lastErr = fmt.Errorf("remove worktree %s: %w", wt.Path, rmErr)
+			if rmErr := os.RemoveAll(path); rmErr != nil {
+				lastErr = fmt.Errorf("remove worktree %s: %w", path, rmErr)
 			}
 		}
 	}
 
-	// Clear the worktrees slice
+	// Clear the worktrees slice and close the pool
 	p.Worktrees = p.Worktrees[:0]
+	p.pool = nil
+	p.poolOpen = false
 
 	// Remove the worktrees directory if empty
 	wtDir := p.WorktreesDir()
@@ -331,21 +407,25 @@ func (p *Project) getWorktreePathForAgent(agentID string) string {
 
 // PullRequestResult represents the outcome of creating a pull request.
 type PullRequestResult struct {
-	Created    bool   // True if PR was created successfully
-	BranchName string // The branch that was pushed
-	PRURL      string // URL of the created pull request
-	Error      error  // Error if PR creation failed
+	Created       bool           // True if PR was created successfully
+	BranchName    string         // The branch that was pushed
+	PRURL         string         // URL of the created pull request
+	Error         error          // Error if PR creation failed
+	ConflictFiles []string       // Files with unresolved conflicts (only set on rebase conflict)
+	Conflicts     []ConflictFile // Conflict hunks of ConflictFiles (only set on rebase conflict)
 }
 
-// CreatePullRequest rebases an agent's branch onto main, pushes it, and creates a pull request.
-// This is used when merge strategy is "pull-request" instead of direct merge.
-// Unlike MergeAgentBranch, this does NOT merge into main - it just creates a PR.
+// CreatePullRequest rebases an agent's branch onto the default branch, pushes it, and creates
+// a pull request against it. This is used when merge strategy is "pull-request" instead of
+// direct merge. Unlike MergeAgentBranch, this does NOT merge - it just creates a PR.
 func (p *Project) CreatePullRequest(agentID, title, body string) (*PullRequestResult, error) {
 	p.mergeMu.Lock()
 	defer p.mergeMu.Unlock()
 
 	repoDir := p.RepoDir()
 	branchName := "fab/" + agentID
+	baseBranch := p.GetDefaultBranch()
+	baseRef := p.remoteBaseRef()
 
 	// Verify the repo is a valid git repository
 	gitDir := filepath.Join(repoDir, ".git")
@@ -359,6 +439,11 @@ func (p *Project) CreatePullRequest(agentID, title, body string) (*PullRequestRe
 		return nil, fmt.Errorf("worktree not found for agent %s", agentID)
 	}
 
+	// Projects added with a clone depth need full history to rebase
+	if err := p.unshallow(); err != nil {
+		return nil, err
+	}
+
 	// Fetch latest from origin
 	fetchCmd := exec.Command("git", "fetch", "origin")
 	fetchCmd.Dir = repoDir
@@ -366,21 +451,27 @@ func (p *Project) CreatePullRequest(agentID, title, body string) (*PullRequestRe
 		return nil, fmt.Errorf("fetch: %w\n%s", err, output)
 	}
 
-	// Rebase the agent's branch onto origin/main
-	rebaseCmd := exec.Command("git", "rebase", "origin/main")
+	// Rebase the agent's branch onto the remote default branch
+	rebaseCmd := exec.Command("git", p.withGitIdentity("rebase", baseRef)...)
 	rebaseCmd.Dir = wtPath
 	rebaseOutput, rebaseErr := rebaseCmd.CombinedOutput()
 
 	if rebaseErr != nil {
+		// Record conflicts before the abort discards them
+		conflicts := conflictedFiles(wtPath)
+		details := conflictDetails(wtPath, conflicts)
+
 		// Rebase failed - abort and return error
 		abortCmd := exec.Command("git", "rebase", "--abort")
 		abortCmd.Dir = wtPath
 		_ = abortCmd.Run()
 
 		return &PullRequestResult{
-			Created:    false,
-			BranchName: branchName,
-			Error:      fmt.Errorf("rebase conflict: %s", string(rebaseOutput)),
+			Created:       false,
+			BranchName:    branchName,
+			Error:         fmt.Errorf("rebase conflict: %s", string(rebaseOutput)),
+			ConflictFiles: conflicts,
+			Conflicts:     details,
 		}, nil
 	}
 
@@ -395,7 +486,7 @@ func (p *Project) CreatePullRequest(agentID, title, body string) (*PullRequestRe
 	prCmd := exec.Command("gh", "pr", "create",
 		"--title", title,
 		"--body", body,
-		"--base", "main",
+		"--base", baseBranch,
 		"--head", branchName,
 	)
 	prCmd.Dir = repoDir
@@ -413,3 +504,55 @@ func (p *Project) CreatePullRequest(agentID, title, body string) (*PullRequestRe
 		PRURL:      prURL,
 	}, nil
 }
+
+// remoteBaseRef returns the remote-tracking ref of the default branch (e.g. "origin/main").
+func (p *Project) remoteBaseRef() string {
+	return "origin/" + p.GetDefaultBranch()
+}
+
+// checkoutBranch switches repoDir's checkout to branch if it isn't already on it.
+// A missing local branch is created from its origin counterpart by git's checkout DWIM.
+func checkoutBranch(repoDir, branch string) error {
+	headCmd := exec.Command("git", "symbolic-ref", "--short", "HEAD")
+	headCmd.Dir = repoDir
+	if output, err := headCmd.Output(); err == nil && strings.TrimSpace(string(output)) == branch {
+		return nil
+	}
+
+	cmd := exec.Command("git", "checkout", branch)
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("checkout %s: %w\n%s", branch, err, output)
+	}
+	return nil
+}
+
+// SetRemoteHead points refs/remotes/origin/HEAD at the default branch, so
+// commands run inside worktrees (e.g. 'fab agent done') resolve the same base
+// as the daemon without access to project config.
+func (p *Project) SetRemoteHead() error {
+	repoDir := p.RepoDir()
+	if _, err := os.Stat(filepath.Join(repoDir, ".git")); os.IsNotExist(err) {
+		return nil // Not a git repo - skip (likely a test scenario)
+	}
+
+	cmd := exec.Command("git", "remote", "set-head", "origin", p.GetDefaultBranch())
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("set origin/HEAD: %w\n%s", err, output)
+	}
+	return nil
+}
+
+// DetectDefaultBranch returns the remote's default branch for the clone at repoDir,
+// as recorded in refs/remotes/origin/HEAD by git clone.
+// Returns an empty string if it cannot be determined.
+func DetectDefaultBranch(repoDir string) string {
+	cmd := exec.Command("git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	cmd.Dir = repoDir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(output)), "origin/")
+}
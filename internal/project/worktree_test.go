@@ -0,0 +1,184 @@
+package project
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestDetectDefaultBranch(t *testing.T) {
+	p, _ := setupClonedProject(t, "master")
+
+	if got := DetectDefaultBranch(p.RepoDir()); got != "master" {
+		t.Errorf("DetectDefaultBranch() = %q, want master", got)
+	}
+	if got := DetectDefaultBranch(t.TempDir()); got != "" {
+		t.Errorf("DetectDefaultBranch(non-repo) = %q, want empty", got)
+	}
+}
+
+func TestMergeAgentBranch_NonMainDefaultBranch(t *testing.T) {
+	p, remote := setupClonedProject(t, "master")
+	p.DefaultBranch = DetectDefaultBranch(p.RepoDir())
+
+	wt, err := p.CreateWorktreeForAgent("agent1", false)
+	if err != nil {
+		t.Fatalf("CreateWorktreeForAgent() error = %v", err)
+	}
+	if got := git(t, wt.Path, "rev-parse", "HEAD"); got != git(t, remote, "rev-parse", "master") {
+		t.Errorf("worktree HEAD = %s, want origin/master", got)
+	}
+
+	sha := commitFile(t, wt.Path, "feature.txt", "done\n")
+	result, err := p.MergeAgentBranch("agent1", nil)
+	if err != nil {
+		t.Fatalf("MergeAgentBranch() error = %v", err)
+	}
+	if !result.Merged {
+		t.Fatalf("MergeAgentBranch() not merged: %v", result.Error)
+	}
+
+	if got := git(t, remote, "rev-parse", "master"); got != sha {
+		t.Errorf("remote master = %s, want %s", got, sha)
+	}
+	if err := exec.Command("git", "-C", remote, "rev-parse", "--verify", "refs/heads/main").Run(); err == nil {
+		t.Error("merge created a main branch on the remote")
+	}
+}
+
+func TestMergeAgentBranch_DefaultBranchOverride(t *testing.T) {
+	p, remote := setupClonedProject(t, "main")
+	repo := p.RepoDir()
+
+	// Publish a develop branch that diverges from main
+	git(t, repo, "checkout", "-b", "develop")
+	developBase := commitFile(t, repo, "develop.txt", "develop\n")
+	git(t, repo, "push", "origin", "develop")
+	git(t, repo, "checkout", "main")
+	mainSHA := git(t, remote, "rev-parse", "main")
+
+	p.DefaultBranch = "develop"
+	if err := p.SetRemoteHead(); err != nil {
+		t.Fatalf("SetRemoteHead() error = %v", err)
+	}
+	if got := DetectDefaultBranch(repo); got != "develop" {
+		t.Errorf("origin/HEAD = %q after SetRemoteHead, want develop", got)
+	}
+
+	wt, err := p.CreateWorktreeForAgent("agent1", false)
+	if err != nil {
+		t.Fatalf("CreateWorktreeForAgent() error = %v", err)
+	}
+	if got := git(t, wt.Path, "rev-parse", "HEAD"); got != developBase {
+		t.Errorf("worktree HEAD = %s, want origin/develop %s", got, developBase)
+	}
+
+	sha := commitFile(t, wt.Path, "feature.txt", "done\n")
+	result, err := p.MergeAgentBranch("agent1", nil)
+	if err != nil {
+		t.Fatalf("MergeAgentBranch() error = %v", err)
+	}
+	if !result.Merged {
+		t.Fatalf("MergeAgentBranch() not merged: %v", result.Error)
+	}
+
+	if got := git(t, remote, "rev-parse", "develop"); got != sha {
+		t.Errorf("remote develop = %s, want %s", got, sha)
+	}
+	if got := git(t, remote, "rev-parse", "main"); got != mainSHA {
+		t.Errorf("remote main moved to %s, want %s", got, mainSHA)
+	}
+}
+
+func TestMergeAgentBranch_ConflictDetails(t *testing.T) {
+	p, _, wtPath := setupMergeProject(t, "main", "agent1")
+	repo := p.RepoDir()
+
+	commitFile(t, wtPath, "shared.txt", "from agent\n")
+	commitFile(t, repo, "shared.txt", "from main\n")
+	git(t, repo, "push", "origin", "main")
+
+	result, err := p.MergeAgentBranch("agent1", nil)
+	if err != nil {
+		t.Fatalf("MergeAgentBranch() error = %v", err)
+	}
+	if result.Merged {
+		t.Fatal("MergeAgentBranch() merged conflicting branches")
+	}
+	if len(result.ConflictFiles) != 1 || result.ConflictFiles[0] != "shared.txt" {
+		t.Errorf("ConflictFiles = %v, want [shared.txt]", result.ConflictFiles)
+	}
+	if len(result.Conflicts) != 1 || len(result.Conflicts[0].Hunks) != 1 {
+		t.Fatalf("Conflicts = %+v, want one hunk in shared.txt", result.Conflicts)
+	}
+	hunk := result.Conflicts[0].Hunks[0]
+	if hunk.StartLine != 1 || !strings.Contains(hunk.Text, "from main") || !strings.Contains(hunk.Text, "from agent") {
+		t.Errorf("hunk = %+v, want both sides from line 1", hunk)
+	}
+	if rebaseInProgress(wtPath) {
+		t.Error("rebase left in progress after collecting conflicts")
+	}
+}
+
+func TestMergeAgentBranch_GitIdentity(t *testing.T) {
+	p, remote := setupClonedProject(t, "main")
+	p.GitAuthorEmail = "bot@example.com"
+
+	wt, err := p.CreateWorktreeForAgent("agent1", false)
+	if err != nil {
+		t.Fatalf("CreateWorktreeForAgent() error = %v", err)
+	}
+	git(t, wt.Path, "config", "user.email", "agent@example.com")
+	git(t, wt.Path, "config", "user.name", "Agent")
+	commitFile(t, wt.Path, "feature.txt", "done\n")
+
+	// Move main on so the merge has to rebase the agent's commit
+	commitFile(t, p.RepoDir(), "other.txt", "other\n")
+	git(t, p.RepoDir(), "push", "origin", "main")
+
+	result, err := p.MergeAgentBranch("agent1", nil)
+	if err != nil || !result.Merged {
+		t.Fatalf("MergeAgentBranch() = %+v, %v; want merged", result, err)
+	}
+
+	got := git(t, remote, "log", "-1", "--format=%an <%ae> / %cn <%ce>", "main")
+	if want := "Agent <agent@example.com> / fab-bot <bot@example.com>"; got != want {
+		t.Errorf("merged commit author / committer = %q, want %q", got, want)
+	}
+}
+
+func TestMergeAgentBranch_RetryAfterRemoteAdvances(t *testing.T) {
+	p, remote, wtPath := setupMergeProject(t, "main", "agent1")
+	commitFile(t, wtPath, "feature.txt", "done\n")
+
+	// Another agent pushes between our rebase and our push
+	var otherSHA string
+	advanceRemote := func(string) {
+		other := t.TempDir()
+		git(t, other, "clone", remote, ".")
+		git(t, other, "config", "user.email", "other@example.com")
+		git(t, other, "config", "user.name", "Other Agent")
+		otherSHA = commitFile(t, other, "other.txt", "other\n")
+		git(t, other, "push", "origin", "main")
+	}
+
+	if _, err := p.MergeAgentBranch("agent1", advanceRemote); err == nil {
+		t.Fatal("MergeAgentBranch() pushed over a remote that moved after the rebase")
+	}
+	if got := git(t, remote, "rev-parse", "main"); got != otherSHA {
+		t.Fatalf("remote main = %s, want the competing push %s", got, otherSHA)
+	}
+
+	// The retry fetches and rebases onto the competing push
+	result, err := p.MergeAgentBranch("agent1", nil)
+	if err != nil || !result.Merged {
+		t.Fatalf("retried MergeAgentBranch() = %+v, %v; want merged", result, err)
+	}
+
+	if got := git(t, remote, "rev-parse", "main"); got != result.SHA {
+		t.Errorf("remote main = %s, want the retried merge %s", got, result.SHA)
+	}
+	if got := git(t, remote, "rev-parse", "main~1"); got != otherSHA {
+		t.Errorf("remote main~1 = %s, want the competing commit %s", got, otherSHA)
+	}
+}
@@ -3,11 +3,14 @@ package registry
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	configPkg "github.com/tessro/fab/internal/config"
@@ -37,13 +40,35 @@ type ProjectEntry struct {
 	IssueBackend       string   `toml:"issue-backend,omitempty"`       // "tk" (default), "github", "gh", "linear"
 	LinearTeam         string   `toml:"linear-team,omitempty"`         // Linear team ID (required for "linear" backend)
 	LinearProject      string   `toml:"linear-project,omitempty"`      // Linear project ID (optional, for scoping issues)
+	GitHubHost         string   `toml:"github-host,omitempty"`         // GitHub Enterprise host (default: detected from remote)
+	GitHubIssueLimit   int      `toml:"github-issue-limit,omitempty"`  // Most issues fetched from GitHub when listing (default: 1000)
 	AllowedAuthors     []string `toml:"allowed-authors,omitempty"`     // GitHub usernames allowed to create issues
 	Autostart          bool     `toml:"autostart,omitempty"`           // Start orchestration when daemon starts
+	DryRun             bool     `toml:"dry-run,omitempty"`             // Log spawn and merge decisions without acting on them
 	PermissionsChecker string   `toml:"permissions-checker,omitempty"` // Permission checker: "manual" (default), "llm"
 	AgentBackend       string   `toml:"agent-backend,omitempty"`       // Agent CLI backend: "claude" (default), "codex" - used as fallback
 	PlannerBackend     string   `toml:"planner-backend,omitempty"`     // Planner CLI backend: "claude" (default), "codex"
 	CodingBackend      string   `toml:"coding-backend,omitempty"`      // Coding agent CLI backend: "claude" (default), "codex"
 	MergeStrategy      string   `toml:"merge-strategy,omitempty"`      // Merge strategy: "direct" (default), "pull-request"
+	DefaultBranch      string   `toml:"default-branch,omitempty"`      // Branch agents start from and merge into (detected on add; default: "main")
+	AutoRebase         *bool    `toml:"auto-rebase,omitempty"`         // Retry a merge whose fetch or push failed (default: true)
+	PullBeforeSpawn    bool     `toml:"pull-before-spawn,omitempty"`   // Fast-forward the main clone before creating each agent
+	ReflectClaims      bool     `toml:"reflect-claims,omitempty"`      // Label and comment on issues in the backend while agents hold claims
+	AutoClaim          bool     `toml:"auto-claim,omitempty"`          // Claim a ready issue for each agent before spawning it
+	AgentNaming        string   `toml:"agent-naming,omitempty"`        // Agent names: "id" (default), "animal", "issue"
+	PreMergeCommand    string   `toml:"pre-merge-command,omitempty"`   // Shell command that must pass before merging (e.g. "go test ./...")
+	GitAuthorName      string   `toml:"git-author-name,omitempty"`     // Name on commits fab makes when merging (default: "fab-bot")
+	GitAuthorEmail     string   `toml:"git-author-email,omitempty"`    // Email on commits fab makes when merging (default: "fab-bot@localhost")
+	PreMergeTimeout    string   `toml:"pre-merge-timeout,omitempty"`   // Timeout for pre-merge-command as a duration (default: "10m")
+	PollInterval       string   `toml:"poll-interval,omitempty"`       // How often to check for ready issues (default: "10s")
+	ActiveHours        string   `toml:"active-hours,omitempty"`        // Weekly window orchestration runs in (default: always)
+	WorktreePoolSize   int      `toml:"worktree-pool-size,omitempty"`  // Idle worktrees kept ready for new agents (default: 0, no pool)
+	Model              string   `toml:"model,omitempty"`               // Model passed to the agent CLI (default: the CLI's default)
+
+	// Priority lanes: slots above max-agents that only high-priority issues may use
+	ReservedSlots     int    `toml:"reserved-high-priority-slots,omitempty"` // Extra slots kept free for high-priority issues
+	PriorityThreshold int    `toml:"high-priority-threshold,omitempty"`      // Minimum priority for reserved slots: 1 (medium) or 2 (high, default)
+	HighPriorityModel string `toml:"high-priority-model,omitempty"`          // Model for issues at or above high-priority-threshold (default: model)
 }
 
 // Config represents the fab configuration file.
@@ -61,6 +86,18 @@ type Config struct {
 	// Defaults is preserved from global config.
 	Defaults map[string]any `toml:"defaults,omitempty"`
 
+	// Notifications is preserved from global config.
+	Notifications map[string]any `toml:"notifications,omitempty"`
+
+	// TUI is preserved from global config.
+	TUI map[string]any `toml:"tui,omitempty"`
+
+	// Backends is preserved from global config.
+	Backends map[string]any `toml:"backends,omitempty"`
+
+	// IssueTypePrompts is preserved from global config.
+	IssueTypePrompts map[string]any `toml:"issue-type-prompts,omitempty"`
+
 	// Projects is the list of registered projects.
 	Projects []ProjectEntry `toml:"projects"`
 }
@@ -138,7 +175,7 @@ func (r *Registry) SetProjectBaseDir(baseDir string) {
 }
 
 // load reads the config file and populates the registry.
-// It also preserves non-project config fields (log-level, providers, llm-auth, defaults) for saving.
+// It also preserves non-project config fields (log-level, providers, llm-auth, defaults, notifications) for saving.
 func (r *Registry) load() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -150,10 +187,14 @@ func (r *Registry) load() error {
 
 	// Preserve global config fields for saving
 	r.globalConfig = &Config{
-		LogLevel:  config.LogLevel,
-		Providers: config.Providers,
-		LLMAuth:   config.LLMAuth,
-		Defaults:  config.Defaults,
+		LogLevel:         config.LogLevel,
+		Providers:        config.Providers,
+		LLMAuth:          config.LLMAuth,
+		Defaults:         config.Defaults,
+		Notifications:    config.Notifications,
+		TUI:              config.TUI,
+		Backends:         config.Backends,
+		IssueTypePrompts: config.IssueTypePrompts,
 	}
 
 	for _, entry := range config.Projects {
@@ -169,15 +210,35 @@ func (r *Registry) load() error {
 		}
 		p.LinearTeam = entry.LinearTeam
 		p.LinearProject = entry.LinearProject
+		p.GitHubHost = entry.GitHubHost
+		p.GitHubIssueLimit = entry.GitHubIssueLimit
 		if len(entry.AllowedAuthors) > 0 {
 			p.AllowedAuthors = entry.AllowedAuthors
 		}
 		p.Autostart = entry.Autostart
+		p.DryRun = entry.DryRun
 		p.PermissionsChecker = entry.PermissionsChecker
 		p.AgentBackend = entry.AgentBackend
 		p.PlannerBackend = entry.PlannerBackend
 		p.CodingBackend = entry.CodingBackend
 		p.MergeStrategy = entry.MergeStrategy
+		p.DefaultBranch = entry.DefaultBranch
+		p.AutoRebase = entry.AutoRebase
+		p.PullBeforeSpawn = entry.PullBeforeSpawn
+		p.ReflectClaims = entry.ReflectClaims
+		p.AutoClaim = entry.AutoClaim
+		p.AgentNaming = entry.AgentNaming
+		p.WorktreePoolSize = entry.WorktreePoolSize
+		p.ReservedSlots = entry.ReservedSlots
+		p.PriorityThreshold = entry.PriorityThreshold
+		p.PreMergeCommand = entry.PreMergeCommand
+		p.GitAuthorName = entry.GitAuthorName
+		p.GitAuthorEmail = entry.GitAuthorEmail
+		p.PreMergeTimeout = entry.PreMergeTimeout
+		p.PollInterval = entry.PollInterval
+		p.ActiveHours = entry.ActiveHours
+		p.Model = entry.Model
+		p.HighPriorityModel = entry.HighPriorityModel
 		r.projects[entry.Name] = p
 	}
 
@@ -205,6 +266,10 @@ func (r *Registry) save() error {
 		config.Providers = r.globalConfig.Providers
 		config.LLMAuth = r.globalConfig.LLMAuth
 		config.Defaults = r.globalConfig.Defaults
+		config.Notifications = r.globalConfig.Notifications
+		config.TUI = r.globalConfig.TUI
+		config.Backends = r.globalConfig.Backends
+		config.IssueTypePrompts = r.globalConfig.IssueTypePrompts
 	}
 
 	for _, p := range r.projects {
@@ -215,13 +280,33 @@ func (r *Registry) save() error {
 			IssueBackend:       p.IssueBackend,
 			LinearTeam:         p.LinearTeam,
 			LinearProject:      p.LinearProject,
+			GitHubHost:         p.GitHubHost,
+			GitHubIssueLimit:   p.GitHubIssueLimit,
 			AllowedAuthors:     p.AllowedAuthors,
 			Autostart:          p.Autostart,
+			DryRun:             p.DryRun,
 			PermissionsChecker: p.PermissionsChecker,
 			AgentBackend:       p.AgentBackend,
 			PlannerBackend:     p.PlannerBackend,
 			CodingBackend:      p.CodingBackend,
 			MergeStrategy:      p.MergeStrategy,
+			DefaultBranch:      p.DefaultBranch,
+			AutoRebase:         p.AutoRebase,
+			PullBeforeSpawn:    p.PullBeforeSpawn,
+			ReflectClaims:      p.ReflectClaims,
+			AutoClaim:          p.AutoClaim,
+			AgentNaming:        p.AgentNaming,
+			WorktreePoolSize:   p.WorktreePoolSize,
+			ReservedSlots:      p.ReservedSlots,
+			PriorityThreshold:  p.PriorityThreshold,
+			PreMergeCommand:    p.PreMergeCommand,
+			GitAuthorName:      p.GitAuthorName,
+			GitAuthorEmail:     p.GitAuthorEmail,
+			PreMergeTimeout:    p.PreMergeTimeout,
+			PollInterval:       p.PollInterval,
+			ActiveHours:        p.ActiveHours,
+			Model:              p.Model,
+			HighPriorityModel:  p.HighPriorityModel,
 		})
 	}
 
@@ -384,17 +469,37 @@ const (
 	ConfigKeyIssueBackend       ConfigKey = "issue-backend"
 	ConfigKeyLinearTeam         ConfigKey = "linear-team"
 	ConfigKeyLinearProject      ConfigKey = "linear-project"
+	ConfigKeyGitHubHost         ConfigKey = "github-host"
+	ConfigKeyGitHubIssueLimit   ConfigKey = "github-issue-limit"
 	ConfigKeyAllowedAuthors     ConfigKey = "allowed-authors"
 	ConfigKeyPermissionsChecker ConfigKey = "permissions-checker"
 	ConfigKeyAgentBackend       ConfigKey = "agent-backend"
 	ConfigKeyPlannerBackend     ConfigKey = "planner-backend"
 	ConfigKeyCodingBackend      ConfigKey = "coding-backend"
 	ConfigKeyMergeStrategy      ConfigKey = "merge-strategy"
+	ConfigKeyDefaultBranch      ConfigKey = "default-branch"
+	ConfigKeyAutoRebase         ConfigKey = "auto-rebase"
+	ConfigKeyPreMergeCommand    ConfigKey = "pre-merge-command"
+	ConfigKeyGitAuthorName      ConfigKey = "git-author-name"
+	ConfigKeyGitAuthorEmail     ConfigKey = "git-author-email"
+	ConfigKeyPreMergeTimeout    ConfigKey = "pre-merge-timeout"
+	ConfigKeyWorktreePoolSize   ConfigKey = "worktree-pool-size"
+	ConfigKeyReservedSlots      ConfigKey = "reserved-high-priority-slots"
+	ConfigKeyPriorityThreshold  ConfigKey = "high-priority-threshold"
+	ConfigKeyModel              ConfigKey = "model"
+	ConfigKeyHighPriorityModel  ConfigKey = "high-priority-model"
+	ConfigKeyDryRun             ConfigKey = "dry-run"
+	ConfigKeyPullBeforeSpawn    ConfigKey = "pull-before-spawn"
+	ConfigKeyReflectClaims      ConfigKey = "reflect-claims"
+	ConfigKeyAutoClaim          ConfigKey = "auto-claim"
+	ConfigKeyAgentNaming        ConfigKey = "agent-naming"
+	ConfigKeyPollInterval       ConfigKey = "poll-interval"
+	ConfigKeyActiveHours        ConfigKey = "active-hours"
 )
 
 // ValidConfigKeys returns all valid configuration keys.
 func ValidConfigKeys() []ConfigKey {
-	return []ConfigKey{ConfigKeyMaxAgents, ConfigKeyAutostart, ConfigKeyIssueBackend, ConfigKeyLinearTeam, ConfigKeyLinearProject, ConfigKeyAllowedAuthors, ConfigKeyPermissionsChecker, ConfigKeyAgentBackend, ConfigKeyPlannerBackend, ConfigKeyCodingBackend, ConfigKeyMergeStrategy}
+	return []ConfigKey{ConfigKeyMaxAgents, ConfigKeyAutostart, ConfigKeyIssueBackend, ConfigKeyLinearTeam, ConfigKeyLinearProject, ConfigKeyGitHubHost, ConfigKeyGitHubIssueLimit, ConfigKeyAllowedAuthors, ConfigKeyPermissionsChecker, ConfigKeyAgentBackend, ConfigKeyPlannerBackend, ConfigKeyCodingBackend, ConfigKeyMergeStrategy, ConfigKeyDefaultBranch, ConfigKeyAutoRebase, ConfigKeyPreMergeCommand, ConfigKeyPreMergeTimeout, ConfigKeyReservedSlots, ConfigKeyPriorityThreshold, ConfigKeyModel, ConfigKeyHighPriorityModel, ConfigKeyDryRun, ConfigKeyPullBeforeSpawn, ConfigKeyReflectClaims, ConfigKeyAutoClaim, ConfigKeyAgentNaming, ConfigKeyPollInterval, ConfigKeyActiveHours, ConfigKeyWorktreePoolSize, ConfigKeyGitAuthorName, ConfigKeyGitAuthorEmail}
 }
 
 // IsValidConfigKey returns true if the key is a valid configuration key.
@@ -418,32 +523,11 @@ func (r *Registry) GetConfigValue(name string, key ConfigKey) (any, error) {
 		return nil, ErrProjectNotFound
 	}
 
-	switch key {
-	case ConfigKeyMaxAgents:
-		return p.MaxAgents, nil
-	case ConfigKeyAutostart:
-		return p.Autostart, nil
-	case ConfigKeyIssueBackend:
-		return p.GetIssueBackend(), nil
-	case ConfigKeyLinearTeam:
-		return p.LinearTeam, nil
-	case ConfigKeyLinearProject:
-		return p.LinearProject, nil
-	case ConfigKeyAllowedAuthors:
-		return p.AllowedAuthors, nil
-	case ConfigKeyPermissionsChecker:
-		return p.GetPermissionsChecker(), nil
-	case ConfigKeyAgentBackend:
-		return p.GetAgentBackend(), nil
-	case ConfigKeyPlannerBackend:
-		return p.GetPlannerBackend(), nil
-	case ConfigKeyCodingBackend:
-		return p.GetCodingBackend(), nil
-	case ConfigKeyMergeStrategy:
-		return p.GetMergeStrategy(), nil
-	default:
+	value, ok := configValue(p, key)
+	if !ok {
 		return nil, errors.New("invalid configuration key")
 	}
+	return value, nil
 }
 
 // GetConfig returns all configuration for a project as a map.
@@ -457,19 +541,183 @@ func (r *Registry) GetConfig(name string) (map[string]any, error) {
 		return nil, ErrProjectNotFound
 	}
 
-	return map[string]any{
-		string(ConfigKeyMaxAgents):          p.MaxAgents,
-		string(ConfigKeyAutostart):          p.Autostart,
-		string(ConfigKeyIssueBackend):       p.GetIssueBackend(),
-		string(ConfigKeyLinearTeam):         p.LinearTeam,
-		string(ConfigKeyLinearProject):      p.LinearProject,
-		string(ConfigKeyAllowedAuthors):     p.AllowedAuthors,
-		string(ConfigKeyPermissionsChecker): p.GetPermissionsChecker(),
-		string(ConfigKeyAgentBackend):       p.GetAgentBackend(),
-		string(ConfigKeyPlannerBackend):     p.GetPlannerBackend(),
-		string(ConfigKeyCodingBackend):      p.GetCodingBackend(),
-		string(ConfigKeyMergeStrategy):      p.GetMergeStrategy(),
-	}, nil
+	config := make(map[string]any, len(ValidConfigKeys()))
+	for _, key := range ValidConfigKeys() {
+		config[string(key)], _ = configValue(p, key)
+	}
+	return config, nil
+}
+
+// ConfigEntry describes one project configuration key.
+type ConfigEntry struct {
+	Key     ConfigKey
+	Value   any  // Effective value: int, bool, string, or []string
+	Default any  // Value the key takes when unset in the project
+	Set     bool // Whether the project sets the key itself
+	Secret  bool // Whether the value is sensitive and must not be displayed
+}
+
+// ConfigEntries returns every configuration key of a project with its
+// effective value, its default, and whether the project sets it, in
+// ValidConfigKeys order. Defaults follow the same precedence as values:
+// global defaults, then internal defaults.
+func (r *Registry) ConfigEntries(name string) ([]ConfigEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, exists := r.projects[name]
+	if !exists {
+		return nil, ErrProjectNotFound
+	}
+
+	// A project with nothing set resolves every key to its default
+	unset := project.NewProject(p.Name, p.RemoteURL)
+	unset.Defaults = p.Defaults
+
+	keys := ValidConfigKeys()
+	entries := make([]ConfigEntry, len(keys))
+	for i, key := range keys {
+		value, _ := configValue(p, key)
+		def, _ := configValue(unset, key)
+		entries[i] = ConfigEntry{
+			Key:     key,
+			Value:   value,
+			Default: def,
+			Set:     isConfigSet(p, key, value, def),
+			Secret:  IsSecretConfigKey(key),
+		}
+	}
+	return entries, nil
+}
+
+// secretKeyMarkers are substrings of config keys whose values are sensitive.
+var secretKeyMarkers = []string{"token", "secret", "password", "api-key", "apikey", "credential"}
+
+// IsSecretConfigKey reports whether a config key holds a sensitive value
+// (a token, password, or API key) that must be redacted when displayed.
+func IsSecretConfigKey(key ConfigKey) bool {
+	k := strings.ToLower(string(key))
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(k, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isConfigSet reports whether a project sets key itself. Keys stored as
+// strings, lists, or optional values are set when present; keys stored as
+// plain numbers or booleans can't be told apart from an explicit default,
+// so they count as set when they differ from it.
+func isConfigSet(p *project.Project, key ConfigKey, value, def any) bool {
+	switch key {
+	case ConfigKeyIssueBackend:
+		return p.IssueBackend != ""
+	case ConfigKeyPermissionsChecker:
+		return p.PermissionsChecker != ""
+	case ConfigKeyAgentBackend:
+		return p.AgentBackend != ""
+	case ConfigKeyPlannerBackend:
+		return p.PlannerBackend != ""
+	case ConfigKeyCodingBackend:
+		return p.CodingBackend != ""
+	case ConfigKeyMergeStrategy:
+		return p.MergeStrategy != ""
+	case ConfigKeyDefaultBranch:
+		return p.DefaultBranch != ""
+	case ConfigKeyAutoRebase:
+		return p.AutoRebase != nil
+	case ConfigKeyAgentNaming:
+		return p.AgentNaming != ""
+	case ConfigKeyPreMergeTimeout:
+		return p.PreMergeTimeout != ""
+	case ConfigKeyPollInterval:
+		return p.PollInterval != ""
+	case ConfigKeyGitAuthorName:
+		return p.GitAuthorName != ""
+	case ConfigKeyGitAuthorEmail:
+		return p.GitAuthorEmail != ""
+	case ConfigKeyPriorityThreshold:
+		return p.PriorityThreshold != 0
+	case ConfigKeyAllowedAuthors:
+		return len(p.AllowedAuthors) > 0
+	case ConfigKeyMaxAgents, ConfigKeyAutostart, ConfigKeyDryRun, ConfigKeyPullBeforeSpawn, ConfigKeyReflectClaims, ConfigKeyAutoClaim, ConfigKeyReservedSlots, ConfigKeyGitHubIssueLimit, ConfigKeyWorktreePoolSize:
+		return value != def
+	default:
+		// Plain strings with an empty default
+		return value != ""
+	}
+}
+
+// configValue returns the effective value of key for p. ok is false for an
+// unknown key.
+func configValue(p *project.Project, key ConfigKey) (value any, ok bool) {
+	switch key {
+	case ConfigKeyMaxAgents:
+		return p.MaxAgents, true
+	case ConfigKeyAutostart:
+		return p.Autostart, true
+	case ConfigKeyDryRun:
+		return p.DryRun, true
+	case ConfigKeyIssueBackend:
+		return p.GetIssueBackend(), true
+	case ConfigKeyLinearTeam:
+		return p.LinearTeam, true
+	case ConfigKeyLinearProject:
+		return p.LinearProject, true
+	case ConfigKeyGitHubHost:
+		return p.GitHubHost, true
+	case ConfigKeyGitHubIssueLimit:
+		return p.GetGitHubIssueLimit(), true
+	case ConfigKeyAllowedAuthors:
+		return p.AllowedAuthors, true
+	case ConfigKeyPermissionsChecker:
+		return p.GetPermissionsChecker(), true
+	case ConfigKeyAgentBackend:
+		return p.GetAgentBackend(), true
+	case ConfigKeyPlannerBackend:
+		return p.GetPlannerBackend(), true
+	case ConfigKeyCodingBackend:
+		return p.GetCodingBackend(), true
+	case ConfigKeyMergeStrategy:
+		return p.GetMergeStrategy(), true
+	case ConfigKeyDefaultBranch:
+		return p.GetDefaultBranch(), true
+	case ConfigKeyAutoRebase:
+		return p.GetAutoRebase(), true
+	case ConfigKeyPullBeforeSpawn:
+		return p.PullBeforeSpawn, true
+	case ConfigKeyReflectClaims:
+		return p.ReflectClaims, true
+	case ConfigKeyAutoClaim:
+		return p.AutoClaim, true
+	case ConfigKeyAgentNaming:
+		return p.GetAgentNaming(), true
+	case ConfigKeyPreMergeCommand:
+		return p.PreMergeCommand, true
+	case ConfigKeyGitAuthorName:
+		return p.GetGitAuthorName(), true
+	case ConfigKeyGitAuthorEmail:
+		return p.GetGitAuthorEmail(), true
+	case ConfigKeyPreMergeTimeout:
+		return p.GetPreMergeTimeout().String(), true
+	case ConfigKeyPollInterval:
+		return p.GetPollInterval().String(), true
+	case ConfigKeyActiveHours:
+		return p.ActiveHours, true
+	case ConfigKeyWorktreePoolSize:
+		return p.GetWorktreePoolSize(), true
+	case ConfigKeyReservedSlots:
+		return p.ReservedSlots, true
+	case ConfigKeyPriorityThreshold:
+		return p.GetPriorityThreshold(), true
+	case ConfigKeyModel:
+		return p.Model, true
+	case ConfigKeyHighPriorityModel:
+		return p.HighPriorityModel, true
+	default:
+		return nil, false
+	}
 }
 
 // SetConfigValue sets a single configuration key for a project.
@@ -482,34 +730,30 @@ func (r *Registry) SetConfigValue(name string, key ConfigKey, value string) erro
 		return ErrProjectNotFound
 	}
 
+	if err := ValidateConfigValue(key, value); err != nil {
+		return err
+	}
+
 	switch key {
 	case ConfigKeyMaxAgents:
-		maxAgents, err := strconv.Atoi(value)
-		if err != nil {
-			return errors.New("invalid value for max-agents: must be a positive integer")
-		}
-		if err := configPkg.ValidateMaxAgents(maxAgents); err != nil {
-			return err
-		}
-		p.MaxAgents = maxAgents
+		p.MaxAgents, _ = strconv.Atoi(value)
 	case ConfigKeyAutostart:
-		autostart, err := strconv.ParseBool(value)
-		if err != nil {
-			return errors.New("invalid value for autostart: must be true or false")
-		}
-		p.Autostart = autostart
+		p.Autostart, _ = strconv.ParseBool(value)
+	case ConfigKeyDryRun:
+		p.DryRun, _ = strconv.ParseBool(value)
 	case ConfigKeyIssueBackend:
-		v := strings.ToLower(value)
-		if v != "tk" && v != "github" && v != "gh" && v != "linear" {
-			return errors.New("invalid value for issue-backend: must be 'tk', 'github', 'gh', or 'linear'")
-		}
-		p.IssueBackend = v
+		p.IssueBackend = strings.ToLower(value)
 	case ConfigKeyLinearTeam:
 		// Linear team ID (UUID or team key)
 		p.LinearTeam = value
 	case ConfigKeyLinearProject:
 		// Linear project ID (UUID or project key) - optional
 		p.LinearProject = value
+	case ConfigKeyGitHubHost:
+		// Empty value detects the host from the git remote
+		p.GitHubHost = strings.ToLower(strings.TrimSpace(value))
+	case ConfigKeyGitHubIssueLimit:
+		p.GitHubIssueLimit, _ = strconv.Atoi(value)
 	case ConfigKeyAllowedAuthors:
 		// Parse comma-separated list of GitHub usernames
 		if value == "" {
@@ -522,40 +766,253 @@ func (r *Registry) SetConfigValue(name string, key ConfigKey, value string) erro
 			p.AllowedAuthors = authors
 		}
 	case ConfigKeyPermissionsChecker:
-		v := strings.ToLower(value)
-		if v != "manual" && v != "llm" {
-			return errors.New("invalid value for permissions-checker: must be 'manual' or 'llm'")
-		}
-		p.PermissionsChecker = v
+		p.PermissionsChecker = strings.ToLower(value)
 	case ConfigKeyAgentBackend:
-		v := strings.ToLower(value)
-		if v != "claude" && v != "codex" {
-			return errors.New("invalid value for agent-backend: must be 'claude' or 'codex'")
-		}
-		p.AgentBackend = v
+		p.AgentBackend = strings.ToLower(value)
 	case ConfigKeyPlannerBackend:
-		v := strings.ToLower(value)
-		if v != "claude" && v != "codex" {
-			return errors.New("invalid value for planner-backend: must be 'claude' or 'codex'")
-		}
-		p.PlannerBackend = v
+		p.PlannerBackend = strings.ToLower(value)
 	case ConfigKeyCodingBackend:
-		v := strings.ToLower(value)
-		if v != "claude" && v != "codex" {
-			return errors.New("invalid value for coding-backend: must be 'claude' or 'codex'")
+		p.CodingBackend = strings.ToLower(value)
+	case ConfigKeyMergeStrategy:
+		p.MergeStrategy = strings.ToLower(value)
+	case ConfigKeyDefaultBranch:
+		p.DefaultBranch = strings.TrimSpace(value)
+	case ConfigKeyAutoRebase:
+		autoRebase, _ := strconv.ParseBool(value)
+		p.AutoRebase = &autoRebase
+	case ConfigKeyPullBeforeSpawn:
+		p.PullBeforeSpawn, _ = strconv.ParseBool(value)
+	case ConfigKeyReflectClaims:
+		p.ReflectClaims, _ = strconv.ParseBool(value)
+	case ConfigKeyAutoClaim:
+		p.AutoClaim, _ = strconv.ParseBool(value)
+	case ConfigKeyAgentNaming:
+		p.AgentNaming = strings.ToLower(value)
+	case ConfigKeyPreMergeCommand:
+		// Empty value disables the pre-merge gate
+		p.SetPreMergeCommand(strings.TrimSpace(value))
+	case ConfigKeyGitAuthorName:
+		// Empty value falls back to the global default
+		p.GitAuthorName = strings.TrimSpace(value)
+	case ConfigKeyGitAuthorEmail:
+		p.GitAuthorEmail = strings.TrimSpace(value)
+	case ConfigKeyPreMergeTimeout:
+		p.PreMergeTimeout = value
+	case ConfigKeyPollInterval:
+		// Empty value falls back to the global default
+		p.PollInterval = value
+	case ConfigKeyActiveHours:
+		// Empty value orchestrates at any time
+		p.ActiveHours = strings.TrimSpace(value)
+	case ConfigKeyWorktreePoolSize:
+		p.WorktreePoolSize, _ = strconv.Atoi(value)
+	case ConfigKeyReservedSlots:
+		reserved, _ := strconv.Atoi(value)
+		if err := configPkg.ValidateMaxAgents(p.MaxAgents + reserved); err != nil {
+			return errors.New("invalid value for reserved-high-priority-slots: max-agents plus reserved slots must not exceed 100")
 		}
-		p.CodingBackend = v
+		p.ReservedSlots = reserved
+	case ConfigKeyPriorityThreshold:
+		p.PriorityThreshold, _ = strconv.Atoi(value)
+	case ConfigKeyModel:
+		// Empty value falls back to the agent CLI's default model
+		p.Model = strings.TrimSpace(value)
+	case ConfigKeyHighPriorityModel:
+		// Empty value uses model for high-priority issues too
+		p.HighPriorityModel = strings.TrimSpace(value)
+	}
+
+	return r.save()
+}
+
+// ValidateConfigValue checks that value is acceptable for key, so bad values
+// are rejected when set rather than when the project is next used.
+// Checks that depend on other keys (such as reserved slots against
+// max-agents) are left to SetConfigValue.
+func ValidateConfigValue(key ConfigKey, value string) error {
+	switch key {
+	case ConfigKeyMaxAgents:
+		maxAgents, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("invalid value for max-agents: must be a positive integer")
+		}
+		return configPkg.ValidateMaxAgents(maxAgents)
+	case ConfigKeyAutostart, ConfigKeyAutoRebase, ConfigKeyDryRun, ConfigKeyPullBeforeSpawn, ConfigKeyReflectClaims, ConfigKeyAutoClaim:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid value for %s: must be true or false", key)
+		}
+	case ConfigKeyIssueBackend:
+		return validateEnum(key, value, "tk", "github", "gh", "linear")
+	case ConfigKeyPermissionsChecker:
+		return validateEnum(key, value, "manual", "llm")
+	case ConfigKeyAgentBackend, ConfigKeyPlannerBackend, ConfigKeyCodingBackend:
+		return validateEnum(key, value, "claude", "codex")
 	case ConfigKeyMergeStrategy:
-		v := strings.ToLower(value)
-		if v != "direct" && v != "pull-request" {
-			return errors.New("invalid value for merge-strategy: must be 'direct' or 'pull-request'")
+		return validateEnum(key, value, "direct", "pull-request")
+	case ConfigKeyAgentNaming:
+		return validateEnum(key, value, "id", "animal", "issue")
+	case ConfigKeyDefaultBranch:
+		if !isValidBranchName(strings.TrimSpace(value)) {
+			return errors.New("invalid value for default-branch: must be a valid git branch name (e.g. 'main', 'master', 'develop')")
+		}
+	case ConfigKeyPreMergeTimeout:
+		if value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return errors.New("invalid value for pre-merge-timeout: must be a positive duration (e.g. '10m')")
+			}
+		}
+	case ConfigKeyPollInterval:
+		if value != "" {
+			if err := configPkg.ValidatePollInterval(value); err != nil {
+				return fmt.Errorf("invalid value for poll-interval: must be a duration of at least %s (e.g. '30s')", configPkg.MinPollInterval)
+			}
 		}
-		p.MergeStrategy = v
+	case ConfigKeyActiveHours:
+		if strings.TrimSpace(value) != "" {
+			if _, err := project.ParseActiveHours(value); err != nil {
+				return fmt.Errorf("invalid value for active-hours: %v", err)
+			}
+		}
+	case ConfigKeyGitHubHost:
+		if value != "" && !isValidHostName(strings.TrimSpace(value)) {
+			return errors.New("invalid value for github-host: must be a host name without scheme or path (e.g. 'github.example.com')")
+		}
+	case ConfigKeyGitHubIssueLimit:
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return errors.New("invalid value for github-issue-limit: must be a positive integer")
+		}
+	case ConfigKeyGitAuthorName, ConfigKeyGitAuthorEmail:
+		if strings.ContainsAny(value, "<>\n") {
+			return fmt.Errorf("invalid value for %s: must be a single line without '<' or '>'", key)
+		}
+	case ConfigKeyWorktreePoolSize:
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 0 {
+			return errors.New("invalid value for worktree-pool-size: must be a non-negative integer")
+		}
+	case ConfigKeyReservedSlots:
+		reserved, err := strconv.Atoi(value)
+		if err != nil || reserved < 0 {
+			return errors.New("invalid value for reserved-high-priority-slots: must be a non-negative integer")
+		}
+	case ConfigKeyPriorityThreshold:
+		threshold, err := strconv.Atoi(value)
+		if err != nil || threshold < 1 || threshold > 2 {
+			return errors.New("invalid value for high-priority-threshold: must be 1 (medium) or 2 (high)")
+		}
+	case ConfigKeyLinearTeam, ConfigKeyLinearProject, ConfigKeyAllowedAuthors,
+		ConfigKeyPreMergeCommand, ConfigKeyModel, ConfigKeyHighPriorityModel:
+		// Free-form values
 	default:
 		return errors.New("invalid configuration key")
 	}
+	return nil
+}
 
-	return r.save()
+// ValidateProject re-checks a project's stored configuration, catching bad
+// values written to config.toml by hand. Unset keys are skipped since they
+// fall back to defaults. Unlike ValidateConfigValue it also checks keys that
+// depend on each other.
+func (r *Registry) ValidateProject(name string) ([]error, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, exists := r.projects[name]
+	if !exists {
+		return nil, ErrProjectNotFound
+	}
+
+	type keyValue struct {
+		key   ConfigKey
+		value string
+	}
+	values := []keyValue{
+		{ConfigKeyMaxAgents, strconv.Itoa(p.MaxAgents)},
+		{ConfigKeyIssueBackend, p.IssueBackend},
+		{ConfigKeyPermissionsChecker, p.PermissionsChecker},
+		{ConfigKeyAgentBackend, p.AgentBackend},
+		{ConfigKeyPlannerBackend, p.PlannerBackend},
+		{ConfigKeyCodingBackend, p.CodingBackend},
+		{ConfigKeyMergeStrategy, p.MergeStrategy},
+		{ConfigKeyDefaultBranch, p.DefaultBranch},
+		{ConfigKeyGitHubHost, p.GitHubHost},
+		{ConfigKeyPreMergeTimeout, p.PreMergeTimeout},
+		{ConfigKeyPollInterval, p.PollInterval},
+		{ConfigKeyActiveHours, p.ActiveHours},
+		{ConfigKeyReservedSlots, strconv.Itoa(p.ReservedSlots)},
+		{ConfigKeyWorktreePoolSize, strconv.Itoa(p.WorktreePoolSize)},
+		{ConfigKeyGitAuthorName, p.GitAuthorName},
+		{ConfigKeyGitAuthorEmail, p.GitAuthorEmail},
+	}
+	if p.GitHubIssueLimit != 0 {
+		values = append(values, keyValue{ConfigKeyGitHubIssueLimit, strconv.Itoa(p.GitHubIssueLimit)})
+	}
+	if p.PriorityThreshold != 0 {
+		values = append(values, keyValue{ConfigKeyPriorityThreshold, strconv.Itoa(p.PriorityThreshold)})
+	}
+
+	var problems []error
+	for _, v := range values {
+		if v.value == "" {
+			continue
+		}
+		if err := ValidateConfigValue(v.key, v.value); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	if p.ReservedSlots > 0 && configPkg.ValidateMaxAgents(p.MaxAgents+p.ReservedSlots) != nil {
+		problems = append(problems, errors.New("invalid value for reserved-high-priority-slots: max-agents plus reserved slots must not exceed 100"))
+	}
+	if p.GetIssueBackend() == "linear" && p.LinearTeam == "" {
+		problems = append(problems, errors.New("linear-team must be set when issue-backend is 'linear'"))
+	}
+
+	return problems, nil
+}
+
+// validateEnum checks that value (case-insensitively) is one of allowed.
+func validateEnum(key ConfigKey, value string, allowed ...string) error {
+	if slices.Contains(allowed, strings.ToLower(value)) {
+		return nil
+	}
+	quoted := make([]string, len(allowed))
+	for i, a := range allowed {
+		quoted[i] = "'" + a + "'"
+	}
+	return fmt.Errorf("invalid value for %s: %q is not one of %s", key, value, strings.Join(quoted, ", "))
+}
+
+// isValidBranchName reports whether name is usable as a git branch name.
+// This is a conservative subset of git check-ref-format rules.
+func isValidBranchName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "-") || strings.HasPrefix(name, "/") ||
+		strings.HasSuffix(name, "/") || strings.HasSuffix(name, ".") || strings.HasSuffix(name, ".lock") ||
+		strings.Contains(name, "..") || strings.Contains(name, "//") || strings.Contains(name, "@{") {
+		return false
+	}
+	for _, r := range name {
+		if r <= ' ' || r == 0x7f || strings.ContainsRune("~^:?*[\\", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidHostName reports whether name is a bare host name, optionally with
+// a port, as used for GitHub Enterprise servers.
+func isValidHostName(name string) bool {
+	if name == "" || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == ':') {
+			return false
+		}
+	}
+	return true
 }
 
 // Count returns the number of registered projects.
@@ -349,6 +349,9 @@ api-key = "sk-secret-key"
 provider = "anthropic"
 model = "claude-haiku-4-5"
 
+[issue-type-prompts]
+bug = "Write a failing test first."
+
 [[projects]]
 name = "existing-project"
 remote-url = "git@github.com:user/existing.git"
@@ -395,6 +398,9 @@ max-agents = 2
 	if !strings.Contains(configStr, `model = "claude-haiku-4-5"`) {
 		t.Errorf("Config should contain model = claude-haiku, got:\n%s", configStr)
 	}
+	if !strings.Contains(configStr, `bug = "Write a failing test first."`) {
+		t.Errorf("Config should contain issue-type-prompts, got:\n%s", configStr)
+	}
 
 	// Verify both projects are present
 	if !strings.Contains(configStr, `name = "existing-project"`) {
@@ -458,6 +464,307 @@ max-agents = 1
 	}
 }
 
+func TestRegistry_SetPreMergeConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+
+	r, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("NewWithPath() error = %v", err)
+	}
+	if _, err := r.Add("git@github.com:user/test.git", "test-project", 0, false, ""); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if err := r.SetConfigValue("test-project", ConfigKeyPreMergeCommand, "go test ./..."); err != nil {
+		t.Fatalf("SetConfigValue(pre-merge-command) error = %v", err)
+	}
+	if err := r.SetConfigValue("test-project", ConfigKeyPreMergeTimeout, "forever"); err == nil {
+		t.Error("SetConfigValue(pre-merge-timeout, forever) should fail")
+	}
+	if err := r.SetConfigValue("test-project", ConfigKeyPreMergeTimeout, "15m"); err != nil {
+		t.Fatalf("SetConfigValue(pre-merge-timeout) error = %v", err)
+	}
+
+	// Reload from disk to verify persistence
+	r2, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("NewWithPath() reload error = %v", err)
+	}
+	cmd, err := r2.GetConfigValue("test-project", ConfigKeyPreMergeCommand)
+	if err != nil || cmd != "go test ./..." {
+		t.Errorf("pre-merge-command = %v, %v; want %q", cmd, err, "go test ./...")
+	}
+	timeout, err := r2.GetConfigValue("test-project", ConfigKeyPreMergeTimeout)
+	if err != nil || timeout != "15m0s" {
+		t.Errorf("pre-merge-timeout = %v, %v; want 15m0s", timeout, err)
+	}
+}
+
+func TestRegistry_SetModelConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+
+	r, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("NewWithPath() error = %v", err)
+	}
+	if _, err := r.Add("git@github.com:user/test.git", "test-project", 0, false, ""); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if err := r.SetConfigValue("test-project", ConfigKeyModel, "sonnet"); err != nil {
+		t.Fatalf("SetConfigValue(model) error = %v", err)
+	}
+	if err := r.SetConfigValue("test-project", ConfigKeyHighPriorityModel, " opus "); err != nil {
+		t.Fatalf("SetConfigValue(high-priority-model) error = %v", err)
+	}
+
+	// Reload from disk to verify persistence
+	r2, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("NewWithPath() reload error = %v", err)
+	}
+	p, err := r2.Get("test-project")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if p.Model != "sonnet" || p.HighPriorityModel != "opus" {
+		t.Errorf("Model = %q, HighPriorityModel = %q; want sonnet, opus", p.Model, p.HighPriorityModel)
+	}
+}
+
+func TestRegistry_SetDefaultBranch(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+
+	r, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("NewWithPath() error = %v", err)
+	}
+	if _, err := r.Add("git@github.com:user/test.git", "test-project", 0, false, ""); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	branch, err := r.GetConfigValue("test-project", ConfigKeyDefaultBranch)
+	if err != nil || branch != "main" {
+		t.Errorf("default-branch = %v, %v; want main", branch, err)
+	}
+
+	for _, invalid := range []string{"", "-x", "a..b", "has space", "ref.lock", "a:b", "trailing/"} {
+		if err := r.SetConfigValue("test-project", ConfigKeyDefaultBranch, invalid); err == nil {
+			t.Errorf("SetConfigValue(default-branch, %q) should fail", invalid)
+		}
+	}
+	if err := r.SetConfigValue("test-project", ConfigKeyDefaultBranch, "release/v2"); err != nil {
+		t.Fatalf("SetConfigValue(default-branch) error = %v", err)
+	}
+
+	// Reload from disk to verify persistence
+	r2, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("NewWithPath() reload error = %v", err)
+	}
+	branch, err = r2.GetConfigValue("test-project", ConfigKeyDefaultBranch)
+	if err != nil || branch != "release/v2" {
+		t.Errorf("default-branch = %v, %v; want release/v2", branch, err)
+	}
+}
+
+func TestRegistry_ConfigEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	r, err := NewWithPath(filepath.Join(tmpDir, "config.toml"))
+	if err != nil {
+		t.Fatalf("NewWithPath() error = %v", err)
+	}
+	if _, err := r.Add("git@github.com:user/test.git", "test-project", 5, false, ""); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := r.SetConfigValue("test-project", ConfigKeyMergeStrategy, "pull-request"); err != nil {
+		t.Fatalf("SetConfigValue() error = %v", err)
+	}
+
+	entries, err := r.ConfigEntries("test-project")
+	if err != nil {
+		t.Fatalf("ConfigEntries() error = %v", err)
+	}
+	if len(entries) != len(ValidConfigKeys()) {
+		t.Errorf("got %d entries, want one per key (%d)", len(entries), len(ValidConfigKeys()))
+	}
+	byKey := make(map[ConfigKey]ConfigEntry)
+	for _, e := range entries {
+		byKey[e.Key] = e
+	}
+
+	tests := []struct {
+		key        ConfigKey
+		value, def any
+		set        bool
+	}{
+		{ConfigKeyMaxAgents, 5, 3, true},
+		{ConfigKeyAutostart, false, false, false},
+		{ConfigKeyMergeStrategy, "pull-request", "direct", true},
+		{ConfigKeyAutoRebase, true, true, false},
+		{ConfigKeyPreMergeTimeout, "10m0s", "10m0s", false},
+		{ConfigKeyPollInterval, "10s", "10s", false},
+		{ConfigKeyGitHubIssueLimit, 1000, 1000, false},
+		{ConfigKeyWorktreePoolSize, 0, 0, false},
+		{ConfigKeyGitAuthorName, "fab-bot", "fab-bot", false},
+		{ConfigKeyModel, "", "", false},
+	}
+	for _, tt := range tests {
+		e := byKey[tt.key]
+		if e.Value != tt.value || e.Default != tt.def || e.Set != tt.set {
+			t.Errorf("%s = {%v, default %v, set %v}, want {%v, default %v, set %v}",
+				tt.key, e.Value, e.Default, e.Set, tt.value, tt.def, tt.set)
+		}
+	}
+
+	if _, err := r.ConfigEntries("missing"); err != ErrProjectNotFound {
+		t.Errorf("ConfigEntries(missing) error = %v, want ErrProjectNotFound", err)
+	}
+}
+
+func TestIsSecretConfigKey(t *testing.T) {
+	for _, key := range ValidConfigKeys() {
+		if IsSecretConfigKey(key) {
+			t.Errorf("IsSecretConfigKey(%s) = true, want false", key)
+		}
+	}
+	for _, key := range []ConfigKey{"linear-api-key", "github-token", "webhook-secret"} {
+		if !IsSecretConfigKey(key) {
+			t.Errorf("IsSecretConfigKey(%s) = false, want true", key)
+		}
+	}
+}
+
+func TestValidateConfigValue(t *testing.T) {
+	tests := []struct {
+		key     ConfigKey
+		value   string
+		wantErr bool
+	}{
+		{ConfigKeyIssueBackend, "tk", false},
+		{ConfigKeyIssueBackend, "GitHub", false},
+		{ConfigKeyIssueBackend, "banana", true},
+		{ConfigKeyMaxAgents, "3", false},
+		{ConfigKeyMaxAgents, "0", true},
+		{ConfigKeyMaxAgents, "101", true},
+		{ConfigKeyMaxAgents, "lots", true},
+		{ConfigKeyAutostart, "yes", true},
+		{ConfigKeyAutoRebase, "false", false},
+		{ConfigKeyDryRun, "true", false},
+		{ConfigKeyDryRun, "maybe", true},
+		{ConfigKeyPullBeforeSpawn, "true", false},
+		{ConfigKeyPullBeforeSpawn, "later", true},
+		{ConfigKeyReflectClaims, "true", false},
+		{ConfigKeyReflectClaims, "sometimes", true},
+		{ConfigKeyAutoClaim, "false", false},
+		{ConfigKeyAutoClaim, "always", true},
+		{ConfigKeyAgentNaming, "animal", false},
+		{ConfigKeyAgentNaming, "planet", true},
+		{ConfigKeyCodingBackend, "codex", false},
+		{ConfigKeyCodingBackend, "gpt", true},
+		{ConfigKeyMergeStrategy, "squash", true},
+		{ConfigKeyPreMergeTimeout, "", false},
+		{ConfigKeyPreMergeTimeout, "-1m", true},
+		{ConfigKeyPollInterval, "", false},
+		{ConfigKeyPollInterval, "1m", false},
+		{ConfigKeyPollInterval, "500ms", true},
+		{ConfigKeyPollInterval, "often", true},
+		{ConfigKeyPriorityThreshold, "3", true},
+		{ConfigKeyActiveHours, "", false},
+		{ConfigKeyActiveHours, "09:00-18:00 Mon-Fri, UTC", false},
+		{ConfigKeyActiveHours, "9 to 5", true},
+		{ConfigKeyModel, "anything", false},
+		{ConfigKeyGitHubHost, "", false},
+		{ConfigKeyGitHubHost, "github.example.com", false},
+		{ConfigKeyGitHubHost, "ghe.internal:8443", false},
+		{ConfigKeyGitHubHost, "https://github.example.com", true},
+		{ConfigKeyGitHubHost, "github.example.com/api", true},
+		{ConfigKeyGitHubIssueLimit, "5000", false},
+		{ConfigKeyGitHubIssueLimit, "0", true},
+		{ConfigKeyGitHubIssueLimit, "all", true},
+		{ConfigKeyWorktreePoolSize, "2", false},
+		{ConfigKeyWorktreePoolSize, "0", false},
+		{ConfigKeyWorktreePoolSize, "-1", true},
+		{ConfigKeyGitAuthorName, "Release Bot", false},
+		{ConfigKeyGitAuthorEmail, "bot@example.com", false},
+		{ConfigKeyGitAuthorEmail, "<bot@example.com>", true},
+		{ConfigKey("unknown"), "x", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.key)+"="+tt.value, func(t *testing.T) {
+			err := ValidateConfigValue(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfigValue(%s, %q) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+
+	err := ValidateConfigValue(ConfigKeyIssueBackend, "banana")
+	if err == nil || !strings.Contains(err.Error(), "'tk'") {
+		t.Errorf("issue-backend error should list allowed values, got %v", err)
+	}
+}
+
+func TestRegistry_ValidateProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+
+	// Hand-edited values that SetConfigValue would have rejected
+	cfg := `[[projects]]
+name = "good"
+remote-url = "git@github.com:user/good.git"
+max-agents = 3
+
+[[projects]]
+name = "bad"
+remote-url = "git@github.com:user/bad.git"
+max-agents = 99
+issue-backend = "linear"
+merge-strategy = "squash"
+pre-merge-timeout = "soon"
+reserved-high-priority-slots = 5
+`
+	if err := os.WriteFile(configPath, []byte(cfg), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	r, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("NewWithPath() error = %v", err)
+	}
+
+	problems, err := r.ValidateProject("good")
+	if err != nil || len(problems) != 0 {
+		t.Errorf("ValidateProject(good) = %v, %v; want no problems", problems, err)
+	}
+
+	problems, err = r.ValidateProject("bad")
+	if err != nil {
+		t.Fatalf("ValidateProject(bad) error = %v", err)
+	}
+	var msgs []string
+	for _, p := range problems {
+		msgs = append(msgs, p.Error())
+	}
+	joined := strings.Join(msgs, "\n")
+	for _, want := range []string{"merge-strategy", "pre-merge-timeout", "reserved-high-priority-slots", "linear-team"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("problems should mention %s, got:\n%s", want, joined)
+		}
+	}
+	if len(problems) != 4 {
+		t.Errorf("got %d problems, want 4:\n%s", len(problems), joined)
+	}
+
+	if _, err := r.ValidateProject("missing"); err != ErrProjectNotFound {
+		t.Errorf("ValidateProject(missing) error = %v, want ErrProjectNotFound", err)
+	}
+}
+
 func TestRegistry_AddWithBackend(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "config.toml")
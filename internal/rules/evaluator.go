@@ -82,9 +82,15 @@ func (e *Evaluator) Evaluate(ctx context.Context, projectName, toolName string,
 	}
 
 	// Evaluate rules in order
-	primaryField := ResolvePrimaryField(toolName, toolInput)
-	matchString := toolName + ":" + primaryField
+	matchString := toolName + ":" + ResolvePrimaryField(toolName, toolInput)
 	slog.Info("tool use request", "match_string", matchString)
+	return evaluateRules(ctx, allRules, toolName, toolInput, cwd)
+}
+
+// evaluateRules applies rules in order. The first matching rule that isn't
+// a pass decides the action.
+func evaluateRules(ctx context.Context, allRules []Rule, toolName string, toolInput json.RawMessage, cwd string) (Action, bool, error) {
+	primaryField := ResolvePrimaryField(toolName, toolInput)
 	slog.Debug("evaluating rules", "tool", toolName, "primaryField", primaryField, "ruleCount", len(allRules), "cwd", cwd)
 
 	for _, rule := range allRules {
@@ -79,10 +79,26 @@ func MatchPattern(pattern, value string) bool {
 	return pattern == value
 }
 
+// primaryFields maps tool names to the input field their rules match against.
+var primaryFields = map[string]string{
+	"Bash":         "command",
+	"Read":         "file_path",
+	"Write":        "file_path",
+	"Edit":         "file_path",
+	"Glob":         "pattern",
+	"Grep":         "pattern",
+	"WebFetch":     "url",
+	"Task":         "prompt",
+	"Skill":        "skill",
+	"WebSearch":    "query",
+	"NotebookEdit": "notebook_path",
+}
+
 // ResolvePrimaryField extracts the primary field value for matching based on tool type.
 // Returns empty string if the field cannot be extracted.
 func ResolvePrimaryField(toolName string, toolInput json.RawMessage) string {
-	if len(toolInput) == 0 {
+	field, ok := primaryFields[toolName]
+	if !ok || len(toolInput) == 0 {
 		return ""
 	}
 
@@ -91,45 +107,9 @@ func ResolvePrimaryField(toolName string, toolInput json.RawMessage) string {
 		return ""
 	}
 
-	switch toolName {
-	case "Bash":
-		if cmd, ok := input["command"].(string); ok {
-			return cmd
-		}
-	case "Read", "Write", "Edit":
-		if path, ok := input["file_path"].(string); ok {
-			return path
-		}
-	case "Glob":
-		if pattern, ok := input["pattern"].(string); ok {
-			return pattern
-		}
-	case "Grep":
-		if pattern, ok := input["pattern"].(string); ok {
-			return pattern
-		}
-	case "WebFetch":
-		if url, ok := input["url"].(string); ok {
-			return url
-		}
-	case "Task":
-		if prompt, ok := input["prompt"].(string); ok {
-			return prompt
-		}
-	case "Skill":
-		if skill, ok := input["skill"].(string); ok {
-			return skill
-		}
-	case "WebSearch":
-		if query, ok := input["query"].(string); ok {
-			return query
-		}
-	case "NotebookEdit":
-		if path, ok := input["notebook_path"].(string); ok {
-			return path
-		}
+	if value, ok := input[field].(string); ok {
+		return value
 	}
-
 	return ""
 }
 
@@ -0,0 +1,118 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RoleEnvVar carries an agent's role into its CLI process, so the permission
+// hook can apply the role's rules before any other rule.
+const RoleEnvVar = "FAB_AGENT_ROLE"
+
+// RoleReviewer is the role of agents that read the codebase and comment but
+// never write: file edits are denied and Bash is limited to read-only
+// commands.
+const RoleReviewer = "reviewer"
+
+// ReviewerRules allow the read-only tools and commands a reviewer needs.
+// Anything they don't allow is denied.
+var ReviewerRules = []Rule{
+	{Tool: "Read", Action: ActionAllow},
+	{Tool: "Glob", Action: ActionAllow},
+	{Tool: "Grep", Action: ActionAllow},
+	{Tool: "TodoWrite", Action: ActionAllow},
+	{Tool: "WebSearch", Action: ActionAllow},
+	{Tool: "WebFetch", Action: ActionAllow},
+	// Subagents' tool calls go through the same hook and rules
+	{Tool: "Task", Action: ActionAllow},
+	{Tool: "Bash", Action: ActionAllow, Patterns: readOnlyCommandPatterns(
+		"fab status", "fab claims", "fab agent list", "fab agent lineage",
+		"fab issue list", "fab issue show", "fab issue ready", "fab issue comment",
+		"git status", "git diff", "git log", "git show", "git blame", "git grep",
+		"ls", "cat", "head", "tail", "wc", "grep", "rg",
+	)},
+}
+
+// readOnlyCommandPatterns returns patterns matching each command alone or
+// with arguments, but not commands that merely share a prefix.
+func readOnlyCommandPatterns(commands ...string) []string {
+	patterns := make([]string, 0, 2*len(commands))
+	for _, c := range commands {
+		patterns = append(patterns, c, c+" :*")
+	}
+	return patterns
+}
+
+// shellControlChars could chain, substitute, or redirect a Bash command
+// past a prefix rule (e.g., "git status; rm -rf .").
+const shellControlChars = ";&|<>`$\n"
+
+// reviewerDeniedFlags are long options of the allowed commands that write
+// files or run programs: rg --pre, git grep --open-files-in-pager, and git
+// diff/log/show --output and --ext-diff.
+var reviewerDeniedFlags = []string{"--pre", "--open-files-in-pager", "--output", "--ext-diff"}
+
+// hasDeniedFlag reports whether a Bash command passes one of
+// reviewerDeniedFlags, or git's -O (open matches in a pager) in a cluster of
+// short options. Quotes are dropped first, since the shell would drop them
+// too, and any unique prefix of a long option counts, as git accepts
+// abbreviations.
+func hasDeniedFlag(command string) bool {
+	unquote := strings.NewReplacer(`"`, "", `'`, "", `\`, "")
+	for _, arg := range strings.Fields(unquote.Replace(command)) {
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			name, _, _ := strings.Cut(arg, "=")
+			if len(name) <= 2 {
+				continue
+			}
+			for _, flag := range reviewerDeniedFlags {
+				if strings.HasPrefix(flag, name) {
+					return true
+				}
+			}
+		case strings.HasPrefix(arg, "-") && strings.Contains(arg, "O"):
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRole checks an agent role. The empty role has no restrictions.
+func ValidateRole(role string) error {
+	switch role {
+	case "", RoleReviewer:
+		return nil
+	default:
+		return fmt.Errorf("invalid role %q: must be %q or empty", role, RoleReviewer)
+	}
+}
+
+// EvaluateRole decides a tool invocation from the agent's role alone.
+// matched is false for the empty role, leaving the decision to the usual
+// rules. For a reviewer, anything ReviewerRules don't allow is denied, as
+// is any Bash command using shell control characters or flags that write
+// files or run programs.
+func EvaluateRole(ctx context.Context, role, toolName string, toolInput json.RawMessage, cwd string) (Action, bool, error) {
+	if role != RoleReviewer {
+		return ActionPass, false, nil
+	}
+
+	if toolName == "Bash" {
+		command := ResolvePrimaryField(toolName, toolInput)
+		if strings.ContainsAny(command, shellControlChars) || hasDeniedFlag(command) {
+			return ActionDeny, true, nil
+		}
+	}
+
+	action, matched, err := evaluateRules(ctx, ReviewerRules, toolName, toolInput, cwd)
+	if err != nil {
+		return ActionDeny, true, err
+	}
+	if !matched || action != ActionAllow {
+		return ActionDeny, true, nil
+	}
+	return ActionAllow, true, nil
+}
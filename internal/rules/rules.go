@@ -2,8 +2,14 @@
 package rules
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/BurntSushi/toml"
 
@@ -40,10 +46,32 @@ type ManagerConfig struct {
 	AllowedPatterns []string `toml:"allowed-patterns,omitempty"`
 }
 
+// Headless fallbacks for permission requests the allow lists don't cover.
+const (
+	// HeadlessHold keeps the request pending until a TUI attaches to answer it.
+	HeadlessHold = "hold"
+	// HeadlessDeny rejects the request immediately.
+	HeadlessDeny = "deny"
+)
+
+// HeadlessConfig controls permission requests that arrive while no TUI is
+// attached to answer them.
+type HeadlessConfig struct {
+	// Fallback applies to requests not covered by the allow lists:
+	// "hold" (default) or "deny".
+	Fallback string `toml:"fallback,omitempty"`
+	// AllowedPatterns are Bash command patterns allowed automatically.
+	// Uses the same pattern syntax as manager.allowed-patterns (e.g., "go test:*").
+	AllowedPatterns []string `toml:"allowed-patterns,omitempty"`
+	// AllowedTools are tools allowed automatically whatever their input (e.g., "Read").
+	AllowedTools []string `toml:"allowed-tools,omitempty"`
+}
+
 // Config represents a permissions configuration file.
 type Config struct {
-	Rules   []Rule         `toml:"rules"`
-	Manager *ManagerConfig `toml:"manager,omitempty"`
+	Rules    []Rule          `toml:"rules"`
+	Manager  *ManagerConfig  `toml:"manager,omitempty"`
+	Headless *HeadlessConfig `toml:"headless,omitempty"`
 }
 
 // DefaultManagerAllowedPatterns returns the default allowed patterns for the manager.
@@ -80,6 +108,13 @@ func LoadConfig(path string) (*Config, error) {
 		}
 	}
 
+	// Validate headless config if present
+	if h := cfg.Headless; h != nil {
+		if err := config.ValidateHeadlessConfig(h.Fallback, h.AllowedPatterns, h.AllowedTools); err != nil {
+			return nil, fmt.Errorf("headless: %w", err)
+		}
+	}
+
 	return &cfg, nil
 }
 
@@ -92,6 +127,118 @@ func (c *Config) ManagerAllowedPatterns() []string {
 	return DefaultManagerAllowedPatterns
 }
 
+// HeadlessPolicy returns the headless permission policy from the config.
+// With no headless section, nothing is auto-allowed and requests are held.
+func (c *Config) HeadlessPolicy() HeadlessConfig {
+	var h HeadlessConfig
+	if c != nil && c.Headless != nil {
+		h = *c.Headless
+	}
+	if h.Fallback == "" {
+		h.Fallback = HeadlessHold
+	}
+	return h
+}
+
+// Allows reports whether the policy auto-allows a tool invocation.
+// Bash commands that chain, substitute, or redirect (";", "&&", "|", "$", ">", ...) are
+// never matched by AllowedPatterns, since a prefix match can't vouch for
+// the rest of the command line.
+func (h HeadlessConfig) Allows(toolName string, toolInput json.RawMessage) bool {
+	if slices.Contains(h.AllowedTools, toolName) {
+		return true
+	}
+	if toolName != "Bash" || len(h.AllowedPatterns) == 0 {
+		return false
+	}
+
+	command := strings.TrimSpace(ResolvePrimaryField(toolName, toolInput))
+	if command == "" || strings.ContainsAny(command, ";&|`$<>\n") {
+		return false
+	}
+	for _, pattern := range h.AllowedPatterns {
+		if MatchPattern(pattern, command) {
+			return true
+		}
+	}
+	return false
+}
+
+// RememberRule builds a rule that matches exactly one tool invocation, so a
+// decision made in the TUI can be persisted and applied to identical
+// requests. Tools without a primary field are matched whatever their input.
+func RememberRule(action Action, toolName string, toolInput json.RawMessage) (Rule, error) {
+	if action != ActionAllow && action != ActionDeny {
+		return Rule{}, fmt.Errorf("cannot remember %q decision", action)
+	}
+
+	rule := Rule{Tool: toolName, Action: action}
+	if _, ok := primaryFields[toolName]; !ok {
+		return rule, nil
+	}
+
+	value := ResolvePrimaryField(toolName, toolInput)
+	switch {
+	case value == "":
+		return Rule{}, fmt.Errorf("%s request has no %s to match", toolName, primaryFields[toolName])
+	case strings.HasSuffix(value, ":*"), value == "~", strings.HasPrefix(value, "~/"):
+		// These would be read back as a prefix match or a home directory path
+		return Rule{}, fmt.Errorf("cannot match %q exactly", value)
+	case strings.HasPrefix(value, "/"):
+		// Escape absolute paths so they aren't rewritten as worktree-scoped
+		value = "/" + value
+	}
+	rule.Pattern = value
+	return rule, nil
+}
+
+// appendMu serializes AppendRule so concurrent writes don't interleave.
+var appendMu sync.Mutex
+
+// AppendRule adds a rule to the end of the permissions config at path,
+// creating the file if needed. Existing content and comments are left
+// untouched, and a rule already present is not added again. Appended rules
+// come last, so earlier rules in the file still take precedence.
+func AppendRule(path string, rule Rule) error {
+	if err := config.ValidateRule(rule.Tool, string(rule.Action), rule.Pattern, rule.Patterns, rule.Script); err != nil {
+		return err
+	}
+
+	appendMu.Lock()
+	defer appendMu.Unlock()
+
+	// Refuse to append to a file we can't parse, since the result wouldn't load either
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	if cfg != nil && slices.ContainsFunc(cfg.Rules, func(r Rule) bool {
+		return r.Tool == rule.Tool && r.Action == rule.Action && r.Pattern == rule.Pattern &&
+			len(r.Patterns) == 0 && r.Script == ""
+	}) {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\n# Remembered from a TUI decision\n[[rules]]\n")
+	if err := toml.NewEncoder(&buf).Encode(rule); err != nil {
+		return fmt.Errorf("encode rule: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create rules directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open rules file %s: %w", path, err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write rules file %s: %w", path, err)
+	}
+	return f.Close()
+}
+
 // GlobalConfigPath returns the path to the global permissions config.
 func GlobalConfigPath() (string, error) {
 	return paths.PermissionsPath()
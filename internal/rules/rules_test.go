@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -814,3 +815,253 @@ func TestEvaluatorWithDefaultRules(t *testing.T) {
 		})
 	}
 }
+
+func TestHeadlessPolicy_Default(t *testing.T) {
+	var cfg *Config
+	policy := cfg.HeadlessPolicy()
+	if policy.Fallback != HeadlessHold {
+		t.Errorf("Fallback = %q, want %q", policy.Fallback, HeadlessHold)
+	}
+	if policy.Allows("Read", json.RawMessage(`{"file_path":"/tmp/x"}`)) {
+		t.Error("default policy should not allow anything")
+	}
+}
+
+func TestHeadlessPolicy_Allows(t *testing.T) {
+	policy := HeadlessConfig{
+		AllowedPatterns: []string{"go test:*", "fab:*"},
+		AllowedTools:    []string{"Read", "Grep"},
+	}
+
+	tests := []struct {
+		name  string
+		tool  string
+		input string
+		want  bool
+	}{
+		{"allowed tool", "Read", `{"file_path":"/etc/passwd"}`, true},
+		{"other tool", "Write", `{"file_path":"/tmp/x"}`, false},
+		{"matching command", "Bash", `{"command":"go test ./..."}`, true},
+		{"second pattern", "Bash", `{"command":"fab agent list"}`, true},
+		{"non-matching command", "Bash", `{"command":"rm -rf /"}`, false},
+		{"chained command", "Bash", `{"command":"go test ./... && rm -rf /"}`, false},
+		{"piped command", "Bash", `{"command":"fab agent list | sh"}`, false},
+		{"command substitution", "Bash", `{"command":"fab issue show $(cat secret)"}`, false},
+		{"redirect", "Bash", `{"command":"fab status > ~/.bashrc"}`, false},
+		{"multi-line command", "Bash", `{"command":"go test ./...\nrm -rf /"}`, false},
+		{"missing command", "Bash", `{}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Allows(tt.tool, json.RawMessage(tt.input)); got != tt.want {
+				t.Errorf("Allows(%s, %s) = %v, want %v", tt.tool, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Headless(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "permissions.toml")
+
+	content := `
+[headless]
+fallback = "deny"
+allowed-patterns = ["go test:*"]
+allowed-tools = ["Read"]
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	policy := cfg.HeadlessPolicy()
+	if policy.Fallback != HeadlessDeny {
+		t.Errorf("Fallback = %q, want deny", policy.Fallback)
+	}
+	if !policy.Allows("Bash", json.RawMessage(`{"command":"go test ./..."}`)) {
+		t.Error("expected go test to be allowed")
+	}
+
+	for _, bad := range []string{
+		"[headless]\nfallback = \"allow\"\n",
+		"[headless]\nallowed-tools = [\"NoSuchTool\"]\n",
+		"[headless]\nallowed-patterns = [\"\"]\n",
+	} {
+		if err := os.WriteFile(configPath, []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(configPath); err == nil {
+			t.Errorf("LoadConfig(%q) expected error", bad)
+		}
+	}
+}
+
+func TestRememberRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      Action
+		tool        string
+		input       string
+		wantPattern string
+		wantErr     bool
+	}{
+		{"bash command", ActionAllow, "Bash", `{"command":"go test ./..."}`, "go test ./...", false},
+		{"absolute path escaped", ActionDeny, "Write", `{"file_path":"/etc/hosts"}`, "//etc/hosts", false},
+		{"tool without primary field", ActionAllow, "TodoWrite", `{"todos":[]}`, "", false},
+		{"missing primary field", ActionAllow, "Bash", `{}`, "", true},
+		{"prefix-like value", ActionAllow, "Bash", `{"command":"echo :*"}`, "", true},
+		{"home path", ActionAllow, "Read", `{"file_path":"~/notes"}`, "", true},
+		{"pass action", ActionPass, "Bash", `{"command":"ls"}`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := RememberRule(tt.action, tt.tool, json.RawMessage(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RememberRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if rule.Tool != tt.tool || rule.Action != tt.action || rule.Pattern != tt.wantPattern {
+				t.Errorf("RememberRule() = %+v, want tool %q action %q pattern %q", rule, tt.tool, tt.action, tt.wantPattern)
+			}
+		})
+	}
+}
+
+func TestAppendRule(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FAB_DIR", dir)
+
+	path, err := ProjectConfigPath("demo")
+	if err != nil {
+		t.Fatalf("ProjectConfigPath() error = %v", err)
+	}
+
+	// Existing content must survive the append
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := "# keep me\n[manager]\nallowed-patterns = [\"fab:*\"]\n"
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	input := json.RawMessage(`{"file_path":"/etc/hosts"}`)
+	rule, err := RememberRule(ActionAllow, "Read", input)
+	if err != nil {
+		t.Fatalf("RememberRule() error = %v", err)
+	}
+	for range 2 {
+		if err := AppendRule(path, rule); err != nil {
+			t.Fatalf("AppendRule() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), existing) {
+		t.Errorf("existing content not preserved:\n%s", data)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if len(cfg.Rules) != 1 {
+		t.Fatalf("rules = %d, want 1 (duplicate not skipped)", len(cfg.Rules))
+	}
+	if got := cfg.ManagerAllowedPatterns(); len(got) != 1 || got[0] != "fab:*" {
+		t.Errorf("ManagerAllowedPatterns() = %v", got)
+	}
+
+	evaluator := NewEvaluator()
+	ctx := context.Background()
+	action, matched, err := evaluator.Evaluate(ctx, "demo", "Read", input, "/work")
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if !matched || action != ActionAllow {
+		t.Errorf("Evaluate() = %v, %v, want allow, true", action, matched)
+	}
+
+	other := json.RawMessage(`{"file_path":"/etc/passwd"}`)
+	if _, matched, _ := evaluator.Evaluate(ctx, "demo", "Read", other, "/work"); matched {
+		t.Error("remembered rule should only match the exact path")
+	}
+}
+
+func TestEvaluateRole(t *testing.T) {
+	bash := func(cmd string) json.RawMessage {
+		input, _ := json.Marshal(map[string]string{"command": cmd})
+		return input
+	}
+	file := json.RawMessage(`{"file_path":"/repo/main.go"}`)
+
+	tests := []struct {
+		name        string
+		role        string
+		tool        string
+		input       json.RawMessage
+		wantAction  Action
+		wantMatched bool
+	}{
+		{"no role leaves it to the rules", "", "Edit", file, ActionPass, false},
+		{"reviewer reads", RoleReviewer, "Read", file, ActionAllow, true},
+		{"reviewer greps", RoleReviewer, "Grep", json.RawMessage(`{"pattern":"TODO"}`), ActionAllow, true},
+		{"reviewer can't edit", RoleReviewer, "Edit", file, ActionDeny, true},
+		{"reviewer can't write", RoleReviewer, "Write", file, ActionDeny, true},
+		{"reviewer can't use unknown tools", RoleReviewer, "NotebookEdit", json.RawMessage(`{}`), ActionDeny, true},
+		{"reviewer runs git diff", RoleReviewer, "Bash", bash("git diff main...HEAD"), ActionAllow, true},
+		{"reviewer runs bare ls", RoleReviewer, "Bash", bash("ls"), ActionAllow, true},
+		{"reviewer comments via fab", RoleReviewer, "Bash", bash("fab issue comment 12 'looks good'"), ActionAllow, true},
+		{"reviewer lists agents via fab", RoleReviewer, "Bash", bash("fab agent list"), ActionAllow, true},
+		{"reviewer can't fab agent exec", RoleReviewer, "Bash", bash("fab agent exec a1 -- rm -rf ."), ActionDeny, true},
+		{"reviewer can't fab agent done", RoleReviewer, "Bash", bash("fab agent done"), ActionDeny, true},
+		{"reviewer can't close issues", RoleReviewer, "Bash", bash("fab issue close 12"), ActionDeny, true},
+		{"reviewer can't set project config", RoleReviewer, "Bash", bash("fab project config set app pre-merge-command 'rm -rf /'"), ActionDeny, true},
+		{"reviewer can't stop the server", RoleReviewer, "Bash", bash("fab server stop"), ActionDeny, true},
+		{"rg --pre is denied", RoleReviewer, "Bash", bash("rg --pre sh TODO"), ActionDeny, true},
+		{"git grep -O is denied", RoleReviewer, "Bash", bash("git grep -nOvim TODO"), ActionDeny, true},
+		{"git grep pager is denied", RoleReviewer, "Bash", bash("git grep --open-files-in-pager=vim TODO"), ActionDeny, true},
+		{"git diff --output is denied", RoleReviewer, "Bash", bash("git diff --output=main.go"), ActionDeny, true},
+		{"quoted flag is denied", RoleReviewer, "Bash", bash(`git log "--out"put=x`), ActionDeny, true},
+		{"abbreviated flag is denied", RoleReviewer, "Bash", bash("git diff --ext"), ActionDeny, true},
+		{"git log --oneline is allowed", RoleReviewer, "Bash", bash("git log --oneline -5"), ActionAllow, true},
+		{"reviewer can't commit", RoleReviewer, "Bash", bash("git commit -am wip"), ActionDeny, true},
+		{"prefix alone doesn't match", RoleReviewer, "Bash", bash("lsof -i"), ActionDeny, true},
+		{"chaining is denied", RoleReviewer, "Bash", bash("git status; rm -rf ."), ActionDeny, true},
+		{"redirection is denied", RoleReviewer, "Bash", bash("cat a > b"), ActionDeny, true},
+		{"substitution is denied", RoleReviewer, "Bash", bash("ls $(rm x)"), ActionDeny, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, matched, err := EvaluateRole(context.Background(), tt.role, tt.tool, tt.input, "/repo")
+			if err != nil {
+				t.Fatalf("EvaluateRole() error = %v", err)
+			}
+			if action != tt.wantAction || matched != tt.wantMatched {
+				t.Errorf("EvaluateRole() = %v, %v; want %v, %v", action, matched, tt.wantAction, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestValidateRole(t *testing.T) {
+	for _, role := range []string{"", RoleReviewer} {
+		if err := ValidateRole(role); err != nil {
+			t.Errorf("ValidateRole(%q) error = %v", role, err)
+		}
+	}
+	if err := ValidateRole("admin"); err == nil {
+		t.Error("ValidateRole(admin) should fail")
+	}
+}
@@ -0,0 +1,180 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tessro/fab/internal/paths"
+)
+
+// MergeIntent records an in-flight merge of an agent's branch into main.
+// It is written before git is mutated and removed once the merge has fully
+// completed or failed cleanly, so any entry left behind after a restart
+// marks a merge that was interrupted.
+type MergeIntent struct {
+	AgentID   string    `json:"agent_id"`
+	Project   string    `json:"project"`
+	TaskID    string    `json:"task_id,omitempty"`
+	Branch    string    `json:"branch"`
+	TargetSHA string    `json:"target_sha,omitempty"` // Rebased branch tip; set just before main is moved
+	StartedAt time.Time `json:"started_at"`
+}
+
+// ErrMergeIntentNotFound is returned when no journal entry exists for an agent.
+var ErrMergeIntentNotFound = errors.New("merge intent not found")
+
+// MergeJournal persists merge intents for crash recovery.
+// There is at most one intent per agent.
+type MergeJournal struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewMergeJournal creates a merge journal backed by the given file.
+func NewMergeJournal(path string) *MergeJournal {
+	return &MergeJournal{path: path}
+}
+
+// NewMergeJournalDefault creates a merge journal at the default path.
+func NewMergeJournalDefault() (*MergeJournal, error) {
+	path, err := MergeJournalPath()
+	if err != nil {
+		return nil, err
+	}
+	return NewMergeJournal(path), nil
+}
+
+// MergeJournalPath returns the default path for the merge journal.
+func MergeJournalPath() (string, error) {
+	dir, err := paths.RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "merges.json"), nil
+}
+
+// Begin records the intent to merge, replacing any existing entry for the agent.
+func (j *MergeJournal) Begin(intent MergeIntent) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	intents, err := j.readLocked()
+	if err != nil {
+		return err
+	}
+
+	filtered := make([]MergeIntent, 0, len(intents)+1)
+	for _, in := range intents {
+		if in.AgentID != intent.AgentID {
+			filtered = append(filtered, in)
+		}
+	}
+	filtered = append(filtered, intent)
+
+	return j.writeLocked(filtered)
+}
+
+// SetTarget records the SHA that main is about to be moved to.
+// Returns ErrMergeIntentNotFound if Begin was not called for the agent.
+func (j *MergeJournal) SetTarget(agentID, sha string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	intents, err := j.readLocked()
+	if err != nil {
+		return err
+	}
+
+	for i, in := range intents {
+		if in.AgentID == agentID {
+			intents[i].TargetSHA = sha
+			return j.writeLocked(intents)
+		}
+	}
+
+	return ErrMergeIntentNotFound
+}
+
+// Complete removes the agent's entry from the journal.
+// Returns nil if there is no entry (idempotent).
+func (j *MergeJournal) Complete(agentID string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	intents, err := j.readLocked()
+	if err != nil {
+		return err
+	}
+
+	filtered := make([]MergeIntent, 0, len(intents))
+	for _, in := range intents {
+		if in.AgentID != agentID {
+			filtered = append(filtered, in)
+		}
+	}
+	if len(filtered) == len(intents) {
+		return nil
+	}
+
+	return j.writeLocked(filtered)
+}
+
+// List returns all recorded merge intents.
+func (j *MergeJournal) List() ([]MergeIntent, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.readLocked()
+}
+
+// readLocked reads the journal file. Must be called with mu held.
+func (j *MergeJournal) readLocked() ([]MergeIntent, error) {
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []MergeIntent{}, nil
+		}
+		return nil, fmt.Errorf("read merge journal: %w", err)
+	}
+
+	if len(data) == 0 {
+		return []MergeIntent{}, nil
+	}
+
+	var intents []MergeIntent
+	if err := json.Unmarshal(data, &intents); err != nil {
+		return nil, fmt.Errorf("parse merge journal: %w", err)
+	}
+
+	return intents, nil
+}
+
+// writeLocked writes the journal file atomically. Must be called with mu held.
+func (j *MergeJournal) writeLocked(intents []MergeIntent) error {
+	dir := filepath.Dir(j.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create runtime dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(intents, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal merge journal: %w", err)
+	}
+
+	tmpFile := j.path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpFile, j.path); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package runtime
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMergeJournal_Lifecycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	journal := NewMergeJournal(filepath.Join(tmpDir, "merges.json"))
+
+	intent := MergeIntent{
+		AgentID:   "abc123",
+		Project:   "test-project",
+		TaskID:    "42",
+		Branch:    "fab/abc123",
+		StartedAt: time.Now(),
+	}
+	if err := journal.Begin(intent); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	if err := journal.SetTarget("abc123", "deadbeef"); err != nil {
+		t.Fatalf("SetTarget failed: %v", err)
+	}
+
+	// A fresh journal on the same file sees the entry (survives restart)
+	reopened := NewMergeJournal(journal.path)
+	intents, err := reopened.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(intents) != 1 {
+		t.Fatalf("got %d intents, want 1", len(intents))
+	}
+	if intents[0].TargetSHA != "deadbeef" || intents[0].TaskID != "42" {
+		t.Errorf("got intent %+v", intents[0])
+	}
+
+	if err := reopened.Complete("abc123"); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	intents, err = journal.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(intents) != 0 {
+		t.Errorf("got %d intents after Complete, want 0", len(intents))
+	}
+
+	// Complete is idempotent
+	if err := journal.Complete("abc123"); err != nil {
+		t.Errorf("second Complete failed: %v", err)
+	}
+}
+
+func TestMergeJournal_BeginReplacesExisting(t *testing.T) {
+	journal := NewMergeJournal(filepath.Join(t.TempDir(), "merges.json"))
+
+	if err := journal.Begin(MergeIntent{AgentID: "a", TargetSHA: "old"}); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	if err := journal.Begin(MergeIntent{AgentID: "a"}); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+
+	intents, err := journal.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(intents) != 1 || intents[0].TargetSHA != "" {
+		t.Errorf("got %+v, want single entry with no target", intents)
+	}
+}
+
+func TestMergeJournal_SetTargetNotFound(t *testing.T) {
+	journal := NewMergeJournal(filepath.Join(t.TempDir(), "merges.json"))
+
+	err := journal.SetTarget("missing", "sha")
+	if !errors.Is(err, ErrMergeIntentNotFound) {
+		t.Errorf("SetTarget error = %v, want ErrMergeIntentNotFound", err)
+	}
+}
@@ -0,0 +1,190 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tessro/fab/internal/paths"
+)
+
+// DefaultMaxTranscripts is the default number of transcripts kept on disk.
+// The oldest transcripts are pruned when a new one is saved past this limit.
+const DefaultMaxTranscripts = 100
+
+// ErrTranscriptNotFound is returned when no transcript exists for an agent.
+var ErrTranscriptNotFound = errors.New("transcript not found")
+
+// TranscriptEntry is a single persisted chat entry.
+type TranscriptEntry struct {
+	Role       string    `json:"role"`
+	Content    string    `json:"content,omitempty"`
+	ToolName   string    `json:"tool_name,omitempty"`
+	ToolInput  string    `json:"tool_input,omitempty"`
+	ToolResult string    `json:"tool_result,omitempty"`
+	IsError    bool      `json:"is_error,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Transcript is the chat history of an agent, saved when the agent is deleted
+// so it can still be inspected or replayed afterwards.
+type Transcript struct {
+	AgentID string            `json:"agent_id"`
+	Project string            `json:"project"`
+	Backend string            `json:"backend,omitempty"`
+	SavedAt time.Time         `json:"saved_at"`
+	Entries []TranscriptEntry `json:"entries"`
+}
+
+// TranscriptStore persists agent transcripts as one JSON file per agent.
+type TranscriptStore struct {
+	mu  sync.Mutex
+	dir string
+
+	// maxTranscripts bounds how many transcripts are kept (0 = unlimited)
+	maxTranscripts int
+}
+
+// NewTranscriptStore creates a transcript store backed by the given directory.
+func NewTranscriptStore(dir string) *TranscriptStore {
+	return &TranscriptStore{
+		dir:            dir,
+		maxTranscripts: DefaultMaxTranscripts,
+	}
+}
+
+// NewTranscriptStoreDefault creates a transcript store at the default path.
+func NewTranscriptStoreDefault() (*TranscriptStore, error) {
+	dir, err := TranscriptDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewTranscriptStore(dir), nil
+}
+
+// TranscriptDir returns the default directory for agent transcripts.
+func TranscriptDir() (string, error) {
+	dir, err := paths.RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "transcripts"), nil
+}
+
+// Save writes the transcript, replacing any existing one for the agent,
+// and prunes the oldest transcripts beyond the store's limit.
+func (s *TranscriptStore) Save(t Transcript) error {
+	path, ok := s.pathFor(t.AgentID)
+	if !ok {
+		return fmt.Errorf("invalid agent ID: %q", t.AgentID)
+	}
+	if t.SavedAt.IsZero() {
+		t.SavedAt = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("create transcript dir: %w", err)
+	}
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("marshal transcript: %w", err)
+	}
+
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpFile, path); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	s.pruneLocked()
+	return nil
+}
+
+// Load returns the saved transcript for an agent.
+// Returns ErrTranscriptNotFound if none exists.
+func (s *TranscriptStore) Load(agentID string) (*Transcript, error) {
+	path, ok := s.pathFor(agentID)
+	if !ok {
+		return nil, ErrTranscriptNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrTranscriptNotFound
+		}
+		return nil, fmt.Errorf("read transcript: %w", err)
+	}
+
+	var t Transcript
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parse transcript: %w", err)
+	}
+	return &t, nil
+}
+
+// pathFor returns the file path for an agent's transcript.
+// Returns false for IDs that would escape the transcript directory.
+func (s *TranscriptStore) pathFor(agentID string) (string, bool) {
+	if agentID == "" || agentID != filepath.Base(agentID) || strings.HasPrefix(agentID, ".") {
+		return "", false
+	}
+	return filepath.Join(s.dir, agentID+".json"), true
+}
+
+// pruneLocked removes the oldest transcripts beyond maxTranscripts.
+// Must be called with mu held. Errors are ignored; pruning is best-effort.
+func (s *TranscriptStore) pruneLocked() {
+	if s.maxTranscripts <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+
+	type file struct {
+		path    string
+		modTime time.Time
+	}
+	var files []file
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{filepath.Join(s.dir, e.Name()), info.ModTime()})
+	}
+
+	if len(files) <= s.maxTranscripts {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	for _, f := range files[:len(files)-s.maxTranscripts] {
+		os.Remove(f.path)
+	}
+}
@@ -0,0 +1,89 @@
+package runtime
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTranscriptStore_SaveLoad(t *testing.T) {
+	store := NewTranscriptStore(t.TempDir())
+
+	tr := Transcript{
+		AgentID: "abc123",
+		Project: "test-project",
+		Backend: "claude",
+		Entries: []TranscriptEntry{
+			{Role: "user", Content: "fix the bug", Timestamp: time.Now()},
+			{Role: "tool", ToolName: "Bash", ToolInput: "go test ./..."},
+			{Role: "assistant", Content: "done"},
+		},
+	}
+	if err := store.Save(tr); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := store.Load("abc123")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got.Project != "test-project" || got.Backend != "claude" {
+		t.Errorf("got transcript %+v", got)
+	}
+	if len(got.Entries) != 3 || got.Entries[0].Content != "fix the bug" || got.Entries[1].ToolName != "Bash" {
+		t.Errorf("got entries %+v", got.Entries)
+	}
+	if got.SavedAt.IsZero() {
+		t.Error("SavedAt not set")
+	}
+}
+
+func TestTranscriptStore_LoadMissing(t *testing.T) {
+	store := NewTranscriptStore(t.TempDir())
+
+	for _, id := range []string{"missing", "", "../etc", "."} {
+		if _, err := store.Load(id); !errors.Is(err, ErrTranscriptNotFound) {
+			t.Errorf("Load(%q) error = %v, want ErrTranscriptNotFound", id, err)
+		}
+	}
+}
+
+func TestTranscriptStore_RejectsInvalidID(t *testing.T) {
+	store := NewTranscriptStore(t.TempDir())
+
+	if err := store.Save(Transcript{AgentID: "../escape"}); err == nil {
+		t.Error("expected error for path-like agent ID")
+	}
+}
+
+func TestTranscriptStore_PrunesOldest(t *testing.T) {
+	dir := t.TempDir()
+	store := NewTranscriptStore(dir)
+	store.maxTranscripts = 2
+
+	for i, id := range []string{"a1", "a2", "a3"} {
+		if err := store.Save(Transcript{AgentID: id}); err != nil {
+			t.Fatalf("Save(%s) failed: %v", id, err)
+		}
+		// Spread mtimes so ordering doesn't depend on filesystem resolution
+		mtime := time.Now().Add(time.Duration(i-10) * time.Minute)
+		if err := os.Chtimes(filepath.Join(dir, id+".json"), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Trigger another prune now that mtimes are ordered
+	if err := store.Save(Transcript{AgentID: "a3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Load("a1"); !errors.Is(err, ErrTranscriptNotFound) {
+		t.Errorf("oldest transcript not pruned, err = %v", err)
+	}
+	for _, id := range []string{"a2", "a3"} {
+		if _, err := store.Load(id); err != nil {
+			t.Errorf("Load(%s) failed: %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/logging"
+)
+
+// compactPrompt asks an agent to summarize its conversation so the summary
+// can stand in for its chat history.
+const compactPrompt = `Summarize our conversation so far for your own future reference. Your chat history is about to be replaced by this summary.
+
+Include the task, decisions made and why, the current state of the work (files changed, what is done and what remains), and any open questions. Be concise. Reply with the summary only and don't make any changes.`
+
+// handleAgentCompact asks an idle agent to summarize its conversation and,
+// once it has, replaces the agent's chat history with the summary. The full
+// history is saved to the transcript store first, so nothing is lost.
+func (s *Supervisor) handleAgentCompact(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var compactReq daemon.AgentCompactRequest
+	if err := unmarshalPayload(req.Payload, &compactReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	if compactReq.ID == "" {
+		return errorResponse(req, "agent ID required")
+	}
+
+	a, err := s.agents.Get(compactReq.ID)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("agent not found: %s", compactReq.ID))
+	}
+	if state := a.GetState(); state != agent.StateIdle {
+		return errorResponse(req, fmt.Sprintf("agent %s is %s; compact it while it is idle", a.ID, state))
+	}
+	entries, _, _ := a.History().Page(0, 0)
+	if len(entries) == 0 {
+		return errorResponse(req, fmt.Sprintf("agent %s has no chat history to compact", a.ID))
+	}
+
+	idle, ok := s.registerTurns(a.ID)
+	if !ok {
+		return errorResponse(req, fmt.Sprintf("agent %s is already being replayed or compacted", a.ID))
+	}
+	last := entries[len(entries)-1].Seq
+	if err := a.SendMessage(compactPrompt); err != nil {
+		s.unregisterReplay(a.ID)
+		return errorResponse(req, fmt.Sprintf("failed to ask for a summary: %v", err))
+	}
+	go s.runCompaction(a, last, idle)
+
+	slog.Info("agent compaction started", "agent", a.ID, "entries", len(entries))
+	return successResponse(req, daemon.AgentCompactResponse{
+		ID:      a.ID,
+		Entries: len(entries),
+	})
+}
+
+// runCompaction waits for the agent to answer the summary prompt, saves its
+// full transcript, and replaces its history with the summary. after is the
+// sequence number of the last entry before the prompt.
+func (s *Supervisor) runCompaction(a *agent.Agent, after int64, idle <-chan struct{}) {
+	defer logging.LogPanic("agent-compact", nil)
+	defer s.unregisterReplay(a.ID)
+
+	log := slog.With("agent", a.ID)
+	if !s.waitForReplayTurn(a, idle) {
+		log.Warn("compaction stopped: agent did not finish the summary")
+		return
+	}
+
+	summary := compactionSummary(a.History().Entries(0), after)
+	if summary == "" {
+		log.Warn("compaction stopped: agent gave no summary")
+		return
+	}
+	if err := s.agents.SaveTranscript(a); err != nil {
+		log.Warn("compaction stopped: failed to save transcript", "error", err)
+		return
+	}
+	a.History().Compact(agent.ChatEntry{
+		Content:   "Summary of earlier conversation:\n\n" + summary,
+		Timestamp: time.Now(),
+	})
+	log.Info("agent history compacted")
+}
+
+// compactionSummary returns the assistant's reply to the summary prompt: the
+// text of the assistant entries after the entry with sequence number after.
+func compactionSummary(entries []agent.ChatEntry, after int64) string {
+	var parts []string
+	for _, e := range entries {
+		if e.Seq > after && e.Role == "assistant" && strings.TrimSpace(e.Content) != "" {
+			parts = append(parts, strings.TrimSpace(e.Content))
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,62 @@
+package supervisor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/project"
+)
+
+func TestCompactionSummary(t *testing.T) {
+	entries := []agent.ChatEntry{
+		{Role: "assistant", Content: "earlier work", Seq: 3},
+		{Role: "user", Content: compactPrompt, Seq: 4},
+		{Role: "assistant", Content: "  Task: fix the flaky test. ", Seq: 5},
+		{Role: "tool", ToolName: "Read", Seq: 6},
+		{Role: "assistant", Content: "Remaining: add a regression test.", Seq: 7},
+	}
+	want := "Task: fix the flaky test.\n\nRemaining: add a regression test."
+	if got := compactionSummary(entries, 3); got != want {
+		t.Errorf("compactionSummary() = %q, want %q", got, want)
+	}
+	if got := compactionSummary(entries, 7); got != "" {
+		t.Errorf("compactionSummary() = %q, want no summary", got)
+	}
+}
+
+func TestSupervisor_HandleAgentCompact_Errors(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+	sup.agents.RegisterProject(&project.Project{Name: "proj"})
+
+	running, err := sup.agents.Hydrate(agent.HydrateInfo{ID: "run001", Project: "proj", State: agent.StateRunning, StartedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("Hydrate() error = %v", err)
+	}
+	running.History().Add(agent.ChatEntry{Role: "user", Content: "hi"})
+	if _, err := sup.agents.Hydrate(agent.HydrateInfo{ID: "idle01", Project: "proj", State: agent.StateIdle, StartedAt: time.Now()}); err != nil {
+		t.Fatalf("Hydrate() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		id      string
+		wantErr string
+	}{
+		{"", "agent ID required"},
+		{"missing", "agent not found"},
+		{"run001", "compact it while it is idle"},
+		{"idle01", "no chat history"},
+	} {
+		resp := sup.Handle(context.Background(), &daemon.Request{
+			Type:    daemon.MsgAgentCompact,
+			Payload: daemon.AgentCompactRequest{ID: tt.id},
+		})
+		if resp.Success || !strings.Contains(resp.Error, tt.wantErr) {
+			t.Errorf("compact(%q) = %+v, want error containing %q", tt.id, resp, tt.wantErr)
+		}
+	}
+}
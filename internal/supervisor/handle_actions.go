@@ -4,9 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/tessro/fab/internal/agent"
 	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/notify"
+	"github.com/tessro/fab/internal/orchestrator"
+	"github.com/tessro/fab/internal/project"
 )
 
 // handleAgentDone handles agent completion signals.
@@ -29,8 +36,25 @@ func (s *Supervisor) handleAgentDone(ctx context.Context, req *daemon.Request) *
 	// Find the agent and its orchestrator
 	orch := s.getOrchestratorForAgent(doneReq.AgentID)
 	if orch == nil {
+		// A retried done for an agent that already merged finds it deleted;
+		// answer with the recorded outcome instead of failing
+		if prior := s.priorDoneResult(doneReq.AgentID, doneReq.TaskID); prior != nil {
+			slog.Info("agent done already handled, returning prior result", "agent", doneReq.AgentID, "task", doneReq.TaskID)
+			return successResponse(req, agentDoneResponse(prior))
+		}
 		return errorResponse(req, "agent not found or no orchestrator")
 	}
+	projectName := orch.Project().Name
+
+	// A merge cleans up the agent, so note its task and summary first
+	issueID := doneReq.TaskID
+	var summary string
+	if a, err := s.agents.Get(doneReq.AgentID); err == nil {
+		if issueID == "" {
+			issueID = a.Info().Task
+		}
+		summary = doneSummary(a)
+	}
 
 	// Notify the orchestrator
 	result, err := orch.HandleAgentDone(doneReq.AgentID, doneReq.TaskID, doneReq.Error)
@@ -38,23 +62,38 @@ func (s *Supervisor) handleAgentDone(ctx context.Context, req *daemon.Request) *
 		return errorResponse(req, fmt.Sprintf("handle agent done: %v", err))
 	}
 
-	resp := daemon.AgentDoneResponse{
-		Merged:     result.Merged,
-		BranchName: result.BranchName,
-		SHA:        result.SHA,
-		MergeError: result.MergeError,
-		PRCreated:  result.PRCreated,
-		PRURL:      result.PRURL,
+	resp := agentDoneResponse(result)
+	if result.Duplicate {
+		return successResponse(req, resp)
+	}
+	s.recordDone(projectName, doneReq.AgentID, issueID, summary, result)
+
+	// Describe what was accomplished rather than the last step in progress.
+	// A merged agent is already gone; its summary lives on in the timeline.
+	if doneReq.Error == "" && result.MergeError == "" && summary != "" {
+		if a, err := s.agents.Get(doneReq.AgentID); err == nil {
+			a.SetDescription(summary)
+		}
 	}
 
+	s.notifyAgentDone(projectName, doneReq.AgentID, doneReq.Error, result)
+
 	// Check for conflicts (both merge and PR strategies can have rebase conflicts)
 	if !result.Merged && !result.PRCreated && result.MergeError != "" {
+		errMsg := fmt.Sprintf("conflict on %s: %s", result.BranchName, result.MergeError)
+		if result.CheckFailed {
+			errMsg = fmt.Sprintf("merge blocked on %s: %s", result.BranchName, result.MergeError)
+		}
+		if len(result.ConflictFiles) > 0 {
+			errMsg += fmt.Sprintf("\nconflicting files: %s", strings.Join(result.ConflictFiles, ", "))
+		}
+
 		// Return success: false to signal agent should resolve conflicts
 		return &daemon.Response{
 			Type:    req.Type,
 			ID:      req.ID,
 			Success: false,
-			Error:   fmt.Sprintf("conflict on %s: %s", result.BranchName, result.MergeError),
+			Error:   errMsg,
 			Payload: resp,
 		}
 	}
@@ -62,6 +101,141 @@ func (s *Supervisor) handleAgentDone(ctx context.Context, req *daemon.Request) *
 	return successResponse(req, resp)
 }
 
+// recordDone records the outcome of an agent's done in the project's
+// activity timeline, with the agent's summary as the detail of a merge.
+// Dry runs change nothing, so they aren't recorded.
+func (s *Supervisor) recordDone(projectName, agentID, issueID, summary string, result *orchestrator.AgentDoneResult) {
+	ev := project.TimelineEvent{AgentID: agentID, IssueID: issueID}
+	switch {
+	case result.DryRun:
+		return
+	case result.Merged:
+		ev.Type = project.TimelineMerge
+		ev.SHA = result.SHA
+		ev.Detail = summary
+	case result.PRCreated:
+		ev.Type = project.TimelinePullRequest
+		ev.Detail = result.PRURL
+	case result.MergeError != "":
+		ev.Type = project.TimelineMergeFailed
+		ev.Detail, _, _ = strings.Cut(result.MergeError, "\n")
+	default:
+		return
+	}
+	s.recordTimeline(projectName, ev)
+}
+
+// Bounds for descriptions taken from an agent's final summary.
+const (
+	doneDescriptionGrace = 5 * time.Minute // Descriptions set this recently are kept
+	maxDoneDescription   = 100
+)
+
+// doneSummary returns a short description of what an agent accomplished,
+// taken from the first line of text in its last assistant message, which is
+// usually the summary written just before running done. It is empty if the
+// agent set its own description within doneDescriptionGrace, since that is
+// likely more specific, or if there is no assistant message to use.
+func doneSummary(a *agent.Agent) string {
+	if time.Since(a.DescriptionSetAt()) < doneDescriptionGrace {
+		return ""
+	}
+	entries := a.History().Entries(0)
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Role != "assistant" || strings.TrimSpace(e.Content) == "" {
+			continue
+		}
+		for line := range strings.Lines(e.Content) {
+			// Skip headings and lead-ins like "Summary:" for the first real line
+			line = strings.TrimSpace(strings.ReplaceAll(line, "**", ""))
+			line = strings.TrimSpace(strings.TrimLeft(line, "-*> "))
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasSuffix(line, ":") {
+				continue
+			}
+			return truncate(line, maxDoneDescription)
+		}
+		return ""
+	}
+	return ""
+}
+
+// agentDoneResponse converts an orchestrator result to its wire format.
+func agentDoneResponse(result *orchestrator.AgentDoneResult) daemon.AgentDoneResponse {
+	return daemon.AgentDoneResponse{
+		Merged:      result.Merged,
+		BranchName:  result.BranchName,
+		SHA:         result.SHA,
+		MergeError:  result.MergeError,
+		CheckFailed: result.CheckFailed,
+		PRCreated:   result.PRCreated,
+		PRURL:       result.PRURL,
+		DryRun:      result.DryRun,
+		Duplicate:   result.Duplicate,
+		Conflicts:   conflictFiles(result.Conflicts),
+	}
+}
+
+// conflictFiles converts rebase conflict details to their wire format.
+func conflictFiles(conflicts []project.ConflictFile) []daemon.ConflictFile {
+	if len(conflicts) == 0 {
+		return nil
+	}
+	files := make([]daemon.ConflictFile, 0, len(conflicts))
+	for _, c := range conflicts {
+		f := daemon.ConflictFile{Path: c.Path, Truncated: c.Truncated}
+		for _, h := range c.Hunks {
+			f.Hunks = append(f.Hunks, daemon.ConflictHunk{StartLine: h.StartLine, EndLine: h.EndLine, Text: h.Text})
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// priorDoneResult looks up a recorded successful done for an agent that may
+// no longer exist, across all running orchestrators.
+func (s *Supervisor) priorDoneResult(agentID, taskID string) *orchestrator.AgentDoneResult {
+	s.mu.RLock()
+	orchs := slices.Collect(maps.Values(s.orchestrators))
+	s.mu.RUnlock()
+
+	for _, orch := range orchs {
+		if prior, ok := orch.PriorDoneResult(agentID, taskID); ok {
+			return prior
+		}
+	}
+	return nil
+}
+
+// notifyAgentDone sends an agent_done notification summarizing the outcome.
+func (s *Supervisor) notifyAgentDone(projectName, agentID, errMsg string, result *orchestrator.AgentDoneResult) {
+	var message string
+	switch {
+	case errMsg != "":
+		message = fmt.Sprintf("Agent %s finished with error: %s", agentID, errMsg)
+	case result.DryRun:
+		message = fmt.Sprintf("Agent %s finished (dry run, nothing merged)", agentID)
+	case result.Merged:
+		message = fmt.Sprintf("Agent %s merged its work", agentID)
+	case result.PRCreated:
+		message = fmt.Sprintf("Agent %s opened %s", agentID, result.PRURL)
+	case result.CheckFailed:
+		message = fmt.Sprintf("Agent %s failed the pre-merge check", agentID)
+	case result.MergeError != "":
+		message = fmt.Sprintf("Agent %s hit a merge conflict", agentID)
+	default:
+		message = fmt.Sprintf("Agent %s finished", agentID)
+	}
+
+	s.notifier.Send(notify.Event{
+		Type:    notify.EventAgentDone,
+		Project: projectName,
+		AgentID: agentID,
+		Title:   "Agent done",
+		Message: message,
+	})
+}
+
 // handlePlannerDone handles completion signals from planner agents.
 // It stops the planner and deletes it from the manager, triggering
 // the appropriate cleanup and TUI events.
@@ -85,6 +259,10 @@ func (s *Supervisor) handlePlannerDone(_ context.Context, req *daemon.Request, p
 		)
 	}
 
+	// Announce completion before the planner is deleted so waiters
+	// (e.g. 'fab prompt') see it ahead of the planner_deleted event
+	s.broadcastPlanComplete(plannerID, p.Project(), errMsg)
+
 	// Stop the planner gracefully
 	if err := s.planners.Stop(plannerID); err != nil {
 		slog.Warn("error stopping planner", "planner", plannerID, "error", err)
@@ -0,0 +1,58 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/project"
+)
+
+func TestDoneSummary(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+	sup.agents.RegisterProject(&project.Project{Name: "proj"})
+
+	hydrate := func(id string, entries ...agent.ChatEntry) *agent.Agent {
+		t.Helper()
+		a, err := sup.agents.Hydrate(agent.HydrateInfo{
+			ID:          id,
+			Project:     "proj",
+			State:       agent.StateRunning,
+			Description: "Reading the test suite",
+			StartedAt:   time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Hydrate() error = %v", err)
+		}
+		for _, e := range entries {
+			a.History().Add(e)
+		}
+		return a
+	}
+
+	a := hydrate("a1",
+		agent.ChatEntry{Role: "assistant", Content: "Looking at the flaky test"},
+		agent.ChatEntry{Role: "assistant", Content: "## Summary\n\n**Fixed the timer race** in TestFlaky\n- added a fake clock"},
+		agent.ChatEntry{Role: "tool", ToolName: "Bash", ToolInput: "fab agent done"},
+	)
+	if got, want := doneSummary(a), "Fixed the timer race in TestFlaky"; got != want {
+		t.Errorf("doneSummary() = %q, want %q", got, want)
+	}
+
+	long := hydrate("a2", agent.ChatEntry{Role: "assistant", Content: strings.Repeat("word ", 50)})
+	if got := doneSummary(long); len(got) != maxDoneDescription || !strings.HasSuffix(got, "...") {
+		t.Errorf("doneSummary() = %q, want truncated to %d", got, maxDoneDescription)
+	}
+
+	// A description the agent set just now is more specific than the summary
+	a.SetDescription("Fix TestFlaky timer race")
+	if got := doneSummary(a); got != "" {
+		t.Errorf("doneSummary() after SetDescription = %q, want empty", got)
+	}
+
+	if got := doneSummary(hydrate("a3", agent.ChatEntry{Role: "user", Content: "go"})); got != "" {
+		t.Errorf("doneSummary() without assistant messages = %q, want empty", got)
+	}
+}
@@ -4,11 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/tessro/fab/internal/agent"
 	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/project"
+	"github.com/tessro/fab/internal/rules"
+	"github.com/tessro/fab/internal/transcript"
 )
 
 // ManagerAgentID is the special agent ID for the manager in the agent list.
@@ -23,9 +30,14 @@ func (s *Supervisor) handleAgentList(ctx context.Context, req *daemon.Request) *
 		}
 	}
 
+	if err := daemon.ValidateAgentSort(listReq.SortBy, listReq.Order); err != nil {
+		return errorResponse(req, err.Error())
+	}
+
 	agents := s.agents.List(listReq.Project)
-	slog.Debug("agent list requested", "filter", listReq.Project, "count", len(agents))
+	slog.Debug("agent list requested", "filter", listReq.Project, "sort", listReq.SortBy, "count", len(agents))
 	statuses := make([]daemon.AgentStatus, 0, len(agents)+1)
+	attention := s.agentsNeedingAttention()
 
 	// Add running project managers to the list
 	s.mu.RLock()
@@ -38,38 +50,69 @@ func (s *Supervisor) handleAgentList(ctx context.Context, req *daemon.Request) *
 		// Check if project has a running manager
 		if mgr.IsRunning() {
 			statuses = append(statuses, daemon.AgentStatus{
-				ID:          ManagerAgentID,
-				Project:     projectName,
-				State:       string(mgr.State()),
-				Worktree:    mgr.WorkDir(),
-				StartedAt:   mgr.StartedAt(),
-				Task:        "",
-				Description: "Manager",
-				Backend:     "claude", // Manager always uses Claude Code
+				ID:             ManagerAgentID,
+				Project:        projectName,
+				State:          string(mgr.State()),
+				Worktree:       mgr.WorkDir(),
+				StartedAt:      mgr.StartedAt(),
+				Task:           "",
+				Description:    "Manager",
+				Backend:        "claude", // Manager always uses Claude Code
+				NeedsAttention: attention[ManagerAgentID+":"+projectName],
 			})
 		}
 	}
 	s.mu.RUnlock()
 
 	for _, a := range agents {
-		info := a.Info()
-		statuses = append(statuses, daemon.AgentStatus{
-			ID:          info.ID,
-			Project:     info.Project,
-			State:       string(info.State),
-			Worktree:    info.Worktree,
-			StartedAt:   info.StartedAt,
-			Task:        info.Task,
-			Description: info.Description,
-			Backend:     info.Backend,
-		})
+		status := agentStatus(a.Info())
+		status.NeedsAttention = attention[status.ID]
+		statuses = append(statuses, status)
 	}
 
+	daemon.SortAgents(statuses, listReq.SortBy, listReq.Order)
+
 	return successResponse(req, daemon.AgentListResponse{
 		Agents: statuses,
 	})
 }
 
+// agentsNeedingAttention returns the IDs of agents with a pending permission
+// request or user question. Managers are keyed by "manager:<project>".
+func (s *Supervisor) agentsNeedingAttention() map[string]bool {
+	attention := make(map[string]bool)
+	for _, p := range s.permissions.List() {
+		attention[p.AgentID] = true
+	}
+	for _, q := range s.questions.List() {
+		attention[q.AgentID] = true
+	}
+	return attention
+}
+
+// agentStatus converts an agent snapshot to its protocol form.
+// NeedsAttention is left for the caller.
+func agentStatus(info agent.AgentInfo) daemon.AgentStatus {
+	return daemon.AgentStatus{
+		ID:          info.ID,
+		Name:        info.Name,
+		Project:     info.Project,
+		State:       string(info.State),
+		Worktree:    info.Worktree,
+		StartedAt:   info.StartedAt,
+		Task:        info.Task,
+		Description: info.Description,
+		Backend:     info.Backend,
+		StashRef:    info.StashRef,
+		Epic:        info.Epic,
+		CurrentFile: info.CurrentFile,
+		Model:       info.Model,
+		SpawnedBy:   info.SpawnedBy,
+		Role:        info.Role,
+		Subdir:      info.Subdir,
+	}
+}
+
 // handleAgentCreate creates a new agent.
 func (s *Supervisor) handleAgentCreate(ctx context.Context, req *daemon.Request) *daemon.Response {
 	var createReq daemon.AgentCreateRequest
@@ -80,16 +123,32 @@ func (s *Supervisor) handleAgentCreate(ctx context.Context, req *daemon.Request)
 	if createReq.Project == "" {
 		return errorResponse(req, "project name required")
 	}
+	if err := rules.ValidateRole(createReq.Role); err != nil {
+		return errorResponse(req, err.Error())
+	}
 
 	proj, err := s.registry.Get(createReq.Project)
 	if err != nil {
 		return errorResponse(req, fmt.Sprintf("project not found: %s", createReq.Project))
 	}
 
-	a, err := s.agents.Create(proj)
+	a, err := s.agents.Create(proj, agent.SpawnedByUser, false)
 	if err != nil {
 		return errorResponse(req, fmt.Sprintf("failed to create agent: %v", err))
 	}
+	if createReq.Model != "" {
+		a.SetModel(createReq.Model)
+	}
+	a.SetRole(createReq.Role)
+	if err := a.SetSubdir(createReq.Subdir); err != nil {
+		_ = s.agents.Delete(a.ID)
+		return errorResponse(req, fmt.Sprintf("invalid subdir: %v", err))
+	}
+	if createReq.DebugCapture {
+		if err := a.SetDebugCapture(true); err != nil {
+			slog.Warn("failed to enable debug capture", "agent", a.ID, "error", err)
+		}
+	}
 
 	return successResponse(req, daemon.AgentCreateResponse{
 		ID:       a.ID,
@@ -98,6 +157,101 @@ func (s *Supervisor) handleAgentCreate(ctx context.Context, req *daemon.Request)
 	})
 }
 
+// handleAgentDebugCapture turns raw CLI output capture on or off for an agent.
+func (s *Supervisor) handleAgentDebugCapture(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var captureReq daemon.AgentDebugCaptureRequest
+	if err := unmarshalPayload(req.Payload, &captureReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	if captureReq.ID == "" {
+		return errorResponse(req, "agent ID required")
+	}
+
+	a, err := s.agents.Get(captureReq.ID)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("agent not found: %s", captureReq.ID))
+	}
+
+	if err := a.SetDebugCapture(captureReq.Enabled); err != nil {
+		return errorResponse(req, fmt.Sprintf("failed to set debug capture: %v", err))
+	}
+
+	return successResponse(req, daemon.AgentDebugCaptureResponse{Path: a.DebugCapturePath()})
+}
+
+// handleAgentTranscriptExport renders an agent's chat history as markdown,
+// optionally writing it into the agent's worktree.
+func (s *Supervisor) handleAgentTranscriptExport(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var exportReq daemon.AgentTranscriptExportRequest
+	if err := unmarshalPayload(req.Payload, &exportReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	if exportReq.ID == "" {
+		return errorResponse(req, "agent ID required")
+	}
+
+	a, err := s.agents.Get(exportReq.ID)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("agent not found: %s", exportReq.ID))
+	}
+	info := a.Info()
+
+	history := a.History().Entries(0)
+	entries := make([]transcript.Entry, len(history))
+	for i, e := range history {
+		entries[i] = transcript.Entry{
+			Role:       e.Role,
+			Content:    e.Content,
+			ToolName:   e.ToolName,
+			ToolInput:  e.ToolInput,
+			ToolResult: e.ToolResult,
+			IsError:    e.IsError,
+			Timestamp:  e.Timestamp,
+		}
+	}
+
+	assistant := "Claude"
+	if info.Backend != "" {
+		assistant = strings.ToUpper(info.Backend[:1]) + info.Backend[1:]
+	}
+	title := "Transcript: " + info.ID
+	if info.Task != "" {
+		title += " (" + info.Task + ")"
+	}
+	md := transcript.Markdown(entries, transcript.Options{
+		Title:          title,
+		AssistantLabel: assistant,
+		Worktree:       info.Worktree,
+	})
+
+	resp := daemon.AgentTranscriptExportResponse{AgentID: info.ID, Markdown: md}
+	if exportReq.Write {
+		if info.Worktree == "" {
+			return errorResponse(req, "agent has no worktree")
+		}
+		name := exportReq.Path
+		if name == "" {
+			name = transcript.Filename(info.ID)
+		}
+		// Keep the file inside the worktree
+		if !filepath.IsLocal(name) {
+			return errorResponse(req, fmt.Sprintf("path must be relative to the worktree: %s", name))
+		}
+		path := filepath.Join(info.Worktree, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return errorResponse(req, fmt.Sprintf("failed to create directory: %v", err))
+		}
+		if err := os.WriteFile(path, []byte(md), 0644); err != nil {
+			return errorResponse(req, fmt.Sprintf("failed to write transcript: %v", err))
+		}
+		resp.Path = path
+	}
+
+	return successResponse(req, resp)
+}
+
 // handleAgentDelete deletes an agent.
 func (s *Supervisor) handleAgentDelete(ctx context.Context, req *daemon.Request) *daemon.Response {
 	var deleteReq daemon.AgentDeleteRequest
@@ -147,6 +301,9 @@ func (s *Supervisor) handleAgentAbort(ctx context.Context, req *daemon.Request)
 	}
 
 	if abortReq.Force {
+		// Save uncommitted work first; the agent gets no chance to commit it
+		s.stashAgentWork(a)
+
 		// Force stop: sends SIGTERM then SIGKILL after timeout
 		if err := a.Stop(); err != nil {
 			return errorResponse(req, fmt.Sprintf("failed to stop agent: %v", err))
@@ -156,8 +313,25 @@ func (s *Supervisor) handleAgentAbort(ctx context.Context, req *daemon.Request)
 		if err := a.SendMessage("/quit"); err != nil {
 			return errorResponse(req, fmt.Sprintf("failed to send quit command: %v", err))
 		}
+
+		// A clean exit doesn't release claims the way a crash does, and an
+		// aborted agent won't finish its issues
+		if orch := s.getOrchestrator(a.Info().Project); orch != nil {
+			orch.Claims().ReleaseByAgent(abortReq.ID)
+		}
 	}
 
+	detail := "graceful"
+	if abortReq.Force {
+		detail = "forced"
+	}
+	s.recordTimeline(a.Info().Project, project.TimelineEvent{
+		Type:    project.TimelineAbort,
+		AgentID: abortReq.ID,
+		IssueID: a.Info().Task,
+		Detail:  detail,
+	})
+
 	return successResponse(req, nil)
 }
 
@@ -256,8 +430,15 @@ func (s *Supervisor) handleAgentChatHistory(ctx context.Context, req *daemon.Req
 		return errorResponse(req, fmt.Sprintf("agent not found: %s", histReq.ID))
 	}
 
-	// Get entries from the agent's history
-	entries := a.History().Entries(histReq.Limit)
+	// Cursors are sequence numbers into the agent's history
+	var before int64
+	if histReq.Before != "" {
+		before, err = strconv.ParseInt(histReq.Before, 10, 64)
+		if err != nil || before <= 0 {
+			return errorResponse(req, fmt.Sprintf("invalid cursor: %s", histReq.Before))
+		}
+	}
+	entries, start, more := a.History().Page(before, histReq.Limit)
 
 	// Convert to DTO format
 	dtos := make([]daemon.ChatEntryDTO, len(entries))
@@ -270,13 +451,18 @@ func (s *Supervisor) handleAgentChatHistory(ctx context.Context, req *daemon.Req
 			ToolResult: e.ToolResult,
 			IsError:    e.IsError,
 			Timestamp:  e.Timestamp.Format(time.RFC3339),
+			Seq:        e.Seq,
 		}
 	}
 
-	return successResponse(req, daemon.AgentChatHistoryResponse{
+	resp := daemon.AgentChatHistoryResponse{
 		AgentID: histReq.ID,
 		Entries: dtos,
-	})
+	}
+	if more {
+		resp.NextCursor = strconv.FormatInt(start, 10)
+	}
+	return successResponse(req, resp)
 }
 
 // handleAgentDescribe sets the description for an agent or planner.
@@ -324,6 +510,118 @@ func (s *Supervisor) handleAgentDescribe(ctx context.Context, req *daemon.Reques
 	return successResponse(req, nil)
 }
 
+// handleAgentRename sets the human-friendly name for an agent.
+func (s *Supervisor) handleAgentRename(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var renameReq daemon.AgentRenameRequest
+	if err := unmarshalPayload(req.Payload, &renameReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	if renameReq.AgentID == "" {
+		return errorResponse(req, "agent_id is required")
+	}
+
+	a, err := s.agents.Get(renameReq.AgentID)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("agent not found: %s", renameReq.AgentID))
+	}
+
+	a.SetName(strings.TrimSpace(renameReq.Name))
+
+	slog.Info("agent renamed",
+		"agent", renameReq.AgentID,
+		"name", a.GetName(),
+	)
+
+	return successResponse(req, nil)
+}
+
+// handleAgentGet returns one agent's status along with details agent.list
+// leaves out: worktree state, claims, pending requests, and token usage.
+func (s *Supervisor) handleAgentGet(_ context.Context, req *daemon.Request) *daemon.Response {
+	var getReq daemon.AgentGetRequest
+	if err := unmarshalPayload(req.Payload, &getReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	a, err := s.agents.Get(getReq.ID)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("agent not found: %s", getReq.ID))
+	}
+	info := a.Info()
+
+	resp := daemon.AgentGetResponse{Agent: agentStatus(info)}
+
+	if info.Worktree != "" {
+		dirty, err := project.IsWorktreeDirty(info.Worktree)
+		if err != nil {
+			slog.Debug("failed to check worktree status", "agent", info.ID, "error", err)
+		}
+		resp.WorktreeDirty = dirty
+	}
+
+	if orch := s.getOrchestrator(info.Project); orch != nil {
+		for ticketID, claimant := range orch.Claims().List() {
+			if claimant == info.ID {
+				resp.Claims = append(resp.Claims, ticketID)
+			}
+		}
+		slices.Sort(resp.Claims)
+	}
+
+	for _, p := range s.permissions.List() {
+		if p.AgentID == info.ID {
+			resp.PendingRequests++
+		}
+	}
+	for _, q := range s.questions.List() {
+		if q.AgentID == info.ID {
+			resp.PendingRequests++
+		}
+	}
+
+	u := a.Usage()
+	resp.Usage = daemon.TokenUsage{
+		InputTokens:              u.InputTokens,
+		OutputTokens:             u.OutputTokens,
+		CacheCreationInputTokens: u.CacheCreationInputTokens,
+		CacheReadInputTokens:     u.CacheReadInputTokens,
+	}
+
+	if s.heartbeat != nil {
+		resp.LastActivity, _ = s.heartbeat.LastOutput(info.ID)
+	}
+
+	return successResponse(req, resp)
+}
+
+// handleAgentLineage returns agents arranged by who spawned them.
+func (s *Supervisor) handleAgentLineage(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var lineageReq daemon.AgentLineageRequest
+	if req.Payload != nil {
+		if err := unmarshalPayload(req.Payload, &lineageReq); err != nil {
+			return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+		}
+	}
+
+	agents := s.agents.List(lineageReq.Project)
+	statuses := make([]daemon.AgentStatus, 0, len(agents))
+	for _, a := range agents {
+		info := a.Info()
+		statuses = append(statuses, daemon.AgentStatus{
+			ID:        info.ID,
+			Name:      info.Name,
+			Project:   info.Project,
+			State:     string(info.State),
+			SpawnedBy: info.SpawnedBy,
+		})
+	}
+
+	return successResponse(req, daemon.AgentLineageResponse{
+		Roots: daemon.BuildLineage(statuses),
+	})
+}
+
 // handleAgentIdle handles the idle notification from the Stop hook.
 // This is called when Claude Code finishes responding, signaling the agent is idle.
 func (s *Supervisor) handleAgentIdle(ctx context.Context, req *daemon.Request) *daemon.Response {
@@ -360,6 +658,11 @@ func (s *Supervisor) handleAgentIdle(ctx context.Context, req *daemon.Request) *
 		)
 	}
 
+	// Replayed agents are driven by the replay, not the kickstart prompt
+	if s.notifyReplayIdle(idleReq.AgentID) {
+		return successResponse(req, nil)
+	}
+
 	// Execute kickstart to resume the agent (respects intervention detection)
 	info := a.Info()
 	if orch := s.getOrchestrator(info.Project); orch != nil {
@@ -373,3 +676,60 @@ func (s *Supervisor) handleAgentIdle(ctx context.Context, req *daemon.Request) *
 
 	return successResponse(req, nil)
 }
+
+// handleAgentDelegate spawns an agent with a specific ticket already claimed for it.
+// Used by the manager to hand work to an agent from within its conversation.
+func (s *Supervisor) handleAgentDelegate(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var delegateReq daemon.AgentDelegateRequest
+	if err := unmarshalPayload(req.Payload, &delegateReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	if delegateReq.TicketID == "" {
+		return errorResponse(req, "ticket_id is required")
+	}
+
+	projectName := delegateReq.Project
+	if projectName == "" {
+		projectName = s.delegatorProject(delegateReq.DelegatedBy)
+	}
+	if projectName == "" {
+		return errorResponse(req, "project name required")
+	}
+
+	orch := s.getOrchestrator(projectName)
+	if orch == nil {
+		return errorResponse(req, "orchestrator not running for project")
+	}
+
+	a, err := orch.Delegate(delegateReq.TicketID, delegateReq.DelegatedBy)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("delegate failed: %v", err))
+	}
+
+	slog.Info("ticket delegated",
+		"ticket", delegateReq.TicketID,
+		"agent", a.ID,
+		"project", projectName,
+		"delegated_by", delegateReq.DelegatedBy,
+	)
+
+	return successResponse(req, daemon.AgentDelegateResponse{
+		ID:       a.ID,
+		Project:  projectName,
+		TicketID: delegateReq.TicketID,
+		Worktree: a.Info().Worktree,
+	})
+}
+
+// delegatorProject returns the project of a delegating manager or agent.
+// Manager IDs have the form "manager:<project>".
+func (s *Supervisor) delegatorProject(delegatedBy string) string {
+	if project, ok := strings.CutPrefix(delegatedBy, ManagerAgentID+":"); ok {
+		return project
+	}
+	if a, err := s.agents.Get(delegatedBy); err == nil {
+		return a.Info().Project
+	}
+	return ""
+}
@@ -4,10 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/tessro/fab/internal/agent"
 	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/issue"
+	"github.com/tessro/fab/internal/logging"
+	"github.com/tessro/fab/internal/orchestrator"
+	"github.com/tessro/fab/internal/project"
 )
 
+// claimedIssueTimeout bounds the lookup of a newly claimed issue.
+const claimedIssueTimeout = 30 * time.Second
+
 // handleAgentClaim handles ticket claim requests from agents.
 func (s *Supervisor) handleAgentClaim(_ context.Context, req *daemon.Request) *daemon.Response {
 	var claimReq daemon.AgentClaimRequest
@@ -34,6 +43,10 @@ func (s *Supervisor) handleAgentClaim(_ context.Context, req *daemon.Request) *d
 		return errorResponse(req, "orchestrator not running for project")
 	}
 
+	if orch.BlocksClaim(claimReq.TicketID) {
+		return errorResponse(req, fmt.Sprintf("claim failed: ticket %s is blocked after repeated agent failures", claimReq.TicketID))
+	}
+
 	// Attempt to claim the ticket
 	if err := orch.Claims().Claim(claimReq.TicketID, claimReq.AgentID); err != nil {
 		return errorResponse(req, fmt.Sprintf("claim failed: %v", err))
@@ -47,10 +60,96 @@ func (s *Supervisor) handleAgentClaim(_ context.Context, req *daemon.Request) *d
 		"agent", claimReq.AgentID,
 		"project", a.Info().Project,
 	)
+	s.recordTimeline(a.Info().Project, project.TimelineEvent{
+		Type:    project.TimelineClaim,
+		AgentID: claimReq.AgentID,
+		IssueID: claimReq.TicketID,
+	})
+
+	// Fetching the issue can be slow (e.g. a GitHub API call), so the claim
+	// returns right away and the epic and guidance follow
+	go s.inspectClaimedIssue(a, orch, claimReq.TicketID)
 
 	return successResponse(req, nil)
 }
 
+// handleAgentStarted starts the read loop of an agent the orchestrator just
+// started. Agents spawned with a ticket already claimed for them (delegated
+// or auto-claimed) get the claim recorded and inspected as if they had
+// claimed it themselves.
+func (s *Supervisor) handleAgentStarted(a *agent.Agent) {
+	// Log but don't fail - agent is still usable without broadcasting
+	_ = s.StartAgentReadLoop(a)
+
+	info := a.Info()
+	if info.Task == "" {
+		return
+	}
+	claim := project.TimelineEvent{
+		Type:    project.TimelineClaim,
+		AgentID: info.ID,
+		IssueID: info.Task,
+	}
+	if agent.Delegated(info.SpawnedBy) {
+		claim.Detail = "delegated by " + info.SpawnedBy
+	}
+	s.recordTimeline(info.Project, claim)
+
+	if orch := s.getOrchestrator(info.Project); orch != nil {
+		go s.inspectClaimedIssue(a, orch, info.Task)
+	}
+}
+
+// inspectClaimedIssue fetches a newly claimed issue, records its parent as
+// the agent's epic, and sends any guidance configured for its type.
+func (s *Supervisor) inspectClaimedIssue(a *agent.Agent, orch *orchestrator.Orchestrator, ticketID string) {
+	defer logging.LogPanic("inspect-claimed-issue", nil)
+
+	// Drop the previous ticket's epic until the new one is known
+	a.SetEpic("")
+
+	ctx, cancel := context.WithTimeout(context.Background(), claimedIssueTimeout)
+	defer cancel()
+
+	iss, err := orch.Issue(ctx, ticketID)
+	if err != nil {
+		slog.Debug("failed to fetch claimed issue", "ticket", ticketID, "agent", a.ID, "error", err)
+		return
+	}
+
+	// The agent may have moved on to another ticket during the fetch
+	if a.GetTask() != ticketID {
+		return
+	}
+	a.SetEpic(iss.Parent)
+	if a.Project != nil && a.Project.GetAgentNaming() == agent.NamingIssue {
+		if name := agent.NameFromTitle(iss.Title); name != "" {
+			a.SetName(name)
+		}
+	}
+
+	if s.globalConfig.HasIssueTypePrompts() {
+		s.sendIssueTypeGuidance(a, ticketID, iss)
+	}
+}
+
+// sendIssueTypeGuidance sends the configured guidance for the claimed
+// issue's type to the agent. Issues without a type, or whose type has no
+// guidance configured, get nothing.
+func (s *Supervisor) sendIssueTypeGuidance(a *agent.Agent, ticketID string, iss *issue.Issue) {
+	prompt := s.globalConfig.GetIssueTypePrompt(iss.Type)
+	if prompt == "" {
+		return
+	}
+
+	message := fmt.Sprintf("Issue %s is a %s. Guidance for this type of issue:\n\n%s", ticketID, iss.Type, prompt)
+	if err := a.SendMessage(message); err != nil {
+		slog.Warn("failed to send issue type guidance", "ticket", ticketID, "agent", a.ID, "error", err)
+		return
+	}
+	slog.Info("sent issue type guidance", "ticket", ticketID, "agent", a.ID, "type", iss.Type)
+}
+
 // handleClaimList returns all active ticket claims.
 func (s *Supervisor) handleClaimList(_ context.Context, req *daemon.Request) *daemon.Response {
 	var listReq daemon.ClaimListRequest
@@ -82,3 +181,57 @@ func (s *Supervisor) handleClaimList(_ context.Context, req *daemon.Request) *da
 		Claims: claims,
 	})
 }
+
+// handleClaimRelease releases a ticket claim on behalf of the user, freeing a
+// ticket held by a stuck agent without waiting for the agent to exit. The
+// agent keeps running but no longer shows the ticket as its task.
+func (s *Supervisor) handleClaimRelease(_ context.Context, req *daemon.Request) *daemon.Response {
+	var releaseReq daemon.ClaimReleaseRequest
+	if err := unmarshalPayload(req.Payload, &releaseReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	if releaseReq.TicketID == "" {
+		return errorResponse(req, "ticket_id is required")
+	}
+
+	// Ticket IDs are per backend, so the same ID may be claimed in more
+	// than one project
+	var held []daemon.ClaimInfo
+	s.mu.RLock()
+	for name, orch := range s.orchestrators {
+		if releaseReq.Project != "" && releaseReq.Project != name {
+			continue
+		}
+		if agentID := orch.Claims().ClaimedBy(releaseReq.TicketID); agentID != "" {
+			held = append(held, daemon.ClaimInfo{TicketID: releaseReq.TicketID, AgentID: agentID, Project: name})
+		}
+	}
+	s.mu.RUnlock()
+
+	switch {
+	case len(held) == 0:
+		return errorResponse(req, fmt.Sprintf("ticket %s is not claimed", releaseReq.TicketID))
+	case len(held) > 1:
+		return errorResponse(req, fmt.Sprintf("ticket %s is claimed in several projects; specify one", releaseReq.TicketID))
+	}
+	claim := held[0]
+
+	orch := s.getOrchestrator(claim.Project)
+	if orch == nil {
+		return errorResponse(req, "orchestrator not running for project")
+	}
+	orch.Claims().Release(claim.TicketID)
+
+	// Clearing the task broadcasts an info event, so clients drop the claim
+	if a, err := s.agents.Get(claim.AgentID); err == nil && a.GetTask() == claim.TicketID {
+		a.SetTask("")
+	}
+
+	slog.Info("ticket claim released",
+		"ticket", claim.TicketID,
+		"agent", claim.AgentID,
+		"project", claim.Project,
+	)
+	return successResponse(req, claim)
+}
@@ -0,0 +1,84 @@
+package supervisor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/orchestrator"
+)
+
+func TestSupervisor_HandleClaimRelease(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	orchs := make(map[string]*orchestrator.Orchestrator)
+	for _, name := range []string{"api", "web"} {
+		proj, err := sup.registry.Add("git@github.com:user/"+name+".git", name, 1, false, "")
+		if err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		sup.agents.RegisterProject(proj)
+		orchs[name] = orchestrator.New(proj, sup.agents, orchestrator.DefaultConfig())
+	}
+	sup.mu.Lock()
+	sup.orchestrators["api"] = orchs["api"]
+	sup.orchestrators["web"] = orchs["web"]
+	sup.mu.Unlock()
+
+	a, err := sup.agents.Hydrate(agent.HydrateInfo{ID: "a1", Project: "api", State: agent.StateRunning, Task: "42", StartedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("Hydrate() error = %v", err)
+	}
+	if err := orchs["api"].Claims().Claim("42", "a1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := orchs["web"].Claims().Claim("42", "b1"); err != nil {
+		t.Fatal(err)
+	}
+
+	release := func(ticket, project string) *daemon.Response {
+		return sup.Handle(context.Background(), &daemon.Request{
+			Type:    daemon.MsgClaimRelease,
+			ID:      "test-1",
+			Payload: daemon.ClaimReleaseRequest{TicketID: ticket, Project: project},
+		})
+	}
+
+	if resp := release("42", ""); resp.Success || !strings.Contains(resp.Error, "several projects") {
+		t.Fatalf("release without project = %+v, want an ambiguity error", resp)
+	}
+
+	resp := release("42", "api")
+	if !resp.Success {
+		t.Fatalf("release failed: %s", resp.Error)
+	}
+	if claim, ok := resp.Payload.(daemon.ClaimInfo); !ok || claim.AgentID != "a1" || claim.Project != "api" {
+		t.Errorf("payload = %+v, want the api claim held by a1", resp.Payload)
+	}
+	if orchs["api"].Claims().IsClaimed("42") || !orchs["web"].Claims().IsClaimed("42") {
+		t.Error("only the api claim should be released")
+	}
+	if task := a.GetTask(); task != "" {
+		t.Errorf("agent task = %q, want cleared", task)
+	}
+
+	// With one claim left, the project can be omitted
+	if resp := release("42", ""); !resp.Success {
+		t.Fatalf("release of the web claim failed: %s", resp.Error)
+	}
+
+	for _, tt := range []struct {
+		ticket, wantErr string
+	}{
+		{"", "ticket_id is required"},
+		{"42", "not claimed"},
+	} {
+		if resp := release(tt.ticket, ""); resp.Success || !strings.Contains(resp.Error, tt.wantErr) {
+			t.Errorf("release(%q) = %+v, want error containing %q", tt.ticket, resp, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/tessro/fab/internal/daemon"
+)
+
+// dashboardSection is one part of a dashboard response, computed by the
+// handler of the message that returns it on its own.
+type dashboardSection struct {
+	name    string
+	handle  func(context.Context, *daemon.Request) *daemon.Response
+	payload any
+	set     func(payload any) bool // Stores the handler's payload; false if it has the wrong type
+}
+
+// handleDashboard returns everything the TUI shows at startup in one
+// response. Sections are computed concurrently, each taking the locks its
+// own handler does; a section that fails is left empty and its error
+// reported, so one failure doesn't hide the rest.
+func (s *Supervisor) handleDashboard(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var dashReq daemon.DashboardRequest
+	if req.Payload != nil {
+		if err := unmarshalPayload(req.Payload, &dashReq); err != nil {
+			return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+		}
+	}
+
+	if err := daemon.ValidateAgentSort(dashReq.SortBy, dashReq.Order); err != nil {
+		return errorResponse(req, err.Error())
+	}
+
+	// Each section sets a different field, so only Errors needs the lock
+	var resp daemon.DashboardResponse
+	sections := []dashboardSection{
+		{
+			name:    daemon.DashboardAgents,
+			handle:  s.handleAgentList,
+			payload: daemon.AgentListRequest{SortBy: dashReq.SortBy, Order: dashReq.Order},
+			set: func(p any) bool {
+				r, ok := p.(daemon.AgentListResponse)
+				resp.Agents = r.Agents
+				return ok
+			},
+		},
+		{
+			name:   daemon.DashboardPlanners,
+			handle: s.handlePlanList,
+			set: func(p any) bool {
+				r, ok := p.(daemon.PlanListResponse)
+				resp.Planners = r.Planners
+				return ok
+			},
+		},
+		{
+			name:   daemon.DashboardStatus,
+			handle: s.handleStatus,
+			set: func(p any) bool {
+				r, ok := p.(daemon.StatusResponse)
+				if ok {
+					resp.Status = &r
+				}
+				return ok
+			},
+		},
+		{
+			name:   daemon.DashboardClaims,
+			handle: s.handleClaimList,
+			set: func(p any) bool {
+				r, ok := p.(daemon.ClaimListResponse)
+				resp.Claims = r.Claims
+				return ok
+			},
+		},
+		{
+			name:   daemon.DashboardPermissions,
+			handle: s.handlePermissionList,
+			set: func(p any) bool {
+				r, ok := p.(daemon.PermissionListResponse)
+				resp.Permissions = r.Requests
+				return ok
+			},
+		},
+	}
+	if dashReq.Manager != "" {
+		sections = append(sections, dashboardSection{
+			name:    daemon.DashboardManager,
+			handle:  s.handleManagerStatus,
+			payload: daemon.ManagerStatusRequest{Project: dashReq.Manager},
+			set: func(p any) bool {
+				r, ok := p.(daemon.ManagerStatusResponse)
+				if ok {
+					resp.Manager = &r
+				}
+				return ok
+			},
+		})
+	}
+
+	var (
+		wg     sync.WaitGroup
+		errMu  sync.Mutex
+		failed = make(map[string]string)
+	)
+	for _, sec := range sections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			secResp := sec.handle(ctx, &daemon.Request{Type: req.Type, ID: req.ID, Payload: sec.payload})
+			var errMsg string
+			switch {
+			case !secResp.Success:
+				errMsg = secResp.Error
+			case !sec.set(secResp.Payload):
+				errMsg = fmt.Sprintf("unexpected payload type %T", secResp.Payload)
+			default:
+				return
+			}
+			errMu.Lock()
+			failed[sec.name] = errMsg
+			errMu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		resp.Errors = failed
+	}
+	return successResponse(req, resp)
+}
@@ -0,0 +1,145 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tessro/fab/internal/daemon"
+)
+
+// execMaxOutput caps how much agent exec output is returned. The tail is
+// kept since that's where errors and summaries usually are.
+const execMaxOutput = 64 * 1024
+
+// execWaitDelay bounds how long we wait for output pipes to close after a
+// timed-out command is killed.
+const execWaitDelay = 5 * time.Second
+
+// handleAgentExec runs an operator's shell command in an agent's worktree.
+// This is separate from messaging the agent: the command runs directly,
+// and the agent never sees it or its output.
+func (s *Supervisor) handleAgentExec(ctx context.Context, req *daemon.Request) *daemon.Response {
+	if !s.globalConfig.ExecEnabled() {
+		return errorResponse(req, "agent exec is disabled; set exec.enabled = true in config.toml and restart the daemon")
+	}
+
+	var execReq daemon.AgentExecRequest
+	if err := unmarshalPayload(req.Payload, &execReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	if execReq.ID == "" {
+		return errorResponse(req, "agent ID required")
+	}
+	if strings.TrimSpace(execReq.Command) == "" {
+		return errorResponse(req, "command required")
+	}
+
+	a, err := s.agents.Get(execReq.ID)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("agent not found: %s", execReq.ID))
+	}
+	worktree := a.Info().Worktree
+	if worktree == "" {
+		return errorResponse(req, fmt.Sprintf("agent %s has no worktree", execReq.ID))
+	}
+
+	// Never outlast the client, or it gives up before the output arrives
+	timeout := s.globalConfig.GetExecTimeout()
+	if execReq.Timeout != "" {
+		d, err := time.ParseDuration(execReq.Timeout)
+		if err != nil {
+			return errorResponse(req, fmt.Sprintf("invalid timeout: %v", err))
+		}
+		if d > 0 && d < timeout {
+			timeout = d
+		}
+	}
+
+	slog.Info("running agent exec command", "agent", execReq.ID, "command", execReq.Command, "timeout", timeout)
+
+	resp, err := runExecCommand(ctx, worktree, execReq.Command, timeout)
+	if err != nil {
+		return errorResponse(req, err.Error())
+	}
+	resp.ID = execReq.ID
+
+	slog.Info("agent exec command finished",
+		"agent", execReq.ID,
+		"exit_code", resp.ExitCode,
+		"timed_out", resp.TimedOut,
+	)
+
+	return successResponse(req, *resp)
+}
+
+// runExecCommand runs command with sh -c in dir, killing its process group
+// after timeout. A non-zero exit is reported in the response, not as an
+// error; errors are reserved for failures to start the command.
+func runExecCommand(ctx context.Context, dir, command string, timeout time.Duration) (*daemon.AgentExecResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var output tailBuffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = execWaitDelay
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start command: %w", err)
+	}
+	waitErr := cmd.Wait()
+
+	// String marks the buffer truncated, so read it before the flag
+	out := output.String()
+	resp := &daemon.AgentExecResponse{
+		Worktree:  dir,
+		Output:    out,
+		ExitCode:  cmd.ProcessState.ExitCode(),
+		TimedOut:  errors.Is(ctx.Err(), context.DeadlineExceeded),
+		Truncated: output.truncated,
+	}
+	var exitErr *exec.ExitError
+	if waitErr != nil && !errors.As(waitErr, &exitErr) && !resp.TimedOut {
+		return nil, fmt.Errorf("wait for command: %w", waitErr)
+	}
+	return resp, nil
+}
+
+// tailBuffer is an io.Writer that keeps roughly the last execMaxOutput bytes
+// written to it.
+type tailBuffer struct {
+	buf       []byte
+	truncated bool
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	// Trim in batches so steady output doesn't copy on every write
+	if len(b.buf) > 2*execMaxOutput {
+		b.buf = append(b.buf[:0], b.buf[len(b.buf)-execMaxOutput:]...)
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+// String returns the captured output, at most execMaxOutput bytes.
+func (b *tailBuffer) String() string {
+	if len(b.buf) > execMaxOutput {
+		b.truncated = true
+		return string(b.buf[len(b.buf)-execMaxOutput:])
+	}
+	return string(b.buf)
+}
@@ -0,0 +1,97 @@
+package supervisor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/config"
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/project"
+)
+
+func TestSupervisor_HandleAgentExec(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	worktree := t.TempDir()
+	if err := os.WriteFile(filepath.Join(worktree, "marker.txt"), []byte("here\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sup.agents.RegisterProject(&project.Project{Name: "proj"})
+	if _, err := sup.agents.Hydrate(agent.HydrateInfo{
+		ID:        "exec01",
+		Project:   "proj",
+		State:     agent.StateRunning,
+		Worktree:  worktree,
+		StartedAt: time.Now(),
+		Backend:   "claude",
+	}); err != nil {
+		t.Fatalf("Hydrate() error = %v", err)
+	}
+
+	exec := func(command string) *daemon.Response {
+		return sup.Handle(context.Background(), &daemon.Request{
+			Type:    daemon.MsgAgentExec,
+			ID:      "test-1",
+			Payload: map[string]any{"id": "exec01", "command": command},
+		})
+	}
+
+	// Disabled by default
+	if resp := exec("cat marker.txt"); resp.Success || !strings.Contains(resp.Error, "disabled") {
+		t.Fatalf("exec while disabled: success=%v error=%q, want disabled error", resp.Success, resp.Error)
+	}
+
+	sup.globalConfig = &config.GlobalConfig{Exec: config.ExecConfig{Enabled: true, Timeout: "200ms"}}
+
+	resp := exec("cat marker.txt; echo oops >&2; exit 3")
+	if !resp.Success {
+		t.Fatalf("exec failed: %s", resp.Error)
+	}
+	result := resp.Payload.(daemon.AgentExecResponse)
+	if result.Output != "here\noops\n" || result.ExitCode != 3 || result.TimedOut {
+		t.Errorf("exec result = %+v, want output from the worktree and exit code 3", result)
+	}
+
+	resp = exec("sleep 5")
+	if !resp.Success {
+		t.Fatalf("exec failed: %s", resp.Error)
+	}
+	if result := resp.Payload.(daemon.AgentExecResponse); !result.TimedOut {
+		t.Errorf("exec result = %+v, want timed out", result)
+	}
+
+	// A client that waits less than exec.timeout caps it
+	sup.globalConfig.Exec.Timeout = "1m"
+	start := time.Now()
+	resp = sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgAgentExec,
+		ID:      "test-2",
+		Payload: map[string]any{"id": "exec01", "command": "sleep 5", "timeout": "200ms"},
+	})
+	if !resp.Success {
+		t.Fatalf("exec failed: %s", resp.Error)
+	}
+	if result := resp.Payload.(daemon.AgentExecResponse); !result.TimedOut || time.Since(start) > 4*time.Second {
+		t.Errorf("exec result = %+v after %s, want timed out at the client's timeout", result, time.Since(start))
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	var b tailBuffer
+	chunk := strings.Repeat("x", 1000) + "\n"
+	for range 3 * execMaxOutput / len(chunk) {
+		_, _ = b.Write([]byte(chunk))
+	}
+	_, _ = b.Write([]byte("end\n"))
+
+	out := b.String()
+	if len(out) != execMaxOutput || !strings.HasSuffix(out, "end\n") || !b.truncated {
+		t.Errorf("String() len=%d truncated=%v, want the last %d bytes", len(out), b.truncated, execMaxOutput)
+	}
+}
@@ -0,0 +1,138 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/runtime"
+)
+
+// Bounds for agent.grep responses.
+const (
+	defaultGrepLimit = 100
+	maxGrepContext   = 20
+)
+
+// handleAgentGrep searches an agent's chat history, including entries
+// spilled to disk, so clients don't have to fetch the whole transcript to
+// search it. Deleted agents are searched in their saved transcript.
+func (s *Supervisor) handleAgentGrep(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var grepReq daemon.AgentGrepRequest
+	if err := unmarshalPayload(req.Payload, &grepReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	if grepReq.ID == "" {
+		return errorResponse(req, "agent ID required")
+	}
+	if grepReq.Pattern == "" && grepReq.Tool == "" {
+		return errorResponse(req, "pattern or tool required")
+	}
+	re, err := regexp.Compile(grepReq.Pattern)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid pattern: %v", err))
+	}
+
+	entries, err := s.grepEntries(grepReq.ID)
+	if err != nil {
+		return errorResponse(req, err.Error())
+	}
+
+	limit := grepReq.Limit
+	if limit <= 0 {
+		limit = defaultGrepLimit
+	}
+	matches, truncated := grepHistory(entries, re, grepReq.Tool, min(max(grepReq.Context, 0), maxGrepContext), limit)
+	return successResponse(req, daemon.AgentGrepResponse{
+		AgentID:   grepReq.ID,
+		Matches:   matches,
+		Searched:  len(entries),
+		Truncated: truncated,
+	})
+}
+
+// grepEntries returns an agent's chat history in order. Running agents are
+// read from memory and their spill file; deleted agents from their saved
+// transcript, numbered by position since transcripts don't keep sequence
+// numbers.
+func (s *Supervisor) grepEntries(id string) ([]daemon.ChatEntryDTO, error) {
+	if a, err := s.agents.Get(id); err == nil {
+		history, _, _ := a.History().Page(0, 0)
+		entries := make([]daemon.ChatEntryDTO, len(history))
+		for i, e := range history {
+			entries[i] = daemon.ChatEntryDTO{
+				Role:       e.Role,
+				Content:    e.Content,
+				ToolName:   e.ToolName,
+				ToolInput:  e.ToolInput,
+				ToolResult: e.ToolResult,
+				IsError:    e.IsError,
+				Timestamp:  e.Timestamp.Format(time.RFC3339),
+				Seq:        e.Seq,
+			}
+		}
+		return entries, nil
+	}
+
+	t, err := s.agents.LoadTranscript(id)
+	if err != nil {
+		if errors.Is(err, runtime.ErrTranscriptNotFound) {
+			return nil, fmt.Errorf("agent not found: %s", id)
+		}
+		return nil, fmt.Errorf("load transcript: %w", err)
+	}
+	entries := make([]daemon.ChatEntryDTO, len(t.Entries))
+	for i, e := range t.Entries {
+		entries[i] = daemon.ChatEntryDTO{
+			Role:       e.Role,
+			Content:    e.Content,
+			ToolName:   e.ToolName,
+			ToolInput:  e.ToolInput,
+			ToolResult: e.ToolResult,
+			IsError:    e.IsError,
+			Timestamp:  e.Timestamp.Format(time.RFC3339),
+			Seq:        int64(i),
+		}
+	}
+	return entries, nil
+}
+
+// grepHistory returns up to limit entries matching re, with contextSize
+// entries on each side. If tool is set, only tool entries for that tool
+// match; results don't name their tool, so each is attributed to the
+// closest call before it. truncated reports whether more entries matched.
+func grepHistory(entries []daemon.ChatEntryDTO, re *regexp.Regexp, tool string, contextSize, limit int) (matches []daemon.AgentGrepMatch, truncated bool) {
+	matches = []daemon.AgentGrepMatch{}
+	lastTool := ""
+	for i, e := range entries {
+		entryTool := ""
+		if e.Role == "tool" {
+			if e.ToolName != "" {
+				lastTool = e.ToolName
+			}
+			entryTool = lastTool
+		}
+		if tool != "" && !strings.EqualFold(entryTool, tool) {
+			continue
+		}
+		if !re.MatchString(e.Content) && !re.MatchString(e.ToolInput) && !re.MatchString(e.ToolResult) {
+			continue
+		}
+		if len(matches) == limit {
+			return matches, true
+		}
+
+		m := daemon.AgentGrepMatch{Entry: e}
+		if contextSize > 0 {
+			m.Before = entries[max(i-contextSize, 0):i]
+			m.After = entries[i+1 : min(i+1+contextSize, len(entries))]
+		}
+		matches = append(matches, m)
+	}
+	return matches, false
+}
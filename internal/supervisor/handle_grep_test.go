@@ -0,0 +1,132 @@
+package supervisor
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/project"
+	"github.com/tessro/fab/internal/runtime"
+)
+
+func TestGrepHistory(t *testing.T) {
+	entries := []daemon.ChatEntryDTO{
+		{Role: "user", Content: "fix the flaky test", Seq: 0},
+		{Role: "tool", ToolName: "Bash", ToolInput: "go test ./...", Seq: 1},
+		{Role: "tool", ToolResult: "FAIL: TestFlaky", IsError: true, Seq: 2},
+		{Role: "assistant", Content: "TestFlaky races on the timer", Seq: 3},
+		{Role: "tool", ToolName: "Edit", ToolInput: "flaky_test.go", Seq: 4},
+		{Role: "tool", ToolResult: "ok", Seq: 5},
+	}
+	seqs := func(matches []daemon.AgentGrepMatch) []int64 {
+		var s []int64
+		for _, m := range matches {
+			s = append(s, m.Entry.Seq)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		tool    string
+		limit   int
+		want    []int64
+		trunc   bool
+	}{
+		{"content and results", "(?i)flaky", "", 10, []int64{0, 2, 3, 4}, false},
+		{"tool input", `go test`, "", 10, []int64{1}, false},
+		{"tool filter includes results", "", "bash", 10, []int64{1, 2}, false},
+		{"tool filter and pattern", "ok", "Edit", 10, []int64{5}, false},
+		{"limit", "(?i)flaky", "", 2, []int64{0, 2}, true},
+		{"no matches", "panic", "", 10, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, truncated := grepHistory(entries, regexp.MustCompile(tt.pattern), tt.tool, 0, tt.limit)
+			if got := seqs(matches); !slices.Equal(got, tt.want) || truncated != tt.trunc {
+				t.Errorf("grepHistory() = %v, truncated %v; want %v, truncated %v", got, truncated, tt.want, tt.trunc)
+			}
+		})
+	}
+
+	matches, _ := grepHistory(entries, regexp.MustCompile("races"), "", 2, 10)
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1", len(matches))
+	}
+	if before := matches[0].Before; len(before) != 2 || before[0].Seq != 1 || before[1].Seq != 2 {
+		t.Errorf("Before = %+v, want entries 1 and 2", before)
+	}
+	if len(matches[0].After) != 2 || matches[0].After[1].Seq != 5 {
+		t.Errorf("After = %+v, want entries 4 and 5", matches[0].After)
+	}
+}
+
+func TestSupervisor_HandleAgentGrep(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+	store := runtime.NewTranscriptStore(t.TempDir())
+	sup.agents.SetTranscriptStore(store)
+	sup.agents.RegisterProject(&project.Project{Name: "proj"})
+
+	a, err := sup.agents.Hydrate(agent.HydrateInfo{
+		ID:        "live1",
+		Project:   "proj",
+		State:     agent.StateRunning,
+		StartedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Hydrate() error = %v", err)
+	}
+	a.History().Add(agent.ChatEntry{Role: "user", Content: "deploy it"})
+	a.History().Add(agent.ChatEntry{Role: "assistant", Content: "Deploying now"})
+
+	if err := store.Save(runtime.Transcript{
+		AgentID: "old1",
+		Project: "proj",
+		Entries: []runtime.TranscriptEntry{
+			{Role: "user", Content: "first"},
+			{Role: "assistant", Content: "deploy failed"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	grep := func(payload daemon.AgentGrepRequest) *daemon.Response {
+		return sup.Handle(context.Background(), &daemon.Request{Type: daemon.MsgAgentGrep, ID: "test-1", Payload: payload})
+	}
+
+	for _, id := range []string{"live1", "old1"} {
+		resp := grep(daemon.AgentGrepRequest{ID: id, Pattern: "(?i)deploy", Context: 1})
+		if !resp.Success {
+			t.Fatalf("grep %s: expected success, got error: %s", id, resp.Error)
+		}
+		payload, ok := resp.Payload.(daemon.AgentGrepResponse)
+		if !ok {
+			t.Fatalf("expected AgentGrepResponse payload, got %T", resp.Payload)
+		}
+		if payload.AgentID != id || payload.Searched != 2 || len(payload.Matches) == 0 {
+			t.Errorf("grep %s = %+v, want matches among 2 entries", id, payload)
+		}
+	}
+
+	for _, tt := range []struct {
+		req     daemon.AgentGrepRequest
+		wantErr string
+	}{
+		{daemon.AgentGrepRequest{Pattern: "x"}, "agent ID required"},
+		{daemon.AgentGrepRequest{ID: "live1"}, "pattern or tool required"},
+		{daemon.AgentGrepRequest{ID: "live1", Pattern: "("}, "invalid pattern"},
+		{daemon.AgentGrepRequest{ID: "missing", Pattern: "x"}, "agent not found"},
+	} {
+		resp := grep(tt.req)
+		if resp.Success || !strings.Contains(resp.Error, tt.wantErr) {
+			t.Errorf("grep(%+v) = %+v, want error containing %q", tt.req, resp, tt.wantErr)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/tessro/fab/internal/agent"
@@ -16,6 +18,9 @@ import (
 
 // getProjectManager returns the manager for a project, creating it if necessary.
 // It also creates the manager worktree if it doesn't exist.
+//
+// The worktree is created without holding s.mu, since `git worktree add` can
+// be slow and would otherwise stall every other manager handler.
 func (s *Supervisor) getProjectManager(projectName string) (*manager.Manager, error) {
 	proj, err := s.registry.Get(projectName)
 	if err != nil {
@@ -23,35 +28,50 @@ func (s *Supervisor) getProjectManager(projectName string) (*manager.Manager, er
 	}
 
 	// Check if we already have a manager for this project
-	s.mu.Lock()
-	mgr, ok := s.managers[projectName]
-	if ok {
-		s.mu.Unlock()
+	if mgr, ok := s.lookupManager(projectName); ok {
+		return mgr, nil
+	}
+
+	// Serialize creation so concurrent starts don't race on the worktree,
+	// then check again in case another caller finished first.
+	s.managerCreateMu.Lock()
+	defer s.managerCreateMu.Unlock()
+	if mgr, ok := s.lookupManager(projectName); ok {
 		return mgr, nil
 	}
 
 	// Ensure manager worktree exists
-	if err := proj.CreateManagerWorktree(); err != nil {
-		s.mu.Unlock()
+	if err := s.createManagerWorktree(proj); err != nil {
 		return nil, fmt.Errorf("create manager worktree: %w", err)
 	}
 
 	// Get the agent backend for this project
 	b, err := backend.Get(proj.GetAgentBackend())
 	if err != nil {
-		s.mu.Unlock()
 		return nil, fmt.Errorf("get backend: %w", err)
 	}
 
 	// Create new manager for this project
 	wtPath := proj.ManagerWorktreePath()
-	mgr = manager.New(wtPath, projectName, b, s.managerPatterns)
-	s.managers[projectName] = mgr
-	s.mu.Unlock()
+	mgr := manager.New(wtPath, projectName, b, s.managerPatterns)
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.managers[projectName]; ok {
+		return existing, nil
+	}
+	s.managers[projectName] = mgr
 	return mgr, nil
 }
 
+// lookupManager returns the existing manager for a project, if any.
+func (s *Supervisor) lookupManager(projectName string) (*manager.Manager, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	mgr, ok := s.managers[projectName]
+	return mgr, ok
+}
+
 // setupManagerCallbacks sets up callbacks for manager events to broadcast to TUI clients.
 func (s *Supervisor) setupManagerCallbacks(mgr *manager.Manager) {
 	projectName := mgr.Project()
@@ -66,6 +86,9 @@ func (s *Supervisor) setupManagerCallbacks(mgr *manager.Manager) {
 	mgr.OnThreadIDChange(func(threadID string) {
 		s.updateManagerThreadID(projectName, threadID)
 	})
+	mgr.OnTurnComplete(func(isError bool) {
+		s.broadcastManagerIdle(projectName, isError)
+	})
 }
 
 // saveManagerRuntime persists manager runtime metadata to the store.
@@ -164,6 +187,27 @@ func (s *Supervisor) broadcastManagerStateTyped(projectName string, state manage
 	srv.Broadcast(event)
 }
 
+// broadcastManagerIdle tells attached clients that the manager finished a
+// turn and is waiting for input.
+func (s *Supervisor) broadcastManagerIdle(projectName string, isError bool) {
+	s.mu.RLock()
+	srv := s.server
+	s.mu.RUnlock()
+
+	if srv == nil {
+		return
+	}
+
+	event := &daemon.StreamEvent{
+		Type:    "manager_idle",
+		Project: projectName,
+	}
+	if isError {
+		event.Error = "manager turn ended with an error"
+	}
+	srv.Broadcast(event)
+}
+
 // handleManagerStart starts the manager agent for a project.
 func (s *Supervisor) handleManagerStart(_ context.Context, req *daemon.Request) *daemon.Response {
 	var startReq daemon.ManagerStartRequest
@@ -255,18 +299,41 @@ func (s *Supervisor) handleManagerStatus(_ context.Context, req *daemon.Request)
 		})
 	}
 
+	return successResponse(req, managerStatus(mgr))
+}
+
+// handleManagerListAll returns the status of every project's manager in one
+// call, so clients don't need a manager.status request per project.
+// Projects whose manager was never started are left out.
+func (s *Supervisor) handleManagerListAll(_ context.Context, req *daemon.Request) *daemon.Response {
+	s.mu.RLock()
+	managers := make([]daemon.ManagerStatusResponse, 0, len(s.managers))
+	for _, mgr := range s.managers {
+		managers = append(managers, managerStatus(mgr))
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(managers, func(a, b daemon.ManagerStatusResponse) int {
+		return strings.Compare(a.Project, b.Project)
+	})
+	return successResponse(req, daemon.ManagerListResponse{Managers: managers})
+}
+
+// managerStatus reports a manager's state for manager.status and
+// manager.list responses.
+func managerStatus(mgr *manager.Manager) daemon.ManagerStatusResponse {
 	startedAt := ""
 	if mgr.IsRunning() {
 		startedAt = mgr.StartedAt().Format(time.RFC3339)
 	}
 
-	return successResponse(req, daemon.ManagerStatusResponse{
-		Project:   statusReq.Project,
+	return daemon.ManagerStatusResponse{
+		Project:   mgr.Project(),
 		Running:   mgr.IsRunning(),
 		State:     string(mgr.State()),
 		StartedAt: startedAt,
 		WorkDir:   mgr.WorkDir(),
-	})
+	}
 }
 
 // handleManagerSendMessage sends a message to the manager agent for a project.
@@ -377,20 +444,21 @@ func (s *Supervisor) handleManagerClearHistory(_ context.Context, req *daemon.Re
 	return successResponse(req, nil)
 }
 
-// loadManagerPatterns loads manager allowed patterns from the global permissions.toml.
-// Returns default patterns if the file doesn't exist or has no manager section.
-func loadManagerPatterns() []string {
+// loadPermissionsConfig loads the global permissions.toml for the manager's
+// allowed patterns and the headless permission policy. Returns nil (defaults)
+// if the file doesn't exist or can't be loaded.
+func loadPermissionsConfig() *rules.Config {
 	path, err := rules.GlobalConfigPath()
 	if err != nil {
 		slog.Debug("failed to get permissions config path", "error", err)
-		return rules.DefaultManagerAllowedPatterns
+		return nil
 	}
 
 	cfg, err := rules.LoadConfig(path)
 	if err != nil {
 		slog.Warn("failed to load permissions config", "path", path, "error", err)
-		return rules.DefaultManagerAllowedPatterns
+		return nil
 	}
 
-	return cfg.ManagerAllowedPatterns()
+	return cfg
 }
@@ -0,0 +1,88 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/project"
+)
+
+// handleNoteAppend appends a note to a project's shared notes file.
+func (s *Supervisor) handleNoteAppend(_ context.Context, req *daemon.Request) *daemon.Response {
+	var appendReq daemon.NoteAppendRequest
+	if err := unmarshalPayload(req.Payload, &appendReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	proj, err := s.notesProject(appendReq.AgentID, appendReq.Project)
+	if err != nil {
+		return errorResponse(req, err.Error())
+	}
+
+	if err := proj.AppendNote(s.noteAuthor(appendReq.AgentID), appendReq.Text, time.Now()); err != nil {
+		return errorResponse(req, fmt.Sprintf("failed to append note: %v", err))
+	}
+
+	slog.Info("note appended", "project", proj.Name, "agent", appendReq.AgentID, "bytes", len(appendReq.Text))
+	return successResponse(req, nil)
+}
+
+// handleNoteRead returns a project's shared notes.
+func (s *Supervisor) handleNoteRead(_ context.Context, req *daemon.Request) *daemon.Response {
+	var readReq daemon.NoteReadRequest
+	if err := unmarshalPayload(req.Payload, &readReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	proj, err := s.notesProject(readReq.AgentID, readReq.Project)
+	if err != nil {
+		return errorResponse(req, err.Error())
+	}
+
+	content, truncated, err := proj.ReadNotes()
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("failed to read notes: %v", err))
+	}
+
+	return successResponse(req, daemon.NoteReadResponse{
+		Project:   proj.Name,
+		Path:      proj.NotesPath(),
+		Content:   content,
+		Truncated: truncated,
+	})
+}
+
+// notesProject resolves the project whose notes a request refers to: the
+// named project, else the project of the calling agent or manager.
+func (s *Supervisor) notesProject(agentID, projectName string) (*project.Project, error) {
+	if projectName == "" {
+		projectName = s.delegatorProject(agentID)
+	}
+	if projectName == "" {
+		return nil, fmt.Errorf("project is required")
+	}
+	proj, err := s.registry.Get(projectName)
+	if err != nil {
+		return nil, fmt.Errorf("project not found: %s", projectName)
+	}
+	return proj, nil
+}
+
+// noteAuthor names a note's author in the notes file: the agent and the
+// ticket it's working on, or "user" for notes added from a shell.
+func (s *Supervisor) noteAuthor(agentID string) string {
+	if agentID == "" {
+		return "user"
+	}
+	a, err := s.agents.Get(agentID)
+	if err != nil {
+		return agentID
+	}
+	if task := a.Info().Task; task != "" {
+		return fmt.Sprintf("%s (%s)", agentID, task)
+	}
+	return agentID
+}
@@ -3,10 +3,12 @@ package supervisor
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/tessro/fab/internal/agent"
 	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/orchestrator"
 )
 
 // handleStart starts orchestration for a project.
@@ -17,14 +19,19 @@ func (s *Supervisor) handleStart(ctx context.Context, req *daemon.Request) *daem
 	}
 
 	if startReq.All {
-		// Start all projects
+		// Start all projects, continuing past failures so each outcome is reported
 		projects := s.registry.List()
+		results := make([]daemon.ProjectStartResult, 0, len(projects))
 		for _, p := range projects {
+			result := daemon.ProjectStartResult{Project: p.Name, Started: true}
 			if err := s.startOrchestrator(ctx, p); err != nil {
-				return errorResponse(req, fmt.Sprintf("failed to start project %s: %v", p.Name, err))
+				slog.Warn("failed to start project", "project", p.Name, "error", err)
+				result.Started = false
+				result.Error = err.Error()
 			}
+			results = append(results, result)
 		}
-		return successResponse(req, nil)
+		return successResponse(req, daemon.StartResponse{Results: results})
 	}
 
 	if startReq.Project == "" {
@@ -40,7 +47,9 @@ func (s *Supervisor) handleStart(ctx context.Context, req *daemon.Request) *daem
 		return errorResponse(req, fmt.Sprintf("failed to start project: %v", err))
 	}
 
-	return successResponse(req, nil)
+	return successResponse(req, daemon.StartResponse{
+		Results: []daemon.ProjectStartResult{{Project: proj.Name, Started: true}},
+	})
 }
 
 // handleStop stops orchestration for a project.
@@ -53,10 +62,14 @@ func (s *Supervisor) handleStop(ctx context.Context, req *daemon.Request) *daemo
 	if stopReq.All {
 		// Stop all projects
 		projects := s.registry.List()
+		results := make([]daemon.ProjectStopResult, 0, len(projects))
 		for _, p := range projects {
-			s.stopOrchestrator(p.Name)
+			results = append(results, daemon.ProjectStopResult{
+				Project: p.Name,
+				Stopped: s.stopOrchestrator(p.Name),
+			})
 		}
-		return successResponse(req, nil)
+		return successResponse(req, daemon.StopResponse{Results: results})
 	}
 
 	if stopReq.Project == "" {
@@ -68,8 +81,10 @@ func (s *Supervisor) handleStop(ctx context.Context, req *daemon.Request) *daemo
 		return errorResponse(req, fmt.Sprintf("project not found: %s", stopReq.Project))
 	}
 
-	s.stopOrchestrator(proj.Name)
-	return successResponse(req, nil)
+	stopped := s.stopOrchestrator(proj.Name)
+	return successResponse(req, daemon.StopResponse{
+		Results: []daemon.ProjectStopResult{{Project: proj.Name, Stopped: stopped}},
+	})
 }
 
 // handleStatus returns the current daemon status.
@@ -92,22 +107,63 @@ func (s *Supervisor) handleStatus(ctx context.Context, req *daemon.Request) *dae
 			info := a.Info()
 			agentStatuses = append(agentStatuses, daemon.AgentStatus{
 				ID:          info.ID,
+				Name:        info.Name,
 				Project:     info.Project,
 				State:       string(info.State),
 				Worktree:    info.Worktree,
 				StartedAt:   info.StartedAt,
 				Task:        info.Task,
 				Description: info.Description,
+				StashRef:    info.StashRef,
+				Epic:        info.Epic,
+				CurrentFile: info.CurrentFile,
+				Model:       info.Model,
+				SpawnedBy:   info.SpawnedBy,
+				Role:        info.Role,
+				Subdir:      info.Subdir,
 			})
 		}
 
+		var cacheStats *daemon.IssueCacheStats
+		s.mu.RLock()
+		cache := s.issueCaches[p.Name]
+		s.mu.RUnlock()
+		if cache != nil {
+			stats := cache.Stats()
+			cacheStats = &daemon.IssueCacheStats{Hits: stats.Hits, Misses: stats.Misses}
+		}
+
+		var failures []daemon.IssueFailureStats
+		var backlog orchestrator.Backlog
+		if orch := s.getOrchestrator(p.Name); orch != nil {
+			var err error
+			if backlog, err = orch.Backlog(); err != nil {
+				slog.Debug("failed to count waiting issues", "project", p.Name, "error", err)
+			}
+			for _, f := range orch.IssueFailures() {
+				failures = append(failures, daemon.IssueFailureStats{
+					Issue:       f.IssueID,
+					Failures:    f.Failures,
+					LastFailure: f.LastFailure,
+					LastReason:  f.LastReason,
+					Blocked:     f.Blocked,
+				})
+			}
+		}
+
 		projectStatuses = append(projectStatuses, daemon.ProjectStatus{
 			Name:         p.Name,
 			RemoteURL:    p.RemoteURL,
 			Running:      p.IsRunning(),
 			MaxAgents:    p.MaxAgents,
 			ActiveAgents: p.ActiveAgentCount(),
+			Reserved:     p.ReservedSlots,
 			Agents:       agentStatuses,
+			IssueCache:   cacheStats,
+
+			WaitingIssues: backlog.Waiting,
+			AtCapacity:    backlog.AtCapacity,
+			IssueFailures: failures,
 		})
 	}
 
@@ -12,11 +12,17 @@ import (
 	"github.com/tessro/fab/internal/llmauth"
 	"github.com/tessro/fab/internal/logging"
 	"github.com/tessro/fab/internal/project"
+	"github.com/tessro/fab/internal/rules"
 )
 
 // handlePermissionRequest handles a permission request from the hook command.
 // This blocks until a TUI client responds via permission.respond, or if LLM auth
 // is enabled for the project, uses LLM to make the decision automatically.
+// Requests matching a permission rule (project rules, including decisions
+// remembered from the TUI, then global rules) are answered without
+// prompting; only requests no rule decides reach the TUI.
+// When no TUI is attached, the headless policy from permissions.toml decides
+// whether to allow, deny, or hold the request.
 func (s *Supervisor) handlePermissionRequest(ctx context.Context, req *daemon.Request) *daemon.Response {
 	var permReq daemon.PermissionRequestPayload
 	if err := unmarshalPayload(req.Payload, &permReq); err != nil {
@@ -30,6 +36,8 @@ func (s *Supervisor) handlePermissionRequest(ctx context.Context, req *daemon.Re
 	// Find the project and agent for this request
 	var projectName string
 	var agentTask string
+	var workDir string
+	var role string
 	var conversationCtx []string
 	var proj *project.Project
 
@@ -41,6 +49,7 @@ func (s *Supervisor) handlePermissionRequest(ctx context.Context, req *daemon.Re
 				info := p.Info()
 				projectName = info.Project
 				agentTask = "Planning agent"
+				workDir = info.WorkDir
 
 				// Get recent conversation history for context
 				entries := p.History().Entries(10) // Last 10 entries
@@ -60,6 +69,8 @@ func (s *Supervisor) handlePermissionRequest(ctx context.Context, req *daemon.Re
 		} else if a, err := s.agents.Get(permReq.AgentID); err == nil {
 			info := a.Info()
 			projectName = info.Project
+			workDir = info.Worktree
+			role = info.Role
 			agentTask = info.Description
 			if agentTask == "" {
 				agentTask = info.Task
@@ -88,6 +99,16 @@ func (s *Supervisor) handlePermissionRequest(ctx context.Context, req *daemon.Re
 		"input", logging.TruncateForLog(string(permReq.ToolInput), 200),
 	)
 
+	// A role's restrictions hold no matter what other rules or the TUI say
+	if resp := s.roleDecision(ctx, role, workDir, permReq, log); resp != nil {
+		return successResponse(req, resp)
+	}
+
+	// Answer from the permission rules before asking anyone
+	if resp := s.ruleDecision(ctx, projectName, workDir, permReq, log); resp != nil {
+		return successResponse(req, resp)
+	}
+
 	// Check if LLM permissions checker is enabled for this project
 	// Uses config precedence: project -> global defaults -> internal defaults
 	if proj != nil && proj.GetPermissionsChecker() == "llm" {
@@ -104,6 +125,13 @@ func (s *Supervisor) handlePermissionRequest(ctx context.Context, req *daemon.Re
 		})
 	}
 
+	// With no TUI attached nobody can answer a prompt, so apply the headless policy
+	if !s.hasAttachedClients() {
+		if resp := s.headlessDecision(permReq, log); resp != nil {
+			return successResponse(req, resp)
+		}
+	}
+
 	// Create the permission request for TUI
 	permissionReq := &daemon.PermissionRequest{
 		AgentID:     permReq.AgentID,
@@ -114,19 +142,37 @@ func (s *Supervisor) handlePermissionRequest(ctx context.Context, req *daemon.Re
 		RequestedAt: time.Now(),
 	}
 
-	// Add to the permission manager and get the response channel
-	id, respCh := s.permissions.Add(permissionReq)
-	permissionReq.ID = id
-
-	// Broadcast the permission request to attached TUI clients
-	s.broadcastPermissionRequest(permissionReq)
+	// Add to the permission manager and get the response channel. A repeat
+	// of a pending request joins it and gets the same answer.
+	timeout := s.globalConfig.GetPermissionTimeout(permReq.ToolName)
+	id, respCh := s.permissions.AddWithTimeout(permissionReq, timeout)
+
+	// Broadcast the permission request, or a duplicate's updated count, to
+	// attached TUI clients
+	if pending := s.permissions.Get(id); pending != nil {
+		if pending.Duplicates > 0 {
+			log.Info("permission request collapsed into pending duplicate",
+				"id", id,
+				"tool", permReq.ToolName,
+				"duplicates", pending.Duplicates,
+			)
+		}
+		s.broadcastPermissionRequest(pending)
+	}
 
-	// Block waiting for a response from the TUI
-	resp := <-respCh
+	// Block waiting for a response from the TUI. A held request stays
+	// pending so a TUI that attaches later can still answer it.
+	var resp *daemon.PermissionResponse
+	select {
+	case resp = <-respCh:
+	case <-time.After(timeout):
+		s.permissions.Remove(id)
+	}
 	if resp == nil {
 		log.Warn("permission request timed out",
 			"id", id,
 			"tool", permReq.ToolName,
+			"timeout", timeout,
 		)
 		// Channel was closed without a response (timeout or cancellation)
 		return errorResponse(req, "permission request cancelled or timed out")
@@ -143,6 +189,108 @@ func (s *Supervisor) handlePermissionRequest(ctx context.Context, req *daemon.Re
 	return successResponse(req, resp)
 }
 
+// hasAttachedClients reports whether any client is attached to receive
+// permission prompts.
+func (s *Supervisor) hasAttachedClients() bool {
+	s.mu.RLock()
+	srv := s.server
+	s.mu.RUnlock()
+
+	return srv != nil && srv.AttachedCount() > 0
+}
+
+// headlessDecision applies the headless permission policy to a request that
+// arrived with no TUI attached. Returns nil if the request should be held
+// for a TUI to answer.
+func (s *Supervisor) headlessDecision(permReq daemon.PermissionRequestPayload, log *slog.Logger) *daemon.PermissionResponse {
+	input := logging.TruncateForLog(string(permReq.ToolInput), 200)
+
+	if s.headless.Allows(permReq.ToolName, permReq.ToolInput) {
+		log.Info("permission auto-allowed: no TUI attached",
+			"tool", permReq.ToolName,
+			"input", input,
+		)
+		return &daemon.PermissionResponse{Behavior: "allow"}
+	}
+
+	if s.headless.Fallback == rules.HeadlessDeny {
+		log.Info("permission auto-denied: no TUI attached",
+			"tool", permReq.ToolName,
+			"input", input,
+		)
+		return &daemon.PermissionResponse{
+			Behavior: "deny",
+			Message:  "No one is available to approve this operation. It is not on the headless allow list, so it was blocked.",
+		}
+	}
+
+	log.Info("permission request held until a TUI attaches",
+		"tool", permReq.ToolName,
+		"input", input,
+		"timeout", s.globalConfig.GetPermissionTimeout(permReq.ToolName),
+	)
+	return nil
+}
+
+// roleDecision denies requests the agent's role forbids. Returns nil if the
+// role allows the request (or the agent has no role), leaving it to the
+// usual rules.
+func (s *Supervisor) roleDecision(ctx context.Context, role, workDir string, permReq daemon.PermissionRequestPayload, log *slog.Logger) *daemon.PermissionResponse {
+	action, matched, err := rules.EvaluateRole(ctx, role, permReq.ToolName, permReq.ToolInput, workDir)
+	if err != nil {
+		log.Warn("failed to evaluate role rules", "role", role, "error", err)
+	}
+	if !matched || action != rules.ActionDeny {
+		return nil
+	}
+
+	log.Info("permission auto-denied by role",
+		"role", role,
+		"tool", permReq.ToolName,
+		"input", logging.TruncateForLog(string(permReq.ToolInput), 200),
+	)
+	return &daemon.PermissionResponse{
+		Behavior: "deny",
+		Message:  fmt.Sprintf("Blocked: %s agents are read-only", role),
+	}
+}
+
+// ruleDecision evaluates a request against the permission rules: the
+// project's rules first (if the project is known), then global rules, or
+// the built-in defaults when no config exists. Returns nil if no rule
+// decides it.
+func (s *Supervisor) ruleDecision(ctx context.Context, projectName, workDir string, permReq daemon.PermissionRequestPayload, log *slog.Logger) *daemon.PermissionResponse {
+	action, matched, err := s.ruleEval.Evaluate(ctx, projectName, permReq.ToolName, permReq.ToolInput, workDir)
+	if err != nil {
+		log.Warn("failed to evaluate permission rules", "error", err)
+		return nil
+	}
+	if !matched {
+		return nil
+	}
+
+	input := logging.TruncateForLog(string(permReq.ToolInput), 200)
+	switch action {
+	case rules.ActionAllow:
+		log.Info("permission auto-allowed by rule",
+			"tool", permReq.ToolName,
+			"input", input,
+		)
+		return &daemon.PermissionResponse{Behavior: "allow"}
+	case rules.ActionDeny:
+		log.Info("permission auto-denied by rule",
+			"tool", permReq.ToolName,
+			"input", input,
+		)
+		return &daemon.PermissionResponse{
+			Behavior: "deny",
+			Message:  "Blocked by a permission rule",
+		}
+	default:
+		return nil
+	}
+}
+
 // handleLLMAuth uses the LLM to authorize a permission request.
 // Returns the response if successful, nil if authorization failed and should fall back to TUI.
 func (s *Supervisor) handleLLMAuth(ctx context.Context, permReq daemon.PermissionRequestPayload, projectName, agentTask string, conversationCtx []string, log *slog.Logger) *daemon.PermissionResponse {
@@ -250,6 +398,12 @@ func (s *Supervisor) handlePermissionRespond(_ context.Context, req *daemon.Requ
 		)
 	}
 
+	// Save the decision first, so a request arriving right after the
+	// response is answered by the new rule
+	if respPayload.Remember {
+		s.rememberPermission(origReq, respPayload.Behavior)
+	}
+
 	resp := &daemon.PermissionResponse{
 		ID:        respPayload.ID,
 		Behavior:  respPayload.Behavior,
@@ -264,6 +418,39 @@ func (s *Supervisor) handlePermissionRespond(_ context.Context, req *daemon.Requ
 	return successResponse(req, nil)
 }
 
+// rememberPermission persists a TUI decision as a rule in the project's
+// permissions config. Failures are logged; the decision itself still stands.
+func (s *Supervisor) rememberPermission(permReq *daemon.PermissionRequest, behavior string) {
+	if permReq == nil || permReq.Project == "" {
+		slog.Warn("cannot remember permission decision without a project")
+		return
+	}
+
+	log := slog.With("agent", permReq.AgentID, "project", permReq.Project)
+
+	rule, err := rules.RememberRule(rules.Action(behavior), permReq.ToolName, permReq.ToolInput)
+	if err != nil {
+		log.Warn("cannot remember permission decision", "tool", permReq.ToolName, "error", err)
+		return
+	}
+	path, err := rules.ProjectConfigPath(permReq.Project)
+	if err != nil {
+		log.Warn("failed to resolve project permissions path", "error", err)
+		return
+	}
+	if err := rules.AppendRule(path, rule); err != nil {
+		log.Warn("failed to save permission rule", "path", path, "error", err)
+		return
+	}
+
+	log.Info("permission decision remembered",
+		"path", path,
+		"tool", rule.Tool,
+		"action", rule.Action,
+		"pattern", rule.Pattern,
+	)
+}
+
 // handlePermissionList returns pending permission requests.
 func (s *Supervisor) handlePermissionList(_ context.Context, req *daemon.Request) *daemon.Response {
 	var listReq daemon.PermissionListRequest
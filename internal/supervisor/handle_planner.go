@@ -11,6 +11,7 @@ import (
 	"github.com/tessro/fab/internal/agent"
 	"github.com/tessro/fab/internal/backend"
 	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/paths"
 	"github.com/tessro/fab/internal/planner"
 )
 
@@ -173,6 +174,12 @@ func (s *Supervisor) handlePlanList(_ context.Context, req *daemon.Request) *dae
 		}
 	}
 
+	switch planner.State(listReq.State) {
+	case "", planner.StateStopped, planner.StateStarting, planner.StateRunning, planner.StateStopping:
+	default:
+		return errorResponse(req, fmt.Sprintf("invalid state: %s", listReq.State))
+	}
+
 	var planners []*planner.Planner
 	if listReq.Project != "" {
 		planners = s.planners.ListByProject(listReq.Project)
@@ -183,6 +190,13 @@ func (s *Supervisor) handlePlanList(_ context.Context, req *daemon.Request) *dae
 	statuses := make([]daemon.PlannerStatus, 0, len(planners))
 	for _, p := range planners {
 		info := p.Info()
+		if listReq.State != "" && string(info.State) != listReq.State {
+			continue
+		}
+		planFile := storedPlanFile(info.ID)
+		if listReq.CompletedOnly && planFile == "" {
+			continue
+		}
 		startedAt := ""
 		if !info.StartedAt.IsZero() {
 			startedAt = info.StartedAt.Format(time.RFC3339)
@@ -195,6 +209,7 @@ func (s *Supervisor) handlePlanList(_ context.Context, req *daemon.Request) *dae
 			StartedAt:   startedAt,
 			Description: info.Description,
 			Backend:     info.Backend,
+			PlanFile:    planFile,
 		})
 	}
 
@@ -203,6 +218,19 @@ func (s *Supervisor) handlePlanList(_ context.Context, req *daemon.Request) *dae
 	})
 }
 
+// storedPlanFile returns the path of the plan a planner wrote with
+// 'fab plan write', or "" if it hasn't written one.
+func storedPlanFile(plannerID string) string {
+	path, err := paths.PlanPath(plannerID)
+	if err != nil {
+		return ""
+	}
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
 // handlePlanSendMessage sends a message to a planning agent.
 func (s *Supervisor) handlePlanSendMessage(_ context.Context, req *daemon.Request) *daemon.Response {
 	var sendReq daemon.PlanSendMessageRequest
@@ -0,0 +1,66 @@
+package supervisor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tessro/fab/internal/backend"
+	"github.com/tessro/fab/internal/daemon"
+)
+
+func TestSupervisor_HandlePlanList_Filters(t *testing.T) {
+	fabDir := t.TempDir()
+	t.Setenv("FAB_DIR", fabDir)
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	for _, id := range []string{"done1", "draft1"} {
+		if _, err := sup.planners.CreateWithID(id, "proj", t.TempDir(), "plan it", backend.NewClaudeBackend()); err != nil {
+			t.Fatalf("CreateWithID(%s) error = %v", id, err)
+		}
+	}
+	planFile := filepath.Join(fabDir, "plans", "done1.md")
+	if err := os.MkdirAll(filepath.Dir(planFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(planFile, []byte("# Plan\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	list := func(listReq daemon.PlanListRequest) *daemon.Response {
+		return sup.Handle(context.Background(), &daemon.Request{Type: daemon.MsgPlanList, ID: "test-1", Payload: listReq})
+	}
+	ids := func(resp *daemon.Response) []string {
+		t.Helper()
+		if !resp.Success {
+			t.Fatalf("expected success, got error: %s", resp.Error)
+		}
+		var got []string
+		for _, p := range resp.Payload.(daemon.PlanListResponse).Planners {
+			got = append(got, p.ID)
+		}
+		return got
+	}
+
+	resp := list(daemon.PlanListRequest{Project: "proj", CompletedOnly: true})
+	if got := ids(resp); len(got) != 1 || got[0] != "done1" {
+		t.Errorf("completed planners = %v, want [done1]", got)
+	}
+	if p := resp.Payload.(daemon.PlanListResponse).Planners[0]; p.PlanFile != planFile {
+		t.Errorf("PlanFile = %q, want %q", p.PlanFile, planFile)
+	}
+
+	if got := ids(list(daemon.PlanListRequest{State: "stopped"})); len(got) != 2 {
+		t.Errorf("stopped planners = %v, want both", got)
+	}
+	if got := ids(list(daemon.PlanListRequest{State: "running"})); len(got) != 0 {
+		t.Errorf("running planners = %v, want none", got)
+	}
+
+	if resp := list(daemon.PlanListRequest{State: "done"}); resp.Success || !strings.Contains(resp.Error, "invalid state") {
+		t.Errorf("state done: got %+v, want invalid state error", resp)
+	}
+}
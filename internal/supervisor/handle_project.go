@@ -3,10 +3,19 @@ package supervisor
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"maps"
 	"os"
 	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/tessro/fab/internal/agent"
 	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/orchestrator"
+	"github.com/tessro/fab/internal/project"
 	"github.com/tessro/fab/internal/registry"
 )
 
@@ -20,6 +29,16 @@ func (s *Supervisor) handleProjectAdd(ctx context.Context, req *daemon.Request)
 	if addReq.RemoteURL == "" {
 		return errorResponse(req, "remote URL required")
 	}
+	if addReq.Depth < 0 {
+		return errorResponse(req, "depth must be >= 0")
+	}
+
+	// Resolve and validate preset and inline config before cloning, so a
+	// typo fails fast rather than after a long clone
+	configValues, err := s.resolveProjectAddConfig(addReq)
+	if err != nil {
+		return errorResponse(req, err.Error())
+	}
 
 	// Register project in config first (validates and generates name)
 	proj, err := s.registry.Add(addReq.RemoteURL, addReq.Name, addReq.MaxAgents, addReq.Autostart, addReq.Backend)
@@ -36,13 +55,30 @@ func (s *Supervisor) handleProjectAdd(ctx context.Context, req *daemon.Request)
 
 	// Clone the repository
 	repoDir := proj.RepoDir()
-	cmd := exec.Command("git", "clone", addReq.RemoteURL, repoDir)
+	cmd := exec.Command("git", cloneArgs(addReq, repoDir)...)
 	if output, err := cmd.CombinedOutput(); err != nil {
 		_ = s.registry.Remove(proj.Name)
 		_ = os.RemoveAll(projectDir)
 		return errorResponse(req, fmt.Sprintf("failed to clone: %v\n%s", err, output))
 	}
 
+	// Record the remote's default branch so agents branch from and merge
+	// into it (repos may use master, develop, etc.)
+	if branch := project.DetectDefaultBranch(repoDir); branch != "" {
+		if err := s.registry.SetConfigValue(proj.Name, registry.ConfigKeyDefaultBranch, branch); err != nil {
+			slog.Warn("failed to save default branch", "project", proj.Name, "branch", branch, "error", err)
+		}
+	}
+
+	// Apply after detection so a preset's default-branch wins
+	for _, key := range slices.Sorted(maps.Keys(configValues)) {
+		if err := s.setProjectConfigValue(proj.Name, registry.ConfigKey(key), configValues[key]); err != nil {
+			_ = s.registry.Remove(proj.Name)
+			_ = os.RemoveAll(projectDir)
+			return errorResponse(req, fmt.Sprintf("failed to apply %s: %v", key, err))
+		}
+	}
+
 	// Worktrees are created on-demand when agents start
 
 	return successResponse(req, daemon.ProjectAddResponse{
@@ -50,10 +86,85 @@ func (s *Supervisor) handleProjectAdd(ctx context.Context, req *daemon.Request)
 		RemoteURL: proj.RemoteURL,
 		RepoDir:   proj.RepoDir(),
 		MaxAgents: proj.MaxAgents,
+		Config:    configValues,
 	})
 }
 
-// handleProjectRemove removes a project.
+// cloneArgs returns the git arguments for cloning a project.add request's
+// remote into repoDir.
+func cloneArgs(addReq daemon.ProjectAddRequest, repoDir string) []string {
+	args := []string{"clone"}
+	if addReq.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(addReq.Depth))
+		// --depth implies --single-branch; keep the other branches unless asked
+		if !addReq.SingleBranch {
+			args = append(args, "--no-single-branch")
+		}
+	}
+	if addReq.SingleBranch {
+		args = append(args, "--single-branch")
+	}
+	return append(args, addReq.RemoteURL, repoDir)
+}
+
+// resolveProjectAddConfig merges a project.add request's preset with its
+// inline config (which takes precedence) and validates every key and value.
+func (s *Supervisor) resolveProjectAddConfig(addReq daemon.ProjectAddRequest) (map[string]string, error) {
+	values := make(map[string]string)
+	if addReq.Preset != "" {
+		preset, ok := s.globalConfig.GetPreset(addReq.Preset)
+		if !ok {
+			return nil, fmt.Errorf("unknown preset: %s", addReq.Preset)
+		}
+		maps.Copy(values, preset)
+	}
+	maps.Copy(values, addReq.Config)
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if !registry.IsValidConfigKey(key) {
+			return nil, fmt.Errorf("invalid config key: %s", key)
+		}
+		if err := registry.ValidateConfigValue(registry.ConfigKey(key), values[key]); err != nil {
+			return nil, err
+		}
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return values, nil
+}
+
+// setProjectConfigValue sets a project config value and applies its side
+// effects.
+func (s *Supervisor) setProjectConfigValue(name string, key registry.ConfigKey, value string) error {
+	if err := s.registry.SetConfigValue(name, key, value); err != nil {
+		return err
+	}
+
+	change := string(key) + "=" + value
+	if registry.IsSecretConfigKey(key) {
+		change = string(key) + " changed"
+	}
+	s.recordTimeline(name, project.TimelineEvent{Type: project.TimelineConfig, Detail: change})
+
+	// Keep origin/HEAD in sync so 'fab agent done' in worktrees rebases onto the same base
+	if key == registry.ConfigKeyDefaultBranch {
+		if proj, err := s.registry.Get(name); err == nil {
+			if err := proj.SetRemoteHead(); err != nil {
+				slog.Warn("failed to update origin/HEAD", "project", name, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+// projectRemoveDrainTimeout bounds how long a forced project.remove waits
+// for the project's agents to exit and their in-flight merges to finish.
+const projectRemoveDrainTimeout = 30 * time.Second
+
+// handleProjectRemove removes a project. A running project is refused
+// unless forced, since tearing it down under an agent that is merging can
+// leave the repository half-merged.
 func (s *Supervisor) handleProjectRemove(ctx context.Context, req *daemon.Request) *daemon.Response {
 	var removeReq daemon.ProjectRemoveRequest
 	if err := unmarshalPayload(req.Payload, &removeReq); err != nil {
@@ -64,6 +175,18 @@ func (s *Supervisor) handleProjectRemove(ctx context.Context, req *daemon.Reques
 		return errorResponse(req, "project name required")
 	}
 
+	if orch := s.getOrchestrator(removeReq.Name); orch != nil {
+		if !removeReq.Force {
+			if n := orch.DoneInFlight(); n > 0 {
+				return errorResponse(req, fmt.Sprintf("project %s has %d agent(s) merging; wait for them to finish or use force", removeReq.Name, n))
+			}
+			return errorResponse(req, fmt.Sprintf("project %s is running; stop it first or use force", removeReq.Name))
+		}
+		if err := s.drainProject(removeReq.Name, orch, projectRemoveDrainTimeout); err != nil {
+			return errorResponse(req, err.Error())
+		}
+	}
+
 	// Stop all agents first
 	s.agents.DeleteAll(removeReq.Name)
 	s.agents.UnregisterProject(removeReq.Name)
@@ -83,6 +206,21 @@ func (s *Supervisor) handleProjectRemove(ctx context.Context, req *daemon.Reques
 	return successResponse(req, nil)
 }
 
+// drainProject stops a project's orchestration so it can be torn down:
+// spawning stops, agents are asked to exit, and in-flight merges get until
+// timeout to finish. The orchestrator is unregistered before waiting, so no
+// new merge can start.
+func (s *Supervisor) drainProject(name string, orch *orchestrator.Orchestrator, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	orch.Stop()
+	s.drainAgents([]string{name}, timeout/2)
+	s.stopOrchestrator(name)
+	if !orch.WaitDone(time.Until(deadline)) {
+		return fmt.Errorf("project %s stopped, but %d merge(s) are still running; try again once they finish", name, orch.DoneInFlight())
+	}
+	return nil
+}
+
 // handleProjectList lists all projects.
 func (s *Supervisor) handleProjectList(ctx context.Context, req *daemon.Request) *daemon.Response {
 	projects := s.registry.List()
@@ -119,6 +257,12 @@ func (s *Supervisor) handleProjectSet(ctx context.Context, req *daemon.Request)
 	if err := s.registry.Update(setReq.Name, setReq.MaxAgents, setReq.Autostart); err != nil {
 		return errorResponse(req, fmt.Sprintf("failed to update project: %v", err))
 	}
+	if setReq.MaxAgents != nil {
+		s.recordTimeline(setReq.Name, project.TimelineEvent{Type: project.TimelineConfig, Detail: fmt.Sprintf("%s=%d", registry.ConfigKeyMaxAgents, *setReq.MaxAgents)})
+	}
+	if setReq.Autostart != nil {
+		s.recordTimeline(setReq.Name, project.TimelineEvent{Type: project.TimelineConfig, Detail: fmt.Sprintf("%s=%t", registry.ConfigKeyAutostart, *setReq.Autostart)})
+	}
 
 	// No need to resize worktree pool - worktrees are created/deleted on-demand
 
@@ -136,15 +280,51 @@ func (s *Supervisor) handleProjectConfigShow(ctx context.Context, req *daemon.Re
 		return errorResponse(req, "project name required")
 	}
 
-	config, err := s.registry.GetConfig(showReq.Name)
+	entries, err := s.registry.ConfigEntries(showReq.Name)
 	if err != nil {
 		return errorResponse(req, fmt.Sprintf("failed to get config: %v", err))
 	}
 
-	return successResponse(req, daemon.ProjectConfigShowResponse{
-		Name:   showReq.Name,
-		Config: config,
-	})
+	resp := daemon.ProjectConfigShowResponse{
+		Name:    showReq.Name,
+		Config:  make(map[string]any, len(entries)),
+		Entries: make([]daemon.ProjectConfigEntry, len(entries)),
+	}
+	for i, e := range entries {
+		entry := daemon.ProjectConfigEntry{
+			Key:     string(e.Key),
+			Type:    configValueType(e.Value),
+			Value:   e.Value,
+			Default: e.Default,
+			Set:     e.Set,
+		}
+		if e.Secret {
+			entry.Type = daemon.ConfigTypeString
+			entry.Value, entry.Default = "", ""
+			if e.Set {
+				entry.Value = agent.RedactedText
+			}
+			entry.Redacted = true
+		}
+		resp.Entries[i] = entry
+		resp.Config[entry.Key] = entry.Value
+	}
+	return successResponse(req, resp)
+}
+
+// configValueType names the type of a project config value for
+// ProjectConfigEntry.Type.
+func configValueType(v any) string {
+	switch v.(type) {
+	case int:
+		return daemon.ConfigTypeInt
+	case bool:
+		return daemon.ConfigTypeBool
+	case []string:
+		return daemon.ConfigTypeList
+	default:
+		return daemon.ConfigTypeString
+	}
 }
 
 // handleProjectConfigGet returns a single config value for a project.
@@ -192,12 +372,122 @@ func (s *Supervisor) handleProjectConfigSet(ctx context.Context, req *daemon.Req
 	}
 
 	if !registry.IsValidConfigKey(setReq.Key) {
-		return errorResponse(req, fmt.Sprintf("invalid config key: %s (valid keys: max-agents, autostart, issue-backend, permissions-checker, agent-backend)", setReq.Key))
+		keys := make([]string, 0, len(registry.ValidConfigKeys()))
+		for _, k := range registry.ValidConfigKeys() {
+			keys = append(keys, string(k))
+		}
+		return errorResponse(req, fmt.Sprintf("invalid config key: %s (valid keys: %s)", setReq.Key, strings.Join(keys, ", ")))
+	}
+
+	// Reject bad values now rather than when the project is next started
+	if err := registry.ValidateConfigValue(registry.ConfigKey(setReq.Key), setReq.Value); err != nil {
+		return errorResponse(req, err.Error())
 	}
 
-	if err := s.registry.SetConfigValue(setReq.Name, registry.ConfigKey(setReq.Key), setReq.Value); err != nil {
+	if err := s.setProjectConfigValue(setReq.Name, registry.ConfigKey(setReq.Key), setReq.Value); err != nil {
 		return errorResponse(req, fmt.Sprintf("failed to set config value: %v", err))
 	}
 
 	return successResponse(req, nil)
 }
+
+// handleProjectPull fetches origin and fast-forwards the default branch in
+// the project's main clone, so new agent worktrees start from current code.
+func (s *Supervisor) handleProjectPull(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var pullReq daemon.ProjectPullRequest
+	if err := unmarshalPayload(req.Payload, &pullReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	if pullReq.Name == "" {
+		return errorResponse(req, "project name required")
+	}
+
+	proj, err := s.registry.Get(pullReq.Name)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("project not found: %s", pullReq.Name))
+	}
+
+	result, err := proj.Pull()
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("failed to pull: %v", err))
+	}
+
+	if result.LocalChanges {
+		slog.Warn("project clone has local changes, skipped fast-forward",
+			"project", proj.Name,
+			"branch", result.Branch,
+			"repo", proj.RepoDir(),
+		)
+	} else {
+		slog.Info("project pulled",
+			"project", proj.Name,
+			"branch", result.Branch,
+			"sha", result.SHA,
+			"updated", result.Updated,
+		)
+	}
+
+	return successResponse(req, daemon.ProjectPullResponse{
+		Name:         proj.Name,
+		Branch:       result.Branch,
+		PreviousSHA:  result.PreviousSHA,
+		SHA:          result.SHA,
+		Updated:      result.Updated,
+		LocalChanges: result.LocalChanges,
+	})
+}
+
+// Bounds for project.log responses.
+const (
+	defaultProjectLogLimit = 20
+	maxProjectLogLimit     = 1000
+)
+
+// handleProjectLog returns recent commits on a project's default branch,
+// marking the ones fab agents merged, for context when reviewing agent work.
+func (s *Supervisor) handleProjectLog(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var logReq daemon.ProjectLogRequest
+	if err := unmarshalPayload(req.Payload, &logReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	if logReq.Name == "" {
+		return errorResponse(req, "project name required")
+	}
+
+	proj, err := s.registry.Get(logReq.Name)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("project not found: %s", logReq.Name))
+	}
+
+	limit := logReq.Limit
+	if limit <= 0 {
+		limit = defaultProjectLogLimit
+	}
+	if !logReq.Since.IsZero() && !logReq.Until.IsZero() && logReq.Until.Before(logReq.Since) {
+		return errorResponse(req, "until must not be before since")
+	}
+	commits, err := proj.Log(min(limit, maxProjectLogLimit), logReq.Since, logReq.Until, logReq.Fetch)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("failed to read log: %v", err))
+	}
+
+	resp := daemon.ProjectLogResponse{
+		Name:    proj.Name,
+		Branch:  proj.GetDefaultBranch(),
+		Commits: make([]daemon.ProjectLogCommit, len(commits)),
+	}
+	for i, c := range commits {
+		resp.Commits[i] = daemon.ProjectLogCommit{
+			SHA:     c.SHA,
+			Author:  c.Author,
+			Time:    c.Time,
+			Subject: c.Subject,
+			Fab:     c.AgentID != "",
+			AgentID: c.AgentID,
+			IssueID: c.IssueID,
+		}
+	}
+	return successResponse(req, resp)
+}
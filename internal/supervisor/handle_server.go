@@ -2,9 +2,19 @@ package supervisor
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/user"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/tessro/fab/internal/config"
 	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/logging"
+	"github.com/tessro/fab/internal/paths"
+	"github.com/tessro/fab/internal/project"
 )
 
 // handlePing responds to ping requests.
@@ -17,6 +27,67 @@ func (s *Supervisor) handlePing(ctx context.Context, req *daemon.Request) *daemo
 	})
 }
 
+// handleWhoami reports the identity and environment the daemon runs with,
+// for diagnosing differences from the shell running the CLI.
+func (s *Supervisor) handleWhoami(ctx context.Context, req *daemon.Request) *daemon.Response {
+	resp := daemon.WhoamiResponse{
+		UID:        os.Getuid(),
+		PID:        os.Getpid(),
+		Version:    Version,
+		SocketPath: paths.SocketPath(),
+	}
+	if u, err := user.Current(); err == nil {
+		resp.User = u.Username
+	} else {
+		resp.User = os.Getenv("USER")
+	}
+	resp.Home, _ = os.UserHomeDir()
+	resp.ConfigPath, _ = config.GlobalConfigPath()
+
+	s.mu.RLock()
+	if s.server != nil {
+		resp.SocketPath = s.server.SocketPath()
+	}
+	s.mu.RUnlock()
+
+	projects := s.registry.List()
+	slices.SortFunc(projects, func(a, b *project.Project) int { return strings.Compare(a.Name, b.Name) })
+	for _, proj := range projects {
+		resp.IssueAuth = append(resp.IssueAuth, s.issueAuthStatus(proj))
+	}
+
+	return successResponse(req, resp)
+}
+
+// handleSetLogLevel changes the daemon's log level, so debug logs can be
+// turned on while reproducing a problem without restarting agents.
+func (s *Supervisor) handleSetLogLevel(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var levelReq daemon.SetLogLevelRequest
+	if err := unmarshalPayload(req.Payload, &levelReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	prev := logging.Level()
+	resp := daemon.SetLogLevelResponse{
+		Level:    logging.LevelName(prev),
+		Previous: logging.LevelName(prev),
+	}
+	if levelReq.Level == "" {
+		return successResponse(req, resp)
+	}
+
+	level, ok := logging.LookupLevel(levelReq.Level)
+	if !ok {
+		return errorResponse(req, fmt.Sprintf("invalid log level %q: must be debug, info, warn, or error", levelReq.Level))
+	}
+	logging.SetLevel(level)
+	resp.Level = logging.LevelName(level)
+
+	// Logged at warn so the change shows up at any level
+	slog.Warn("log level changed", "from", resp.Previous, "to", resp.Level)
+	return successResponse(req, resp)
+}
+
 // handleShutdown initiates daemon shutdown.
 func (s *Supervisor) handleShutdown(ctx context.Context, req *daemon.Request) *daemon.Response {
 	// Parse the shutdown request to get stopHost flag
@@ -26,18 +97,22 @@ func (s *Supervisor) handleShutdown(ctx context.Context, req *daemon.Request) *d
 		shutdownReq = daemon.ShutdownRequest{}
 	}
 
+	s.requestShutdown(shutdownReq.StopHost)
+	return successResponse(req, nil)
+}
+
+// requestShutdown signals the daemon to shut down. stopHost also stops the
+// agent host. Later requests while shutdown is underway are ignored.
+func (s *Supervisor) requestShutdown(stopHost bool) {
 	s.shutdownMu.Lock()
 	defer s.shutdownMu.Unlock()
 
-	// Store the stopHost flag for use during shutdown
-	s.stopHost = shutdownReq.StopHost
-
 	select {
 	case <-s.shutdownCh:
 		// Already shutting down
 	default:
+		// Store the stopHost flag for use during shutdown
+		s.stopHost = stopHost
 		close(s.shutdownCh)
 	}
-
-	return successResponse(req, nil)
 }
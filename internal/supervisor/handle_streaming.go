@@ -2,12 +2,17 @@ package supervisor
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/tessro/fab/internal/agent"
 	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/logging"
+	"github.com/tessro/fab/internal/notify"
+	"github.com/tessro/fab/internal/orchestrator"
 	"github.com/tessro/fab/internal/planner"
+	"github.com/tessro/fab/internal/project"
 )
 
 // handleAttach subscribes a client to streaming events.
@@ -45,12 +50,52 @@ func (s *Supervisor) handleDetach(ctx context.Context, req *daemon.Request) *dae
 	return successResponse(req, nil)
 }
 
+// handleLogSubscribe streams daemon log records to the client.
+func (s *Supervisor) handleLogSubscribe(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var logReq daemon.LogSubscribeRequest
+	if req.Payload != nil {
+		if err := unmarshalPayload(req.Payload, &logReq); err != nil {
+			return errorResponse(req, "invalid payload: "+err.Error())
+		}
+	}
+
+	conn := daemon.ConnFromContext(ctx)
+	srv := daemon.ServerFromContext(ctx)
+	encoder := daemon.EncoderFromContext(ctx)
+	writeMu := daemon.WriteMuFromContext(ctx)
+
+	if conn == nil || srv == nil || encoder == nil || writeMu == nil {
+		return errorResponse(req, "internal error: missing connection context")
+	}
+
+	srv.SubscribeLogs(conn, logging.ParseLevel(logReq.Level), encoder, writeMu)
+	return successResponse(req, nil)
+}
+
 // SetServer sets the daemon server for broadcasting events.
 // This must be called before agents are created.
 func (s *Supervisor) SetServer(srv *daemon.Server) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.server = srv
+	if srv != nil {
+		srv.SetReplaySize(s.globalConfig.GetEventReplaySize())
+		srv.SetReplayFilter(s.shouldReplay)
+	}
+}
+
+// shouldReplay reports whether a buffered event is still worth replaying to
+// a client that attaches now. Permission requests and user questions are
+// only replayed while they are waiting for an answer.
+func (s *Supervisor) shouldReplay(event *daemon.StreamEvent) bool {
+	switch {
+	case event.PermissionRequest != nil:
+		return s.permissions.Get(event.PermissionRequest.ID) != nil
+	case event.UserQuestion != nil:
+		return s.questions.Get(event.UserQuestion.ID) != nil
+	default:
+		return true
+	}
 }
 
 // Server returns the daemon server, or nil if not set.
@@ -60,8 +105,19 @@ func (s *Supervisor) Server() *daemon.Server {
 	return s.server
 }
 
-// handleAgentEvent broadcasts agent events to attached clients.
+// handleAgentEvent broadcasts agent events to attached clients and records
+// spawns in the project's activity timeline.
 func (s *Supervisor) handleAgentEvent(event agent.Event) {
+	if event.Type == agent.EventCreated {
+		info := event.Agent.Info()
+		s.recordTimeline(info.Project, project.TimelineEvent{
+			Type:    project.TimelineSpawn,
+			AgentID: info.ID,
+			IssueID: info.Task,
+			Detail:  info.SpawnedBy,
+		})
+	}
+
 	s.mu.RLock()
 	srv := s.server
 	s.mu.RUnlock()
@@ -79,7 +135,10 @@ func (s *Supervisor) handleAgentEvent(event agent.Event) {
 			Type:      "created",
 			AgentID:   info.ID,
 			Project:   info.Project,
+			Name:      info.Name,
+			SpawnedBy: info.SpawnedBy,
 			StartedAt: info.StartedAt.Format(time.RFC3339),
+			Model:     info.Model,
 		}
 	case agent.EventStateChanged:
 		info := event.Agent.Info()
@@ -95,8 +154,12 @@ func (s *Supervisor) handleAgentEvent(event agent.Event) {
 			Type:        "info",
 			AgentID:     info.ID,
 			Project:     info.Project,
+			Name:        info.Name,
 			Task:        info.Task,
 			Description: info.Description,
+			Epic:        info.Epic,
+			CurrentFile: info.CurrentFile,
+			Model:       info.Model,
 		}
 	case agent.EventDeleted:
 		info := event.Agent.Info()
@@ -139,6 +202,7 @@ func (s *Supervisor) broadcastChatEntry(agentID, project string, entry agent.Cha
 		ToolResult: entry.ToolResult,
 		IsError:    entry.IsError,
 		Timestamp:  entry.Timestamp.Format(time.RFC3339),
+		Seq:        entry.Seq,
 	}
 	srv.Broadcast(&daemon.StreamEvent{
 		Type:      "chat_entry",
@@ -148,6 +212,65 @@ func (s *Supervisor) broadcastChatEntry(agentID, project string, entry agent.Cha
 	})
 }
 
+// broadcastChatDelta sends a chunk of streaming assistant text to attached
+// clients. It extends the in-progress assistant entry until the complete
+// entry arrives as a chat_entry event.
+func (s *Supervisor) broadcastChatDelta(agentID, project, text string) {
+	s.mu.RLock()
+	srv := s.server
+	s.mu.RUnlock()
+
+	if srv == nil {
+		return
+	}
+
+	srv.Broadcast(&daemon.StreamEvent{
+		Type:    "chat_delta",
+		AgentID: agentID,
+		Project: project,
+		Data:    text,
+	})
+}
+
+// broadcastParseError tells attached TUI clients that an agent emitted a line
+// that isn't valid stream-json. The line is skipped by the read loop.
+func (s *Supervisor) broadcastParseError(agentID, project string, line []byte, err error) {
+	s.mu.RLock()
+	srv := s.server
+	s.mu.RUnlock()
+
+	if srv == nil {
+		return
+	}
+
+	srv.Broadcast(&daemon.StreamEvent{
+		Type:    "parse_error",
+		AgentID: agentID,
+		Project: project,
+		Data:    logging.TruncateForLog(string(line), 200),
+		Error:   err.Error(),
+	})
+}
+
+// broadcastDecision sends a dry-run orchestrator decision to attached TUI clients.
+func (s *Supervisor) broadcastDecision(d orchestrator.Decision) {
+	s.mu.RLock()
+	srv := s.server
+	s.mu.RUnlock()
+
+	if srv == nil {
+		return
+	}
+
+	srv.Broadcast(&daemon.StreamEvent{
+		Type:        d.Type,
+		AgentID:     d.AgentID,
+		Project:     d.Project,
+		Task:        d.IssueID,
+		Description: d.Reason,
+	})
+}
+
 // broadcastInterventionState sends an intervention state change to attached TUI clients.
 func (s *Supervisor) broadcastInterventionState(agentID, project string, intervening bool) {
 	s.mu.RLock()
@@ -179,16 +302,39 @@ func (s *Supervisor) StartAgentReadLoop(a *agent.Agent) error {
 			s.heartbeat.RecordOutput(info.ID)
 		}
 	}
+	cfg.OnDelta = func(text string) {
+		s.broadcastChatDelta(info.ID, info.Project, text)
+		if s.heartbeat != nil {
+			s.heartbeat.RecordOutput(info.ID)
+		}
+	}
+	cfg.OnParseError = func(line []byte, err error) {
+		s.broadcastParseError(info.ID, info.Project, line, err)
+	}
+	exited := make(chan struct{})
+	s.mu.Lock()
+	s.agentExits[info.ID] = exited
+	s.mu.Unlock()
+
 	cfg.OnExit = func(exitErr error) {
+		// Acknowledge the exit for a shutdown waiting on this agent
+		s.mu.Lock()
+		if s.agentExits[info.ID] == exited {
+			delete(s.agentExits, info.ID)
+		}
+		s.mu.Unlock()
+		close(exited)
+
 		// Remove from heartbeat monitoring
 		if s.heartbeat != nil {
 			s.heartbeat.RemoveAgent(info.ID)
 		}
-		// Release claims when agent crashes (non-nil exitErr means crash)
+		// Count a failure against the agent's issues and release its claims
+		// when it crashes (non-nil exitErr means crash)
 		if exitErr != nil {
 			orch := s.getOrchestrator(info.Project)
 			if orch != nil {
-				released := orch.Claims().ReleaseByAgent(info.ID)
+				released := orch.HandleAgentCrash(info.ID, exitErr)
 				if released > 0 {
 					slog.Info("released claims for crashed agent",
 						"agent", info.ID,
@@ -206,6 +352,17 @@ func (s *Supervisor) StartAgentReadLoop(a *agent.Agent) error {
 
 // broadcastPermissionRequest sends a permission request to attached TUI clients.
 func (s *Supervisor) broadcastPermissionRequest(req *daemon.PermissionRequest) {
+	// A collapsed duplicate only updates the count; the user was already notified
+	if req.Duplicates == 0 {
+		s.notifier.Send(notify.Event{
+			Type:    notify.EventPermissionRequest,
+			Project: req.Project,
+			AgentID: req.AgentID,
+			Title:   "Permission needed",
+			Message: fmt.Sprintf("Agent %s wants to use %s", req.AgentID, req.ToolName),
+		})
+	}
+
 	s.mu.RLock()
 	srv := s.server
 	s.mu.RUnlock()
@@ -224,6 +381,18 @@ func (s *Supervisor) broadcastPermissionRequest(req *daemon.PermissionRequest) {
 
 // broadcastUserQuestion sends a user question to attached TUI clients.
 func (s *Supervisor) broadcastUserQuestion(question *daemon.UserQuestion) {
+	message := fmt.Sprintf("Agent %s has a question", question.AgentID)
+	if len(question.Questions) > 0 {
+		message = fmt.Sprintf("Agent %s asks: %s", question.AgentID, question.Questions[0].Question)
+	}
+	s.notifier.Send(notify.Event{
+		Type:    notify.EventUserQuestion,
+		Project: question.Project,
+		AgentID: question.AgentID,
+		Title:   "Question from agent",
+		Message: message,
+	})
+
 	s.mu.RLock()
 	srv := s.server
 	s.mu.RUnlock()
@@ -266,6 +435,25 @@ func (s *Supervisor) broadcastManagerChatEntry(projectName string, entry agent.C
 	})
 }
 
+// broadcastPlanComplete tells attached clients that a planner finished via
+// 'fab agent done'. errMsg is the error the planner reported, if any.
+func (s *Supervisor) broadcastPlanComplete(plannerID, project, errMsg string) {
+	s.mu.RLock()
+	srv := s.server
+	s.mu.RUnlock()
+
+	if srv == nil {
+		return
+	}
+
+	srv.Broadcast(&daemon.StreamEvent{
+		Type:    "plan_complete",
+		AgentID: plannerID,
+		Project: project,
+		Error:   errMsg,
+	})
+}
+
 // handlePlannerEvent broadcasts planner events to attached clients.
 func (s *Supervisor) handlePlannerEvent(event planner.Event) {
 	s.mu.RLock()
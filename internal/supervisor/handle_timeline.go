@@ -0,0 +1,70 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/project"
+)
+
+// defaultTimelineLimit is how many of the newest timeline events a timeline
+// request returns when it doesn't set a limit.
+const defaultTimelineLimit = 1000
+
+// handleTimeline returns a project's activity timeline.
+func (s *Supervisor) handleTimeline(_ context.Context, req *daemon.Request) *daemon.Response {
+	var timelineReq daemon.TimelineRequest
+	if err := unmarshalPayload(req.Payload, &timelineReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	if timelineReq.Project == "" {
+		return errorResponse(req, "project name required")
+	}
+	proj, err := s.registry.Get(timelineReq.Project)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("project not found: %s", timelineReq.Project))
+	}
+
+	limit := timelineReq.Limit
+	if limit <= 0 {
+		limit = defaultTimelineLimit
+	}
+	events, truncated, err := proj.ReadTimeline(timelineReq.Since, limit)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("failed to read timeline: %v", err))
+	}
+
+	resp := daemon.TimelineResponse{
+		Project:   proj.Name,
+		Path:      proj.TimelinePath(),
+		Events:    make([]daemon.TimelineEvent, len(events)),
+		Truncated: truncated,
+	}
+	for i, ev := range events {
+		resp.Events[i] = daemon.TimelineEvent{
+			Time:    ev.Time,
+			Type:    ev.Type,
+			AgentID: ev.AgentID,
+			IssueID: ev.IssueID,
+			SHA:     ev.SHA,
+			Detail:  ev.Detail,
+		}
+	}
+	return successResponse(req, resp)
+}
+
+// recordTimeline appends an event to a project's activity timeline. The
+// timeline is an audit trail, so failures are logged rather than failing
+// the operation being recorded.
+func (s *Supervisor) recordTimeline(projectName string, ev project.TimelineEvent) {
+	proj, err := s.registry.Get(projectName)
+	if err != nil {
+		return
+	}
+	if err := proj.AppendTimeline(ev); err != nil {
+		slog.Warn("failed to record timeline event", "project", projectName, "type", ev.Type, "error", err)
+	}
+}
@@ -0,0 +1,73 @@
+package supervisor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/project"
+)
+
+func TestSupervisor_HandleTimeline(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	if _, err := sup.registry.Add("git@github.com:user/proj.git", "proj", 1, false, ""); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	start := time.Now().UTC().Add(-time.Hour)
+	sup.recordTimeline("proj", project.TimelineEvent{Time: start, Type: project.TimelineClaim, AgentID: "a1", IssueID: "42"})
+	sup.recordTimeline("missing", project.TimelineEvent{Type: project.TimelineClaim}) // Ignored
+
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgProjectConfigSet,
+		ID:      "test-1",
+		Payload: daemon.ProjectConfigSetRequest{Name: "proj", Key: "max-agents", Value: "3"},
+	})
+	if !resp.Success {
+		t.Fatalf("config set: expected success, got error: %s", resp.Error)
+	}
+
+	timeline := func(payload daemon.TimelineRequest) *daemon.Response {
+		return sup.Handle(context.Background(), &daemon.Request{Type: daemon.MsgTimeline, ID: "test-2", Payload: payload})
+	}
+
+	resp = timeline(daemon.TimelineRequest{Project: "proj"})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	payload, ok := resp.Payload.(daemon.TimelineResponse)
+	if !ok {
+		t.Fatalf("expected TimelineResponse payload, got %T", resp.Payload)
+	}
+	if len(payload.Events) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(payload.Events), payload.Events)
+	}
+	if ev := payload.Events[0]; ev.Type != project.TimelineClaim || ev.IssueID != "42" {
+		t.Errorf("Events[0] = %+v, want the claim of 42", ev)
+	}
+	if ev := payload.Events[1]; ev.Type != project.TimelineConfig || ev.Detail != "max-agents=3" {
+		t.Errorf("Events[1] = %+v, want the max-agents change", ev)
+	}
+
+	resp = timeline(daemon.TimelineRequest{Project: "proj", Since: start.Add(time.Minute)})
+	if payload := resp.Payload.(daemon.TimelineResponse); len(payload.Events) != 1 {
+		t.Errorf("since: got %+v, want only the config change", payload.Events)
+	}
+
+	for _, tt := range []struct {
+		req     daemon.TimelineRequest
+		wantErr string
+	}{
+		{daemon.TimelineRequest{}, "project name required"},
+		{daemon.TimelineRequest{Project: "missing"}, "project not found"},
+	} {
+		resp := timeline(tt.req)
+		if resp.Success || !strings.Contains(resp.Error, tt.wantErr) {
+			t.Errorf("timeline(%+v) = %+v, want error containing %q", tt.req, resp, tt.wantErr)
+		}
+	}
+}
@@ -172,6 +172,19 @@ func (h *HeartbeatMonitor) RecordOutput(agentID string) {
 	tracker.state = HeartbeatNormal
 }
 
+// LastOutput returns when an agent last produced output. For an agent that
+// has been silent since monitoring began, that is when monitoring began.
+// Returns false if the agent isn't tracked.
+func (h *HeartbeatMonitor) LastOutput(agentID string) (time.Time, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	tracker, ok := h.trackers[agentID]
+	if !ok || tracker.lastOutputTime.IsZero() {
+		return time.Time{}, false
+	}
+	return tracker.lastOutputTime, true
+}
+
 // RemoveAgent removes tracking for an agent (e.g., when it's deleted).
 func (h *HeartbeatMonitor) RemoveAgent(agentID string) {
 	h.mu.Lock()
@@ -0,0 +1,89 @@
+package supervisor
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/tessro/fab/internal/logging"
+)
+
+// idleCheckInterval returns how often to check whether the daemon has been
+// idle for idle: a tenth of it, between a second and a minute.
+func idleCheckInterval(idle time.Duration) time.Duration {
+	return min(max(idle/10, time.Second), time.Minute)
+}
+
+// StartIdleShutdown shuts the daemon down once it has been idle for the
+// configured auto-shutdown-idle duration. Idle means no project is running,
+// no agents, planners, managers, or director exist, no client is attached,
+// and no request has arrived. Does nothing if auto-shutdown is not configured.
+func (s *Supervisor) StartIdleShutdown() {
+	idle := s.globalConfig.GetAutoShutdownIdle()
+	if idle <= 0 {
+		return
+	}
+	slog.Info("idle auto-shutdown enabled", "after", idle)
+	go s.runIdleShutdown(idle, idleCheckInterval(idle))
+}
+
+// runIdleShutdown checks for idleness every interval until shutdown.
+func (s *Supervisor) runIdleShutdown(idle, interval time.Duration) {
+	defer logging.LogPanic("idle-shutdown", nil)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdownCh:
+			return
+		case now := <-ticker.C:
+			if s.idleShutdownDue(now, idle) {
+				slog.Info("daemon idle, shutting down", "idle", idle)
+				s.requestShutdown(false)
+				return
+			}
+		}
+	}
+}
+
+// touchActivity records activity, restarting the idle countdown.
+func (s *Supervisor) touchActivity() {
+	s.lastActivity.Store(time.Now().UnixNano())
+}
+
+// idleShutdownDue reports whether the daemon has been idle for at least
+// idle as of now. While anything keeps it busy, the countdown is reset.
+func (s *Supervisor) idleShutdownDue(now time.Time, idle time.Duration) bool {
+	if s.busy() {
+		s.lastActivity.Store(now.UnixNano())
+		return false
+	}
+	return now.Sub(time.Unix(0, s.lastActivity.Load())) >= idle
+}
+
+// busy reports whether anything is running or attached that should keep
+// the daemon alive.
+func (s *Supervisor) busy() bool {
+	for _, p := range s.registry.List() {
+		if p.IsRunning() {
+			return true
+		}
+	}
+	if len(s.agents.List("")) > 0 || s.planners.Count() > 0 {
+		return true
+	}
+
+	s.mu.RLock()
+	srv := s.server
+	running := s.director != nil && s.director.IsRunning()
+	for _, mgr := range s.managers {
+		running = running || mgr.IsRunning()
+	}
+	s.mu.RUnlock()
+	if running {
+		return true
+	}
+
+	return srv != nil && (srv.AttachedCount() > 0 || srv.SinkCount() > 0)
+}
@@ -0,0 +1,83 @@
+package supervisor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/project"
+)
+
+func TestSupervisor_IdleShutdownDue(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	idle := time.Minute
+	start := time.Unix(0, sup.lastActivity.Load())
+
+	if sup.idleShutdownDue(start.Add(idle/2), idle) {
+		t.Error("shutdown due before the idle period elapsed")
+	}
+	if !sup.idleShutdownDue(start.Add(idle), idle) {
+		t.Error("shutdown not due after the idle period")
+	}
+
+	// An agent keeps the daemon busy and restarts the countdown
+	sup.agents.RegisterProject(&project.Project{Name: "proj"})
+	if _, err := sup.agents.Hydrate(agent.HydrateInfo{
+		ID:       "idle01",
+		Project:  "proj",
+		State:    agent.StateIdle,
+		Worktree: t.TempDir(),
+		Backend:  "claude",
+	}); err != nil {
+		t.Fatalf("Hydrate() error = %v", err)
+	}
+	busyAt := start.Add(2 * idle)
+	if sup.idleShutdownDue(busyAt, idle) {
+		t.Error("shutdown due while an agent exists")
+	}
+
+	if err := sup.agents.Delete("idle01"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if sup.idleShutdownDue(busyAt.Add(idle/2), idle) {
+		t.Error("shutdown due before the idle period elapsed since the agent went away")
+	}
+	if !sup.idleShutdownDue(busyAt.Add(idle), idle) {
+		t.Error("shutdown not due after the idle period since the agent went away")
+	}
+}
+
+func TestSupervisor_RunIdleShutdown(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	sup.lastActivity.Store(time.Now().Add(-time.Hour).UnixNano())
+	go sup.runIdleShutdown(time.Minute, 10*time.Millisecond)
+
+	select {
+	case <-sup.ShutdownCh():
+	case <-time.After(5 * time.Second):
+		t.Fatal("idle daemon did not shut down")
+	}
+	if sup.StopHost() {
+		t.Error("idle shutdown should preserve the agent host")
+	}
+}
+
+func TestIdleCheckInterval(t *testing.T) {
+	tests := []struct {
+		idle time.Duration
+		want time.Duration
+	}{
+		{2 * time.Second, time.Second},
+		{5 * time.Minute, 30 * time.Second},
+		{2 * time.Hour, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := idleCheckInterval(tt.idle); got != tt.want {
+			t.Errorf("idleCheckInterval(%v) = %v, want %v", tt.idle, got, tt.want)
+		}
+	}
+}
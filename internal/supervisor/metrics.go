@@ -0,0 +1,47 @@
+package supervisor
+
+import (
+	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/metrics"
+)
+
+// agentStates are the states reported by the fab_agents gauge. Each is
+// reported even with no agents in it, so series don't vanish between scrapes.
+var agentStates = []agent.State{
+	agent.StateStarting,
+	agent.StateRunning,
+	agent.StateIdle,
+	agent.StateDone,
+	agent.StateError,
+	agent.StateStuck,
+}
+
+// MetricsGauges samples the daemon's gauges for the /metrics endpoint:
+// agents by state, active projects, and pending permission requests.
+// There is no usage-percent gauge, since nothing tracks usage against a limit.
+func (s *Supervisor) MetricsGauges() []metrics.Sample {
+	byState := make(map[agent.State]int, len(agentStates))
+	for _, info := range s.agents.ListInfo("") {
+		byState[info.State]++
+	}
+	samples := make([]metrics.Sample, 0, len(agentStates)+2)
+	for _, state := range agentStates {
+		samples = append(samples, metrics.Sample{
+			Name:   metrics.Agents,
+			Labels: []metrics.Label{{Name: "state", Value: string(state)}},
+			Value:  float64(byState[state]),
+		})
+	}
+
+	active := 0
+	for _, p := range s.registry.List() {
+		if p.IsRunning() {
+			active++
+		}
+	}
+	samples = append(samples,
+		metrics.Sample{Name: metrics.ActiveProjects, Value: float64(active)},
+		metrics.Sample{Name: metrics.PendingPermissions, Value: float64(s.permissions.Count())},
+	)
+	return samples
+}
@@ -2,15 +2,21 @@ package supervisor
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"maps"
 	"time"
 
+	"github.com/tessro/fab/internal/agent"
 	"github.com/tessro/fab/internal/config"
+	"github.com/tessro/fab/internal/daemon"
 	"github.com/tessro/fab/internal/issue"
 	"github.com/tessro/fab/internal/issue/gh"
 	"github.com/tessro/fab/internal/issue/linear"
 	"github.com/tessro/fab/internal/issue/tk"
+	"github.com/tessro/fab/internal/logging"
 	"github.com/tessro/fab/internal/orchestrator"
 	"github.com/tessro/fab/internal/project"
 )
@@ -25,7 +31,15 @@ func (s *Supervisor) startOrchestrator(_ context.Context, proj *project.Project)
 		return nil
 	}
 
-	// Worktrees are created on-demand when agents start
+	// Worktrees are created on-demand when agents start, or taken from the
+	// pool if the project keeps one
+	proj.OpenWorktreePool()
+	go func() {
+		defer logging.LogPanic("worktree-pool-fill", nil)
+		if err := proj.FillWorktreePool(); err != nil {
+			slog.Warn("failed to fill worktree pool", "project", proj.Name, "error", err)
+		}
+	}()
 
 	// Register project with agent manager
 	s.agents.RegisterProject(proj)
@@ -33,6 +47,15 @@ func (s *Supervisor) startOrchestrator(_ context.Context, proj *project.Project)
 	// Configure orchestrator with issue backend factory for auto-spawning
 	cfg := s.orchConfig
 	cfg.IssueBackendFactory = issueBackendFactoryForProject(proj, s.globalConfig)
+	cfg.PollInterval = proj.GetPollInterval()
+
+	// Cache Ready/List results so frequent polling doesn't hit the tracker's API
+	delete(s.issueCaches, proj.Name)
+	if ttl := s.globalConfig.GetIssueCacheTTL(); ttl > 0 {
+		cache := issue.NewBackendCache(cfg.IssueBackendFactory, ttl)
+		cfg.IssueBackendFactory = cache.New
+		s.issueCaches[proj.Name] = cache
+	}
 
 	// Create orchestrator
 	orch := orchestrator.New(proj, s.agents, cfg)
@@ -60,7 +83,7 @@ func issueBackendFactoryForProject(proj *project.Project, globalCfg *config.Glob
 			if globalCfg != nil {
 				apiKey = globalCfg.GetAPIKey("github")
 			}
-			return gh.New(repoDir, proj.AllowedAuthors, apiKey)
+			return gh.New(repoDir, proj.GitHubHost, proj.AllowedAuthors, proj.GetGitHubIssueLimit(), apiKey)
 		case "linear":
 			apiKey := ""
 			if globalCfg != nil {
@@ -73,10 +96,63 @@ func issueBackendFactoryForProject(proj *project.Project, globalCfg *config.Glob
 	}
 }
 
+// ResolveIssueAuth reports where an issue backend of the given type takes
+// its token from and the token's fingerprint (the first 8 hex digits of its
+// SHA-256), following the same precedence as the backend itself. Both are
+// empty for backends that need no token (tk) or when no token is set.
+// A GitHub CLI token is looked up for host (gh's default host if empty).
+func ResolveIssueAuth(backendType, host string, globalCfg *config.GlobalConfig) (source, fingerprint string) {
+	var token string
+	switch backendType {
+	case "github", "gh":
+		token, source = gh.ResolveToken(globalCfg.GetAPIKey("github"), host)
+	case "linear":
+		token, source = linear.ResolveAPIKey(globalCfg.GetAPIKey("linear"))
+	}
+	if token == "" {
+		return "", ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return source, hex.EncodeToString(sum[:4])
+}
+
+// issueAuthStatus reports whether proj's issue backend has the credentials
+// and settings it needs, without contacting the issue tracker.
+func (s *Supervisor) issueAuthStatus(proj *project.Project) daemon.IssueAuthStatus {
+	status := daemon.IssueAuthStatus{Project: proj.Name, Backend: proj.GetIssueBackend()}
+	if status.Backend == "github" || status.Backend == "gh" {
+		status.Host = gh.ResolveHost(proj.RepoDir(), proj.GitHubHost)
+	}
+	status.Source, status.Fingerprint = ResolveIssueAuth(status.Backend, status.Host, s.globalConfig)
+
+	switch status.Backend {
+	case "tk":
+		status.OK = true
+	case "github", "gh":
+		if status.Source == "" {
+			status.Error = "GITHUB_TOKEN or GH_TOKEN not set in config or environment, and gh CLI not logged in"
+		}
+	case "linear":
+		switch {
+		case status.Source == "":
+			status.Error = "LINEAR_API_KEY not set in config or environment"
+		case proj.LinearTeam == "":
+			status.Error = "linear-team setting not configured for this project"
+		}
+	default:
+		status.Error = fmt.Sprintf("unknown issue backend: %s", status.Backend)
+	}
+	if status.Backend != "tk" {
+		status.OK = status.Error == ""
+	}
+	return status
+}
+
 // stopOrchestrator stops the orchestrator for the given project.
 // If preserveAgents is true, agents continue running in the agent host.
-func (s *Supervisor) stopOrchestrator(projectName string) {
-	s.stopOrchestratorWithOptions(projectName, false)
+// Returns false if the project had no orchestrator running.
+func (s *Supervisor) stopOrchestrator(projectName string) bool {
+	return s.stopOrchestratorWithOptions(projectName, false)
 }
 
 // stopOrchestratorPreserveAgents stops the orchestrator but leaves agents running.
@@ -87,25 +163,33 @@ func (s *Supervisor) stopOrchestratorPreserveAgents(projectName string) {
 
 // stopOrchestratorWithOptions is the internal implementation for stopping orchestrators.
 // If preserveAgents is true, agents are left running for the agent host to manage.
-func (s *Supervisor) stopOrchestratorWithOptions(projectName string, preserveAgents bool) {
+func (s *Supervisor) stopOrchestratorWithOptions(projectName string, preserveAgents bool) bool {
 	s.mu.Lock()
 	orch, ok := s.orchestrators[projectName]
 	s.mu.Unlock()
 
 	if !ok {
-		return
+		return false
 	}
 
 	// Stop the orchestrator (task assignment)
 	orch.Stop()
 
+	// Remove idle pooled worktrees before agents stop, so their worktrees
+	// are deleted rather than recycled
+	proj, err := s.registry.Get(projectName)
+	if err == nil {
+		if err := proj.CloseWorktreePool(); err != nil {
+			slog.Warn("failed to remove pooled worktrees", "project", projectName, "error", err)
+		}
+	}
+
 	// Stop agents unless we're preserving them for the agent host
 	if !preserveAgents {
 		s.agents.StopAll(projectName)
 	}
 
 	// Mark project as not running (orchestration stopped)
-	proj, err := s.registry.Get(projectName)
 	if err == nil {
 		proj.SetRunning(false)
 	}
@@ -122,14 +206,21 @@ func (s *Supervisor) stopOrchestratorWithOptions(projectName string, preserveAge
 		slog.Info("orchestrator stopped, agents terminated",
 			"project", projectName)
 	}
+	return true
 }
 
-// StartAutostart starts orchestration for all projects with autostart=true.
-// This should be called once during daemon startup.
+// StartAutostart starts orchestration for all projects with autostart=true,
+// except those outside their active hours, then starts the active-hours
+// scheduler. This should be called once during daemon startup.
 func (s *Supervisor) StartAutostart() {
 	ctx := context.Background()
+	now := time.Now()
 	for _, proj := range s.registry.List() {
 		if proj.Autostart {
+			if h := proj.GetActiveHours(); h != nil && !h.Contains(now) {
+				slog.Info("not autostarting project outside its active hours", "project", proj.Name, "active_hours", proj.ActiveHours)
+				continue
+			}
 			slog.Info("autostarting project", "project", proj.Name)
 			if err := s.startOrchestrator(ctx, proj); err != nil {
 				slog.Error("failed to autostart project",
@@ -138,6 +229,7 @@ func (s *Supervisor) StartAutostart() {
 			}
 		}
 	}
+	s.startScheduler(now)
 }
 
 // ShutdownTimeout is the maximum time to wait for graceful shutdown.
@@ -150,11 +242,13 @@ func (s *Supervisor) Shutdown() {
 }
 
 // ShutdownWithTimeout stops all orchestrators and agents with a timeout.
+// When agents are being stopped, they're first asked to exit with /quit;
+// agents still running when most of the timeout has passed are force-stopped.
 // Returns true if shutdown completed gracefully, false if timed out.
 func (s *Supervisor) ShutdownWithTimeout(timeout time.Duration) bool {
 	done := make(chan struct{})
 	go func() {
-		s.shutdownInternal()
+		s.shutdownInternal(drainTimeout(timeout))
 		close(done)
 	}()
 
@@ -168,24 +262,50 @@ func (s *Supervisor) ShutdownWithTimeout(timeout time.Duration) bool {
 	}
 }
 
+// drainTimeout returns how much of the shutdown timeout agents get to exit
+// on their own, leaving agent.StopTimeout for force-stopping stragglers.
+func drainTimeout(timeout time.Duration) time.Duration {
+	if timeout > 2*agent.StopTimeout {
+		return timeout - agent.StopTimeout
+	}
+	return timeout / 2
+}
+
 // shutdownInternal performs the actual shutdown work.
-func (s *Supervisor) shutdownInternal() {
+// Agents that will be stopped get up to drain to exit gracefully first.
+func (s *Supervisor) shutdownInternal(drain time.Duration) {
 	// Stop heartbeat monitor first
 	if s.heartbeat != nil {
 		s.heartbeat.Stop()
 	}
+	// Keep the scheduler from starting projects as they are stopped
+	s.stopScheduler()
 
 	// Get list of running orchestrators
 	s.mu.RLock()
 	projectNames := make([]string, 0, len(s.orchestrators))
-	for name := range s.orchestrators {
+	orchs := make([]*orchestrator.Orchestrator, 0, len(s.orchestrators))
+	for name, orch := range s.orchestrators {
 		projectNames = append(projectNames, name)
+		orchs = append(orchs, orch)
 	}
 	s.mu.RUnlock()
 
+	// Stop task assignment everywhere first, so agents exiting during the
+	// drain aren't replaced by new ones that claim tickets only to be
+	// force-stopped moments later
+	for _, orch := range orchs {
+		orch.Stop()
+	}
+
 	// Check if we should stop agents or preserve them
 	stopHost := s.StopHost()
 
+	// Let agents flush their sessions before they're stopped
+	if stopHost {
+		s.drainAgents(projectNames, drain)
+	}
+
 	// Stop each orchestrator
 	for _, name := range projectNames {
 		if stopHost {
@@ -198,6 +318,51 @@ func (s *Supervisor) shutdownInternal() {
 	}
 }
 
+// drainAgents sends /quit to the active agents of the given projects whose
+// read loops run in this daemon, then waits up to timeout for each to exit.
+// Agents still running afterwards are left for the orchestrators to stop.
+func (s *Supervisor) drainAgents(projectNames []string, timeout time.Duration) {
+	s.mu.RLock()
+	exits := maps.Clone(s.agentExits)
+	s.mu.RUnlock()
+
+	pending := make(map[string]chan struct{})
+	for _, name := range projectNames {
+		for _, a := range s.agents.List(name) {
+			exited, ok := exits[a.ID]
+			if !ok || !a.IsActive() {
+				continue
+			}
+			if err := a.SendMessage("/quit"); err != nil {
+				slog.Debug("failed to ask agent to quit", "agent", a.ID, "error", err)
+				continue
+			}
+			pending[a.ID] = exited
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	slog.Info("waiting for agents to exit before shutdown",
+		"agents", len(pending),
+		"timeout", timeout)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for id, exited := range pending {
+		select {
+		case <-exited:
+			delete(pending, id)
+		case <-timer.C:
+			slog.Warn("agents did not exit before the drain timeout, force stopping",
+				"remaining", len(pending))
+			return
+		}
+	}
+	slog.Info("all agents exited gracefully")
+}
+
 // getOrchestrator returns the orchestrator for a project, or nil if not running.
 func (s *Supervisor) getOrchestrator(projectName string) *orchestrator.Orchestrator {
 	s.mu.RLock()
@@ -0,0 +1,79 @@
+package supervisor
+
+import (
+	"log/slog"
+
+	"github.com/tessro/fab/internal/project"
+)
+
+// RecoverInterruptedMerges reconciles merges left in the journal by a daemon
+// that died mid-merge. Each entry is either completed (the merge reached
+// the remote default branch) or rolled back (the agent must run 'fab agent done' again).
+// Entries that fail to recover are kept so the next startup retries them.
+// This should be called once during daemon startup, before autostart.
+func (s *Supervisor) RecoverInterruptedMerges() {
+	if s.mergeJournal == nil {
+		return
+	}
+
+	intents, err := s.mergeJournal.List()
+	if err != nil {
+		slog.Warn("failed to read merge journal", "error", err)
+		return
+	}
+
+	for _, intent := range intents {
+		proj, err := s.registry.Get(intent.Project)
+		if err != nil {
+			slog.Warn("dropping interrupted merge for unknown project",
+				"project", intent.Project,
+				"agent", intent.AgentID,
+			)
+			_ = s.mergeJournal.Complete(intent.AgentID)
+			continue
+		}
+
+		outcome, err := proj.RecoverMerge(intent.AgentID, intent.TargetSHA)
+		if err != nil {
+			slog.Error("failed to recover interrupted merge",
+				"project", intent.Project,
+				"agent", intent.AgentID,
+				"branch", intent.Branch,
+				"error", err,
+			)
+			continue
+		}
+
+		switch outcome {
+		case project.MergeRecoveryCompleted:
+			slog.Info("recovered interrupted merge",
+				"project", intent.Project,
+				"agent", intent.AgentID,
+				"branch", intent.Branch,
+				"task", intent.TaskID,
+				"sha", intent.TargetSHA,
+			)
+			// Finish the cleanup HandleAgentDone would have done after merging
+			if _, err := s.agents.Get(intent.AgentID); err == nil {
+				_ = s.agents.Stop(intent.AgentID)
+				if err := s.agents.Delete(intent.AgentID); err != nil {
+					slog.Warn("failed to clean up agent after recovered merge",
+						"agent", intent.AgentID,
+						"error", err,
+					)
+				}
+			}
+		case project.MergeRecoveryRolledBack:
+			slog.Warn("rolled back interrupted merge; agent must run 'fab agent done' again",
+				"project", intent.Project,
+				"agent", intent.AgentID,
+				"branch", intent.Branch,
+				"task", intent.TaskID,
+			)
+		}
+
+		if err := s.mergeJournal.Complete(intent.AgentID); err != nil {
+			slog.Warn("failed to clear recovered merge", "agent", intent.AgentID, "error", err)
+		}
+	}
+}
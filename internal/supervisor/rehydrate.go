@@ -75,6 +75,10 @@ func (s *Supervisor) rehydrateFromHost(host agenthost.DiscoveredHost) error {
 	// Hydrate the agent
 	info := agent.HydrateInfo{
 		ID:          agentInfo.ID,
+		Name:        agentInfo.Name,
+		SpawnedBy:   agentInfo.SpawnedBy,
+		Role:        agentInfo.Role,
+		Subdir:      agentInfo.Subdir,
 		Project:     agentInfo.Project,
 		State:       state,
 		Worktree:    agentInfo.Worktree,
@@ -112,6 +116,8 @@ func parseAgentState(s string) agent.State {
 		return agent.StateDone
 	case "error":
 		return agent.StateError
+	case "stuck":
+		return agent.StateStuck
 	default:
 		// Default to running if unknown
 		return agent.StateRunning
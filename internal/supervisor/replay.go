@@ -0,0 +1,206 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/logging"
+	"github.com/tessro/fab/internal/runtime"
+)
+
+// replayTurnTimeout bounds how long a replay waits for the agent to finish
+// a turn before giving up on the remaining turns.
+const replayTurnTimeout = 30 * time.Minute
+
+// replayPollInterval is how often a waiting replay checks whether the agent
+// has exited or been deleted.
+const replayPollInterval = time.Second
+
+// handleAgentReplay starts a new agent and replays the user turns of an
+// existing or deleted agent into it, one turn at a time.
+func (s *Supervisor) handleAgentReplay(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var replayReq daemon.AgentReplayRequest
+	if err := unmarshalPayload(req.Payload, &replayReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	if replayReq.ID == "" {
+		return errorResponse(req, "agent ID required")
+	}
+
+	turns, sourceProject, err := s.replayTurns(replayReq.ID)
+	if err != nil {
+		return errorResponse(req, err.Error())
+	}
+	if len(turns) == 0 {
+		return errorResponse(req, fmt.Sprintf("agent %s has no user turns to replay", replayReq.ID))
+	}
+
+	projectName := replayReq.Project
+	if projectName == "" {
+		projectName = sourceProject
+	}
+	proj, err := s.registry.Get(projectName)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("project not found: %s", projectName))
+	}
+
+	a, err := s.agents.Create(proj, agent.SpawnedByUser, false)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("failed to create agent: %v", err))
+	}
+	a.SetDescription(fmt.Sprintf("Replay of %s", replayReq.ID))
+
+	if err := a.Start(""); err != nil {
+		_ = s.agents.Delete(a.ID)
+		return errorResponse(req, fmt.Sprintf("failed to start agent: %v", err))
+	}
+	// Log but don't fail - agent is still usable without broadcasting
+	_ = s.StartAgentReadLoop(a)
+
+	idle := s.registerReplay(a.ID)
+	go s.runReplay(a, replayReq.ID, turns, idle)
+
+	slog.Info("agent replay started",
+		"agent", a.ID,
+		"source", replayReq.ID,
+		"project", proj.Name,
+		"turns", len(turns),
+	)
+
+	return successResponse(req, daemon.AgentReplayResponse{
+		ID:       a.ID,
+		SourceID: replayReq.ID,
+		Project:  proj.Name,
+		Worktree: a.Info().Worktree,
+		Turns:    len(turns),
+	})
+}
+
+// replayTurns returns the user turns of an agent in order, along with its project.
+// Running agents are read from memory; deleted agents from their saved transcript.
+func (s *Supervisor) replayTurns(id string) ([]string, string, error) {
+	var turns []string
+
+	if a, err := s.agents.Get(id); err == nil {
+		for _, e := range a.History().All() {
+			if e.Role == "user" && e.Content != "" {
+				turns = append(turns, e.Content)
+			}
+		}
+		return turns, a.Info().Project, nil
+	}
+
+	t, err := s.agents.LoadTranscript(id)
+	if err != nil {
+		if errors.Is(err, runtime.ErrTranscriptNotFound) {
+			return nil, "", fmt.Errorf("agent not found: %s", id)
+		}
+		return nil, "", fmt.Errorf("load transcript: %w", err)
+	}
+	for _, e := range t.Entries {
+		if e.Role == "user" && e.Content != "" {
+			turns = append(turns, e.Content)
+		}
+	}
+	return turns, t.Project, nil
+}
+
+// runReplay sends each turn and waits for the agent to go idle before the next.
+func (s *Supervisor) runReplay(a *agent.Agent, sourceID string, turns []string, idle <-chan struct{}) {
+	defer logging.LogPanic("agent-replay", nil)
+	defer s.unregisterReplay(a.ID)
+
+	log := slog.With("agent", a.ID, "source", sourceID)
+
+	for i, turn := range turns {
+		if err := a.SendMessage(turn); err != nil {
+			log.Warn("replay stopped: failed to send turn", "turn", i+1, "error", err)
+			return
+		}
+		log.Debug("replayed turn", "turn", i+1, "total", len(turns))
+
+		if !s.waitForReplayTurn(a, idle) {
+			log.Warn("replay stopped: agent did not finish turn", "turn", i+1, "total", len(turns))
+			return
+		}
+	}
+
+	log.Info("agent replay finished", "turns", len(turns))
+}
+
+// waitForReplayTurn blocks until the agent signals idle.
+// Returns false if the agent exits, is deleted, times out, or the daemon shuts down.
+func (s *Supervisor) waitForReplayTurn(a *agent.Agent, idle <-chan struct{}) bool {
+	timeout := time.NewTimer(replayTurnTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(replayPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-idle:
+			return true
+		case <-ticker.C:
+			if a.IsTerminal() || !s.agents.Exists(a.ID) {
+				return false
+			}
+		case <-timeout.C:
+			return false
+		case <-s.shutdownCh:
+			return false
+		}
+	}
+}
+
+// registerReplay marks an agent as being driven by a replay.
+// The returned channel receives a value each time the agent goes idle.
+func (s *Supervisor) registerReplay(agentID string) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.replays[agentID] = ch
+	s.mu.Unlock()
+	return ch
+}
+
+// registerTurns is registerReplay for an agent that may already be driven:
+// it fails if a replay or compaction is already waiting on the agent.
+func (s *Supervisor) registerTurns(agentID string) (<-chan struct{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, busy := s.replays[agentID]; busy {
+		return nil, false
+	}
+	ch := make(chan struct{}, 1)
+	s.replays[agentID] = ch
+	return ch, true
+}
+
+// unregisterReplay returns the agent to normal kickstart handling.
+func (s *Supervisor) unregisterReplay(agentID string) {
+	s.mu.Lock()
+	delete(s.replays, agentID)
+	s.mu.Unlock()
+}
+
+// notifyReplayIdle signals a replay that its agent finished a turn.
+// Returns true if the agent is being replayed.
+func (s *Supervisor) notifyReplayIdle(agentID string) bool {
+	s.mu.RLock()
+	ch, ok := s.replays[agentID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return false
+	}
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package supervisor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/runtime"
+)
+
+func TestSupervisor_HandleAgentReplayNotFound(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+	sup.agents.SetTranscriptStore(runtime.NewTranscriptStore(t.TempDir()))
+
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgAgentReplay,
+		ID:      "test-1",
+		Payload: map[string]any{"id": "nonexistent"},
+	})
+
+	if resp.Success {
+		t.Fatal("expected error for nonexistent agent")
+	}
+	if !strings.Contains(resp.Error, "agent not found") {
+		t.Errorf("error = %q, want agent not found", resp.Error)
+	}
+}
+
+func TestSupervisor_HandleAgentReplayNoUserTurns(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+	store := runtime.NewTranscriptStore(t.TempDir())
+	sup.agents.SetTranscriptStore(store)
+
+	if err := store.Save(runtime.Transcript{
+		AgentID: "old1",
+		Project: "proj",
+		Entries: []runtime.TranscriptEntry{{Role: "assistant", Content: "hi"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgAgentReplay,
+		ID:      "test-1",
+		Payload: map[string]any{"id": "old1"},
+	})
+
+	if resp.Success {
+		t.Fatal("expected error for transcript without user turns")
+	}
+	if !strings.Contains(resp.Error, "no user turns") {
+		t.Errorf("error = %q, want no user turns", resp.Error)
+	}
+}
+
+func TestSupervisor_ReplayTurnsFromTranscript(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+	store := runtime.NewTranscriptStore(t.TempDir())
+	sup.agents.SetTranscriptStore(store)
+
+	if err := store.Save(runtime.Transcript{
+		AgentID: "old1",
+		Project: "proj",
+		Entries: []runtime.TranscriptEntry{
+			{Role: "user", Content: "first"},
+			{Role: "assistant", Content: "working on it"},
+			{Role: "tool", ToolName: "Bash", ToolInput: "ls"},
+			{Role: "user", Content: ""},
+			{Role: "user", Content: "second"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	turns, project, err := sup.replayTurns("old1")
+	if err != nil {
+		t.Fatalf("replayTurns failed: %v", err)
+	}
+	if project != "proj" {
+		t.Errorf("project = %q, want proj", project)
+	}
+	if len(turns) != 2 || turns[0] != "first" || turns[1] != "second" {
+		t.Errorf("turns = %q, want [first second]", turns)
+	}
+}
+
+func TestSupervisor_NotifyReplayIdle(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	if sup.notifyReplayIdle("a1") {
+		t.Error("expected false for agent without a replay")
+	}
+
+	idle := sup.registerReplay("a1")
+	if !sup.notifyReplayIdle("a1") {
+		t.Fatal("expected true for replayed agent")
+	}
+	// A second signal before the first is consumed must not block
+	sup.notifyReplayIdle("a1")
+
+	select {
+	case <-idle:
+	default:
+		t.Error("expected idle signal")
+	}
+
+	sup.unregisterReplay("a1")
+	if sup.notifyReplayIdle("a1") {
+		t.Error("expected false after unregister")
+	}
+}
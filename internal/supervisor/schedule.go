@@ -0,0 +1,95 @@
+package supervisor
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/tessro/fab/internal/logging"
+)
+
+// scheduleInterval is how often active-hours windows are checked. Windows
+// are set to the minute, so starts and stops happen within this of the
+// boundary.
+const scheduleInterval = 30 * time.Second
+
+// activeHoursDrainTimeout bounds how long closing a project's window waits
+// for its agents to exit and in-flight merges to finish.
+const activeHoursDrainTimeout = 2 * time.Minute
+
+// startScheduler records which active-hours windows are open as of now and
+// starts checking them every scheduleInterval until shutdown.
+func (s *Supervisor) startScheduler(now time.Time) {
+	s.applySchedule(now)
+	go s.runScheduler()
+}
+
+// stopScheduler stops the scheduler started by startScheduler, if any.
+func (s *Supervisor) stopScheduler() {
+	s.stopScheduleOnce.Do(func() { close(s.scheduleStop) })
+}
+
+// runScheduler applies active hours every scheduleInterval until stopped.
+func (s *Supervisor) runScheduler() {
+	defer logging.LogPanic("active-hours-scheduler", nil)
+
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.scheduleStop:
+			return
+		case <-s.shutdownCh:
+			return
+		case now := <-ticker.C:
+			s.applySchedule(now)
+		}
+	}
+}
+
+// applySchedule starts and stops orchestration for projects whose
+// active-hours window opened or closed since the last check. When a window
+// opens, autostart projects start; when it closes, running projects drain:
+// spawning stops and agents are asked to exit. Only crossings act, so a
+// project started by hand outside its window keeps running until the
+// window next closes. A project seen for the first time, or whose setting
+// just changed, is only recorded.
+func (s *Supervisor) applySchedule(now time.Time) {
+	for _, proj := range s.registry.List() {
+		h := proj.GetActiveHours()
+		if h == nil {
+			delete(s.windowOpen, proj.Name)
+			continue
+		}
+		open := h.Contains(now)
+		wasOpen, seen := s.windowOpen[proj.Name]
+		s.windowOpen[proj.Name] = open
+		if !seen || open == wasOpen {
+			continue
+		}
+
+		if open {
+			if !proj.Autostart {
+				continue
+			}
+			slog.Info("active hours started, starting project", "project", proj.Name, "active_hours", proj.ActiveHours)
+			if err := s.startOrchestrator(context.Background(), proj); err != nil {
+				slog.Error("failed to start project for active hours", "project", proj.Name, "error", err)
+			}
+			continue
+		}
+
+		orch := s.getOrchestrator(proj.Name)
+		if orch == nil {
+			continue
+		}
+		slog.Info("active hours ended, draining project", "project", proj.Name, "active_hours", proj.ActiveHours)
+		go func(name string) {
+			defer logging.LogPanic("active-hours-drain", nil)
+			if err := s.drainProject(name, orch, activeHoursDrainTimeout); err != nil {
+				slog.Warn("active hours drain incomplete", "project", name, "error", err)
+			}
+		}(proj.Name)
+	}
+}
@@ -0,0 +1,57 @@
+package supervisor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tessro/fab/internal/registry"
+)
+
+func TestSupervisor_ApplySchedule(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	for _, name := range []string{"work", "manual"} {
+		if _, err := sup.registry.Add("git@github.com:user/"+name+".git", name, 1, name == "work", ""); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if err := sup.registry.SetConfigValue(name, registry.ConfigKeyActiveHours, "09:00-17:00 Mon-Fri UTC"); err != nil {
+			t.Fatalf("SetConfigValue() error = %v", err)
+		}
+	}
+	defer sup.stopOrchestrator("work")
+
+	// 2026-01-05 is a Monday
+	monday := func(hour int) time.Time { return time.Date(2026, 1, 5, hour, 0, 0, 0, time.UTC) }
+
+	// The first check only records the window state
+	sup.applySchedule(monday(8))
+	if sup.getOrchestrator("work") != nil {
+		t.Fatal("project started before its window opened")
+	}
+
+	sup.applySchedule(monday(9))
+	if sup.getOrchestrator("work") == nil {
+		t.Fatal("autostart project not started when its window opened")
+	}
+	if sup.getOrchestrator("manual") != nil {
+		t.Error("project without autostart started by the schedule")
+	}
+
+	sup.applySchedule(monday(12))
+	if sup.getOrchestrator("work") == nil {
+		t.Fatal("project stopped inside its window")
+	}
+
+	sup.applySchedule(monday(17))
+	deadline := time.Now().Add(5 * time.Second)
+	for sup.getOrchestrator("work") != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("project still running after its window closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if p, _ := sup.registry.Get("work"); p.IsRunning() {
+		t.Error("project still marked running after its window closed")
+	}
+}
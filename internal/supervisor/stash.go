@@ -0,0 +1,86 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/project"
+)
+
+// stashAgentWork stashes uncommitted changes in the agent's worktree and records
+// the stash on the agent. Failures are logged; they must not block the abort.
+func (s *Supervisor) stashAgentWork(a *agent.Agent) {
+	if a.Project == nil {
+		return
+	}
+
+	ref, err := a.Project.StashWorktree(a.ID)
+	if err != nil {
+		slog.Warn("failed to stash agent worktree before abort", "agent", a.ID, "error", err)
+		return
+	}
+	if ref == "" {
+		return
+	}
+
+	a.SetStashRef(ref)
+	slog.Info("stashed uncommitted agent work before abort",
+		"agent", a.ID,
+		"project", a.Project.Name,
+		"stash", ref,
+	)
+}
+
+// handleAgentRecover pops the most recent abort stash into a named branch.
+func (s *Supervisor) handleAgentRecover(ctx context.Context, req *daemon.Request) *daemon.Response {
+	var recoverReq daemon.AgentRecoverRequest
+	if err := unmarshalPayload(req.Payload, &recoverReq); err != nil {
+		return errorResponse(req, fmt.Sprintf("invalid payload: %v", err))
+	}
+
+	projectName := recoverReq.Project
+	if projectName == "" && recoverReq.ID != "" {
+		if a, err := s.agents.Get(recoverReq.ID); err == nil {
+			projectName = a.Info().Project
+		}
+	}
+	if projectName == "" {
+		return errorResponse(req, "project name required")
+	}
+
+	proj, err := s.registry.Get(projectName)
+	if err != nil {
+		return errorResponse(req, fmt.Sprintf("project not found: %s", projectName))
+	}
+
+	result, err := proj.RecoverStash(recoverReq.ID, recoverReq.Branch)
+	if err != nil {
+		if errors.Is(err, project.ErrNoStash) && recoverReq.ID != "" {
+			return errorResponse(req, fmt.Sprintf("no stashed work found for agent %s", recoverReq.ID))
+		}
+		return errorResponse(req, fmt.Sprintf("failed to recover stash: %v", err))
+	}
+
+	// The stash has been dropped; clear it from the agent if it is still around
+	if a, err := s.agents.Get(result.AgentID); err == nil {
+		a.SetStashRef("")
+	}
+
+	slog.Info("recovered stashed agent work",
+		"agent", result.AgentID,
+		"project", proj.Name,
+		"branch", result.Branch,
+		"commit", result.Commit,
+	)
+
+	return successResponse(req, daemon.AgentRecoverResponse{
+		ID:      result.AgentID,
+		Project: proj.Name,
+		Branch:  result.Branch,
+		Commit:  result.Commit,
+	})
+}
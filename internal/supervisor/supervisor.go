@@ -6,17 +6,22 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/backend"
 	"github.com/tessro/fab/internal/config"
 	"github.com/tessro/fab/internal/daemon"
 	"github.com/tessro/fab/internal/director"
+	"github.com/tessro/fab/internal/issue"
 	"github.com/tessro/fab/internal/manager"
+	"github.com/tessro/fab/internal/notify"
 	"github.com/tessro/fab/internal/orchestrator"
 	"github.com/tessro/fab/internal/planner"
 	"github.com/tessro/fab/internal/project"
 	"github.com/tessro/fab/internal/registry"
+	"github.com/tessro/fab/internal/rules"
 	"github.com/tessro/fab/internal/runtime"
 	"github.com/tessro/fab/internal/version"
 )
@@ -37,9 +42,21 @@ type Supervisor struct {
 	// +checklocks:mu
 	orchestrators map[string]*orchestrator.Orchestrator // project name -> orchestrator
 
+	// Issue caches of running orchestrators (nil entries when caching is off)
+	// +checklocks:mu
+	issueCaches map[string]*issue.BackendCache // project name -> cache
+
 	// Manager allowed patterns loaded from global permissions
 	managerPatterns []string
 
+	// Policy for permission requests when no TUI is attached,
+	// loaded from global permissions
+	headless rules.HeadlessConfig
+
+	// Evaluates permission rules, including decisions remembered
+	// from the TUI
+	ruleEval *rules.Evaluator
+
 	// Per-project manager agents (project name -> manager)
 	// +checklocks:mu
 	managers map[string]*manager.Manager
@@ -48,6 +65,16 @@ type Supervisor struct {
 	// +checklocks:mu
 	director *director.Director
 
+	// Agents being driven by 'fab replay' (agent ID -> idle signal).
+	// Replayed agents skip the kickstart prompt.
+	// +checklocks:mu
+	replays map[string]chan struct{}
+
+	// Agents whose read loops run in this daemon (agent ID -> closed when
+	// the process exits). Used to wait for agents to quit on shutdown.
+	// +checklocks:mu
+	agentExits map[string]chan struct{}
+
 	// Planner agents for implementation planning.
 	// Safe for concurrent access via Manager's internal synchronization.
 	planners *planner.Manager
@@ -56,6 +83,10 @@ type Supervisor struct {
 	shutdownMu sync.Mutex    // Protects closing shutdownCh exactly once
 	stopHost   bool          // If true, stop the agent host on shutdown
 
+	// lastActivity is when the daemon was last busy or handled a request
+	// (Unix nanoseconds), for idle auto-shutdown
+	lastActivity atomic.Int64
+
 	// +checklocks:mu
 	server *daemon.Server // Server reference for broadcasting output events
 
@@ -65,24 +96,48 @@ type Supervisor struct {
 	// Heartbeat monitor for detecting stuck agents
 	heartbeat *HeartbeatMonitor
 
+	// Active-hours scheduler. scheduleStop is closed on shutdown; windowOpen
+	// holds whether each scheduled project's window was open at the last
+	// check and is only used by the scheduler goroutine.
+	scheduleStop     chan struct{}
+	stopScheduleOnce sync.Once
+	windowOpen       map[string]bool
+
 	// runtimeStore persists agent metadata for daemon restart recovery.
 	// May be nil if persistence is disabled.
 	runtimeStore *runtime.Store
 
+	// mergeJournal records in-flight merges for crash recovery.
+	// May be nil if the journal could not be created.
+	mergeJournal *runtime.MergeJournal
+
 	// Comment poller for fetching new issue comments
 	commentPoller *CommentPoller
 	dedupStore    *runtime.DedupStore
 
+	// Notification dispatcher for webhook/desktop alerts.
+	// May be nil if notifications are not configured.
+	notifier *notify.Dispatcher
+
+	// managerCreateMu serializes manager creation so slow worktree setup
+	// happens without holding mu.
+	managerCreateMu sync.Mutex
+
+	// createManagerWorktree ensures a project's manager worktree exists.
+	// Replaceable in tests.
+	createManagerWorktree func(*project.Project) error
+
 	mu sync.RWMutex
 }
 
-// PermissionTimeout is the default timeout for permission requests.
-const PermissionTimeout = 5 * time.Minute
+// PermissionTimeout is the default timeout for permission requests. The
+// permission-timeouts config overrides it per tool.
+const PermissionTimeout = config.DefaultPermissionTimeout
 
 // New creates a new Supervisor with the given registry and agent manager.
 func New(reg *registry.Registry, agents *agent.Manager) *Supervisor {
-	// Load manager allowed patterns from global permissions.toml
-	managerPatterns := loadManagerPatterns()
+	// Load manager allowed patterns and headless policy from global permissions.toml
+	permsCfg := loadPermissionsConfig()
 
 	// Load global config for LLM auth settings
 	globalCfg, err := config.LoadGlobalConfig()
@@ -90,6 +145,9 @@ func New(reg *registry.Registry, agents *agent.Manager) *Supervisor {
 		slog.Warn("failed to load global config", "error", err)
 	}
 
+	// Apply configured CLI paths and arguments before anything is spawned
+	configureBackends(globalCfg)
+
 	// Initialize runtime store for agent metadata persistence
 	runtimeStore, err := runtime.NewStore()
 	if err != nil {
@@ -102,33 +160,74 @@ func New(reg *registry.Registry, agents *agent.Manager) *Supervisor {
 		slog.Warn("failed to create dedup store", "error", err)
 	}
 
+	// Initialize transcript store so deleted agents can be replayed
+	transcriptStore, err := runtime.NewTranscriptStoreDefault()
+	if err != nil {
+		slog.Warn("failed to create transcript store", "error", err)
+	}
+
+	// Initialize merge journal for interrupted-merge recovery
+	mergeJournal, err := runtime.NewMergeJournalDefault()
+	if err != nil {
+		slog.Warn("failed to create merge journal", "error", err)
+	}
+
 	s := &Supervisor{
 		registry:        reg,
 		agents:          agents,
 		orchestrators:   make(map[string]*orchestrator.Orchestrator),
+		issueCaches:     make(map[string]*issue.BackendCache),
 		orchConfig:      orchestrator.DefaultConfig(),
 		permissions:     daemon.NewPermissionManager(PermissionTimeout),
 		questions:       daemon.NewUserQuestionManager(PermissionTimeout),
 		startedAt:       time.Now(),
 		shutdownCh:      make(chan struct{}),
-		managerPatterns: managerPatterns,
+		scheduleStop:    make(chan struct{}),
+		windowOpen:      make(map[string]bool),
+		managerPatterns: permsCfg.ManagerAllowedPatterns(),
+		headless:        permsCfg.HeadlessPolicy(),
+		ruleEval:        rules.NewEvaluator(),
 		managers:        make(map[string]*manager.Manager),
+		replays:         make(map[string]chan struct{}),
+		agentExits:      make(map[string]chan struct{}),
 		planners:        planner.NewManager(),
 		globalConfig:    globalCfg,
 		runtimeStore:    runtimeStore,
 		dedupStore:      dedupStore,
+		mergeJournal:    mergeJournal,
+		notifier:        newNotifier(globalCfg),
+
+		createManagerWorktree: (*project.Project).CreateManagerWorktree,
 	}
+	s.lastActivity.Store(s.startedAt.UnixNano())
 
 	// Wire up runtime store to agent and planner managers
 	if runtimeStore != nil {
 		agents.SetRuntimeStore(runtimeStore)
 		s.planners.SetRuntimeStore(runtimeStore)
 	}
+	if transcriptStore != nil {
+		agents.SetTranscriptStore(transcriptStore)
+	}
+	agents.SetHistorySpill(true)
+
+	redactor, err := agent.NewRedactor(globalCfg.GetRedactionPatterns(), globalCfg.GetRedactionValues())
+	if err != nil {
+		slog.Warn("skipping invalid redaction patterns", "error", err)
+	}
+	agents.SetRedactor(redactor)
 
 	// Set up callback to start agent read loops when agent starts
-	s.orchConfig.OnAgentStarted = func(a *agent.Agent) {
-		// Log but don't fail - agent is still usable without broadcasting
-		_ = s.StartAgentReadLoop(a)
+	s.orchConfig.OnAgentStarted = s.handleAgentStarted
+	s.orchConfig.MergeJournal = mergeJournal
+	s.orchConfig.OnDecision = s.broadcastDecision
+	s.orchConfig.OnAgentChatEntry = func(a *agent.Agent, entry agent.ChatEntry) {
+		info := a.Info()
+		s.broadcastChatEntry(info.ID, info.Project, entry)
+		// Long pre-merge runs shouldn't look like a stuck agent
+		if s.heartbeat != nil {
+			s.heartbeat.RecordOutput(info.ID)
+		}
 	}
 
 	// Register event handler to broadcast agent events
@@ -181,16 +280,66 @@ func New(reg *registry.Registry, agents *agent.Manager) *Supervisor {
 	return s
 }
 
+// newNotifier creates a notification dispatcher from the global config.
+// Unknown event names are logged and skipped rather than silently never
+// matching. Returns nil if no notifiers are configured.
+func newNotifier(globalCfg *config.GlobalConfig) *notify.Dispatcher {
+	if globalCfg == nil {
+		return nil
+	}
+
+	cfg := globalCfg.Notifications
+	events := make([]notify.EventType, 0, len(cfg.Events))
+	for _, e := range cfg.Events {
+		if err := config.ValidateNotificationEvent(e); err != nil {
+			slog.Warn("ignoring notification event", "error", err)
+			continue
+		}
+		events = append(events, notify.EventType(e))
+	}
+
+	return notify.New(notify.Config{
+		WebhookURL: cfg.WebhookURL,
+		Desktop:    cfg.Desktop,
+		Events:     events,
+	})
+}
+
+// configureBackends applies the backends.<name> settings from the global config
+// to the backend registry so agents, managers, planners, and the director all
+// launch the configured CLI binary and arguments. It also lets permission hooks
+// run as long as the longest configured permission timeout.
+func configureBackends(globalCfg *config.GlobalConfig) {
+	backend.SetPermissionHookTimeout(globalCfg.MaxPermissionTimeout())
+	if globalCfg == nil {
+		return
+	}
+	for name, cfg := range globalCfg.Backends {
+		if _, err := backend.Get(name); err != nil {
+			slog.Warn("ignoring config for unknown backend", "backend", name)
+			continue
+		}
+		backend.SetBinaryConfig(name, backend.BinaryConfig{
+			Path:      cfg.Path,
+			ExtraArgs: cfg.ExtraArgs,
+		})
+		slog.Info("configured backend CLI", "backend", name, "path", cfg.Path, "extra_args", cfg.ExtraArgs)
+	}
+}
+
 // Handle processes IPC requests and returns responses.
 // Implements daemon.Handler.
 func (s *Supervisor) Handle(ctx context.Context, req *daemon.Request) *daemon.Response {
 	slog.Debug("supervisor handling request", "type", req.Type)
+	s.touchActivity()
 	switch req.Type {
 	// Server management
 	case daemon.MsgPing:
 		return s.handlePing(ctx, req)
 	case daemon.MsgShutdown:
 		return s.handleShutdown(ctx, req)
+	case daemon.MsgWhoami:
+		return s.handleWhoami(ctx, req)
 
 	// Supervisor control
 	case daemon.MsgStart:
@@ -215,6 +364,10 @@ func (s *Supervisor) Handle(ctx context.Context, req *daemon.Request) *daemon.Re
 		return s.handleProjectConfigGet(ctx, req)
 	case daemon.MsgProjectConfigSet:
 		return s.handleProjectConfigSet(ctx, req)
+	case daemon.MsgProjectPull:
+		return s.handleProjectPull(ctx, req)
+	case daemon.MsgProjectLog:
+		return s.handleProjectLog(ctx, req)
 
 	// Agent management
 	case daemon.MsgAgentList:
@@ -235,14 +388,42 @@ func (s *Supervisor) Handle(ctx context.Context, req *daemon.Request) *daemon.Re
 		return s.handleAgentChatHistory(ctx, req)
 	case daemon.MsgAgentDescribe:
 		return s.handleAgentDescribe(ctx, req)
+	case daemon.MsgAgentRename:
+		return s.handleAgentRename(ctx, req)
+	case daemon.MsgAgentExec:
+		return s.handleAgentExec(ctx, req)
+	case daemon.MsgAgentLineage:
+		return s.handleAgentLineage(ctx, req)
+	case daemon.MsgAgentGet:
+		return s.handleAgentGet(ctx, req)
 	case daemon.MsgAgentIdle:
 		return s.handleAgentIdle(ctx, req)
+	case daemon.MsgAgentReplay:
+		return s.handleAgentReplay(ctx, req)
+	case daemon.MsgAgentCompact:
+		return s.handleAgentCompact(ctx, req)
+	case daemon.MsgAgentRecover:
+		return s.handleAgentRecover(ctx, req)
+	case daemon.MsgAgentDelegate:
+		return s.handleAgentDelegate(ctx, req)
+	case daemon.MsgAgentDebugCapture:
+		return s.handleAgentDebugCapture(ctx, req)
+	case daemon.MsgAgentTranscriptExport:
+		return s.handleAgentTranscriptExport(ctx, req)
+	case daemon.MsgAgentGrep:
+		return s.handleAgentGrep(ctx, req)
 
 	// TUI streaming
+	case daemon.MsgDashboard:
+		return s.handleDashboard(ctx, req)
 	case daemon.MsgAttach:
 		return s.handleAttach(ctx, req)
 	case daemon.MsgDetach:
 		return s.handleDetach(ctx, req)
+	case daemon.MsgLogSubscribe:
+		return s.handleLogSubscribe(ctx, req)
+	case daemon.MsgSetLogLevel:
+		return s.handleSetLogLevel(ctx, req)
 
 	// Orchestrator
 	case daemon.MsgAgentDone:
@@ -267,6 +448,18 @@ func (s *Supervisor) Handle(ctx context.Context, req *daemon.Request) *daemon.Re
 		return s.handleAgentClaim(ctx, req)
 	case daemon.MsgClaimList:
 		return s.handleClaimList(ctx, req)
+	case daemon.MsgClaimRelease:
+		return s.handleClaimRelease(ctx, req)
+
+	// Shared notes
+	case daemon.MsgNoteAppend:
+		return s.handleNoteAppend(ctx, req)
+	case daemon.MsgNoteRead:
+		return s.handleNoteRead(ctx, req)
+
+	// Activity timeline
+	case daemon.MsgTimeline:
+		return s.handleTimeline(ctx, req)
 
 	// Manager agent
 	case daemon.MsgManagerStart:
@@ -275,6 +468,8 @@ func (s *Supervisor) Handle(ctx context.Context, req *daemon.Request) *daemon.Re
 		return s.handleManagerStop(ctx, req)
 	case daemon.MsgManagerStatus:
 		return s.handleManagerStatus(ctx, req)
+	case daemon.MsgManagerList:
+		return s.handleManagerListAll(ctx, req)
 	case daemon.MsgManagerSendMessage:
 		return s.handleManagerSendMessage(ctx, req)
 	case daemon.MsgManagerChatHistory:
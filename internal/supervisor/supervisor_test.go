@@ -2,15 +2,26 @@ package supervisor
 
 import (
 	"context"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/tessro/fab/internal/agent"
+	"github.com/tessro/fab/internal/backend"
+	"github.com/tessro/fab/internal/config"
 	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/logging"
+	"github.com/tessro/fab/internal/manager"
+	"github.com/tessro/fab/internal/orchestrator"
+	"github.com/tessro/fab/internal/project"
 	"github.com/tessro/fab/internal/registry"
+	"github.com/tessro/fab/internal/rules"
 )
 
 // newTestGitRepo creates a temp directory initialized as a git repository.
@@ -196,6 +207,81 @@ func TestSupervisor_HandleShutdown_PreserveHost(t *testing.T) {
 	}
 }
 
+func TestSupervisor_DrainAgents(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	// A fake CLI that exits on /quit, except for the "stubborn" agent
+	script := filepath.Join(t.TempDir(), "claude")
+	if err := os.WriteFile(script, []byte(`#!/bin/sh
+echo '{"type":"system","subtype":"init"}'
+while read -r line; do
+	case "$line" in
+	*'"/quit"'*) [ "$FAB_AGENT_ID" = stubborn ] || exit 0 ;;
+	esac
+done
+`), 0755); err != nil {
+		t.Fatal(err)
+	}
+	backend.SetBinaryConfig("claude", backend.BinaryConfig{Path: script})
+	defer backend.SetBinaryConfig("claude", backend.BinaryConfig{})
+
+	sup.agents.RegisterProject(&project.Project{Name: "proj"})
+	start := func(id string) *agent.Agent {
+		a, err := sup.agents.Hydrate(agent.HydrateInfo{
+			ID:        id,
+			Project:   "proj",
+			State:     agent.StateStarting,
+			Worktree:  t.TempDir(),
+			StartedAt: time.Now(),
+			Backend:   "claude",
+		})
+		if err != nil {
+			t.Fatalf("Hydrate(%s) error = %v", id, err)
+		}
+		if err := a.Start(""); err != nil {
+			t.Fatalf("Start(%s) error = %v", id, err)
+		}
+		if err := sup.StartAgentReadLoop(a); err != nil {
+			t.Fatalf("StartAgentReadLoop(%s) error = %v", id, err)
+		}
+		return a
+	}
+	quitter := start("quitter")
+	stubborn := start("stubborn")
+	defer func() { _ = sup.agents.Stop(stubborn.ID) }()
+
+	began := time.Now()
+	sup.drainAgents([]string{"proj"}, 500*time.Millisecond)
+	if elapsed := time.Since(began); elapsed < 500*time.Millisecond {
+		t.Errorf("drainAgents returned after %v, want it to wait out the timeout for the stubborn agent", elapsed)
+	}
+
+	if got := quitter.GetState(); got != agent.StateDone {
+		t.Errorf("quitter state = %s, want %s after /quit", got, agent.StateDone)
+	}
+	if !stubborn.IsActive() {
+		t.Errorf("stubborn state = %s, want it still active for the force stop", stubborn.GetState())
+	}
+
+	sup.mu.RLock()
+	_, quitterTracked := sup.agentExits["quitter"]
+	_, stubbornTracked := sup.agentExits["stubborn"]
+	sup.mu.RUnlock()
+	if quitterTracked || !stubbornTracked {
+		t.Errorf("tracked exits: quitter=%v stubborn=%v, want only stubborn", quitterTracked, stubbornTracked)
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	if got := drainTimeout(ShutdownTimeout); got != ShutdownTimeout-agent.StopTimeout {
+		t.Errorf("drainTimeout(%v) = %v, want %v", ShutdownTimeout, got, ShutdownTimeout-agent.StopTimeout)
+	}
+	if got := drainTimeout(4 * time.Second); got != 2*time.Second {
+		t.Errorf("drainTimeout(4s) = %v, want 2s", got)
+	}
+}
+
 func TestSupervisor_HandleStatus(t *testing.T) {
 	sup, cleanup := newTestSupervisor(t)
 	defer cleanup()
@@ -227,6 +313,130 @@ func TestSupervisor_HandleStatus(t *testing.T) {
 	}
 }
 
+func TestSupervisor_HandleWhoami(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "gh-token")
+	t.Setenv("LINEAR_API_KEY", "")
+
+	for _, p := range []*project.Project{
+		{Name: "tickets", IssueBackend: "tk"},
+		{Name: "hub", IssueBackend: "github"},
+		{Name: "lin", IssueBackend: "linear", LinearTeam: "ENG"},
+	} {
+		if _, err := sup.registry.Add("https://github.com/example/"+p.Name+".git", p.Name, 1, false, ""); err != nil {
+			t.Fatalf("Add(%s) error = %v", p.Name, err)
+		}
+		if err := sup.registry.SetConfigValue(p.Name, registry.ConfigKeyIssueBackend, p.IssueBackend); err != nil {
+			t.Fatalf("SetConfigValue(%s) error = %v", p.Name, err)
+		}
+		if p.LinearTeam != "" {
+			if err := sup.registry.SetConfigValue(p.Name, registry.ConfigKeyLinearTeam, p.LinearTeam); err != nil {
+				t.Fatalf("SetConfigValue(%s) error = %v", p.Name, err)
+			}
+		}
+	}
+
+	resp := sup.Handle(context.Background(), &daemon.Request{Type: daemon.MsgWhoami, ID: "test-1"})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	payload, ok := resp.Payload.(daemon.WhoamiResponse)
+	if !ok {
+		t.Fatalf("expected WhoamiResponse payload, got %T", resp.Payload)
+	}
+	if payload.PID != os.Getpid() || payload.UID != os.Getuid() || payload.Version != Version {
+		t.Errorf("unexpected identity: %+v", payload)
+	}
+	if payload.SocketPath == "" || payload.ConfigPath == "" {
+		t.Errorf("expected socket and config paths, got %+v", payload)
+	}
+
+	if len(payload.IssueAuth) != 3 {
+		t.Fatalf("got %d issue auth entries, want 3", len(payload.IssueAuth))
+	}
+	// Sorted by project name
+	hub, lin, tickets := payload.IssueAuth[0], payload.IssueAuth[1], payload.IssueAuth[2]
+	if !hub.OK || hub.Source != "GH_TOKEN" || len(hub.Fingerprint) != 8 {
+		t.Errorf("hub = %+v, want OK from GH_TOKEN with a fingerprint", hub)
+	}
+	if strings.Contains(hub.Fingerprint, "gh-token") {
+		t.Error("fingerprint must not reveal the token")
+	}
+	if lin.OK || !strings.Contains(lin.Error, "LINEAR_API_KEY") {
+		t.Errorf("lin = %+v, want missing LINEAR_API_KEY", lin)
+	}
+	if !tickets.OK || tickets.Source != "" {
+		t.Errorf("tickets = %+v, want OK without a token", tickets)
+	}
+}
+
+func TestSupervisor_HandleSetLogLevel(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+	t.Cleanup(func() { logging.SetLevel(slog.LevelInfo) })
+	logging.SetLevel(slog.LevelInfo)
+
+	setLevel := func(level string) *daemon.Response {
+		return sup.Handle(context.Background(), &daemon.Request{
+			Type:    daemon.MsgSetLogLevel,
+			ID:      "test-1",
+			Payload: daemon.SetLogLevelRequest{Level: level},
+		})
+	}
+
+	resp := setLevel("DEBUG")
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	payload, ok := resp.Payload.(daemon.SetLogLevelResponse)
+	if !ok {
+		t.Fatalf("expected SetLogLevelResponse payload, got %T", resp.Payload)
+	}
+	if payload.Level != "debug" || payload.Previous != "info" {
+		t.Errorf("payload = %+v, want info -> debug", payload)
+	}
+	if logging.Level() != slog.LevelDebug {
+		t.Errorf("logging.Level() = %v, want debug", logging.Level())
+	}
+
+	// An empty level reports without changing
+	resp = setLevel("")
+	if payload, _ := resp.Payload.(daemon.SetLogLevelResponse); !resp.Success || payload.Level != "debug" {
+		t.Errorf("empty level: resp = %+v, want the current level", resp)
+	}
+
+	resp = setLevel("trace")
+	if resp.Success || !strings.Contains(resp.Error, "invalid log level") {
+		t.Errorf("expected invalid level error, got %+v", resp)
+	}
+	if logging.Level() != slog.LevelDebug {
+		t.Errorf("logging.Level() = %v after a rejected level, want debug", logging.Level())
+	}
+}
+
+func TestResolveIssueAuth(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	t.Setenv("GH_TOKEN", "")
+
+	source, fp := ResolveIssueAuth("github", "", nil)
+	if source != "GITHUB_TOKEN" || fp == "" {
+		t.Errorf("ResolveIssueAuth(github) = %q, %q; want GITHUB_TOKEN and a fingerprint", source, fp)
+	}
+
+	// Config takes precedence over the environment
+	cfg := &config.GlobalConfig{Providers: config.ProvidersConfig{GitHub: &config.ProviderConfig{APIKey: "config-token"}}}
+	cfgSource, cfgFP := ResolveIssueAuth("gh", "", cfg)
+	if cfgSource != "config" || cfgFP == fp {
+		t.Errorf("ResolveIssueAuth(gh, cfg) = %q, %q; want config with a different fingerprint", cfgSource, cfgFP)
+	}
+
+	if source, fp := ResolveIssueAuth("tk", "", cfg); source != "" || fp != "" {
+		t.Errorf("ResolveIssueAuth(tk) = %q, %q; want empty", source, fp)
+	}
+}
+
 func TestSupervisor_HandleProjectAdd(t *testing.T) {
 	sup, cleanup := newTestSupervisor(t)
 	defer cleanup()
@@ -268,6 +478,182 @@ func TestSupervisor_HandleProjectAdd(t *testing.T) {
 	// Worktrees are now created on-demand when agents start, not upfront
 }
 
+func TestSupervisor_HandleProjectAddDetectsDefaultBranch(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	projDir, projCleanup := newTestGitRepo(t)
+	defer projCleanup()
+
+	// Use a default branch other than main
+	cmd := exec.Command("git", "branch", "-M", "develop")
+	cmd.Dir = projDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to rename branch: %v\n%s", err, output)
+	}
+
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type: daemon.MsgProjectAdd,
+		ID:   "test-1",
+		Payload: map[string]any{
+			"remote_url": "file://" + projDir,
+			"name":       "develop-project",
+		},
+	})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	branch, err := sup.registry.GetConfigValue("develop-project", registry.ConfigKeyDefaultBranch)
+	if err != nil {
+		t.Fatalf("GetConfigValue() error = %v", err)
+	}
+	if branch != "develop" {
+		t.Errorf("default-branch = %v, want develop", branch)
+	}
+}
+
+func TestSupervisor_HandleProjectAddShallow(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	projDir, projCleanup := newTestGitRepo(t)
+	defer projCleanup()
+
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type: daemon.MsgProjectAdd,
+		ID:   "test-1",
+		Payload: map[string]any{
+			"remote_url":    "file://" + projDir,
+			"name":          "shallow-project",
+			"depth":         1,
+			"single_branch": true,
+		},
+	})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--is-shallow-repository")
+	cmd.Dir = resp.Payload.(daemon.ProjectAddResponse).RepoDir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git rev-parse: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "true" {
+		t.Errorf("is-shallow-repository = %s, want true", got)
+	}
+
+	resp = sup.Handle(context.Background(), &daemon.Request{
+		Type: daemon.MsgProjectAdd,
+		ID:   "test-2",
+		Payload: map[string]any{
+			"remote_url": "file://" + projDir,
+			"name":       "bad-depth",
+			"depth":      -1,
+		},
+	})
+	if resp.Success || !strings.Contains(resp.Error, "depth") {
+		t.Errorf("negative depth: got %+v, want depth error", resp)
+	}
+}
+
+func TestCloneArgs(t *testing.T) {
+	tests := []struct {
+		req  daemon.ProjectAddRequest
+		want []string
+	}{
+		{daemon.ProjectAddRequest{RemoteURL: "url"}, []string{"clone", "url", "dir"}},
+		{daemon.ProjectAddRequest{RemoteURL: "url", Depth: 5}, []string{"clone", "--depth", "5", "--no-single-branch", "url", "dir"}},
+		{daemon.ProjectAddRequest{RemoteURL: "url", Depth: 1, SingleBranch: true}, []string{"clone", "--depth", "1", "--single-branch", "url", "dir"}},
+		{daemon.ProjectAddRequest{RemoteURL: "url", SingleBranch: true}, []string{"clone", "--single-branch", "url", "dir"}},
+	}
+	for _, tt := range tests {
+		if got := cloneArgs(tt.req, "dir"); !slices.Equal(got, tt.want) {
+			t.Errorf("cloneArgs(%+v) = %v, want %v", tt.req, got, tt.want)
+		}
+	}
+}
+
+func TestSupervisor_HandleProjectAddPreset(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+	sup.globalConfig = &config.GlobalConfig{Presets: map[string]map[string]any{
+		"service": {"max-agents": int64(7), "autostart": true, "issue-backend": "gh"},
+	}}
+
+	projDir, projCleanup := newTestGitRepo(t)
+	defer projCleanup()
+
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type: daemon.MsgProjectAdd,
+		ID:   "test-1",
+		Payload: map[string]any{
+			"remote_url": "file://" + projDir,
+			"name":       "preset-project",
+			"preset":     "service",
+			// Inline config overrides the preset
+			"config": map[string]string{"issue-backend": "tk"},
+		},
+	})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	payload := resp.Payload.(daemon.ProjectAddResponse)
+	if payload.MaxAgents != 7 {
+		t.Errorf("max_agents = %d, want 7", payload.MaxAgents)
+	}
+	if len(payload.Config) != 3 {
+		t.Errorf("config = %v, want 3 applied values", payload.Config)
+	}
+
+	proj, err := sup.registry.Get("preset-project")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !proj.Autostart {
+		t.Error("autostart should be set by the preset")
+	}
+	if proj.IssueBackend != "tk" {
+		t.Errorf("issue-backend = %q, want tk from inline config", proj.IssueBackend)
+	}
+}
+
+func TestSupervisor_HandleProjectAddRejectsBadConfig(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	projDir, projCleanup := newTestGitRepo(t)
+	defer projCleanup()
+
+	tests := []struct {
+		name    string
+		payload map[string]any
+		wantErr string
+	}{
+		{"unknown preset", map[string]any{"preset": "missing"}, "unknown preset"},
+		{"invalid key", map[string]any{"config": map[string]string{"max-agnets": "2"}}, "invalid config key"},
+		{"invalid value", map[string]any{"config": map[string]string{"max-agents": "lots"}}, "max-agents"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.payload["remote_url"] = "file://" + projDir
+			tt.payload["name"] = "bad-config"
+			resp := sup.Handle(context.Background(), &daemon.Request{Type: daemon.MsgProjectAdd, Payload: tt.payload})
+			if resp.Success {
+				t.Fatal("expected failure")
+			}
+			if !strings.Contains(resp.Error, tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", resp.Error, tt.wantErr)
+			}
+			if _, err := sup.registry.Get("bad-config"); err == nil {
+				t.Error("project should not be registered")
+			}
+		})
+	}
+}
+
 func TestSupervisor_HandleProjectList(t *testing.T) {
 	sup, cleanup := newTestSupervisor(t)
 	defer cleanup()
@@ -355,6 +741,44 @@ func TestSupervisor_HandleProjectRemove(t *testing.T) {
 	}
 }
 
+func TestSupervisor_HandleProjectRemove_Running(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	proj, err := sup.registry.Add("git@github.com:user/proj.git", "proj", 1, false, "")
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	sup.agents.RegisterProject(proj)
+	sup.mu.Lock()
+	sup.orchestrators["proj"] = orchestrator.New(proj, sup.agents, orchestrator.DefaultConfig())
+	sup.mu.Unlock()
+
+	remove := func(force bool) *daemon.Response {
+		return sup.Handle(context.Background(), &daemon.Request{
+			Type:    daemon.MsgProjectRemove,
+			Payload: daemon.ProjectRemoveRequest{Name: "proj", Force: force},
+		})
+	}
+
+	if resp := remove(false); resp.Success || !strings.Contains(resp.Error, "is running") {
+		t.Fatalf("remove without force = %+v, want a running-project error", resp)
+	}
+	if _, err := sup.registry.Get("proj"); err != nil {
+		t.Fatal("refused removal should keep the project")
+	}
+
+	if resp := remove(true); !resp.Success {
+		t.Fatalf("remove with force failed: %s", resp.Error)
+	}
+	if sup.getOrchestrator("proj") != nil {
+		t.Error("forced removal should stop the orchestrator")
+	}
+	if _, err := sup.registry.Get("proj"); err == nil {
+		t.Error("forced removal should remove the project")
+	}
+}
+
 func TestSupervisor_HandleAgentList(t *testing.T) {
 	sup, cleanup := newTestSupervisor(t)
 	defer cleanup()
@@ -380,6 +804,58 @@ func TestSupervisor_HandleAgentList(t *testing.T) {
 	}
 }
 
+func TestSupervisor_HandleAgentList_Sorted(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	sup.agents.RegisterProject(&project.Project{Name: "proj"})
+	now := time.Now()
+	for _, info := range []agent.HydrateInfo{
+		{ID: "done01", State: agent.StateDone, StartedAt: now.Add(-3 * time.Minute)},
+		{ID: "run01", State: agent.StateRunning, StartedAt: now.Add(-1 * time.Minute)},
+		{ID: "idle01", State: agent.StateIdle, StartedAt: now.Add(-2 * time.Minute)},
+		{ID: "ask01", State: agent.StateRunning, StartedAt: now},
+	} {
+		info.Project = "proj"
+		info.Backend = "claude"
+		info.Worktree = t.TempDir()
+		if _, err := sup.agents.Hydrate(info); err != nil {
+			t.Fatalf("Hydrate(%s) error = %v", info.ID, err)
+		}
+	}
+	sup.permissions.Add(&daemon.PermissionRequest{AgentID: "ask01", Project: "proj", ToolName: "Bash"})
+
+	list := func(sortBy, order string) *daemon.Response {
+		return sup.Handle(context.Background(), &daemon.Request{
+			Type:    daemon.MsgAgentList,
+			ID:      "test-1",
+			Payload: map[string]any{"sort_by": sortBy, "order": order},
+		})
+	}
+	ids := func(resp *daemon.Response) string {
+		t.Helper()
+		if !resp.Success {
+			t.Fatalf("agent.list failed: %s", resp.Error)
+		}
+		var got []string
+		for _, a := range resp.Payload.(daemon.AgentListResponse).Agents {
+			got = append(got, a.ID)
+		}
+		return strings.Join(got, ",")
+	}
+
+	if got := ids(list(daemon.AgentSortState, "")); got != "ask01,idle01,run01,done01" {
+		t.Errorf("sorted by state = %s, want ask01,idle01,run01,done01", got)
+	}
+	if got := ids(list(daemon.AgentSortStarted, daemon.SortDescending)); got != "ask01,run01,idle01,done01" {
+		t.Errorf("sorted by started desc = %s, want ask01,run01,idle01,done01", got)
+	}
+
+	if resp := list("color", ""); resp.Success {
+		t.Error("expected an error for an unknown sort key")
+	}
+}
+
 func TestSupervisor_HandleUnknownType(t *testing.T) {
 	sup, cleanup := newTestSupervisor(t)
 	defer cleanup()
@@ -517,6 +993,65 @@ func TestSupervisor_HandleStop(t *testing.T) {
 	}
 }
 
+func TestSupervisor_HandleStartStopAllReportsResults(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	projDir, projCleanup := newTestGitRepo(t)
+	defer projCleanup()
+
+	for _, name := range []string{"all-a", "all-b"} {
+		resp := sup.Handle(context.Background(), &daemon.Request{
+			Type: daemon.MsgProjectAdd,
+			Payload: map[string]any{
+				"remote_url": "file://" + projDir,
+				"name":       name,
+			},
+		})
+		if !resp.Success {
+			t.Fatalf("add %s: %s", name, resp.Error)
+		}
+	}
+
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgStart,
+		Payload: map[string]any{"all": true},
+	})
+	if !resp.Success {
+		t.Fatalf("start --all failed: %s", resp.Error)
+	}
+	startResp := resp.Payload.(daemon.StartResponse)
+	if len(startResp.Results) != 2 {
+		t.Fatalf("expected 2 start results, got %+v", startResp.Results)
+	}
+	for _, r := range startResp.Results {
+		if !r.Started || r.Error != "" {
+			t.Errorf("expected %s to start, got %+v", r.Project, r)
+		}
+	}
+
+	// Stop one project so stop --all reports it as not running
+	sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgStop,
+		Payload: map[string]any{"project": "all-a"},
+	})
+
+	resp = sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgStop,
+		Payload: map[string]any{"all": true},
+	})
+	if !resp.Success {
+		t.Fatalf("stop --all failed: %s", resp.Error)
+	}
+	stopped := make(map[string]bool)
+	for _, r := range resp.Payload.(daemon.StopResponse).Results {
+		stopped[r.Project] = r.Stopped
+	}
+	if stopped["all-a"] || !stopped["all-b"] {
+		t.Errorf("unexpected stop results: %v", stopped)
+	}
+}
+
 func TestSupervisor_HandleProjectSet(t *testing.T) {
 	sup, cleanup := newTestSupervisor(t)
 	defer cleanup()
@@ -604,6 +1139,26 @@ func TestSupervisor_HandleAgentInputNotFound(t *testing.T) {
 	}
 }
 
+func TestSupervisor_HandleAgentTranscriptExportNotFound(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	req := &daemon.Request{
+		Type: daemon.MsgAgentTranscriptExport,
+		ID:   "test-1",
+		Payload: map[string]any{
+			"id":    "nonexistent",
+			"write": true,
+		},
+	}
+
+	resp := sup.Handle(context.Background(), req)
+
+	if resp.Success {
+		t.Error("expected error for nonexistent agent")
+	}
+}
+
 func TestSupervisor_HandleAgentCreateNoProject(t *testing.T) {
 	sup, cleanup := newTestSupervisor(t)
 	defer cleanup()
@@ -622,3 +1177,631 @@ func TestSupervisor_HandleAgentCreateNoProject(t *testing.T) {
 		t.Error("expected error for nonexistent project")
 	}
 }
+
+func TestSupervisor_HandleAgentCreateInvalidRole(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgAgentCreate,
+		ID:      "test-1",
+		Payload: daemon.AgentCreateRequest{Project: "demo", Role: "admin"},
+	})
+	if resp.Success || !strings.Contains(resp.Error, "invalid role") {
+		t.Errorf("Handle() = %+v, want invalid role error", resp)
+	}
+}
+
+func TestSupervisor_HandleAgentDelegateRequiresOrchestrator(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	if got := sup.delegatorProject("manager:myproj"); got != "myproj" {
+		t.Errorf("delegatorProject(manager:myproj) = %q, want myproj", got)
+	}
+
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgAgentDelegate,
+		ID:      "test-1",
+		Payload: map[string]any{"ticket_id": "42", "delegated_by": "manager:myproj"},
+	})
+	if resp.Success {
+		t.Fatal("expected error without a running orchestrator")
+	}
+	if !strings.Contains(resp.Error, "orchestrator not running") {
+		t.Errorf("error = %q, want orchestrator not running", resp.Error)
+	}
+}
+
+func TestSupervisor_GetProjectManagerCreatesWorktreeOutsideLock(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	if _, err := sup.registry.Add("git@github.com:user/slow.git", "slow", 0, false, ""); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	// Simulate a slow `git worktree add` that blocks until released
+	var calls atomic.Int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	sup.createManagerWorktree = func(*project.Project) error {
+		if calls.Add(1) == 1 {
+			close(entered)
+		}
+		<-release
+		return nil
+	}
+
+	type result struct {
+		mgr *manager.Manager
+		err error
+	}
+	results := make(chan result, 2)
+	for range 2 {
+		go func() {
+			mgr, err := sup.getProjectManager("slow")
+			results <- result{mgr, err}
+		}()
+	}
+
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worktree creation never started")
+	}
+
+	// Status calls must not wait on the in-progress worktree creation
+	for i := range 5 {
+		done := make(chan *daemon.Response, 1)
+		go func() {
+			done <- sup.Handle(context.Background(), &daemon.Request{
+				Type:    daemon.MsgManagerStatus,
+				ID:      "status",
+				Payload: map[string]any{"project": "slow"},
+			})
+		}()
+		select {
+		case resp := <-done:
+			if !resp.Success {
+				t.Fatalf("status %d failed: %s", i, resp.Error)
+			}
+		case <-time.After(time.Second):
+			close(release)
+			t.Fatalf("status %d blocked behind worktree creation", i)
+		}
+	}
+
+	close(release)
+
+	first := <-results
+	second := <-results
+	if first.err != nil || second.err != nil {
+		t.Fatalf("getProjectManager() errors = %v, %v", first.err, second.err)
+	}
+	if first.mgr != second.mgr {
+		t.Error("concurrent callers got different managers")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("worktree created %d times, want 1", got)
+	}
+}
+
+func TestSupervisor_PermissionRequestHeadless(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+	t.Setenv("FAB_DIR", t.TempDir()) // keep real permission rules out of the way
+
+	// No server is attached, so the headless policy decides
+	sup.headless = rules.HeadlessConfig{
+		Fallback:        rules.HeadlessDeny,
+		AllowedPatterns: []string{"go test:*"},
+		AllowedTools:    []string{"Read"},
+	}
+
+	tests := []struct {
+		name     string
+		tool     string
+		input    string
+		behavior string
+	}{
+		{"allowed tool", "Read", `{"file_path":"/tmp/x"}`, "allow"},
+		{"allowed command", "Bash", `{"command":"go test ./..."}`, "allow"},
+		{"chained command", "Bash", `{"command":"go test ./... && rm -rf /"}`, "deny"},
+		{"other tool", "Write", `{"file_path":"/tmp/x"}`, "deny"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			resp := sup.Handle(ctx, &daemon.Request{
+				Type: daemon.MsgPermissionRequest,
+				ID:   "req-1",
+				Payload: daemon.PermissionRequestPayload{
+					ToolName:  tt.tool,
+					ToolInput: []byte(tt.input),
+				},
+			})
+			if !resp.Success {
+				t.Fatalf("Handle() error = %s", resp.Error)
+			}
+			permResp, ok := resp.Payload.(*daemon.PermissionResponse)
+			if !ok {
+				t.Fatalf("payload = %T, want *daemon.PermissionResponse", resp.Payload)
+			}
+			if permResp.Behavior != tt.behavior {
+				t.Errorf("Behavior = %q, want %q", permResp.Behavior, tt.behavior)
+			}
+		})
+	}
+	if got := len(sup.permissions.List()); got != 0 {
+		t.Errorf("pending permissions = %d, want 0", got)
+	}
+}
+
+func TestSupervisor_PermissionRequestToolTimeout(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+	t.Setenv("FAB_DIR", t.TempDir())
+
+	// Held with no TUI attached, the request waits out the tool's timeout
+	sup.globalConfig = &config.GlobalConfig{PermissionTimeouts: map[string]string{"Write": "50ms"}}
+
+	start := time.Now()
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type: daemon.MsgPermissionRequest,
+		ID:   "req-1",
+		Payload: daemon.PermissionRequestPayload{
+			ToolName:  "Write",
+			ToolInput: []byte(`{"file_path":"/tmp/x"}`),
+		},
+	})
+	if resp.Success || !strings.Contains(resp.Error, "timed out") {
+		t.Errorf("Handle() = %+v, want a timeout error", resp)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request took %v, want it to expire after the Write timeout", elapsed)
+	}
+	if got := len(sup.permissions.List()); got != 0 {
+		t.Errorf("pending permissions = %d, want 0 after timeout", got)
+	}
+}
+
+func TestSupervisor_PermissionRespondRemember(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+	t.Setenv("FAB_DIR", t.TempDir())
+
+	input := []byte(`{"command":"make lint"}`)
+	id, _ := sup.permissions.Add(&daemon.PermissionRequest{
+		Project:   "demo",
+		ToolName:  "Bash",
+		ToolInput: input,
+	})
+
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type: daemon.MsgPermissionRespond,
+		ID:   "req-1",
+		Payload: daemon.PermissionRespondPayload{
+			ID:       id,
+			Behavior: "allow",
+			Remember: true,
+		},
+	})
+	if !resp.Success {
+		t.Fatalf("Handle() error = %s", resp.Error)
+	}
+
+	log := slog.Default()
+	permReq := daemon.PermissionRequestPayload{ToolName: "Bash", ToolInput: input}
+	got := sup.ruleDecision(context.Background(), "demo", "", permReq, log)
+	if got == nil || got.Behavior != "allow" {
+		t.Fatalf("ruleDecision() = %+v, want allow", got)
+	}
+
+	permReq.ToolInput = []byte(`{"command":"make clean"}`)
+	if got := sup.ruleDecision(context.Background(), "demo", "", permReq, log); got != nil {
+		t.Errorf("ruleDecision() = %+v for a different command, want nil", got)
+	}
+}
+
+func TestSupervisor_PermissionRequestRules(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	t.Setenv("FAB_DIR", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	perms := `
+[[rules]]
+tool = "Bash"
+action = "deny"
+pattern = "rm :*"
+
+[[rules]]
+tool = "Bash"
+action = "allow"
+pattern = "go build:*"
+`
+	if err := os.WriteFile(filepath.Join(dir, "config", "permissions.toml"), []byte(perms), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		command  string
+		behavior string
+	}{
+		{"allow rule", "go build ./...", "allow"},
+		{"deny rule", "rm -rf build", "deny"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			resp := sup.Handle(ctx, &daemon.Request{
+				Type: daemon.MsgPermissionRequest,
+				ID:   "req-1",
+				Payload: daemon.PermissionRequestPayload{
+					ToolName:  "Bash",
+					ToolInput: []byte(`{"command":"` + tt.command + `"}`),
+				},
+			})
+			if !resp.Success {
+				t.Fatalf("Handle() error = %s", resp.Error)
+			}
+			permResp, ok := resp.Payload.(*daemon.PermissionResponse)
+			if !ok {
+				t.Fatalf("payload = %T, want *daemon.PermissionResponse", resp.Payload)
+			}
+			if permResp.Behavior != tt.behavior {
+				t.Errorf("Behavior = %q, want %q", permResp.Behavior, tt.behavior)
+			}
+		})
+	}
+	if got := len(sup.permissions.List()); got != 0 {
+		t.Errorf("pending permissions = %d, want 0 (rule decisions should not be broadcast)", got)
+	}
+}
+
+func TestSupervisor_PermissionRequestReviewerRole(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	// Rules that would allow everything a reviewer must not do
+	dir := t.TempDir()
+	t.Setenv("FAB_DIR", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	perms := `
+[[rules]]
+tool = "Edit"
+action = "allow"
+
+[[rules]]
+tool = "Bash"
+action = "allow"
+patterns = ["rm :*", "git log:*"]
+`
+	if err := os.WriteFile(filepath.Join(dir, "config", "permissions.toml"), []byte(perms), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sup.agents.RegisterProject(&project.Project{Name: "proj"})
+	if _, err := sup.agents.Hydrate(agent.HydrateInfo{
+		ID:        "rev001",
+		Project:   "proj",
+		State:     agent.StateRunning,
+		Worktree:  t.TempDir(),
+		StartedAt: time.Now(),
+		Role:      rules.RoleReviewer,
+	}); err != nil {
+		t.Fatalf("Hydrate() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tool     string
+		input    string
+		behavior string
+	}{
+		{"edit", "Edit", `{"file_path":"main.go"}`, "deny"},
+		{"write command", "Bash", `{"command":"rm -rf build"}`, "deny"},
+		{"chained command", "Bash", `{"command":"git log; rm -rf build"}`, "deny"},
+		{"read-only command", "Bash", `{"command":"git log -5"}`, "allow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			resp := sup.Handle(ctx, &daemon.Request{
+				Type: daemon.MsgPermissionRequest,
+				ID:   "req-1",
+				Payload: daemon.PermissionRequestPayload{
+					AgentID:   "rev001",
+					ToolName:  tt.tool,
+					ToolInput: []byte(tt.input),
+				},
+			})
+			if !resp.Success {
+				t.Fatalf("Handle() error = %s", resp.Error)
+			}
+			permResp, ok := resp.Payload.(*daemon.PermissionResponse)
+			if !ok {
+				t.Fatalf("payload = %T, want *daemon.PermissionResponse", resp.Payload)
+			}
+			if permResp.Behavior != tt.behavior {
+				t.Errorf("Behavior = %q, want %q", permResp.Behavior, tt.behavior)
+			}
+		})
+	}
+}
+
+func TestSupervisor_MetricsGauges(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	sup.agents.RegisterProject(&project.Project{Name: "proj"})
+	for _, id := range []string{"run001", "run002"} {
+		if _, err := sup.agents.Hydrate(agent.HydrateInfo{
+			ID:        id,
+			Project:   "proj",
+			State:     agent.StateRunning,
+			Worktree:  t.TempDir(),
+			StartedAt: time.Now(),
+		}); err != nil {
+			t.Fatalf("Hydrate() error = %v", err)
+		}
+	}
+	sup.permissions.Add(&daemon.PermissionRequest{AgentID: "run001", ToolName: "Bash"})
+
+	values := make(map[string]float64)
+	for _, s := range sup.MetricsGauges() {
+		key := s.Name
+		for _, l := range s.Labels {
+			key += "/" + l.Value
+		}
+		values[key] = s.Value
+	}
+	want := map[string]float64{
+		"fab_agents/running":      2,
+		"fab_agents/idle":         0,
+		"fab_active_projects":     0,
+		"fab_pending_permissions": 1,
+	}
+	for key, v := range want {
+		if got, ok := values[key]; !ok || got != v {
+			t.Errorf("%s = %v (present %v), want %v", key, got, ok, v)
+		}
+	}
+}
+
+func TestSupervisor_Notes(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	if _, err := sup.registry.Add("git@github.com:user/proj.git", "proj", 1, false, ""); err != nil {
+		t.Fatalf("failed to add project: %v", err)
+	}
+	sup.agents.RegisterProject(&project.Project{Name: "proj"})
+	a, err := sup.agents.Hydrate(agent.HydrateInfo{
+		ID:        "note01",
+		Project:   "proj",
+		State:     agent.StateRunning,
+		Worktree:  t.TempDir(),
+		StartedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Hydrate() error = %v", err)
+	}
+	a.SetTask("FAB-7")
+
+	appends := []daemon.NoteAppendRequest{
+		{AgentID: "note01", Text: "integration tests need docker"},
+		{Project: "proj", Text: "from the shell"},
+		{AgentID: ManagerAgentID + ":proj", Text: "from the manager"},
+	}
+	for _, p := range appends {
+		resp := sup.Handle(context.Background(), &daemon.Request{Type: daemon.MsgNoteAppend, Payload: p})
+		if !resp.Success {
+			t.Fatalf("note.append %+v failed: %s", p, resp.Error)
+		}
+	}
+
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgNoteRead,
+		Payload: daemon.NoteReadRequest{AgentID: "note01"},
+	})
+	if !resp.Success {
+		t.Fatalf("note.read failed: %s", resp.Error)
+	}
+	notes, ok := resp.Payload.(daemon.NoteReadResponse)
+	if !ok {
+		t.Fatalf("payload type = %T, want NoteReadResponse", resp.Payload)
+	}
+	if notes.Project != "proj" || filepath.Base(notes.Path) != project.NotesFile {
+		t.Errorf("project = %q, path = %q", notes.Project, notes.Path)
+	}
+	for _, want := range []string{
+		"note01 (FAB-7)\n\nintegration tests need docker",
+		"user\n\nfrom the shell",
+		ManagerAgentID + ":proj\n\nfrom the manager",
+	} {
+		if !strings.Contains(notes.Content, want) {
+			t.Errorf("notes missing %q:\n%s", want, notes.Content)
+		}
+	}
+
+	resp = sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgNoteAppend,
+		Payload: daemon.NoteAppendRequest{Text: "nowhere"},
+	})
+	if resp.Success {
+		t.Error("note.append without an agent or project succeeded, want error")
+	}
+}
+
+func TestSupervisor_HandleAgentGet(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	worktree := t.TempDir()
+	if out, err := exec.Command("git", "init", worktree).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(worktree, "new.go"), []byte("package x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	proj := &project.Project{Name: "proj"}
+	sup.agents.RegisterProject(proj)
+	if _, err := sup.agents.Hydrate(agent.HydrateInfo{
+		ID:        "get001",
+		Project:   "proj",
+		State:     agent.StateRunning,
+		Worktree:  worktree,
+		StartedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("Hydrate() error = %v", err)
+	}
+
+	orch := orchestrator.New(proj, sup.agents, orchestrator.DefaultConfig())
+	_ = orch.Claims().Claim("FAB-9", "get001")
+	_ = orch.Claims().Claim("FAB-10", "other")
+	sup.mu.Lock()
+	sup.orchestrators["proj"] = orch
+	sup.mu.Unlock()
+
+	sup.permissions.Add(&daemon.PermissionRequest{AgentID: "get001", Project: "proj", ToolName: "Bash"})
+	sup.heartbeat.RecordOutput("get001")
+
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgAgentGet,
+		Payload: daemon.AgentGetRequest{ID: "get001"},
+	})
+	if !resp.Success {
+		t.Fatalf("agent.get failed: %s", resp.Error)
+	}
+	got, ok := resp.Payload.(daemon.AgentGetResponse)
+	if !ok {
+		t.Fatalf("payload type = %T, want AgentGetResponse", resp.Payload)
+	}
+	if got.Agent.ID != "get001" || got.Agent.State != string(agent.StateRunning) {
+		t.Errorf("Agent = %+v", got.Agent)
+	}
+	if !got.WorktreeDirty {
+		t.Error("WorktreeDirty = false, want true with an untracked file")
+	}
+	if len(got.Claims) != 1 || got.Claims[0] != "FAB-9" {
+		t.Errorf("Claims = %v, want [FAB-9]", got.Claims)
+	}
+	if got.PendingRequests != 1 {
+		t.Errorf("PendingRequests = %d, want 1", got.PendingRequests)
+	}
+	if got.LastActivity.IsZero() {
+		t.Error("LastActivity is zero, want the recorded output time")
+	}
+
+	resp = sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgAgentGet,
+		Payload: daemon.AgentGetRequest{ID: "missing"},
+	})
+	if resp.Success {
+		t.Error("agent.get for an unknown agent succeeded, want error")
+	}
+}
+
+func TestSupervisor_HandleDashboard(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	sup.agents.RegisterProject(&project.Project{Name: "proj"})
+	if _, err := sup.agents.Hydrate(agent.HydrateInfo{
+		ID:        "dash01",
+		Project:   "proj",
+		State:     agent.StateRunning,
+		Worktree:  t.TempDir(),
+		StartedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("Hydrate() error = %v", err)
+	}
+	sup.permissions.Add(&daemon.PermissionRequest{AgentID: "dash01", Project: "proj", ToolName: "Bash"})
+
+	// The manager section fails for an unknown project; the rest still load
+	resp := sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgDashboard,
+		Payload: daemon.DashboardRequest{SortBy: daemon.AgentSortStarted, Manager: "missing"},
+	})
+	if !resp.Success {
+		t.Fatalf("dashboard failed: %s", resp.Error)
+	}
+	got, ok := resp.Payload.(daemon.DashboardResponse)
+	if !ok {
+		t.Fatalf("payload type = %T, want DashboardResponse", resp.Payload)
+	}
+	if len(got.Agents) != 1 || got.Agents[0].ID != "dash01" || !got.Agents[0].NeedsAttention {
+		t.Errorf("Agents = %+v, want dash01 needing attention", got.Agents)
+	}
+	if len(got.Permissions) != 1 || got.Permissions[0].AgentID != "dash01" {
+		t.Errorf("Permissions = %+v, want the pending request", got.Permissions)
+	}
+	if got.Status == nil || got.Status.Supervisor.TotalAgents != 1 || got.Status.Daemon.Version != Version {
+		t.Errorf("Status = %+v, want one agent and the daemon version", got.Status)
+	}
+	if got.Manager != nil {
+		t.Errorf("Manager = %+v, want nil after the section failed", got.Manager)
+	}
+	if len(got.Errors) != 1 || !strings.Contains(got.Errors[daemon.DashboardManager], "project not found") {
+		t.Errorf("Errors = %v, want only the manager section's error", got.Errors)
+	}
+
+	resp = sup.Handle(context.Background(), &daemon.Request{
+		Type:    daemon.MsgDashboard,
+		Payload: daemon.DashboardRequest{SortBy: "bogus"},
+	})
+	if resp.Success {
+		t.Error("dashboard with an invalid sort succeeded, want error")
+	}
+}
+
+func TestSupervisor_HandleManagerListAll(t *testing.T) {
+	sup, cleanup := newTestSupervisor(t)
+	defer cleanup()
+
+	list := func() []daemon.ManagerStatusResponse {
+		t.Helper()
+		resp := sup.Handle(context.Background(), &daemon.Request{Type: daemon.MsgManagerList, ID: "test-1"})
+		if !resp.Success {
+			t.Fatalf("manager.list failed: %s", resp.Error)
+		}
+		payload, ok := resp.Payload.(daemon.ManagerListResponse)
+		if !ok {
+			t.Fatalf("expected ManagerListResponse payload, got %T", resp.Payload)
+		}
+		return payload.Managers
+	}
+
+	if managers := list(); len(managers) != 0 {
+		t.Fatalf("manager.list = %+v, want none before any manager starts", managers)
+	}
+
+	sup.mu.Lock()
+	for _, name := range []string{"zeta", "alpha"} {
+		sup.managers[name] = manager.New(filepath.Join("/tmp", name), name, &backend.ClaudeBackend{}, nil)
+	}
+	sup.mu.Unlock()
+
+	managers := list()
+	if len(managers) != 2 || managers[0].Project != "alpha" || managers[1].Project != "zeta" {
+		t.Fatalf("manager.list = %+v, want alpha then zeta", managers)
+	}
+	if m := managers[0]; m.Running || m.State != string(manager.StateStopped) || m.WorkDir != "/tmp/alpha" || m.StartedAt != "" {
+		t.Errorf("alpha = %+v, want a stopped manager in /tmp/alpha", m)
+	}
+}
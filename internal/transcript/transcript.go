@@ -0,0 +1,243 @@
+// Package transcript renders agent chat history for display and export.
+// The TUI uses its tool summaries and path helpers; the daemon uses it to
+// export a conversation as a markdown document.
+package transcript
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is a single chat history entry to render.
+type Entry struct {
+	Role       string // "assistant", "user", "tool"
+	Content    string // Message text (assistant and user entries)
+	ToolName   string // Tool invoked (tool call entries)
+	ToolInput  string // Tool input summary (tool call entries)
+	ToolResult string // Tool output (tool result entries)
+	IsError    bool   // True if the tool result is an error
+	Timestamp  time.Time
+}
+
+// Options controls markdown rendering.
+type Options struct {
+	Title          string // Document heading (default: "Transcript")
+	AssistantLabel string // Heading for assistant turns (default: "Assistant")
+	Worktree       string // Paths under this directory are shown relative to it
+}
+
+// Markdown renders entries as a markdown document. Assistant and user turns
+// become sections; each tool call becomes a collapsible <details> block
+// holding its input and result.
+func Markdown(entries []Entry, opts Options) string {
+	title := opts.Title
+	if title == "" {
+		title = "Transcript"
+	}
+	assistant := opts.AssistantLabel
+	if assistant == "" {
+		assistant = "Assistant"
+	}
+
+	var b strings.Builder
+	b.WriteString("# " + title + "\n")
+
+	for i := 0; i < len(entries); i++ {
+		e := entries[i]
+		switch e.Role {
+		case "assistant":
+			writeTurn(&b, assistant, e)
+		case "user":
+			writeTurn(&b, "User", e)
+		case "tool":
+			call := e
+			// Results usually arrive as a separate entry right after the call
+			if call.ToolName != "" && call.ToolResult == "" && i+1 < len(entries) {
+				if next := entries[i+1]; next.Role == "tool" && next.ToolName == "" {
+					call.ToolResult = next.ToolResult
+					call.IsError = next.IsError
+					i++
+				}
+			}
+			writeTool(&b, call, opts.Worktree)
+		default:
+			if e.Content != "" {
+				b.WriteString("\n" + e.Content + "\n")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// writeTurn writes an assistant or user turn as a section.
+func writeTurn(b *strings.Builder, who string, e Entry) {
+	heading := who
+	if !e.Timestamp.IsZero() {
+		heading += " · " + e.Timestamp.Format("2006-01-02 15:04:05")
+	}
+	b.WriteString("\n## " + heading + "\n\n")
+	b.WriteString(strings.TrimSpace(e.Content) + "\n")
+}
+
+// writeTool writes a tool call and its result as a collapsible block.
+func writeTool(b *strings.Builder, e Entry, worktree string) {
+	name := e.ToolName
+	if name == "" {
+		name = "tool result"
+	}
+	summary := "<code>" + escapeHTML(name) + "</code>"
+	if input := TruncateToolInput(ShortenPath(e.ToolInput, worktree)); input != "" {
+		summary += " " + escapeHTML(input)
+	}
+	if e.ToolResult != "" {
+		result := SummarizeResult(e.ToolName, e.ToolResult)
+		if e.IsError {
+			result = "error"
+		}
+		summary += " — " + escapeHTML(result)
+	}
+
+	b.WriteString("\n<details>\n<summary>" + summary + "</summary>\n")
+	if e.ToolInput != "" {
+		b.WriteString("\n**Input**\n\n")
+		writeFenced(b, ShortenPathsInLine(e.ToolInput, worktree))
+	}
+	if e.ToolResult != "" {
+		label := "**Result**"
+		if e.IsError {
+			label = "**Result (error)**"
+		}
+		b.WriteString("\n" + label + "\n\n")
+		writeFenced(b, ShortenPathsInLine(e.ToolResult, worktree))
+	}
+	b.WriteString("\n</details>\n")
+}
+
+// writeFenced writes text as a code block, using a fence longer than any
+// backtick run inside it so the block can't be closed early.
+func writeFenced(b *strings.Builder, text string) {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	b.WriteString(fence + "\n" + strings.TrimRight(text, "\n") + "\n" + fence + "\n")
+}
+
+// escapeHTML escapes text placed inside HTML tags such as <summary>.
+func escapeHTML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// SummarizeResult returns a one-line summary of a tool result. Read and
+// Grep results are reduced to line and match counts; anything else is
+// described by its length.
+func SummarizeResult(toolName, result string) string {
+	if summary, ok := SummarizeToolResult(toolName, result); ok {
+		return summary
+	}
+	return FormatLineCount(countLines(result))
+}
+
+// SummarizeToolResult returns a one-line summary for tools whose output is
+// better described than shown: line counts for Read and match counts for
+// Grep. ok is false for other tools.
+func SummarizeToolResult(toolName, result string) (summary string, ok bool) {
+	switch toolName {
+	case "Read":
+		return "Read " + FormatLineCount(countLines(result)), true
+
+	case "Grep":
+		// Count non-empty lines (matches) in the result
+		matchCount := 0
+		for _, line := range strings.Split(result, "\n") {
+			if strings.TrimSpace(line) != "" {
+				matchCount++
+			}
+		}
+		if matchCount == 0 {
+			return "No matches", true
+		}
+		return FormatMatchCount(matchCount), true
+
+	default:
+		return "", false
+	}
+}
+
+// countLines counts lines in s, including a final line with no newline.
+func countLines(s string) int {
+	n := strings.Count(s, "\n")
+	if !strings.HasSuffix(s, "\n") && len(s) > 0 {
+		n++
+	}
+	return n
+}
+
+// TruncateToolInput flattens tool input to a single line and truncates it
+// for display.
+func TruncateToolInput(input string) string {
+	// Remove newlines for single-line display
+	input = strings.ReplaceAll(input, "\n", " ")
+	input = strings.TrimSpace(input)
+
+	const maxLen = 80
+	if len(input) > maxLen {
+		return input[:maxLen-3] + "..."
+	}
+	return input
+}
+
+// FormatLineCount formats a line count for display.
+func FormatLineCount(count int) string {
+	if count == 1 {
+		return "1 line"
+	}
+	return strconv.Itoa(count) + " lines"
+}
+
+// FormatMatchCount formats a match count for display.
+func FormatMatchCount(count int) string {
+	if count == 1 {
+		return "1 match"
+	}
+	return strconv.Itoa(count) + " matches"
+}
+
+// ShortenPath replaces a worktree prefix in a path with "./" for more readable display.
+// If the path doesn't start with the worktree prefix, it's returned unchanged.
+func ShortenPath(path, worktree string) string {
+	if worktree == "" || path == "" {
+		return path
+	}
+	// Ensure worktree has no trailing slash for consistent matching
+	worktree = strings.TrimSuffix(worktree, "/")
+	// Check for exact match (path is the worktree dir itself)
+	if path == worktree {
+		return "."
+	}
+	// Check for prefix with trailing slash
+	prefix := worktree + "/"
+	if strings.HasPrefix(path, prefix) {
+		return "./" + path[len(prefix):]
+	}
+	return path
+}
+
+// ShortenPathsInLine shortens any absolute paths under worktree within text.
+// This handles common output formats like grep results (path:line:content).
+func ShortenPathsInLine(line, worktree string) string {
+	if worktree == "" {
+		return line
+	}
+	prefix := strings.TrimSuffix(worktree, "/") + "/"
+	// Replace all occurrences of the worktree prefix with "./"
+	return strings.ReplaceAll(line, prefix, "./")
+}
+
+// Filename returns the default file name for an exported transcript.
+func Filename(agentID string) string {
+	return fmt.Sprintf("transcript-%s.md", agentID)
+}
@@ -0,0 +1,171 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarkdown(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	entries := []Entry{
+		{Role: "user", Content: "Fix the bug", Timestamp: ts},
+		{Role: "assistant", Content: "Looking at main.go now."},
+		{Role: "tool", ToolName: "Read", ToolInput: "/wt/main.go"},
+		{Role: "tool", ToolResult: "package main\nfunc main() {}\n"},
+		{Role: "tool", ToolName: "Bash", ToolInput: "go test ./..."},
+		{Role: "tool", ToolResult: "FAIL: contains ``` fences", IsError: true},
+	}
+
+	md := Markdown(entries, Options{Title: "Transcript: abc123", AssistantLabel: "Claude", Worktree: "/wt"})
+
+	for _, want := range []string{
+		"# Transcript: abc123\n",
+		"## User · 2026-01-02 15:04:05\n\nFix the bug\n",
+		"## Claude\n\nLooking at main.go now.\n",
+		"<summary><code>Read</code> ./main.go — Read 2 lines</summary>",
+		"<summary><code>Bash</code> go test ./... — error</summary>",
+		"**Result (error)**",
+		"````\nFAIL: contains ``` fences\n````",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q in:\n%s", want, md)
+		}
+	}
+
+	// Each call and its result share one block
+	if got := strings.Count(md, "<details>"); got != 2 {
+		t.Errorf("got %d <details> blocks, want 2:\n%s", got, md)
+	}
+}
+
+func TestMarkdownEscapesSummary(t *testing.T) {
+	md := Markdown([]Entry{{Role: "tool", ToolName: "Bash", ToolInput: "echo <b>"}}, Options{})
+	if !strings.Contains(md, "echo &lt;b&gt;</summary>") {
+		t.Errorf("summary should escape HTML, got:\n%s", md)
+	}
+	if !strings.HasPrefix(md, "# Transcript\n") {
+		t.Errorf("default title missing, got:\n%s", md)
+	}
+}
+
+func TestSummarizeToolResult(t *testing.T) {
+	tests := []struct {
+		tool   string
+		result string
+		want   string
+		ok     bool
+	}{
+		{"Read", "a\nb\nc", "Read 3 lines", true},
+		{"Grep", "a.go:1\n\nb.go:2\n", "2 matches", true},
+		{"Grep", "", "No matches", true},
+		{"Bash", "ok", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := SummarizeToolResult(tt.tool, tt.result)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("SummarizeToolResult(%q, %q) = %q, %v; want %q, %v", tt.tool, tt.result, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestShortenPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		worktree string
+		want     string
+	}{
+		{
+			name:     "path within worktree",
+			path:     "/home/user/.fab/worktrees/proj/internal/tui/chatview.go",
+			worktree: "/home/user/.fab/worktrees/proj",
+			want:     "./internal/tui/chatview.go",
+		},
+		{
+			name:     "path is worktree root",
+			path:     "/home/user/.fab/worktrees/proj",
+			worktree: "/home/user/.fab/worktrees/proj",
+			want:     ".",
+		},
+		{
+			name:     "path outside worktree",
+			path:     "/home/user/other/file.go",
+			worktree: "/home/user/.fab/worktrees/proj",
+			want:     "/home/user/other/file.go",
+		},
+		{
+			name:     "empty worktree",
+			path:     "/home/user/.fab/worktrees/proj/file.go",
+			worktree: "",
+			want:     "/home/user/.fab/worktrees/proj/file.go",
+		},
+		{
+			name:     "empty path",
+			path:     "",
+			worktree: "/home/user/.fab/worktrees/proj",
+			want:     "",
+		},
+		{
+			name:     "worktree with trailing slash",
+			path:     "/home/user/.fab/worktrees/proj/internal/file.go",
+			worktree: "/home/user/.fab/worktrees/proj/",
+			want:     "./internal/file.go",
+		},
+		{
+			name:     "similar prefix but not inside worktree",
+			path:     "/home/user/.fab/worktrees/proj-other/file.go",
+			worktree: "/home/user/.fab/worktrees/proj",
+			want:     "/home/user/.fab/worktrees/proj-other/file.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ShortenPath(tt.path, tt.worktree)
+			if got != tt.want {
+				t.Errorf("ShortenPath(%q, %q) = %q, want %q", tt.path, tt.worktree, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatLineCount(t *testing.T) {
+	tests := []struct {
+		count int
+		want  string
+	}{
+		{0, "0 lines"},
+		{1, "1 line"},
+		{2, "2 lines"},
+		{100, "100 lines"},
+		{1000, "1000 lines"},
+	}
+
+	for _, tt := range tests {
+		got := FormatLineCount(tt.count)
+		if got != tt.want {
+			t.Errorf("FormatLineCount(%d) = %q, want %q", tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMatchCount(t *testing.T) {
+	tests := []struct {
+		count int
+		want  string
+	}{
+		{0, "0 matches"},
+		{1, "1 match"},
+		{2, "2 matches"},
+		{100, "100 matches"},
+	}
+
+	for _, tt := range tests {
+		got := FormatMatchCount(tt.count)
+		if got != tt.want {
+			t.Errorf("FormatMatchCount(%d) = %q, want %q", tt.count, got, tt.want)
+		}
+	}
+}
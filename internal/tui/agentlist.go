@@ -2,6 +2,8 @@ package tui
 
 import (
 	"fmt"
+	"hash/fnv"
+	"slices"
 	"strings"
 	"time"
 
@@ -21,6 +23,23 @@ type AgentList struct {
 	spinnerFrame   int
 	needsAttention map[string]bool // agents with pending permissions/actions
 	focused        bool
+	ghosts         []GhostEntry // dry-run decisions, shown below the agents
+}
+
+// ghostTTL is how long a ghost entry is shown without being reported again.
+// Dry-run orchestrators re-report pending spawns on every poll, so entries
+// that stop being reported fade out after a few cycles.
+const ghostTTL = 30 * time.Second
+
+// GhostEntry is an action a dry-run orchestrator reported it would take.
+// Ghosts are shown in the agent list but can't be selected.
+type GhostEntry struct {
+	Type    string // "would_spawn" or "would_merge"
+	Project string
+	IssueID string
+	AgentID string
+	Reason  string
+	SeenAt  time.Time
 }
 
 // NewAgentList creates a new agent list component.
@@ -37,8 +56,17 @@ func (l *AgentList) SetSize(width, height int) {
 	l.height = height
 }
 
-// SetAgents updates the agent list.
+// SetAgents updates the agent list. The selected agent stays selected if
+// it is still in the list, even if it moved.
 func (l *AgentList) SetAgents(agents []daemon.AgentStatus) {
+	if selected := l.Selected(); selected != nil {
+		id, project := selected.ID, selected.Project
+		if i := slices.IndexFunc(agents, func(a daemon.AgentStatus) bool {
+			return a.ID == id && a.Project == project
+		}); i >= 0 {
+			l.selected = i
+		}
+	}
 	l.agents = agents
 	// Adjust selection if list shrunk
 	if l.selected >= len(agents) && len(agents) > 0 {
@@ -100,6 +128,49 @@ func (l *AgentList) MoveToBottom() {
 	}
 }
 
+// MoveToNextAttention moves selection to the next agent that needs
+// attention, wrapping around to the top. Returns false, leaving the
+// selection alone, if no agent needs attention.
+func (l *AgentList) MoveToNextAttention() bool {
+	for i := 1; i <= len(l.agents); i++ {
+		next := (l.selected + i) % len(l.agents)
+		if l.needsAttention[l.agents[next].ID] {
+			l.selected = next
+			return true
+		}
+	}
+	return false
+}
+
+// AddGhost records a dry-run decision, replacing any earlier report of the
+// same decision.
+func (l *AgentList) AddGhost(g GhostEntry) {
+	for i, existing := range l.ghosts {
+		if existing.Type == g.Type && existing.Project == g.Project &&
+			existing.IssueID == g.IssueID && existing.AgentID == g.AgentID {
+			l.ghosts[i] = g
+			return
+		}
+	}
+	l.ghosts = append(l.ghosts, g)
+}
+
+// PruneGhosts drops ghost entries that haven't been reported within ghostTTL.
+func (l *AgentList) PruneGhosts(now time.Time) {
+	kept := l.ghosts[:0]
+	for _, g := range l.ghosts {
+		if now.Sub(g.SeenAt) < ghostTTL {
+			kept = append(kept, g)
+		}
+	}
+	l.ghosts = kept
+}
+
+// Ghosts returns the current ghost entries.
+func (l *AgentList) Ghosts() []GhostEntry {
+	return l.ghosts
+}
+
 // SetSpinnerFrame updates the current spinner animation frame.
 func (l *AgentList) SetSpinnerFrame(frame int) {
 	l.spinnerFrame = frame
@@ -141,7 +212,7 @@ func (l AgentList) View() string {
 
 	// Content
 	var content string
-	if len(l.agents) == 0 {
+	if len(l.agents) == 0 && len(l.ghosts) == 0 {
 		content = agentListEmptyStyle.Width(innerWidth).Height(innerHeight).Render("No agents")
 	} else {
 		var rows []string
@@ -152,6 +223,10 @@ func (l AgentList) View() string {
 			row := l.renderAgent(i, agent, innerWidth)
 			rows = append(rows, row)
 		}
+		for _, g := range l.ghosts {
+			rows = append(rows, l.renderGhost(g, innerWidth))
+		}
+		rows = append(rows, l.renderLineage(innerWidth)...)
 		content = agentListContainerStyle.Width(innerWidth).Height(innerHeight).Render(strings.Join(rows, "\n"))
 	}
 
@@ -196,6 +271,32 @@ func plannerAgentID(plannerID string) string {
 	return PlannerAgentIDPrefix + plannerID
 }
 
+// ManagerSelectorPrefix is the prefix for selecting a project's manager on
+// startup (e.g., "manager:myapp").
+const ManagerSelectorPrefix = "manager:"
+
+// ParseInitialSelection resolves a startup selector to the agent list entry
+// it refers to. Accepted forms are a plain agent ID, "plan:<id>" for a
+// planner, and "manager:<project>" for a project's manager. For managers,
+// agentID is ManagerAgentID and managerProject names the project.
+func ParseInitialSelection(sel string) (agentID, managerProject string, err error) {
+	switch {
+	case strings.HasPrefix(sel, ManagerSelectorPrefix):
+		project := strings.TrimPrefix(sel, ManagerSelectorPrefix)
+		if project == "" {
+			return "", "", fmt.Errorf("invalid selector %q: expected manager:<project>", sel)
+		}
+		return ManagerAgentID, project, nil
+	case strings.HasPrefix(sel, PlannerAgentIDPrefix):
+		if !isPlannerAgent(sel) {
+			return "", "", fmt.Errorf("invalid selector %q: expected plan:<id>", sel)
+		}
+		return sel, "", nil
+	default:
+		return sel, "", nil
+	}
+}
+
 // extractPlannerID extracts the real planner ID from a TUI agent ID.
 func extractPlannerID(agentID string) string {
 	if isPlannerAgent(agentID) {
@@ -216,6 +317,14 @@ func backendStyle(backend string) lipgloss.Style {
 	}
 }
 
+// epicStyle returns the tag style for an epic. The color is derived from
+// the epic ID so it stays stable across refreshes.
+func epicStyle(epic string) lipgloss.Style {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(epic))
+	return lipgloss.NewStyle().Foreground(epicColors[h.Sum32()%uint32(len(epicColors))])
+}
+
 // renderAgent renders a single agent row.
 func (l AgentList) renderAgent(index int, agent daemon.AgentStatus, width int) string {
 	isSelected := index == l.selected
@@ -250,6 +359,11 @@ func (l AgentList) renderAgent(index int, agent daemon.AgentStatus, width int) s
 		displayID = extractPlannerID(agent.ID) // Show just the short ID, not the prefix
 	}
 	idStr := idStyle.Inherit(bgStyle).Render(displayID)
+	if agent.Name != "" {
+		// The name leads; the ID stays visible since it's what commands take
+		idStr = idStyle.Inherit(bgStyle).Render(agent.Name) +
+			agentNamedIDStyle.Inherit(bgStyle).Render(" "+displayID)
+	}
 
 	// Project name
 	projectStr := agentProjectStyle.Inherit(bgStyle).Render(agent.Project)
@@ -266,6 +380,12 @@ func (l AgentList) renderAgent(index int, agent daemon.AgentStatus, width int) s
 		taskStr = agentTaskStyle.Inherit(bgStyle).Render(agent.Task)
 	}
 
+	// Epic tag, colored so agents under the same parent issue stand out
+	epicStr := ""
+	if agent.Epic != "" {
+		epicStr = epicStyle(agent.Epic).Inherit(bgStyle).Render("◆" + agent.Epic)
+	}
+
 	// Duration since started
 	duration := time.Since(agent.StartedAt).Truncate(time.Second)
 	durationStr := agentDurationStyle.Inherit(bgStyle).Render(formatDuration(duration))
@@ -282,6 +402,9 @@ func (l AgentList) renderAgent(index int, agent daemon.AgentStatus, width int) s
 	if taskStr != "" {
 		left = lipgloss.JoinHorizontal(lipgloss.Center, left, " ", taskStr)
 	}
+	if epicStr != "" {
+		left = lipgloss.JoinHorizontal(lipgloss.Center, left, " ", epicStr)
+	}
 
 	// Calculate available width for description and add it if present
 	leftWidth := lipgloss.Width(left)
@@ -323,10 +446,75 @@ func (l AgentList) renderAgent(index int, agent daemon.AgentStatus, width int) s
 		row = left + spacer + durationStr
 	}
 
+	// Show the file the agent is working in on a second line
+	if agent.CurrentFile != "" && contentWidth > 6 {
+		file := truncatePathLeft(agent.CurrentFile, contentWidth-4)
+		fileLine := bgStyle.Render("  ") + agentCurrentFileStyle.Inherit(bgStyle).Render("↳ "+file)
+		row = lipgloss.JoinVertical(lipgloss.Left, row, fileLine)
+	}
+
 	// Apply row styling with full width - padding is applied only here
 	return rowStyle.Width(width).Render(row)
 }
 
+// renderGhost renders a dry-run decision as a muted, unselectable row.
+func (l AgentList) renderGhost(g GhostEntry, width int) string {
+	action := "would spawn"
+	subject := g.IssueID
+	if g.Type == "would_merge" {
+		action = "would merge"
+		subject = g.AgentID
+	}
+	text := fmt.Sprintf("◌ %s %s %s", action, subject, g.Project)
+	if g.Reason != "" {
+		text += " — " + g.Reason
+	}
+	// Available content width is total width minus padding (1 on each side = 2)
+	if contentWidth := width - 2; contentWidth > 3 {
+		text = truncateDescription(text, contentWidth)
+	}
+	return agentRowStyle.Width(width).Render(agentGhostStyle.Render(text))
+}
+
+// renderLineage renders agents that spawned other agents as indented trees,
+// so delegation chains are visible under the flat list. Agents with no
+// children are left out; the list above already shows them.
+func (l AgentList) renderLineage(width int) []string {
+	var lines []string
+	var walk func(node daemon.LineageNode, linePrefix, childPrefix string)
+	walk = func(node daemon.LineageNode, linePrefix, childPrefix string) {
+		label := node.ID
+		if node.Name != "" {
+			label = node.Name + " " + node.ID
+		}
+		lines = append(lines, linePrefix+l.stateIcon(node.ID, node.State)+" "+label)
+		for i, child := range node.Children {
+			if i == len(node.Children)-1 {
+				walk(child, childPrefix+"└─ ", childPrefix+"   ")
+			} else {
+				walk(child, childPrefix+"├─ ", childPrefix+"│  ")
+			}
+		}
+	}
+	for _, root := range daemon.BuildLineage(l.agents) {
+		if len(root.Children) > 0 {
+			walk(root, "", "")
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	rows := []string{agentRowStyle.Width(width).Render(lipgloss.NewStyle().Foreground(mutedColor).Render("LINEAGE"))}
+	// Available content width is total width minus padding (1 on each side = 2).
+	// MaxWidth cuts long lines rather than wrapping them, keeping the indent.
+	lineStyle := agentLineageStyle.MaxWidth(max(width-2, 1))
+	for _, line := range lines {
+		rows = append(rows, agentRowStyle.Width(width).Render(lineStyle.Render(line)))
+	}
+	return rows
+}
+
 // renderColumnHeader renders the column header row.
 func (l AgentList) renderColumnHeader(width int) string {
 	// Column header labels styled with muted color
@@ -390,6 +578,9 @@ func (l AgentList) stateIcon(agentID, state string) string {
 		return "✓"
 	case "error":
 		return "✗"
+	case "stuck":
+		// Process is alive but hasn't produced valid output - needs a look
+		return "⚠"
 	default:
 		return "?"
 	}
@@ -413,6 +604,8 @@ func (l AgentList) stateStyle(agentID, state string) lipgloss.Style {
 		return lipgloss.NewStyle().Foreground(secondaryColor)
 	case "error":
 		return lipgloss.NewStyle().Foreground(errorColor)
+	case "stuck":
+		return lipgloss.NewStyle().Foreground(warningColor).Bold(true)
 	default:
 		return lipgloss.NewStyle().Foreground(mutedColor)
 	}
@@ -437,6 +630,19 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dd%dh", days, remainingHours)
 }
 
+// truncatePathLeft shortens a path to fit within maxLen characters by
+// dropping leading characters, keeping the file name visible.
+func truncatePathLeft(path string, maxLen int) string {
+	runes := []rune(path)
+	if len(runes) <= maxLen {
+		return path
+	}
+	if maxLen < 2 {
+		return string(runes[len(runes)-1:])
+	}
+	return "…" + string(runes[len(runes)-maxLen+1:])
+}
+
 // truncateDescription truncates a description to fit within maxLen characters.
 func truncateDescription(desc string, maxLen int) string {
 	// Replace newlines with spaces for single-line display
@@ -1,8 +1,11 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/tessro/fab/internal/daemon"
 )
 
 func TestFormatDuration(t *testing.T) {
@@ -43,3 +46,202 @@ func TestFormatDurationMaxLength(t *testing.T) {
 		t.Errorf("formatDuration for long duration produced unexpectedly long output: %q (len=%d)", result, len(result))
 	}
 }
+
+func TestTruncatePathLeft(t *testing.T) {
+	tests := []struct {
+		path   string
+		maxLen int
+		want   string
+	}{
+		{"main.go", 20, "main.go"},
+		{"internal/tui/agentlist.go", 14, "…/agentlist.go"},
+		{"internal/tui/agentlist.go", 1, "o"},
+	}
+	for _, tt := range tests {
+		if got := truncatePathLeft(tt.path, tt.maxLen); got != tt.want {
+			t.Errorf("truncatePathLeft(%q, %d) = %q, want %q", tt.path, tt.maxLen, got, tt.want)
+		}
+	}
+}
+
+func TestParseInitialSelection(t *testing.T) {
+	tests := []struct {
+		sel         string
+		wantAgentID string
+		wantProject string
+		wantErr     bool
+	}{
+		{sel: "", wantAgentID: ""},
+		{sel: "a1b2c3", wantAgentID: "a1b2c3"},
+		{sel: "plan:d4e5f6", wantAgentID: "plan:d4e5f6"},
+		{sel: "manager:myapp", wantAgentID: ManagerAgentID, wantProject: "myapp"},
+		{sel: "manager:", wantErr: true},
+		{sel: "plan:", wantErr: true},
+	}
+	for _, tt := range tests {
+		agentID, project, err := ParseInitialSelection(tt.sel)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseInitialSelection(%q) error = %v, wantErr %v", tt.sel, err, tt.wantErr)
+			continue
+		}
+		if agentID != tt.wantAgentID || project != tt.wantProject {
+			t.Errorf("ParseInitialSelection(%q) = %q, %q; want %q, %q", tt.sel, agentID, project, tt.wantAgentID, tt.wantProject)
+		}
+	}
+}
+
+func TestAgentListGhosts(t *testing.T) {
+	l := NewAgentList()
+	now := time.Now()
+
+	l.AddGhost(GhostEntry{Type: "would_spawn", Project: "app", IssueID: "1", Reason: "old", SeenAt: now.Add(-time.Minute)})
+	l.AddGhost(GhostEntry{Type: "would_spawn", Project: "app", IssueID: "2", SeenAt: now.Add(-time.Minute)})
+	// Re-reporting a decision replaces it rather than adding a duplicate
+	l.AddGhost(GhostEntry{Type: "would_spawn", Project: "app", IssueID: "1", Reason: "new", SeenAt: now})
+
+	ghosts := l.Ghosts()
+	if len(ghosts) != 2 || ghosts[0].Reason != "new" {
+		t.Fatalf("Ghosts() = %+v, want 2 entries with issue 1 updated", ghosts)
+	}
+
+	l.PruneGhosts(now)
+	ghosts = l.Ghosts()
+	if len(ghosts) != 1 || ghosts[0].IssueID != "1" {
+		t.Errorf("after PruneGhosts, Ghosts() = %+v, want only issue 1", ghosts)
+	}
+
+	// Ghosts are not selectable
+	if l.Selected() != nil {
+		t.Errorf("Selected() = %+v, want nil with only ghost entries", l.Selected())
+	}
+}
+
+func TestAgentListRendersEpic(t *testing.T) {
+	l := NewAgentList()
+	l.SetSize(100, 10)
+	l.SetAgents([]daemon.AgentStatus{
+		{ID: "a1", Project: "app", State: "running", Task: "12", Epic: "7", StartedAt: time.Now()},
+	})
+
+	if view := l.View(); !strings.Contains(view, "◆7") {
+		t.Errorf("View() should show the epic tag, got:\n%s", view)
+	}
+
+	// Agents under the same epic share a color
+	if epicStyle("7").GetForeground() != epicStyle("7").GetForeground() {
+		t.Error("epicStyle should be stable for the same epic")
+	}
+}
+
+func TestAgentListRendersLineage(t *testing.T) {
+	l := NewAgentList()
+	l.SetSize(100, 12)
+	l.SetAgents([]daemon.AgentStatus{
+		{ID: "a1", Project: "app", State: "idle", SpawnedBy: "orchestrator", StartedAt: time.Now()},
+		{ID: "a2", Project: "app", State: "idle", SpawnedBy: "user", StartedAt: time.Now()},
+	})
+
+	// Without any parent-child links there's nothing to draw
+	if view := l.View(); strings.Contains(view, "LINEAGE") {
+		t.Errorf("View() should omit lineage for flat agents, got:\n%s", view)
+	}
+
+	l.SetAgents([]daemon.AgentStatus{
+		{ID: "a1", Project: "app", State: "idle", SpawnedBy: "orchestrator", StartedAt: time.Now()},
+		{ID: "a2", Project: "app", State: "idle", SpawnedBy: "a1", StartedAt: time.Now()},
+		{ID: "a3", Project: "app", State: "idle", SpawnedBy: "a2", StartedAt: time.Now()},
+	})
+
+	view := l.View()
+	if !strings.Contains(view, "LINEAGE") || !strings.Contains(view, "└─ ○ a2") || !strings.Contains(view, "   └─ ○ a3") {
+		t.Errorf("View() should show a1 → a2 → a3 as a tree, got:\n%s", view)
+	}
+}
+
+func TestAgentListKeepsSelectionAcrossReorder(t *testing.T) {
+	l := NewAgentList()
+	l.SetAgents([]daemon.AgentStatus{{ID: "a1"}, {ID: "a2"}, {ID: "a3"}})
+	l.SetSelected(1)
+
+	l.SetAgents([]daemon.AgentStatus{{ID: "a2"}, {ID: "a3"}, {ID: "a1"}})
+	if got := l.Selected(); got == nil || got.ID != "a2" {
+		t.Errorf("Selected() = %+v, want a2 after reorder", got)
+	}
+}
+
+func TestAgentListMoveToNextAttention(t *testing.T) {
+	l := NewAgentList()
+	l.SetAgents([]daemon.AgentStatus{{ID: "a1"}, {ID: "a2"}, {ID: "a3"}, {ID: "a4"}})
+	l.SetSelected(1)
+
+	if l.MoveToNextAttention() {
+		t.Error("MoveToNextAttention() = true with no agents needing attention")
+	}
+	if l.SelectedIndex() != 1 {
+		t.Errorf("selection moved to %d, want it left at 1", l.SelectedIndex())
+	}
+
+	l.SetNeedsAttention(map[string]bool{"a1": true, "a3": true})
+	for _, want := range []string{"a3", "a1", "a3"} {
+		if !l.MoveToNextAttention() {
+			t.Fatal("MoveToNextAttention() = false, want true")
+		}
+		if got := l.Selected(); got.ID != want {
+			t.Errorf("Selected() = %s, want %s", got.ID, want)
+		}
+	}
+}
+
+func TestHeaderShowsAttentionCount(t *testing.T) {
+	h := NewHeader()
+	h.SetWidth(120)
+	h.SetAgentCounts(4, 2)
+	if strings.Contains(h.View(), "need attention") {
+		t.Error("header shows attention count with none pending")
+	}
+
+	h.SetAttentionCount(3)
+	if !strings.Contains(h.View(), "3 need attention") {
+		t.Errorf("header missing attention count: %q", h.View())
+	}
+}
+
+func TestHeaderShowsWaitingIssues(t *testing.T) {
+	projects := []daemon.ProjectStatus{
+		{Name: "full", WaitingIssues: 2, AtCapacity: true},
+		{Name: "free", WaitingIssues: 5},
+	}
+	if n := waitingAtCapacity(projects); n != 2 {
+		t.Errorf("waitingAtCapacity() = %d, want only the full project's 2", n)
+	}
+
+	h := NewHeader()
+	h.SetWidth(120)
+	h.SetAgentCounts(3, 3)
+	if strings.Contains(h.View(), "waiting") {
+		t.Error("header shows waiting issues with none waiting")
+	}
+
+	h.SetWaitingIssues(2)
+	if !strings.Contains(h.View(), "2 waiting (at max)") {
+		t.Errorf("header missing waiting issues: %q", h.View())
+	}
+}
+
+func TestHeaderShowsVersionWarning(t *testing.T) {
+	if w := versionWarning("v0.4.0", "v0.4.0"); w != "" {
+		t.Errorf("versionWarning() = %q for matching versions, want none", w)
+	}
+
+	h := NewHeader()
+	h.SetWidth(160)
+	h.SetVersionWarning(versionWarning("v0.3.0", "v0.4.0"))
+	if !strings.Contains(h.View(), "daemon v0.3.0 ≠ cli v0.4.0") {
+		t.Errorf("header missing version warning: %q", h.View())
+	}
+
+	h.SetVersionWarning("")
+	if strings.Contains(h.View(), "daemon") {
+		t.Errorf("header still warns after clearing: %q", h.View())
+	}
+}
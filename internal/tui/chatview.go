@@ -1,6 +1,11 @@
 package tui
 
 import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -9,7 +14,10 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/reflow/wordwrap"
 
+	"github.com/tessro/fab/internal/backend"
+	"github.com/tessro/fab/internal/config"
 	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/transcript"
 )
 
 // ChatView displays chat entries for a selected agent in a conversational format.
@@ -20,19 +28,30 @@ type ChatView struct {
 	focused             bool
 	agentID             string
 	project             string
-	backend             string // CLI backend name (e.g., "claude", "codex")
-	worktree            string // Agent's working directory (for path shortening)
+	backend             string                   // CLI backend name (e.g., "claude", "codex")
+	model               string                   // Model passed to the CLI (empty = CLI default)
+	name                string                   // Human-friendly agent name (empty = show ID only)
+	worktree            string                   // Agent's working directory (for path shortening)
+	details             *daemon.AgentGetResponse // Detailed state shown in the header (nil until fetched)
 	viewport            viewport.Model
 	ready               bool
 	pendingPermission   *daemon.PermissionRequest // pending permission request
 	pendingUserQuestion *daemon.UserQuestion      // pending user question
 	questionSelected    int                       // index of selected option (0-based, per question)
 	questionIndex       int                       // which question we're on (for multi-question)
+	questionChecked     map[int]bool              // toggled option indices (multi-select questions)
+	questionError       string                    // validation error for the "Other" answer
 	inputView           string                    // rendered input line view
 	inputHeight         int                       // height of input line (for layout)
 	inputFocused        bool                      // whether input line is focused (input mode)
 	abortConfirming     bool                      // awaiting abort confirmation
 	abortAgentID        string                    // agent being aborted
+	olderCursor         string                    // cursor for the next older history page ("" = none)
+	loadingOlder        bool                      // older history page fetch in flight
+	maxEntries          int                       // cap on entries held in memory
+	assistantName       string                    // prefix for assistant messages ("" = backend name)
+	truncated           bool                      // older entries were dropped and can't be reloaded
+	streaming           bool                      // last entry is assistant text still being built from deltas
 
 	// Plan mode state
 	planProjectSelect bool     // in plan project selection mode
@@ -48,15 +67,37 @@ type ChatView struct {
 	supervisorProjectIndex   int             // selected project index
 	supervisorProjectFilter  string          // current filter text for fuzzy matching
 	supervisorProjectRunning map[string]bool // which projects have running supervision
+
+	// New agent mode state
+	agentProjectSelect bool     // in new-agent project selection mode
+	agentProjects      []string // list of available projects
+	agentProjectIndex  int      // selected project index
+	agentProjectFilter string   // current filter text for fuzzy matching
 }
 
 // NewChatView creates a new chat view component.
 func NewChatView() ChatView {
 	return ChatView{
-		entries: make([]daemon.ChatEntryDTO, 0),
+		entries:    make([]daemon.ChatEntryDTO, 0),
+		maxEntries: config.DefaultMaxChatEntries,
 	}
 }
 
+// SetAssistantName sets the name shown before assistant messages.
+// Empty uses the capitalized backend name.
+func (v *ChatView) SetAssistantName(name string) {
+	v.assistantName = name
+}
+
+// SetMaxEntries sets the cap on entries held in memory.
+// Non-positive values restore the default.
+func (v *ChatView) SetMaxEntries(n int) {
+	if n <= 0 {
+		n = config.DefaultMaxChatEntries
+	}
+	v.maxEntries = n
+}
+
 // SetSize updates the component dimensions.
 func (v *ChatView) SetSize(width, height int) {
 	v.width = width
@@ -71,9 +112,7 @@ func (v *ChatView) updateViewportSize() {
 	contentHeight := v.height - 2 - 1 // -1 for header
 
 	// Reserve space for pending permission request if present
-	if v.pendingPermission != nil {
-		contentHeight -= 2 // 1 line for content + 1 line padding
-	}
+	contentHeight -= v.permissionHeight()
 
 	// Reserve space for pending user question if present
 	if v.pendingUserQuestion != nil {
@@ -127,18 +166,45 @@ func (v *ChatView) SetAgent(agentID, project, backend, worktree string) {
 		v.project = project
 		v.backend = backend
 		v.worktree = worktree
+		v.details = nil
 		v.entries = make([]daemon.ChatEntryDTO, 0)
+		v.olderCursor = ""
+		v.loadingOlder = false
+		v.truncated = false
+		v.streaming = false
 		v.updateContent()
 	}
 }
 
+// SetModel sets the model shown in the chat header for the current agent.
+func (v *ChatView) SetModel(model string) {
+	v.model = model
+}
+
+// SetDetails sets the detailed agent state shown in the chat header.
+func (v *ChatView) SetDetails(details *daemon.AgentGetResponse) {
+	v.details = details
+}
+
+// SetName sets the human-friendly name shown in the chat header for the current agent.
+func (v *ChatView) SetName(name string) {
+	v.name = name
+}
+
 // ClearAgent clears the current agent view.
 func (v *ChatView) ClearAgent() {
 	v.agentID = ""
 	v.project = ""
 	v.backend = ""
+	v.model = ""
+	v.name = ""
 	v.worktree = ""
+	v.details = nil
 	v.entries = make([]daemon.ChatEntryDTO, 0)
+	v.olderCursor = ""
+	v.loadingOlder = false
+	v.truncated = false
+	v.streaming = false
 	v.updateContent()
 }
 
@@ -180,18 +246,31 @@ func (v *ChatView) PendingPermissionID() string {
 func (v *ChatView) SetPendingUserQuestion(question *daemon.UserQuestion) {
 	hadQuestion := v.pendingUserQuestion != nil
 	hasQuestion := question != nil
-	v.pendingUserQuestion = question
 	// Reset selection state when question changes
-	if question != nil && (v.pendingUserQuestion == nil || v.pendingUserQuestion.ID != question.ID) {
+	changed := question != nil && (v.pendingUserQuestion == nil || v.pendingUserQuestion.ID != question.ID)
+	if changed {
 		v.questionSelected = 0
 		v.questionIndex = 0
+		v.questionChecked = nil
+		v.questionError = ""
 	}
+	v.pendingUserQuestion = question
 	// Recalculate viewport size if pending question state changed
-	if hadQuestion != hasQuestion {
+	if changed || hadQuestion != hasQuestion {
 		v.updateViewportSize()
 	}
 }
 
+// setQuestionError sets the validation error shown under the question,
+// resizing the viewport since the error takes a line.
+func (v *ChatView) setQuestionError(msg string) {
+	if v.questionError == msg {
+		return
+	}
+	v.questionError = msg
+	v.updateViewportSize()
+}
+
 // HasPendingUserQuestion returns whether there's a pending user question.
 func (v *ChatView) HasPendingUserQuestion() bool {
 	return v.pendingUserQuestion != nil
@@ -234,8 +313,34 @@ func (v *ChatView) QuestionMoveDown() {
 	}
 }
 
+// QuestionIsMultiSelect returns whether the current pending question allows multiple selections.
+func (v *ChatView) QuestionIsMultiSelect() bool {
+	if v.pendingUserQuestion == nil || v.questionIndex >= len(v.pendingUserQuestion.Questions) {
+		return false
+	}
+	return v.pendingUserQuestion.Questions[v.questionIndex].MultiSelect
+}
+
+// QuestionToggle toggles the highlighted option of a multi-select question.
+// Does nothing for single-select questions or when "Other" is highlighted.
+func (v *ChatView) QuestionToggle() {
+	if v.pendingUserQuestion == nil || v.questionIndex >= len(v.pendingUserQuestion.Questions) {
+		return
+	}
+	q := v.pendingUserQuestion.Questions[v.questionIndex]
+	if !q.MultiSelect || v.questionSelected >= len(q.Options) {
+		return
+	}
+	if v.questionChecked == nil {
+		v.questionChecked = make(map[int]bool)
+	}
+	v.questionChecked[v.questionSelected] = !v.questionChecked[v.questionSelected]
+}
+
 // GetSelectedAnswer returns the selected answer for the current question.
 // Returns the option label, or "" for "Other" (which requires freeform input).
+// For multi-select questions, the label is the toggled options joined with
+// daemon.MultiSelectSeparator, or the highlighted option if none are toggled.
 func (v *ChatView) GetSelectedAnswer() (header string, label string, isOther bool) {
 	if v.pendingUserQuestion == nil || v.questionIndex >= len(v.pendingUserQuestion.Questions) {
 		return "", "", false
@@ -245,9 +350,54 @@ func (v *ChatView) GetSelectedAnswer() (header string, label string, isOther boo
 		// "Other" option selected
 		return q.Header, "", true
 	}
+	if q.MultiSelect {
+		if labels := v.checkedLabels(q); len(labels) > 0 {
+			return q.Header, strings.Join(labels, daemon.MultiSelectSeparator), false
+		}
+	}
 	return q.Header, q.Options[v.questionSelected].Label, false
 }
 
+// OtherAnswer validates a freeform "Other" answer for the current question and
+// returns the answer to send. For multi-select questions the freeform text is
+// appended to the toggled options. Returns an error if the question's Validate
+// pattern doesn't match; the error is also shown under the question.
+func (v *ChatView) OtherAnswer(input string) (header string, answer string, err error) {
+	if v.pendingUserQuestion == nil || v.questionIndex >= len(v.pendingUserQuestion.Questions) {
+		return "", "", fmt.Errorf("no pending question")
+	}
+	q := v.pendingUserQuestion.Questions[v.questionIndex]
+
+	if q.Validate != "" {
+		re, err := regexp.Compile(q.Validate)
+		if err != nil {
+			// A bad pattern from the agent shouldn't block the user from answering
+			slog.Warn("invalid user question validation pattern", "pattern", q.Validate, "error", err)
+		} else if !re.MatchString(input) {
+			v.setQuestionError(fmt.Sprintf("answer must match %s", q.Validate))
+			return "", "", fmt.Errorf("%s", v.questionError)
+		}
+	}
+	v.setQuestionError("")
+
+	answer = input
+	if q.MultiSelect {
+		answer = strings.Join(append(v.checkedLabels(q), input), daemon.MultiSelectSeparator)
+	}
+	return q.Header, answer, nil
+}
+
+// checkedLabels returns the labels of toggled options in display order.
+func (v *ChatView) checkedLabels(q daemon.QuestionItem) []string {
+	var labels []string
+	for i, opt := range q.Options {
+		if v.questionChecked[i] {
+			labels = append(labels, opt.Label)
+		}
+	}
+	return labels
+}
+
 // calculateUserQuestionHeight calculates the height needed for the user question UI.
 func (v *ChatView) calculateUserQuestionHeight() int {
 	if v.pendingUserQuestion == nil || v.questionIndex >= len(v.pendingUserQuestion.Questions) {
@@ -255,7 +405,11 @@ func (v *ChatView) calculateUserQuestionHeight() int {
 	}
 	q := v.pendingUserQuestion.Questions[v.questionIndex]
 	// 1 for question header + options count + 1 for "Other" + 1 for padding
-	return 1 + len(q.Options) + 1 + 1
+	height := 1 + len(q.Options) + 1 + 1
+	if v.questionError != "" {
+		height++
+	}
+	return height
 }
 
 // SetInputView sets the rendered input line view to display.
@@ -278,15 +432,29 @@ func (v *ChatView) SetAbortConfirming(confirming bool, agentID string) {
 
 // AppendEntry adds a chat entry to the view.
 func (v *ChatView) AppendEntry(entry daemon.ChatEntryDTO) {
+	// Skip entries already shown, e.g. replayed on attach after the
+	// history was fetched
+	if n := len(v.entries); n > 0 && entry.Seq > 0 && entry.Seq <= v.entries[n-1].Seq {
+		return
+	}
+
 	// Capture scroll position before updating content
 	wasAtBottom := v.viewport.AtBottom() || v.viewport.YOffset >= v.viewport.TotalLineCount()-v.viewport.Height-5
 
+	// The complete assistant entry replaces the one built from deltas
+	if v.streaming && entry.Role == "assistant" {
+		v.entries = v.entries[:len(v.entries)-1]
+	}
+	v.streaming = false
+
 	v.entries = append(v.entries, entry)
 
-	// Cap at max entries to prevent unbounded growth
-	const maxEntries = 1000
-	if len(v.entries) > maxEntries {
-		v.entries = v.entries[len(v.entries)-maxEntries:]
+	// Cap at max entries to prevent unbounded growth. While the user is
+	// scrolled up reading (possibly paged-in) history, allow up to twice the
+	// cap so the content doesn't shift away underneath them.
+	if len(v.entries) > v.maxEntries && (wasAtBottom || len(v.entries) > 2*v.maxEntries) {
+		v.entries = v.entries[len(v.entries)-v.maxEntries:]
+		v.trimmedOlder()
 	}
 
 	v.updateContent()
@@ -297,6 +465,42 @@ func (v *ChatView) AppendEntry(entry daemon.ChatEntryDTO) {
 	}
 }
 
+// AppendDelta extends the in-progress assistant entry with a chunk of
+// streaming text, starting a new entry if none is in progress. The entry is
+// replaced when the complete one arrives through AppendEntry.
+func (v *ChatView) AppendDelta(text string) {
+	wasAtBottom := v.viewport.AtBottom() || v.viewport.YOffset >= v.viewport.TotalLineCount()-v.viewport.Height-5
+
+	if v.streaming {
+		v.entries[len(v.entries)-1].Content += text
+	} else {
+		v.entries = append(v.entries, daemon.ChatEntryDTO{
+			Role:      "assistant",
+			Content:   text,
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		v.streaming = true
+	}
+
+	v.updateContent()
+
+	if wasAtBottom {
+		v.viewport.GotoBottom()
+	}
+}
+
+// trimmedOlder updates paging state after the oldest entries were dropped.
+// Agent histories are paged by sequence number, so the trimmed entries can be
+// reloaded from the first kept one; otherwise they're marked as truncated.
+func (v *ChatView) trimmedOlder() {
+	if first := v.entries[0]; first.Seq > 0 && !isManager(v.agentID) && !isDirector(v.agentID) && !isPlanner(v.agentID) {
+		v.olderCursor = strconv.FormatInt(first.Seq, 10)
+		return
+	}
+	v.olderCursor = ""
+	v.truncated = true
+}
+
 // SetEntries merges historical entries with any streaming entries that may have
 // arrived while the history was being fetched. This prevents a race condition
 // where switching agents triggers a history fetch, but streaming events arrive
@@ -322,12 +526,56 @@ func (v *ChatView) SetEntries(entries []daemon.ChatEntryDTO) {
 		}
 	}
 
-	// Merge: history entries + any streaming entries that arrived after
+	// Merge: history entries + any streaming entries that arrived after.
+	// An in-progress delta entry is always last, so it survives only if
+	// something newer than the history did.
+	v.streaming = v.streaming && len(streamingEntries) > 0
 	v.entries = append(entries, streamingEntries...)
 	v.updateContent()
 	v.viewport.GotoBottom()
 }
 
+// SetHistoryCursor records the cursor for the next older page of history.
+// An empty cursor means there is no older history to load.
+func (v *ChatView) SetHistoryCursor(cursor string) {
+	v.olderCursor = cursor
+	v.loadingOlder = false
+}
+
+// BeginOlderHistoryLoad returns the cursor for the next older history page
+// if the viewport is scrolled to the top and no fetch is in flight.
+// On success it marks a fetch as in flight until PrependEntries or
+// CancelOlderHistoryLoad is called.
+func (v *ChatView) BeginOlderHistoryLoad() (string, bool) {
+	if v.olderCursor == "" || v.loadingOlder || !v.viewport.AtTop() {
+		return "", false
+	}
+	v.loadingOlder = true
+	return v.olderCursor, true
+}
+
+// CancelOlderHistoryLoad clears the in-flight flag after a failed fetch,
+// so scrolling to the top retries.
+func (v *ChatView) CancelOlderHistoryLoad() {
+	v.loadingOlder = false
+}
+
+// PrependEntries adds an older page of history above the current entries,
+// keeping the viewport anchored on the content the user was looking at.
+// Paged-in entries may exceed the cap; they're trimmed again once the user
+// returns to the bottom and new entries arrive.
+func (v *ChatView) PrependEntries(entries []daemon.ChatEntryDTO, cursor string) {
+	v.SetHistoryCursor(cursor)
+	if len(entries) == 0 {
+		return
+	}
+
+	linesBefore := v.viewport.TotalLineCount()
+	v.entries = append(slices.Clone(entries), v.entries...)
+	v.updateContent()
+	v.viewport.SetYOffset(v.viewport.YOffset + v.viewport.TotalLineCount() - linesBefore)
+}
+
 // ScrollUp scrolls the viewport up.
 func (v *ChatView) ScrollUp(n int) {
 	v.viewport.ScrollUp(n)
@@ -365,6 +613,9 @@ func (v *ChatView) updateContent() {
 	}
 
 	var lines []string
+	if v.truncated {
+		lines = append(lines, chatTruncatedStyle.Render("··· older messages truncated ···"))
+	}
 	var lastToolName string
 	for _, entry := range v.entries {
 		// Track the last seen tool name for linking tool_result entries
@@ -409,17 +660,17 @@ func (v *ChatView) renderEntry(entry daemon.ChatEntryDTO, lastToolName string) s
 
 	switch entry.Role {
 	case "assistant":
-		// Use backend name for prefix, capitalize first letter
-		backendName := v.backend
-		if backendName == "" {
-			backendName = "claude"
-		}
-		// Capitalize first letter
-		if len(backendName) > 0 {
-			backendName = strings.ToUpper(backendName[:1]) + backendName[1:]
+		// Use the configured name, or the backend name with its first letter capitalized
+		name := v.assistantName
+		if name == "" {
+			name = v.backend
+			if name == "" {
+				name = "claude"
+			}
+			name = strings.ToUpper(name[:1]) + name[1:]
 		}
-		prefix := backendName + ": "
-		prefixLen := len(prefix)
+		prefix := name + ": "
+		prefixLen := lipgloss.Width(prefix)
 		if timeStr != "" {
 			prefixLen += len(timeStr) + 1 // +1 for space
 		}
@@ -442,10 +693,7 @@ func (v *ChatView) renderEntry(entry daemon.ChatEntryDTO, lastToolName string) s
 
 		// Tool invocation line (only show if we have a tool name)
 		if entry.ToolName != "" {
-			// Shorten paths in tool input (e.g., file_path for Read/Write/Edit)
-			toolInput := shortenPath(entry.ToolInput, v.worktree)
-			toolLine := "  " + chatToolStyle.Render("["+entry.ToolName+"]") + " " + truncateToolInput(toolInput)
-			parts = append(parts, toolLine)
+			parts = append(parts, v.renderToolCall(entry.ToolName, entry.ToolInput, contentWidth))
 		}
 
 		// Tool result (if present)
@@ -466,6 +714,121 @@ func (v *ChatView) renderEntry(entry daemon.ChatEntryDTO, lastToolName string) s
 	}
 }
 
+// toolBodyIndent indents the lines a tool formatter renders under the tool
+// name.
+const toolBodyIndent = "    "
+
+// maxToolBodyLines caps how many lines of a Bash command or Edit hunk are
+// shown in chat.
+const maxToolBodyLines = 10
+
+// toolFormatter renders a known tool's input summary. summary is shown on
+// the tool line after the name; body lines are shown indented below it,
+// already styled and fit to width.
+type toolFormatter func(input string, width int) (summary string, body []string)
+
+// toolFormatters holds the tool-specific renderers, keyed by ToolName.
+// Tools without one show their input flattened and truncated on the tool line.
+var toolFormatters = map[string]toolFormatter{
+	"Bash":      formatBashTool,
+	"Edit":      formatEditTool,
+	"TodoWrite": formatTodoWriteTool,
+}
+
+// renderToolCall renders a tool invocation: the tool name, plus a structured
+// view of its input for known tools.
+func (v *ChatView) renderToolCall(toolName, input string, width int) string {
+	toolLine := "  " + chatToolStyle.Render("["+toolName+"]")
+
+	format, ok := toolFormatters[toolName]
+	if !ok || input == "" {
+		// Shorten paths in tool input (e.g., file_path for Read/Write)
+		toolInput := transcript.ShortenPath(input, v.worktree)
+		return toolLine + " " + transcript.TruncateToolInput(toolInput)
+	}
+
+	summary, body := format(v.shortenPathsInLine(input), width-len(toolBodyIndent))
+	if summary != "" {
+		toolLine += " " + summary
+	}
+	lines := []string{toolLine}
+	for _, line := range body {
+		lines = append(lines, toolBodyIndent+line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatBashTool shows the command on its own lines, prompt-style.
+func formatBashTool(input string, width int) (string, []string) {
+	cmd := strings.Split(strings.TrimRight(input, "\n"), "\n")
+	body := make([]string, 0, min(len(cmd), maxToolBodyLines+1))
+	for i, line := range cmd {
+		if i == maxToolBodyLines {
+			body = append(body, chatTruncatedStyle.Render(fmt.Sprintf("... (%d more lines)", len(cmd)-i)))
+			break
+		}
+		prefix := "  "
+		if i == 0 {
+			prefix = "$ "
+		}
+		body = append(body, truncateLine(prefix+line, width))
+	}
+	return "", body
+}
+
+// formatEditTool shows the edited file on the tool line and a compact,
+// colored hunk below it. The input is the file path followed by "- " and
+// "+ " lines, as produced by the backend.
+func formatEditTool(input string, width int) (string, []string) {
+	lines := strings.Split(input, "\n")
+	path, hunk := lines[0], lines[1:]
+
+	body := make([]string, 0, min(len(hunk), maxToolBodyLines+1))
+	for i, line := range hunk {
+		if i == maxToolBodyLines {
+			body = append(body, chatTruncatedStyle.Render(fmt.Sprintf("... (%d more lines)", len(hunk)-i)))
+			break
+		}
+		line = truncateLine(line, width)
+		switch {
+		case strings.HasPrefix(line, "- "):
+			line = chatDiffRemoveStyle.Render(line)
+		case strings.HasPrefix(line, "+ "):
+			line = chatDiffAddStyle.Render(line)
+		}
+		body = append(body, line)
+	}
+	return path, body
+}
+
+// formatTodoWriteTool renders the todo list as a checklist, with a count of
+// completed items on the tool line.
+func formatTodoWriteTool(input string, width int) (string, []string) {
+	items := strings.Split(input, "\n")
+	done := 0
+	body := make([]string, 0, len(items))
+	for _, item := range items {
+		line := truncateLine(item, width)
+		switch {
+		case strings.HasPrefix(item, "[x] "):
+			done++
+			line = chatTodoDoneStyle.Render(line)
+		case strings.HasPrefix(item, "[~] "):
+			line = chatTodoActiveStyle.Render(line)
+		}
+		body = append(body, line)
+	}
+	return fmt.Sprintf("%d/%d done", done, len(items)), body
+}
+
+// truncateLine cuts line to maxWidth, marking the cut with "...".
+func truncateLine(line string, maxWidth int) string {
+	if maxWidth <= 3 || len(line) <= maxWidth {
+		return line
+	}
+	return line[:maxWidth-3] + "..."
+}
+
 // wrapText wraps text to the given width with optional indentation for continuation lines.
 func wrapText(text string, width, indent int) string {
 	if width <= 0 {
@@ -489,19 +852,6 @@ func wrapText(text string, width, indent int) string {
 	return strings.Join(lines, "\n")
 }
 
-// truncateToolInput truncates tool input for display.
-func truncateToolInput(input string) string {
-	// Remove newlines for single-line display
-	input = strings.ReplaceAll(input, "\n", " ")
-	input = strings.TrimSpace(input)
-
-	const maxLen = 80
-	if len(input) > maxLen {
-		return input[:maxLen-3] + "..."
-	}
-	return input
-}
-
 // truncateResult truncates tool result for display.
 // Applies path shortening to each line.
 func (v *ChatView) truncateResult(result string, maxWidth int) string {
@@ -547,33 +897,10 @@ func (v *ChatView) summarizeToolResult(toolName, result string, maxWidth int, is
 		return v.formatFullResult(result, maxWidth)
 	}
 
-	switch toolName {
-	case "Read":
-		// Count lines in the result
-		lineCount := strings.Count(result, "\n")
-		if !strings.HasSuffix(result, "\n") && len(result) > 0 {
-			lineCount++
-		}
-		return "Read " + formatLineCount(lineCount)
-
-	case "Grep":
-		// Count lines (matches) in the result
-		lines := strings.Split(result, "\n")
-		// Filter empty lines
-		matchCount := 0
-		for _, line := range lines {
-			if strings.TrimSpace(line) != "" {
-				matchCount++
-			}
-		}
-		if matchCount == 0 {
-			return "No matches"
-		}
-		return formatMatchCount(matchCount)
-
-	default:
-		return v.truncateResult(result, maxWidth)
+	if summary, ok := transcript.SummarizeToolResult(toolName, result); ok {
+		return summary
 	}
+	return v.truncateResult(result, maxWidth)
 }
 
 // formatFullResult formats a result for display without line truncation.
@@ -604,57 +931,48 @@ func (v *ChatView) formatFullResult(result string, maxWidth int) string {
 	return strings.Join(parts, "\n")
 }
 
-// formatLineCount formats a line count for display.
-func formatLineCount(count int) string {
-	if count == 1 {
-		return "1 line"
-	}
-	return formatNumber(count) + " lines"
+// shortenPathsInLine shortens any absolute paths matching the worktree prefix within a line.
+// This handles common output formats like grep results (path:line:content).
+func (v *ChatView) shortenPathsInLine(line string) string {
+	return transcript.ShortenPathsInLine(line, v.worktree)
 }
 
-// formatMatchCount formats a match count for display.
-func formatMatchCount(count int) string {
-	if count == 1 {
-		return "1 match"
+// detailsSummary returns the agent's detailed state as header items, most
+// important first, or nil until details are fetched.
+func (v ChatView) detailsSummary(now time.Time) []string {
+	d := v.details
+	if d == nil || d.Agent.ID != v.agentID {
+		return nil
 	}
-	return formatNumber(count) + " matches"
-}
-
-// formatNumber formats a number as a string.
-func formatNumber(n int) string {
-	return strconv.Itoa(n)
-}
-
-// shortenPath replaces a worktree prefix in a path with "./" for more readable display.
-// If the path doesn't start with the worktree prefix, it's returned unchanged.
-func shortenPath(path, worktree string) string {
-	if worktree == "" || path == "" {
-		return path
+	var items []string
+	if len(d.Claims) > 0 {
+		items = append(items, strings.Join(d.Claims, ","))
+	}
+	if d.WorktreeDirty {
+		items = append(items, "uncommitted changes")
 	}
-	// Ensure worktree has no trailing slash for consistent matching
-	worktree = strings.TrimSuffix(worktree, "/")
-	// Check for exact match (path is the worktree dir itself)
-	if path == worktree {
-		return "."
+	if d.PendingRequests > 0 {
+		items = append(items, fmt.Sprintf("%d waiting", d.PendingRequests))
 	}
-	// Check for prefix with trailing slash
-	prefix := worktree + "/"
-	if strings.HasPrefix(path, prefix) {
-		return "./" + path[len(prefix):]
+	if ctx := d.Usage.ContextTokens(); ctx > 0 {
+		items = append(items, formatTokenCount(ctx)+" ctx")
 	}
-	return path
+	if !d.LastActivity.IsZero() {
+		items = append(items, "active "+formatDuration(now.Sub(d.LastActivity))+" ago")
+	}
+	return items
 }
 
-// shortenPathsInLine shortens any absolute paths matching the worktree prefix within a line.
-// This handles common output formats like grep results (path:line:content).
-func (v *ChatView) shortenPathsInLine(line string) string {
-	if v.worktree == "" {
-		return line
+// formatTokenCount formats a token count compactly (e.g., 950, 12.3k, 1.2M).
+func formatTokenCount(n int) string {
+	switch {
+	case n < 1000:
+		return strconv.Itoa(n)
+	case n < 1_000_000:
+		return strconv.FormatFloat(float64(n)/1000, 'f', 1, 64) + "k"
+	default:
+		return strconv.FormatFloat(float64(n)/1_000_000, 'f', 1, 64) + "M"
 	}
-	worktree := strings.TrimSuffix(v.worktree, "/")
-	prefix := worktree + "/"
-	// Replace all occurrences of the worktree prefix with "./"
-	return strings.ReplaceAll(line, prefix, "./")
 }
 
 // View renders the chat view.
@@ -668,6 +986,15 @@ func (v ChatView) View() string {
 		return chatViewFocusedBorderStyle.Width(v.width - 2).Height(v.height - 2).Render(inner)
 	}
 
+	// Handle new-agent project selection mode
+	if v.agentProjectSelect {
+		innerWidth := v.width - 2
+		header := paneTitleFocusedStyle.Width(innerWidth).Render("New Agent")
+		content := v.renderProjectPicker("Select a project for the new agent:", v.agentProjects, v.agentProjectIndex, v.agentProjectFilter)
+		inner := lipgloss.JoinVertical(lipgloss.Left, header, content)
+		return chatViewFocusedBorderStyle.Width(v.width - 2).Height(v.height - 2).Render(inner)
+	}
+
 	// Handle plan project selection mode
 	if v.planProjectSelect {
 		innerWidth := v.width - 2
@@ -711,23 +1038,41 @@ func (v ChatView) View() string {
 	}
 
 	// Header showing agent info - use pane title styles for consistency
-	headerText := v.agentID
-	if v.project != "" {
-		headerText += " · " + v.project
-	}
-
 	titleStyle := paneTitleStyle
 	if v.focused {
 		titleStyle = paneTitleFocusedStyle
 	}
-	header := titleStyle.Width(v.width - 2).Render(headerText)
+
+	headerText := v.agentID
+	if v.name != "" {
+		// Render each segment on the title background so the dimmed ID
+		// doesn't reset it for the text that follows
+		segment := titleStyle.UnsetPadding()
+		headerText = segment.Render(v.name+" ") + segment.Faint(true).Render(v.agentID)
+	}
+	var rest string
+	if v.project != "" {
+		rest += " · " + v.project
+	}
+	if v.model != "" {
+		rest += " · " + v.model
+	}
+	// Details go in as far as they fit on the title line (less its padding)
+	for _, item := range v.detailsSummary(time.Now()) {
+		if lipgloss.Width(headerText+rest+" · "+item) > v.width-4 {
+			break
+		}
+		rest += " · " + item
+	}
+	if v.name != "" && rest != "" {
+		rest = titleStyle.UnsetPadding().Render(rest)
+	}
+	header := titleStyle.Width(v.width - 2).Render(headerText + rest)
 
 	// Viewport content
 	var content string
 	emptyHeight := v.height - 3
-	if v.pendingPermission != nil {
-		emptyHeight -= 2
-	}
+	emptyHeight -= v.permissionHeight()
 	if v.pendingUserQuestion != nil {
 		emptyHeight -= v.calculateUserQuestionHeight()
 	}
@@ -792,32 +1137,70 @@ func (v ChatView) View() string {
 	return borderStyle.Width(v.width - 2).Height(v.height - 2).Render(inner)
 }
 
-// renderPendingPermission renders the pending permission request bar.
+// renderPendingPermission renders the pending permission request bar: the
+// tool and a preview of what it will do, decoded from its input, so the
+// request isn't approved blind.
 func (v ChatView) renderPendingPermission() string {
 	if v.pendingPermission == nil {
 		return ""
 	}
 
-	// Format tool input for display
-	toolInput := string(v.pendingPermission.ToolInput)
-	// Remove outer braces and quotes for cleaner display
-	toolInput = strings.TrimPrefix(toolInput, "{")
-	toolInput = strings.TrimSuffix(toolInput, "}")
-	toolInput = strings.ReplaceAll(toolInput, "\n", " ")
-	toolInput = strings.TrimSpace(toolInput)
-
-	// Truncate for display
-	maxLen := v.width - 40
-	if maxLen < 20 {
-		maxLen = 20
+	summary, body := v.permissionPreview(v.width - 6)
+	label := pendingPermissionLabelStyle.Render("🔐 Permission:")
+	toolName := pendingPermissionToolStyle.Render("[" + v.pendingPermission.ToolName + "]")
+	if n := v.pendingPermission.Duplicates; n > 0 {
+		// Identical requests collapsed into this one are answered with it
+		toolName += pendingPermissionToolStyle.Render(fmt.Sprintf(" ×%d", n+1))
 	}
-	if len(toolInput) > maxLen {
-		toolInput = toolInput[:maxLen-3] + "..."
+	lines := []string{label + " " + toolName + " " + summary}
+	for _, line := range body {
+		lines = append(lines, toolBodyIndent+line)
 	}
+	return pendingPermissionStyle.Width(v.width - 4).Render(strings.Join(lines, "\n"))
+}
 
-	label := pendingPermissionLabelStyle.Render("🔐 Permission:")
-	toolName := pendingPermissionToolStyle.Render("[" + v.pendingPermission.ToolName + "]")
-	return pendingPermissionStyle.Width(v.width - 4).Render(label + " " + toolName + " " + toolInput)
+// permissionHeight returns the lines reserved for the pending permission
+// bar: the tool line, its preview, and 1 line padding.
+func (v *ChatView) permissionHeight() int {
+	if v.pendingPermission == nil {
+		return 0
+	}
+	_, body := v.permissionPreview(v.width - 6)
+	return 2 + len(body)
+}
+
+// permissionPreview decodes the pending permission's tool input the way
+// chat shows tool calls: a summary for the tool line and, for tools with a
+// formatter, body lines such as the command or the edit's hunk. Write shows
+// the content it will write as added lines.
+func (v *ChatView) permissionPreview(width int) (summary string, body []string) {
+	req := v.pendingPermission
+	input := backend.FormatToolInput(req.ToolName, req.ToolInput)
+	format, ok := toolFormatters[req.ToolName]
+	if req.ToolName == "Write" {
+		input, format, ok = writePreviewInput(input, req.ToolInput), formatEditTool, true
+	}
+	input = v.shortenPathsInLine(input)
+	if !ok || input == "" {
+		return truncateLine(strings.ReplaceAll(input, "\n", " "), max(v.width-40, 20)), nil
+	}
+	return format(input, width-len(toolBodyIndent))
+}
+
+// writePreviewInput returns a Write tool's path followed by its content as
+// "+ " lines, the form formatEditTool renders.
+func writePreviewInput(path string, raw json.RawMessage) string {
+	var data struct {
+		Content string `json:"content"`
+	}
+	if json.Unmarshal(raw, &data) != nil || data.Content == "" {
+		return path
+	}
+	lines := []string{path}
+	for _, line := range strings.Split(strings.TrimSuffix(data.Content, "\n"), "\n") {
+		lines = append(lines, "+ "+line)
+	}
+	return strings.Join(lines, "\n")
 }
 
 // renderAbortConfirmation renders the abort confirmation bar.
@@ -843,20 +1226,32 @@ func (v ChatView) renderPendingUserQuestion() string {
 
 	// Question header with icon
 	headerLine := userQuestionHeaderStyle.Render("❓ " + q.Question)
+	if q.MultiSelect {
+		headerLine += userQuestionDescStyle.Render(" (select all that apply)")
+	}
 	lines = append(lines, headerLine)
 
 	// Render each option
 	for i, opt := range q.Options {
+		label := opt.Label
+		if q.MultiSelect {
+			if v.questionChecked[i] {
+				label = "[x] " + label
+			} else {
+				label = "[ ] " + label
+			}
+		}
+
 		var line string
 		if i == v.questionSelected {
 			// Selected option
-			line = userQuestionSelectedStyle.Render("▶ " + opt.Label)
+			line = userQuestionSelectedStyle.Render("▶ " + label)
 			if opt.Description != "" {
 				line += userQuestionDescStyle.Render(" - " + opt.Description)
 			}
 		} else {
 			// Unselected option
-			line = userQuestionOptionStyle.Render("  " + opt.Label)
+			line = userQuestionOptionStyle.Render("  " + label)
 			if opt.Description != "" {
 				line += userQuestionDescStyle.Render(" - " + opt.Description)
 			}
@@ -872,6 +1267,10 @@ func (v ChatView) renderPendingUserQuestion() string {
 		lines = append(lines, userQuestionOptionStyle.Render("  Other")+userQuestionDescStyle.Render(" - Enter custom response"))
 	}
 
+	if v.questionError != "" {
+		lines = append(lines, userQuestionErrorStyle.Render("✗ "+v.questionError))
+	}
+
 	// Join all lines
 	content := strings.Join(lines, "\n")
 
@@ -919,12 +1318,34 @@ func (v *ChatView) ClearPlanPromptMode() {
 	v.updateViewportSize()
 }
 
+// SetAgentProjectSelection sets the new-agent project selection mode state.
+func (v *ChatView) SetAgentProjectSelection(projects []string, selectedIndex int, filter string) {
+	v.agentProjectSelect = true
+	v.agentProjects = projects
+	v.agentProjectIndex = selectedIndex
+	v.agentProjectFilter = filter
+	v.updateViewportSize()
+}
+
+// ClearAgentProjectSelection clears new-agent project selection mode.
+func (v *ChatView) ClearAgentProjectSelection() {
+	v.agentProjectSelect = false
+	v.agentProjects = nil
+	v.agentProjectIndex = 0
+	v.agentProjectFilter = ""
+	v.updateViewportSize()
+}
+
 // renderPlanProjectSelection renders the project selection UI.
 func (v *ChatView) renderPlanProjectSelection() string {
 	if !v.planProjectSelect {
 		return ""
 	}
+	return v.renderProjectPicker("Select a project to plan for:", v.planProjects, v.planProjectIndex, v.planProjectFilter)
+}
 
+// renderProjectPicker renders a filterable project list under title.
+func (v *ChatView) renderProjectPicker(title string, projects []string, selectedIndex int, filter string) string {
 	style := lipgloss.NewStyle().
 		Background(lipgloss.Color("#2B4B3B")). // Dark green background
 		Padding(0, 1)
@@ -946,25 +1367,25 @@ func (v *ChatView) renderPlanProjectSelection() string {
 		Background(lipgloss.Color("#3B5B4B"))
 
 	var lines []string
-	lines = append(lines, headerStyle.Render("Select a project to plan for:"))
+	lines = append(lines, headerStyle.Render(title))
 
 	// Show filter input
-	filterDisplay := v.planProjectFilter
+	filterDisplay := filter
 	if filterDisplay == "" {
 		filterDisplay = "Type to filter..."
 	}
 	lines = append(lines, filterStyle.Render("▸ "+filterDisplay+"█"))
 	lines = append(lines, "") // Empty line
 
-	if len(v.planProjects) == 0 {
+	if len(projects) == 0 {
 		// No matching projects
 		noMatchStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FF6666")).
 			Italic(true)
 		lines = append(lines, noMatchStyle.Render("  No matching projects"))
 	} else {
-		for i, project := range v.planProjects {
-			if i == v.planProjectIndex {
+		for i, project := range projects {
+			if i == selectedIndex {
 				lines = append(lines, selectedStyle.Render("▶ "+project))
 			} else {
 				lines = append(lines, optionStyle.Render("  "+project))
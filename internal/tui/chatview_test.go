@@ -1,8 +1,13 @@
 package tui
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/tessro/fab/internal/config"
 	"github.com/tessro/fab/internal/daemon"
 )
 
@@ -127,67 +132,6 @@ func generateLines(n int) string {
 	return s
 }
 
-func TestShortenPath(t *testing.T) {
-	tests := []struct {
-		name     string
-		path     string
-		worktree string
-		want     string
-	}{
-		{
-			name:     "path within worktree",
-			path:     "/home/user/.fab/worktrees/proj/internal/tui/chatview.go",
-			worktree: "/home/user/.fab/worktrees/proj",
-			want:     "./internal/tui/chatview.go",
-		},
-		{
-			name:     "path is worktree root",
-			path:     "/home/user/.fab/worktrees/proj",
-			worktree: "/home/user/.fab/worktrees/proj",
-			want:     ".",
-		},
-		{
-			name:     "path outside worktree",
-			path:     "/home/user/other/file.go",
-			worktree: "/home/user/.fab/worktrees/proj",
-			want:     "/home/user/other/file.go",
-		},
-		{
-			name:     "empty worktree",
-			path:     "/home/user/.fab/worktrees/proj/file.go",
-			worktree: "",
-			want:     "/home/user/.fab/worktrees/proj/file.go",
-		},
-		{
-			name:     "empty path",
-			path:     "",
-			worktree: "/home/user/.fab/worktrees/proj",
-			want:     "",
-		},
-		{
-			name:     "worktree with trailing slash",
-			path:     "/home/user/.fab/worktrees/proj/internal/file.go",
-			worktree: "/home/user/.fab/worktrees/proj/",
-			want:     "./internal/file.go",
-		},
-		{
-			name:     "similar prefix but not inside worktree",
-			path:     "/home/user/.fab/worktrees/proj-other/file.go",
-			worktree: "/home/user/.fab/worktrees/proj",
-			want:     "/home/user/.fab/worktrees/proj-other/file.go",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := shortenPath(tt.path, tt.worktree)
-			if got != tt.want {
-				t.Errorf("shortenPath(%q, %q) = %q, want %q", tt.path, tt.worktree, got, tt.want)
-			}
-		})
-	}
-}
-
 func TestShortenPathsInLine(t *testing.T) {
 	cv := NewChatView()
 	cv.worktree = "/home/user/.fab/worktrees/proj"
@@ -240,42 +184,136 @@ func TestShortenPathsInLineEmptyWorktree(t *testing.T) {
 	}
 }
 
-func TestFormatLineCount(t *testing.T) {
+func TestRenderToolCall(t *testing.T) {
+	cv := NewChatView()
+	cv.worktree = "/home/user/.fab/worktrees/proj"
+
 	tests := []struct {
-		count int
-		want  string
+		name      string
+		toolName  string
+		input     string
+		wantLines []string // substrings expected on each rendered line, in order
 	}{
-		{0, "0 lines"},
-		{1, "1 line"},
-		{2, "2 lines"},
-		{100, "100 lines"},
-		{1000, "1000 lines"},
+		{
+			name:      "Bash command on its own line",
+			toolName:  "Bash",
+			input:     "go test ./...",
+			wantLines: []string{"[Bash]", "$ go test ./..."},
+		},
+		{
+			name:      "multi-line Bash command",
+			toolName:  "Bash",
+			input:     "cat <<EOF\nhello\nEOF",
+			wantLines: []string{"[Bash]", "$ cat <<EOF", "  hello", "  EOF"},
+		},
+		{
+			name:      "Edit shows file and hunk",
+			toolName:  "Edit",
+			input:     "/home/user/.fab/worktrees/proj/main.go\n- x := 1\n+ x := 2",
+			wantLines: []string{"[Edit] ./main.go", "- x := 1", "+ x := 2"},
+		},
+		{
+			name:      "TodoWrite renders checklist",
+			toolName:  "TodoWrite",
+			input:     "[x] Write tests\n[~] Fix bug\n[ ] Ship it",
+			wantLines: []string{"[TodoWrite] 1/3 done", "[x] Write tests", "[~] Fix bug", "[ ] Ship it"},
+		},
+		{
+			name:      "unknown tool falls back to truncated input",
+			toolName:  "Read",
+			input:     "/home/user/.fab/worktrees/proj/main.go",
+			wantLines: []string{"[Read] ./main.go"},
+		},
 	}
 
 	for _, tt := range tests {
-		got := formatLineCount(tt.count)
-		if got != tt.want {
-			t.Errorf("formatLineCount(%d) = %q, want %q", tt.count, got, tt.want)
-		}
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(cv.renderToolCall(tt.toolName, tt.input, 80), "\n")
+			if len(lines) != len(tt.wantLines) {
+				t.Fatalf("got %d lines %q, want %d", len(lines), lines, len(tt.wantLines))
+			}
+			for i, want := range tt.wantLines {
+				if !strings.Contains(lines[i], want) {
+					t.Errorf("line %d = %q, want it to contain %q", i, lines[i], want)
+				}
+			}
+		})
 	}
 }
 
-func TestFormatMatchCount(t *testing.T) {
+func TestRenderToolCallCapsLongCommands(t *testing.T) {
+	cv := NewChatView()
+	cmd := strings.TrimSuffix(strings.Repeat("echo hi\n", maxToolBodyLines+3), "\n")
+
+	lines := strings.Split(cv.renderToolCall("Bash", cmd, 80), "\n")
+	// Tool line, capped command lines, and the "more lines" marker
+	if len(lines) != maxToolBodyLines+2 {
+		t.Fatalf("got %d lines, want %d", len(lines), maxToolBodyLines+2)
+	}
+	if last := lines[len(lines)-1]; !strings.Contains(last, "3 more lines") {
+		t.Errorf("last line = %q, want a 3 more lines marker", last)
+	}
+}
+
+func TestPermissionPreview(t *testing.T) {
+	cv := NewChatView()
+	cv.worktree = "/home/user/.fab/worktrees/proj"
+	cv.width = 80
+
 	tests := []struct {
-		count int
-		want  string
+		name        string
+		toolName    string
+		input       string
+		wantSummary string
+		wantBody    []string
 	}{
-		{0, "0 matches"},
-		{1, "1 match"},
-		{2, "2 matches"},
-		{100, "100 matches"},
+		{
+			name:     "Bash shows the command",
+			toolName: "Bash",
+			input:    `{"command":"rm -rf build\nmake"}`,
+			wantBody: []string{"$ rm -rf build", "  make"},
+		},
+		{
+			name:        "Edit shows the hunk",
+			toolName:    "Edit",
+			input:       `{"file_path":"/home/user/.fab/worktrees/proj/main.go","old_string":"x := 1","new_string":"x := 2"}`,
+			wantSummary: "./main.go",
+			wantBody:    []string{"- x := 1", "+ x := 2"},
+		},
+		{
+			name:        "Write shows the content as added lines",
+			toolName:    "Write",
+			input:       `{"file_path":"/home/user/.fab/worktrees/proj/new.go","content":"package main\n"}`,
+			wantSummary: "./new.go",
+			wantBody:    []string{"+ package main"},
+		},
+		{
+			name:        "other tools show decoded input on one line",
+			toolName:    "Read",
+			input:       `{"file_path":"/home/user/.fab/worktrees/proj/main.go"}`,
+			wantSummary: "./main.go",
+		},
 	}
 
 	for _, tt := range tests {
-		got := formatMatchCount(tt.count)
-		if got != tt.want {
-			t.Errorf("formatMatchCount(%d) = %q, want %q", tt.count, got, tt.want)
-		}
+		t.Run(tt.name, func(t *testing.T) {
+			cv.SetPendingPermission(&daemon.PermissionRequest{ID: "p1", ToolName: tt.toolName, ToolInput: []byte(tt.input)})
+			summary, body := cv.permissionPreview(74)
+			if !strings.Contains(summary, tt.wantSummary) {
+				t.Errorf("summary = %q, want it to contain %q", summary, tt.wantSummary)
+			}
+			if len(body) != len(tt.wantBody) {
+				t.Fatalf("body = %q, want %d lines", body, len(tt.wantBody))
+			}
+			for i, want := range tt.wantBody {
+				if !strings.Contains(body[i], want) {
+					t.Errorf("body[%d] = %q, want it to contain %q", i, body[i], want)
+				}
+			}
+			if got, want := cv.permissionHeight(), 2+len(tt.wantBody); got != want {
+				t.Errorf("permissionHeight() = %d, want %d", got, want)
+			}
+		})
 	}
 }
 
@@ -473,3 +511,275 @@ func TestChatViewSetEntriesPreservesOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestChatViewAppendDelta(t *testing.T) {
+	cv := NewChatView()
+	cv.SetSize(80, 24)
+	cv.SetAgent("test-agent", "test-project", "claude", "/test/worktree")
+
+	cv.AppendEntry(daemon.ChatEntryDTO{Role: "user", Content: "hi", Timestamp: "2024-01-15T10:00:00Z"})
+	cv.AppendDelta("Hel")
+	cv.AppendDelta("lo")
+	if len(cv.entries) != 2 || cv.entries[1].Role != "assistant" || cv.entries[1].Content != "Hello" {
+		t.Fatalf("entries after deltas = %+v, want user entry + assistant \"Hello\"", cv.entries)
+	}
+
+	// The complete entry replaces the in-progress one
+	cv.AppendEntry(daemon.ChatEntryDTO{Role: "assistant", Content: "Hello there", Seq: 2})
+	if len(cv.entries) != 2 || cv.entries[1].Content != "Hello there" || cv.entries[1].Seq != 2 {
+		t.Fatalf("entries after completion = %+v, want final entry in place of deltas", cv.entries)
+	}
+
+	// A new turn starts a new entry rather than extending the completed one
+	cv.AppendEntry(daemon.ChatEntryDTO{Role: "tool", ToolName: "Bash"})
+	cv.AppendDelta("Done")
+	if len(cv.entries) != 4 || cv.entries[3].Content != "Done" {
+		t.Fatalf("entries after second turn = %+v, want 4 with trailing \"Done\"", cv.entries)
+	}
+
+	// Switching agents drops the in-progress state
+	cv.SetAgent("other-agent", "test-project", "claude", "/test/worktree")
+	cv.AppendEntry(daemon.ChatEntryDTO{Role: "assistant", Content: "other"})
+	if len(cv.entries) != 1 {
+		t.Errorf("entries after switching agents = %d, want 1", len(cv.entries))
+	}
+}
+
+func TestChatViewOlderHistoryPaging(t *testing.T) {
+	cv := NewChatView()
+	cv.SetSize(80, 24)
+	cv.SetAgent("test-agent", "test-project", "claude", "/test/worktree")
+
+	var page []daemon.ChatEntryDTO
+	for i := range 30 {
+		page = append(page, daemon.ChatEntryDTO{Role: "user", Content: fmt.Sprintf("recent %d", i)})
+	}
+	cv.SetEntries(page)
+	cv.SetHistoryCursor("100")
+
+	// Not at top after SetEntries, so nothing to load yet
+	if _, ok := cv.BeginOlderHistoryLoad(); ok {
+		t.Fatal("BeginOlderHistoryLoad() should not fire when scrolled to bottom")
+	}
+
+	cv.ScrollToTop()
+	cursor, ok := cv.BeginOlderHistoryLoad()
+	if !ok || cursor != "100" {
+		t.Fatalf("BeginOlderHistoryLoad() = %q, %v; want \"100\", true", cursor, ok)
+	}
+	if _, ok := cv.BeginOlderHistoryLoad(); ok {
+		t.Fatal("BeginOlderHistoryLoad() should not fire while a fetch is in flight")
+	}
+
+	cv.PrependEntries([]daemon.ChatEntryDTO{{Role: "user", Content: "older"}}, "")
+	if got := cv.entries[0].Content; got != "older" {
+		t.Errorf("entries[0].Content = %q, want %q", got, "older")
+	}
+	if len(cv.entries) != 31 {
+		t.Errorf("entry count = %d, want 31", len(cv.entries))
+	}
+
+	// Empty cursor means no more history
+	cv.ScrollToTop()
+	if _, ok := cv.BeginOlderHistoryLoad(); ok {
+		t.Error("BeginOlderHistoryLoad() should not fire without a cursor")
+	}
+}
+
+func TestChatViewMultiSelectAnswer(t *testing.T) {
+	v := NewChatView()
+	v.SetPendingUserQuestion(&daemon.UserQuestion{
+		ID: "q1",
+		Questions: []daemon.QuestionItem{{
+			Header:      "Features",
+			MultiSelect: true,
+			Options:     []daemon.QuestionOption{{Label: "A"}, {Label: "B"}, {Label: "C"}},
+		}},
+	})
+
+	// Nothing toggled: falls back to the highlighted option
+	if _, label, _ := v.GetSelectedAnswer(); label != "A" {
+		t.Errorf("label = %q, want A", label)
+	}
+
+	v.QuestionToggle() // A
+	v.QuestionMoveDown()
+	v.QuestionMoveDown()
+	v.QuestionToggle() // C
+	v.QuestionMoveDown()
+	v.QuestionToggle() // "Other" can't be toggled
+
+	header, label, isOther := v.GetSelectedAnswer()
+	if header != "Features" || !isOther {
+		t.Fatalf("got header %q isOther %v", header, isOther)
+	}
+	v.QuestionMoveUp()
+	if _, label, _ = v.GetSelectedAnswer(); label != "A, C" {
+		t.Errorf("label = %q, want \"A, C\"", label)
+	}
+
+	if _, answer, err := v.OtherAnswer("D"); err != nil || answer != "A, C, D" {
+		t.Errorf("OtherAnswer = %q, %v; want \"A, C, D\"", answer, err)
+	}
+}
+
+func TestChatViewOtherAnswerValidation(t *testing.T) {
+	v := NewChatView()
+	v.SetSize(80, 24)
+	v.SetPendingUserQuestion(&daemon.UserQuestion{
+		ID: "q1",
+		Questions: []daemon.QuestionItem{{
+			Header:   "Port",
+			Options:  []daemon.QuestionOption{{Label: "8080"}},
+			Validate: `^[0-9]+$`,
+		}},
+	})
+
+	height := v.viewport.Height
+	if _, _, err := v.OtherAnswer("eighty"); err == nil {
+		t.Fatal("expected validation error")
+	}
+	if v.questionError == "" {
+		t.Error("expected validation error to be shown")
+	}
+	// The error line shrinks the viewport
+	if v.viewport.Height != height-1 {
+		t.Errorf("viewport height with error = %d, want %d", v.viewport.Height, height-1)
+	}
+
+	header, answer, err := v.OtherAnswer("80")
+	if err != nil || header != "Port" || answer != "80" {
+		t.Errorf("OtherAnswer = %q, %q, %v", header, answer, err)
+	}
+	if v.questionError != "" {
+		t.Errorf("validation error not cleared: %q", v.questionError)
+	}
+	if v.viewport.Height != height {
+		t.Errorf("viewport height after clearing error = %d, want %d", v.viewport.Height, height)
+	}
+
+	// A new question resets toggles and errors
+	v.questionError = "stale"
+	v.SetPendingUserQuestion(&daemon.UserQuestion{ID: "q2", Questions: []daemon.QuestionItem{{Header: "X"}}})
+	if v.questionError != "" {
+		t.Error("expected error reset for new question")
+	}
+}
+
+func TestChatViewMaxEntries(t *testing.T) {
+	entry := func(i int) daemon.ChatEntryDTO {
+		return daemon.ChatEntryDTO{Role: "user", Content: fmt.Sprintf("msg %d", i), Seq: int64(i)}
+	}
+
+	t.Run("agent history resumes paging from first kept entry", func(t *testing.T) {
+		cv := NewChatView()
+		cv.SetMaxEntries(5)
+		cv.SetSize(80, 24)
+		cv.SetAgent("test-agent", "test-project", "claude", "/test/worktree")
+
+		for i := range 8 {
+			cv.AppendEntry(entry(i))
+		}
+		if len(cv.entries) != 5 {
+			t.Fatalf("entry count = %d, want 5", len(cv.entries))
+		}
+		if cv.olderCursor != "3" {
+			t.Errorf("olderCursor = %q, want %q", cv.olderCursor, "3")
+		}
+		if cv.truncated {
+			t.Error("truncated should be false when older entries can be reloaded")
+		}
+	})
+
+	t.Run("planner history shows truncation marker", func(t *testing.T) {
+		cv := NewChatView()
+		cv.SetMaxEntries(5)
+		cv.SetSize(80, 24)
+		cv.SetAgent("plan:abc123", "test-project", "claude", "")
+
+		for i := range 8 {
+			cv.AppendEntry(entry(i))
+		}
+		if cv.olderCursor != "" {
+			t.Errorf("olderCursor = %q, want empty", cv.olderCursor)
+		}
+		if !cv.truncated {
+			t.Fatal("truncated should be true")
+		}
+		cv.ScrollToTop()
+		if !strings.Contains(cv.viewport.View(), "older messages truncated") {
+			t.Error("expected truncation marker at top of chat view")
+		}
+	})
+
+	t.Run("non-positive cap restores default", func(t *testing.T) {
+		cv := NewChatView()
+		cv.SetMaxEntries(0)
+		if cv.maxEntries != config.DefaultMaxChatEntries {
+			t.Errorf("maxEntries = %d, want %d", cv.maxEntries, config.DefaultMaxChatEntries)
+		}
+	})
+}
+
+func TestChatViewDetailsSummary(t *testing.T) {
+	now := time.Now()
+	cv := NewChatView()
+	cv.SetAgent("abc123", "proj", "claude", "")
+
+	if got := cv.detailsSummary(now); got != nil {
+		t.Errorf("detailsSummary() without details = %v, want nil", got)
+	}
+
+	cv.SetDetails(&daemon.AgentGetResponse{
+		Agent:           daemon.AgentStatus{ID: "abc123"},
+		WorktreeDirty:   true,
+		Claims:          []string{"FAB-1", "FAB-2"},
+		PendingRequests: 2,
+		Usage:           daemon.TokenUsage{InputTokens: 1200, CacheReadInputTokens: 40000},
+		LastActivity:    now.Add(-3 * time.Minute),
+	})
+	want := []string{"FAB-1,FAB-2", "uncommitted changes", "2 waiting", "41.2k ctx", "active 3m ago"}
+	if got := cv.detailsSummary(now); !slices.Equal(got, want) {
+		t.Errorf("detailsSummary() = %q, want %q", got, want)
+	}
+
+	// Details of a previously selected agent are ignored
+	cv.SetDetails(&daemon.AgentGetResponse{Agent: daemon.AgentStatus{ID: "other"}, WorktreeDirty: true})
+	if got := cv.detailsSummary(now); got != nil {
+		t.Errorf("detailsSummary() for another agent = %v, want nil", got)
+	}
+}
+
+func TestFormatTokenCount(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "0"},
+		{950, "950"},
+		{12345, "12.3k"},
+		{1_200_000, "1.2M"},
+	}
+	for _, tt := range tests {
+		if got := formatTokenCount(tt.n); got != tt.want {
+			t.Errorf("formatTokenCount(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestChatViewAssistantName(t *testing.T) {
+	cv := NewChatView()
+	cv.SetSize(80, 20)
+	cv.SetAgent("abc123", "proj", "codex", "")
+	entry := daemon.ChatEntryDTO{Role: "assistant", Content: "Done."}
+
+	if got := cv.renderEntry(entry, ""); !strings.Contains(got, "Codex: ") {
+		t.Errorf("renderEntry() = %q, want the capitalized backend name", got)
+	}
+
+	cv.SetAssistantName("Bot")
+	got := cv.renderEntry(entry, "")
+	if !strings.Contains(got, "Bot: ") || strings.Contains(got, "Codex") {
+		t.Errorf("renderEntry() = %q, want the configured name", got)
+	}
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
@@ -18,6 +19,31 @@ func (m Model) tickCmd() tea.Cmd {
 	})
 }
 
+// backlogPollInterval is how often the TUI polls daemon status for issues
+// waiting on projects at their agent limit. The daemon caches the counts.
+const backlogPollInterval = 15 * time.Second
+
+// backlogTickCmd returns a command that triggers the next backlog poll.
+func (m Model) backlogTickCmd() tea.Cmd {
+	return tea.Tick(backlogPollInterval, func(time.Time) tea.Msg {
+		return backlogTickMsg{}
+	})
+}
+
+// fetchBacklog retrieves project statuses for the waiting issue count.
+func (m Model) fetchBacklog() tea.Cmd {
+	return func() tea.Msg {
+		if m.client == nil {
+			return nil
+		}
+		resp, err := m.client.Status()
+		if err != nil {
+			return backlogMsg{Err: err}
+		}
+		return backlogMsg{Projects: resp.Projects}
+	}
+}
+
 // EventStreamer is the interface for streaming events from the daemon.
 type EventStreamer interface {
 	StreamEvents(projects []string) (<-chan daemon.EventResult, error)
@@ -96,11 +122,59 @@ func (m Model) attemptReconnect() tea.Cmd {
 	}
 }
 
+// reconnectGaveUpError describes why automatic reconnection stopped,
+// including the reconnect settings in effect so users can tune them.
+func (m Model) reconnectGaveUpError() error {
+	return fmt.Errorf("connection lost after %d attempts (tui.reconnect-max=%d, tui.reconnect-base-delay=%s, tui.reconnect-max-delay=%s; press 'r' to reconnect)",
+		m.reconnectCount, m.maxReconnects, m.reconnectBaseDelay, m.reconnectMaxDelay)
+}
+
 // waitForEvent waits for the next event from the channel.
 func (m Model) waitForEvent() tea.Cmd {
 	return waitForEventCmd(m.eventChan)
 }
 
+// fetchAgentDetails retrieves one agent's detailed state for the chat header.
+func (m Model) fetchAgentDetails(agentID string) tea.Cmd {
+	return func() tea.Msg {
+		if m.client == nil {
+			return nil
+		}
+		details, err := m.client.AgentGet(agentID)
+		return agentDetailsMsg{AgentID: agentID, Details: details, Err: err}
+	}
+}
+
+// fetchDaemonVersion asks the daemon for its version, so the header can
+// warn when it differs from this CLI's.
+func (m Model) fetchDaemonVersion() tea.Cmd {
+	return func() tea.Msg {
+		if m.client == nil {
+			return nil
+		}
+		resp, err := m.client.Ping()
+		if err != nil {
+			return daemonVersionMsg{Err: err}
+		}
+		return daemonVersionMsg{Version: resp.Version}
+	}
+}
+
+// fetchPendingPermissions retrieves permission requests that were queued
+// before this TUI attached.
+func (m Model) fetchPendingPermissions() tea.Cmd {
+	return func() tea.Msg {
+		if m.client == nil {
+			return nil
+		}
+		resp, err := m.client.ListPendingPermissions("")
+		if err != nil {
+			return pendingPermissionsMsg{Err: err}
+		}
+		return pendingPermissionsMsg{Requests: resp.Requests}
+	}
+}
+
 // fetchAgentList retrieves the current agent list (including planners).
 func (m Model) fetchAgentList() tea.Cmd {
 	return func() tea.Msg {
@@ -109,50 +183,116 @@ func (m Model) fetchAgentList() tea.Cmd {
 			return nil
 		}
 		slog.Debug("tui.fetchAgentList: fetching agents")
-		resp, err := m.client.AgentList("")
+		resp, err := m.client.AgentListSorted("", m.agentSort, m.agentSortOrder)
 		if err != nil {
 			slog.Error("tui.fetchAgentList: AgentList failed", "error", err)
 			return agentListMsg{Err: err}
 		}
 		slog.Debug("tui.fetchAgentList: got agents", "count", len(resp.Agents))
 
-		// Also fetch planners and merge them into the list
-		agents := resp.Agents
+		var planners []daemon.PlannerStatus
 		plannerResp, err := m.client.PlanList("")
 		if err == nil && plannerResp != nil {
-			slog.Debug("tui.fetchAgentList: got planners", "count", len(plannerResp.Planners))
-			for _, p := range plannerResp.Planners {
-				startedAt := time.Now()
-				if p.StartedAt != "" {
-					if t, err := time.Parse(time.RFC3339, p.StartedAt); err == nil {
-						startedAt = t
-					}
-				}
-				agentID := plannerAgentID(p.ID)
-				slog.Debug("tui.fetchAgentList: adding planner to list", "planner_id", p.ID, "agent_id", agentID)
-				backend := p.Backend
-				if backend == "" {
-					backend = "claude" // Default if not set
-				}
-				agents = append(agents, daemon.AgentStatus{
-					ID:          agentID,
-					Project:     p.Project,
-					State:       p.State,
-					Worktree:    p.WorkDir,
-					StartedAt:   startedAt,
-					Description: "Planner",
-					Backend:     backend,
-				})
-			}
+			planners = plannerResp.Planners
 		} else if err != nil {
 			slog.Warn("tui.fetchAgentList: PlanList failed", "error", err)
 		}
 
+		var manager *daemon.ManagerStatusResponse
+		if m.managerProject != "" {
+			manager, err = m.client.ManagerStatus(m.managerProject)
+			if err != nil {
+				slog.Warn("tui.fetchAgentList: ManagerStatus failed", "project", m.managerProject, "error", err)
+			}
+		}
+
+		agents := m.mergeAgentList(resp.Agents, planners, manager)
 		slog.Debug("tui.fetchAgentList: returning", "total_agents", len(agents))
 		return agentListMsg{Agents: agents}
 	}
 }
 
+// mergeAgentList adds planners and, if it's running, the manager we were
+// asked to attach to the daemon's agent list.
+func (m Model) mergeAgentList(agents []daemon.AgentStatus, planners []daemon.PlannerStatus, manager *daemon.ManagerStatusResponse) []daemon.AgentStatus {
+	slog.Debug("tui.mergeAgentList: got planners", "count", len(planners))
+	for _, p := range planners {
+		startedAt := time.Now()
+		if p.StartedAt != "" {
+			if t, err := time.Parse(time.RFC3339, p.StartedAt); err == nil {
+				startedAt = t
+			}
+		}
+		agentID := plannerAgentID(p.ID)
+		slog.Debug("tui.mergeAgentList: adding planner to list", "planner_id", p.ID, "agent_id", agentID)
+		backend := p.Backend
+		if backend == "" {
+			backend = "claude" // Default if not set
+		}
+		agents = append(agents, daemon.AgentStatus{
+			ID:          agentID,
+			Project:     p.Project,
+			State:       p.State,
+			Worktree:    p.WorkDir,
+			StartedAt:   startedAt,
+			Description: "Planner",
+			Backend:     backend,
+		})
+	}
+
+	// Include the manager we were asked to attach to; otherwise managers
+	// only appear in the list via manager_state events
+	if manager != nil && manager.Running {
+		startedAt := time.Now()
+		if t, err := time.Parse(time.RFC3339, manager.StartedAt); err == nil {
+			startedAt = t
+		}
+		agents = append([]daemon.AgentStatus{{
+			ID:          ManagerAgentID,
+			Project:     m.managerProject,
+			State:       manager.State,
+			Worktree:    manager.WorkDir,
+			StartedAt:   startedAt,
+			Description: "Manager",
+		}}, agents...)
+	}
+
+	// Planners and the attached manager were added here, so place them
+	// the same way the daemon placed the rest
+	daemon.SortAgents(agents, m.agentSort, m.agentSortOrder)
+	return agents
+}
+
+// fetchDashboard retrieves the agent list, pending permissions, and daemon
+// version in one round trip, in place of fetchAgentList,
+// fetchPendingPermissions, and fetchDaemonVersion.
+func (m Model) fetchDashboard() tea.Cmd {
+	return func() tea.Msg {
+		if m.client == nil {
+			return nil
+		}
+		resp, err := m.client.Dashboard(m.agentSort, m.agentSortOrder, m.managerProject)
+		if err != nil {
+			return dashboardMsg{Err: err}
+		}
+		for section, errMsg := range resp.Errors {
+			slog.Warn("tui.fetchDashboard: section failed", "section", section, "error", errMsg)
+		}
+
+		msg := dashboardMsg{Permissions: resp.Permissions}
+		if errMsg, ok := resp.Errors[daemon.DashboardAgents]; ok {
+			msg.AgentsErr = errors.New(errMsg)
+		} else {
+			msg.Agents = m.mergeAgentList(resp.Agents, resp.Planners, resp.Manager)
+		}
+		if resp.Status != nil {
+			msg.Version = resp.Status.Daemon.Version
+			msg.Projects = resp.Status.Projects
+		}
+		return msg
+	}
+}
+
 // sendAgentMessage sends a user message to an agent via stream-json.
 // project is required when agentID is "manager".
 func (m Model) sendAgentMessage(agentID, project, content string) tea.Cmd {
@@ -174,6 +314,10 @@ func (m Model) sendAgentMessage(agentID, project, content string) tea.Cmd {
 	}
 }
 
+// chatHistoryPageSize is how many agent chat entries to fetch per page.
+// The most recent page is fetched on select; older pages load on scroll-to-top.
+const chatHistoryPageSize = 200
+
 // fetchAgentChatHistory retrieves chat history for an agent (or manager/planner/director).
 // project is required when agentID is "manager".
 func (m Model) fetchAgentChatHistory(agentID, project string) tea.Cmd {
@@ -182,6 +326,7 @@ func (m Model) fetchAgentChatHistory(agentID, project string) tea.Cmd {
 			return agentChatHistoryMsg{AgentID: agentID, Entries: nil}
 		}
 		var entries []daemon.ChatEntryDTO
+		var nextCursor string
 		var err error
 		if isDirector(agentID) {
 			var resp *daemon.DirectorChatHistoryResponse
@@ -203,37 +348,80 @@ func (m Model) fetchAgentChatHistory(agentID, project string) tea.Cmd {
 			}
 		} else {
 			var resp *daemon.AgentChatHistoryResponse
-			resp, err = m.client.AgentChatHistory(agentID, 0) // 0 = all entries
+			resp, err = m.client.AgentChatHistory(agentID, chatHistoryPageSize, "")
 			if err == nil {
 				entries = resp.Entries
+				nextCursor = resp.NextCursor
 			}
 		}
 		if err != nil {
 			return agentChatHistoryMsg{AgentID: agentID, Err: err}
 		}
-		return agentChatHistoryMsg{AgentID: agentID, Entries: entries}
+		return agentChatHistoryMsg{AgentID: agentID, Entries: entries, NextCursor: nextCursor}
+	}
+}
+
+// fetchOlderChatHistory retrieves the page of agent chat history preceding cursor.
+func (m Model) fetchOlderChatHistory(agentID, cursor string) tea.Cmd {
+	return func() tea.Msg {
+		if m.client == nil {
+			return agentChatHistoryMsg{AgentID: agentID, Older: true}
+		}
+		resp, err := m.client.AgentChatHistory(agentID, chatHistoryPageSize, cursor)
+		if err != nil {
+			return agentChatHistoryMsg{AgentID: agentID, Older: true, Err: err}
+		}
+		return agentChatHistoryMsg{AgentID: agentID, Entries: resp.Entries, NextCursor: resp.NextCursor, Older: true}
 	}
 }
 
 // fetchProjectsForPlan retrieves the list of projects for plan mode.
 func (m Model) fetchProjectsForPlan() tea.Cmd {
+	return func() tea.Msg {
+		projects, err := m.sortedProjectNames()
+		return projectListMsg{Projects: projects, Err: err}
+	}
+}
+
+// fetchProjectsForAgent retrieves the list of projects for a new agent.
+func (m Model) fetchProjectsForAgent() tea.Cmd {
+	return func() tea.Msg {
+		projects, err := m.sortedProjectNames()
+		return agentProjectListMsg{Projects: projects, Err: err}
+	}
+}
+
+// sortedProjectNames returns the registered project names, sorted
+// alphabetically (case-insensitive).
+func (m Model) sortedProjectNames() ([]string, error) {
+	if m.client == nil {
+		return nil, fmt.Errorf("not connected")
+	}
+	resp, err := m.client.ProjectList()
+	if err != nil {
+		return nil, err
+	}
+	var projects []string
+	for _, p := range resp.Projects {
+		projects = append(projects, p.Name)
+	}
+	slices.SortFunc(projects, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return projects, nil
+}
+
+// createAgent creates a new agent for the given project.
+func (m Model) createAgent(project string) tea.Cmd {
 	return func() tea.Msg {
 		if m.client == nil {
-			return projectListMsg{Err: fmt.Errorf("not connected")}
+			return agentCreateResultMsg{Err: fmt.Errorf("not connected")}
 		}
-		resp, err := m.client.ProjectList()
+		resp, err := m.client.AgentCreate(project, "", "")
 		if err != nil {
-			return projectListMsg{Err: err}
-		}
-		var projects []string
-		for _, p := range resp.Projects {
-			projects = append(projects, p.Name)
+			return agentCreateResultMsg{Err: err}
 		}
-		// Sort projects alphabetically (case-insensitive)
-		slices.SortFunc(projects, func(a, b string) int {
-			return strings.Compare(strings.ToLower(a), strings.ToLower(b))
-		})
-		return projectListMsg{Projects: projects}
+		return agentCreateResultMsg{AgentID: resp.ID, Project: resp.Project}
 	}
 }
 
@@ -251,24 +439,26 @@ func (m Model) startPlanner(project, prompt string) tea.Cmd {
 	}
 }
 
-// allowPermission approves a permission request.
-func (m Model) allowPermission(requestID string) tea.Cmd {
+// allowPermission approves a permission request. If remember is set, the
+// daemon saves the approval as a project rule.
+func (m Model) allowPermission(requestID string, remember bool) tea.Cmd {
 	return func() tea.Msg {
 		if m.client == nil {
 			return nil
 		}
-		err := m.client.RespondPermission(requestID, "allow", "", false)
+		err := m.client.RespondPermission(requestID, "allow", "", false, remember)
 		return permissionResultMsg{Err: err}
 	}
 }
 
-// denyPermission denies a permission request.
-func (m Model) denyPermission(requestID string) tea.Cmd {
+// denyPermission denies a permission request. If remember is set, the
+// daemon saves the denial as a project rule.
+func (m Model) denyPermission(requestID string, remember bool) tea.Cmd {
 	return func() tea.Msg {
 		if m.client == nil {
 			return nil
 		}
-		err := m.client.RespondPermission(requestID, "deny", "denied by user", false)
+		err := m.client.RespondPermission(requestID, "deny", "denied by user", false, remember)
 		return permissionResultMsg{Err: err}
 	}
 }
@@ -338,7 +528,7 @@ func (m Model) startSupervisor(project string) tea.Cmd {
 		if m.client == nil {
 			return supervisorStartResultMsg{Err: fmt.Errorf("not connected")}
 		}
-		err := m.client.Start(project, false)
+		_, err := m.client.Start(project, false)
 		if err != nil {
 			return supervisorStartResultMsg{Err: err}
 		}
@@ -352,7 +542,7 @@ func (m Model) stopSupervisor(project string) tea.Cmd {
 		if m.client == nil {
 			return supervisorStopResultMsg{Err: fmt.Errorf("not connected")}
 		}
-		err := m.client.Stop(project, false)
+		_, err := m.client.Stop(project, false)
 		if err != nil {
 			return supervisorStopResultMsg{Err: err}
 		}
@@ -12,11 +12,16 @@ type Header struct {
 	width int
 
 	// Agent stats
-	agentCount   int
-	runningCount int
+	agentCount     int
+	runningCount   int
+	attentionCount int
+	waitingIssues  int // Ready issues waiting on projects at max agents
 
 	// Connection state
 	connState connectionState
+
+	// Persistent warning that the daemon runs a different version
+	versionWarning string
 }
 
 // NewHeader creates a new header component.
@@ -37,11 +42,27 @@ func (h *Header) SetAgentCounts(total, running int) {
 	h.runningCount = running
 }
 
+// SetAttentionCount updates how many agents need attention.
+func (h *Header) SetAttentionCount(n int) {
+	h.attentionCount = n
+}
+
+// SetWaitingIssues updates how many ready issues wait for an agent slot.
+func (h *Header) SetWaitingIssues(n int) {
+	h.waitingIssues = n
+}
+
 // SetConnectionState updates the connection state display.
 func (h *Header) SetConnectionState(state connectionState) {
 	h.connState = state
 }
 
+// SetVersionWarning sets the version mismatch warning. Unlike errors in the
+// status bar it stays until cleared with "".
+func (h *Header) SetVersionWarning(warning string) {
+	h.versionWarning = warning
+}
+
 // View renders the header.
 func (h Header) View() string {
 	// Left side: branding
@@ -70,9 +91,22 @@ func (h Header) View() string {
 	if connStatus != "" {
 		sections = append(sections, connStatus)
 	}
+	if h.versionWarning != "" {
+		sections = append(sections, headerConnReconnectingStyle.Render(" ⚠ "+h.versionWarning))
+	}
 
 	// Collect right-side stats
 	var rightStats []string
+	if h.attentionCount > 0 && h.connState == connectionConnected {
+		rightStats = append(rightStats, headerAttentionStyle.Render(
+			fmt.Sprintf("%d need attention", h.attentionCount),
+		))
+	}
+	if h.waitingIssues > 0 && h.connState == connectionConnected {
+		rightStats = append(rightStats, headerStatsStyle.Render(
+			fmt.Sprintf("%d waiting (at max)", h.waitingIssues),
+		))
+	}
 	if agentStats != "" {
 		rightStats = append(rightStats, agentStats)
 	}
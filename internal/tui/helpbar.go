@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // HelpBar displays context-sensitive keyboard shortcuts at the bottom of the TUI.
@@ -16,6 +17,9 @@ type HelpBar struct {
 
 	// Error display
 	errorMsg string
+
+	// Compact layout: one pane at a time on a narrow terminal
+	compact bool
 }
 
 // NewHelpBar creates a new help bar component.
@@ -30,6 +34,17 @@ func (h *HelpBar) SetWidth(width int) {
 	h.width = width
 }
 
+// SetKeys sets the key bindings shown in the help bar.
+func (h *HelpBar) SetKeys(keys KeyBindings) {
+	h.keys = keys
+}
+
+// SetCompact sets whether the TUI is in the compact one-pane layout, where
+// Tab is the only way between panes and hints must fit on one short line.
+func (h *HelpBar) SetCompact(compact bool) {
+	h.compact = compact
+}
+
 // SetModeState updates the help bar's mode state for rendering appropriate shortcuts.
 func (h *HelpBar) SetModeState(state ModeState) {
 	h.modeState = state
@@ -75,6 +90,13 @@ func (h HelpBar) View() string {
 		return statusStyle.Width(h.width).Render("-- SELECT PROJECT (type to filter) -- " + helpText)
 	}
 
+	// New-agent project selection mode
+	if h.modeState.IsAgentProjectSelect() {
+		bindings = []key.Binding{h.keys.Submit, h.keys.Down, h.keys.Cancel, h.keys.Quit}
+		helpText := formatHelp(bindings)
+		return statusStyle.Width(h.width).Render("-- NEW AGENT (type to filter) -- " + helpText)
+	}
+
 	// Supervisor project selection mode
 	if h.modeState.IsSupervisorProjectSelect() {
 		bindings = []key.Binding{h.keys.Submit, h.keys.Down, h.keys.Cancel, h.keys.Quit}
@@ -92,14 +114,24 @@ func (h HelpBar) View() string {
 	// Normal mode bindings depend on focus and pending approvals
 	switch h.modeState.Focus {
 	case FocusAgentList:
-		if h.modeState.NeedsApproval() {
-			bindings = []key.Binding{h.keys.Approve, h.keys.Reject, h.keys.Down, h.keys.Tab, h.keys.Quit}
+		if h.modeState.HasPendingUserQuestion && h.modeState.PendingQuestionMultiSelect {
+			bindings = []key.Binding{h.keys.Toggle, h.keys.Approve, h.keys.Down, h.keys.Tab, h.keys.Quit}
+		} else if h.modeState.HasPendingPermission && !h.modeState.HasPendingUserQuestion {
+			bindings = []key.Binding{h.keys.Approve, h.keys.Reject, h.keys.ApproveAlways, h.keys.RejectAlways, h.keys.NextAttention, h.keys.Tab, h.keys.Quit}
+		} else if h.modeState.NeedsApproval() {
+			bindings = []key.Binding{h.keys.Approve, h.keys.Reject, h.keys.Down, h.keys.NextAttention, h.keys.Tab, h.keys.Quit}
 		} else {
-			bindings = []key.Binding{h.keys.Down, h.keys.Tab, h.keys.Plan, h.keys.Supervisor, h.keys.Abort, h.keys.Quit}
+			bindings = []key.Binding{h.keys.Down, h.keys.Tab, h.keys.NewAgent, h.keys.Plan, h.keys.Supervisor, h.keys.Abort, h.keys.Quit}
 		}
 	case FocusChatView:
-		if h.modeState.NeedsApproval() {
-			bindings = []key.Binding{h.keys.Approve, h.keys.Reject, h.keys.Down, h.keys.Tab, h.keys.Quit}
+		if h.modeState.HasPendingUserQuestion && h.modeState.PendingQuestionMultiSelect {
+			bindings = []key.Binding{h.keys.Toggle, h.keys.Approve, h.keys.Down, h.keys.Tab, h.keys.Quit}
+		} else if h.modeState.HasPendingPermission && !h.modeState.HasPendingUserQuestion {
+			bindings = []key.Binding{h.keys.Approve, h.keys.Reject, h.keys.ApproveAlways, h.keys.RejectAlways, h.keys.NextAttention, h.keys.Tab, h.keys.Quit}
+		} else if h.modeState.NeedsApproval() {
+			bindings = []key.Binding{h.keys.Approve, h.keys.Reject, h.keys.Down, h.keys.NextAttention, h.keys.Tab, h.keys.Quit}
+		} else if h.compact {
+			bindings = []key.Binding{h.keys.FocusChat, h.keys.Tab, h.keys.Down, h.keys.PageUp, h.keys.Plan, h.keys.Supervisor, h.keys.Abort, h.keys.Quit}
 		} else {
 			bindings = []key.Binding{h.keys.FocusChat, h.keys.Down, h.keys.PageUp, h.keys.Plan, h.keys.Supervisor, h.keys.Abort, h.keys.Quit}
 		}
@@ -108,6 +140,13 @@ func (h HelpBar) View() string {
 	}
 
 	helpText := formatHelp(bindings)
+	if h.compact {
+		// Drop the least important hints rather than wrap onto the panes
+		for len(bindings) > 1 && lipgloss.Width(helpText) > h.width-2 {
+			bindings = bindings[:len(bindings)-1]
+			helpText = formatHelp(bindings)
+		}
+	}
 	return statusStyle.Width(h.width).Render(helpText)
 }
 
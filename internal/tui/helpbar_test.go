@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHelpBarShowsToggleForMultiSelect(t *testing.T) {
+	h := NewHelpBar()
+	h.SetWidth(200)
+
+	state := NewModeState()
+	state.Focus = FocusChatView
+	state.SetPendingApprovals(false, false, true)
+	h.SetModeState(state)
+	if strings.Contains(h.View(), "toggle") {
+		t.Error("single-select question should not show toggle")
+	}
+
+	state.PendingQuestionMultiSelect = true
+	h.SetModeState(state)
+	if !strings.Contains(h.View(), "space: toggle") {
+		t.Errorf("multi-select question should show toggle, got %q", h.View())
+	}
+}
+
+func TestHelpBarCompactFitsOneLine(t *testing.T) {
+	h := NewHelpBar()
+	h.SetWidth(40)
+	h.SetCompact(true)
+
+	state := NewModeState()
+	state.Focus = FocusChatView
+	h.SetModeState(state)
+
+	view := h.View()
+	if strings.Contains(view, "\n") {
+		t.Errorf("compact help bar should fit on one line, got %q", view)
+	}
+	if !strings.Contains(view, "tab: switch pane") {
+		t.Errorf("compact chat help should show how to switch panes, got %q", view)
+	}
+}
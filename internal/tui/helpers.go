@@ -1,6 +1,10 @@
 package tui
 
 import (
+	"fmt"
+	"log/slog"
+	"slices"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/tessro/fab/internal/daemon"
 )
@@ -20,6 +24,17 @@ func isPlanner(agentID string) bool {
 	return isPlannerAgent(agentID)
 }
 
+// versionWarning returns the header warning for a daemon running a different
+// version than this CLI, or "" if the versions match. A mismatch usually
+// means the daemon wasn't restarted after an upgrade, which shows up as
+// confusing decode errors when the protocol changed.
+func versionWarning(daemonVersion, cliVersion string) string {
+	if daemonVersion == "" || daemonVersion == cliVersion {
+		return ""
+	}
+	return fmt.Sprintf("daemon %s ≠ cli %s, run 'fab server restart'", daemonVersion, cliVersion)
+}
+
 // countRunning counts agents in running or starting state.
 func countRunning(agents []daemon.AgentStatus) int {
 	count := 0
@@ -31,6 +46,19 @@ func countRunning(agents []daemon.AgentStatus) int {
 	return count
 }
 
+// waitingAtCapacity counts ready issues waiting in projects whose agent
+// slots are all taken. Issues in projects with a free slot are about to be
+// picked up, so they aren't counted.
+func waitingAtCapacity(projects []daemon.ProjectStatus) int {
+	n := 0
+	for _, p := range projects {
+		if p.AtCapacity {
+			n += p.WaitingIssues
+		}
+	}
+	return n
+}
+
 // pendingPermissionForAgent returns the first pending permission request for the given agent.
 func (m *Model) pendingPermissionForAgent(agentID string) *daemon.PermissionRequest {
 	if agentID == "" {
@@ -44,6 +72,24 @@ func (m *Model) pendingPermissionForAgent(agentID string) *daemon.PermissionRequ
 	return nil
 }
 
+// addPendingPermissions adds permission requests not already pending,
+// updates the duplicate counts of those that are, and refreshes the chat
+// view and attention indicators.
+func (m *Model) addPendingPermissions(requests []daemon.PermissionRequest) {
+	for _, req := range requests {
+		i := slices.IndexFunc(m.pendingPermissions, func(p daemon.PermissionRequest) bool { return p.ID == req.ID })
+		if i < 0 {
+			m.pendingPermissions = append(m.pendingPermissions, req)
+		} else if req.Duplicates > m.pendingPermissions[i].Duplicates {
+			m.pendingPermissions[i].Duplicates = req.Duplicates
+		}
+	}
+	if agentID := m.chatView.AgentID(); agentID != "" {
+		m.chatView.SetPendingPermission(m.pendingPermissionForAgent(agentID))
+	}
+	m.updateNeedsAttention()
+}
+
 // pendingUserQuestionForAgent returns the first pending user question for the given agent.
 func (m *Model) pendingUserQuestionForAgent(agentID string) *daemon.UserQuestion {
 	if agentID == "" {
@@ -67,6 +113,84 @@ func (m *Model) updateNeedsAttention() {
 		attention[question.AgentID] = true
 	}
 	m.agentList.SetNeedsAttention(attention)
+	m.header.SetAttentionCount(len(attention))
+}
+
+// applyAgentList shows a freshly fetched agent list, selects a pending or
+// initial agent, and attaches to the event stream on the first fetch.
+func (m *Model) applyAgentList(agents []daemon.AgentStatus) []tea.Cmd {
+	var cmds []tea.Cmd
+	slog.Debug("tui.applyAgentList: agents received", "count", len(agents), "initial_agent_id", m.initialAgentID, "pending_planner_id", m.pendingPlannerID)
+	m.agentList.SetAgents(agents)
+	m.header.SetAgentCounts(len(agents), countRunning(agents))
+	// Prune state for agents that no longer exist (e.g., after reconnecting)
+	if cmd := m.pruneStaleAgentState(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
+	// Check if we have a pending planner to select (from starting plan in TUI)
+	if m.pendingPlannerID != "" {
+		tuiPlannerID := plannerAgentID(m.pendingPlannerID)
+		slog.Debug("tui.Update: looking for pending planner", "pending_planner_id", m.pendingPlannerID, "tui_planner_id", tuiPlannerID)
+		found := false
+		for i, agent := range agents {
+			if agent.ID == tuiPlannerID {
+				slog.Debug("tui.Update: found pending planner, selecting", "index", i, "agent_id", agent.ID)
+				m.pendingPlannerID = "" // Clear pending
+				m.agentList.SetSelected(i)
+				if cmd := m.selectCurrentAgent(); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			slog.Debug("tui.Update: pending planner not found in agent list", "tui_planner_id", tuiPlannerID)
+		}
+	} else if m.pendingAgentID != "" {
+		// Check if we have a pending agent to select (from creating one in TUI)
+		for i, agent := range agents {
+			if agent.ID == m.pendingAgentID {
+				m.pendingAgentID = "" // Clear pending
+				m.agentList.SetSelected(i)
+				if cmd := m.selectCurrentAgent(); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
+				break
+			}
+		}
+	} else if m.chatView.AgentID() == "" && len(agents) > 0 {
+		// Auto-select agent if none is currently selected
+		// If an initial agent was specified, find and select it
+		if m.initialAgentID != "" {
+			slog.Debug("tui.Update: looking for initial agent", "initial_agent_id", m.initialAgentID)
+			found := false
+			for i, agent := range agents {
+				slog.Debug("tui.Update: checking agent", "index", i, "agent_id", agent.ID)
+				if agent.ID == m.initialAgentID {
+					slog.Debug("tui.Update: found initial agent, selecting", "index", i)
+					m.agentList.SetSelected(i)
+					found = true
+					break
+				}
+			}
+			if !found {
+				slog.Warn("tui.Update: initial agent not found in agent list", "initial_agent_id", m.initialAgentID, "agent_count", len(agents))
+			}
+			// Clear the initial agent ID so we don't keep trying to select it
+			m.initialAgentID = ""
+		}
+		if cmd := m.selectCurrentAgent(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	// Attach to event stream after initial agent list fetch
+	if !m.attached {
+		slog.Debug("tui.Update: attaching to event stream")
+		cmds = append(cmds, m.attachToStream())
+	}
+	return cmds
 }
 
 // pruneStaleAgentState removes state for agents that no longer exist.
@@ -118,9 +242,24 @@ func (m *Model) selectCurrentAgent() tea.Cmd {
 		return nil
 	}
 	m.chatView.SetAgent(agent.ID, agent.Project, agent.Backend, agent.Worktree)
+	m.chatView.SetModel(agent.Model)
+	m.chatView.SetName(agent.Name)
 	m.chatView.SetPendingPermission(m.pendingPermissionForAgent(agent.ID))
 	m.chatView.SetPendingUserQuestion(m.pendingUserQuestionForAgent(agent.ID))
-	return m.fetchAgentChatHistory(agent.ID, agent.Project)
+	if isManager(agent.ID) || isDirector(agent.ID) || isPlanner(agent.ID) {
+		return m.fetchAgentChatHistory(agent.ID, agent.Project)
+	}
+	return tea.Batch(m.fetchAgentChatHistory(agent.ID, agent.Project), m.fetchAgentDetails(agent.ID))
+}
+
+// loadOlderHistoryAtTop returns a command to fetch the next older page of chat
+// history when the chat view is scrolled to the top, or nil if there's none.
+func (m *Model) loadOlderHistoryAtTop() tea.Cmd {
+	cursor, ok := m.chatView.BeginOlderHistoryLoad()
+	if !ok {
+		return nil
+	}
+	return m.fetchOlderChatHistory(m.chatView.AgentID(), cursor)
 }
 
 // syncFocusToComponents updates component focus states to match the ModeState focus.
@@ -136,7 +275,19 @@ func (m *Model) syncFocusToComponents(focus Focus) {
 	}
 }
 
-// updateLayout recalculates component dimensions for two-pane layout.
+// compactLayoutWidth is the terminal width below which the panes are shown
+// one at a time; a split pane would leave the agent list under 30 columns.
+const compactLayoutWidth = 80
+
+// showsAgentList reports whether the compact layout shows the agent list
+// rather than the chat view. Prompts such as project selection and abort
+// confirmation render in the chat view, so it is shown outside normal mode.
+func (m *Model) showsAgentList() bool {
+	return m.modeState.IsNormal() && m.modeState.Focus == FocusAgentList
+}
+
+// updateLayout recalculates component dimensions for the two-pane layout,
+// or for the one-pane compact layout on narrow terminals.
 func (m *Model) updateLayout() {
 	headerHeight := 1 // Single line header
 	statusHeight := 1 // Single line status bar
@@ -146,12 +297,18 @@ func (m *Model) updateLayout() {
 	}
 
 	// Split width: 38% left pane, 62% chat view
+	m.layout = layoutSplit
 	listWidth := m.width * 38 / 100
 	chatWidth := m.width - listWidth
+	if m.width < compactLayoutWidth {
+		m.layout = layoutCompact
+		listWidth, chatWidth = m.width, m.width
+	}
 
 	m.agentList.SetSize(listWidth, contentHeight)
 	m.chatView.SetSize(chatWidth, contentHeight)
 	m.helpBar.SetWidth(m.width)
+	m.helpBar.SetCompact(m.layout == layoutCompact)
 
 	// Input line sized to fit inside chat pane (no border, just content + padding)
 	// Height: content lines + 1 line divider
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestUpdateLayout_CompactOnNarrowTerminals(t *testing.T) {
+	resize := func(m tea.Model, width int) Model {
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: 20})
+		return updated.(Model)
+	}
+
+	m := resize(New(), 120)
+	if m.layout != layoutSplit {
+		t.Fatalf("layout at 120 columns = %v, want split", m.layout)
+	}
+	if view := m.View(); !strings.Contains(view, "Agents") || !strings.Contains(view, "Chat") {
+		t.Errorf("split view should show both panes:\n%s", view)
+	}
+
+	m = resize(m, 60)
+	if m.layout != layoutCompact {
+		t.Fatalf("layout at 60 columns = %v, want compact", m.layout)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Agents") || strings.Contains(view, "Chat") {
+		t.Errorf("compact view should show only the agent list:\n%s", view)
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > 60 {
+			t.Errorf("line is %d columns wide, want at most 60: %q", w, line)
+		}
+	}
+	if lines := strings.Count(view, "\n") + 1; lines > 20 {
+		t.Errorf("view is %d lines tall, want at most 20", lines)
+	}
+
+	// Tab switches to the chat view and back
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if view := m.View(); !strings.Contains(view, "Chat") || strings.Contains(view, "Agents") {
+		t.Errorf("compact view after tab should show only the chat view:\n%s", view)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if view := m.View(); !strings.Contains(view, "Agents") {
+		t.Errorf("compact view after second tab should show the agent list:\n%s", view)
+	}
+
+	// Prompts render in the chat view, so it is shown outside normal mode
+	_ = m.modeState.EnterAgentProjectSelect([]string{"proj"})
+	if m.showsAgentList() {
+		t.Error("showsAgentList() = true while selecting a project, want false")
+	}
+}
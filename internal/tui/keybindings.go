@@ -1,6 +1,13 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 // KeyBindings defines all keyboard shortcuts for the TUI.
 type KeyBindings struct {
@@ -11,19 +18,24 @@ type KeyBindings struct {
 	Reconnect key.Binding
 
 	// Navigation keys
-	Up       key.Binding
-	Down     key.Binding
-	Top      key.Binding
-	Bottom   key.Binding
-	PageUp   key.Binding
-	PageDown key.Binding
+	Up            key.Binding
+	Down          key.Binding
+	Top           key.Binding
+	Bottom        key.Binding
+	PageUp        key.Binding
+	PageDown      key.Binding
+	NextAttention key.Binding
 
 	// Action keys
-	Approve    key.Binding
-	Reject     key.Binding
-	Abort      key.Binding
-	Plan       key.Binding
-	Supervisor key.Binding
+	Approve       key.Binding
+	Reject        key.Binding
+	ApproveAlways key.Binding
+	RejectAlways  key.Binding
+	Abort         key.Binding
+	Plan          key.Binding
+	NewAgent      key.Binding
+	Supervisor    key.Binding
+	Toggle        key.Binding
 
 	// Input keys
 	Submit      key.Binding
@@ -77,6 +89,10 @@ func DefaultKeyBindings() KeyBindings {
 			key.WithKeys("ctrl+d", "pgdown"),
 			key.WithHelp("pgdn", "page down"),
 		),
+		NextAttention: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "next attention"),
+		),
 
 		Approve: key.NewBinding(
 			key.WithKeys("y"),
@@ -86,6 +102,14 @@ func DefaultKeyBindings() KeyBindings {
 			key.WithKeys("n"),
 			key.WithHelp("n", "reject"),
 		),
+		ApproveAlways: key.NewBinding(
+			key.WithKeys("Y"),
+			key.WithHelp("Y", "always allow"),
+		),
+		RejectAlways: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "always deny"),
+		),
 		Abort: key.NewBinding(
 			key.WithKeys("x"),
 			key.WithHelp("x", "abort"),
@@ -94,10 +118,18 @@ func DefaultKeyBindings() KeyBindings {
 			key.WithKeys("p"),
 			key.WithHelp("p", "plan"),
 		),
+		NewAgent: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "new agent"),
+		),
 		Supervisor: key.NewBinding(
 			key.WithKeys("s"),
 			key.WithHelp("s", "supervisor"),
 		),
+		Toggle: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "toggle"),
+		),
 
 		Submit: key.NewBinding(
 			key.WithKeys("enter"),
@@ -121,3 +153,114 @@ func DefaultKeyBindings() KeyBindings {
 		),
 	}
 }
+
+// keyActions maps the action names used in the tui.keys config to their
+// bindings.
+func (k *KeyBindings) keyActions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"quit":           &k.Quit,
+		"tab":            &k.Tab,
+		"focus-chat":     &k.FocusChat,
+		"reconnect":      &k.Reconnect,
+		"up":             &k.Up,
+		"down":           &k.Down,
+		"top":            &k.Top,
+		"bottom":         &k.Bottom,
+		"page-up":        &k.PageUp,
+		"page-down":      &k.PageDown,
+		"next-attention": &k.NextAttention,
+		"approve":        &k.Approve,
+		"reject":         &k.Reject,
+		"approve-always": &k.ApproveAlways,
+		"reject-always":  &k.RejectAlways,
+		"abort":          &k.Abort,
+		"plan":           &k.Plan,
+		"new-agent":      &k.NewAgent,
+		"supervisor":     &k.Supervisor,
+		"toggle":         &k.Toggle,
+		"submit":         &k.Submit,
+		"cancel":         &k.Cancel,
+		"history-up":     &k.HistoryUp,
+		"history-down":   &k.HistoryDown,
+		"new-line":       &k.NewLine,
+	}
+}
+
+// keyModes lists the actions that are live at the same time, so no two of
+// them may share a key.
+var keyModes = map[string][]string{
+	"agent list": {
+		"quit", "tab", "focus-chat", "reconnect", "up", "down", "top", "bottom",
+		"page-up", "page-down", "next-attention", "approve", "reject",
+		"approve-always", "reject-always", "abort", "plan", "new-agent",
+		"supervisor", "toggle",
+	},
+	"chat input": {"submit", "cancel", "new-line", "tab", "history-up", "history-down"},
+	"prompt":     {"submit", "cancel", "new-line", "up", "down"},
+}
+
+// KeyActions returns the action names that can be rebound, sorted.
+func KeyActions() []string {
+	var k KeyBindings
+	actions := make([]string, 0, len(k.keyActions()))
+	for action := range k.keyActions() {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// NewKeyBindings returns the default key bindings with overrides applied.
+// Each override maps an action (see KeyActions) to the keys that trigger it,
+// replacing its default keys; the help bar shows the first one. An error is
+// returned for an unknown action, an action with no keys, or a key bound to
+// two actions that are live at the same time.
+func NewKeyBindings(overrides map[string][]string) (KeyBindings, error) {
+	keys := DefaultKeyBindings()
+	actions := keys.keyActions()
+	for action, bound := range overrides {
+		b, ok := actions[action]
+		if !ok {
+			return DefaultKeyBindings(), fmt.Errorf("unknown key action %q (valid: %s)", action, strings.Join(KeyActions(), ", "))
+		}
+		if len(bound) == 0 || slices.Contains(bound, "") {
+			return DefaultKeyBindings(), fmt.Errorf("key action %q: keys must not be empty", action)
+		}
+		*b = key.NewBinding(
+			key.WithKeys(bound...),
+			key.WithHelp(keyHelpName(bound[0]), b.Help().Desc),
+		)
+	}
+
+	modes := make([]string, 0, len(keyModes))
+	for mode := range keyModes {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	for _, mode := range modes {
+		owner := make(map[string]string)
+		for _, action := range keyModes[mode] {
+			for _, k := range actions[action].Keys() {
+				if other, ok := owner[k]; ok && other != action {
+					return DefaultKeyBindings(), fmt.Errorf("key %q is bound to both %q and %q in the %s", k, other, action, mode)
+				}
+				owner[k] = action
+			}
+		}
+	}
+	return keys, nil
+}
+
+// keyHelpName returns how a key is shown in the help bar.
+func keyHelpName(k string) string {
+	switch k {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	default:
+		return k
+	}
+}
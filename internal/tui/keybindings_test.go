@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNewKeyBindings(t *testing.T) {
+	keys, err := NewKeyBindings(nil)
+	if err != nil {
+		t.Fatalf("NewKeyBindings(nil) error = %v; defaults should not conflict", err)
+	}
+	if got := keys.Approve.Keys(); len(got) != 1 || got[0] != "y" {
+		t.Errorf("default Approve keys = %v, want [y]", got)
+	}
+
+	keys, err = NewKeyBindings(map[string][]string{
+		"approve":   {"A"},
+		"new-agent": {"c"},
+		"toggle":    {" ", "t"},
+	})
+	if err != nil {
+		t.Fatalf("NewKeyBindings() error = %v", err)
+	}
+	approve := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")}
+	if !key.Matches(approve, keys.Approve) || key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}, keys.Approve) {
+		t.Errorf("Approve keys = %v, want only A", keys.Approve.Keys())
+	}
+	if help := keys.Approve.Help(); help.Key != "A" || help.Desc != "approve" {
+		t.Errorf("Approve help = %+v, want A: approve", help)
+	}
+	if help := keys.Toggle.Help(); help.Key != "space" {
+		t.Errorf("Toggle help key = %q, want space", help.Key)
+	}
+	if got := keys.Reject.Keys(); len(got) != 1 || got[0] != "n" {
+		t.Errorf("Reject keys = %v, want the default [n]", got)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		overrides map[string][]string
+		wantErr   string
+	}{
+		{"unknown action", map[string][]string{"launch": {"l"}}, "unknown key action"},
+		{"no keys", map[string][]string{"abort": {}}, "must not be empty"},
+		{"empty key", map[string][]string{"abort": {""}}, "must not be empty"},
+		{"conflict with default", map[string][]string{"abort": {"y"}}, `"approve" and "abort"`},
+		{"conflict in input", map[string][]string{"new-line": {"enter"}}, "chat input"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := NewKeyBindings(tt.overrides)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("NewKeyBindings(%v) error = %v, want %q", tt.overrides, err, tt.wantErr)
+			}
+			if got := keys.Abort.Keys(); len(got) != 1 || got[0] != "x" {
+				t.Errorf("Abort keys = %v, want defaults on error", got)
+			}
+		})
+	}
+
+	// Keys only clash within a mode: up in the agent list and history-up
+	// in chat input are never live together.
+	if _, err := NewKeyBindings(map[string][]string{"history-up": {"k"}}); err != nil {
+		t.Errorf("NewKeyBindings() error = %v, want keys shared across modes to be allowed", err)
+	}
+}
+
+func TestHelpBarShowsRebinding(t *testing.T) {
+	keys, err := NewKeyBindings(map[string][]string{"abort": {"ctrl+x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHelpBar()
+	h.SetKeys(keys)
+	h.SetWidth(200)
+	h.SetModeState(NewModeState())
+
+	if view := h.View(); !strings.Contains(view, "ctrl+x: abort") {
+		t.Errorf("help bar should show the rebound abort key, got %q", view)
+	}
+}
@@ -18,6 +18,18 @@ type agentListMsg struct {
 	Err    error
 }
 
+// dashboardMsg contains the TUI's startup state fetched in one round trip.
+// Err is set if the request failed as a whole (e.g., an older daemon);
+// AgentsErr if only the agent list couldn't be fetched.
+type dashboardMsg struct {
+	Agents      []daemon.AgentStatus
+	AgentsErr   error
+	Permissions []daemon.PermissionRequest
+	Version     string // Empty if the daemon status couldn't be fetched
+	Projects    []daemon.ProjectStatus
+	Err         error
+}
+
 // agentInputMsg is the result of sending input to an agent.
 type agentInputMsg struct {
 	Err error
@@ -25,11 +37,43 @@ type agentInputMsg struct {
 
 // agentChatHistoryMsg contains chat history fetched for an agent.
 type agentChatHistoryMsg struct {
+	AgentID    string
+	Entries    []daemon.ChatEntryDTO
+	NextCursor string // Cursor for the next older page ("" = none)
+	Older      bool   // Entries are an older page to prepend
+	Err        error
+}
+
+// agentDetailsMsg contains the detailed state of one agent.
+type agentDetailsMsg struct {
 	AgentID string
-	Entries []daemon.ChatEntryDTO
+	Details *daemon.AgentGetResponse
+	Err     error
+}
+
+// daemonVersionMsg contains the version reported by the daemon's ping.
+type daemonVersionMsg struct {
+	Version string
 	Err     error
 }
 
+// backlogMsg contains project statuses, polled for the ready issues waiting
+// on projects at their agent limit.
+type backlogMsg struct {
+	Projects []daemon.ProjectStatus
+	Err      error
+}
+
+// backlogTickMsg triggers the next backlog poll.
+type backlogTickMsg struct{}
+
+// pendingPermissionsMsg contains permission requests that were already
+// pending when the TUI attached (e.g., held while no TUI was attached).
+type pendingPermissionsMsg struct {
+	Requests []daemon.PermissionRequest
+	Err      error
+}
+
 // permissionResultMsg is the result of responding to a permission request.
 type permissionResultMsg struct {
 	Err error
@@ -59,6 +103,19 @@ type planStartResultMsg struct {
 	Err       error
 }
 
+// agentProjectListMsg contains the list of projects for a new agent.
+type agentProjectListMsg struct {
+	Projects []string
+	Err      error
+}
+
+// agentCreateResultMsg is the result of creating an agent.
+type agentCreateResultMsg struct {
+	AgentID string
+	Project string
+	Err     error
+}
+
 // tickMsg is sent on regular intervals to drive spinner animation.
 type tickMsg time.Time
 
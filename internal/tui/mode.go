@@ -24,6 +24,8 @@ const (
 	ModePlanPrompt
 	// ModeSupervisorProjectSelect means the user is selecting a project for supervisor start.
 	ModeSupervisorProjectSelect
+	// ModeAgentProjectSelect means the user is selecting a project for a new agent.
+	ModeAgentProjectSelect
 )
 
 // String returns the string representation of a Mode.
@@ -43,6 +45,8 @@ func (m Mode) String() string {
 		return "plan_prompt"
 	case ModeSupervisorProjectSelect:
 		return "supervisor_project_select"
+	case ModeAgentProjectSelect:
+		return "agent_project_select"
 	default:
 		return "unknown"
 	}
@@ -66,6 +70,9 @@ type ModeState struct {
 	// HasPendingUserQuestion indicates if there's a user question awaiting response.
 	HasPendingUserQuestion bool
 
+	// PendingQuestionMultiSelect indicates the pending user question allows multiple selections.
+	PendingQuestionMultiSelect bool
+
 	// PlanProject is the selected project for planning (only valid when Mode == ModePlanPrompt).
 	PlanProject string
 
@@ -95,6 +102,18 @@ type ModeState struct {
 
 	// SupervisorProjectRunning tracks which projects have running supervision.
 	SupervisorProjectRunning map[string]bool
+
+	// AgentProjects is the list of available projects for a new agent (only valid when Mode == ModeAgentProjectSelect).
+	AgentProjects []string
+
+	// AgentProjectIndex is the currently selected project index (only valid when Mode == ModeAgentProjectSelect).
+	AgentProjectIndex int
+
+	// AgentProjectFilter is the current filter text for fuzzy matching (only valid when Mode == ModeAgentProjectSelect).
+	AgentProjectFilter string
+
+	// AgentProjectFiltered is the list of projects that match the filter (only valid when Mode == ModeAgentProjectSelect).
+	AgentProjectFiltered []string
 }
 
 // NewModeState creates a new ModeState with default values.
@@ -572,3 +591,117 @@ func (s *ModeState) SupervisorProjectBackspaceFilter() {
 		s.SupervisorProjectSetFilter(s.SupervisorProjectFilter[:len(s.SupervisorProjectFilter)-1])
 	}
 }
+
+// EnterAgentProjectSelect transitions to new-agent project selection mode.
+// projects is the list of available projects to choose from.
+func (s *ModeState) EnterAgentProjectSelect(projects []string) error {
+	if s.Mode != ModeNormal {
+		return ErrInvalidModeTransition
+	}
+	if len(projects) == 0 {
+		return errors.New("no projects available")
+	}
+	s.Mode = ModeAgentProjectSelect
+	s.AgentProjects = projects
+	s.AgentProjectIndex = 0
+	s.AgentProjectFilter = ""
+	s.AgentProjectFiltered = projects // Initially show all projects
+	return nil
+}
+
+// AgentProjectSelectUp moves the selection up in the project list.
+func (s *ModeState) AgentProjectSelectUp() {
+	if s.Mode != ModeAgentProjectSelect {
+		return
+	}
+	if s.AgentProjectIndex > 0 {
+		s.AgentProjectIndex--
+	}
+}
+
+// AgentProjectSelectDown moves the selection down in the project list.
+func (s *ModeState) AgentProjectSelectDown() {
+	if s.Mode != ModeAgentProjectSelect {
+		return
+	}
+	if s.AgentProjectIndex < len(s.AgentProjectFiltered)-1 {
+		s.AgentProjectIndex++
+	}
+}
+
+// SelectAgentProject selects the current project and returns to normal mode.
+func (s *ModeState) SelectAgentProject() (string, error) {
+	if s.Mode != ModeAgentProjectSelect {
+		return "", ErrInvalidModeTransition
+	}
+	if len(s.AgentProjectFiltered) == 0 {
+		return "", errors.New("no matching projects")
+	}
+	if s.AgentProjectIndex < 0 || s.AgentProjectIndex >= len(s.AgentProjectFiltered) {
+		return "", errors.New("invalid project selection")
+	}
+	project := s.AgentProjectFiltered[s.AgentProjectIndex]
+	s.clearAgentProjectSelect()
+	return project, nil
+}
+
+// CancelAgentProjectSelect cancels project selection and returns to normal mode.
+func (s *ModeState) CancelAgentProjectSelect() error {
+	if s.Mode != ModeAgentProjectSelect {
+		return ErrInvalidModeTransition
+	}
+	s.clearAgentProjectSelect()
+	return nil
+}
+
+// clearAgentProjectSelect resets new-agent project selection state.
+func (s *ModeState) clearAgentProjectSelect() {
+	s.Mode = ModeNormal
+	s.AgentProjects = nil
+	s.AgentProjectIndex = 0
+	s.AgentProjectFilter = ""
+	s.AgentProjectFiltered = nil
+}
+
+// IsAgentProjectSelect returns true if in new-agent project selection mode.
+func (s *ModeState) IsAgentProjectSelect() bool {
+	return s.Mode == ModeAgentProjectSelect
+}
+
+// SelectedAgentProject returns the filtered list of projects and the current index.
+func (s *ModeState) SelectedAgentProject() ([]string, int) {
+	return s.AgentProjectFiltered, s.AgentProjectIndex
+}
+
+// AgentProjectFilterState returns the current filter string.
+func (s *ModeState) AgentProjectFilterState() string {
+	return s.AgentProjectFilter
+}
+
+// AgentProjectSetFilter updates the filter and recomputes the filtered list.
+func (s *ModeState) AgentProjectSetFilter(filter string) {
+	if s.Mode != ModeAgentProjectSelect {
+		return
+	}
+	s.AgentProjectFilter = filter
+	s.AgentProjectFiltered = filterProjects(s.AgentProjects, filter)
+	s.AgentProjectIndex = 0
+}
+
+// AgentProjectAppendFilter appends a character to the filter.
+func (s *ModeState) AgentProjectAppendFilter(ch rune) {
+	if s.Mode != ModeAgentProjectSelect {
+		return
+	}
+	s.AgentProjectSetFilter(s.AgentProjectFilter + string(ch))
+}
+
+// AgentProjectBackspaceFilter removes the last character from the filter.
+func (s *ModeState) AgentProjectBackspaceFilter() {
+	if s.Mode != ModeAgentProjectSelect {
+		return
+	}
+	if len(s.AgentProjectFilter) > 0 {
+		s.AgentProjectSetFilter(s.AgentProjectFilter[:len(s.AgentProjectFilter)-1])
+	}
+}
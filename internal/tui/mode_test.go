@@ -330,6 +330,7 @@ func TestMode_String(t *testing.T) {
 		{ModeNormal, "normal"},
 		{ModeInput, "input"},
 		{ModeAbortConfirm, "abort_confirm"},
+		{ModeAgentProjectSelect, "agent_project_select"},
 		{Mode(99), "unknown"},
 	}
 
@@ -508,3 +509,46 @@ func TestModeState_SelectPlanProjectNoMatches(t *testing.T) {
 		t.Error("expected error when selecting with no matches")
 	}
 }
+
+func TestModeState_AgentProjectSelect(t *testing.T) {
+	state := NewModeState()
+
+	if err := state.EnterAgentProjectSelect(nil); err == nil {
+		t.Error("expected error entering agent project select with no projects")
+	}
+	if err := state.EnterAgentProjectSelect([]string{"Alpha", "Beta", "Gamma"}); err != nil {
+		t.Fatalf("EnterAgentProjectSelect() error: %v", err)
+	}
+	if !state.IsAgentProjectSelect() {
+		t.Fatalf("expected agent project select mode, got %s", state.Mode)
+	}
+
+	state.AgentProjectAppendFilter('a')
+	state.AgentProjectSelectDown()
+	projects, idx := state.SelectedAgentProject()
+	if len(projects) != 3 || idx != 1 {
+		t.Errorf("SelectedAgentProject() = %v, %d; want all 3 projects, index 1", projects, idx)
+	}
+
+	project, err := state.SelectAgentProject()
+	if err != nil {
+		t.Fatalf("SelectAgentProject() error: %v", err)
+	}
+	if project != "Beta" {
+		t.Errorf("SelectAgentProject() = %q, want %q", project, "Beta")
+	}
+	if !state.IsNormal() || state.AgentProjects != nil || state.AgentProjectFilter != "" {
+		t.Errorf("expected normal mode with cleared selection state, got %+v", state)
+	}
+
+	// Cancel also returns to normal mode
+	if err := state.EnterAgentProjectSelect([]string{"Alpha"}); err != nil {
+		t.Fatalf("EnterAgentProjectSelect() error: %v", err)
+	}
+	if err := state.CancelAgentProjectSelect(); err != nil {
+		t.Fatalf("CancelAgentProjectSelect() error: %v", err)
+	}
+	if !state.IsNormal() {
+		t.Errorf("expected normal mode after cancel, got %s", state.Mode)
+	}
+}
@@ -25,6 +25,12 @@ var (
 				Background(primaryColor).
 				Padding(0, 1)
 
+	headerAttentionStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(warningColor).
+				Background(primaryColor).
+				Padding(0, 1)
+
 	// Connection status styles
 	headerConnDisconnectedStyle = lipgloss.NewStyle().
 					Foreground(errorColor).
@@ -105,6 +111,21 @@ var (
 	agentTaskStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#A0A0A0"))
 
+	// Agents working under the same epic share a tag color
+	epicColors = []lipgloss.Color{
+		lipgloss.Color("#F472B6"), // Pink
+		lipgloss.Color("#FBBF24"), // Amber
+		lipgloss.Color("#38BDF8"), // Sky
+		lipgloss.Color("#A3E635"), // Lime
+		lipgloss.Color("#C084FC"), // Violet
+		lipgloss.Color("#FB923C"), // Orange
+	}
+
+	// ID shown after an agent's name, dimmed like a subscript
+	agentNamedIDStyle = lipgloss.NewStyle().
+				Foreground(mutedColor).
+				Faint(true)
+
 	agentDescriptionStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#888888")).
 				Italic(true)
@@ -112,6 +133,18 @@ var (
 	agentDurationStyle = lipgloss.NewStyle().
 				Foreground(mutedColor)
 
+	agentCurrentFileStyle = lipgloss.NewStyle().
+				Foreground(mutedColor)
+
+	// Ghost rows show what a dry-run orchestrator would have done
+	agentGhostStyle = lipgloss.NewStyle().
+			Foreground(mutedColor).
+			Italic(true)
+
+	// Lineage rows show which agents spawned which, below the list
+	agentLineageStyle = lipgloss.NewStyle().
+				Foreground(mutedColor)
+
 	// Backend styles - distinct color per backend
 	agentBackendClaudeStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#60A5FA")) // Light blue for Claude
@@ -144,12 +177,19 @@ var (
 	inputDividerFocusedStyle = lipgloss.NewStyle().
 					Foreground(primaryColor)
 
-	// Chat view styles
-	chatAssistantStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("12")) // blue
-	chatUserStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("10")) // green
-	chatToolStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))  // gray
-	chatResultStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))  // gray
-	chatTimeStyle      = lipgloss.NewStyle().Foreground(mutedColor)           // gray, muted
+	// Chat view styles, in the dark theme until applyChatTheme changes them
+	chatAssistantStyle = lipgloss.NewStyle().Foreground(darkChatTheme.assistant)
+	chatUserStyle      = lipgloss.NewStyle().Foreground(darkChatTheme.user)
+	chatToolStyle      = lipgloss.NewStyle().Foreground(darkChatTheme.tool)
+	chatResultStyle    = lipgloss.NewStyle().Foreground(darkChatTheme.tool)
+	chatTimeStyle      = lipgloss.NewStyle().Foreground(darkChatTheme.muted)
+	chatTruncatedStyle = lipgloss.NewStyle().Foreground(darkChatTheme.muted).Italic(true)
+
+	// Structured tool call styles
+	chatDiffRemoveStyle = lipgloss.NewStyle().Foreground(darkChatTheme.diffRemove)
+	chatDiffAddStyle    = lipgloss.NewStyle().Foreground(darkChatTheme.diffAdd)
+	chatTodoDoneStyle   = lipgloss.NewStyle().Foreground(darkChatTheme.muted).Strikethrough(true)
+	chatTodoActiveStyle = lipgloss.NewStyle().Foreground(darkChatTheme.highlight).Bold(true)
 
 	chatViewBorderStyle = lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
@@ -205,9 +245,11 @@ var (
 				Foreground(lipgloss.Color("#888888")).
 				Italic(true)
 
+	userQuestionErrorStyle = lipgloss.NewStyle().
+				Foreground(errorColor)
+
 	// Error display styles
 	errorBarStyle = lipgloss.NewStyle().
 			Foreground(errorColor).
 			Padding(0, 1)
-
 )
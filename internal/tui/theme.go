@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tessro/fab/internal/config"
+)
+
+// Chat themes.
+const (
+	ThemeDark   = "dark"   // The default, for dark terminal backgrounds
+	ThemeLight  = "light"  // Darker colors for light terminal backgrounds
+	ThemeCustom = "custom" // The dark theme with tui.colors applied
+)
+
+// chatTheme holds the colors of the chat styles.
+type chatTheme struct {
+	assistant  lipgloss.Color
+	user       lipgloss.Color
+	tool       lipgloss.Color
+	muted      lipgloss.Color
+	diffAdd    lipgloss.Color
+	diffRemove lipgloss.Color
+	highlight  lipgloss.Color
+}
+
+var (
+	darkChatTheme = chatTheme{
+		assistant:  lipgloss.Color("12"), // blue
+		user:       lipgloss.Color("10"), // green
+		tool:       lipgloss.Color("8"),  // gray
+		muted:      mutedColor,
+		diffAdd:    secondaryColor,
+		diffRemove: errorColor,
+		highlight:  warningColor,
+	}
+
+	lightChatTheme = chatTheme{
+		assistant:  lipgloss.Color("#1D4ED8"), // Dark blue
+		user:       lipgloss.Color("#047857"), // Dark green
+		tool:       lipgloss.Color("#4B5563"), // Dark gray
+		muted:      lipgloss.Color("#6B7280"), // Gray
+		diffAdd:    lipgloss.Color("#15803D"), // Dark green
+		diffRemove: lipgloss.Color("#B91C1C"), // Dark red
+		highlight:  lipgloss.Color("#B45309"), // Dark amber
+	}
+)
+
+// hexColorRegex matches "#RGB" and "#RRGGBB" colors.
+var hexColorRegex = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validateColor checks a color is hex ("#RRGGBB" or "#RGB") or an ANSI
+// color number (0-255).
+func validateColor(color string) error {
+	if hexColorRegex.MatchString(color) {
+		return nil
+	}
+	if n, err := strconv.Atoi(color); err == nil && n >= 0 && n <= 255 {
+		return nil
+	}
+	return fmt.Errorf("invalid color %q: must be #RRGGBB, #RGB, or 0-255", color)
+}
+
+// resolveChatTheme returns the theme named name, with colors applied if
+// it's ThemeCustom. An empty name is ThemeDark.
+func resolveChatTheme(name string, colors config.TUIColors) (chatTheme, error) {
+	switch name {
+	case "", ThemeDark:
+		return darkChatTheme, nil
+	case ThemeLight:
+		return lightChatTheme, nil
+	case ThemeCustom:
+	default:
+		return darkChatTheme, fmt.Errorf("invalid theme %q: must be %q, %q, or %q", name, ThemeDark, ThemeLight, ThemeCustom)
+	}
+
+	theme := darkChatTheme
+	for _, c := range []struct {
+		key   string
+		value string
+		dst   *lipgloss.Color
+	}{
+		{"assistant", colors.Assistant, &theme.assistant},
+		{"user", colors.User, &theme.user},
+		{"tool", colors.Tool, &theme.tool},
+		{"muted", colors.Muted, &theme.muted},
+		{"diff-add", colors.DiffAdd, &theme.diffAdd},
+		{"diff-remove", colors.DiffRemove, &theme.diffRemove},
+		{"highlight", colors.Highlight, &theme.highlight},
+	} {
+		if c.value == "" {
+			continue
+		}
+		if err := validateColor(c.value); err != nil {
+			return darkChatTheme, fmt.Errorf("tui.colors.%s: %w", c.key, err)
+		}
+		*c.dst = lipgloss.Color(c.value)
+	}
+	return theme, nil
+}
+
+// applyChatTheme sets the chat styles to a theme's colors.
+func applyChatTheme(t chatTheme) {
+	chatAssistantStyle = lipgloss.NewStyle().Foreground(t.assistant)
+	chatUserStyle = lipgloss.NewStyle().Foreground(t.user)
+	chatToolStyle = lipgloss.NewStyle().Foreground(t.tool)
+	chatResultStyle = lipgloss.NewStyle().Foreground(t.tool)
+	chatTimeStyle = lipgloss.NewStyle().Foreground(t.muted)
+	chatTruncatedStyle = lipgloss.NewStyle().Foreground(t.muted).Italic(true)
+	chatDiffRemoveStyle = lipgloss.NewStyle().Foreground(t.diffRemove)
+	chatDiffAddStyle = lipgloss.NewStyle().Foreground(t.diffAdd)
+	chatTodoDoneStyle = lipgloss.NewStyle().Foreground(t.muted).Strikethrough(true)
+	chatTodoActiveStyle = lipgloss.NewStyle().Foreground(t.highlight).Bold(true)
+}
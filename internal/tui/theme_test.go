@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tessro/fab/internal/config"
+)
+
+func TestResolveChatTheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   string
+		colors  config.TUIColors
+		want    chatTheme
+		wantErr string
+	}{
+		{name: "default", want: darkChatTheme},
+		{name: "dark", theme: ThemeDark, want: darkChatTheme},
+		{name: "light", theme: ThemeLight, want: lightChatTheme},
+		{
+			name:   "colors ignored outside custom",
+			theme:  ThemeLight,
+			colors: config.TUIColors{Assistant: "#FF0000"},
+			want:   lightChatTheme,
+		},
+		{
+			name:   "custom overrides the dark theme",
+			theme:  ThemeCustom,
+			colors: config.TUIColors{Assistant: "#FF0000", Muted: "244"},
+			want: func() chatTheme {
+				t := darkChatTheme
+				t.assistant = lipgloss.Color("#FF0000")
+				t.muted = lipgloss.Color("244")
+				return t
+			}(),
+		},
+		{name: "unknown theme", theme: "solarized", want: darkChatTheme, wantErr: "invalid theme"},
+		{
+			name:    "invalid color",
+			theme:   ThemeCustom,
+			colors:  config.TUIColors{DiffAdd: "green"},
+			want:    darkChatTheme,
+			wantErr: "tui.colors.diff-add",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveChatTheme(tt.theme, tt.colors)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("resolveChatTheme() error = %v, want one containing %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("resolveChatTheme() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveChatTheme() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateColor(t *testing.T) {
+	for _, c := range []string{"#FFF", "#7c3aed", "0", "12", "255"} {
+		if err := validateColor(c); err != nil {
+			t.Errorf("validateColor(%q) error = %v", c, err)
+		}
+	}
+	for _, c := range []string{"", "red", "#GGGGGG", "#12345", "256", "-1"} {
+		if err := validateColor(c); err == nil {
+			t.Errorf("validateColor(%q) succeeded, want error", c)
+		}
+	}
+}
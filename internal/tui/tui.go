@@ -8,6 +8,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/tessro/fab/internal/config"
 	"github.com/tessro/fab/internal/daemon"
 )
 
@@ -20,6 +21,17 @@ const (
 	FocusInputLine
 )
 
+// layoutMode is how the panes are arranged on screen.
+type layoutMode int
+
+const (
+	// layoutSplit shows the agent list and chat view side by side.
+	layoutSplit layoutMode = iota
+	// layoutCompact shows one pane at a time, for terminals too narrow to
+	// split. Tab switches between them.
+	layoutCompact
+)
+
 // connectionState represents the current IPC connection status.
 type connectionState int
 
@@ -37,6 +49,7 @@ type Model struct {
 	// Window dimensions
 	width  int
 	height int
+	layout layoutMode
 
 	// UI state
 	ready bool
@@ -60,10 +73,12 @@ type Model struct {
 	eventChan <-chan daemon.EventResult
 
 	// Connection state tracking
-	connState      connectionState
-	reconnectDelay time.Duration
-	reconnectCount int
-	maxReconnects  int
+	connState          connectionState
+	reconnectDelay     time.Duration
+	reconnectCount     int
+	maxReconnects      int
+	reconnectBaseDelay time.Duration
+	reconnectMaxDelay  time.Duration
 
 	// Pending permission requests (for selected agent)
 	pendingPermissions []daemon.PermissionRequest
@@ -80,9 +95,21 @@ type Model struct {
 	// Initial agent to select on startup (empty = first agent)
 	initialAgentID string
 
+	// Project whose manager is included when fetching the agent list
+	// (set when starting attached to a manager)
+	managerProject string
+
+	// Agent list order requested from the daemon (empty = daemon's order)
+	agentSort      string
+	agentSortOrder string
+
 	// Pending planner ID to select when it appears in the list
 	// Set when user starts a plan from TUI, cleared when selected
 	pendingPlannerID string
+
+	// Pending agent ID to select when it appears in the list
+	// Set when user creates an agent from TUI, cleared when selected
+	pendingAgentID string
 }
 
 // New creates a new TUI model.
@@ -91,24 +118,62 @@ func New() Model {
 	agentList.SetFocused(true) // Agent list is focused by default
 
 	return Model{
-		header:         NewHeader(),
-		agentList:      agentList,
-		chatView:       NewChatView(),
-		inputLine:      NewInputLine(),
-		helpBar:        NewHelpBar(),
-		modeState:      NewModeState(),
-		keys:           DefaultKeyBindings(),
-		connState:      connectionConnected,
-		reconnectDelay: 500 * time.Millisecond,
-		maxReconnects:  10,
+		header:             NewHeader(),
+		agentList:          agentList,
+		chatView:           NewChatView(),
+		inputLine:          NewInputLine(),
+		helpBar:            NewHelpBar(),
+		modeState:          NewModeState(),
+		keys:               DefaultKeyBindings(),
+		connState:          connectionConnected,
+		reconnectDelay:     config.DefaultReconnectBaseDelay,
+		maxReconnects:      config.DefaultReconnectMax,
+		reconnectBaseDelay: config.DefaultReconnectBaseDelay,
+		reconnectMaxDelay:  config.DefaultReconnectMaxDelay,
 	}
 }
 
 // TUIOptions configures the TUI behavior.
 type TUIOptions struct {
-	// InitialAgentID specifies an agent to select on startup.
+	// InitialAgentID specifies an entry to select on startup: an agent ID,
+	// "plan:<id>" for a planner, or "manager:<project>" for a project's
+	// manager (see ParseInitialSelection).
 	// If empty, the first agent in the list will be selected.
 	InitialAgentID string
+
+	// ReconnectMax is how many consecutive reconnect attempts to make
+	// before giving up. Zero uses the default.
+	ReconnectMax int
+
+	// ReconnectBaseDelay is the delay before the first reconnect attempt.
+	// Zero uses the default.
+	ReconnectBaseDelay time.Duration
+
+	// ReconnectMaxDelay caps the exponential reconnect backoff.
+	// Zero uses the default.
+	ReconnectMaxDelay time.Duration
+
+	// MaxChatEntries caps the entries held by the chat view.
+	// Zero uses the default.
+	MaxChatEntries int
+
+	// AgentSort and AgentSortOrder order the agent list (see
+	// daemon.AgentSortState). Empty keeps the daemon's order.
+	AgentSort      string
+	AgentSortOrder string
+
+	// AssistantName is shown before assistant messages in chat.
+	// Empty uses the backend name.
+	AssistantName string
+
+	// Theme picks the chat colors: ThemeDark, ThemeLight, or ThemeCustom
+	// to use ThemeColors. Empty uses ThemeDark.
+	Theme       string
+	ThemeColors config.TUIColors
+
+	// Keys rebinds actions to keys (see NewKeyBindings). Invalid
+	// rebindings are ignored in favor of the defaults.
+	Keys map[string][]string
 }
 
 // NewWithClient creates a new TUI model with a pre-connected daemon client.
@@ -116,7 +181,43 @@ func NewWithClient(client daemon.TUIClient, opts *TUIOptions) Model {
 	m := New()
 	m.client = client
 	if opts != nil {
-		m.initialAgentID = opts.InitialAgentID
+		if agentID, managerProject, err := ParseInitialSelection(opts.InitialAgentID); err == nil {
+			m.initialAgentID = agentID
+			m.managerProject = managerProject
+		} else {
+			slog.Warn("tui: ignoring invalid initial selection", "initial", opts.InitialAgentID, "error", err)
+		}
+		if opts.ReconnectMax > 0 {
+			m.maxReconnects = opts.ReconnectMax
+		}
+		if opts.ReconnectBaseDelay > 0 {
+			m.reconnectBaseDelay = opts.ReconnectBaseDelay
+			m.reconnectDelay = opts.ReconnectBaseDelay
+		}
+		if opts.ReconnectMaxDelay > 0 {
+			m.reconnectMaxDelay = opts.ReconnectMaxDelay
+		}
+		m.reconnectMaxDelay = max(m.reconnectMaxDelay, m.reconnectBaseDelay)
+		if opts.MaxChatEntries > 0 {
+			m.chatView.SetMaxEntries(opts.MaxChatEntries)
+		}
+		if err := daemon.ValidateAgentSort(opts.AgentSort, opts.AgentSortOrder); err == nil {
+			m.agentSort, m.agentSortOrder = opts.AgentSort, opts.AgentSortOrder
+		} else {
+			slog.Warn("tui: ignoring invalid agent sort", "sort", opts.AgentSort, "order", opts.AgentSortOrder, "error", err)
+		}
+		m.chatView.SetAssistantName(opts.AssistantName)
+		theme, err := resolveChatTheme(opts.Theme, opts.ThemeColors)
+		if err != nil {
+			slog.Warn("tui: ignoring invalid theme", "theme", opts.Theme, "error", err)
+		}
+		applyChatTheme(theme)
+		keys, err := NewKeyBindings(opts.Keys)
+		if err != nil {
+			slog.Warn("tui: ignoring invalid key bindings", "error", err)
+		}
+		m.keys = keys
+		m.helpBar.SetKeys(keys)
 	}
 	return m
 }
@@ -129,10 +230,11 @@ func (m Model) Init() tea.Cmd {
 		m.tickCmd(), // Start spinner animation
 	}
 	if m.client != nil {
-		// Fetch agent list first, then attach to stream
+		// Fetch the agent list, permissions, and daemon version in one
+		// round trip, then attach to the stream
 		// (must be sequential to avoid concurrent decoder access)
-		slog.Debug("tui.Init: scheduling fetchAgentList")
-		cmds = append(cmds, m.fetchAgentList())
+		slog.Debug("tui.Init: scheduling fetchDashboard")
+		cmds = append(cmds, m.fetchDashboard(), m.backlogTickCmd())
 	}
 	return tea.Batch(cmds...)
 }
@@ -152,16 +254,20 @@ func (m Model) View() string {
 		false, // no more staged actions (removed manual mode)
 		m.pendingUserQuestionForAgent(m.chatView.AgentID()) != nil,
 	)
+	m.modeState.PendingQuestionMultiSelect = m.chatView.QuestionIsMultiSelect()
 	m.helpBar.SetModeState(m.modeState)
 	status := m.helpBar.View()
 
-	// Left pane: agent list
-	agentList := m.agentList.View()
-
-	// Right pane: chat view
-	chatView := m.chatView.View()
-
-	content := lipgloss.JoinHorizontal(lipgloss.Top, agentList, chatView)
+	var content string
+	switch {
+	case m.layout == layoutCompact && m.showsAgentList():
+		content = m.agentList.View()
+	case m.layout == layoutCompact:
+		content = m.chatView.View()
+	default:
+		// Agent list on the left, chat view on the right
+		content = lipgloss.JoinHorizontal(lipgloss.Top, m.agentList.View(), m.chatView.View())
+	}
 
 	return fmt.Sprintf("%s\n%s\n%s", header, content, status)
 }
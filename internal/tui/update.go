@@ -3,11 +3,13 @@ package tui
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/tessro/fab/internal/daemon"
+	"github.com/tessro/fab/internal/version"
 )
 
 // Update implements tea.Model.
@@ -34,14 +36,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if question := m.pendingUserQuestionForAgent(m.chatView.AgentID()); question != nil {
 					input := m.inputLine.Value()
 					if input != "" {
-						// Get the current question header for the answer
-						header, _, _ := m.chatView.GetSelectedAnswer()
+						header, answer, err := m.chatView.OtherAnswer(input)
+						if err != nil {
+							// Stay in input mode so the user can correct the answer
+							m.chatView.SetInputView(m.inputLine.View(), m.inputLine.ContentHeight(), true)
+							return m, tea.Batch(cmds...)
+						}
 						slog.Debug("user question 'Other' answered",
 							"question_id", question.ID,
 							"header", header,
-							"answer", input,
+							"answer", answer,
 						)
-						cmds = append(cmds, m.answerUserQuestion(question.ID, map[string]string{header: input}))
+						cmds = append(cmds, m.answerUserQuestion(question.ID, map[string]string{header: answer}))
 						m.inputLine.AddToHistory(input)
 						m.inputLine.Clear()
 						m.inputLine.SetPlaceholder("Type a message...")
@@ -139,6 +145,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(cmds...)
 		}
 
+		// Handle new-agent project selection mode
+		if m.modeState.IsAgentProjectSelect() {
+			switch {
+			case key.Matches(msg, m.keys.Cancel):
+				_ = m.modeState.CancelAgentProjectSelect()
+				m.chatView.ClearAgentProjectSelection()
+			case key.Matches(msg, m.keys.Submit):
+				// Select project and create the agent
+				project, err := m.modeState.SelectAgentProject()
+				if err == nil {
+					m.chatView.ClearAgentProjectSelection()
+					cmds = append(cmds, m.createAgent(project))
+				}
+			case key.Matches(msg, m.keys.Up):
+				m.modeState.AgentProjectSelectUp()
+				projects, idx := m.modeState.SelectedAgentProject()
+				m.chatView.SetAgentProjectSelection(projects, idx, m.modeState.AgentProjectFilterState())
+			case key.Matches(msg, m.keys.Down):
+				m.modeState.AgentProjectSelectDown()
+				projects, idx := m.modeState.SelectedAgentProject()
+				m.chatView.SetAgentProjectSelection(projects, idx, m.modeState.AgentProjectFilterState())
+			case msg.Type == tea.KeyBackspace:
+				m.modeState.AgentProjectBackspaceFilter()
+				projects, idx := m.modeState.SelectedAgentProject()
+				m.chatView.SetAgentProjectSelection(projects, idx, m.modeState.AgentProjectFilterState())
+			case msg.Type == tea.KeyRunes:
+				for _, r := range msg.Runes {
+					m.modeState.AgentProjectAppendFilter(r)
+				}
+				projects, idx := m.modeState.SelectedAgentProject()
+				m.chatView.SetAgentProjectSelection(projects, idx, m.modeState.AgentProjectFilterState())
+			}
+			return m, tea.Batch(cmds...)
+		}
+
 		// Handle plan prompt mode
 		if m.modeState.IsPlanPrompt() {
 			switch {
@@ -281,7 +322,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						"permission_id", perm.ID,
 						"tool", perm.ToolName,
 					)
-					cmds = append(cmds, m.allowPermission(perm.ID))
+					cmds = append(cmds, m.allowPermission(perm.ID, false))
 				}
 			}
 
@@ -298,10 +339,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						"permission_id", perm.ID,
 						"tool", perm.ToolName,
 					)
-					cmds = append(cmds, m.denyPermission(perm.ID))
+					cmds = append(cmds, m.denyPermission(perm.ID, false))
 				}
 			}
 
+		case key.Matches(msg, m.keys.ApproveAlways):
+			// Approve pending permission and remember it as a project rule
+			if m.modeState.IsNormal() {
+				if perm := m.pendingPermissionForAgent(m.chatView.AgentID()); perm != nil {
+					slog.Debug("approving permission permanently",
+						"permission_id", perm.ID,
+						"tool", perm.ToolName,
+					)
+					cmds = append(cmds, m.allowPermission(perm.ID, true))
+				}
+			}
+
+		case key.Matches(msg, m.keys.RejectAlways):
+			// Deny pending permission and remember it as a project rule
+			if m.modeState.IsNormal() {
+				if perm := m.pendingPermissionForAgent(m.chatView.AgentID()); perm != nil {
+					slog.Debug("denying permission permanently",
+						"permission_id", perm.ID,
+						"tool", perm.ToolName,
+					)
+					cmds = append(cmds, m.denyPermission(perm.ID, true))
+				}
+			}
+
+		case key.Matches(msg, m.keys.Toggle):
+			// Toggle the highlighted option of a multi-select question
+			if m.modeState.IsNormal() && m.pendingUserQuestionForAgent(m.chatView.AgentID()) != nil {
+				m.chatView.QuestionToggle()
+			}
+
 		case key.Matches(msg, m.keys.Abort):
 			// Start abort confirmation for selected agent
 			agentID := m.chatView.AgentID()
@@ -317,7 +388,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				slog.Debug("manual reconnection triggered")
 				m.connState = connectionReconnecting
 				m.reconnectCount = 0
-				m.reconnectDelay = 500 * time.Millisecond
+				m.reconnectDelay = m.reconnectBaseDelay
 				m.header.SetConnectionState(m.connState)
 				cmds = append(cmds, m.attemptReconnect())
 			}
@@ -351,6 +422,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.chatView.QuestionMoveUp()
 				} else {
 					m.chatView.ScrollUp(1)
+					if cmd := m.loadOlderHistoryAtTop(); cmd != nil {
+						cmds = append(cmds, cmd)
+					}
 				}
 			}
 
@@ -363,6 +437,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			case FocusChatView:
 				m.chatView.ScrollToTop()
+				if cmd := m.loadOlderHistoryAtTop(); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
 			}
 
 		case key.Matches(msg, m.keys.Bottom):
@@ -376,9 +453,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.chatView.ScrollToBottom()
 			}
 
+		case key.Matches(msg, m.keys.NextAttention):
+			// Jump to the next agent waiting on the user, cycling through them
+			if m.modeState.IsNormal() && m.agentList.MoveToNextAttention() {
+				if cmd := m.selectCurrentAgent(); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
+			}
+
 		case key.Matches(msg, m.keys.PageUp):
 			if m.modeState.Focus == FocusChatView {
 				m.chatView.PageUp()
+				if cmd := m.loadOlderHistoryAtTop(); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
 			}
 
 		case key.Matches(msg, m.keys.PageDown):
@@ -392,6 +480,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmds = append(cmds, m.fetchProjectsForPlan())
 			}
 
+		case key.Matches(msg, m.keys.NewAgent):
+			// Create an agent in the selected agent's project, or ask for one
+			if m.modeState.IsNormal() {
+				if selected := m.agentList.Selected(); selected != nil && selected.Project != "" {
+					cmds = append(cmds, m.createAgent(selected.Project))
+				} else {
+					cmds = append(cmds, m.fetchProjectsForAgent())
+				}
+			}
+
 		case key.Matches(msg, m.keys.Supervisor):
 			// Start supervisor mode - fetch projects first
 			if m.modeState.IsNormal() {
@@ -412,9 +510,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.attached = true
 		m.connState = connectionConnected
 		m.reconnectCount = 0
-		m.reconnectDelay = 500 * time.Millisecond
+		m.reconnectDelay = m.reconnectBaseDelay
 		m.header.SetConnectionState(m.connState)
 		cmds = append(cmds, m.waitForEvent())
+		cmds = append(cmds, m.fetchPendingPermissions())
 
 	case streamEventMsg:
 		if msg.Err != nil {
@@ -428,7 +527,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.header.SetConnectionState(m.connState)
 				cmds = append(cmds, m.attemptReconnect())
 			} else {
-				cmds = append(cmds, m.setError(fmt.Errorf("connection lost (press 'r' to reconnect)")))
+				cmds = append(cmds, m.setError(m.reconnectGaveUpError()))
 			}
 		} else if msg.Event != nil {
 			slog.Debug("stream event received", "type", msg.Event.Type)
@@ -446,10 +545,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.attached = true
 			m.connState = connectionConnected
 			m.reconnectCount = 0
-			m.reconnectDelay = 500 * time.Millisecond
+			m.reconnectDelay = m.reconnectBaseDelay
 			m.header.SetConnectionState(m.connState)
-			// Fetch fresh agent list after reconnection
-			cmds = append(cmds, m.fetchAgentList())
+			// Fetch fresh agents, permissions, and daemon version (it may
+			// have been restarted with a different build) after reconnection
+			cmds = append(cmds, m.fetchDashboard())
 			cmds = append(cmds, m.waitForEvent())
 			// If an agent is currently selected, refetch its history
 			// This handles daemon restart where in-memory history was lost
@@ -460,80 +560,76 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			slog.Debug("reconnection failed", "err", msg.Err, "attempt", m.reconnectCount+1)
 			m.reconnectCount++
-			// Exponential backoff: 500ms, 1s, 2s, 4s, 8s (capped)
-			m.reconnectDelay = min(m.reconnectDelay*2, 8*time.Second)
+			// Exponential backoff from the base delay, capped at the max delay
+			// (defaults: 500ms, 1s, 2s, 4s, 8s)
+			m.reconnectDelay = min(m.reconnectDelay*2, m.reconnectMaxDelay)
 			if m.reconnectCount < m.maxReconnects {
 				cmds = append(cmds, m.attemptReconnect())
 			} else {
 				m.connState = connectionDisconnected
 				m.header.SetConnectionState(m.connState)
-				cmds = append(cmds, m.setError(fmt.Errorf("connection lost after %d attempts (press 'r' to reconnect)", m.reconnectCount)))
+				cmds = append(cmds, m.setError(m.reconnectGaveUpError()))
 			}
 		}
 
-	case agentListMsg:
+	case agentDetailsMsg:
 		if msg.Err != nil {
-			slog.Error("tui.Update: agentListMsg error", "error", msg.Err)
-			cmds = append(cmds, m.setError(msg.Err))
+			slog.Debug("tui.Update: failed to fetch agent details", "agent", msg.AgentID, "error", msg.Err)
+		} else if msg.AgentID == m.chatView.AgentID() {
+			m.chatView.SetDetails(msg.Details)
+		}
+
+	case daemonVersionMsg:
+		if msg.Err != nil {
+			slog.Debug("tui.Update: failed to fetch daemon version", "error", msg.Err)
 		} else {
-			slog.Debug("tui.Update: agentListMsg received", "count", len(msg.Agents), "initial_agent_id", m.initialAgentID, "pending_planner_id", m.pendingPlannerID)
-			m.agentList.SetAgents(msg.Agents)
-			m.header.SetAgentCounts(len(msg.Agents), countRunning(msg.Agents))
-			// Prune state for agents that no longer exist (e.g., after reconnecting)
-			if cmd := m.pruneStaleAgentState(); cmd != nil {
-				cmds = append(cmds, cmd)
-			}
+			m.header.SetVersionWarning(versionWarning(msg.Version, version.Version))
+		}
 
-			// Check if we have a pending planner to select (from starting plan in TUI)
-			if m.pendingPlannerID != "" {
-				tuiPlannerID := plannerAgentID(m.pendingPlannerID)
-				slog.Debug("tui.Update: looking for pending planner", "pending_planner_id", m.pendingPlannerID, "tui_planner_id", tuiPlannerID)
-				found := false
-				for i, agent := range msg.Agents {
-					if agent.ID == tuiPlannerID {
-						slog.Debug("tui.Update: found pending planner, selecting", "index", i, "agent_id", agent.ID)
-						m.pendingPlannerID = "" // Clear pending
-						m.agentList.SetSelected(i)
-						if cmd := m.selectCurrentAgent(); cmd != nil {
-							cmds = append(cmds, cmd)
-						}
-						found = true
-						break
-					}
-				}
-				if !found {
-					slog.Debug("tui.Update: pending planner not found in agent list", "tui_planner_id", tuiPlannerID)
-				}
-			} else if m.chatView.AgentID() == "" && len(msg.Agents) > 0 {
-				// Auto-select agent if none is currently selected
-				// If an initial agent was specified, find and select it
-				if m.initialAgentID != "" {
-					slog.Debug("tui.Update: looking for initial agent", "initial_agent_id", m.initialAgentID)
-					found := false
-					for i, agent := range msg.Agents {
-						slog.Debug("tui.Update: checking agent", "index", i, "agent_id", agent.ID)
-						if agent.ID == m.initialAgentID {
-							slog.Debug("tui.Update: found initial agent, selecting", "index", i)
-							m.agentList.SetSelected(i)
-							found = true
-							break
-						}
-					}
-					if !found {
-						slog.Warn("tui.Update: initial agent not found in agent list", "initial_agent_id", m.initialAgentID, "agent_count", len(msg.Agents))
-					}
-					// Clear the initial agent ID so we don't keep trying to select it
-					m.initialAgentID = ""
-				}
-				if cmd := m.selectCurrentAgent(); cmd != nil {
-					cmds = append(cmds, cmd)
-				}
+	case backlogTickMsg:
+		cmds = append(cmds, m.fetchBacklog(), m.backlogTickCmd())
+
+	case backlogMsg:
+		if msg.Err != nil {
+			slog.Debug("tui.Update: failed to fetch backlog", "error", msg.Err)
+		} else {
+			m.header.SetWaitingIssues(waitingAtCapacity(msg.Projects))
+		}
+
+	case pendingPermissionsMsg:
+		if msg.Err != nil {
+			slog.Debug("tui.Update: failed to fetch pending permissions", "error", msg.Err)
+		} else {
+			m.addPendingPermissions(msg.Requests)
+		}
+
+	case dashboardMsg:
+		if msg.Err != nil {
+			// Older daemons don't know the dashboard message
+			slog.Debug("tui.Update: dashboard failed, fetching sections separately", "error", msg.Err)
+			cmds = append(cmds, m.fetchAgentList(), m.fetchPendingPermissions(), m.fetchDaemonVersion())
+		} else {
+			if msg.Version != "" {
+				m.header.SetVersionWarning(versionWarning(msg.Version, version.Version))
 			}
-			// Attach to event stream after initial agent list fetch
-			if !m.attached {
-				slog.Debug("tui.Update: attaching to event stream")
-				cmds = append(cmds, m.attachToStream())
+			if msg.Projects != nil {
+				m.header.SetWaitingIssues(waitingAtCapacity(msg.Projects))
 			}
+			m.addPendingPermissions(msg.Permissions)
+			if msg.AgentsErr != nil {
+				slog.Error("tui.Update: dashboard agent list error", "error", msg.AgentsErr)
+				cmds = append(cmds, m.setError(msg.AgentsErr))
+			} else {
+				cmds = append(cmds, m.applyAgentList(msg.Agents)...)
+			}
+		}
+
+	case agentListMsg:
+		if msg.Err != nil {
+			slog.Error("tui.Update: agentListMsg error", "error", msg.Err)
+			cmds = append(cmds, m.setError(msg.Err))
+		} else {
+			cmds = append(cmds, m.applyAgentList(msg.Agents)...)
 		}
 
 	case agentInputMsg:
@@ -543,10 +639,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case agentChatHistoryMsg:
 		if msg.Err != nil {
+			if msg.Older && msg.AgentID == m.chatView.AgentID() {
+				m.chatView.CancelOlderHistoryLoad()
+			}
 			cmds = append(cmds, m.setError(msg.Err))
 		} else if msg.AgentID == m.chatView.AgentID() {
 			// Only apply if still viewing this agent
-			m.chatView.SetEntries(msg.Entries)
+			if msg.Older {
+				m.chatView.PrependEntries(msg.Entries, msg.NextCursor)
+			} else {
+				m.chatView.SetEntries(msg.Entries)
+				m.chatView.SetHistoryCursor(msg.NextCursor)
+			}
 		}
 
 	case permissionResultMsg:
@@ -624,6 +728,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, m.fetchAgentList())
 		}
 
+	case agentProjectListMsg:
+		if msg.Err != nil {
+			cmds = append(cmds, m.setError(msg.Err))
+		} else if len(msg.Projects) == 0 {
+			cmds = append(cmds, m.setError(fmt.Errorf("no projects configured")))
+		} else {
+			if err := m.modeState.EnterAgentProjectSelect(msg.Projects); err != nil {
+				cmds = append(cmds, m.setError(err))
+			} else {
+				m.chatView.SetAgentProjectSelection(msg.Projects, 0, "")
+			}
+		}
+
+	case agentCreateResultMsg:
+		if msg.Err != nil {
+			cmds = append(cmds, m.setError(msg.Err))
+		} else {
+			slog.Info("agent created from TUI",
+				"agent", msg.AgentID,
+				"project", msg.Project,
+			)
+			// Select the agent when its created event arrives; the event may
+			// already have been handled, so refresh the list too
+			m.pendingAgentID = msg.AgentID
+			cmds = append(cmds, m.fetchAgentList())
+		}
+
 	case supervisorProjectListMsg:
 		if msg.Err != nil {
 			cmds = append(cmds, m.setError(msg.Err))
@@ -667,6 +798,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Advance spinner frame and schedule next tick
 		m.spinnerFrame++
 		m.agentList.SetSpinnerFrame(m.spinnerFrame)
+		m.agentList.PruneGhosts(time.Now())
 		cmds = append(cmds, m.tickCmd())
 
 	case clearErrorMsg:
@@ -694,6 +826,12 @@ func (m *Model) handleStreamEvent(event *daemon.StreamEvent) tea.Cmd {
 			m.chatView.AppendEntry(*event.ChatEntry)
 		}
 
+	case "chat_delta":
+		// Streaming assistant text; the chat_entry that follows completes it
+		if event.Data != "" && event.AgentID == m.chatView.AgentID() {
+			m.chatView.AppendDelta(event.Data)
+		}
+
 	case "output":
 		// Deprecated: kept for backwards compatibility with raw output
 		// This is no longer used by the chat view
@@ -709,19 +847,42 @@ func (m *Model) handleStreamEvent(event *daemon.StreamEvent) tea.Cmd {
 			}
 		}
 		m.header.SetAgentCounts(len(agents), countRunning(agents))
+		if event.AgentID == m.chatView.AgentID() {
+			return m.fetchAgentDetails(event.AgentID)
+		}
 
 	case "info":
-		// Update agent task/description in the list
+		// Update agent name/task/description/epic/current file/model in the list
 		agents := m.agentList.Agents()
 		for i := range agents {
 			if agents[i].ID == event.AgentID {
+				agents[i].Name = event.Name
 				agents[i].Task = event.Task
 				agents[i].Description = event.Description
+				agents[i].Epic = event.Epic
+				agents[i].CurrentFile = event.CurrentFile
+				agents[i].Model = event.Model
 				m.agentList.SetAgents(agents)
+				if m.chatView.AgentID() == event.AgentID {
+					m.chatView.SetModel(event.Model)
+					m.chatView.SetName(event.Name)
+					return m.fetchAgentDetails(event.AgentID)
+				}
 				break
 			}
 		}
 
+	case "would_spawn", "would_merge":
+		// A dry-run orchestrator reported an action it skipped
+		m.agentList.AddGhost(GhostEntry{
+			Type:    event.Type,
+			Project: event.Project,
+			IssueID: event.Task,
+			AgentID: event.AgentID,
+			Reason:  event.Description,
+			SeenAt:  time.Now(),
+		})
+
 	case "created":
 		// A new agent was created - add to list with proper StartedAt
 		agents := m.agentList.Agents()
@@ -731,14 +892,31 @@ func (m *Model) handleStreamEvent(event *daemon.StreamEvent) tea.Cmd {
 				startedAt = t
 			}
 		}
-		agents = append(agents, daemon.AgentStatus{
-			ID:        event.AgentID,
-			Project:   event.Project,
-			State:     "starting",
-			StartedAt: startedAt,
-		})
+		// A list refresh (e.g. after creating an agent from the TUI) may
+		// already include it
+		index := slices.IndexFunc(agents, func(a daemon.AgentStatus) bool { return a.ID == event.AgentID })
+		if index < 0 {
+			agents = append(agents, daemon.AgentStatus{
+				ID:        event.AgentID,
+				Project:   event.Project,
+				Name:      event.Name,
+				SpawnedBy: event.SpawnedBy,
+				State:     "starting",
+				StartedAt: startedAt,
+				Model:     event.Model,
+			})
+			index = len(agents) - 1
+		}
 		m.agentList.SetAgents(agents)
 		m.header.SetAgentCounts(len(agents), countRunning(agents))
+
+		// Check if this is the agent we just created from TUI
+		if m.pendingAgentID == event.AgentID {
+			m.pendingAgentID = "" // Clear pending
+			m.agentList.SetSelected(index)
+			return m.selectCurrentAgent()
+		}
+
 		// Auto-select the new agent if no agent is currently selected
 		if m.chatView.AgentID() == "" {
 			return m.selectCurrentAgent()
@@ -771,14 +949,9 @@ func (m *Model) handleStreamEvent(event *daemon.StreamEvent) tea.Cmd {
 				"agent", event.AgentID,
 				"tool", event.PermissionRequest.ToolName,
 			)
-			// Add to our list of pending permissions
-			m.pendingPermissions = append(m.pendingPermissions, *event.PermissionRequest)
-			// Update chat view if this is for the current agent
-			if event.AgentID == m.chatView.AgentID() {
-				m.chatView.SetPendingPermission(m.pendingPermissionForAgent(event.AgentID))
-			}
-			// Update attention indicators
-			m.updateNeedsAttention()
+			// Add to our list of pending permissions (it may already be
+			// there if it was fetched on attach)
+			m.addPendingPermissions([]daemon.PermissionRequest{*event.PermissionRequest})
 		}
 
 	case "user_question":
@@ -788,8 +961,11 @@ func (m *Model) handleStreamEvent(event *daemon.StreamEvent) tea.Cmd {
 				"agent", event.AgentID,
 				"question_count", len(event.UserQuestion.Questions),
 			)
-			// Add to our list of pending user questions
-			m.pendingUserQuestions = append(m.pendingUserQuestions, *event.UserQuestion)
+			// Add to our list of pending user questions (a replayed event
+			// may repeat one we already have)
+			if !slices.ContainsFunc(m.pendingUserQuestions, func(q daemon.UserQuestion) bool { return q.ID == event.UserQuestion.ID }) {
+				m.pendingUserQuestions = append(m.pendingUserQuestions, *event.UserQuestion)
+			}
 			// Update chat view if this is for the current agent
 			if event.AgentID == m.chatView.AgentID() {
 				m.chatView.SetPendingUserQuestion(m.pendingUserQuestionForAgent(event.AgentID))
@@ -844,7 +1020,7 @@ func (m *Model) handleStreamEvent(event *daemon.StreamEvent) tea.Cmd {
 				// Prepend manager as first entry
 				managerAgent := daemon.AgentStatus{
 					ID:          ManagerAgentID,
-					Project:     "manager",
+					Project:     event.Project,
 					State:       event.ManagerState,
 					StartedAt:   startedAt,
 					Description: "Manager",
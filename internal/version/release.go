@@ -0,0 +1,103 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LatestReleaseURL is the GitHub API endpoint for fab's latest release.
+// It is a variable so tests can point it at a local server.
+var LatestReleaseURL = "https://api.github.com/repos/tessro/fab/releases/latest"
+
+// CheckTimeout bounds the release lookup so an offline check fails fast.
+const CheckTimeout = 5 * time.Second
+
+// Release describes a published fab release.
+type Release struct {
+	Tag string `json:"tag_name"` // e.g., "v0.4.0"
+	URL string `json:"html_url"` // Release page
+}
+
+// LatestRelease fetches the latest published release from GitHub.
+func LatestRelease(ctx context.Context) (*Release, error) {
+	ctx, cancel := context.WithTimeout(ctx, CheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, LatestReleaseURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned %s", resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("decode release: %w", err)
+	}
+	if release.Tag == "" {
+		return nil, fmt.Errorf("release has no tag")
+	}
+	return &release, nil
+}
+
+// UpdateAvailable reports whether latest is a newer release than current.
+// Versions that aren't semantic versions (e.g., "dev") never need updates.
+func UpdateAvailable(current, latest string) bool {
+	c, ok := parseSemver(current)
+	if !ok {
+		return false
+	}
+	l, ok := parseSemver(latest)
+	if !ok {
+		return false
+	}
+	for i := range c {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// IsSemver reports whether v is a semantic version like "v1.2.3", as
+// opposed to a development build.
+func IsSemver(v string) bool {
+	_, ok := parseSemver(v)
+	return ok
+}
+
+// parseSemver parses the major, minor, and patch numbers of a version like
+// "v1.2.3" or "1.2.3-rc.1". Pre-release and build suffixes are ignored.
+func parseSemver(v string) ([3]int, bool) {
+	var nums [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return nums, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nums, false
+		}
+		nums[i] = n
+	}
+	return nums, true
+}
@@ -0,0 +1,62 @@
+package version
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpdateAvailable(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v0.3.0", "v0.4.0", true},
+		{"0.3.9", "v0.3.10", true},
+		{"v1.0.0", "v1.0.0", false},
+		{"v1.2.0", "v1.1.5", false},
+		{"v1.0.0-rc.1", "v1.0.1", true},
+		{"dev", "v1.0.0", false},
+		{"v1.0.0", "nightly", false},
+	}
+	for _, tt := range tests {
+		if got := UpdateAvailable(tt.current, tt.latest); got != tt.want {
+			t.Errorf("UpdateAvailable(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestLatestRelease(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tag_name":"v0.5.0","html_url":"https://github.com/tessro/fab/releases/tag/v0.5.0"}`))
+	}))
+	defer srv.Close()
+
+	orig := LatestReleaseURL
+	LatestReleaseURL = srv.URL
+	defer func() { LatestReleaseURL = orig }()
+
+	release, err := LatestRelease(context.Background())
+	if err != nil {
+		t.Fatalf("LatestRelease() error = %v", err)
+	}
+	if release.Tag != "v0.5.0" || release.URL == "" {
+		t.Errorf("LatestRelease() = %+v", release)
+	}
+}
+
+func TestLatestRelease_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	orig := LatestReleaseURL
+	LatestReleaseURL = srv.URL
+	defer func() { LatestReleaseURL = orig }()
+
+	if _, err := LatestRelease(context.Background()); err == nil {
+		t.Error("LatestRelease() succeeded on a 403, want error")
+	}
+}